@@ -0,0 +1,141 @@
+// Package agentstatus tells what an agent is doing from its pane output.
+//
+// A live agent process says nothing about whether the agent is thinking,
+// blocked on a confirmation prompt, finished, or sitting on an API error.
+// Each agent CLI shows these states in its own way, so every preset can
+// carry output patterns (config.OutputPatterns) that an Adapter matches
+// against the bottom of the pane. Kinds a preset leaves out use generic
+// patterns that fit most CLIs.
+package agentstatus
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Status is what an agent appears to be doing.
+type Status string
+
+const (
+	StatusUnknown Status = "unknown" // Nothing recognized on screen
+	StatusWorking Status = "working" // Busy on its task
+	StatusWaiting Status = "waiting" // Blocked on a confirmation or question
+	StatusDone    Status = "done"    // Back at its prompt
+	StatusError   Status = "error"   // Stopped on an error
+)
+
+// TailLines is how many non-blank lines at the bottom of the pane are
+// matched; older output is ignored.
+const TailLines = 15
+
+// genericPatterns fill in the kinds a preset doesn't define.
+var genericPatterns = config.OutputPatterns{
+	Waiting: []string{`(?i)\[y/n\]`, `(?i)\(y/n\)`, `(?i)\byes/no\b`, `(?i)press enter to continue`, `(?i)do you want to (proceed|continue)\?`},
+	Error:   []string{`(?i)^error:`, `(?i)rate limit(ed)? exceeded`, `^panic: `},
+}
+
+// Result is a classified pane: the status and the line that showed it.
+type Result struct {
+	Status Status
+	Line   string
+}
+
+// Adapter classifies the pane output of one kind of agent.
+type Adapter struct {
+	// Kinds in the order they win when several match: a prompt on screen
+	// blocks the agent whatever else it shows, a spinner means an earlier
+	// error is being retried, and an error outranks the prompt it left.
+	kinds []kind
+}
+
+type kind struct {
+	status   Status
+	patterns []*regexp.Regexp
+}
+
+// New compiles an adapter from p, using the generic patterns for the
+// kinds p leaves empty (all of them when p is nil).
+func New(p *config.OutputPatterns) (*Adapter, error) {
+	if p == nil {
+		p = &config.OutputPatterns{}
+	}
+	a := &Adapter{}
+	for _, k := range []struct {
+		status          Status
+		patterns, other []string
+	}{
+		{StatusWaiting, p.Waiting, genericPatterns.Waiting},
+		{StatusWorking, p.Working, genericPatterns.Working},
+		{StatusError, p.Error, genericPatterns.Error},
+		{StatusDone, p.Done, genericPatterns.Done},
+	} {
+		patterns := k.patterns
+		if len(patterns) == 0 {
+			patterns = k.other
+		}
+		compiled := kind{status: k.status}
+		for _, pattern := range patterns {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			compiled.patterns = append(compiled.patterns, re)
+		}
+		a.kinds = append(a.kinds, compiled)
+	}
+	return a, nil
+}
+
+// ForAgent returns the adapter for the agent preset named agent (a
+// session's GT_AGENT; empty for the default, claude). An unknown preset
+// or invalid patterns get the generic adapter.
+func ForAgent(agent string) *Adapter {
+	if agent == "" {
+		agent = string(config.AgentClaude)
+	}
+	var patterns *config.OutputPatterns
+	if preset := config.GetAgentPresetByName(agent); preset != nil {
+		patterns = preset.OutputPatterns
+	}
+	if a, err := New(patterns); err == nil {
+		return a
+	}
+	a, _ := New(nil)
+	return a
+}
+
+// Classify returns the status shown by the last TailLines non-blank lines
+// of pane, and the most recent line that showed it.
+func (a *Adapter) Classify(pane string) Result {
+	lines := tail(pane, TailLines)
+	for _, k := range a.kinds {
+		for i := len(lines) - 1; i >= 0; i-- {
+			for _, re := range k.patterns {
+				if re.MatchString(lines[i]) {
+					return Result{Status: k.status, Line: lines[i]}
+				}
+			}
+		}
+	}
+	return Result{Status: StatusUnknown}
+}
+
+// tail returns the last n non-blank lines of s, with surrounding space
+// and box-drawing borders trimmed.
+func tail(s string, n int) []string {
+	all := strings.Split(s, "\n")
+	var lines []string
+	for i := len(all) - 1; i >= 0 && len(lines) < n; i-- {
+		line := strings.Trim(all[i], " \t\r│┃|╭╮╰╯─")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	// Back into screen order
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines
+}
@@ -0,0 +1,93 @@
+package agentstatus
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestClassify_Claude(t *testing.T) {
+	a := ForAgent("") // The default agent
+	tests := []struct {
+		name string
+		pane string
+		want Status
+	}{
+		{"thinking", `
+● Reading internal/cmd/at.go
+
+✻ Pondering… (12s · ↑ 1.2k tokens · esc to interrupt)
+
+╭──────────────────────────────────╮
+│ ❯                                │
+╰──────────────────────────────────╯`, StatusWorking},
+		{"permission prompt", `
+ Bash command
+   rm -rf build/
+
+ Do you want to proceed?
+ ❯ 1. Yes
+   2. No, and tell Claude what to do differently (esc)`, StatusWaiting},
+		{"finished", `
+● All tests pass. The change is committed.
+
+╭──────────────────────────────────╮
+│ ❯                                │
+╰──────────────────────────────────╯
+  ⏵⏵ bypass permissions on`, StatusDone},
+		{"api error", `
+  ⎿  API Error: 529 {"type":"error","error":{"type":"overloaded_error"}}
+
+╭──────────────────────────────────╮
+│ ❯                                │
+╰──────────────────────────────────╯`, StatusError},
+		{"empty", "", StatusUnknown},
+	}
+	for _, tt := range tests {
+		if got := a.Classify(tt.pane); got.Status != tt.want {
+			t.Errorf("%s: Classify() = %+v, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassify_Generic(t *testing.T) {
+	a, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := a.Classify("Overwrite existing file? [y/N]\n\n")
+	if got.Status != StatusWaiting || got.Line != "Overwrite existing file? [y/N]" {
+		t.Errorf("Classify() = %+v, want waiting on the question", got)
+	}
+	if got := a.Classify("Error: connection refused\n$ "); got.Status != StatusError {
+		t.Errorf("Classify() = %+v, want error", got)
+	}
+	if got := a.Classify("compiling...\n"); got.Status != StatusUnknown {
+		t.Errorf("Classify() = %+v, want unknown", got)
+	}
+}
+
+func TestClassify_OnlyTail(t *testing.T) {
+	a, _ := New(nil)
+	pane := "Continue? [y/n]\n" + strings.Repeat("output\n", TailLines)
+	if got := a.Classify(pane); got.Status != StatusUnknown {
+		t.Errorf("Classify() = %+v, want the old question scrolled out", got)
+	}
+}
+
+func TestNew_PresetOverridesGeneric(t *testing.T) {
+	a, err := New(&config.OutputPatterns{Waiting: []string{`^Proceed\?$`}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := a.Classify("Continue? [y/n]"); got.Status != StatusUnknown {
+		t.Errorf("Classify() = %+v, want the generic waiting patterns replaced", got)
+	}
+	if got := a.Classify("Error: boom"); got.Status != StatusError {
+		t.Errorf("Classify() = %+v, want the generic error patterns kept", got)
+	}
+	if _, err := New(&config.OutputPatterns{Done: []string{`(`}}); err == nil {
+		t.Error("New() with an invalid pattern = nil error")
+	}
+}
@@ -0,0 +1,64 @@
+// Package aider provides Aider workspace integration.
+package aider
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ExcludePattern matches the files Aider writes at the root of a worktree:
+// chat and input history, and its repo map cache.
+const ExcludePattern = ".aider*"
+
+// RunPrefix makes Aider run the rest of a chat line as a shell command.
+// Anything else typed into Aider is sent to the model.
+const RunPrefix = "/run "
+
+// EnsureGitExcludeAt adds ExcludePattern to the info/exclude file of the
+// git repository containing workDir, so Aider's files never show up as
+// uncommitted changes. Linked worktrees share their main repository's
+// exclude file. A workDir outside any repository is left alone.
+func EnsureGitExcludeAt(workDir string) error {
+	if workDir == "" {
+		return nil
+	}
+	out, err := exec.Command("git", "-C", workDir, "rev-parse", "--git-common-dir").Output()
+	if err != nil {
+		return nil // Not a git repository
+	}
+	gitDir := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(workDir, gitDir)
+	}
+	excludePath := filepath.Join(gitDir, "info", "exclude")
+
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading git exclude: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == ExcludePattern {
+			return nil
+		}
+	}
+	entry := ExcludePattern + "\n"
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		entry = "\n" + entry
+	}
+
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return fmt.Errorf("creating git info directory: %w", err)
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening git exclude: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(entry); err != nil {
+		return fmt.Errorf("writing git exclude: %w", err)
+	}
+	return nil
+}
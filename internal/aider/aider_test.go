@@ -0,0 +1,48 @@
+package aider
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEnsureGitExcludeAt(t *testing.T) {
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Skipf("git init: %v: %s", err, out)
+	}
+	excludePath := filepath.Join(dir, ".git", "info", "exclude")
+	if err := os.WriteFile(excludePath, []byte("*.log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Twice: the pattern is only added once
+	for i := 0; i < 2; i++ {
+		if err := EnsureGitExcludeAt(dir); err != nil {
+			t.Fatalf("EnsureGitExcludeAt() = %v", err)
+		}
+	}
+	data, err := os.ReadFile(excludePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "*.log\n.aider*\n" {
+		t.Errorf("exclude = %q, want the existing entry kept and .aider* added once", got)
+	}
+
+	if out, err := exec.Command("git", "-C", dir, "check-ignore", "-q", ".aider.chat.history.md").CombinedOutput(); err != nil {
+		t.Errorf("git check-ignore .aider.chat.history.md: %v %s", err, strings.TrimSpace(string(out)))
+	}
+}
+
+func TestEnsureGitExcludeAtOutsideRepo(t *testing.T) {
+	dir := t.TempDir()
+	if err := EnsureGitExcludeAt(dir); err != nil {
+		t.Errorf("EnsureGitExcludeAt() outside a repo = %v, want nil", err)
+	}
+	if err := EnsureGitExcludeAt(""); err != nil {
+		t.Errorf("EnsureGitExcludeAt(\"\") = %v, want nil", err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package beads provides the bead dependency graph.
+package beads
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrDependencyCycle indicates beads that need each other, directly or
+// through other beads, so none of them can ever be worked.
+var ErrDependencyCycle = errors.New("dependency cycle")
+
+// ParseDependencyFields extracts the dependencies a bead declares in its
+// description: "needs: <id>..." lines name beads it needs done first, and
+// "blocks: <id>..." lines beads that need it done first. IDs are separated
+// by commas or spaces.
+func ParseDependencyFields(description string) (needs, blocks []string) {
+	for _, line := range strings.Split(description, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		ids := strings.FieldsFunc(value, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "needs", "depends_on", "depends-on":
+			needs = append(needs, ids...)
+		case "blocks":
+			blocks = append(blocks, ids...)
+		}
+	}
+	return needs, blocks
+}
+
+// DependencyGraph is the graph of which beads need which others done
+// (closed) before they can be worked. A bead's needs come from its own
+// needs: lines and bd "blocks" dependencies, and from the blocks: lines of
+// the other beads in the graph.
+type DependencyGraph struct {
+	issues map[string]*Issue
+	ids    []string            // In the order added, for stable output
+	needs  map[string][]string // Bead ID to the IDs it needs, sorted
+}
+
+// NewDependencyGraph returns the dependency graph of issues.
+func NewDependencyGraph(issues []*Issue) *DependencyGraph {
+	g := &DependencyGraph{issues: make(map[string]*Issue)}
+	g.Add(issues...)
+	return g
+}
+
+// Add adds issues to the graph. An issue already in it is replaced.
+func (g *DependencyGraph) Add(issues ...*Issue) {
+	for _, issue := range issues {
+		if _, ok := g.issues[issue.ID]; !ok {
+			g.ids = append(g.ids, issue.ID)
+		}
+		g.issues[issue.ID] = issue
+	}
+	g.link()
+}
+
+// link rebuilds the needs of every bead in the graph.
+func (g *DependencyGraph) link() {
+	g.needs = make(map[string][]string)
+	for _, id := range g.ids {
+		issue := g.issues[id]
+		needs, blocks := ParseDependencyFields(issue.Description)
+		g.needs[id] = append(g.needs[id], needs...)
+		g.needs[id] = append(g.needs[id], issue.DependsOn...)
+		g.needs[id] = append(g.needs[id], issue.BlockedBy...)
+		for _, dep := range issue.Dependencies {
+			// Only "blocks" dependencies hold work back - not "parent-child".
+			if dep.DependencyType == "blocks" {
+				g.needs[id] = append(g.needs[id], dep.ID)
+			}
+		}
+		for _, blocked := range blocks {
+			g.needs[blocked] = append(g.needs[blocked], id)
+		}
+	}
+	for id, needs := range g.needs {
+		slices.Sort(needs)
+		needs = slices.Compact(needs)
+		g.needs[id] = slices.DeleteFunc(needs, func(need string) bool { return need == id })
+	}
+}
+
+// IDs returns the IDs of the beads in the graph, in the order added.
+func (g *DependencyGraph) IDs() []string {
+	return slices.Clone(g.ids)
+}
+
+// Issue returns the bead with id, or nil if it isn't in the graph.
+func (g *DependencyGraph) Issue(id string) *Issue {
+	return g.issues[id]
+}
+
+// Needs returns the IDs of the beads id needs done before it can be worked.
+func (g *DependencyGraph) Needs(id string) []string {
+	return g.needs[id]
+}
+
+// Missing returns the IDs of beads needed by beads in the graph that aren't
+// in it themselves.
+func (g *DependencyGraph) Missing() []string {
+	var missing []string
+	for _, id := range g.ids {
+		for _, need := range g.needs[id] {
+			if g.issues[need] == nil && !slices.Contains(missing, need) {
+				missing = append(missing, need)
+			}
+		}
+	}
+	return missing
+}
+
+// Done reports whether bead id is done: closed. A bead not in the graph
+// isn't known to be done.
+func (g *DependencyGraph) Done(id string) bool {
+	issue := g.issues[id]
+	return issue != nil && issue.Status == "closed"
+}
+
+// Unmet returns the IDs of the beads id needs that aren't done yet.
+func (g *DependencyGraph) Unmet(id string) []string {
+	var unmet []string
+	for _, need := range g.needs[id] {
+		if !g.Done(need) {
+			unmet = append(unmet, need)
+		}
+	}
+	return unmet
+}
+
+// Ready reports whether bead id can be worked: everything it needs is done.
+func (g *DependencyGraph) Ready(id string) bool {
+	return len(g.Unmet(id)) == 0
+}
+
+// Order returns the beads in the graph ordered so that every bead comes
+// after the beads it needs, otherwise in the order added. It fails with
+// ErrDependencyCycle, naming the beads, if some need each other.
+func (g *DependencyGraph) Order() ([]string, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	order := make([]string, 0, len(g.ids))
+	var path []string
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(path[slices.Index(path, id):], id)
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " → "))
+		}
+		state[id] = visiting
+		path = append(path, id)
+		for _, need := range g.needs[id] {
+			if g.issues[need] == nil {
+				continue
+			}
+			if err := visit(need); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[id] = visited
+		order = append(order, id)
+		return nil
+	}
+
+	for _, id := range g.ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
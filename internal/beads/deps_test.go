@@ -0,0 +1,58 @@
+package beads
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseDependencyFields(t *testing.T) {
+	needs, blocks := ParseDependencyFields("Wire the UI to the API.\n\nneeds: gt-api, gt-schema\nBlocks: gt-docs gt-release\nattached_args: fast")
+	if want := []string{"gt-api", "gt-schema"}; !reflect.DeepEqual(needs, want) {
+		t.Errorf("needs = %v, want %v", needs, want)
+	}
+	if want := []string{"gt-docs", "gt-release"}; !reflect.DeepEqual(blocks, want) {
+		t.Errorf("blocks = %v, want %v", blocks, want)
+	}
+}
+
+func TestDependencyGraph(t *testing.T) {
+	g := NewDependencyGraph([]*Issue{
+		{ID: "gt-ui", Status: "open", Description: "needs: gt-api"},
+		{ID: "gt-api", Status: "open", Dependencies: []IssueDep{
+			{ID: "gt-schema", DependencyType: "blocks"},
+			{ID: "gt-epic", DependencyType: "parent-child"},
+		}},
+		{ID: "gt-schema", Status: "closed", Description: "blocks: gt-ui"},
+		{ID: "gt-docs", Status: "open", Description: "needs: gt-gone"},
+	})
+
+	if got, want := g.Needs("gt-ui"), []string{"gt-api", "gt-schema"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Needs(gt-ui) = %v, want %v", got, want)
+	}
+	if got, want := g.Unmet("gt-ui"), []string{"gt-api"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmet(gt-ui) = %v, want %v", got, want)
+	}
+	if !g.Ready("gt-api") {
+		t.Errorf("gt-api needs only closed gt-schema, want ready")
+	}
+	if g.Ready("gt-docs") {
+		t.Errorf("gt-docs needs gt-gone, which isn't known to be done, want not ready")
+	}
+	if got, want := g.Missing(), []string{"gt-gone"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Missing() = %v, want %v", got, want)
+	}
+
+	order, err := g.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"gt-schema", "gt-api", "gt-ui", "gt-docs"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("Order() = %v, want %v", order, want)
+	}
+
+	g.Add(&Issue{ID: "gt-schema", Status: "open", Description: "needs: gt-ui"})
+	if _, err := g.Order(); !errors.Is(err, ErrDependencyCycle) {
+		t.Errorf("Order() with gt-schema needing gt-ui = %v, want ErrDependencyCycle", err)
+	}
+}
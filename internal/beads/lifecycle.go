@@ -0,0 +1,232 @@
+// Package beads provides the bead lifecycle state machine.
+package beads
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/store"
+)
+
+// Stage is where a bead is in its lifecycle. It is tracked by Gas Town
+// alongside the bead's bd status, which only says open/hooked/closed.
+type Stage string
+
+// Lifecycle stages, in the order work normally moves through them.
+const (
+	StageQueued     Stage = "queued"      // Ready to be worked, on nobody's hook
+	StageAssigned   Stage = "assigned"    // Slung or hooked to an agent
+	StageInProgress Stage = "in-progress" // The agent has started on it
+	StageReview     Stage = "review"      // Submitted to the merge queue
+	StageDone       Stage = "done"        // Merged or otherwise completed
+	StageFailed     Stage = "failed"      // Escalated or rejected
+)
+
+// Stages lists every stage in lifecycle order.
+var Stages = []Stage{StageQueued, StageAssigned, StageInProgress, StageReview, StageDone, StageFailed}
+
+// stageTransitions lists the stages each stage may move to. Forward moves
+// may skip stages (a bead can be closed straight from assigned); the
+// backward moves are releases, re-slings, reviews sending work back, and
+// retries or reopens.
+var stageTransitions = map[Stage][]Stage{
+	StageQueued:     {StageAssigned, StageInProgress, StageDone, StageFailed},
+	StageAssigned:   {StageQueued, StageAssigned, StageInProgress, StageReview, StageDone, StageFailed},
+	StageInProgress: {StageQueued, StageAssigned, StageReview, StageDone, StageFailed},
+	StageReview:     {StageInProgress, StageDone, StageFailed},
+	StageDone:       {StageQueued},
+	StageFailed:     {StageQueued, StageAssigned},
+}
+
+var (
+	// ErrInvalidStage indicates a stage name that isn't one of Stages.
+	ErrInvalidStage = errors.New("invalid lifecycle stage")
+
+	// ErrInvalidTransition indicates a move the lifecycle doesn't allow.
+	ErrInvalidTransition = errors.New("invalid lifecycle transition")
+)
+
+// ParseStage returns the stage named s.
+func ParseStage(s string) (Stage, error) {
+	if slices.Contains(Stages, Stage(s)) {
+		return Stage(s), nil
+	}
+	return "", fmt.Errorf("%w: %q (want one of %v)", ErrInvalidStage, s, Stages)
+}
+
+// CanTransition reports whether a bead may move from one stage to another.
+func CanTransition(from, to Stage) bool {
+	return slices.Contains(stageTransitions[from], to)
+}
+
+// Transition is one recorded move of a bead between stages.
+type Transition struct {
+	Bead    string    `json:"bead"`
+	From    Stage     `json:"from"`
+	To      Stage     `json:"to"`
+	Agent   string    `json:"agent,omitempty"`   // Who holds the bead after the move
+	Trigger string    `json:"trigger,omitempty"` // What moved it, e.g. "gt sling"
+	Note    string    `json:"note,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// BeadState is a bead's current stage, folded from its transitions.
+type BeadState struct {
+	Bead    string    `json:"bead"`
+	Stage   Stage     `json:"stage"`
+	Agent   string    `json:"agent,omitempty"`
+	Updated time.Time `json:"updated,omitempty"` // Zero for a bead never moved
+}
+
+// Lifecycle is a town's record of bead stage transitions: an append-only
+// JSONL log, so a crash loses at most the transition being written and the
+// current stage of every bead can be rebuilt by replaying it.
+type Lifecycle struct {
+	path string
+}
+
+// LifecyclePath returns the town's lifecycle log.
+func LifecyclePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "beads", "lifecycle.jsonl")
+}
+
+// NewLifecycle returns the lifecycle of the town at townRoot.
+func NewLifecycle(townRoot string) *Lifecycle {
+	return &Lifecycle{path: LifecyclePath(townRoot)}
+}
+
+// State returns bead's current stage. A bead with no transitions is queued.
+func (l *Lifecycle) State(bead string) (BeadState, error) {
+	history, err := l.History(bead)
+	if err != nil {
+		return BeadState{}, err
+	}
+	return foldState(bead, history), nil
+}
+
+// History returns bead's transitions, oldest first.
+func (l *Lifecycle) History(bead string) ([]Transition, error) {
+	all, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+	var history []Transition
+	for _, t := range all {
+		if t.Bead == bead {
+			history = append(history, t)
+		}
+	}
+	return history, nil
+}
+
+// Advance moves bead to stage to, held by agent, and records the move.
+// Moving a bead to the stage and agent it already has is a no-op and
+// returns a zero Transition. A move the lifecycle doesn't allow from the
+// bead's current stage fails with ErrInvalidTransition.
+func (l *Lifecycle) Advance(bead string, to Stage, agent, trigger, note string) (Transition, error) {
+	if _, err := ParseStage(string(to)); err != nil {
+		return Transition{}, err
+	}
+	var t Transition
+	err := l.withLock(func() error {
+		history, err := l.History(bead)
+		if err != nil {
+			return err
+		}
+		cur := foldState(bead, history)
+		if cur.Stage == to && cur.Agent == agent {
+			return nil
+		}
+		if !CanTransition(cur.Stage, to) {
+			return fmt.Errorf("%w: %s is %s, cannot move to %s", ErrInvalidTransition, bead, cur.Stage, to)
+		}
+		t = Transition{Bead: bead, From: cur.Stage, To: to, Agent: agent, Trigger: trigger, Note: note, At: time.Now().UTC()}
+		return l.append(t)
+	})
+	return t, err
+}
+
+// foldState replays history (oldest first) into bead's current state.
+func foldState(bead string, history []Transition) BeadState {
+	state := BeadState{Bead: bead, Stage: StageQueued}
+	for _, t := range history {
+		state.Stage, state.Agent, state.Updated = t.To, t.Agent, t.At
+	}
+	return state
+}
+
+func (l *Lifecycle) read() ([]Transition, error) {
+	f, err := os.Open(l.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading lifecycle log: %w", err)
+	}
+	defer f.Close()
+
+	var all []Transition
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var t Transition
+		if err := json.Unmarshal(scanner.Bytes(), &t); err != nil {
+			continue // A line torn by a crash mid-write; the rest is intact
+		}
+		all = append(all, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading lifecycle log: %w", err)
+	}
+	return all, nil
+}
+
+func (l *Lifecycle) append(t Transition) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("marshaling transition: %w", err)
+	}
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0644) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening lifecycle log: %w", err)
+	}
+	line := append(data, '\n')
+	if tornTail(f) {
+		line = append([]byte{'\n'}, line...) // Don't run on from a torn line
+	}
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("writing transition: %w", err)
+	}
+	// Synced so a recorded transition survives the machine going down
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("syncing lifecycle log: %w", err)
+	}
+	return f.Close()
+}
+
+// tornTail reports whether f ends in a partial line.
+func tornTail(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return false
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return false
+	}
+	return last[0] != '\n'
+}
+
+// withLock runs fn holding the lifecycle lock, so that checking a bead's
+// stage and recording its move happen as one step across gt processes.
+func (l *Lifecycle) withLock(fn func() error) error {
+	return store.WithLock(l.path, fn)
+}
@@ -0,0 +1,125 @@
+package beads
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestParseStage(t *testing.T) {
+	for _, s := range Stages {
+		if got, err := ParseStage(string(s)); err != nil || got != s {
+			t.Errorf("ParseStage(%q) = %q, %v", s, got, err)
+		}
+	}
+	if _, err := ParseStage("merged"); !errors.Is(err, ErrInvalidStage) {
+		t.Errorf("ParseStage(merged) = %v, want ErrInvalidStage", err)
+	}
+}
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to Stage
+		want     bool
+	}{
+		{StageQueued, StageAssigned, true},
+		{StageAssigned, StageInProgress, true},
+		{StageAssigned, StageAssigned, true}, // Re-sling to another agent
+		{StageInProgress, StageReview, true},
+		{StageReview, StageDone, true},
+		{StageReview, StageInProgress, true}, // Sent back
+		{StageFailed, StageQueued, true},     // Retry
+		{StageDone, StageQueued, true},       // Reopened
+		{StageQueued, StageReview, false},
+		{StageDone, StageReview, false},
+		{StageDone, StageFailed, false},
+		{StageReview, StageQueued, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestLifecycleAdvance(t *testing.T) {
+	lc := NewLifecycle(t.TempDir())
+	state, err := lc.State("gt-abc")
+	if err != nil || state.Stage != StageQueued || !state.Updated.IsZero() {
+		t.Fatalf("State of an untracked bead = %+v, %v; want queued", state, err)
+	}
+
+	steps := []struct {
+		to    Stage
+		agent string
+	}{
+		{StageAssigned, "gastown/polecats/toast"},
+		{StageInProgress, "gastown/polecats/toast"},
+		{StageReview, "gastown/polecats/toast"},
+		{StageDone, "gastown/refinery"},
+	}
+	for _, s := range steps {
+		if _, err := lc.Advance("gt-abc", s.to, s.agent, "test", ""); err != nil {
+			t.Fatalf("Advance(%s): %v", s.to, err)
+		}
+	}
+	if _, err := lc.Advance("gt-xyz", StageAssigned, "gastown/polecats/nux", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	state, _ = lc.State("gt-abc")
+	if state.Stage != StageDone || state.Agent != "gastown/refinery" {
+		t.Errorf("State = %+v, want done by gastown/refinery", state)
+	}
+	history, _ := lc.History("gt-abc")
+	if len(history) != len(steps) || history[0].From != StageQueued || history[3].From != StageReview {
+		t.Errorf("History = %+v, want the %d moves in order", history, len(steps))
+	}
+
+	if _, err := lc.Advance("gt-abc", StageReview, "", "test", ""); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("Advance(done → review) = %v, want ErrInvalidTransition", err)
+	}
+	if _, err := lc.Advance("gt-abc", "merged", "", "test", ""); !errors.Is(err, ErrInvalidStage) {
+		t.Errorf("Advance(merged) = %v, want ErrInvalidStage", err)
+	}
+}
+
+func TestLifecycleAdvanceNoop(t *testing.T) {
+	lc := NewLifecycle(t.TempDir())
+	if _, err := lc.Advance("gt-abc", StageInProgress, "gastown/crew/max", "gt prime", ""); err != nil {
+		t.Fatal(err)
+	}
+	tr, err := lc.Advance("gt-abc", StageInProgress, "gastown/crew/max", "gt prime", "")
+	if err != nil || tr.To != "" {
+		t.Errorf("repeated Advance = %+v, %v; want a no-op", tr, err)
+	}
+	if history, _ := lc.History("gt-abc"); len(history) != 1 {
+		t.Errorf("History after a no-op = %+v, want one move", history)
+	}
+}
+
+func TestLifecycleSkipsTornLines(t *testing.T) {
+	townRoot := t.TempDir()
+	lc := NewLifecycle(townRoot)
+	if _, err := lc.Advance("gt-abc", StageAssigned, "gastown/polecats/toast", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	// A crash mid-write leaves a partial line at the end of the log
+	f, err := os.OpenFile(LifecyclePath(townRoot), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"bead":"gt-abc","from":"assigned","to":"in-pro`)
+	_ = f.Close()
+
+	state, err := lc.State("gt-abc")
+	if err != nil || state.Stage != StageAssigned {
+		t.Fatalf("State with a torn line = %+v, %v; want assigned", state, err)
+	}
+	if _, err := lc.Advance("gt-abc", StageInProgress, "gastown/polecats/toast", "test", ""); err != nil {
+		t.Fatalf("Advance after a torn line: %v", err)
+	}
+	if state, _ := lc.State("gt-abc"); state.Stage != StageInProgress {
+		t.Errorf("State after writing past a torn line = %+v, want in-progress", state)
+	}
+}
@@ -0,0 +1,42 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// HookMatcher is a Claude Code hook entry: the hooks to run for the tool
+// calls (or other event subjects) Matcher selects. An empty Matcher
+// selects everything.
+type HookMatcher struct {
+	Matcher string `json:"matcher"`
+	Hooks   []Hook `json:"hooks"`
+}
+
+// Hook is one hook to run.
+type Hook struct {
+	Type    string `json:"type"`
+	Command string `json:"command,omitempty"`
+	Timeout int    `json:"timeout,omitempty"`
+}
+
+// Hooks returns Gas Town's hook definitions for roleType, keyed by Claude
+// Code event name (SessionStart, PreToolUse, ...). These are the canonical
+// definitions other agents' hook configs are translated from.
+func Hooks(roleType RoleType) (map[string][]HookMatcher, error) {
+	templateName := "config/settings-interactive.json"
+	if roleType == Autonomous {
+		templateName = "config/settings-autonomous.json"
+	}
+	content, err := configFS.ReadFile(templateName)
+	if err != nil {
+		return nil, fmt.Errorf("reading template %s: %w", templateName, err)
+	}
+	var settings struct {
+		Hooks map[string][]HookMatcher `json:"hooks"`
+	}
+	if err := json.Unmarshal(content, &settings); err != nil {
+		return nil, fmt.Errorf("parsing template %s: %w", templateName, err)
+	}
+	return settings.Hooks, nil
+}
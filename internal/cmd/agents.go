@@ -6,10 +6,12 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/lock"
 	"github.com/steveyegge/gastown/internal/style"
@@ -75,8 +77,15 @@ The menu appears as a tmux popup for quick session switching.`,
 var agentsListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List agent sessions (no popup)",
-	Long:  `List all agent sessions to stdout without the popup menu.`,
-	RunE:  runAgentsList,
+	Long: `List all agent sessions to stdout without the popup menu.
+
+With --supports, list the agent presets that have a capability instead
+(hooks, fork, resume, mcp, json-output), one name per line.
+
+Examples:
+  gt agents list
+  gt agents list --supports fork`,
+	RunE: runAgentsList,
 }
 
 var agentsCheckCmd = &cobra.Command{
@@ -111,13 +120,15 @@ For collisions with live processes, you must manually:
 }
 
 var (
-	agentsAllFlag   bool
-	agentsCheckJSON bool
+	agentsAllFlag      bool
+	agentsCheckJSON    bool
+	agentsListSupports string
 )
 
 func init() {
 	agentsCmd.PersistentFlags().BoolVarP(&agentsAllFlag, "all", "a", false, "Include polecats in the menu")
 	agentsCheckCmd.Flags().BoolVar(&agentsCheckJSON, "json", false, "Output as JSON")
+	agentsListCmd.Flags().StringVar(&agentsListSupports, "supports", "", "List agent presets with this capability ("+strings.Join(config.AgentCapabilities(), ", ")+")")
 
 	agentsCmd.AddCommand(agentsListCmd)
 	agentsCmd.AddCommand(agentsCheckCmd)
@@ -344,6 +355,10 @@ func runAgents(cmd *cobra.Command, args []string) error {
 }
 
 func runAgentsList(cmd *cobra.Command, args []string) error {
+	if agentsListSupports != "" {
+		return runAgentsListSupports(agentsListSupports)
+	}
+
 	agents, err := getAgentSessions(agentsAllFlag)
 	if err != nil {
 		return fmt.Errorf("listing sessions: %w", err)
@@ -385,6 +400,24 @@ func runAgentsList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runAgentsListSupports prints the presets (built-in, plus the town's
+// settings/agents.json when run inside a town) that support capability.
+func runAgentsListSupports(capability string) error {
+	if !slices.Contains(config.AgentCapabilities(), capability) {
+		return fmt.Errorf("unknown capability %q (valid: %s)", capability, strings.Join(config.AgentCapabilities(), ", "))
+	}
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if err := config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot)); err != nil {
+			return fmt.Errorf("loading agent registry: %w", err)
+		}
+	}
+
+	for _, name := range config.ListAgentPresetsWithCapability(capability) {
+		fmt.Println(name)
+	}
+	return nil
+}
+
 // CollisionReport holds the results of a collision check.
 type CollisionReport struct {
 	TotalSessions int                    `json:"total_sessions"`
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var agentsLaunchCmd = &cobra.Command{
+	Use:   "launch-command <name>",
+	Short: "Print the command that would launch an agent",
+	Long: `Print the exact command line Gas Town would run to launch an agent,
+and the environment it sets, without running anything.
+
+The runtime config is resolved the way sessions resolve it: inside a town,
+town and rig settings (custom agents, args overrides) apply; elsewhere the
+built-in preset is used. --model adds the agent's model flag, and --resume
+prints the command that resumes that session ID instead (with --resume-flag
+in place of the preset's resume flag, if given).
+
+Examples:
+  gt agents launch-command kimi
+  gt agents launch-command kimi --model kimi-k2-turbo
+  gt agents launch-command claude --resume 0b6c5f2e --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentsLaunchCommand,
+}
+
+var (
+	agentsLaunchModel  string
+	agentsLaunchResume string
+	agentsLaunchFlag   string
+	agentsLaunchJSON   bool
+)
+
+func init() {
+	agentsLaunchCmd.Flags().StringVar(&agentsLaunchModel, "model", "", "Model to launch the agent with (via its model flag)")
+	agentsLaunchCmd.Flags().StringVar(&agentsLaunchResume, "resume", "", "Print the command that resumes this session ID")
+	agentsLaunchCmd.Flags().StringVar(&agentsLaunchFlag, "resume-flag", "", "Resume flag to use with --resume (overrides the preset's)")
+	agentsLaunchCmd.Flags().BoolVar(&agentsLaunchJSON, "json", false, "Output as JSON")
+	agentsCmd.AddCommand(agentsLaunchCmd)
+}
+
+// AgentLaunch is what gt agents launch-command prints.
+type AgentLaunch struct {
+	Agent   string            `json:"agent"`
+	Command string            `json:"command"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+func runAgentsLaunchCommand(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd()
+	if agentsLaunchFlag != "" && agentsLaunchResume == "" {
+		return fmt.Errorf("--resume-flag requires --resume")
+	}
+	launch, err := agentLaunch(townRoot, args[0], agentsLaunchModel, agentsLaunchResume, agentsLaunchFlag)
+	if err != nil {
+		return err
+	}
+
+	if agentsLaunchJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(launch)
+	}
+	printAgentLaunch(launch)
+	return nil
+}
+
+// agentLaunch builds the launch (or, with resume, resume) command for agent
+// name; a non-empty resumeFlag replaces the preset's resume flag. townRoot
+// may be empty, in which case only built-in presets resolve.
+func agentLaunch(townRoot, name, model, resume, resumeFlag string) (*AgentLaunch, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	var rc *config.RuntimeConfig
+	switch {
+	case townRoot != "":
+		var err error
+		if rc, _, err = config.ResolveAgentConfigWithOverride(townRoot, "", name); err != nil {
+			return nil, err
+		}
+	case config.GetAgentPresetByName(name) != nil:
+		rc = config.RuntimeConfigFromPreset(config.AgentPreset(name))
+	default:
+		known := config.ListAgentPresets()
+		sort.Strings(known)
+		return nil, fmt.Errorf("unknown agent preset %q (available: %s)", name, strings.Join(known, ", "))
+	}
+
+	rc.Model = model
+	rc.ResumeFlag = resumeFlag
+	if err := rc.Validate(); err != nil {
+		return nil, err
+	}
+
+	launch := &AgentLaunch{Agent: name, Env: rc.Env}
+	if resume == "" {
+		// Env is reported on its own; keep it out of the command
+		bare := rc.Clone()
+		bare.Env = nil
+		launch.Command = bare.BuildCommand()
+		return launch, nil
+	}
+	resumeCmd, err := config.BuildResumeCommandWithConfig(name, resume, rc)
+	if err != nil {
+		return nil, err
+	}
+	if resumeCmd == "" {
+		return nil, fmt.Errorf("%w: %s", config.ErrResumeUnsupported, name)
+	}
+	launch.Command = resumeCmd
+	return launch, nil
+}
+
+func printAgentLaunch(l *AgentLaunch) {
+	fmt.Printf("%s\n", style.Bold.Render("Agent: "+l.Agent))
+	fmt.Printf("Command: %s\n", l.Command)
+	if len(l.Env) == 0 {
+		fmt.Printf("Env:     %s\n", orNone(""))
+		return
+	}
+	keys := make([]string, 0, len(l.Env))
+	for k := range l.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Println("Env:")
+	for _, k := range keys {
+		fmt.Printf("  %s=%s\n", k, config.ShellQuote(l.Env[k]))
+	}
+}
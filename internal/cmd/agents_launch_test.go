@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestAgentLaunchKimi(t *testing.T) {
+	tests := []struct {
+		name   string
+		model  string
+		resume string
+		want   string
+	}{
+		{"default", "", "", "kimi --yolo"},
+		{"model", "kimi-k2-turbo", "", "kimi --yolo --model kimi-k2-turbo"},
+		{"resume", "", "sess-1", "kimi --yolo --continue sess-1"},
+		{"model and resume", "kimi-k2-turbo", "sess-1", "kimi --yolo --model kimi-k2-turbo --continue sess-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			launch, err := agentLaunch("", "kimi", tt.model, tt.resume, "")
+			if err != nil {
+				t.Fatalf("agentLaunch: %v", err)
+			}
+			if launch.Command != tt.want {
+				t.Errorf("command = %q, want %q", launch.Command, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgentLaunchPrintsEnv(t *testing.T) {
+	launch, err := agentLaunch("", "opencode", "", "", "")
+	if err != nil {
+		t.Fatalf("agentLaunch: %v", err)
+	}
+	out := captureStdout(t, func() { printAgentLaunch(launch) })
+	for _, want := range []string{"Agent: opencode", "Command: opencode", `OPENCODE_PERMISSION='{"*":"allow"}'`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAgentLaunchErrors(t *testing.T) {
+	if _, err := agentLaunch("", "nope", "", "", ""); err == nil || !strings.Contains(err.Error(), "kimi") {
+		t.Errorf("unknown agent: err = %v, want the available presets listed", err)
+	}
+	if _, err := agentLaunch("", "amp", "gpt-5", "", ""); !errors.Is(err, config.ErrModelUnsupported) {
+		t.Errorf("model without a model flag: err = %v, want ErrModelUnsupported", err)
+	}
+}
+
+func TestAgentLaunchOpenCodeResume(t *testing.T) {
+	launch, err := agentLaunch("", "opencode", "anthropic/claude-sonnet-4", "ses_1", "")
+	if err != nil {
+		t.Fatalf("agentLaunch: %v", err)
+	}
+	if want := "opencode --session ses_1 --model anthropic/claude-sonnet-4"; launch.Command != want {
+		t.Errorf("command = %q, want %q", launch.Command, want)
+	}
+}
+
+func TestAgentLaunchResumeFlagOverride(t *testing.T) {
+	launch, err := agentLaunch("", "claude", "", "abc-123", "--continue")
+	if err != nil {
+		t.Fatalf("agentLaunch: %v", err)
+	}
+	if !strings.Contains(launch.Command, "--continue abc-123") || strings.Contains(launch.Command, "--resume") {
+		t.Errorf("command = %q, want the --continue override", launch.Command)
+	}
+}
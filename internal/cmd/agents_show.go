@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var agentsShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show full detail of an agent preset",
+	Long: `Show every field of an agent preset and the runtime defaults derived from it.
+
+Prints the preset's command, args, process names, session and resume
+settings, capabilities, and the resolved runtime config (hooks directory,
+instructions file, tmux readiness heuristics) used when launching the agent.
+
+Examples:
+  gt agents show kimi
+  gt agents show claude --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runAgentsShow,
+}
+
+var agentsShowJSON bool
+
+func init() {
+	agentsShowCmd.Flags().BoolVar(&agentsShowJSON, "json", false, "Output as JSON")
+	agentsCmd.AddCommand(agentsShowCmd)
+}
+
+// AgentPresetDetail is the JSON shape of gt agents show.
+type AgentPresetDetail struct {
+	Preset  *config.AgentPresetInfo `json:"preset"`
+	Runtime *config.RuntimeConfig   `json:"runtime"`
+	Launch  string                  `json:"launch_command"`
+}
+
+func runAgentsShow(cmd *cobra.Command, args []string) error {
+	detail, err := agentPresetDetail(args[0])
+	if err != nil {
+		return err
+	}
+
+	if agentsShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(detail)
+	}
+
+	printAgentPresetDetail(detail)
+	return nil
+}
+
+// agentPresetDetail looks up a preset by normalized name and derives its
+// runtime defaults. Unknown names produce an error listing the known presets.
+func agentPresetDetail(name string) (*AgentPresetDetail, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	preset := config.GetAgentPresetByName(name)
+	if preset == nil {
+		known := config.ListAgentPresets()
+		sort.Strings(known)
+		return nil, fmt.Errorf("unknown agent preset %q (available: %s)", name, strings.Join(known, ", "))
+	}
+
+	// Resolve with the preset as provider; left empty, Resolved fills in
+	// claude's defaults whatever the agent
+	rc := config.RuntimeConfigFromPreset(preset.Name)
+	rc.Provider = string(preset.Name)
+	rc = rc.Resolved()
+	return &AgentPresetDetail{
+		Preset:  preset,
+		Runtime: rc,
+		Launch:  rc.BuildCommand(),
+	}, nil
+}
+
+func printAgentPresetDetail(d *AgentPresetDetail) {
+	p, rc := d.Preset, d.Runtime
+
+	fmt.Printf("%s\n", style.Bold.Render("Agent: "+string(p.Name)))
+	if p.Description != "" {
+		fmt.Printf("%s\n", style.Dim.Render(p.Description))
+	}
+	fmt.Println()
+	fmt.Printf("Command:         %s\n", p.Command)
+	fmt.Printf("Args:            %s\n", orNone(strings.Join(p.Args, " ")))
+	fmt.Printf("Process names:   %s\n", orNone(strings.Join(p.ProcessNames, ", ")))
+	if len(p.Env) > 0 {
+		keys := make([]string, 0, len(p.Env))
+		for k := range p.Env {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fmt.Println("Env:")
+		for _, k := range keys {
+			fmt.Printf("  %s=%s\n", k, p.Env[k])
+		}
+	}
+	fmt.Printf("Session ID env:  %s\n", orNone(p.SessionIDEnv))
+	if p.ResumeFlag != "" && p.ResumeTemplate != "" {
+		fmt.Printf("Resume:          %s (template %q)\n", p.ResumeFlag, p.ResumeTemplate)
+	} else if p.ResumeFlag != "" {
+		fmt.Printf("Resume:          %s (%s)\n", p.ResumeFlag, p.ResumeStyle)
+	} else {
+		fmt.Printf("Resume:          %s\n", orNone(""))
+	}
+	fmt.Printf("MCP config flag: %s\n", orNone(p.MCPConfigFlag))
+	fmt.Printf("Model flag:      %s\n", orNone(p.ModelFlag))
+	fmt.Printf("JSON output:     %s\n", orNone(p.OutputJSONFlag))
+	fmt.Printf("Hooks dir:       %s\n", orNone(p.HooksDir))
+	fmt.Printf("Instructions:    %s\n", orNone(p.InstructionsFile))
+	fmt.Printf("Priming prompt:  %s\n", orNone(p.PrimingPrompt))
+	if ni := p.NonInteractive; ni != nil {
+		fmt.Printf("Non-interactive: subcommand=%s prompt=%s output=%s\n",
+			orNone(ni.Subcommand), orNone(ni.PromptFlag), orNone(ni.OutputFlag))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Capabilities"))
+	fmt.Printf("  Hooks:         %v\n", p.Supports(config.CapHooks))
+	fmt.Printf("  Fork session:  %v\n", p.Supports(config.CapFork))
+	fmt.Printf("  Resume:        %v\n", p.Supports(config.CapResume))
+	fmt.Printf("  MCP config:    %v\n", p.Supports(config.CapMCP))
+	fmt.Printf("  JSON output:   %v\n", p.Supports(config.CapJSONOutput))
+	fmt.Printf("  Requires TTY:  %v\n", p.RequiresTTY)
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Runtime defaults"))
+	fmt.Printf("  Provider:          %s\n", rc.Provider)
+	fmt.Printf("  Prompt mode:       %s\n", rc.PromptMode)
+	fmt.Printf("  Hooks:             %s (%s/%s)\n", rc.Hooks.Provider, orNone(rc.Hooks.Dir), orNone(rc.Hooks.SettingsFile))
+	fmt.Printf("  Instructions file: %s\n", orNone(rc.Instructions.File))
+	fmt.Printf("  Config dir env:    %s\n", orNone(rc.Session.ConfigDirEnv))
+	fmt.Printf("  Ready prompt:      %q (delay %dms)\n", rc.Tmux.ReadyPromptPrefix, rc.Tmux.ReadyDelayMs)
+	fmt.Printf("  Launch command:    %s\n", d.Launch)
+}
+
+// orNone renders an empty value as a dimmed "(none)".
+func orNone(s string) string {
+	if s == "" {
+		return style.Dim.Render("(none)")
+	}
+	return s
+}
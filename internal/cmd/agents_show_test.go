@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAgentsShowKimi(t *testing.T) {
+	detail, err := agentPresetDetail(" Kimi ")
+	if err != nil {
+		t.Fatalf("agentPresetDetail(kimi): %v", err)
+	}
+
+	out := captureStdout(t, func() { printAgentPresetDetail(detail) })
+	for _, want := range []string{
+		"Agent: kimi",
+		"Moonshot Kimi K2.5 CLI in yolo mode",
+		"--yolo",
+		"KIMI_SESSION_ID",
+		"--continue (flag)",
+		"--mcp-config-file",
+		"Hooks dir:       .kimi",
+		"Launch command:    kimi --yolo",
+		// Runtime defaults are kimi's, not the claude fallback
+		"Provider:          kimi",
+		"Prompt mode:       arg",
+		"Hooks:             kimi (.kimi/settings.json)",
+		"Instructions file: AGENTS.md",
+		"Config dir env:    KIMI_CONFIG_DIR",
+		`Ready prompt:      "> "`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestAgentsShowJSON(t *testing.T) {
+	detail, err := agentPresetDetail("kimi")
+	if err != nil {
+		t.Fatalf("agentPresetDetail(kimi): %v", err)
+	}
+
+	data, err := json.Marshal(detail)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	var decoded struct {
+		Preset struct {
+			Command      string `json:"command"`
+			SessionIDEnv string `json:"session_id_env"`
+		} `json:"preset"`
+		Runtime struct {
+			Provider string `json:"provider"`
+		} `json:"runtime"`
+		Launch string `json:"launch_command"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded.Preset.Command != "kimi" || decoded.Preset.SessionIDEnv != "KIMI_SESSION_ID" {
+		t.Errorf("unexpected preset in JSON: %s", data)
+	}
+	if decoded.Runtime.Provider != "kimi" {
+		t.Errorf("runtime provider = %q, want kimi", decoded.Runtime.Provider)
+	}
+	if decoded.Launch != "kimi --yolo" {
+		t.Errorf("launch_command = %q, want %q", decoded.Launch, "kimi --yolo")
+	}
+}
+
+func TestAgentsShowUnknown(t *testing.T) {
+	_, err := agentPresetDetail("nope")
+	if err == nil {
+		t.Fatal("expected error for unknown preset")
+	}
+	if !strings.Contains(err.Error(), "kimi") || !strings.Contains(err.Error(), "claude") {
+		t.Errorf("error %q should list the available presets", err)
+	}
+}
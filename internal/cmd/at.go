@@ -0,0 +1,231 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/suggest"
+	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
+)
+
+// atPickerLimit caps how many matches gt at offers to pick from.
+const atPickerLimit = 20
+
+var atCmd = &cobra.Command{
+	Use:     "at <query>...",
+	GroupID: GroupAgents,
+	Short:   "Attach to the agent session that best matches a query",
+	Long: `Attach to any Gas Town session without remembering its exact name.
+
+The query is matched against every agent session's name, address, rig,
+role, worker name and tags (see gt tag), on this machine and on remote rigs'
+hosts. Words of the query, separated by spaces or '/', must all match; a
+word matches a field exactly, as its prefix, as a substring, or as letters
+in order (gtrf matches gt-gastown-refinery). A key=value word matches a tag.
+
+The best match is attached right away. When several sessions match equally
+well, gt at lists them and asks which one. Inside tmux it switches the
+client to the session, outside it attaches.
+
+Examples:
+  gt at mayor
+  gt at max                  # gastown/crew/max, if it's the only max
+  gt at gastown/ref          # gastown's refinery
+  gt at beads max            # max of the beads rig
+  gt at team=backend`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAt,
+}
+
+func init() {
+	rootCmd.AddCommand(atCmd)
+}
+
+func runAt(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd() // Outside a town, local sessions only
+	sessions := listTaggedSessions(townRoot)
+	if len(sessions) == 0 {
+		return fmt.Errorf("no agent sessions running (start some with gt up)")
+	}
+
+	query := strings.Join(args, " ")
+	matches := matchSessions(sessions, query)
+	if len(matches) == 0 {
+		names := make([]string, len(sessions))
+		for i, s := range sessions {
+			names[i] = s.Address
+		}
+		return fmt.Errorf("%s", suggest.FormatSuggestion("Session", query, suggest.FindSimilar(query, names, 3), "List sessions with: gt list"))
+	}
+
+	target := matches[0].Session
+	if len(matches) > 1 && matches[0].score == matches[1].score {
+		tied := 1
+		for tied < len(matches) && matches[tied].score == matches[0].score {
+			tied++
+		}
+		var err error
+		if target, err = pickSession(matches[:tied]); err != nil || target == "" {
+			return err
+		}
+	}
+	return attachAt(target)
+}
+
+// attachAt attaches to, or switches the client to, sess.
+func attachAt(sess string) error {
+	if isInTmuxSession(sess) {
+		fmt.Printf("Already in %s\n", sess)
+		return nil
+	}
+	fmt.Printf("%s Attaching to %s...\n", style.Bold.Render("→"), sess)
+	return attachToTmuxSession(sess)
+}
+
+// sessionMatch is a session matched by a gt at query, and how well.
+type sessionMatch struct {
+	TaggedSession
+	score int
+}
+
+// Match strengths of a query word against a session field.
+const (
+	matchSubsequence = 1 + iota
+	matchSubstring
+	matchPrefix
+	matchExact
+)
+
+// matchSessions returns the sessions every word of query matches, best
+// first. A session scores the sum of its words' best matches; ties keep
+// gt list order.
+func matchSessions(sessions []TaggedSession, query string) []sessionMatch {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return r == '/' || r == ' ' || r == '\t'
+	})
+	if len(words) == 0 {
+		return nil
+	}
+
+	var matches []sessionMatch
+	for _, s := range sessions {
+		fields := sessionFields(s)
+		total := 0
+		for _, w := range words {
+			best := 0
+			if key, value, ok := strings.Cut(w, "="); ok {
+				for k, v := range s.Tags {
+					if strings.EqualFold(k, key) && strings.EqualFold(v, value) {
+						best = matchExact
+					}
+				}
+			} else {
+				for _, f := range fields {
+					best = max(best, matchField(w, f))
+				}
+			}
+			if best == 0 {
+				total = 0
+				break
+			}
+			total += best
+		}
+		if total > 0 {
+			matches = append(matches, sessionMatch{TaggedSession: s, score: total})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	return matches
+}
+
+// sessionFields returns the lowercased strings a query word can match:
+// the session name, the address and its parts, and tag values.
+func sessionFields(s TaggedSession) []string {
+	fields := []string{s.Session, s.Address, s.Rig, s.Role}
+	fields = append(fields, strings.Split(s.Address, "/")...)
+	for _, v := range s.Tags {
+		fields = append(fields, v)
+	}
+	for i, f := range fields {
+		fields[i] = strings.ToLower(f)
+	}
+	return fields
+}
+
+// matchField returns how strongly word matches field, or 0.
+func matchField(word, field string) int {
+	switch {
+	case field == "":
+		return 0
+	case word == field:
+		return matchExact
+	case strings.HasPrefix(field, word):
+		return matchPrefix
+	case strings.Contains(field, word):
+		return matchSubstring
+	case isSubsequence(word, field):
+		return matchSubsequence
+	}
+	return 0
+}
+
+// isSubsequence reports whether the letters of word appear in s in order.
+func isSubsequence(word, s string) bool {
+	i := 0
+	for j := 0; i < len(word) && j < len(s); j++ {
+		if word[i] == s[j] {
+			i++
+		}
+	}
+	return i == len(word)
+}
+
+// pickSession asks which of the equally good matches to attach to, and
+// returns its session name, or "" if the user picked none. Without a
+// terminal to ask on, the matches are returned in the error.
+func pickSession(matches []sessionMatch) (string, error) {
+	if len(matches) > atPickerLimit {
+		matches = matches[:atPickerLimit]
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		var names []string
+		for _, m := range matches {
+			names = append(names, m.Address)
+		}
+		return "", fmt.Errorf("query matches several sessions: %s (add words to narrow it down)", strings.Join(names, ", "))
+	}
+
+	fmt.Printf("%s\n", style.Bold.Render("Several sessions match:"))
+	width := 0
+	for _, m := range matches {
+		width = max(width, len(m.Address))
+	}
+	for i, m := range matches {
+		line := fmt.Sprintf("  %2d) %-*s  %s", i+1, width, m.Address, style.Dim.Render(m.Session))
+		if m.Host != "" {
+			line += " " + style.Dim.Render("@"+m.Host)
+		}
+		if len(m.Tags) > 0 {
+			line += "  " + m.Tags.String()
+		}
+		fmt.Println(line)
+	}
+
+	p := &prompter{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+	for {
+		answer := p.line(fmt.Sprintf("Attach to (1-%d, q to cancel)", len(matches)), "1")
+		if answer == "q" {
+			return "", nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(matches) {
+			return matches[n-1].Session, nil
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestMatchSessions(t *testing.T) {
+	sessions := []TaggedSession{
+		{Session: "gt-beads-crew-max", Address: "beads/crew/max", Role: "crew", Rig: "beads"},
+		{Session: "gt-gastown-crew-max", Address: "gastown/crew/max", Role: "crew", Rig: "gastown", Tags: session.Tags{"team": "backend"}},
+		{Session: "gt-gastown-maxwell", Address: "gastown/polecats/maxwell", Role: "polecat", Rig: "gastown"},
+		{Session: "gt-gastown-refinery", Address: "gastown/refinery", Role: "refinery", Rig: "gastown"},
+		{Session: "hq-mayor", Address: "mayor", Role: "mayor"},
+	}
+
+	tests := []struct {
+		query string
+		want  []string // Sessions tied for the best match
+	}{
+		{"mayor", []string{"hq-mayor"}},
+		{"max", []string{"gt-beads-crew-max", "gt-gastown-crew-max"}},
+		{"beads max", []string{"gt-beads-crew-max"}},
+		{"gastown/max", []string{"gt-gastown-crew-max"}},
+		{"gastown/ref", []string{"gt-gastown-refinery"}},
+		{"maxw", []string{"gt-gastown-maxwell"}},
+		{"gtrf", []string{"gt-gastown-refinery"}},
+		{"team=backend", []string{"gt-gastown-crew-max"}},
+		{"TEAM=Backend max", []string{"gt-gastown-crew-max"}},
+		{"witness", nil},
+		{"max team=frontend", nil},
+		{" / ", nil},
+	}
+	for _, tt := range tests {
+		matches := matchSessions(sessions, tt.query)
+		var got []string
+		for _, m := range matches {
+			if m.score == matches[0].score {
+				got = append(got, m.Session)
+			}
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("matchSessions(%q) best = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("matchSessions(%q) best = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestIsSubsequence(t *testing.T) {
+	for _, tt := range []struct {
+		word, s string
+		want    bool
+	}{
+		{"gstcmx", "gt-gastown-crew-max", true},
+		{"", "anything", true},
+		{"xam", "max", false},
+		{"maxx", "max", false},
+	} {
+		if got := isSubsequence(tt.word, tt.s); got != tt.want {
+			t.Errorf("isSubsequence(%q, %q) = %v, want %v", tt.word, tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadGraphJSON bool
+	beadGraphDot  bool
+)
+
+var beadGraphCmd = &cobra.Command{
+	Use:   "graph [bead-id...]",
+	Short: "Show the dependency graph of beads",
+	Long: `Show which beads need which others done before they can be worked.
+
+A bead declares dependencies in its description:
+
+  needs: gt-abc, gt-def    # This bead can't start until these are closed
+  blocks: gt-xyz           # gt-xyz can't start until this bead is closed
+
+bd dependencies of type "blocks" (bd dep add) count too. gt sling only
+dispatches a bead once everything it needs is done (closed), so agents
+don't start work that can't land; --ignore-deps overrides that.
+
+With bead IDs, shows those beads and everything they need; otherwise the
+open beads of the current directory's beads database. Beads are listed so
+that each comes after the beads it needs:
+
+  done      Closed
+  ready     Open, and everything it needs is done
+  waiting   Needs beads that aren't done yet
+  (other)   The bd status of work under way, e.g. hooked
+
+Examples:
+  gt bead graph                  # Open beads in this rig
+  gt bead graph gt-abc           # gt-abc and what it needs
+  gt bead graph --dot | dot -Tsvg > beads.svg`,
+	RunE: runBeadGraph,
+}
+
+func init() {
+	beadGraphCmd.Flags().BoolVar(&beadGraphJSON, "json", false, "Output as JSON")
+	beadGraphCmd.Flags().BoolVar(&beadGraphDot, "dot", false, "Output as a Graphviz digraph")
+	beadCmd.AddCommand(beadGraphCmd)
+}
+
+// errBeadWaiting is returned by checkBeadNeeds for a bead that needs beads
+// that aren't done yet.
+var errBeadWaiting = errors.New("waiting on dependencies")
+
+// beadGraphNode is a bead in gt bead graph --json output.
+type beadGraphNode struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title,omitempty"`
+	Status string   `json:"status,omitempty"` // Empty for a bead that wasn't found
+	State  string   `json:"state"`
+	Needs  []string `json:"needs,omitempty"`
+	Unmet  []string `json:"unmet,omitempty"`
+}
+
+func runBeadGraph(cmd *cobra.Command, args []string) error {
+	if beadGraphJSON && beadGraphDot {
+		return fmt.Errorf("--json and --dot can't be used together")
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+	g, err := loadBeadGraph(townRoot, args)
+	if err != nil {
+		return err
+	}
+	order, err := g.Order()
+	if err != nil {
+		return err
+	}
+	if len(args) > 0 {
+		order = beadGraphClosure(g, order, args)
+	}
+
+	switch {
+	case beadGraphJSON:
+		nodes := make([]beadGraphNode, 0, len(order))
+		for _, id := range order {
+			nodes = append(nodes, newBeadGraphNode(g, id))
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(nodes)
+	case beadGraphDot:
+		return printBeadGraphDot(os.Stdout, g, order)
+	}
+
+	if len(order) == 0 {
+		fmt.Println("No open beads.")
+		return nil
+	}
+	return printBeadGraph(os.Stdout, g, order)
+}
+
+// loadBeadGraph builds the dependency graph of beadIDs - or, with none, of
+// the open beads in the current directory's beads database - and of the
+// beads they need. Beads are looked up in their own rig's database, found
+// from their prefix, whose open beads are read too for blocks: lines that
+// name them.
+func loadBeadGraph(townRoot string, beadIDs []string) (*beads.DependencyGraph, error) {
+	g := beads.NewDependencyGraph(nil)
+	if len(beadIDs) == 0 {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return nil, err
+		}
+		issues, err := beads.New(cwd).List(beads.ListOptions{Priority: -1})
+		if err != nil {
+			return nil, fmt.Errorf("listing beads: %w", err)
+		}
+		g.Add(issues...)
+	} else {
+		var dirs []string
+		for _, id := range beadIDs {
+			if dir := beads.ResolveHookDir(townRoot, id, ""); !slices.Contains(dirs, dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+		for _, dir := range dirs {
+			issues, err := beads.New(dir).List(beads.ListOptions{Priority: -1})
+			if err != nil {
+				return nil, fmt.Errorf("listing beads: %w", err)
+			}
+			g.Add(issues...)
+		}
+		// bd list leaves out the details of bd dependencies; show has them.
+		if err := addBeadsToGraph(g, townRoot, beadIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	// Look up what the unfinished beads need, and what those need in turn.
+	// Beads that can't be found stay missing.
+	tried := make(map[string]bool)
+	for {
+		var missing []string
+		for _, id := range g.IDs() {
+			if g.Done(id) {
+				continue
+			}
+			for _, need := range g.Needs(id) {
+				if g.Issue(need) == nil && !tried[need] {
+					tried[need] = true
+					missing = append(missing, need)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			return g, nil
+		}
+		if err := addBeadsToGraph(g, townRoot, missing); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// addBeadsToGraph adds the beads with ids, shown from their rigs'
+// databases, to g.
+func addBeadsToGraph(g *beads.DependencyGraph, townRoot string, ids []string) error {
+	byDir := make(map[string][]string)
+	var dirs []string
+	for _, id := range ids {
+		dir := beads.ResolveHookDir(townRoot, id, "")
+		if _, ok := byDir[dir]; !ok {
+			dirs = append(dirs, dir)
+		}
+		byDir[dir] = append(byDir[dir], id)
+	}
+	for _, dir := range dirs {
+		shown, err := beads.New(dir).ShowMultiple(byDir[dir])
+		if err != nil {
+			return fmt.Errorf("showing beads: %w", err)
+		}
+		for _, id := range byDir[dir] {
+			if issue := shown[id]; issue != nil {
+				g.Add(issue)
+			}
+		}
+	}
+	return nil
+}
+
+// beadGraphClosure returns the beads in order that are in ids or needed by
+// them, directly or not.
+func beadGraphClosure(g *beads.DependencyGraph, order, ids []string) []string {
+	keep := make(map[string]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		if keep[id] {
+			return
+		}
+		keep[id] = true
+		for _, need := range g.Needs(id) {
+			visit(need)
+		}
+	}
+	for _, id := range ids {
+		visit(id)
+	}
+
+	var closure []string
+	for _, id := range order {
+		if keep[id] {
+			closure = append(closure, id)
+		}
+	}
+	// Needed beads that weren't found aren't in the order; list them last.
+	for _, id := range g.Missing() {
+		if keep[id] {
+			closure = append(closure, id)
+		}
+	}
+	return closure
+}
+
+// beadGraphState is how gt bead graph describes where bead id stands.
+func beadGraphState(g *beads.DependencyGraph, id string) string {
+	issue := g.Issue(id)
+	switch {
+	case issue == nil:
+		return "not found"
+	case g.Done(id):
+		return "done"
+	case !g.Ready(id):
+		return "waiting"
+	case issue.Status == "open":
+		return "ready"
+	}
+	return issue.Status
+}
+
+func newBeadGraphNode(g *beads.DependencyGraph, id string) beadGraphNode {
+	node := beadGraphNode{
+		ID:    id,
+		State: beadGraphState(g, id),
+		Needs: g.Needs(id),
+		Unmet: g.Unmet(id),
+	}
+	if issue := g.Issue(id); issue != nil {
+		node.Title = issue.Title
+		node.Status = issue.Status
+	}
+	return node
+}
+
+// printBeadGraph prints each bead in order with its state and the beads it
+// needs, marking those that are done.
+func printBeadGraph(out io.Writer, g *beads.DependencyGraph, order []string) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, id := range order {
+		state := beadGraphState(g, id)
+		switch state {
+		case "done":
+			state = style.Success.Render("✓ " + state)
+		case "ready":
+			state = style.Bold.Render("● " + state)
+		case "waiting", "not found":
+			state = style.Dim.Render("○ " + state)
+		default:
+			state = "▶ " + state
+		}
+
+		var title string
+		if issue := g.Issue(id); issue != nil {
+			title = issue.Title
+		}
+		var needs []string
+		for _, need := range g.Needs(id) {
+			if g.Done(need) {
+				need += " ✓"
+			}
+			needs = append(needs, need)
+		}
+		line := fmt.Sprintf("%s\t%s\t%s", id, state, title)
+		if len(needs) > 0 {
+			line += "\t" + style.Dim.Render("← "+strings.Join(needs, ", "))
+		}
+		fmt.Fprintln(w, line)
+	}
+	return w.Flush()
+}
+
+// printBeadGraphDot prints the beads in order as a Graphviz digraph, with
+// an edge from each bead to the beads that need it.
+func printBeadGraphDot(out io.Writer, g *beads.DependencyGraph, order []string) error {
+	colors := map[string]string{
+		"done":      "palegreen",
+		"ready":     "lightblue",
+		"waiting":   "lightgrey",
+		"not found": "white",
+	}
+	fmt.Fprintln(out, "digraph beads {")
+	fmt.Fprintln(out, "  rankdir=LR;")
+	fmt.Fprintln(out, "  node [shape=box, style=filled];")
+	for _, id := range order {
+		label := id
+		if issue := g.Issue(id); issue != nil && issue.Title != "" {
+			label += "\n" + issue.Title
+		}
+		color, ok := colors[beadGraphState(g, id)]
+		if !ok {
+			color = "gold" // Under way
+		}
+		fmt.Fprintf(out, "  %q [label=%q, fillcolor=%s];\n", id, label, color)
+	}
+	for _, id := range order {
+		for _, need := range g.Needs(id) {
+			fmt.Fprintf(out, "  %q -> %q;\n", need, id)
+		}
+	}
+	fmt.Fprintln(out, "}")
+	return nil
+}
+
+// checkBeadNeeds fails with errBeadWaiting if beadID needs beads that
+// aren't done yet, naming them. Dependencies that can't be read (bd
+// failing) are reported but don't hold the bead back.
+func checkBeadNeeds(townRoot, beadID string) error {
+	g, err := loadBeadGraph(townRoot, []string{beadID})
+	if err != nil {
+		style.PrintWarning("could not check %s's dependencies: %v", beadID, err)
+		return nil
+	}
+	if unmet := g.Unmet(beadID); len(unmet) > 0 {
+		return fmt.Errorf("%w: %s needs %s done first\nUse --ignore-deps to sling it anyway", errBeadWaiting, beadID, describeBeadNeeds(g, unmet))
+	}
+	return nil
+}
+
+// describeBeadNeeds lists beads with where each one stands, e.g.
+// "gt-abc (hooked), gt-def (not found)".
+func describeBeadNeeds(g *beads.DependencyGraph, ids []string) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		state := "not found"
+		if issue := g.Issue(id); issue != nil {
+			state = issue.Status
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", id, state))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// holdWaitingBeads orders beadIDs for slinging and holds back those
+// waiting on dependencies, as orderSlingBeads does. If the dependency graph
+// can't be read, the beads are slung as given.
+func holdWaitingBeads(townRoot string, beadIDs []string) (ready []string, held []heldBead) {
+	g, err := loadBeadGraph(townRoot, beadIDs)
+	if err != nil {
+		style.PrintWarning("could not check dependencies: %v", err)
+		return beadIDs, nil
+	}
+	return orderSlingBeads(g, beadIDs)
+}
+
+// heldBead is a bead a batch sling holds back, and what it waits on.
+type heldBead struct {
+	ID     string
+	Reason string
+}
+
+// orderSlingBeads returns beadIDs ordered so that each comes after the
+// beads it needs. Unless --ignore-deps is set, those needing beads that
+// aren't done yet are split off into held.
+func orderSlingBeads(g *beads.DependencyGraph, beadIDs []string) (ready []string, held []heldBead) {
+	// Beads in a cycle need each other, so all are held: keep the given order.
+	order, _ := g.Order()
+	var ordered []string
+	for _, id := range order {
+		if slices.Contains(beadIDs, id) {
+			ordered = append(ordered, id)
+		}
+	}
+	for _, id := range beadIDs {
+		if !slices.Contains(ordered, id) {
+			ordered = append(ordered, id)
+		}
+	}
+
+	for _, id := range ordered {
+		if unmet := g.Unmet(id); len(unmet) > 0 && !slingIgnoreDeps {
+			held = append(held, heldBead{ID: id, Reason: "needs " + describeBeadNeeds(g, unmet)})
+			continue
+		}
+		ready = append(ready, id)
+	}
+	return ready, held
+}
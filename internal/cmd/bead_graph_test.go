@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func testBeadGraph() *beads.DependencyGraph {
+	return beads.NewDependencyGraph([]*beads.Issue{
+		{ID: "gt-ui", Title: "Wire the UI", Status: "open", Description: "needs: gt-api"},
+		{ID: "gt-docs", Title: "Write docs", Status: "open"},
+		{ID: "gt-api", Title: "Build the API", Status: "hooked", Description: "needs: gt-schema"},
+		{ID: "gt-schema", Title: "Add the schema", Status: "closed", Description: "blocks: gt-docs"},
+	})
+}
+
+func TestOrderSlingBeads(t *testing.T) {
+	g := testBeadGraph()
+
+	ready, held := orderSlingBeads(g, []string{"gt-ui", "gt-docs", "gt-api"})
+	if want := []string{"gt-api", "gt-docs"}; !reflect.DeepEqual(ready, want) {
+		t.Errorf("ready = %v, want %v", ready, want)
+	}
+	if want := []heldBead{{ID: "gt-ui", Reason: "needs gt-api (hooked)"}}; !reflect.DeepEqual(held, want) {
+		t.Errorf("held = %v, want %v", held, want)
+	}
+
+	slingIgnoreDeps = true
+	defer func() { slingIgnoreDeps = false }()
+	ready, held = orderSlingBeads(g, []string{"gt-ui", "gt-docs", "gt-api"})
+	if want := []string{"gt-api", "gt-ui", "gt-docs"}; !reflect.DeepEqual(ready, want) || held != nil {
+		t.Errorf("with --ignore-deps: ready = %v, held = %v, want %v and none held", ready, held, want)
+	}
+}
+
+func TestPrintBeadGraph(t *testing.T) {
+	g := testBeadGraph()
+	order, err := g.Order()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := printBeadGraph(&out, g, beadGraphClosure(g, order, []string{"gt-ui"})); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("gt-ui needs gt-api, which needs gt-schema; got:\n%s", out.String())
+	}
+	for i, want := range []string{"gt-schema  ✓ done", "gt-api     ▶ hooked", "gt-ui      ○ waiting"} {
+		if !strings.HasPrefix(lines[i], want) {
+			t.Errorf("line %d = %q, want prefix %q", i, lines[i], want)
+		}
+	}
+	if !strings.Contains(lines[1], "← gt-schema ✓") {
+		t.Errorf("gt-api line %q should list gt-schema as done", lines[1])
+	}
+
+	out.Reset()
+	if err := printBeadGraphDot(&out, g, order); err != nil {
+		t.Fatal(err)
+	}
+	for _, edge := range []string{`"gt-api" -> "gt-ui";`, `"gt-schema" -> "gt-docs";`} {
+		if !strings.Contains(out.String(), edge) {
+			t.Errorf("dot output missing %s:\n%s", edge, out.String())
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadHandbackReason string
+	beadHandbackTo     string
+	beadHandbackAgent  string
+	beadHandbackDryRun bool
+)
+
+var beadHandbackCmd = &cobra.Command{
+	Use:   "handback [bead-id]",
+	Short: "Hand the bead on your hook back, with a reason",
+	Long: `Give up the bead on your hook because you can't finish it: it's too
+hard, needs a different skillset, or is blocked on something you can't fix.
+
+The hook is cleared, the bead goes back to open and queued, and the reason
+is added to the bead as a comment and to its lifecycle history (see gt bead
+status), so whoever picks it up next knows what was tried.
+
+With --to or --agent the bead is slung again right away: --to names the
+new target (a rig or an agent address), --agent the agent to run it, e.g.
+to escalate from kimi to claude. --agent alone slings to a new polecat in
+your rig.
+
+With a bead ID, only hands back if that bead is on your hook.
+
+Examples:
+  gt bead handback -r "needs Rust FFI knowledge"
+  gt bead handback gt-abc12 -r "too hard" --agent claude
+  gt bead handback -r "frontend work" --to gastown/crew/joe`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBeadHandback,
+}
+
+func init() {
+	beadHandbackCmd.Flags().StringVarP(&beadHandbackReason, "reason", "r", "", "Why you're handing the bead back (required)")
+	beadHandbackCmd.Flags().StringVar(&beadHandbackTo, "to", "", "Sling the bead to this rig or agent after handing it back")
+	beadHandbackCmd.Flags().StringVar(&beadHandbackAgent, "agent", "", "Agent to run the bead when slinging it again (e.g., claude)")
+	beadHandbackCmd.Flags().BoolVarP(&beadHandbackDryRun, "dry-run", "n", false, "Show what would be done")
+	_ = beadHandbackCmd.MarkFlagRequired("reason")
+	beadCmd.AddCommand(beadHandbackCmd)
+}
+
+func runBeadHandback(cmd *cobra.Command, args []string) error {
+	reason := strings.TrimSpace(beadHandbackReason)
+	if reason == "" {
+		return fmt.Errorf("--reason is required: say why you're handing the bead back")
+	}
+
+	agentID, _, _, err := resolveSelfTarget()
+	if err != nil {
+		return fmt.Errorf("detecting agent identity: %w", err)
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil {
+		return fmt.Errorf("finding town root: %w", err)
+	}
+
+	// Town-level agents' beads live in the town, others' in their rig
+	rigName := strings.Split(agentID, "/")[0]
+	beadsPath := filepath.Join(townRoot, rigName)
+	if rigName == "mayor" || rigName == "deacon" {
+		beadsPath = townRoot
+		rigName = ""
+	}
+
+	slingArgs, err := handbackSlingArgs(rigName, beadHandbackTo, beadHandbackAgent)
+	if err != nil {
+		return err
+	}
+
+	b := beads.New(beadsPath)
+	agentBeadID := agentIDToBeadID(agentID, townRoot)
+	if agentBeadID == "" {
+		return fmt.Errorf("could not convert agent ID %s to bead ID", agentID)
+	}
+	agentBead, err := b.Show(agentBeadID)
+	if err != nil {
+		return fmt.Errorf("getting agent bead %s: %w", agentBeadID, err)
+	}
+	beadID := agentBead.HookBead
+	if beadID == "" {
+		return fmt.Errorf("nothing on your hook to hand back")
+	}
+	if len(args) > 0 && args[0] != beadID {
+		return fmt.Errorf("bead %s is not hooked (current hook: %s)", args[0], beadID)
+	}
+
+	note := fmt.Sprintf("Handed back by %s: %s", agentID, reason)
+	fmt.Printf("%s Handing back %s...\n", style.Bold.Render("↩"), beadID)
+	if beadHandbackDryRun {
+		fmt.Printf("Would clear hook_bead from agent bead %s\n", agentBeadID)
+		fmt.Printf("Would reopen %s and record: %s\n", beadID, note)
+		if slingArgs != nil {
+			fmt.Printf("Would run: gt sling %s %s\n", beadID, strings.Join(slingArgs, " "))
+		}
+		return nil
+	}
+
+	if err := b.ClearHookBead(agentBeadID); err != nil {
+		return fmt.Errorf("clearing hook from agent bead %s: %w", agentBeadID, err)
+	}
+	openStatus := "open"
+	emptyAssignee := ""
+	if err := b.Update(beadID, beads.UpdateOptions{Status: &openStatus, Assignee: &emptyAssignee}); err != nil {
+		style.PrintWarning("couldn't reopen bead %s: %v", beadID, err)
+	}
+
+	commentCmd := exec.Command("bd", "comment", beadID, note)
+	commentCmd.Dir = beadsPath
+	if err := commentCmd.Run(); err != nil {
+		style.PrintWarning("couldn't add the reason to %s: %v", beadID, err)
+	}
+	if _, err := beads.NewLifecycle(townRoot).Advance(beadID, beads.StageQueued, "", "gt bead handback", note); err != nil {
+		style.PrintWarning("bead lifecycle: %v", err)
+	}
+	_ = events.LogFeed(events.TypeHandback, agentID, events.HandbackPayload(beadID, reason, beadHandbackTo, beadHandbackAgent))
+
+	fmt.Printf("%s %s handed back and queued\n", style.Bold.Render("✓"), beadID)
+	fmt.Printf("  Reason: %s\n", reason)
+
+	if slingArgs == nil {
+		return nil
+	}
+	slingCmd := exec.Command("gt", append([]string{"sling", beadID}, slingArgs...)...)
+	slingCmd.Stdout = os.Stdout
+	slingCmd.Stderr = os.Stderr
+	if err := slingCmd.Run(); err != nil {
+		return fmt.Errorf("slinging %s again (it stays queued): %w", beadID, err)
+	}
+	return nil
+}
+
+// handbackSlingArgs returns the gt sling arguments after the bead ID that
+// re-sling a handed-back bead to target with agent, or nil if neither is
+// set. An agent without a target slings to rig, the agent's own rig (empty
+// for town-level agents, which must name a target).
+func handbackSlingArgs(rig, target, agent string) ([]string, error) {
+	if target == "" && agent == "" {
+		return nil, nil
+	}
+	if target == "" {
+		if rig == "" {
+			return nil, fmt.Errorf("--agent needs --to outside a rig: name the rig or agent to sling to")
+		}
+		target = rig
+	}
+	args := []string{target}
+	if agent != "" {
+		args = append(args, "--agent", agent)
+	}
+	return args, nil
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHandbackSlingArgs(t *testing.T) {
+	tests := []struct {
+		name               string
+		rig, target, agent string
+		want               []string
+		wantErr            bool
+	}{
+		{name: "no re-sling", rig: "gastown"},
+		{name: "to target", rig: "gastown", target: "gastown/crew/joe", want: []string{"gastown/crew/joe"}},
+		{name: "agent to own rig", rig: "gastown", agent: "claude", want: []string{"gastown", "--agent", "claude"}},
+		{name: "target and agent", rig: "gastown", target: "beads", agent: "claude", want: []string{"beads", "--agent", "claude"}},
+		{name: "agent outside a rig", agent: "claude", wantErr: true},
+		{name: "town agent with target", target: "beads", want: []string{"beads"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := handbackSlingArgs(tt.rig, tt.target, tt.agent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	beadStatusJSON bool
+
+	beadTransitionAgent string
+	beadTransitionNote  string
+)
+
+var beadStatusCmd = &cobra.Command{
+	Use:   "status <bead-id>",
+	Short: "Show a bead's lifecycle stage and history",
+	Long: `Show where a bead is in its lifecycle and how it got there.
+
+Gas Town tracks each bead through these stages:
+
+  queued → assigned → in-progress → review → done
+                                            ↘ failed
+
+Commands move beads as they go: gt sling and gt hook assign a bead, gt prime
+marks hooked work in progress, gt done submits it for review (or fails it
+with --status ESCALATED), a refinery merge completes it, and gt unsling,
+gt release or gt bead handback puts it back in the queue. Hook scripts and people can move a
+bead with gt bead transition.
+
+The history is kept in .runtime/beads/lifecycle.jsonl in the town, so it
+survives crashes of the agent holding the bead.
+
+Examples:
+  gt bead status gt-abc12
+  gt bead status gt-abc12 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runBeadStatus,
+}
+
+var beadTransitionCmd = &cobra.Command{
+	Use:   "transition <bead-id> <stage>",
+	Short: "Move a bead to a lifecycle stage",
+	Long: `Move a bead to a lifecycle stage: queued, assigned, in-progress, review,
+done or failed. Moves the lifecycle doesn't allow (e.g. done → review) are
+refused; see gt bead status for the stages.
+
+This is how hook scripts (.gastown/hooks) report transitions that Gas Town
+can't see itself, e.g. a review passing in an external system.
+
+Examples:
+  gt bead transition gt-abc12 failed --note "flaky upstream API"
+  gt bead transition gt-abc12 queued                # Retry it`,
+	Args: cobra.ExactArgs(2),
+	RunE: runBeadTransition,
+}
+
+func init() {
+	beadStatusCmd.Flags().BoolVar(&beadStatusJSON, "json", false, "Output as JSON")
+	beadTransitionCmd.Flags().StringVar(&beadTransitionAgent, "agent", "", "Agent holding the bead after the move (default: its current agent)")
+	beadTransitionCmd.Flags().StringVar(&beadTransitionNote, "note", "", "Why the bead moved (recorded in its history)")
+	beadCmd.AddCommand(beadStatusCmd)
+	beadCmd.AddCommand(beadTransitionCmd)
+}
+
+func runBeadStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	lc := beads.NewLifecycle(townRoot)
+	state, err := lc.State(args[0])
+	if err != nil {
+		return err
+	}
+	history, err := lc.History(args[0])
+	if err != nil {
+		return err
+	}
+
+	if beadStatusJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			beads.BeadState
+			History []beads.Transition `json:"history"`
+		}{state, history})
+	}
+
+	fmt.Printf("%s %s\n", style.Bold.Render(state.Bead), formatStage(state.Stage))
+	if state.Agent != "" {
+		fmt.Printf("  Agent: %s\n", state.Agent)
+	}
+	if len(history) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No transitions recorded"))
+		return nil
+	}
+	fmt.Println()
+	for _, t := range history {
+		line := fmt.Sprintf("  %s  %s → %s", t.At.Local().Format("2006-01-02 15:04:05"), t.From, t.To)
+		var details []string
+		if t.Agent != "" {
+			details = append(details, t.Agent)
+		}
+		if t.Trigger != "" {
+			details = append(details, "by "+t.Trigger)
+		}
+		if len(details) > 0 {
+			line += "  " + style.Dim.Render(strings.Join(details, ", "))
+		}
+		if t.Note != "" {
+			line += "  " + t.Note
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func runBeadTransition(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	to, err := beads.ParseStage(args[1])
+	if err != nil {
+		return err
+	}
+	lc := beads.NewLifecycle(townRoot)
+	agent := beadTransitionAgent
+	if agent == "" && to != beads.StageQueued {
+		state, err := lc.State(args[0])
+		if err != nil {
+			return err
+		}
+		agent = state.Agent
+	}
+	t, err := lc.Advance(args[0], to, agent, "gt bead transition", beadTransitionNote)
+	if err != nil {
+		return err
+	}
+	if t.To == "" {
+		fmt.Printf("%s is already %s\n", args[0], to)
+		return nil
+	}
+	fmt.Printf("%s %s: %s → %s\n", style.Bold.Render("✓"), args[0], t.From, t.To)
+	return nil
+}
+
+// formatStage renders a stage for gt bead status.
+func formatStage(s beads.Stage) string {
+	switch s {
+	case beads.StageDone:
+		return style.Success.Render(string(s))
+	case beads.StageFailed:
+		return style.Error.Render(string(s))
+	default:
+		return style.Bold.Render(string(s))
+	}
+}
+
+// advanceBeadStage records a lifecycle move of bead made by a command.
+// Tracking never blocks the command itself: failures are only warned about,
+// and moves the lifecycle refuses go to the structured log. An empty
+// townRoot is looked up from the working directory.
+func advanceBeadStage(townRoot, bead string, to beads.Stage, agent, trigger string) {
+	if bead == "" {
+		return
+	}
+	if townRoot == "" {
+		var err error
+		if townRoot, err = workspace.FindFromCwd(); err != nil || townRoot == "" {
+			return
+		}
+	}
+	_, err := beads.NewLifecycle(townRoot).Advance(bead, to, agent, trigger, "")
+	switch {
+	case errors.Is(err, beads.ErrInvalidTransition):
+		// Not the agent's problem; keep it out of its output
+		gtlog.L().Warn("bead lifecycle transition refused", "bead", bead, "to", string(to), "trigger", trigger, "err", err)
+	case err != nil:
+		style.PrintWarning("bead lifecycle: %v", err)
+	}
+}
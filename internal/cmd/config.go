@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -29,7 +30,8 @@ Commands:
   gt config agent get <name>         Show agent configuration
   gt config agent set <name> <cmd>   Set custom agent command
   gt config agent remove <name>      Remove custom agent
-  gt config default-agent [name]     Get or set default agent`,
+  gt config default-agent [name]     Get or set default agent
+  gt config validate                 Check config files for errors`,
 }
 
 // Agent subcommands
@@ -39,7 +41,7 @@ var configAgentListCmd = &cobra.Command{
 	Short: "List all agents",
 	Long: `List all available agents (built-in and custom).
 
-Shows all built-in agent presets (claude, gemini, codex, cursor, auggie, amp, opencode, kimi) and any
+Shows all built-in agent presets (claude, gemini, codex, cursor, auggie, amp, opencode, kimi, aider, qwen) and any
 custom agents defined in your town settings.
 
 Examples:
@@ -89,7 +91,7 @@ var configAgentRemoveCmd = &cobra.Command{
 	Long: `Remove a custom agent definition from town settings.
 
 This removes a custom agent from your town settings. Built-in agents
-(claude, gemini, codex, cursor, auggie, amp, opencode, kimi) cannot be removed.
+(claude, gemini, codex, cursor, auggie, amp, opencode, kimi, aider, qwen) cannot be removed.
 
 Examples:
   gt config agent remove claude-glm`,
@@ -108,7 +110,7 @@ With no arguments, shows the current default agent.
 With an argument, sets the default agent to the specified name.
 
 The default agent is used when a rig doesn't specify its own agent
-setting. Can be a built-in preset (claude, gemini, codex, cursor, auggie, amp, opencode, kimi) or a
+setting. Can be a built-in preset (claude, gemini, codex, cursor, auggie, amp, opencode, kimi, aider, qwen) or a
 custom agent name.
 
 Examples:
@@ -141,17 +143,45 @@ Examples:
 }
 
 // Flags
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check config files for errors",
+	Long: `Check the town's config files for problems that would otherwise only show
+up when an agent is spawned.
+
+Checks town settings (settings/config.json), the agent registry
+(settings/agents.json) and every rig's settings for:
+  - Agent names that aren't built in or defined anywhere
+  - Agent definitions that can't be resumed (invalid resume_style,
+    resume_template placeholders) or can't get hooks (missing hooks dir,
+    unknown hooks provider)
+  - Invalid custom roles and watchdog settings
+  - Files written by a newer gt
+
+Files stored at an older schema version are migrated whenever gt loads
+them; --migrate rewrites them at the current version.
+
+Fails if any problem is found.
+
+Examples:
+  gt config validate
+  gt config validate --migrate`,
+	RunE: runConfigValidate,
+}
+
 var (
-	configAgentListJSON bool
+	configAgentListJSON   bool
+	configValidateMigrate bool
 )
 
 // AgentListItem represents an agent in list output.
 type AgentListItem struct {
-	Name     string `json:"name"`
-	Command  string `json:"command"`
-	Args     string `json:"args,omitempty"`
-	Type     string `json:"type"` // "built-in" or "custom"
-	IsCustom bool   `json:"is_custom"`
+	Name        string `json:"name"`
+	Command     string `json:"command"`
+	Args        string `json:"args,omitempty"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"` // "built-in" or "custom"
+	IsCustom    bool   `json:"is_custom"`
 }
 
 func runConfigAgentList(cmd *cobra.Command, args []string) error {
@@ -188,11 +218,12 @@ func runConfigAgentList(cmd *cobra.Command, args []string) error {
 		preset := config.GetAgentPresetByName(name)
 		if preset != nil {
 			items = append(items, AgentListItem{
-				Name:     name,
-				Command:  preset.Command,
-				Args:     strings.Join(preset.Args, " "),
-				Type:     "built-in",
-				IsCustom: false,
+				Name:        name,
+				Command:     preset.Command,
+				Args:        strings.Join(preset.Args, " "),
+				Description: preset.Description,
+				Type:        "built-in",
+				IsCustom:    false,
 			})
 		}
 	}
@@ -230,6 +261,9 @@ func runConfigAgentList(cmd *cobra.Command, args []string) error {
 			fmt.Printf(" %s", item.Args)
 		}
 		fmt.Println()
+		if item.Description != "" {
+			fmt.Printf("    %s\n", style.Dim.Render(item.Description))
+		}
 	}
 
 	// Show default
@@ -300,6 +334,9 @@ func displayAgentConfig(name string, runtime *config.RuntimeConfig, preset *conf
 	}
 
 	if preset != nil {
+		if preset.Description != "" {
+			fmt.Printf("Description: %s\n", preset.Description)
+		}
 		if preset.SessionIDEnv != "" {
 			fmt.Printf("Session ID Env: %s\n", preset.SessionIDEnv)
 		}
@@ -513,9 +550,45 @@ func runConfigAgentEmailDomain(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	issues, migrations := config.ValidateTownConfig(townRoot)
+	if configValidateMigrate {
+		if err := config.MigrateConfigFiles(migrations); err != nil {
+			return err
+		}
+	}
+	for _, m := range migrations {
+		rel, _ := filepath.Rel(townRoot, m.Path)
+		if configValidateMigrate {
+			fmt.Printf("%s Migrated %s (version %d → %d)\n", style.Bold.Render("✓"), rel, m.FromVersion, m.ToVersion)
+		} else {
+			fmt.Printf("%s %s is schema version %d (current %d); run with --migrate to rewrite it\n",
+				style.Dim.Render("○"), rel, m.FromVersion, m.ToVersion)
+		}
+	}
+
+	if len(issues) == 0 {
+		fmt.Printf("%s Config is valid\n", style.Bold.Render("✓"))
+		return nil
+	}
+	for _, issue := range issues {
+		if rel, err := filepath.Rel(townRoot, issue.Path); err == nil {
+			issue.Path = rel
+		}
+		fmt.Printf("%s %s\n", style.Error.Render("✗"), issue)
+	}
+	return fmt.Errorf("%d config problem(s) found", len(issues))
+}
+
 func init() {
 	// Add flags
 	configAgentListCmd.Flags().BoolVar(&configAgentListJSON, "json", false, "Output as JSON")
+	configValidateCmd.Flags().BoolVar(&configValidateMigrate, "migrate", false, "Rewrite older config files at the current schema version")
 
 	// Add agent subcommands
 	configAgentCmd := &cobra.Command{
@@ -532,6 +605,7 @@ func init() {
 	configCmd.AddCommand(configAgentCmd)
 	configCmd.AddCommand(configDefaultAgentCmd)
 	configCmd.AddCommand(configAgentEmailDomainCmd)
+	configCmd.AddCommand(configValidateCmd)
 
 	// Register with root
 	rootCmd.AddCommand(configCmd)
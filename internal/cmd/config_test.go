@@ -147,6 +147,28 @@ func TestConfigAgentList(t *testing.T) {
 			t.Fatalf("runConfigAgentList failed: %v", err)
 		}
 	})
+
+	t.Run("shows preset descriptions", func(t *testing.T) {
+		townRoot := setupTestTownForConfig(t)
+
+		originalWd, _ := os.Getwd()
+		defer os.Chdir(originalWd)
+		if err := os.Chdir(townRoot); err != nil {
+			t.Fatalf("chdir: %v", err)
+		}
+
+		var runErr error
+		out := captureStdout(t, func() {
+			runErr = runConfigAgentList(&cobra.Command{}, nil)
+		})
+		if runErr != nil {
+			t.Fatalf("runConfigAgentList failed: %v", runErr)
+		}
+		kimi := config.GetAgentPresetByName("kimi")
+		if !strings.Contains(out, kimi.Description) {
+			t.Errorf("listing missing kimi description %q:\n%s", kimi.Description, out)
+		}
+	})
 }
 
 func TestConfigAgentGet(t *testing.T) {
@@ -18,10 +18,13 @@ var (
 	crewMessage       string
 	crewAccount       string
 	crewAgentOverride string
+	crewLayout        string
+	crewTags          []string
 	crewAll           bool
 	crewListAll       bool
 	crewDryRun        bool
 	crewDebug         bool
+	crewPrune         bool
 )
 
 var crewCmd = &cobra.Command{
@@ -53,7 +56,10 @@ Commands:
   gt crew at <name>        Attach to session
   gt crew remove <name>    Remove workspace
   gt crew refresh <name>   Context cycle with handoff mail
-  gt crew restart <name>   Kill and restart session fresh`,
+  gt crew restart <name>   Kill and restart session fresh
+  gt crew pause <name>     Interrupt the agent and hold it idle, keeping its context
+  gt crew resume <name>    Continue a paused agent
+  gt crew scale <rig>      Start or retire sessions to a target count`,
 }
 
 var crewAddCmd = &cobra.Command{
@@ -64,14 +70,16 @@ var crewAddCmd = &cobra.Command{
 Each workspace is created at <rig>/crew/<name>/ with:
 - A full git clone of the project repository
 - Mail directory for message delivery
-- CLAUDE.md with crew worker prompting
+- The agent's instructions file (CLAUDE.md, AGENTS.md, ...) if the repo
+  has none, seeded from the agent's instructions.seed (git-excluded)
 - Optional feature branch (crew/<name>)
 
 Examples:
   gt crew add dave                       # Create single workspace
   gt crew add murgen croaker goblin      # Create multiple at once
   gt crew add emma --rig greenplace      # Create in specific rig
-  gt crew add fred --branch              # Create with feature branch`,
+  gt crew add fred --branch              # Create with feature branch
+  gt crew add kai --agent kimi           # Seed AGENTS.md for a kimi crew`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runCrewAdd,
 }
@@ -106,6 +114,13 @@ current pane. Use C-b s to switch to the new session.
 When run from outside tmux, you are attached to the session (unless
 --detached is specified).
 
+Set GT_MULTIPLEXER=zellij to run the session in zellij instead; the
+session is started and attached the same way, without tmux theming.
+
+A new session opens with the crew layout from role_layouts in town
+settings (e.g. agent + log tail + shell panes); --layout picks another one,
+and also applies to an existing session that isn't split yet. See gt layout.
+
 Role Discovery:
   If no name is provided, attempts to detect the crew workspace from the
   current directory. If you're in <rig>/crew/<name>/, it will attach to
@@ -115,6 +130,7 @@ Examples:
   gt crew at dave                 # Attach to dave's session
   gt crew at                      # Auto-detect from cwd
   gt crew at dave --detached      # Start session without attaching
+  gt crew at dave --layout dev    # Open the dev pane layout
   gt crew at dave --no-tmux       # Just print path`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runCrewAt,
@@ -247,6 +263,25 @@ Examples:
 	RunE: runCrewPristine,
 }
 
+var crewWorktreesCmd = &cobra.Command{
+	Use:   "worktrees <name>",
+	Short: "List or prune a crew worker's bead worktrees",
+	Long: `List the bead worktrees of a crew worker.
+
+gt sling <bead> <rig>/crew/<name> --worktree gives the bead its own git
+worktree inside the worker's clone (.worktrees/<bead>, on branch
+crew/<name>/<bead>) and moves the worker's session there. Worktrees of
+closed beads are removed the next time a bead is slung with --worktree,
+or with --prune. Worktrees with uncommitted changes, and the one the
+session is working in, are kept. Branches are always kept.
+
+Examples:
+  gt crew worktrees dave           # List dave's bead worktrees
+  gt crew worktrees dave --prune   # Remove those of closed beads`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCrewWorktrees,
+}
+
 var crewNextCmd = &cobra.Command{
 	Use:    "next",
 	Short:  "Switch to next crew session in same rig",
@@ -276,7 +311,8 @@ Examples:
   gt crew start beads             # Start all crew in beads rig
   gt crew start                   # Start all crew (rig inferred from cwd)
   gt crew start beads grip fang   # Start specific crew in beads rig
-  gt crew start gastown joe       # Start joe in gastown rig`,
+  gt crew start gastown joe       # Start joe in gastown rig
+  gt crew start gastown joe --tag team=backend`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		// With --all, we can have 0 args (infer rig) or 1+ args (rig specified)
 		if crewAll {
@@ -330,6 +366,7 @@ func init() {
 	// Add flags
 	crewAddCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to create crew workspace in")
 	crewAddCmd.Flags().BoolVar(&crewBranch, "branch", false, "Create a feature branch (crew/<name>)")
+	crewAddCmd.Flags().StringVar(&crewAgentOverride, "agent", "", "Agent alias whose instructions file to seed (overrides rig/town default)")
 
 	crewListCmd.Flags().StringVar(&crewRig, "rig", "", "Filter by rig name")
 	crewListCmd.Flags().BoolVar(&crewListAll, "all", false, "List crew workspaces in all rigs")
@@ -341,6 +378,7 @@ func init() {
 	crewAtCmd.Flags().StringVar(&crewAccount, "account", "", "Claude Code account handle to use (overrides default)")
 	crewAtCmd.Flags().StringVar(&crewAgentOverride, "agent", "", "Agent alias to run crew worker with (overrides rig/town default)")
 	crewAtCmd.Flags().BoolVar(&crewDebug, "debug", false, "Show debug output for troubleshooting")
+	crewAtCmd.Flags().StringVar(&crewLayout, "layout", "", "Pane layout to open in the session (default: the crew role layout, see gt layout)")
 
 	crewRemoveCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
 	crewRemoveCmd.Flags().BoolVar(&crewForce, "force", false, "Force remove (skip safety checks)")
@@ -364,12 +402,16 @@ func init() {
 	crewStartCmd.Flags().BoolVar(&crewAll, "all", false, "Start all crew members in the rig")
 	crewStartCmd.Flags().StringVar(&crewAccount, "account", "", "Claude Code account handle to use")
 	crewStartCmd.Flags().StringVar(&crewAgentOverride, "agent", "", "Agent alias to run crew worker with (overrides rig/town default)")
+	crewStartCmd.Flags().StringArrayVar(&crewTags, "tag", nil, "Tag the sessions key=value (repeatable, see gt tag)")
 
 	crewStopCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use (filter when using --all)")
 	crewStopCmd.Flags().BoolVar(&crewAll, "all", false, "Stop all running crew sessions")
 	crewStopCmd.Flags().BoolVar(&crewDryRun, "dry-run", false, "Show what would be stopped without stopping")
 	crewStopCmd.Flags().BoolVar(&crewForce, "force", false, "Skip output capture for faster shutdown")
 
+	crewWorktreesCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use")
+	crewWorktreesCmd.Flags().BoolVar(&crewPrune, "prune", false, "Remove worktrees of closed beads")
+
 	// Add subcommands
 	crewCmd.AddCommand(crewAddCmd)
 	crewCmd.AddCommand(crewListCmd)
@@ -380,6 +422,7 @@ func init() {
 	crewCmd.AddCommand(crewRenameCmd)
 	crewCmd.AddCommand(crewPristineCmd)
 	crewCmd.AddCommand(crewRestartCmd)
+	crewCmd.AddCommand(crewWorktreesCmd)
 
 	// Add --session flag to next/prev commands for tmux key binding support
 	// When run via run-shell, tmux session context may be wrong, so we pass it explicitly
@@ -80,7 +80,10 @@ func runCrewAdd(cmd *cobra.Command, args []string) error {
 		// Create crew workspace
 		fmt.Printf("Creating crew workspace %s in %s...\n", name, rigName)
 
-		worker, err := crewMgr.Add(name, crewBranch)
+		worker, err := crewMgr.AddWithOptions(name, crew.AddOptions{
+			CreateBranch:  crewBranch,
+			AgentOverride: crewAgentOverride,
+		})
 		if err != nil {
 			if err == crew.ErrCrewExists {
 				style.PrintWarning("crew workspace '%s' already exists, skipping", name)
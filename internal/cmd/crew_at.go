@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -111,9 +112,17 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		style.PrintWarning("could not ensure settings for %s: %v", name, err)
 	}
 
-	// Check if session exists
-	t := tmux.NewTmux()
 	sessionID := crewSessionName(r.Name, name)
+	mux, err := tmux.NewMultiplexer()
+	if err != nil {
+		return err
+	}
+	if _, isTmux := mux.(*tmux.Tmux); !isTmux {
+		return crewAtMultiplexer(mux, r.Name, r.Path, name, worker.ClonePath, sessionID, runtimeConfig, claudeConfigDir)
+	}
+
+	// Check if session exists
+	t := tmux.ForRig(townRoot, r.Name)
 	if debug {
 		fmt.Printf("[DEBUG] sessionID=%q (r.Name=%q, name=%q)\n", sessionID, r.Name, name)
 	}
@@ -180,6 +189,7 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 		// Note: ConfigureGasTownSession includes cycle bindings
 		theme := getThemeForRig(r.Name)
 		_ = t.ConfigureGasTownSession(sessionID, theme, r.Name, name, "crew")
+		_ = transcript.Enable(t, sessionID) // Non-fatal, likewise
 
 		// Wait for shell to be ready after session creation
 		if err := t.WaitForShellReady(sessionID, constants.ShellReadyTimeout); err != nil {
@@ -223,6 +233,7 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 
 		fmt.Printf("%s Created session for %s/%s\n",
 			style.Bold.Render("✓"), r.Name, name)
+		applyCrewLayout(t, sessionID, townRoot, true)
 	} else {
 		// Session exists - check if runtime is still running
 		// Uses both pane command check and UI marker detection to avoid
@@ -283,6 +294,7 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 				return fmt.Errorf("restarting runtime: %w", err)
 			}
 		}
+		applyCrewLayout(t, sessionID, townRoot, false)
 	}
 
 	// Check if we're already in the target session
@@ -333,3 +345,67 @@ func runCrewAt(cmd *cobra.Command, args []string) error {
 	}
 	return attachToTmuxSession(sessionID)
 }
+
+// crewAtMultiplexer is gt crew at for a non-tmux backend (GT_MULTIPLEXER):
+// start the crew session if it isn't running, then attach unless --detached.
+// The tmux-only extras (theming, stale-session recovery) are skipped.
+func crewAtMultiplexer(mux tmux.Multiplexer, rigName, rigPath, name, clonePath, sessionID string, runtimeConfig *config.RuntimeConfig, claudeConfigDir string) error {
+	hasSession, err := mux.HasSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+
+	if !hasSession {
+		beacon := session.FormatStartupBeacon(session.BeaconConfig{
+			Recipient: fmt.Sprintf("%s/crew/%s", rigName, name),
+			Sender:    "human",
+			Topic:     "start",
+		})
+		// The startup command exports GT_ROLE and friends itself, so it
+		// needs no tmux session environment
+		startupCmd, err := config.BuildCrewStartupCommandWithAgentOverride(rigName, name, rigPath, beacon, crewAgentOverride)
+		if err != nil {
+			return fmt.Errorf("building startup command: %w", err)
+		}
+		if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && claudeConfigDir != "" {
+			startupCmd = config.PrependEnv(startupCmd, map[string]string{runtimeConfig.Session.ConfigDirEnv: claudeConfigDir})
+		}
+		if err := mux.NewSessionWithCommand(sessionID, clonePath, startupCmd); err != nil {
+			return fmt.Errorf("creating session: %w", err)
+		}
+		fmt.Printf("%s Created session for %s/%s\n",
+			style.Bold.Render("✓"), rigName, name)
+	}
+
+	if crewDetached {
+		fmt.Printf("Started %s/%s. Run 'gt crew at %s' to attach.\n", rigName, name, name)
+		return nil
+	}
+	fmt.Printf("Attaching to %s...\n", sessionID)
+	return mux.AttachSession(sessionID)
+}
+
+// applyCrewLayout opens the pane layout of a crew session: --layout, or
+// for a new session the crew role layout. Layout problems are only
+// warned about; the session works without its extra panes.
+func applyCrewLayout(t *tmux.Tmux, sessionID, townRoot string, newSession bool) {
+	name := crewLayout
+	if name == "" && newSession {
+		settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+		if err != nil {
+			return
+		}
+		name = settings.RoleLayout("crew")
+	}
+	if name == "" {
+		return
+	}
+	layout, err := loadLayout(townRoot, name)
+	if err != nil {
+		style.PrintWarning("%v", err)
+		return
+	}
+	if _, err := applyPaneLayout(t, sessionID, townRoot, layout, false); err != nil {
+		style.PrintWarning("applying layout %s: %v", name, err)
+	}
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -215,7 +217,13 @@ func isInTmuxSession(targetSession string) bool {
 
 // attachToTmuxSession attaches to a tmux session.
 // If already inside tmux, uses switch-client instead of attach-session.
+// Sessions of a remote rig are attached over ssh -t, nested in the current
+// client if there is one.
 func attachToTmuxSession(sessionID string) error {
+	if remote, ok := sessionRemote(sessionID); ok {
+		return tmux.NewTmux(tmux.WithRemote(remote)).AttachSession(sessionID)
+	}
+
 	tmuxPath, err := exec.LookPath("tmux")
 	if err != nil {
 		return fmt.Errorf("tmux not found: %w", err)
@@ -236,6 +244,20 @@ func attachToTmuxSession(sessionID string) error {
 	return cmd.Run()
 }
 
+// sessionRemote returns the SSH host of the rig sessionID belongs to, if the
+// rig is remote.
+func sessionRemote(sessionID string) (tmux.Remote, bool) {
+	identity, err := session.ParseSessionName(sessionID)
+	if err != nil || identity.Rig == "" {
+		return tmux.Remote{}, false
+	}
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return tmux.Remote{}, false
+	}
+	return tmux.RigRemote(townRoot, identity.Rig)
+}
+
 // ensureDefaultBranch checks if a git directory is on the default branch.
 // If not, warns the user and offers to switch.
 // Returns true if on default branch (or switched to it), false if user declined.
@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/crew"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
@@ -311,6 +312,11 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 	accountsPath := constants.MayorAccountsPath(townRoot)
 	claudeConfigDir, _, _ := config.ResolveAccountConfigDir(accountsPath, crewAccount)
 
+	tags, err := parseTagArgs(crewTags)
+	if err != nil {
+		return err
+	}
+
 	// Build start options (shared across all crew members)
 	opts := crew.StartOptions{
 		Account:         crewAccount,
@@ -323,6 +329,7 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 		name    string
 		err     error
 		skipped bool // true if session was already running
+		queued  bool // true if the start waits for an agent slot
 	}
 	results := make(chan result, len(crewNames))
 	var wg sync.WaitGroup
@@ -338,7 +345,11 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 			if skipped {
 				err = nil // Not an error, just already running
 			}
-			results <- result{name: crewName, err: err, skipped: skipped}
+			queued := errors.Is(err, scheduler.ErrQueued)
+			if queued {
+				err = nil // The daemon starts it when a slot frees up
+			}
+			results <- result{name: crewName, err: err, skipped: skipped, queued: queued}
 		}(name)
 	}
 
@@ -352,6 +363,7 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 	var lastErr error
 	startedCount := 0
 	skippedCount := 0
+	queuedCount := 0
 	for res := range results {
 		if res.err != nil {
 			fmt.Printf("  %s %s/%s: %v\n", style.ErrorPrefix, rigName, res.name, res.err)
@@ -359,10 +371,19 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 		} else if res.skipped {
 			fmt.Printf("  %s %s/%s: already running\n", style.Dim.Render("○"), rigName, res.name)
 			skippedCount++
+		} else if res.queued {
+			fmt.Printf("  %s %s/%s: queued until an agent slot frees up\n", style.Dim.Render("⏳"), rigName, res.name)
+			queuedCount++
 		} else {
 			fmt.Printf("  %s %s/%s: started\n", style.SuccessPrefix, rigName, res.name)
 			startedCount++
 		}
+		if res.err == nil && !res.queued && len(tags) > 0 {
+			sess := crewSessionName(rigName, res.name)
+			if err := updateSessionTags(tmux.ForRig(townRoot, rigName), sess, tags, nil); err != nil {
+				style.PrintWarning("could not tag %s: %v", sess, err)
+			}
+		}
 	}
 
 	// Summary
@@ -371,6 +392,10 @@ func runCrewStart(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s Started %d, skipped %d (already running) in %s\n",
 			style.Bold.Render("✓"), startedCount, skippedCount, r.Name)
 	}
+	if queuedCount > 0 {
+		fmt.Printf("%s Queued %d: the daemon starts them as running agents exit (see gt scheduler)\n",
+			style.Dim.Render("⏳"), queuedCount)
+	}
 
 	return lastErr
 }
@@ -20,6 +20,7 @@ type CrewListItem struct {
 	Branch     string `json:"branch"`
 	Path       string `json:"path"`
 	HasSession bool   `json:"has_session"`
+	Paused     bool   `json:"paused,omitempty"` // Session paused by gt crew pause
 	GitClean   bool   `json:"git_clean"`
 }
 
@@ -60,6 +61,11 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 		for _, w := range workers {
 			sessionID := crewSessionName(r.Name, w.Name)
 			hasSession, _ := t.HasSession(sessionID)
+			var paused bool
+			if hasSession {
+				since, _ := crewMgr.PausedSince(w.Name)
+				paused = since != nil
+			}
 
 			workerGit := git.NewGit(w.ClonePath)
 			gitClean := true
@@ -73,6 +79,7 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 				Branch:     w.Branch,
 				Path:       w.ClonePath,
 				HasSession: hasSession,
+				Paused:     paused,
 				GitClean:   gitClean,
 			})
 		}
@@ -96,13 +103,18 @@ func runCrewList(cmd *cobra.Command, args []string) error {
 		if item.HasSession {
 			status = style.Bold.Render("●")
 		}
+		paused := ""
+		if item.Paused {
+			status = style.Dim.Render("⏸")
+			paused = style.Dim.Render(" (paused)")
+		}
 
 		gitStatus := style.Dim.Render("clean")
 		if !item.GitClean {
 			gitStatus = style.Bold.Render("dirty")
 		}
 
-		fmt.Printf("  %s %s/%s\n", status, item.Rig, item.Name)
+		fmt.Printf("  %s %s/%s%s\n", status, item.Rig, item.Name, paused)
 		fmt.Printf("    Branch: %s  Git: %s\n", item.Branch, gitStatus)
 		fmt.Printf("    %s\n", style.Dim.Render(item.Path))
 	}
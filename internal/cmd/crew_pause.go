@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var crewPauseCmd = &cobra.Command{
+	Use:   "pause [name...]",
+	Short: "Pause crew agent(s) without killing their sessions",
+	Long: `Pause the agent in crew workspace session(s), keeping its context.
+
+The agent's current turn is interrupted (as Escape does), leaving it idle
+at its prompt - making no API calls - with its session and everything in
+its context intact. While paused, nothing wakes it: nudges to the session
+(gt nudge, mail notifications, patrols) are refused, and the watchdog
+neither reports it nor runs its scheduled handoffs. Mail still arrives in
+its inbox.
+
+gt crew resume lifts the pause and nudges the agent to carry on where it
+was interrupted. gt crew list and gt crew status show paused sessions.
+Stopping or restarting a paused session ends the pause.
+
+Examples:
+  gt crew pause dave                 # Pause dave in the current rig
+  gt crew pause beads/grip           # Pause grip in the beads rig
+  gt crew pause --all --rig gastown  # Pause every running crew in gastown`,
+	Args: crewPauseArgs,
+	RunE: runCrewPause,
+}
+
+var crewResumeCmd = &cobra.Command{
+	Use:   "resume [name...]",
+	Short: "Resume crew agent(s) paused by gt crew pause",
+	Long: `Resume the agent in crew workspace session(s) paused by gt crew pause.
+
+The pause is lifted and the agent is nudged to continue the work it was
+doing when it was paused.
+
+Examples:
+  gt crew resume dave                 # Resume dave in the current rig
+  gt crew resume --all                # Resume every paused crew
+  gt crew resume --all --rig gastown  # ...in the gastown rig`,
+	Args: crewPauseArgs,
+	RunE: runCrewResume,
+}
+
+func init() {
+	crewPauseCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use (filter when using --all)")
+	crewPauseCmd.Flags().BoolVar(&crewAll, "all", false, "Pause all running crew sessions")
+	crewResumeCmd.Flags().StringVar(&crewRig, "rig", "", "Rig to use (filter when using --all)")
+	crewResumeCmd.Flags().BoolVar(&crewAll, "all", false, "Resume all paused crew sessions")
+
+	crewCmd.AddCommand(crewPauseCmd)
+	crewCmd.AddCommand(crewResumeCmd)
+}
+
+// crewPauseArgs requires crew names or --all, not both.
+func crewPauseArgs(cmd *cobra.Command, args []string) error {
+	if crewAll && len(args) > 0 {
+		return fmt.Errorf("cannot specify both --all and a name")
+	}
+	if !crewAll && len(args) == 0 {
+		return fmt.Errorf("requires at least 1 argument (or --all)")
+	}
+	return nil
+}
+
+// crewPauseTarget is a crew member to pause or resume.
+type crewPauseTarget struct {
+	rig, name string
+}
+
+// crewPauseTargets resolves the crew members named in args ("name" or
+// "rig/name"), or with --all those with running sessions in crewRig (or
+// every rig).
+func crewPauseTargets(args []string) ([]crewPauseTarget, error) {
+	if !crewAll {
+		var targets []crewPauseTarget
+		for _, arg := range args {
+			target := crewPauseTarget{rig: crewRig, name: arg}
+			if rig, name, ok := parseRigSlashName(arg); ok {
+				if target.rig == "" {
+					target.rig = rig
+				}
+				target.name = name
+			}
+			targets = append(targets, target)
+		}
+		return targets, nil
+	}
+
+	agents, err := getAgentSessions(true)
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	var targets []crewPauseTarget
+	for _, agent := range agents {
+		if agent.Type != AgentCrew || (crewRig != "" && agent.Rig != crewRig) {
+			continue
+		}
+		targets = append(targets, crewPauseTarget{rig: agent.Rig, name: agent.AgentName})
+	}
+	return targets, nil
+}
+
+func runCrewPause(cmd *cobra.Command, args []string) error {
+	return forEachCrewPauseTarget(args, "pause", func(mgr *crew.Manager, rigName, name string) (string, error) {
+		err := mgr.Pause(name)
+		switch {
+		case errors.Is(err, crew.ErrPaused):
+			return "already paused", nil
+		case errors.Is(err, crew.ErrSessionNotFound):
+			return "", fmt.Errorf("no session to pause")
+		case err != nil:
+			return "", err
+		}
+		return "paused", nil
+	})
+}
+
+func runCrewResume(cmd *cobra.Command, args []string) error {
+	return forEachCrewPauseTarget(args, "resume", func(mgr *crew.Manager, rigName, name string) (string, error) {
+		err := mgr.Resume(name)
+		switch {
+		case errors.Is(err, crew.ErrNotPaused):
+			if crewAll {
+				return "", nil // --all resumes only the paused ones
+			}
+			return "not paused", nil
+		case errors.Is(err, crew.ErrSessionNotFound):
+			return "", fmt.Errorf("no session to resume (start it with: gt crew start %s %s)", rigName, name)
+		case err != nil:
+			return "", err
+		}
+		return "resumed", nil
+	})
+}
+
+// forEachCrewPauseTarget runs fn for each target, printing what it did (an
+// empty result prints nothing), and fails if any failed.
+func forEachCrewPauseTarget(args []string, verb string, fn func(mgr *crew.Manager, rigName, name string) (string, error)) error {
+	targets, err := crewPauseTargets(args)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Printf("No running crew sessions to %s.\n", verb)
+		return nil
+	}
+
+	var failed int
+	for _, target := range targets {
+		mgr, r, err := getCrewManager(target.rig)
+		var result string
+		if err == nil {
+			result, err = fn(mgr, r.Name, target.name)
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("  %s %s: %s\n", style.ErrorPrefix, target.name, style.Dim.Render(err.Error()))
+			continue
+		}
+		if result != "" {
+			fmt.Printf("  %s [%s] %s: %s\n", style.SuccessPrefix, r.Name, target.name, result)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d %s(s) failed", failed, verb)
+	}
+	return nil
+}
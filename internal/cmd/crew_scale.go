@@ -0,0 +1,269 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/polecat"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var crewScaleCount int
+
+var crewScaleCmd = &cobra.Command{
+	Use:   "scale <rig> --count N",
+	Short: "Scale a rig's running crew sessions to a target count",
+	Long: `Start or stop crew sessions so that exactly N are running in the rig.
+
+Scaling up starts idle crew workspaces (those with no session) first, then
+creates new workspaces named from the rig's name theme.
+
+Scaling down retires sessions without hooked work first. A retired crew
+member's hooked work is handed off: re-slung to a surviving crew member
+that has nothing hooked, or released back to open when there is none.
+Retiring stops the session only; the workspace is kept and is reused first
+on the next scale up.
+
+Examples:
+  gt crew scale gastown --count 4              # Run 4 crew sessions
+  gt crew scale gastown --count 6 --agent kimi # New sessions run kimi
+  gt crew scale gastown --count 0              # Retire all crew sessions
+  gt crew scale gastown --count 2 --dry-run    # Show the plan`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCrewScale,
+}
+
+func init() {
+	crewScaleCmd.Flags().IntVar(&crewScaleCount, "count", 0, "Number of crew sessions to run (required)")
+	crewScaleCmd.Flags().StringVar(&crewAgentOverride, "agent", "", "Agent alias to run started crew workers with (overrides rig/town default)")
+	crewScaleCmd.Flags().BoolVar(&crewDryRun, "dry-run", false, "Show what would be started and retired")
+	_ = crewScaleCmd.MarkFlagRequired("count")
+
+	crewCmd.AddCommand(crewScaleCmd)
+}
+
+// crewScalePlan is what gt crew scale does to reach its target.
+type crewScalePlan struct {
+	Start  []string // Idle workspaces to start
+	Create []string // New workspaces to create and start
+	Retire []string // Running sessions to stop
+}
+
+// planCrewScale plans scaling from the running crew to count sessions.
+// workers are the rig's crew workspaces, busy those running crew with
+// hooked work, and names the candidates for new workspaces, in order.
+func planCrewScale(workers []string, running, busy map[string]bool, count int, names []string) crewScalePlan {
+	var plan crewScalePlan
+	var up, idle []string
+	for _, w := range workers {
+		if running[w] {
+			up = append(up, w)
+		} else {
+			idle = append(idle, w)
+		}
+	}
+	sort.Strings(up)
+	sort.Strings(idle)
+
+	if len(up) > count {
+		// Retire idle sessions before busy ones, newest names first
+		sort.SliceStable(up, func(i, j int) bool {
+			if busy[up[i]] != busy[up[j]] {
+				return !busy[up[i]]
+			}
+			return up[i] > up[j]
+		})
+		plan.Retire = up[:len(up)-count]
+		return plan
+	}
+
+	need := count - len(up)
+	for _, w := range idle {
+		if need == 0 {
+			return plan
+		}
+		plan.Start = append(plan.Start, w)
+		need--
+	}
+
+	taken := make(map[string]bool)
+	for _, w := range workers {
+		taken[w] = true
+	}
+	for _, n := range names {
+		if need == 0 {
+			return plan
+		}
+		if taken[n] || strings.ContainsAny(n, "-. ") {
+			continue // Not a valid crew name
+		}
+		plan.Create = append(plan.Create, n)
+		taken[n] = true
+		need--
+	}
+	for i := 1; need > 0; i++ {
+		n := fmt.Sprintf("crew%d", i)
+		if !taken[n] {
+			plan.Create = append(plan.Create, n)
+			taken[n] = true
+			need--
+		}
+	}
+	return plan
+}
+
+func runCrewScale(cmd *cobra.Command, args []string) error {
+	if crewScaleCount < 0 {
+		return fmt.Errorf("--count must be 0 or more, got %d", crewScaleCount)
+	}
+	crewMgr, r, err := getCrewManager(args[0])
+	if err != nil {
+		return err
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	workers, err := crewMgr.List()
+	if err != nil {
+		return fmt.Errorf("listing crew: %w", err)
+	}
+	var names []string
+	running := make(map[string]bool)
+	busy := make(map[string]bool)
+	hooked := make(map[string][]*beads.Issue)
+	b := beads.New(r.Path)
+	for _, w := range workers {
+		names = append(names, w.Name)
+		if ok, _ := crewMgr.IsRunning(w.Name); !ok {
+			continue
+		}
+		running[w.Name] = true
+		issues, err := b.List(beads.ListOptions{
+			Status:   beads.StatusHooked,
+			Assignee: crewAddress(r.Name, w.Name),
+			Priority: -1,
+		})
+		if err == nil && len(issues) > 0 {
+			busy[w.Name] = true
+			hooked[w.Name] = issues
+		}
+	}
+
+	themeNames, _ := polecat.GetThemeNames(polecat.ThemeForRig(r.Name))
+	plan := planCrewScale(names, running, busy, crewScaleCount, themeNames)
+	if len(plan.Start)+len(plan.Create)+len(plan.Retire) == 0 {
+		fmt.Printf("%s %s already runs %d crew session(s)\n", style.Dim.Render("○"), r.Name, crewScaleCount)
+		return nil
+	}
+
+	if crewDryRun {
+		fmt.Printf("Would scale %s from %d to %d crew session(s):\n", r.Name, len(running), crewScaleCount)
+		for _, n := range plan.Start {
+			fmt.Printf("  start   %s\n", crewAddress(r.Name, n))
+		}
+		for _, n := range plan.Create {
+			fmt.Printf("  create  %s\n", crewAddress(r.Name, n))
+		}
+		for _, n := range plan.Retire {
+			fmt.Printf("  retire  %s", crewAddress(r.Name, n))
+			for _, issue := range hooked[n] {
+				fmt.Printf("  (hands off %s)", issue.ID)
+			}
+			fmt.Println()
+		}
+		return nil
+	}
+
+	fmt.Printf("Scaling %s from %d to %d crew session(s)...\n", r.Name, len(running), crewScaleCount)
+	var lastErr error
+	if len(plan.Retire) > 0 {
+		retiring := make(map[string]bool)
+		for _, n := range plan.Retire {
+			retiring[n] = true
+		}
+		// Surviving crew with nothing hooked take over retired work
+		var takers []string
+		for _, n := range names {
+			if running[n] && !retiring[n] && !busy[n] {
+				takers = append(takers, n)
+			}
+		}
+		for _, n := range plan.Retire {
+			takers = handOffCrewWork(townRoot, r, b, n, hooked[n], takers)
+			if err := crewMgr.Stop(n); err != nil && !errors.Is(err, crew.ErrSessionNotFound) {
+				fmt.Printf("  %s %s: %v\n", style.ErrorPrefix, crewAddress(r.Name, n), err)
+				lastErr = err
+				continue
+			}
+			fmt.Printf("  %s %s: retired\n", style.SuccessPrefix, crewAddress(r.Name, n))
+		}
+	}
+
+	opts := crew.StartOptions{AgentOverride: crewAgentOverride}
+	for _, n := range append(plan.Start, plan.Create...) {
+		err := crewMgr.Start(n, opts)
+		switch {
+		case errors.Is(err, scheduler.ErrQueued):
+			fmt.Printf("  %s %s: queued until an agent slot frees up\n", style.Dim.Render("⏳"), crewAddress(r.Name, n))
+		case errors.Is(err, crew.ErrSessionRunning):
+			fmt.Printf("  %s %s: already running\n", style.Dim.Render("○"), crewAddress(r.Name, n))
+		case err != nil:
+			fmt.Printf("  %s %s: %v\n", style.ErrorPrefix, crewAddress(r.Name, n), err)
+			lastErr = err
+		default:
+			fmt.Printf("  %s %s: started\n", style.SuccessPrefix, crewAddress(r.Name, n))
+		}
+	}
+	return lastErr
+}
+
+// handOffCrewWork moves the hooked work of a crew member being retired: each
+// bead is re-slung to the next of takers, or released back to open once
+// takers run out. It returns the takers still free.
+func handOffCrewWork(townRoot string, r *rig.Rig, b *beads.Beads, name string, issues []*beads.Issue, takers []string) []string {
+	if len(issues) == 0 {
+		return takers
+	}
+	from := crewAddress(r.Name, name)
+	if agentBeadID := agentIDToBeadID(from, townRoot); agentBeadID != "" {
+		_ = b.ClearHookBead(agentBeadID) // Non-fatal: the session is going away
+	}
+	for _, issue := range issues {
+		if len(takers) > 0 {
+			to := crewAddress(r.Name, takers[0])
+			slingCmd := exec.Command("gt", "sling", issue.ID, to, "--force")
+			slingCmd.Stderr = os.Stderr
+			if err := slingCmd.Run(); err == nil {
+				fmt.Printf("  %s %s: handed %s to %s\n", style.Bold.Render("→"), from, issue.ID, to)
+				takers = takers[1:]
+				continue
+			}
+			style.PrintWarning("could not sling %s to %s, releasing it instead", issue.ID, to)
+		}
+		openStatus := "open"
+		emptyAssignee := ""
+		if err := b.Update(issue.ID, beads.UpdateOptions{Status: &openStatus, Assignee: &emptyAssignee}); err != nil {
+			style.PrintWarning("could not release %s: %v", issue.ID, err)
+			continue
+		}
+		fmt.Printf("  %s %s: released %s back to open\n", style.Bold.Render("→"), from, issue.ID)
+	}
+	return takers
+}
+
+// crewAddress returns the agent address of a crew member.
+func crewAddress(rigName, name string) string {
+	return fmt.Sprintf("%s/crew/%s", rigName, name)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanCrewScale(t *testing.T) {
+	workers := []string{"dave", "emma", "fred", "joe"}
+	running := map[string]bool{"dave": true, "emma": true, "fred": true}
+	busy := map[string]bool{"fred": true}
+	names := []string{"emma", "war-boy", "nux", "slit"}
+
+	tests := []struct {
+		name  string
+		count int
+		want  crewScalePlan
+	}{
+		{"at target", 3, crewScalePlan{}},
+		{"reuses idle first", 4, crewScalePlan{Start: []string{"joe"}}},
+		{"then creates", 6, crewScalePlan{Start: []string{"joe"}, Create: []string{"nux", "slit"}}},
+		{"overflows the theme", 8, crewScalePlan{Start: []string{"joe"}, Create: []string{"nux", "slit", "crew1", "crew2"}}},
+		{"retires idle first", 1, crewScalePlan{Retire: []string{"emma", "dave"}}},
+		{"retires busy last", 0, crewScalePlan{Retire: []string{"emma", "dave", "fred"}}},
+	}
+	for _, tt := range tests {
+		got := planCrewScale(workers, running, busy, tt.count, names)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: planCrewScale(%d) = %+v, want %+v", tt.name, tt.count, got, tt.want)
+		}
+	}
+}
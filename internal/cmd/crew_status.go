@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/crew"
@@ -17,17 +18,18 @@ import (
 
 // CrewStatusItem represents detailed status for a crew worker.
 type CrewStatusItem struct {
-	Name         string   `json:"name"`
-	Rig          string   `json:"rig"`
-	Path         string   `json:"path"`
-	Branch       string   `json:"branch"`
-	HasSession   bool     `json:"has_session"`
-	SessionID    string   `json:"session_id,omitempty"`
-	GitClean     bool     `json:"git_clean"`
-	GitModified  []string `json:"git_modified,omitempty"`
-	GitUntracked []string `json:"git_untracked,omitempty"`
-	MailTotal    int      `json:"mail_total"`
-	MailUnread   int      `json:"mail_unread"`
+	Name         string     `json:"name"`
+	Rig          string     `json:"rig"`
+	Path         string     `json:"path"`
+	Branch       string     `json:"branch"`
+	HasSession   bool       `json:"has_session"`
+	SessionID    string     `json:"session_id,omitempty"`
+	PausedAt     *time.Time `json:"paused_at,omitempty"` // Session paused by gt crew pause
+	GitClean     bool       `json:"git_clean"`
+	GitModified  []string   `json:"git_modified,omitempty"`
+	GitUntracked []string   `json:"git_untracked,omitempty"`
+	MailTotal    int        `json:"mail_total"`
+	MailUnread   int        `json:"mail_unread"`
 }
 
 func runCrewStatus(cmd *cobra.Command, args []string) error {
@@ -123,6 +125,7 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 		}
 		if hasSession {
 			item.SessionID = sessionID
+			item.PausedAt, _ = crewMgr.PausedSince(w.Name)
 		}
 
 		items = append(items, item)
@@ -144,6 +147,9 @@ func runCrewStatus(cmd *cobra.Command, args []string) error {
 		if item.HasSession {
 			sessionStatus = style.Bold.Render("● running")
 		}
+		if item.PausedAt != nil {
+			sessionStatus = style.Dim.Render("⏸ paused since " + item.PausedAt.Local().Format("Jan 2 15:04"))
+		}
 
 		fmt.Printf("%s %s/%s\n", sessionStatus, item.Rig, item.Name)
 		fmt.Printf("  Path:   %s\n", item.Path)
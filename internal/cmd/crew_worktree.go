@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/git"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/worktree"
+)
+
+func runCrewWorktrees(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	rigName := crewRig
+	// Parse rig/name format (e.g., "beads/emma" -> rig=beads, name=emma)
+	if r, crewName, ok := parseRigSlashName(name); ok {
+		rigName, name = r, crewName
+	}
+	crewMgr, r, err := getCrewManager(rigName)
+	if err != nil {
+		return err
+	}
+	worker, err := crewMgr.Get(name)
+	if err != nil {
+		if err == crew.ErrCrewNotFound {
+			return fmt.Errorf("crew workspace '%s' not found", name)
+		}
+		return fmt.Errorf("getting crew worker: %w", err)
+	}
+
+	wm := worktree.NewManager(worker.ClonePath, name)
+	t := tmux.ForRig(filepath.Dir(r.Path), r.Name) // Rigs live in the town root
+	inUse, _ := t.GetPaneWorkDir(crewSessionName(r.Name, name))
+
+	if crewPrune {
+		removed, err := wm.Prune(isBeadClosed, inUse)
+		for _, bead := range removed {
+			fmt.Printf("%s Removed worktree of %s (branch %s kept)\n", style.Bold.Render("✓"), bead, wm.Branch(bead))
+		}
+		if err != nil {
+			return err
+		}
+		if len(removed) == 0 {
+			fmt.Printf("%s No worktrees of closed beads\n", style.Dim.Render("○"))
+		}
+		return nil
+	}
+
+	worktrees, err := wm.List()
+	if err != nil {
+		return fmt.Errorf("listing worktrees: %w", err)
+	}
+	if len(worktrees) == 0 {
+		fmt.Printf("No bead worktrees for %s/%s\n", r.Name, name)
+		return nil
+	}
+	busy, _ := wm.Contains(inUse)
+	fmt.Printf("%s\n\n", style.Bold.Render(fmt.Sprintf("Bead worktrees for %s/%s", r.Name, name)))
+	for _, wt := range worktrees {
+		marker := "  "
+		if wt.Bead == busy {
+			marker = style.Bold.Render("●") + " "
+		}
+		fmt.Printf("  %s%-16s %s\n", marker, wt.Bead, style.Dim.Render(wt.Branch))
+		fmt.Printf("      %s\n", wt.Path)
+	}
+	return nil
+}
+
+// isBeadClosed reports whether beadID is closed, for pruning its worktree.
+// Beads that can't be looked up count as open.
+func isBeadClosed(beadID string) bool {
+	info, err := getBeadInfo(beadID)
+	return err == nil && info.Status == "closed"
+}
+
+// slingToWorktree gives beadID its own worktree in the clone of crew member
+// targetAgent (<rig>/crew/<name>) and restarts the member's session there.
+// Worktrees of the member's closed beads are pruned afterwards.
+func slingToWorktree(targetAgent, beadID, townRoot string) error {
+	parts := strings.Split(targetAgent, "/")
+	if len(parts) != 3 || parts[1] != "crew" {
+		return fmt.Errorf("--worktree needs a crew target, not %s", targetAgent)
+	}
+	rigName, name := parts[0], parts[2]
+
+	_, r, err := getRig(rigName)
+	if err != nil {
+		return err
+	}
+	worker, err := crew.NewManager(r, git.NewGit(r.Path)).Get(name)
+	if err != nil {
+		return fmt.Errorf("getting crew worker: %w", err)
+	}
+	wm := worktree.NewManager(worker.ClonePath, name)
+	wt, err := wm.Create(beadID)
+	if err != nil {
+		return err
+	}
+	// Give the worktree what the clone has: shared beads, PRIME.md and
+	// the agent's hooks (non-fatal, like for the clone itself)
+	if err := beads.SetupRedirect(townRoot, wt.Path); err != nil {
+		style.PrintWarning("could not set up shared beads in worktree: %v", err)
+	}
+	if err := beads.ProvisionPrimeMDForWorktree(wt.Path); err != nil {
+		style.PrintWarning("could not provision PRIME.md in worktree: %v", err)
+	}
+	if err := runtime.EnsureSettingsForRole(wt.Path, "crew", config.LoadRuntimeConfig(r.Path)); err != nil {
+		style.PrintWarning("could not ensure settings in worktree: %v", err)
+	}
+
+	t := tmux.ForRig(townRoot, rigName)
+	sessionID := crewSessionName(rigName, name)
+	if current, err := t.GetPaneWorkDir(sessionID); err == nil && current != wt.Path {
+		pane, err := t.GetPaneID(sessionID)
+		if err != nil {
+			return fmt.Errorf("getting pane: %w", err)
+		}
+		agent, _ := t.GetEnvironment(sessionID, "GT_AGENT")
+		beacon := session.FormatStartupBeacon(session.BeaconConfig{
+			Recipient: targetAgent,
+			Sender:    "gt-sling",
+			Topic:     "restart",
+		})
+		startupCmd, err := config.BuildCrewStartupCommandWithAgentOverride(rigName, name, r.Path, beacon, agent)
+		if err != nil {
+			return fmt.Errorf("building startup command: %w", err)
+		}
+		if err := t.RespawnPaneWithWorkDir(pane, wt.Path, startupCmd); err != nil {
+			gtlog.L().Error("worktree respawn failed", "session", sessionID, "workdir", wt.Path, "bead", beadID, "err", err)
+			return fmt.Errorf("moving session to worktree: %w", err)
+		}
+		gtlog.L().Info("moved session to worktree", "session", sessionID, "workdir", wt.Path, "bead", beadID)
+	}
+	fmt.Printf("%s Working in worktree %s (branch %s)\n", style.Bold.Render("✓"), wt.Path, wt.Branch)
+
+	removed, err := wm.Prune(isBeadClosed, wt.Path)
+	for _, bead := range removed {
+		fmt.Printf("  Removed worktree of closed bead %s\n", bead)
+	}
+	if err != nil {
+		style.PrintWarning("could not prune worktrees: %v", err)
+	}
+	return nil
+}
@@ -8,41 +8,71 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/tui/dashboard"
 	"github.com/steveyegge/gastown/internal/web"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
 var (
-	dashboardPort int
-	dashboardOpen bool
+	dashboardPort     int
+	dashboardOpen     bool
+	dashboardWeb      bool
+	dashboardInterval time.Duration
 )
 
 var dashboardCmd = &cobra.Command{
 	Use:     "dashboard",
 	GroupID: GroupDiag,
-	Short:   "Start the convoy tracking web dashboard",
-	Long: `Start a web server that displays the convoy tracking dashboard.
+	Short:   "Live overview of agent sessions (or the web dashboard)",
+	Long: `Open a live, full-screen table of every Gas Town tmux session.
 
-The dashboard shows real-time convoy status with:
-- Convoy list with status indicators
-- Progress tracking for each convoy
-- Last activity indicator (green/yellow/red)
-- Auto-refresh every 30 seconds via htmx
+Each row shows the session, its role, the agent CLI running in it, the bead
+on its hook, how long since the pane last produced output, and its health
+(as probed by gt doctor sessions). The table refreshes every --interval.
+
+Keys:
+  j/k, ↑/↓   Move the selection
+  enter, a   Attach to the session (detach to come back)
+  h          Hand the session off to a fresh agent
+  x          Kill the session (asks first)
+  r          Refresh now
+  ?          Toggle full help
+  q          Quit
+
+With --web (implied by --port or --open), start a web server with the
+convoy tracking dashboard instead: convoy status, progress and last
+activity, auto-refreshed every 30 seconds via htmx. Outside a workspace the
+web server runs in setup mode.
 
 Example:
-  gt dashboard              # Start on default port 8080
-  gt dashboard --port 3000  # Start on port 3000
-  gt dashboard --open       # Start and open browser`,
+  gt dashboard                # Session overview in the terminal
+  gt dashboard --interval 2s  # Refresh every 2 seconds
+  gt dashboard --web          # Web dashboard on default port 8080
+  gt dashboard --port 3000    # Web dashboard on port 3000
+  gt dashboard --open         # Start the web dashboard and open browser`,
 	RunE: runDashboard,
 }
 
 func init() {
 	dashboardCmd.Flags().IntVar(&dashboardPort, "port", 8080, "HTTP port to listen on")
 	dashboardCmd.Flags().BoolVar(&dashboardOpen, "open", false, "Open browser automatically")
+	dashboardCmd.Flags().BoolVar(&dashboardWeb, "web", false, "Start the web dashboard instead of the terminal UI")
+	dashboardCmd.Flags().DurationVar(&dashboardInterval, "interval", dashboard.DefaultInterval, "How often the terminal UI refreshes")
 	rootCmd.AddCommand(dashboardCmd)
 }
 
 func runDashboard(cmd *cobra.Command, args []string) error {
+	useWeb := dashboardWeb || cmd.Flags().Changed("port") || cmd.Flags().Changed("open")
+	if !useWeb {
+		if townRoot, err := workspace.FindFromCwdOrError(); err == nil {
+			return runDashboardTUI(townRoot)
+		}
+	}
+	return runWebDashboard()
+}
+
+// runWebDashboard serves the convoy tracking web dashboard.
+func runWebDashboard() error {
 	// Check if we're in a workspace - if not, run in setup mode
 	var handler http.Handler
 	var err error
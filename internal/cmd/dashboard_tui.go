@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/tui/dashboard"
+)
+
+// runDashboardTUI launches the interactive session dashboard.
+func runDashboardTUI(townRoot string) error {
+	if dashboardInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	t := tmux.NewTmux()
+	m := dashboard.New(dashboard.Config{
+		Load: func() ([]dashboard.Row, error) {
+			return loadDashboardRows(townRoot, t)
+		},
+		Attach: func(row dashboard.Row) *exec.Cmd {
+			if tmux.IsInsideTmux() {
+				return exec.Command("tmux", "switch-client", "-t", row.Session)
+			}
+			return exec.Command("tmux", "attach-session", "-t", row.Session)
+		},
+		Handoff: dashboardHandoff,
+		Kill: func(row dashboard.Row) error {
+			return t.KillSessionWithProcesses(row.Session)
+		},
+		Interval: dashboardInterval,
+	})
+	p := tea.NewProgram(m, tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// loadDashboardRows probes the sessions and joins in their agent, activity
+// and hooked bead.
+func loadDashboardRows(townRoot string, t *tmux.Tmux) ([]dashboard.Row, error) {
+	report, err := health.NewChecker(townRoot).Run()
+	if err != nil {
+		return nil, err
+	}
+
+	rigs := make(map[string]bool)
+	for _, s := range report.Sessions {
+		if s.Rig != "" {
+			rigs[s.Rig] = true
+		}
+	}
+	hooked := hookedBeadsByAssignee(townRoot, rigs)
+
+	agent := func(sess string) string {
+		env, _ := t.GetAllEnvironment(sess)
+		return env["GT_AGENT"]
+	}
+	activity := func(sess string) time.Time {
+		info, err := t.GetSessionInfo(sess)
+		if err != nil {
+			return time.Time{}
+		}
+		return parseTmuxTime(info.Activity)
+	}
+	return dashboardRows(report, agent, activity, hooked), nil
+}
+
+// dashboardRows builds the dashboard table from a health report. agent and
+// activity look up a session's GT_AGENT and last output time; hooked maps
+// an assignee address to the bead on its hook.
+func dashboardRows(report *health.Report, agent func(string) string, activity func(string) time.Time, hooked map[string]*beads.Issue) []dashboard.Row {
+	rows := make([]dashboard.Row, 0, len(report.Sessions))
+	for _, s := range report.Sessions {
+		row := dashboard.Row{
+			Session:  s.Session,
+			Role:     s.Role,
+			Agent:    agent(s.Session),
+			Activity: activity(s.Session),
+			Health:   string(s.Status),
+		}
+		if identity, err := session.ParseSessionName(s.Session); err == nil {
+			row.Address = identity.Address()
+		}
+		issue := hooked[row.Address]
+		if issue == nil && s.Role == string(session.RolePolecat) {
+			issue = hooked[s.Rig+"/"+s.Name] // Short polecat form, e.g. gastown/Toast
+		}
+		if issue != nil {
+			row.Bead = issue.ID
+			row.BeadTitle = issue.Title
+		}
+		var details []string
+		for _, p := range s.Probes {
+			if !p.OK {
+				details = append(details, p.Name+": "+p.Detail)
+			}
+		}
+		row.Detail = strings.Join(details, "; ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// hookedBeadsByAssignee returns the hooked beads in the town and the given
+// rigs, keyed by assignee. Databases that can't be listed are skipped.
+func hookedBeadsByAssignee(townRoot string, rigs map[string]bool) map[string]*beads.Issue {
+	dirs := []string{beads.GetTownBeadsPath(townRoot)}
+	for rig := range rigs {
+		dirs = append(dirs, filepath.Join(townRoot, rig, "mayor", "rig"))
+	}
+
+	hooked := make(map[string]*beads.Issue)
+	for _, dir := range dirs {
+		issues, err := beads.New(dir).List(beads.ListOptions{Status: beads.StatusHooked, Priority: -1})
+		if err != nil {
+			continue
+		}
+		for _, issue := range issues {
+			if assignee := strings.TrimSuffix(issue.Assignee, "/"); assignee != "" {
+				hooked[assignee] = issue
+			}
+		}
+	}
+	return hooked
+}
+
+// parseTmuxTime parses a tmux unix-seconds timestamp; zero if unparseable.
+func parseTmuxTime(s string) time.Time {
+	secs, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil || secs <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0)
+}
+
+// dashboardHandoff runs gt handoff against the row's role without switching
+// the dashboard's client to it.
+func dashboardHandoff(row dashboard.Row) error {
+	if row.Address == "" {
+		return fmt.Errorf("%s has no role address", row.Session)
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	out, err := exec.Command(gtPath, "handoff", row.Address, "--force", "--no-switch").CombinedOutput()
+	if err != nil {
+		lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" {
+			return fmt.Errorf("%s", last)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/health"
+)
+
+func TestDashboardRows(t *testing.T) {
+	active := time.Unix(1700000000, 0)
+	report := &health.Report{Sessions: []health.SessionHealth{
+		{Session: "gt-gastown-crew-max", Role: "crew", Rig: "gastown", Name: "max", Status: health.StatusHealthy,
+			Probes: []health.Probe{{Name: health.ProbeProcess, OK: true}}},
+		{Session: "gt-gastown-Toast", Role: "polecat", Rig: "gastown", Name: "Toast", Status: health.StatusDead,
+			Probes: []health.Probe{
+				{Name: health.ProbeProcess, Detail: "agent process not running in pane"},
+				{Name: health.ProbeEnv, Detail: "GT_ROLE not set"},
+			}},
+	}}
+	agents := map[string]string{"gt-gastown-crew-max": "claude"}
+	hooked := map[string]*beads.Issue{
+		"gastown/crew/max": {ID: "gt-abc", Title: "Fix the build"},
+		"gastown/Toast":    {ID: "gt-def", Title: "Write docs"},
+	}
+
+	rows := dashboardRows(report,
+		func(s string) string { return agents[s] },
+		func(s string) time.Time { return active },
+		hooked)
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+
+	crew := rows[0]
+	if crew.Address != "gastown/crew/max" || crew.Agent != "claude" || crew.Bead != "gt-abc" ||
+		crew.BeadTitle != "Fix the build" || crew.Health != "healthy" || crew.Detail != "" || !crew.Activity.Equal(active) {
+		t.Errorf("crew row = %+v", crew)
+	}
+
+	polecat := rows[1]
+	if polecat.Address != "gastown/polecats/Toast" || polecat.Agent != "" || polecat.Bead != "gt-def" || polecat.Health != "dead" {
+		t.Errorf("polecat row = %+v", polecat)
+	}
+	if want := "process: agent process not running in pane; env: GT_ROLE not set"; polecat.Detail != want {
+		t.Errorf("polecat detail = %q, want %q", polecat.Detail, want)
+	}
+}
+
+func TestParseTmuxTime(t *testing.T) {
+	if got := parseTmuxTime("1700000000\n"); !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("parseTmuxTime() = %v", got)
+	}
+	for _, s := range []string{"", "0", "soon"} {
+		if got := parseTmuxTime(s); !got.IsZero() {
+			t.Errorf("parseTmuxTime(%q) = %v, want zero", s, got)
+		}
+	}
+}
@@ -41,6 +41,7 @@ Town root protection:
 
 Infrastructure checks:
   - stale-binary             Check if gt binary is up to date with repo
+  - tmux-version             Check tmux is installed and new enough (2.6+)
   - daemon                   Check if daemon is running (fixable)
   - repo-fingerprint         Check database has valid repo fingerprint (fixable)
   - boot-health              Check Boot watchdog health (vet mode)
@@ -75,6 +76,7 @@ Routing checks (fixable):
 Session hook checks:
   - session-hooks            Check settings.json use session-start.sh
   - claude-settings          Check Claude settings.json match templates (fixable)
+  - agent-tty                Check agents that require a TTY can launch in tmux
 
 Patrol checks:
   - patrol-molecules-exist   Verify patrol molecules exist
@@ -85,7 +87,9 @@ Patrol checks:
 
 Use --fix to attempt automatic fixes for issues that support it.
 Use --rig to check a specific rig instead of the entire workspace.
-Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).`,
+Use --slow to highlight slow checks (default threshold: 1s, e.g. --slow=500ms).
+
+Run 'gt doctor sessions' to probe the health of each running agent session.`,
 	RunE: runDoctor,
 }
 
@@ -126,6 +130,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	// Register built-in checks
 	d.Register(doctor.NewStaleBinaryCheck())
 	d.Register(doctor.NewSqlite3Check())
+	d.Register(doctor.NewTmuxVersionCheck())
 	d.Register(doctor.NewTownGitCheck())
 	d.Register(doctor.NewTownRootBranchCheck())
 	d.Register(doctor.NewPreCheckoutHookCheck())
@@ -176,6 +181,7 @@ func runDoctor(cmd *cobra.Command, args []string) error {
 	d.Register(doctor.NewRuntimeGitignoreCheck())
 	d.Register(doctor.NewLegacyGastownCheck())
 	d.Register(doctor.NewClaudeSettingsCheck())
+	d.Register(doctor.NewAgentTTYCheck())
 
 	// Priming subsystem check
 	d.Register(doctor.NewPrimingCheck())
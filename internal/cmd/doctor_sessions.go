@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentstatus"
+	"github.com/steveyegge/gastown/internal/health"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	doctorSessionsJSON bool
+	doctorSessionsIdle time.Duration
+)
+
+var doctorSessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Probe the health of every agent session",
+	Long: `Probe every Gas Town tmux session and report its health by role.
+
+For each session:
+  - process   the agent process is still running in the pane
+  - activity  the pane has produced output within --idle (default 1h)
+  - output    the agent isn't waiting for input or stopped on an error,
+              going by its preset's output patterns
+  - env       GT_ROLE and the other identity variables are set
+
+A session whose agent has exited is reported as dead; one that fails any
+other probe is degraded. Worker lock files left behind by sessions that no
+longer exist are listed as stale locks (gt doctor --fix removes them).
+
+Exits non-zero if any session is dead or degraded.
+
+Examples:
+  gt doctor sessions
+  gt doctor sessions --idle 15m
+  gt doctor sessions --json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctorSessions,
+}
+
+func init() {
+	doctorSessionsCmd.Flags().BoolVar(&doctorSessionsJSON, "json", false, "Output as JSON")
+	doctorSessionsCmd.Flags().DurationVar(&doctorSessionsIdle, "idle", health.DefaultIdleAfter, "Report agents whose pane has been silent this long (0 disables)")
+	doctorCmd.AddCommand(doctorSessionsCmd)
+}
+
+func runDoctorSessions(cmd *cobra.Command, args []string) error {
+	if doctorSessionsIdle < 0 {
+		return fmt.Errorf("--idle must not be negative")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	checker := health.NewChecker(townRoot)
+	checker.IdleAfter = doctorSessionsIdle
+	report, err := checker.Run()
+	if err != nil {
+		return err
+	}
+
+	if doctorSessionsJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printSessionHealth(report, townRoot)
+	}
+
+	if report.Unhealthy() > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+func printSessionHealth(report *health.Report, townRoot string) {
+	fmt.Printf("%s\n\n", style.Bold.Render("Agent sessions"))
+	if len(report.Sessions) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("No Gas Town sessions running"))
+	}
+	for _, s := range report.Sessions {
+		var icon string
+		switch s.Status {
+		case health.StatusHealthy:
+			icon = style.SuccessPrefix
+		case health.StatusDegraded:
+			icon = style.WarningPrefix
+		default:
+			icon = style.ErrorPrefix
+		}
+		line := fmt.Sprintf("  %s %-10s %s  %s", icon, s.Role, s.Session, s.Status)
+		if s.Activity != "" && s.Activity != agentstatus.StatusUnknown {
+			line += "  " + style.Dim.Render(string(s.Activity))
+		}
+		fmt.Println(line)
+		for _, p := range s.Probes {
+			if !p.OK {
+				fmt.Printf("      %s: %s\n", p.Name, style.Dim.Render(p.Detail))
+			}
+		}
+	}
+
+	if len(report.StaleLocks) > 0 {
+		fmt.Printf("\n%s\n", style.Bold.Render("Stale locks"))
+		for _, l := range report.StaleLocks {
+			dir := l.WorkerDir
+			if rel, err := filepath.Rel(townRoot, dir); err == nil && !strings.HasPrefix(rel, "..") {
+				dir = rel
+			}
+			fmt.Printf("  %s %s  %s\n", style.WarningPrefix, dir, style.Dim.Render(fmt.Sprintf("dead PID %d", l.PID)))
+		}
+	}
+
+	fmt.Printf("\n%d session(s), %d unhealthy, %d stale lock(s)\n",
+		len(report.Sessions), report.Unhealthy(), len(report.StaleLocks))
+}
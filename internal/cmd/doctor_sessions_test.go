@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/health"
+)
+
+func TestPrintSessionHealth(t *testing.T) {
+	townRoot := t.TempDir()
+	report := &health.Report{
+		Sessions: []health.SessionHealth{
+			{Session: "hq-mayor", Role: "mayor", Status: health.StatusHealthy,
+				Probes: []health.Probe{{Name: health.ProbeProcess, OK: true}}},
+			{Session: "gt-web-witness", Role: "witness", Rig: "web", Status: health.StatusDead,
+				Probes: []health.Probe{{Name: health.ProbeProcess, Detail: "agent process not running in pane"}}},
+		},
+		StaleLocks: []health.StaleLock{{WorkerDir: filepath.Join(townRoot, "web", "polecats", "gone"), PID: 42}},
+	}
+
+	out := captureStdout(t, func() { printSessionHealth(report, townRoot) })
+	for _, want := range []string{
+		"hq-mayor",
+		"gt-web-witness  dead",
+		"process: agent process not running in pane",
+		filepath.Join("web", "polecats", "gone"),
+		"dead PID 42",
+		"2 session(s), 1 unhealthy, 1 stale lock(s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, townRoot) {
+		t.Errorf("stale lock path not shown relative to the town root:\n%s", out)
+	}
+}
@@ -11,9 +11,11 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/townlog"
@@ -520,6 +522,23 @@ notifyWitness:
 	// Log done event (townlog and activity feed)
 	_ = LogDone(townRoot, sender, issueID)
 	_ = events.LogFeed(events.TypeDone, sender, events.DonePayload(issueID, branch))
+	if exitType == ExitCompleted {
+		hooks.Fire(townRoot, hooks.BeadCompleted, map[string]string{
+			"bead":   issueID,
+			"agent":  sender,
+			"branch": branch,
+			"source": "gt done",
+		})
+		notifyBeadDone(townRoot, rigName, sender, issueID, branch, mrID)
+	}
+	switch {
+	case exitType == ExitCompleted && mrID != "":
+		advanceBeadStage(townRoot, issueID, beads.StageReview, sender, "gt done")
+	case exitType == ExitCompleted:
+		advanceBeadStage(townRoot, issueID, beads.StageDone, sender, "gt done")
+	case exitType == ExitEscalated:
+		advanceBeadStage(townRoot, issueID, beads.StageFailed, sender, "gt done")
+	}
 
 	// Update agent bead state (ZFC: self-report completion)
 	updateAgentStateOnDone(cwd, townRoot, exitType, issueID)
@@ -831,6 +850,12 @@ func selfKillSession(townRoot string, roleInfo RoleInfo) error {
 	// Log to events (JSON audit log with structured payload)
 	_ = events.LogFeed(events.TypeSessionDeath, agentID,
 		events.SessionDeathPayload(sessionName, agentID, "self-clean: done means gone", "gt done"))
+	hooks.Fire(townRoot, hooks.SessionEnd, map[string]string{
+		"session": sessionName,
+		"agent":   agentID,
+		"reason":  "self-clean: done means gone",
+	})
+	session.Forget(sessionName) // Before the kill, which takes us with it
 
 	// Kill our own tmux session with proper process cleanup
 	// This will terminate Claude and all child processes, completing the self-cleaning cycle.
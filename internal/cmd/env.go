@@ -0,0 +1,240 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	envShowJSON bool
+	envReveal   bool
+)
+
+var envCmd = &cobra.Command{
+	Use:     "env",
+	GroupID: GroupDiag,
+	Short:   "Show and compare sessions' tmux environments",
+	Long: `Show the environment tmux gives new panes of a session (tmux
+show-environment), or compare two sessions', to find out why one agent
+picks up the right GT_RIG or KIMI_SESSION_ID and another doesn't.
+
+Sessions are tmux session names or agent addresses (gastown/crew/max,
+mayor). Values that look like secrets - API keys, tokens, passwords, by
+variable name or by the look of the value - are printed as <redacted>;
+--reveal prints them as they are.
+
+Examples:
+  gt env show gastown/crew/max
+  gt env show mayor --json
+  gt env diff gastown/crew/max gastown/crew/joe`,
+	RunE: requireSubcommand,
+}
+
+var envShowCmd = &cobra.Command{
+	Use:   "show <session>",
+	Short: "Print a session's environment",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runEnvShow,
+}
+
+var envDiffCmd = &cobra.Command{
+	Use:   "diff <session-a> <session-b>",
+	Short: "Print the variables that differ between two sessions",
+	Long: `Print the variables set in only one of two sessions, or set to
+different values, as - lines for the first session and + lines for the
+second. A secret that differs shows as <redacted> on both sides.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runEnvDiff,
+}
+
+func init() {
+	envShowCmd.Flags().BoolVar(&envShowJSON, "json", false, "Output as JSON")
+	envCmd.PersistentFlags().BoolVar(&envReveal, "reveal", false, "Print secret values instead of <redacted>")
+	envCmd.AddCommand(envShowCmd)
+	envCmd.AddCommand(envDiffCmd)
+	rootCmd.AddCommand(envCmd)
+}
+
+func runEnvShow(cmd *cobra.Command, args []string) error {
+	sess, env, err := sessionEnvironment(args[0])
+	if err != nil {
+		return err
+	}
+	if !envReveal {
+		env = redactEnv(env)
+	}
+
+	if envShowJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(env)
+	}
+	if len(env) == 0 {
+		fmt.Printf("%s %s has no session environment\n", style.Dim.Render("○"), sess)
+		return nil
+	}
+	for _, key := range slices.Sorted(maps.Keys(env)) {
+		fmt.Printf("%s=%s\n", key, env[key])
+	}
+	return nil
+}
+
+func runEnvDiff(cmd *cobra.Command, args []string) error {
+	sessA, envA, err := sessionEnvironment(args[0])
+	if err != nil {
+		return err
+	}
+	sessB, envB, err := sessionEnvironment(args[1])
+	if err != nil {
+		return err
+	}
+
+	changes := diffEnv(envA, envB)
+	if !envReveal {
+		changes = redactEnvChanges(changes)
+	}
+	fmt.Printf("%s\n%s\n", style.Error.Render("--- "+sessA), style.Success.Render("+++ "+sessB))
+	for _, c := range changes {
+		if c.inA {
+			fmt.Println(style.Error.Render("- " + c.key + "=" + c.a))
+		}
+		if c.inB {
+			fmt.Println(style.Success.Render("+ " + c.key + "=" + c.b))
+		}
+	}
+	same := 0
+	for k, v := range envA {
+		if vb, ok := envB[k]; ok && vb == v {
+			same++
+		}
+	}
+	fmt.Println(style.Dim.Render(fmt.Sprintf("%d differ, %d the same", len(changes), same)))
+	return nil
+}
+
+// sessionEnvironment resolves target to a running session and returns its
+// name and tmux environment.
+func sessionEnvironment(target string) (string, map[string]string, error) {
+	sess, err := resolveRoleToSession(target)
+	if err != nil {
+		return "", nil, err
+	}
+	t := tmuxForSession(sess)
+	exists, err := t.HasSession(sess)
+	if err != nil {
+		return "", nil, fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return "", nil, fmt.Errorf("session %s is not running", sess)
+	}
+	env, err := t.GetAllEnvironment(sess)
+	if err != nil {
+		return "", nil, fmt.Errorf("reading environment of %s: %w", sess, err)
+	}
+	return sess, env, nil
+}
+
+// envChange is a variable that differs between two environments: set in
+// only one, or to different values.
+type envChange struct {
+	key      string
+	a, b     string
+	inA, inB bool
+}
+
+// diffEnv returns the variables that differ between a and b, by name.
+func diffEnv(a, b map[string]string) []envChange {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var changes []envChange
+	for _, k := range keys {
+		va, inA := a[k]
+		vb, inB := b[k]
+		if inA && inB && va == vb {
+			continue
+		}
+		changes = append(changes, envChange{key: k, a: va, b: vb, inA: inA, inB: inB})
+	}
+	return changes
+}
+
+// redactedValue replaces the value of a secret in gt env output.
+const redactedValue = "<redacted>"
+
+var (
+	// secretEnvKeyPattern matches names of variables that hold secrets,
+	// e.g. ANTHROPIC_API_KEY, GITHUB_TOKEN, DB_PASSWORD.
+	secretEnvKeyPattern = regexp.MustCompile(`(?i)(^|_)(API_?KEY|ACCESS_KEY|PRIVATE_KEY|SECRET|TOKEN|PASSWORD|PASSWD|CREDENTIALS?)($|_)`)
+
+	// secretEnvValuePatterns match values that look like credentials
+	// whatever the variable is called.
+	secretEnvValuePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{16,}`),                     // OpenAI, Anthropic, Moonshot keys
+		regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{20,}`),                // GitHub tokens
+		regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{20,}`),              // GitHub fine-grained tokens
+		regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),              // Slack tokens
+		regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),                        // AWS access key IDs
+		regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`),                   // Google API keys
+		regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), // JWTs
+		regexp.MustCompile(`://[^/\s:@]+:[^/\s@]+@`),                      // Passwords in URLs
+	}
+)
+
+// isSecretEnv reports whether the variable key=value holds a secret.
+func isSecretEnv(key, value string) bool {
+	if value == "" {
+		return false
+	}
+	if secretEnvKeyPattern.MatchString(key) {
+		return true
+	}
+	for _, re := range secretEnvValuePatterns {
+		if re.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactEnv returns a copy of env with secret values redacted.
+func redactEnv(env map[string]string) map[string]string {
+	redacted := make(map[string]string, len(env))
+	for k, v := range env {
+		if isSecretEnv(k, v) {
+			v = redactedValue
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// redactEnvChanges redacts both sides of a change when either is a
+// secret, so a redacted value can't be read off the other side.
+func redactEnvChanges(changes []envChange) []envChange {
+	redacted := make([]envChange, len(changes))
+	for i, c := range changes {
+		if isSecretEnv(c.key, c.a) || isSecretEnv(c.key, c.b) {
+			if c.inA {
+				c.a = redactedValue
+			}
+			if c.inB {
+				c.b = redactedValue
+			}
+		}
+		redacted[i] = c
+	}
+	return redacted
+}
@@ -34,3 +34,29 @@ func IsSilentExit(err error) (int, bool) {
 	}
 	return 0, false
 }
+
+// ExitCodeError attaches a process exit code to an error. Unlike
+// SilentExitError the error is still reported; the code lets scripts
+// branch on the failure reason.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code carried by an ExitCodeError in err's chain.
+// Returns 0 and false if err is nil or carries no exit code.
+func ExitCode(err error) (int, bool) {
+	var ee *ExitCodeError
+	if errors.As(err, &ee) {
+		return ee.Code, true
+	}
+	return 0, false
+}
@@ -90,3 +90,25 @@ func TestSilentExitError_Is(t *testing.T) {
 		t.Errorf("errors.As extracted code = %d, want 1", target.Code)
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	if code, ok := ExitCode(nil); ok || code != 0 {
+		t.Errorf("ExitCode(nil) = %d, %v; want 0, false", code, ok)
+	}
+	if _, ok := ExitCode(errors.New("plain")); ok {
+		t.Error("ExitCode(plain error) should report no code")
+	}
+
+	base := errors.New("session gone")
+	err := fmt.Errorf("handoff: %w", &ExitCodeError{Code: 3, Err: base})
+	code, ok := ExitCode(err)
+	if !ok || code != 3 {
+		t.Errorf("ExitCode(wrapped) = %d, %v; want 3, true", code, ok)
+	}
+	if !errors.Is(err, base) {
+		t.Error("ExitCodeError should unwrap to its error")
+	}
+	if err.Error() != "handoff: session gone" {
+		t.Errorf("Error() = %q, want the wrapped message", err.Error())
+	}
+}
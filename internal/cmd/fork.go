@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+var (
+	forkLines  int
+	forkDryRun bool
+)
+
+var forkCmd = &cobra.Command{
+	Use:     "fork <role>",
+	GroupID: GroupAgents,
+	Short:   "Branch an agent session into a new one",
+	Long: `Start a copy of an agent session in a new tmux session, leaving the
+original running.
+
+Agents that can fork a session (claude --fork-session) resume a copy of it,
+with the whole conversation. Agents that can't, like Kimi, start fresh and
+are pointed at a context file holding the end of the original session's
+transcript (see gt transcript), so they can pick up where it was.
+
+The fork is named after the original: gt-gastown-crew-max-fork,
+then -fork-2 and so on. Targets are resolved like gt handoff targets.
+
+Examples:
+  gt fork crew                  # Fork your crew session
+  gt fork gastown/crew/max      # Fork another session
+  gt fork mayor --lines 500     # Carry more transcript context
+  gt fork witness --dry-run     # Show what would be started`,
+	Args: cobra.ExactArgs(1),
+	RunE: runFork,
+}
+
+func init() {
+	forkCmd.Flags().IntVarP(&forkLines, "lines", "n", 200, "Transcript lines to carry over when the agent cannot fork")
+	forkCmd.Flags().BoolVar(&forkDryRun, "dry-run", false, "Show the fork without starting it")
+	rootCmd.AddCommand(forkCmd)
+}
+
+// Fork modes.
+const (
+	forkNative     = "native"     // The agent resumes a copy of the session
+	forkTranscript = "transcript" // A fresh session reads transcript context
+)
+
+// ForkPlan is how a session is forked.
+type ForkPlan struct {
+	Source      string
+	Session     string
+	Agent       string
+	Mode        string
+	Command     string
+	ContextFile string // forkTranscript only
+	Prompt      string // Nudged once the fresh agent is ready
+}
+
+func runFork(cmd *cobra.Command, args []string) error {
+	if forkLines <= 0 {
+		return fmt.Errorf("--lines must be positive")
+	}
+	t := tmux.NewTmux()
+	source, err := resolveRoleToSession(args[0])
+	if err != nil {
+		return err
+	}
+	if running, err := t.HasSession(source); err != nil || !running {
+		return fmt.Errorf("session %s is not running", source)
+	}
+
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return fmt.Errorf("cannot detect town root - run from within a Gas Town workspace")
+	}
+	workDir, err := t.GetPaneWorkDir(source)
+	if err != nil || workDir == "" {
+		if workDir, err = sessionWorkDir(source, townRoot); err != nil {
+			return err
+		}
+	}
+
+	env, err := t.GetAllEnvironment(source)
+	if err != nil {
+		return fmt.Errorf("reading %s environment: %w", source, err)
+	}
+	rc, agent, err := config.ResolveAgentConfigWithOverride(townRoot, "", env["GT_AGENT"])
+	if err != nil {
+		return fmt.Errorf("resolving agent: %w", err)
+	}
+
+	fork := nextForkSessionName(source, func(name string) bool {
+		exists, err := t.HasSession(name)
+		return err == nil && exists
+	})
+	plan, err := planFork(source, fork, agent, forkSessionID(agent, env, workDir), rc, workDir)
+	if err != nil {
+		return err
+	}
+
+	// The fork is the same agent identity, so it keeps GT_ROLE, GT_RIG, etc.,
+	// but gets its own agent session ID
+	forkEnv := make(map[string]string)
+	for k, v := range env {
+		if strings.HasPrefix(k, "GT_") && k != "GT_SESSION_ID" {
+			forkEnv[k] = v
+		}
+	}
+	command := config.PrependEnv(plan.Command, forkEnv)
+
+	if forkDryRun {
+		printForkPlan(plan, workDir)
+		return nil
+	}
+
+	if plan.ContextFile != "" {
+		if err := writeForkContext(source, plan.ContextFile, forkLines); err != nil {
+			return err
+		}
+	}
+	if err := t.NewSessionWithCommand(fork, workDir, command); err != nil {
+		return fmt.Errorf("starting fork: %w", err)
+	}
+	for k, v := range forkEnv {
+		_ = t.SetEnvironment(fork, k, v)
+	}
+	_ = transcript.Enable(t, fork)
+
+	if plan.Prompt != "" {
+		if err := t.WaitForRuntimeReady(fork, rc, constants.ClaudeStartTimeout); err != nil {
+			style.PrintWarning("%s did not become ready; send it the context yourself: %s", fork, plan.Prompt)
+		} else if err := t.NudgeSession(fork, plan.Prompt); err != nil {
+			return fmt.Errorf("sending fork context: %w", err)
+		}
+	}
+
+	fmt.Printf("%s Forked %s → %s (%s)\n", style.Success.Render("✓"), source, fork, plan.Mode)
+	fmt.Printf("  Attach with: tmux attach -t %s\n", fork)
+	return nil
+}
+
+// planFork decides how to fork source into fork: natively when the agent
+// can fork and the source's session ID is known, else from its transcript.
+func planFork(source, fork, agent, sessionID string, rc *config.RuntimeConfig, workDir string) (*ForkPlan, error) {
+	plan := &ForkPlan{Source: source, Session: fork, Agent: agent}
+	if preset := config.GetAgentPresetByName(agent); preset.Supports(config.CapFork) && sessionID != "" {
+		command, err := config.BuildForkCommand(agent, sessionID)
+		if err != nil {
+			return nil, err
+		}
+		plan.Mode, plan.Command = forkNative, command
+		return plan, nil
+	}
+
+	if !transcript.Exists(source) {
+		return nil, fmt.Errorf("cannot fork %s: %s can't fork this session and there is no transcript to copy", source, agent)
+	}
+	plan.Mode = forkTranscript
+	plan.Command = rc.BuildCommand()
+	plan.ContextFile = filepath.Join(workDir, ".runtime", "fork-"+fork+".md")
+	plan.Prompt = fmt.Sprintf("You are a fork of session %s. Read %s for the end of its transcript, then carry on the investigation from there on your own; the original session keeps running.",
+		source, plan.ContextFile)
+	return plan, nil
+}
+
+// forkSessionID finds the source's agent session ID: the preset's session
+// ID variable in the tmux environment, else the ID gt prime persisted.
+func forkSessionID(agent string, env map[string]string, workDir string) string {
+	if preset := config.GetAgentPresetByName(agent); preset != nil && preset.SessionIDEnv != "" {
+		if id := env[preset.SessionIDEnv]; id != "" {
+			return id
+		}
+	}
+	return readSessionFile(workDir)
+}
+
+// nextForkSessionName returns the first of <source>-fork, <source>-fork-2,
+// ... that isn't a running session.
+func nextForkSessionName(source string, exists func(string) bool) string {
+	name := source + "-fork"
+	for n := 2; exists(name); n++ {
+		name = fmt.Sprintf("%s-fork-%d", source, n)
+	}
+	return name
+}
+
+// writeForkContext writes the last n transcript lines of source to path.
+func writeForkContext(source, path string, n int) error {
+	files, err := transcript.Files(source)
+	if err != nil {
+		return err
+	}
+	tail, err := transcriptTail(files, n)
+	if err != nil {
+		return err
+	}
+	content := fmt.Sprintf("# Transcript of %s (last %d lines)\n\n```\n%s\n```\n", source, n, strings.TrimRight(transcript.StripANSI(tail), "\n"))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("writing fork context: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return fmt.Errorf("writing fork context: %w", err)
+	}
+	return nil
+}
+
+func printForkPlan(plan *ForkPlan, workDir string) {
+	fmt.Printf("%s\n", style.Bold.Render("Fork plan"))
+	fmt.Printf("  Source:      %s\n", plan.Source)
+	fmt.Printf("  Fork:        %s\n", plan.Session)
+	fmt.Printf("  Agent:       %s\n", plan.Agent)
+	fmt.Printf("  Mode:        %s\n", plan.Mode)
+	fmt.Printf("  Working dir: %s\n", workDir)
+	fmt.Printf("  Command:     %s\n", plan.Command)
+	if plan.ContextFile != "" {
+		fmt.Printf("  Context:     %s\n", plan.ContextFile)
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+func TestPlanForkNative(t *testing.T) {
+	t.Setenv(transcript.EnvDir, t.TempDir())
+	rc := config.RuntimeConfigFromPreset(config.AgentClaude)
+
+	plan, err := planFork("gt-web-crew-jane", "gt-web-crew-jane-fork", "claude", "sess-123", rc, "/town/web/crew/jane")
+	if err != nil {
+		t.Fatalf("planFork: %v", err)
+	}
+	if plan.Mode != forkNative || !strings.Contains(plan.Command, "--resume sess-123 --fork-session") {
+		t.Errorf("plan = %+v, want a native --fork-session resume", plan)
+	}
+	if plan.Prompt != "" || plan.ContextFile != "" {
+		t.Errorf("native fork should carry no context: %+v", plan)
+	}
+}
+
+func TestPlanForkTranscriptFallback(t *testing.T) {
+	t.Setenv(transcript.EnvDir, t.TempDir())
+	workDir := t.TempDir()
+	rc := config.RuntimeConfigFromPreset(config.AgentKimi)
+
+	// Kimi can't fork, and without a transcript there is nothing to carry over
+	if _, err := planFork("gt-web-crew-jane", "gt-web-crew-jane-fork", "kimi", "sess-123", rc, workDir); err == nil {
+		t.Fatal("planFork succeeded without fork support or a transcript")
+	}
+
+	w, err := transcript.NewWriter(transcript.SessionDir("gt-web-crew-jane"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("\x1b[1mstep one\x1b[0m\r\nstep two\nstep three\n"))
+	_ = w.Close()
+
+	plan, err := planFork("gt-web-crew-jane", "gt-web-crew-jane-fork", "kimi", "sess-123", rc, workDir)
+	if err != nil {
+		t.Fatalf("planFork: %v", err)
+	}
+	if plan.Mode != forkTranscript || plan.Command != rc.BuildCommand() {
+		t.Errorf("plan = %+v, want a fresh kimi from the transcript", plan)
+	}
+	if !strings.Contains(plan.Prompt, plan.ContextFile) {
+		t.Errorf("prompt %q doesn't point at %s", plan.Prompt, plan.ContextFile)
+	}
+
+	if err := writeForkContext("gt-web-crew-jane", plan.ContextFile, 2); err != nil {
+		t.Fatalf("writeForkContext: %v", err)
+	}
+	data, err := os.ReadFile(plan.ContextFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "step two\nstep three\n```") || strings.Contains(got, "step one") {
+		t.Errorf("fork context = %q, want the last 2 lines", got)
+	}
+	if filepath.Dir(plan.ContextFile) != filepath.Join(workDir, ".runtime") {
+		t.Errorf("context file %s not under .runtime", plan.ContextFile)
+	}
+}
+
+func TestNextForkSessionName(t *testing.T) {
+	running := map[string]bool{"hq-mayor-fork": true, "hq-mayor-fork-2": true}
+	if got := nextForkSessionName("hq-mayor", func(s string) bool { return running[s] }); got != "hq-mayor-fork-3" {
+		t.Errorf("nextForkSessionName() = %q, want hq-mayor-fork-3", got)
+	}
+	if got := nextForkSessionName("gt-web-witness", func(string) bool { return false }); got != "gt-web-witness-fork" {
+		t.Errorf("nextForkSessionName() = %q, want gt-web-witness-fork", got)
+	}
+}
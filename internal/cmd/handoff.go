@@ -1,20 +1,32 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
+	gtlog "github.com/steveyegge/gastown/internal/log"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/suggest"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -31,7 +43,16 @@ This is the canonical way to end any agent session. It handles all roles:
 
 When run without arguments, hands off the current session.
 When given a bead ID (gt-xxx, hq-xxx), hooks that work first, then restarts.
-When given a role name, hands off that role's session (and switches to it).
+When given a role name, hands off that role's session. Your view stays where
+it is unless --watch is given, which switches your client to the target, or
+attaches this terminal to it when run outside tmux (--no-switch overrides
+--watch, e.g. in aliases). A self handoff never switches. Handing off another
+role (or --all) works outside tmux; a self handoff needs to run inside it.
+
+With another backend (GT_MULTIPLEXER=zellij, or wt for Windows Terminal,
+the default on Windows without tmux) the session is respawned through that
+backend, and a self handoff finds its session from GT_SESSION. --all and
+--wait need tmux.
 
 Examples:
   gt handoff                          # Hand off current session
@@ -39,36 +60,196 @@ Examples:
   gt handoff gt-abc -s "Fix it"       # Hook with context, then restart
   gt handoff -s "Context" -m "Notes"  # Hand off with custom message
   gt handoff -c                       # Collect state into handoff message
+  gt handoff --with-context           # Carry a summary into the new session
   gt handoff crew                     # Hand off crew session
   gt handoff mayor                    # Hand off mayor session
+  gt handoff mayor --watch            # ...and switch to it
+  gt handoff --kill witness           # Kill witness session (no respawn)
+  gt handoff my-session --restart-command "exec my-agent"
+  gt handoff --all --parallel 8       # Hand off every agent session
+  gt handoff --all --role crew --rig gastown  # ...or just gastown's crew
+  gt handoff --reason "context full"  # Record why, for postmortems
+  gt handoff --history witness        # Show witness handoff timeline
+  gt handoff witness --wait           # Return once the new witness is ready
+  gt handoff --plan witness --json    # Resolve the handoff offline (for CI)
+  gt handoff witness --schedule 6h    # Hand off the witness every 6 hours
+  gt handoff mayor --schedule "0 4 * * *"  # ...or daily at 04:00
 
 The --collect (-c) flag gathers current state (hooked work, inbox, ready beads,
 in-progress items) and includes it in the handoff mail. This provides context
 for the next session without manual summarization.
 
+A session cannot be handed off again within the cooldown window (default 10s,
+configurable via handoff.cooldown in settings/config.json). This guards against
+runaway scripts. Use --force to bypass the cooldown.
+
+Sessions that don't follow a Gas Town naming pattern can be handed off by
+supplying --restart-command, which is run verbatim in the respawned pane.
+
+Custom roles defined under "roles" in settings/config.json are handed off by
+name like built-in ones (gt handoff auditor, gt handoff gastown/auditor):
+
+  "roles": {
+    "auditor":   {"session": "gt-{rig}-auditor", "aliases": ["aud"]},
+    "librarian": {"session": "hq-librarian", "agent": "gemini",
+                  "restart_command": "librarian-agent"}
+  }
+
+A role works from work_dir (default {town}/{rig}/<role>, or {town}/<role>
+without {rig}); restart_command, if set, replaces its agent command.
+
+Each handoff is recorded in the town log with its restart command and the
+optional --reason note. Use --history to print that timeline.
+
+The --all flag hands off every running agent session (mayor, deacon,
+witnesses, refineries, crew) except polecats and the current session;
+--role and --rig narrow it to the sessions of one role or rig. Sessions are
+handed off on a rolling basis, --parallel at a time (default 4), so the
+fleet never restarts all at once. Each session is reported as it finishes,
+and a summary lists the sessions that failed to respawn.
+
+The --wait flag blocks after respawning until the new agent shows its ready
+prompt (the agent's tmux.ready_prompt_prefix, or --ready-marker), polling the
+pane until --wait-timeout (default 2m) elapses. Agents without a ready prompt
+are given their fixed startup delay instead. --wait applies to remote and
+--all handoffs; a self handoff replaces the calling process. Once the agent is
+ready, its priming_prompt (if configured) is typed into the new session, or
+the session's prompt template from .gastown/prompts/ (see gt prompt render).
+
+The --plan flag resolves everything a handoff of the target would use (role,
+session, working directory, agent, resume support, restart command) and prints
+it without touching tmux, so it works in CI with no tmux server. Unlike
+--dry-run it never inspects the live session. A target is required unless
+GT_ROLE is set. Add --json for machine-readable output.
+
+The --resume-flag flag is for an agent CLI that resumes with a different flag
+than its preset (e.g., a renamed --continue). The new session keeps it
+(GT_RESUME_FLAG) and records it in the session manifest, so gt resume --all
+and the watchdog resume that session's conversation with it. --plan shows it.
+
+The --with-context flag carries the outgoing agent's summary into the new
+session, so it doesn't start cold. The summary is what the handoff-context
+hook scripts print (see gt hooks events), or else the last 200 lines of the
+session's pane. It is written to .runtime/handoff/<session>.md in the town
+and added to the new session's startup prompt (its last 4000 bytes, if
+longer). Other backends than tmux can only use the hooks.
+
+The --schedule flag records a standing handoff of the target (or the current
+session) instead of handing it off now, so long watches restart before their
+context window fills: a duration ("6h") hands off every that long, a cron
+expression (minute hour day month weekday, local time) at those times. The
+watchdog (gt watchdog start) runs due handoffs while the agent is alive,
+with --reason "scheduled (<spec>)"; a handoff missed while the watchdog or
+session was down runs once when they're back. Schedules are kept in
+daemon/handoff-schedules.json across restarts. --unschedule removes the
+target's schedule and --schedules lists them all.
+
+The --kill flag tears down the target session instead of respawning it.
+It asks for confirmation unless --force is given.
+
+When handoff kills agent processes it sends SIGTERM, waits a grace period,
+then sends SIGKILL. The grace period defaults to 5s and can be set via
+handoff.grace_timeout in settings/config.json or per invocation with --grace.
+
 Any molecule on the hook will be auto-continued by the new session.
-The SessionStart hook runs 'gt prime' to restore context.`,
-	RunE: runHandoff,
+The SessionStart hook runs 'gt prime' to restore context.
+
+Exit codes (for scripting):
+  0  success
+  1  other failure
+  2  not running in tmux (required to hand off the current session)
+  3  target session not found (or tmux server not running)
+  4  respawning the pane failed
+  5  the new session did not become ready within --wait-timeout`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withHandoffExitCode(runHandoff(cmd, args))
+	},
 }
 
 var (
-	handoffWatch   bool
-	handoffDryRun  bool
-	handoffSubject string
-	handoffMessage string
-	handoffCollect bool
+	handoffWatch    bool
+	handoffNoSwitch bool
+	handoffDryRun   bool
+	handoffSubject  string
+	handoffMessage  string
+	handoffCollect  bool
+	handoffForce    bool
+	handoffKill     bool
+	handoffGrace    time.Duration
+	handoffRestart  string
+	handoffAll      bool
+	handoffAllRole  string
+	handoffAllRig   string
+	handoffParallel int
+	handoffReason   string
+	handoffHistory  bool
+	handoffWait     bool
+	handoffWaitFor  time.Duration
+	handoffMarker   string
+	handoffPlan     bool
+	handoffJSON     bool
+	handoffResume   string
+
+	handoffWithContext bool
+
+	handoffSchedule   string
+	handoffUnschedule bool
+	handoffSchedules  bool
 )
 
 func init() {
-	handoffCmd.Flags().BoolVarP(&handoffWatch, "watch", "w", true, "Switch to new session (for remote handoff)")
+	handoffCmd.Flags().BoolVarP(&handoffWatch, "watch", "w", false, "Switch to the target session after a remote handoff")
+	handoffCmd.Flags().BoolVar(&handoffNoSwitch, "no-switch", false, "Never switch to the target session (overrides --watch)")
 	handoffCmd.Flags().BoolVarP(&handoffDryRun, "dry-run", "n", false, "Show what would be done without executing")
 	handoffCmd.Flags().StringVarP(&handoffSubject, "subject", "s", "", "Subject for handoff mail (optional)")
 	handoffCmd.Flags().StringVarP(&handoffMessage, "message", "m", "", "Message body for handoff mail (optional)")
 	handoffCmd.Flags().BoolVarP(&handoffCollect, "collect", "c", false, "Auto-collect state (status, inbox, beads) into handoff message")
+	handoffCmd.Flags().BoolVarP(&handoffForce, "force", "f", false, "Bypass the handoff cooldown (and the --kill confirmation)")
+	handoffCmd.Flags().BoolVar(&handoffKill, "kill", false, "Kill the target session instead of respawning it")
+	handoffCmd.Flags().StringVar(&handoffRestart, "restart-command", "", "Command to respawn the pane with (skips role detection; for custom sessions)")
+	handoffCmd.Flags().DurationVar(&handoffGrace, "grace", 0, "Wait this long after SIGTERM before SIGKILL (overrides handoff.grace_timeout)")
+	handoffCmd.Flags().BoolVar(&handoffAll, "all", false, "Hand off every agent session except polecats and the current one")
+	handoffCmd.Flags().StringVar(&handoffAllRole, "role", "", "With --all, only hand off sessions of this role (crew, witness, ...)")
+	handoffCmd.Flags().StringVar(&handoffAllRig, "rig", "", "With --all, only hand off sessions of this rig")
+	handoffCmd.Flags().IntVar(&handoffParallel, "parallel", defaultHandoffParallel, "Number of sessions --all hands off concurrently")
+	handoffCmd.Flags().StringVar(&handoffReason, "reason", "", "Why this handoff happened (recorded in the handoff history)")
+	handoffCmd.Flags().BoolVar(&handoffHistory, "history", false, "Show recorded handoffs (optionally for one role) and exit")
+	handoffCmd.Flags().BoolVar(&handoffPlan, "plan", false, "Resolve and print the handoff plan without touching tmux")
+	handoffCmd.Flags().BoolVar(&handoffJSON, "json", false, "Output --plan as JSON")
+	handoffCmd.Flags().StringVar(&handoffResume, "resume-flag", "", "Resume flag the agent CLI takes (overrides the preset's for the new session's resumes)")
+	handoffCmd.Flags().BoolVar(&handoffWait, "wait", false, "Wait for the respawned agent to show its ready prompt")
+	handoffCmd.Flags().DurationVar(&handoffWaitFor, "wait-timeout", defaultHandoffWaitTimeout, "Give up on --wait after this long")
+	handoffCmd.Flags().StringVar(&handoffMarker, "ready-marker", "", "Pane line prefix that marks the agent ready (overrides the agent's ready prompt)")
+	handoffCmd.Flags().BoolVar(&handoffWithContext, "with-context", false, "Carry the outgoing agent's summary into the new session's prompt")
+	handoffCmd.Flags().StringVar(&handoffSchedule, "schedule", "", "Hand the target off automatically every interval (4h) or at cron times (\"0 */6 * * *\")")
+	handoffCmd.Flags().BoolVar(&handoffUnschedule, "unschedule", false, "Remove the target's handoff schedule")
+	handoffCmd.Flags().BoolVar(&handoffSchedules, "schedules", false, "List handoff schedules and exit")
 	rootCmd.AddCommand(handoffCmd)
 }
 
 func runHandoff(cmd *cobra.Command, args []string) error {
+	if handoffHistory {
+		return runHandoffHistory(args)
+	}
+	if handoffPlan {
+		return runHandoffPlan(args)
+	}
+	if handoffSchedules {
+		return runHandoffSchedules()
+	}
+	if handoffSchedule != "" || handoffUnschedule {
+		return runHandoffSchedule(args)
+	}
+	if handoffJSON {
+		return fmt.Errorf("--json requires --plan")
+	}
+	if (handoffAllRole != "" || handoffAllRig != "") && !handoffAll {
+		return fmt.Errorf("--role and --rig require --all")
+	}
+	if handoffAllRole == string(session.RolePolecat) {
+		return fmt.Errorf("--all never hands off polecats: their witness manages them")
+	}
+
 	// Check if we're a polecat - polecats use gt done instead
 	// GT_POLECAT is set by the session manager when starting polecat sessions
 	if polecatName := os.Getenv("GT_POLECAT"); polecatName != "" {
@@ -95,22 +276,57 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	t := tmux.NewTmux()
-
-	// Verify we're in tmux
-	if !tmux.IsInsideTmux() {
-		return fmt.Errorf("not running in tmux - cannot hand off")
+	if handoffWait && handoffWaitFor <= 0 {
+		return fmt.Errorf("--wait-timeout must be positive")
 	}
 
-	pane := os.Getenv("TMUX_PANE")
-	if pane == "" {
-		return fmt.Errorf("TMUX_PANE not set - cannot hand off")
+	tmuxOpts := []tmux.Option{tmux.WithKillGracePeriod(GraceTimeout())}
+	if handoffDryRun {
+		// Dry run: tmux mutations print what they would do instead of running
+		tmuxOpts = append(tmuxOpts, tmux.WithDryRun(os.Stdout))
 	}
+	t := tmux.NewTmux(tmuxOpts...)
 
-	// Get current session name
-	currentSession, err := getCurrentTmuxSession()
+	mux, err := tmux.NewMultiplexer(tmuxOpts...)
 	if err != nil {
-		return fmt.Errorf("getting session name: %w", err)
+		return err
+	}
+	if _, isTmux := mux.(*tmux.Tmux); !isTmux {
+		return handoffMultiplexer(mux, args)
+	}
+
+	// A self handoff respawns our own pane, so it needs tmux; handing off
+	// another session (or all of them) also works from a plain terminal.
+	var pane, currentSession string
+	if tmux.IsInsideTmux() {
+		pane = os.Getenv("TMUX_PANE")
+		if pane == "" {
+			return fmt.Errorf("%w (TMUX_PANE not set)", ErrNotInTmux)
+		}
+
+		// Get current session name
+		currentSession, err = getCurrentTmuxSession()
+		if err != nil {
+			return fmt.Errorf("getting session name: %w", err)
+		}
+	} else if !handoffAll && (len(args) == 0 || looksLikeBeadID(args[0])) {
+		return ErrNotInTmux
+	}
+
+	// Hand off every agent session via a bounded worker pool
+	if handoffAll {
+		if len(args) > 0 || handoffKill {
+			return fmt.Errorf("--all cannot be combined with a target or --kill")
+		}
+		sessionTmux := func(w io.Writer) *tmux.Tmux {
+			opts := []tmux.Option{tmux.WithKillGracePeriod(GraceTimeout())}
+			if handoffDryRun {
+				opts = append(opts, tmux.WithDryRun(w))
+			}
+			return tmux.NewTmux(opts...)
+		}
+		filter := handoffAllFilter{role: handoffAllRole, rig: handoffAllRig}
+		return handoffAllSessions(t, currentSession, filter, handoffParallel, sessionTmux, newHandoffWait())
 	}
 
 	// Determine target session and check for bead hook
@@ -137,49 +353,58 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Kill mode - tear down the session instead of respawning it
+	if handoffKill {
+		return handoffKillSession(t, targetSession)
+	}
+
+	if handoffWait && targetSession == currentSession {
+		return fmt.Errorf("--wait needs another session to hand off: a self handoff replaces this process")
+	}
+
+	if err := checkHandoffRate(targetSession); err != nil {
+		return err
+	}
+
 	// Build the restart command
-	restartCmd, err := buildRestartCommand(targetSession)
+	restartCmd, err := handoffRestartCommand(targetSession, t.CapturePane)
 	if err != nil {
 		return err
 	}
 
 	// If handing off a different session, we need to find its pane and respawn there
 	if targetSession != currentSession {
-		return handoffRemoteSession(t, targetSession, restartCmd)
+		return handoffRemoteSession(t, targetSession, restartCmd, newHandoffWait(), handoffShouldSwitch())
 	}
 
-	// Handing off ourselves - print feedback then respawn
-	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), currentSession)
-
-	// Log handoff event (both townlog and events feed)
-	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
-		agent := sessionToGTRole(currentSession)
-		if agent == "" {
-			agent = currentSession
-		}
-		_ = LogHandoff(townRoot, agent, handoffSubject)
-		// Also log to activity feed
-		_ = events.LogFeed(events.TypeHandoff, agent, events.HandoffPayload(handoffSubject, true))
+	// Handing off ourselves - make sure TMUX_PANE really is our pane
+	pane, err = selfHandoffPane(t, pane, currentSession)
+	if err != nil {
+		return err
 	}
 
-	// Dry run mode - show what would happen (BEFORE any side effects)
-	if handoffDryRun {
+	// Print feedback then respawn
+	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), currentSession)
+
+	// Non-tmux side effects are skipped in dry run; tmux ops below report
+	// themselves via the dry-run Tmux.
+	if t.DryRun() {
 		if handoffSubject != "" || handoffMessage != "" {
 			fmt.Printf("Would send handoff mail: subject=%q (auto-hooked)\n", handoffSubject)
 		}
-		fmt.Printf("Would execute: tmux clear-history -t %s\n", pane)
-		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", pane, restartCmd)
-		return nil
-	}
-
-	// Send handoff mail to self (defaults applied inside sendHandoffMail).
-	// The mail is auto-hooked so the next session picks it up.
-	beadID, err := sendHandoffMail(handoffSubject, handoffMessage)
-	if err != nil {
-		style.PrintWarning("could not send handoff mail: %v", err)
-		// Continue anyway - the respawn is more important
 	} else {
-		fmt.Printf("%s Sent handoff mail %s (auto-hooked)\n", style.Bold.Render("📬"), beadID)
+		// Log handoff event (both townlog and events feed)
+		logHandoffEvent(currentSession, restartCmd, true)
+
+		// Send handoff mail to self (defaults applied inside sendHandoffMail).
+		// The mail is auto-hooked so the next session picks it up.
+		beadID, err := sendHandoffMail(handoffSubject, handoffMessage)
+		if err != nil {
+			style.PrintWarning("could not send handoff mail: %v", err)
+			// Continue anyway - the respawn is more important
+		} else {
+			fmt.Printf("%s Sent handoff mail %s (auto-hooked)\n", style.Bold.Render("📬"), beadID)
+		}
 	}
 
 	// NOTE: reportAgentState("stopped") removed (gt-zecmc)
@@ -195,11 +420,8 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 	// Write handoff marker for successor detection (prevents handoff loop bug).
 	// The marker is cleared by gt prime after it outputs the warning.
 	// This tells the new session "you're post-handoff, don't re-run /handoff"
-	if cwd, err := os.Getwd(); err == nil {
-		runtimeDir := filepath.Join(cwd, constants.DirRuntime)
-		_ = os.MkdirAll(runtimeDir, 0755)
-		markerPath := filepath.Join(runtimeDir, constants.FileHandoffMarker)
-		_ = os.WriteFile(markerPath, []byte(currentSession), 0644)
+	if !t.DryRun() {
+		writeHandoffMarker(currentSession)
 	}
 
 	// Set remain-on-exit so the pane survives process death during handoff.
@@ -219,39 +441,468 @@ func runHandoff(cmd *cobra.Command, args []string) error {
 	// If orphans still occur, the solution is to adjust the restart command to
 	// kill orphans at startup, not to kill ourselves before respawning.
 
+	// Logged up front: a successful respawn kills this process
+	gtlog.L().Info("handoff respawning self", "session", currentSession, "pane", pane, "restart_cmd", restartCmd)
+
 	// Check if pane's working directory exists (may have been deleted)
 	paneWorkDir, _ := t.GetPaneWorkDir(currentSession)
 	if paneWorkDir != "" {
 		if _, err := os.Stat(paneWorkDir); err != nil {
 			if townRoot := detectTownRootFromCwd(); townRoot != "" {
 				style.PrintWarning("pane working directory deleted, using town root")
-				return t.RespawnPaneWithWorkDir(pane, townRoot, restartCmd)
+				if err := t.RespawnPaneWithWorkDir(pane, townRoot, restartCmd); err != nil {
+					gtlog.L().Error("handoff respawn failed", "session", currentSession, "pane", pane, "workdir", townRoot, "err", err)
+					return fmt.Errorf("%w: %w", ErrRespawnFailed, err)
+				}
+				return nil
 			}
 		}
 	}
 
 	// Use respawn-pane -k to atomically kill current process and start new one
 	// Note: respawn-pane automatically resets remain-on-exit to off
-	return t.RespawnPane(pane, restartCmd)
+	if err := t.RespawnPane(pane, restartCmd); err != nil {
+		gtlog.L().Error("handoff respawn failed", "session", currentSession, "pane", pane, "err", err)
+		return fmt.Errorf("%w: %w", ErrRespawnFailed, err)
+	}
+	return nil
 }
 
-// getCurrentTmuxSession returns the current tmux session name.
-func getCurrentTmuxSession() (string, error) {
-	out, err := exec.Command("tmux", "display-message", "-p", "#{session_name}").Output()
+// checkHandoffRate rejects rapid-fire handoffs of the same session (runaway
+// script guard) unless --force is set.
+func checkHandoffRate(targetSession string) error {
+	if handoffForce {
+		return nil
+	}
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return nil
+	}
+	events, _ := townlog.ReadEvents(townRoot)
+	return checkHandoffCooldown(events, handoffAgentName(targetSession), loadHandoffCooldown(townRoot), time.Now())
+}
+
+// writeHandoffMarker writes the handoff marker for successor detection
+// (prevents the handoff loop bug). gt prime clears it after it outputs the
+// warning; it tells the new session "you're post-handoff, don't re-run
+// /handoff".
+func writeHandoffMarker(session string) {
+	cwd, err := os.Getwd()
 	if err != nil {
-		return "", err
+		return
 	}
-	return strings.TrimSpace(string(out)), nil
+	runtimeDir := filepath.Join(cwd, constants.DirRuntime)
+	_ = os.MkdirAll(runtimeDir, 0755)
+	_ = os.WriteFile(filepath.Join(runtimeDir, constants.FileHandoffMarker), []byte(session), 0644)
+}
+
+// handoffMultiplexer is gt handoff for a non-tmux backend (GT_MULTIPLEXER).
+// Those backends respawn whole sessions rather than panes and can't capture
+// them, so --all and --wait are tmux-only. The current session comes from
+// GT_SESSION (set by the Windows Terminal backend) or ZELLIJ_SESSION_NAME.
+func handoffMultiplexer(mux tmux.Multiplexer, args []string) error {
+	if handoffAll || handoffWait {
+		return fmt.Errorf("--all and --wait need tmux (%s is set)", tmux.EnvMultiplexer)
+	}
+	sessionExists := func(name string) bool {
+		exists, err := mux.HasSession(name)
+		return err == nil && exists
+	}
+
+	currentSession := os.Getenv("GT_SESSION")
+	if currentSession == "" {
+		currentSession = os.Getenv("ZELLIJ_SESSION_NAME")
+	}
+	targetSession := currentSession
+	if len(args) > 0 {
+		if looksLikeBeadID(args[0]) {
+			if err := hookBeadForHandoff(args[0]); err != nil {
+				return fmt.Errorf("hooking bead: %w", err)
+			}
+			if handoffSubject == "" {
+				handoffSubject = fmt.Sprintf("🪝 HOOKED: %s", args[0])
+			}
+		} else {
+			var err error
+			if targetSession, err = resolveRoleToSessionWith(args[0], sessionExists); err != nil {
+				return fmt.Errorf("resolving role: %w", err)
+			}
+		}
+	}
+	if targetSession == "" {
+		return fmt.Errorf("%w (GT_SESSION not set)", ErrNotInTmux)
+	}
+	if !sessionExists(targetSession) {
+		return fmt.Errorf("%w: '%s' - is the agent running?", tmux.ErrSessionNotFound, targetSession)
+	}
+
+	if handoffKill {
+		if !handoffForce && !handoffDryRun && !promptYesNo(fmt.Sprintf("Kill session %s?", targetSession)) {
+			fmt.Println("Aborted.")
+			return nil
+		}
+		if townRoot := detectTownRootFromCwd(); townRoot != "" && !handoffDryRun {
+			_ = LogKill(townRoot, handoffAgentName(targetSession), "gt handoff --kill")
+		}
+		fmt.Printf("%s Killing %s...\n", style.Bold.Render("💀"), targetSession)
+		return mux.KillSession(targetSession)
+	}
+
+	if err := checkHandoffRate(targetSession); err != nil {
+		return err
+	}
+	restartCmd, err := handoffRestartCommand(targetSession, nil) // Only tmux panes can be captured
+	if err != nil {
+		return err
+	}
+
+	self := targetSession == currentSession
+	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), targetSession)
+	if handoffDryRun {
+		if self && (handoffSubject != "" || handoffMessage != "") {
+			fmt.Printf("Would send handoff mail: subject=%q (auto-hooked)\n", handoffSubject)
+		}
+	} else {
+		logHandoffEvent(targetSession, restartCmd, self)
+		if self {
+			if beadID, err := sendHandoffMail(handoffSubject, handoffMessage); err != nil {
+				style.PrintWarning("could not send handoff mail: %v", err)
+			} else {
+				fmt.Printf("%s Sent handoff mail %s (auto-hooked)\n", style.Bold.Render("📬"), beadID)
+			}
+			writeHandoffMarker(currentSession)
+		}
+	}
+
+	if err := mux.RespawnPane(targetSession, restartCmd); err != nil {
+		return fmt.Errorf("%w: %w", ErrRespawnFailed, err)
+	}
+	return nil
+}
+
+// Handoff failure modes with a distinct exit code (see withHandoffExitCode).
+// A missing target session is reported with tmux.ErrSessionNotFound or
+// tmux.ErrNoServer.
+var (
+	ErrNotInTmux     = errors.New("not running in tmux - cannot hand off")
+	ErrRespawnFailed = errors.New("respawning pane")
+	ErrNotReady      = errors.New("new session not ready")
+)
+
+// Handoff exit codes, documented in the command help.
+const (
+	handoffExitNotInTmux       = 2
+	handoffExitSessionNotFound = 3
+	handoffExitRespawnFailed   = 4
+	handoffExitNotReady        = 5
+)
+
+// withHandoffExitCode attaches the documented exit code to a handoff error,
+// keeping its message. Errors without a distinct mode pass through (exit 1).
+func withHandoffExitCode(err error) error {
+	var code int
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrNotInTmux):
+		code = handoffExitNotInTmux
+	case errors.Is(err, tmux.ErrSessionNotFound), errors.Is(err, tmux.ErrNoServer):
+		code = handoffExitSessionNotFound
+	case errors.Is(err, ErrRespawnFailed):
+		code = handoffExitRespawnFailed
+	case errors.Is(err, ErrNotReady):
+		code = handoffExitNotReady
+	default:
+		return err
+	}
+	return &ExitCodeError{Code: code, Err: err}
+}
+
+// handoffAgentName returns the identity recorded in the town log for a session's handoffs.
+func handoffAgentName(sessionName string) string {
+	if agent := sessionToGTRole(sessionName); agent != "" {
+		return agent
+	}
+	return sessionName
+}
+
+// logHandoffEvent records a handoff in the town log and the activity feed.
+// The town log entries double as the handoff history (--history) and the
+// history used by the cooldown check.
+func logHandoffEvent(sessionName, restartCmd string, self bool) {
+	townRoot, err := workspace.FindFromCwd()
+	if err != nil || townRoot == "" {
+		return
+	}
+	agent := handoffAgentName(sessionName)
+	_ = LogHandoff(townRoot, agent, handoffHistoryContext(handoffSubject, handoffReason, restartCmd))
+	// Also log to activity feed
+	_ = events.LogFeed(events.TypeHandoff, agent, events.HandoffPayload(handoffSubject, self))
+	hooks.Fire(townRoot, hooks.Handoff, map[string]string{
+		"agent":   agent,
+		"session": sessionName,
+		"subject": handoffSubject,
+		"reason":  handoffReason,
+		"self":    strconv.FormatBool(self),
+	})
+	notifyHandoff(townRoot, agent, sessionName, handoffSubject, handoffReason)
+}
+
+// Field labels in a handoff history entry's town log context.
+const (
+	handoffReasonLabel  = "reason: "
+	handoffCommandLabel = "cmd: "
+)
+
+// handoffHistoryContext builds the town log context for a handoff:
+// "<subject>; reason: <reason>; cmd: <restart command>", omitting empty
+// fields. Fields are flattened to a single line so each entry stays one
+// log line (restart commands embed a multi-line startup beacon).
+func handoffHistoryContext(subject, reason, restartCmd string) string {
+	var parts []string
+	if subject = singleLine(subject); subject != "" {
+		parts = append(parts, subject)
+	}
+	if reason = singleLine(reason); reason != "" {
+		parts = append(parts, handoffReasonLabel+reason)
+	}
+	if restartCmd = singleLine(restartCmd); restartCmd != "" {
+		parts = append(parts, handoffCommandLabel+restartCmd)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// parseHandoffHistoryContext splits a context written by handoffHistoryContext.
+// Entries logged before reasons were recorded parse as a bare subject.
+func parseHandoffHistoryContext(context string) (subject, reason, restartCmd string) {
+	if i := labelIndex(context, handoffCommandLabel); i >= 0 {
+		context, restartCmd = strings.TrimSuffix(context[:i], "; "), context[i+len(handoffCommandLabel):]
+	}
+	if i := labelIndex(context, handoffReasonLabel); i >= 0 {
+		context, reason = strings.TrimSuffix(context[:i], "; "), context[i+len(handoffReasonLabel):]
+	}
+	return context, reason, restartCmd
+}
+
+// labelIndex returns the position of a field label that starts s or
+// follows a "; " separator, or -1.
+func labelIndex(s, label string) int {
+	if strings.HasPrefix(s, label) {
+		return 0
+	}
+	if i := strings.Index(s, "; "+label); i >= 0 {
+		return i + 2
+	}
+	return -1
+}
+
+// singleLine collapses newlines and surrounding whitespace into single spaces.
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// formatHandoffHistoryEntry renders one handoff event for --history.
+func formatHandoffHistoryEntry(e townlog.Event) string {
+	subject, reason, restartCmd := parseHandoffHistoryContext(e.Context)
+	line := fmt.Sprintf("%s  %s", e.Timestamp.Format("2006-01-02 15:04:05"), e.Agent)
+	if reason != "" {
+		line += "  reason: " + reason
+	}
+	if subject != "" {
+		line += fmt.Sprintf("  subject: %q", subject)
+	}
+	if restartCmd != "" {
+		line += "\n    " + restartCmd
+	}
+	return line
+}
+
+// runHandoffHistory prints the town's handoff history, oldest first,
+// limited to one role's session when a role is given.
+func runHandoffHistory(args []string) error {
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return fmt.Errorf("cannot detect town root - run from within a Gas Town workspace")
+	}
+
+	var agent string
+	if len(args) > 0 {
+		target, err := resolveRoleToSession(args[0])
+		if err != nil {
+			return fmt.Errorf("resolving role: %w", err)
+		}
+		agent = handoffAgentName(target)
+	}
+
+	all, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		return err
+	}
+	var count int
+	for _, e := range all {
+		if e.Type != townlog.EventHandoff || (agent != "" && e.Agent != agent) {
+			continue
+		}
+		fmt.Println(formatHandoffHistoryEntry(e))
+		count++
+	}
+	if count == 0 {
+		fmt.Println(style.Dim.Render("No handoffs recorded"))
+	}
+	return nil
+}
+
+// loadHandoffConfig returns the handoff section of a town's settings.
+// Returns nil if the settings can't be loaded; HandoffConfig getters are nil-safe.
+func loadHandoffConfig(townRoot string) *config.HandoffConfig {
+	if townRoot == "" {
+		return nil
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil
+	}
+	return settings.Handoff
+}
+
+// loadHandoffCooldown returns the configured handoff cooldown for a town.
+func loadHandoffCooldown(townRoot string) time.Duration {
+	return loadHandoffConfig(townRoot).GetCooldown()
+}
+
+var (
+	graceTimeoutOnce       sync.Once
+	graceTimeoutConfigured time.Duration
+)
+
+// GraceTimeout returns how long handoff waits after SIGTERM before sending
+// SIGKILL. The --grace flag wins over handoff.grace_timeout in town settings,
+// which wins over config.DefaultHandoffGraceTimeout. Settings are read once.
+func GraceTimeout() time.Duration {
+	graceTimeoutOnce.Do(func() {
+		graceTimeoutConfigured = loadHandoffConfig(detectTownRootFromCwd()).GetGraceTimeout()
+	})
+	return resolveGraceTimeout(handoffGrace, graceTimeoutConfigured)
+}
+
+// resolveGraceTimeout applies the flag-over-config precedence for GraceTimeout.
+func resolveGraceTimeout(flag, configured time.Duration) time.Duration {
+	if flag > 0 {
+		return flag
+	}
+	return configured
+}
+
+// checkHandoffCooldown returns an error if agent was handed off less than
+// cooldown before now, according to the town log handoff events.
+func checkHandoffCooldown(history []townlog.Event, agent string, cooldown time.Duration, now time.Time) error {
+	if cooldown <= 0 {
+		return nil
+	}
+	for i := len(history) - 1; i >= 0; i-- {
+		e := history[i]
+		if e.Type != townlog.EventHandoff || e.Agent != agent {
+			continue
+		}
+		elapsed := now.Sub(e.Timestamp)
+		if elapsed >= cooldown {
+			return nil
+		}
+		remaining := (cooldown - elapsed).Round(time.Second)
+		if remaining < time.Second {
+			remaining = time.Second
+		}
+		return fmt.Errorf("handoff cooldown active for %s: last handoff %s ago, retry in %s (use --force to override)",
+			agent, elapsed.Round(time.Second), remaining)
+	}
+	return nil
+}
+
+// handoffKillSession kills the target session instead of respawning it.
+// Asks for confirmation unless --force is set or t is in dry-run mode.
+func handoffKillSession(t *tmux.Tmux, targetSession string) error {
+	exists, err := t.HasSession(targetSession)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return missingSessionError(t, targetSession)
+	}
+
+	if !handoffForce && !t.DryRun() && !promptYesNo(fmt.Sprintf("Kill session %s?", targetSession)) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	if !t.DryRun() {
+		if townRoot := detectTownRootFromCwd(); townRoot != "" {
+			_ = LogKill(townRoot, handoffAgentName(targetSession), "gt handoff --kill")
+		}
+	}
+
+	fmt.Printf("%s Killing %s...\n", style.Bold.Render("💀"), targetSession)
+
+	// Exclude our own PID in case we're running inside the target session;
+	// the final kill-session takes us down with it.
+	myPID := strconv.Itoa(os.Getpid())
+	if err := t.KillSessionWithProcessesExcluding(targetSession, []string{myPID}); err != nil {
+		return fmt.Errorf("killing session %s: %w", targetSession, err)
+	}
+	return nil
+}
+
+// missingSessionError explains why targetSession couldn't be found,
+// distinguishing a down tmux server from a session that isn't running.
+func missingSessionError(t *tmux.Tmux, targetSession string) error {
+	if running, err := t.IsServerRunning(); err == nil && !running {
+		return fmt.Errorf("%w - cannot reach session '%s' (start agents with 'gt up')", tmux.ErrNoServer, targetSession)
+	}
+	return fmt.Errorf("%w: '%s' - is the agent running?", tmux.ErrSessionNotFound, targetSession)
+}
+
+// getCurrentTmuxSession returns the current tmux session name.
+func getCurrentTmuxSession() (string, error) {
+	return tmux.NewTmux().CurrentSession()
 }
 
 // resolveRoleToSession converts a role name or path to a tmux session name.
 // Accepts:
 //   - Role shortcuts: "crew", "witness", "refinery", "mayor", "deacon"
 //   - Full paths: "<rig>/crew/<name>", "<rig>/witness", "<rig>/refinery"
+//   - Custom roles from town settings: "<role>", "<rig>/<role>"
 //   - Direct session names (passed through)
 //
 // For role shortcuts that need context (crew, witness, refinery), it auto-detects from environment.
 func resolveRoleToSession(role string) (string, error) {
+	return resolveRoleToSessionWith(role, func(name string) bool {
+		exists, err := tmux.NewTmux().HasSession(name)
+		return err == nil && exists
+	})
+}
+
+// resolveRoleToSessionWith is resolveRoleToSession with the tmux session
+// check injected. sessionExists decides whether a near-miss of a role name
+// is a real session rather than a typo, and whether to fall back to a
+// session still running under session.LegacyPrefix.
+func resolveRoleToSessionWith(role string, sessionExists func(string) bool) (string, error) {
+	target, err := resolveRoleTarget(role, sessionExists)
+	if err != nil {
+		return "", err
+	}
+	return resolveLegacySession(target, session.Prefix, sessionExists), nil
+}
+
+// resolveLegacySession returns target, or the legacy-prefixed session it
+// refers to when only that one is running (with a deprecation note).
+func resolveLegacySession(target, prefix string, sessionExists func(string) bool) string {
+	resolved, legacy := session.ResolveLegacyPrefix(target, prefix, sessionExists)
+	if legacy {
+		style.PrintWarning("using %s: session prefix %q is deprecated, restart it to move it to %s", resolved, session.LegacyPrefix, target)
+	}
+	return resolved
+}
+
+func resolveRoleTarget(role string, sessionExists func(string) bool) (string, error) {
 	// First, check if it's a path format (contains /)
 	if strings.Contains(role, "/") {
 		return resolvePathToSession(role)
@@ -279,28 +930,76 @@ func resolveRoleToSession(role string) (string, error) {
 		if rig == "" || crewName == "" {
 			return "", fmt.Errorf("cannot determine crew identity - run from crew directory or specify GT_RIG/GT_CREW")
 		}
-		return fmt.Sprintf("gt-%s-crew-%s", rig, crewName), nil
+		return session.CrewSessionName(rig, crewName), nil
 
 	case "witness", "wit":
 		rig := os.Getenv("GT_RIG")
 		if rig == "" {
 			return "", fmt.Errorf("cannot determine rig - set GT_RIG or run from rig context")
 		}
-		return fmt.Sprintf("gt-%s-witness", rig), nil
+		return session.WitnessSessionName(rig), nil
 
 	case "refinery", "ref":
 		rig := os.Getenv("GT_RIG")
 		if rig == "" {
 			return "", fmt.Errorf("cannot determine rig - set GT_RIG or run from rig context")
 		}
-		return fmt.Sprintf("gt-%s-refinery", rig), nil
+		return session.RefinerySessionName(rig), nil
 
 	default:
+		if target, ok, err := resolveCustomRoleTarget(role); ok {
+			return target, err
+		}
+		// A near-miss of a known role is almost certainly a typo; only treat it
+		// as a direct session name if such a session actually exists.
+		if suggestion := suggestHandoffRole(role); suggestion != "" {
+			if !sessionExists(role) {
+				return "", fmt.Errorf("unknown role '%s', did you mean '%s'?", role, suggestion)
+			}
+		}
 		// Assume it's a direct session name (e.g., gt-gastown-crew-max)
 		return role, nil
 	}
 }
 
+// handoffRoleAliases maps every role shortcut accepted by resolveRoleToSession
+// to its canonical role name.
+var handoffRoleAliases = map[string]string{
+	"mayor":    "mayor",
+	"may":      "mayor",
+	"deacon":   "deacon",
+	"dea":      "deacon",
+	"crew":     "crew",
+	"witness":  "witness",
+	"wit":      "witness",
+	"refinery": "refinery",
+	"ref":      "refinery",
+}
+
+// maxRoleTypoDistance is the largest edit distance treated as a role typo.
+const maxRoleTypoDistance = 2
+
+// suggestHandoffRole returns the canonical role closest to input, or "" if
+// input isn't a plausible typo of any known role or alias, built-in or
+// custom.
+func suggestHandoffRole(input string) string {
+	aliases := customRoleNames(customRoles())
+	for name, role := range handoffRoleAliases {
+		aliases[name] = role
+	}
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	match, ok := suggest.Closest(input, names, maxRoleTypoDistance)
+	if !ok {
+		return ""
+	}
+	return aliases[match]
+}
+
 // resolvePathToSession converts a path like "<rig>/crew/<name>" to a session name.
 // Supported formats:
 //   - <rig>/crew/<name> -> gt-<rig>-crew-<name>
@@ -309,6 +1008,10 @@ func resolveRoleToSession(role string) (string, error) {
 //   - <rig>/polecats/<name> -> gt-<rig>-<name> (explicit polecat)
 //   - <rig>/<name> -> gt-<rig>-<name> (polecat shorthand, if name isn't a known role)
 func resolvePathToSession(path string) (string, error) {
+	if target, ok, err := resolveCustomRoleTarget(path); ok {
+		return target, err
+	}
+
 	parts := strings.Split(path, "/")
 
 	// Handle <rig>/crew/<name> format
@@ -373,16 +1076,36 @@ var claudeEnvVars = []string{
 	"AWS_REGION",
 }
 
+// resolveRestartCommand returns the command to respawn sessionName with.
+// A non-empty override (--restart-command) is used verbatim, bypassing the
+// session-name pattern matching in buildRestartCommand.
+func resolveRestartCommand(sessionName, override string) (string, error) {
+	if override = strings.TrimSpace(override); override != "" {
+		return override, nil
+	}
+	return buildRestartCommand(sessionName)
+}
+
 // buildRestartCommand creates the command to run when respawning a session's pane.
 // This needs to be the actual command to execute (e.g., claude), not a session attach command.
 // The command includes a cd to the correct working directory for the role.
 func buildRestartCommand(sessionName string) (string, error) {
+	return buildRestartCommandWithContext(sessionName, "")
+}
+
+// buildRestartCommandWithContext is buildRestartCommand with context (the
+// predecessor's summary, see handoffBeaconContext) added to the beacon.
+func buildRestartCommandWithContext(sessionName, context string) (string, error) {
 	// Detect town root from current directory
 	townRoot := detectTownRootFromCwd()
 	if townRoot == "" {
 		return "", fmt.Errorf("cannot detect town root - run from within a Gas Town workspace")
 	}
 
+	if role, ok := session.ParseCustomRoleSession(sessionName, customRolesIn(townRoot)); ok {
+		return buildCustomRoleRestartCommand(role, townRoot, context)
+	}
+
 	// Determine the working directory for this session type
 	workDir, err := sessionWorkDir(sessionName, townRoot)
 	if err != nil {
@@ -403,6 +1126,7 @@ func buildRestartCommand(sessionName string) (string, error) {
 		Recipient: identity.Address(),
 		Sender:    "self",
 		Topic:     "handoff",
+		Context:   context,
 	})
 
 	// For respawn-pane, we:
@@ -412,13 +1136,15 @@ func buildRestartCommand(sessionName string) (string, error) {
 	// 4. run claude with the startup beacon (triggers immediate context loading)
 	// Use exec to ensure clean process replacement.
 	//
-	// Check if current session is using a non-default agent (GT_AGENT env var).
-	// If so, preserve it across handoff by using the override variant.
+	// Check if current session is using a non-default agent (GT_AGENT env var)
+	// or model (GT_MODEL). If so, preserve them across handoff by using the
+	// override variant.
 	currentAgent := os.Getenv("GT_AGENT")
+	currentModel := os.Getenv("GT_MODEL")
 	var runtimeCmd string
-	if currentAgent != "" {
+	if currentAgent != "" || currentModel != "" {
 		var err error
-		runtimeCmd, err = config.GetRuntimeCommandWithPromptAndAgentOverride("", beacon, currentAgent)
+		runtimeCmd, err = config.GetRuntimeCommandWithPromptAndOverrides("", beacon, currentAgent, currentModel)
 		if err != nil {
 			return "", fmt.Errorf("resolving agent config: %w", err)
 		}
@@ -440,25 +1166,52 @@ func buildRestartCommand(sessionName string) (string, error) {
 
 	// Propagate GT_ROOT so subsequent handoffs can use it as fallback
 	// when cwd-based detection fails (broken state recovery)
-	exports = append(exports, "GT_ROOT="+townRoot)
+	exports = append(exports, "GT_ROOT="+config.ShellQuote(townRoot))
 
+	exports = append(exports, handoffPassthroughExports(currentAgent)...)
+
+	// The result runs in the pane's shell (RespawnPane escapes it for tmux)
+	if len(exports) > 0 {
+		return fmt.Sprintf("cd %s && export %s && exec %s", config.ShellQuote(workDir), strings.Join(exports, " "), runtimeCmd), nil
+	}
+	return fmt.Sprintf("cd %s && exec %s", config.ShellQuote(workDir), runtimeCmd), nil
+}
+
+// handoffPassthroughExports returns the exports that carry the current
+// session's agent and model overrides and Claude-related env vars into its
+// successor.
+func handoffPassthroughExports(currentAgent string) []string {
+	var exports []string
 	// Preserve GT_AGENT across handoff so agent override persists
 	if currentAgent != "" {
-		exports = append(exports, "GT_AGENT="+currentAgent)
+		exports = append(exports, "GT_AGENT="+config.ShellQuote(currentAgent))
+	}
+	// Likewise GT_MODEL, so the successor is started with the same model
+	if model := os.Getenv("GT_MODEL"); model != "" {
+		exports = append(exports, "GT_MODEL="+config.ShellQuote(model))
+	}
+	// And the resume flag override, which gt prime records for gt resume
+	if flag := handoffResumeFlag(); flag != "" {
+		exports = append(exports, "GT_RESUME_FLAG="+config.ShellQuote(flag))
 	}
 
 	// Add Claude-related env vars from current environment
 	for _, name := range claudeEnvVars {
 		if val := os.Getenv(name); val != "" {
 			// Shell-escape the value in case it contains special chars
-			exports = append(exports, fmt.Sprintf("%s=%q", name, val))
+			exports = append(exports, name+"="+config.ShellQuote(val))
 		}
 	}
+	return exports
+}
 
-	if len(exports) > 0 {
-		return fmt.Sprintf("cd %s && export %s && exec %s", workDir, strings.Join(exports, " "), runtimeCmd), nil
+// handoffResumeFlag returns the resume flag override the successor gets:
+// --resume-flag, else the current session's (GT_RESUME_FLAG).
+func handoffResumeFlag() string {
+	if handoffResume != "" {
+		return handoffResume
 	}
-	return fmt.Sprintf("cd %s && exec %s", workDir, runtimeCmd), nil
+	return os.Getenv("GT_RESUME_FLAG")
 }
 
 // sessionWorkDir returns the correct working directory for a session.
@@ -507,6 +1260,9 @@ func sessionWorkDir(sessionName, townRoot string) (string, error) {
 		return fmt.Sprintf("%s/%s/refinery/rig", townRoot, rig), nil
 
 	default:
+		if role, ok := session.ParseCustomRoleSession(sessionName, customRolesIn(townRoot)); ok {
+			return role.WorkDir(townRoot), nil
+		}
 		// Assume polecat: gt-<rig>-<name> -> <townRoot>/<rig>/polecats/<name>
 		// Use session.ParseSessionName to determine rig and name
 		identity, err := session.ParseSessionName(sessionName)
@@ -560,97 +1316,461 @@ func detectTownRootFromCwd() string {
 	return ""
 }
 
-// handoffRemoteSession respawns a different session and optionally switches to it.
-func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string) error {
+// selfHandoffPane returns the pane a self handoff respawns: envPane
+// (TMUX_PANE) if it belongs to currentSession, else currentSession's active
+// pane. TMUX_PANE goes stale in nested or switched clients, and respawning
+// it would kill an unrelated pane.
+func selfHandoffPane(t *tmux.Tmux, envPane, currentSession string) (string, error) {
+	panes, err := t.ListSessionPanes(currentSession)
+	if err != nil {
+		return "", fmt.Errorf("listing panes of %s: %w", currentSession, err)
+	}
+	if slices.Contains(panes, envPane) {
+		return envPane, nil
+	}
+
+	active, err := t.ActivePaneID(currentSession)
+	if err != nil {
+		return "", fmt.Errorf("finding active pane of %s: %w", currentSession, err)
+	}
+	style.PrintWarning("TMUX_PANE %s is not in session %s; using its active pane %s", envPane, currentSession, active)
+	return active, nil
+}
+
+// handoffShouldSwitch reports whether a remote handoff should switch the
+// client to its target: only with --watch, and never with --no-switch.
+func handoffShouldSwitch() bool {
+	return handoffWatch && !handoffNoSwitch
+}
+
+// handoffRemoteSession respawns a different session and, if switchTo is set,
+// moves the user's view to it (switching the client, or attaching from a
+// plain terminal).
+func handoffRemoteSession(t *tmux.Tmux, targetSession, restartCmd string, wait *handoffWaiter, switchTo bool) error {
+	if err := respawnRemoteSession(t, os.Stdout, targetSession, restartCmd); err != nil {
+		return err
+	}
+
+	if !t.DryRun() {
+		logHandoffEvent(targetSession, restartCmd, false)
+	}
+
+	// The handoff itself happened (and is logged) even if the agent is slow
+	if err := wait.await(t, os.Stdout, targetSession); err != nil {
+		return err
+	}
+
+	// If --watch, switch to that session
+	if switchTo {
+		fmt.Printf("Switching to %s...\n", targetSession)
+		if err := t.AttachOrSwitch(targetSession); err != nil {
+			// Non-fatal - they can manually switch
+			fmt.Printf("Note: Could not auto-switch (use: tmux %s -t %s)\n", followCommand(), targetSession)
+		}
+	}
+
+	return nil
+}
+
+// followCommand is the tmux command AttachOrSwitch uses here.
+func followCommand() string {
+	if tmux.IsInsideTmux() {
+		return "switch-client"
+	}
+	return "attach-session"
+}
+
+// respawnRemoteSession kills and respawns another session's pane with
+// restartCmd, writing progress to w. It touches only targetSession, so
+// calls for distinct sessions can run concurrently.
+func respawnRemoteSession(t *tmux.Tmux, w io.Writer, targetSession, restartCmd string) error {
 	// Check if target session exists
 	exists, err := t.HasSession(targetSession)
 	if err != nil {
 		return fmt.Errorf("checking session: %w", err)
 	}
 	if !exists {
-		return fmt.Errorf("session '%s' not found - is the agent running?", targetSession)
+		return missingSessionError(t, targetSession)
 	}
 
 	// Get the pane ID for the target session
-	targetPane, err := getSessionPane(targetSession)
+	targetPane, err := t.GetPaneID(targetSession)
 	if err != nil {
 		return fmt.Errorf("getting target pane: %w", err)
 	}
 
-	fmt.Printf("%s Handing off %s...\n", style.Bold.Render("🤝"), targetSession)
-
-	// Dry run mode
-	if handoffDryRun {
-		fmt.Printf("Would execute: tmux clear-history -t %s\n", targetPane)
-		fmt.Printf("Would execute: tmux respawn-pane -k -t %s %s\n", targetPane, restartCmd)
-		if handoffWatch {
-			fmt.Printf("Would execute: tmux switch-client -t %s\n", targetSession)
-		}
-		return nil
-	}
+	fmt.Fprintf(w, "%s Handing off %s...\n", style.Bold.Render("🤝"), targetSession)
 
 	// Set remain-on-exit so the pane survives process death during handoff.
 	// Without this, killing processes causes tmux to destroy the pane before
 	// we can respawn it. This is essential for tmux session reuse.
 	if err := t.SetRemainOnExit(targetPane, true); err != nil {
-		style.PrintWarning("could not set remain-on-exit: %v", err)
+		style.FprintWarning(w, "could not set remain-on-exit: %v", err)
 	}
 
 	// Kill all processes in the pane before respawning to prevent orphan leaks
 	// RespawnPane's -k flag only sends SIGHUP which Claude/Node may ignore
 	if err := t.KillPaneProcesses(targetPane); err != nil {
 		// Non-fatal but log the warning
-		style.PrintWarning("could not kill pane processes: %v", err)
+		style.FprintWarning(w, "could not kill pane processes: %v", err)
 	}
 
 	// Clear scrollback history before respawn (resets copy-mode from [0/N] to [0/0])
 	if err := t.ClearHistory(targetPane); err != nil {
 		// Non-fatal - continue with respawn even if clear fails
-		style.PrintWarning("could not clear history: %v", err)
+		style.FprintWarning(w, "could not clear history: %v", err)
 	}
 
 	// Respawn the remote session's pane, handling deleted working directories
-	respawnErr := func() error {
-		paneWorkDir, _ := t.GetPaneWorkDir(targetSession)
-		if paneWorkDir != "" {
-			if _, statErr := os.Stat(paneWorkDir); statErr != nil {
-				if townRoot := detectTownRootFromCwd(); townRoot != "" {
-					style.PrintWarning("pane working directory deleted, using town root")
-					return t.RespawnPaneWithWorkDir(targetPane, townRoot, restartCmd)
+	paneWorkDir, _ := t.GetPaneWorkDir(targetSession)
+	if paneWorkDir != "" {
+		if _, statErr := os.Stat(paneWorkDir); statErr != nil {
+			if townRoot := detectTownRootFromCwd(); townRoot != "" {
+				style.FprintWarning(w, "pane working directory deleted, using town root")
+				gtlog.L().Warn("handoff pane working directory deleted", "session", targetSession, "workdir", paneWorkDir)
+				if err := t.RespawnPaneWithWorkDir(targetPane, townRoot, restartCmd); err != nil {
+					gtlog.L().Error("handoff respawn failed", "session", targetSession, "pane", targetPane, "workdir", townRoot, "err", err)
+					return fmt.Errorf("%w: %w", ErrRespawnFailed, err)
 				}
+				gtlog.L().Info("handoff respawned session", "session", targetSession, "workdir", townRoot, "restart_cmd", restartCmd)
+				return nil
 			}
 		}
-		return t.RespawnPane(targetPane, restartCmd)
-	}()
-	if respawnErr != nil {
-		return fmt.Errorf("respawning pane: %w", respawnErr)
 	}
+	if err := t.RespawnPane(targetPane, restartCmd); err != nil {
+		gtlog.L().Error("handoff respawn failed", "session", targetSession, "pane", targetPane, "err", err)
+		return fmt.Errorf("%w: %w", ErrRespawnFailed, err)
+	}
+	gtlog.L().Info("handoff respawned session", "session", targetSession, "restart_cmd", restartCmd)
+	return nil
+}
 
-	// If --watch, switch to that session
-	if handoffWatch {
-		fmt.Printf("Switching to %s...\n", targetSession)
-		// Use tmux switch-client to move our view to the target session
-		if err := exec.Command("tmux", "switch-client", "-t", targetSession).Run(); err != nil {
-			// Non-fatal - they can manually switch
-			fmt.Printf("Note: Could not auto-switch (use: tmux switch-client -t %s)\n", targetSession)
+// defaultHandoffWaitTimeout is the default --wait-timeout.
+const defaultHandoffWaitTimeout = 2 * time.Minute
+
+// handoffWaitInterval is how often --wait captures the respawned pane.
+const handoffWaitInterval = 500 * time.Millisecond
+
+// handoffWaiter implements --wait: it polls a respawned session's pane until
+// the agent shows its ready prompt. A nil waiter doesn't wait.
+type handoffWaiter struct {
+	marker   string        // pane line prefix that marks the agent ready
+	delay    time.Duration // slept instead when there is no marker
+	prompt   string        // priming prompt sent once ready; empty sends nothing
+	townRoot string        // town whose prompt templates apply; empty uses prompt as is
+	agent    string        // agent name for the templates
+	timeout  time.Duration
+	interval time.Duration
+}
+
+// newHandoffWait returns the waiter for the --wait flags, or nil without
+// --wait. The marker is --ready-marker if given, else the ready prompt of
+// the agent buildRestartCommand launches; the priming prompt is that agent's,
+// or its session's prompt template (see gt prompt render).
+func newHandoffWait() *handoffWaiter {
+	if !handoffWait {
+		return nil
+	}
+	townRoot := detectTownRootFromCwd()
+	rc, agent := handoffAgentConfig(townRoot)
+	w := &handoffWaiter{marker: handoffMarker, prompt: rc.PrimingPrompt, townRoot: townRoot, agent: agent, timeout: handoffWaitFor, interval: handoffWaitInterval}
+	if w.marker == "" && rc.Tmux != nil {
+		w.marker = rc.Tmux.ReadyPromptPrefix
+		w.delay = time.Duration(rc.Tmux.ReadyDelayMs) * time.Millisecond
+	}
+	return w
+}
+
+// handoffAgentConfig returns the resolved runtime config and name of the
+// agent buildRestartCommand launches: the town default, or GT_AGENT's
+// override.
+func handoffAgentConfig(townRoot string) (*config.RuntimeConfig, string) {
+	if townRoot == "" {
+		return config.DefaultRuntimeConfig(), ""
+	}
+	rc, name, err := config.ResolveAgentConfigWithOverride(townRoot, "", os.Getenv("GT_AGENT"))
+	if err != nil {
+		return config.DefaultRuntimeConfig(), ""
+	}
+	return rc.Resolved(), name
+}
+
+// promptFor returns the priming prompt for targetSession: its prompt
+// template rendered, or the agent's priming prompt. A template that fails
+// to render is reported to out and the priming prompt used instead.
+func (w *handoffWaiter) promptFor(out io.Writer, targetSession string) string {
+	if w.townRoot == "" {
+		return w.prompt
+	}
+	data := session.PromptData{Town: w.townRoot, Agent: w.agent, Topic: "handoff", Default: w.prompt}
+	if id, err := session.ParseSessionName(targetSession); err == nil {
+		data.Rig, data.Role, data.Name, data.Address = id.Rig, string(id.Role), id.Name, id.Address()
+	}
+	if session.PromptTemplatePath(data.Town, data.Rig, data.Role) == "" {
+		return w.prompt
+	}
+	if data.Address != "" {
+		if hooked := scanAllRigsForHookedBeads(w.townRoot, data.Address); len(hooked) > 0 {
+			data.Bead = hooked[0].ID
+		}
+	}
+	prompt, _, err := session.RenderStartupPrompt(data)
+	if err != nil {
+		gtlog.L().Warn("prompt template failed", "session", targetSession, "err", err)
+		fmt.Fprintf(out, "%s %v; sending the default priming prompt\n", style.WarningPrefix, err)
+		return w.prompt
+	}
+	return prompt
+}
+
+// await blocks until targetSession's agent is ready, writing progress to
+// out, then sends the priming prompt. Returns ErrNotReady if the marker
+// doesn't appear within the timeout; no prompt is sent in that case.
+func (w *handoffWaiter) await(t *tmux.Tmux, out io.Writer, targetSession string) error {
+	if w == nil {
+		return nil
+	}
+	prompt := w.promptFor(out, targetSession)
+	if t.DryRun() {
+		fmt.Fprintf(out, "Would wait up to %s for %s to become ready\n", w.timeout, targetSession)
+		if prompt != "" {
+			fmt.Fprintf(out, "Would send priming prompt: %s\n", prompt)
 		}
+		return nil
+	}
+	if w.marker == "" {
+		// No prompt to detect - fall back to the agent's fixed startup delay
+		delay := w.delay
+		if delay > w.timeout {
+			delay = w.timeout
+		}
+		time.Sleep(delay)
+	} else {
+		fmt.Fprintf(out, "Waiting for %s to become ready...\n", targetSession)
+		if err := t.WaitForReadyPrompt(targetSession, w.marker, w.timeout, w.interval); err != nil {
+			gtlog.L().Warn("handoff agent not ready", "session", targetSession, "marker", w.marker, "timeout", w.timeout.String(), "err", err)
+			return fmt.Errorf("%w: %s showed no %q prompt within %s", ErrNotReady, targetSession, w.marker, w.timeout)
+		}
+		fmt.Fprintf(out, "%s %s is ready\n", style.SuccessPrefix, targetSession)
 	}
 
+	if prompt == "" {
+		return nil
+	}
+	if err := t.SendKeys(targetSession, prompt); err != nil {
+		gtlog.L().Error("handoff priming prompt failed", "session", targetSession, "err", err)
+		return fmt.Errorf("sending priming prompt to %s: %w", targetSession, err)
+	}
+	fmt.Fprintf(out, "Sent priming prompt to %s\n", targetSession)
 	return nil
 }
 
-// getSessionPane returns the pane identifier for a session's main pane.
-func getSessionPane(sessionName string) (string, error) {
-	// Get the pane ID for the first pane in the session
-	out, err := exec.Command("tmux", "list-panes", "-t", sessionName, "-F", "#{pane_id}").Output()
+// defaultHandoffParallel is the default --parallel worker count for --all.
+const defaultHandoffParallel = 4
+
+// handoffAllFilter narrows --all to the sessions of one role and/or rig
+// (--role, --rig); empty fields match every session.
+type handoffAllFilter struct {
+	role string
+	rig  string
+}
+
+func (f handoffAllFilter) match(role, rig string) bool {
+	return (f.role == "" || f.role == role) && (f.rig == "" || f.rig == rig)
+}
+
+// handoffAllTargets returns the sessions --all hands off: every running
+// Gas Town agent session matching filter, custom roles included, except
+// polecats (the Witness owns their lifecycle) and the caller's own session,
+// sorted by name.
+func handoffAllTargets(t *tmux.Tmux, currentSession string, filter handoffAllFilter) ([]string, error) {
+	sessions, err := t.ListSessions()
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+
+	roles := customRoles()
+	var targets []string
+	for _, s := range sessions {
+		if s == currentSession {
+			continue
+		}
+		if custom, ok := session.ParseCustomRoleSession(s, roles); ok {
+			if filter.match(custom.Role, custom.Rig) {
+				targets = append(targets, s)
+			}
+			continue
+		}
+		identity, err := session.ParseSessionName(s)
+		if err != nil || identity.Role == session.RolePolecat || !filter.match(string(identity.Role), identity.Rig) {
+			continue
+		}
+		targets = append(targets, s)
+	}
+	sort.Strings(targets)
+	return targets, nil
+}
+
+// handoffTask is one session to respawn with --all.
+type handoffTask struct {
+	session    string
+	restartCmd string
+	wait       *handoffWaiter // nil without --wait
+}
+
+// handoffResult is the outcome of handing off one session with --all.
+type handoffResult struct {
+	session    string
+	restartCmd string
+	output     string // progress (and dry-run) output, printed once all workers finish
+	err        error
+}
+
+// runParallelHandoffs respawns each task's session using at most parallel
+// workers. Every worker gets its own Tmux from newTmux, writing to a private
+// buffer, so output from concurrent handoffs never interleaves. Workers
+// target distinct sessions, so their tmux commands don't race on a pane.
+// done, if set, is called with each result as its handoff finishes, one call
+// at a time. Results are returned sorted by session name.
+func runParallelHandoffs(tasks []handoffTask, parallel int, newTmux func(w io.Writer) *tmux.Tmux, done func(handoffResult)) []handoffResult {
+	results := make([]handoffResult, len(tasks))
+	if len(tasks) == 0 {
+		return results
+	}
+
+	numWorkers := parallel
+	if numWorkers < 1 {
+		numWorkers = 1
+	}
+	if len(tasks) < numWorkers {
+		numWorkers = len(tasks)
+	}
+
+	indexes := make(chan int, len(tasks))
+	var wg sync.WaitGroup
+	var doneMu sync.Mutex
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				task := tasks[idx]
+				var buf bytes.Buffer
+				tm := newTmux(&buf)
+				err := respawnRemoteSession(tm, &buf, task.session, task.restartCmd)
+				if err == nil {
+					err = task.wait.await(tm, &buf, task.session)
+				}
+				// Each worker writes only its own slot - no locking needed
+				results[idx] = handoffResult{session: task.session, restartCmd: task.restartCmd, output: buf.String(), err: err}
+				if done != nil {
+					doneMu.Lock()
+					done(results[idx])
+					doneMu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range tasks {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].session < results[j].session })
+	return results
+}
+
+// handoffAllSessions hands off every session from handoffAllTargets
+// concurrently, reporting each as it finishes, and prints a summary.
+// Restart commands and the cooldown check are resolved up front, serially;
+// only the tmux work runs in the worker pool.
+func handoffAllSessions(t *tmux.Tmux, currentSession string, filter handoffAllFilter, parallel int, newTmux func(w io.Writer) *tmux.Tmux, wait *handoffWaiter) error {
+	targets, err := handoffAllTargets(t, currentSession, filter)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println("No matching agent sessions to hand off")
+		return nil
+	}
+
+	var history []townlog.Event
+	var cooldown time.Duration
+	if !handoffForce {
+		if townRoot := detectTownRootFromCwd(); townRoot != "" {
+			history, _ = townlog.ReadEvents(townRoot)
+			cooldown = loadHandoffCooldown(townRoot)
+		}
 	}
-	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
-	if len(lines) == 0 || lines[0] == "" {
-		return "", fmt.Errorf("no panes found in session")
+
+	var tasks []handoffTask
+	var results []handoffResult
+	for _, target := range targets {
+		if cooldown > 0 {
+			if err := checkHandoffCooldown(history, handoffAgentName(target), cooldown, time.Now()); err != nil {
+				results = append(results, handoffResult{session: target, err: err})
+				continue
+			}
+		}
+		restartCmd, err := handoffRestartCommand(target, t.CapturePane)
+		if err != nil {
+			results = append(results, handoffResult{session: target, err: err})
+			continue
+		}
+		tasks = append(tasks, handoffTask{session: target, restartCmd: restartCmd, wait: wait})
 	}
-	return lines[0], nil
+
+	fmt.Printf("%s Handing off %d session(s) (parallel %d)...\n", style.Bold.Render("🤝"), len(tasks), parallel)
+	finished := 0
+	progress := func(r handoffResult) {
+		finished++
+		if r.err != nil {
+			fmt.Printf("  [%d/%d] %s %s: %v\n", finished, len(tasks), style.ErrorPrefix, r.session, r.err)
+		} else {
+			fmt.Printf("  [%d/%d] %s %s\n", finished, len(tasks), style.SuccessPrefix, r.session)
+		}
+	}
+	results = append(results, runParallelHandoffs(tasks, parallel, newTmux, progress)...)
+	sort.Slice(results, func(i, j int) bool { return results[i].session < results[j].session })
+
+	return printHandoffResults(results, t.DryRun())
+}
+
+// printHandoffResults prints buffered worker output in session order and a
+// summary naming the sessions that failed, logging each successful handoff.
+// Returns an error if any handoff failed.
+func printHandoffResults(results []handoffResult, dryRun bool) error {
+	var failed []handoffResult
+	for _, r := range results {
+		fmt.Print(r.output)
+		if r.err != nil {
+			failed = append(failed, r)
+			// A session that respawned but never became ready was still handed off
+			if !errors.Is(r.err, ErrNotReady) {
+				continue
+			}
+		}
+		if !dryRun {
+			logHandoffEvent(r.session, r.restartCmd, false)
+		}
+	}
+
+	fmt.Printf("\nHanded off %d, failed %d\n", len(results)-len(failed), len(failed))
+	if len(failed) == 0 {
+		return nil
+	}
+	fmt.Println("\nFailed:")
+	for _, r := range failed {
+		fmt.Printf("  %s %s: %v\n", style.ErrorPrefix, r.session, r.err)
+	}
+	fmt.Printf("\nRetry one with: %s\n", style.Dim.Render("gt handoff "+failed[0].session))
+	return fmt.Errorf("%d of %d handoffs failed", len(failed), len(results))
+}
+
+// getSessionPane returns the pane identifier for a session's main pane.
+func getSessionPane(sessionName string) (string, error) {
+	return tmux.NewTmux().GetPaneID(sessionName)
 }
 
 // sendHandoffMail sends a handoff mail to self and auto-hooks it.
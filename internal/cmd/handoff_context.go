@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// handoffContextLines is how much of the outgoing pane --with-context
+// captures when no handoff-context hook prints a summary.
+const handoffContextLines = 200
+
+// handoffContextMaxBytes caps the summary carried in the new session's
+// prompt. The handoff file always keeps all of it.
+const handoffContextMaxBytes = 4000
+
+// paneCapturer returns the last lines of a session's pane, like
+// tmux.Tmux.CapturePane.
+type paneCapturer func(session string, lines int) (string, error)
+
+// handoffRestartCommand resolves the command that respawns targetSession.
+// With --with-context it first captures the outgoing agent's summary,
+// writes it to the session's handoff file and carries it into the new
+// session's startup prompt. capture reads the pane; nil means the
+// multiplexer can't, leaving only handoff-context hooks as a source.
+func handoffRestartCommand(targetSession string, capture paneCapturer) (string, error) {
+	if !handoffWithContext {
+		return resolveRestartCommand(targetSession, handoffRestart)
+	}
+	if strings.TrimSpace(handoffRestart) != "" {
+		return "", fmt.Errorf("--with-context cannot be combined with --restart-command")
+	}
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return "", fmt.Errorf("cannot detect town root - run from within a Gas Town workspace")
+	}
+
+	summary, source := captureHandoffContext(townRoot, targetSession, capture)
+	if summary == "" {
+		style.PrintWarning("no summary to carry over from %s; its successor starts without one", targetSession)
+		return buildRestartCommand(targetSession)
+	}
+	path := handoffContextPath(townRoot, targetSession)
+	if handoffDryRun {
+		fmt.Printf("Would write handoff context to %s (%d bytes from %s)\n", path, len(summary), source)
+	} else if err := writeHandoffContext(path, targetSession, source, summary); err != nil {
+		return "", err
+	}
+	return buildRestartCommandWithContext(targetSession, handoffBeaconContext(path, summary))
+}
+
+// captureHandoffContext returns the outgoing agent's summary for
+// targetSession and where it came from: the output of the handoff-context
+// hooks if they print anything, else the tail of the session's pane.
+func captureHandoffContext(townRoot, targetSession string, capture paneCapturer) (summary, source string) {
+	summary = strings.TrimSpace(hooks.Collect(townRoot, hooks.HandoffContext, map[string]string{
+		"agent":   handoffAgentName(targetSession),
+		"session": targetSession,
+	}))
+	if summary != "" {
+		return summary, "handoff-context hooks"
+	}
+	if capture == nil {
+		return "", ""
+	}
+	out, err := capture(targetSession, handoffContextLines)
+	if err != nil {
+		style.PrintWarning("could not capture %s's pane: %v", targetSession, err)
+		return "", ""
+	}
+	return trimPaneCapture(out), "pane"
+}
+
+// trimPaneCapture drops trailing whitespace from each captured line and
+// blank lines around the capture.
+func trimPaneCapture(out string) string {
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// handoffContextPath returns the handoff file --with-context writes for a
+// session. Each handoff of the session replaces it.
+func handoffContextPath(townRoot, sessionName string) string {
+	return filepath.Join(townRoot, ".runtime", "handoff", sessionName+".md")
+}
+
+// writeHandoffContext writes summary to the handoff file at path.
+func writeHandoffContext(path, sessionName, source, summary string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating handoff directory: %w", err)
+	}
+	content := fmt.Sprintf("# Handoff context: %s\n\nCaptured %s from the %s.\n\n%s\n",
+		sessionName, time.Now().Format(time.RFC3339), source, summary)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("writing handoff context: %w", err)
+	}
+	return nil
+}
+
+// handoffBeaconContext formats summary for the new session's startup
+// prompt. A summary over handoffContextMaxBytes keeps its last whole lines,
+// where the outgoing agent's final words are, and points at the file.
+func handoffBeaconContext(path, summary string) string {
+	header := "Your predecessor's summary (from " + path + "):"
+	if len(summary) > handoffContextMaxBytes {
+		summary = summary[len(summary)-handoffContextMaxBytes:]
+		if i := strings.IndexByte(summary, '\n'); i >= 0 {
+			summary = summary[i+1:]
+		}
+		header = "The end of your predecessor's summary (all of it is in " + path + "):"
+	}
+	return header + "\n\n" + summary
+}
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/hooks"
+)
+
+// setupHandoffContextTown creates a minimal town, makes it the working
+// directory, and isolates hook scripts from the user's home.
+func setupHandoffContextTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(hooks.EnvEvent, "")
+	t.Setenv("GT_AGENT", "")
+	return townRoot
+}
+
+func TestHandoffRestartCommandWithContext(t *testing.T) {
+	townRoot := setupHandoffContextTown(t)
+	handoffWithContext, handoffDryRun = true, false
+	defer func() { handoffWithContext = false }()
+
+	capture := func(session string, lines int) (string, error) {
+		if lines != handoffContextLines {
+			t.Errorf("captured %d lines, want %d", lines, handoffContextLines)
+		}
+		return "\n  old output\nDone: parser refactored, tests green.   \n\n\n", nil
+	}
+	mayor := getMayorSessionName()
+	got, err := handoffRestartCommand(mayor, capture)
+	if err != nil {
+		t.Fatalf("handoffRestartCommand: %v", err)
+	}
+	if !strings.Contains(got, "Done: parser refactored, tests green.") || !strings.Contains(got, "predecessor's summary") {
+		t.Errorf("restart command missing the summary:\n%s", got)
+	}
+
+	data, err := os.ReadFile(handoffContextPath(townRoot, mayor))
+	if err != nil {
+		t.Fatalf("handoff file not written: %v", err)
+	}
+	if !strings.HasSuffix(string(data), "\n\n  old output\nDone: parser refactored, tests green.\n") {
+		t.Errorf("handoff file = %q", data)
+	}
+}
+
+func TestHandoffRestartCommandWithContextPrefersHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	townRoot := setupHandoffContextTown(t)
+	handoffWithContext, handoffDryRun = true, false
+	defer func() { handoffWithContext = false }()
+
+	dir := filepath.Join(townRoot, hooks.Dir, string(hooks.HandoffContext)+".d")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho \"summary for $GT_HOOK_SESSION\"\necho noise >&2\n"
+	if err := os.WriteFile(filepath.Join(dir, "10-summary.sh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	capture := func(string, int) (string, error) {
+		t.Error("pane captured although a hook printed a summary")
+		return "", nil
+	}
+	mayor := getMayorSessionName()
+	got, err := handoffRestartCommand(mayor, capture)
+	if err != nil {
+		t.Fatalf("handoffRestartCommand: %v", err)
+	}
+	if !strings.Contains(got, "summary for "+mayor) || strings.Contains(got, "noise") {
+		t.Errorf("restart command should carry the hook's stdout only:\n%s", got)
+	}
+}
+
+func TestHandoffRestartCommandWithContextFallsBack(t *testing.T) {
+	townRoot := setupHandoffContextTown(t)
+	handoffWithContext, handoffDryRun = true, false
+	defer func() { handoffWithContext = false }()
+
+	mayor := getMayorSessionName()
+	failing := func(string, int) (string, error) { return "", errors.New("no server") }
+	for name, capture := range map[string]paneCapturer{"capture fails": failing, "no capture": nil} {
+		got, err := handoffRestartCommand(mayor, capture)
+		if err != nil {
+			t.Fatalf("%s: handoffRestartCommand: %v", name, err)
+		}
+		if strings.Contains(got, "predecessor's summary") {
+			t.Errorf("%s: restart command carries a summary:\n%s", name, got)
+		}
+	}
+	if _, err := os.Stat(handoffContextPath(townRoot, mayor)); !os.IsNotExist(err) {
+		t.Errorf("handoff file written without a summary: %v", err)
+	}
+
+	handoffRestart = "exec my-agent"
+	defer func() { handoffRestart = "" }()
+	if _, err := handoffRestartCommand(mayor, nil); err == nil {
+		t.Error("want an error combining --with-context and --restart-command")
+	}
+}
+
+func TestHandoffBeaconContext(t *testing.T) {
+	short := handoffBeaconContext("/town/.runtime/handoff/s.md", "all done")
+	if short != "Your predecessor's summary (from /town/.runtime/handoff/s.md):\n\nall done" {
+		t.Errorf("short context = %q", short)
+	}
+
+	long := strings.Repeat("early line\n", 500) + "final words"
+	got := handoffBeaconContext("/f.md", long)
+	header, body, _ := strings.Cut(got, "\n\n")
+	if !strings.Contains(header, "all of it is in /f.md") {
+		t.Errorf("truncated header = %q", header)
+	}
+	if len(body) > handoffContextMaxBytes || !strings.HasSuffix(body, "final words") || !strings.HasPrefix(body, "early line\n") {
+		t.Errorf("truncated body (%d bytes) should keep the last whole lines", len(body))
+	}
+}
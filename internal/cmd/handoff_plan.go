@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// HandoffPlan is everything a handoff of one target resolves to, as
+// printed by gt handoff --plan.
+type HandoffPlan struct {
+	Role           string `json:"role"`
+	Session        string `json:"session"`
+	WorkDir        string `json:"work_dir,omitempty"` // empty with --restart-command
+	Agent          string `json:"agent"`
+	Model          string `json:"model,omitempty"` // GT_MODEL; empty for the agent's default
+	Resume         bool   `json:"resume"`
+	ResumeFlag     string `json:"resume_flag,omitempty"`
+	RestartCommand string `json:"restart_command"`
+}
+
+func runHandoffPlan(args []string) error {
+	role := os.Getenv("GT_ROLE")
+	if len(args) > 0 {
+		role = args[0]
+	}
+	if role == "" {
+		return fmt.Errorf("--plan needs a target role (or GT_ROLE); it does not ask tmux for the current session")
+	}
+
+	plan, err := buildHandoffPlan(role)
+	if err != nil {
+		return err
+	}
+
+	if handoffJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+	printHandoffPlan(plan)
+	return nil
+}
+
+// buildHandoffPlan resolves role the way gt handoff would, without calling
+// tmux: a near-miss of a role name is always reported as a typo, since
+// there is no live session to check it against.
+func buildHandoffPlan(role string) (*HandoffPlan, error) {
+	target, err := resolveRoleToSessionWith(role, func(string) bool { return false })
+	if err != nil {
+		return nil, fmt.Errorf("resolving role: %w", err)
+	}
+
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return nil, fmt.Errorf("cannot detect town root - run from within a Gas Town workspace")
+	}
+
+	plan := &HandoffPlan{Role: role, Session: target}
+	if strings.TrimSpace(handoffRestart) == "" {
+		if plan.WorkDir, err = sessionWorkDir(target, townRoot); err != nil {
+			return nil, err
+		}
+	}
+	if plan.RestartCommand, err = resolveRestartCommand(target, handoffRestart); err != nil {
+		return nil, err
+	}
+	plan.RestartCommand = redactHandoffSecrets(plan.RestartCommand)
+
+	// Same agent buildRestartCommand launches: GT_AGENT, else a custom
+	// role's agent, else the town default
+	agentOverride := os.Getenv("GT_AGENT")
+	if role, ok := session.ParseCustomRoleSession(target, customRolesIn(townRoot)); ok && agentOverride == "" {
+		agentOverride = role.Config.Agent
+	}
+	_, agent, err := config.ResolveAgentConfigWithOverride(townRoot, "", agentOverride)
+	if err != nil {
+		return nil, fmt.Errorf("resolving agent: %w", err)
+	}
+	plan.Agent = agent
+	plan.Model = os.Getenv("GT_MODEL")
+	// --resume-flag (or the session's GT_RESUME_FLAG) replaces the preset's
+	// flag, as RuntimeConfig.ResumeFlag does in BuildResumeCommandWithConfig
+	if preset := config.GetAgentPresetByName(agent); preset != nil {
+		plan.ResumeFlag = preset.ResumeFlag
+		if flag := handoffResumeFlag(); flag != "" {
+			plan.ResumeFlag = flag
+		}
+		plan.Resume = plan.ResumeFlag != ""
+	}
+	return plan, nil
+}
+
+// handoffSecretEnvVars are the claudeEnvVars whose values a plan must not
+// print (it is meant for CI logs).
+var handoffSecretEnvVars = []string{"ANTHROPIC_API_KEY"}
+
+// redactHandoffSecrets masks the exported values of handoffSecretEnvVars
+// in a restart command built by buildRestartCommand.
+func redactHandoffSecrets(restartCmd string) string {
+	for _, name := range handoffSecretEnvVars {
+		if val := os.Getenv(name); val != "" {
+			restartCmd = strings.ReplaceAll(restartCmd, name+"="+config.ShellQuote(val), name+"=<redacted>")
+		}
+	}
+	return restartCmd
+}
+
+func printHandoffPlan(plan *HandoffPlan) {
+	fmt.Printf("%s\n", style.Bold.Render("Handoff plan"))
+	fmt.Printf("  Role:            %s\n", plan.Role)
+	fmt.Printf("  Session:         %s\n", plan.Session)
+	fmt.Printf("  Working dir:     %s\n", orNone(plan.WorkDir))
+	fmt.Printf("  Agent:           %s\n", orNone(plan.Agent))
+	if plan.Model != "" {
+		fmt.Printf("  Model:           %s\n", plan.Model)
+	}
+	if plan.Resume {
+		fmt.Printf("  Resume:          yes (%s)\n", plan.ResumeFlag)
+	} else {
+		fmt.Printf("  Resume:          no\n")
+	}
+	fmt.Printf("  Restart command: %s\n", plan.RestartCommand)
+}
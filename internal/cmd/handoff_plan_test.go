@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func setupHandoffPlanTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	for _, name := range []string{"GT_AGENT", "GT_ROLE", "GT_RIG", "GT_CREW", "GT_TOWN_ROOT", "GT_ROOT", "GT_RESUME_FLAG"} {
+		t.Setenv(name, "")
+	}
+	return townRoot
+}
+
+func TestBuildHandoffPlan(t *testing.T) {
+	townRoot := setupHandoffPlanTown(t)
+	t.Setenv("GT_RIG", "gastown")
+	t.Setenv("GT_CREW", "max")
+	t.Setenv("ANTHROPIC_API_KEY", "sk-secret")
+
+	tests := []struct {
+		role        string
+		wantSession string
+		wantWorkDir string
+	}{
+		{"mayor", "hq-mayor", "mayor"},
+		{"deacon", "hq-deacon", "deacon"},
+		{"witness", "gt-gastown-witness", "gastown/witness"},
+		{"refinery", "gt-gastown-refinery", "gastown/refinery/rig"},
+		{"crew", "gt-gastown-crew-max", "gastown/crew/max"},
+		{"gastown/polecats/nux", "gt-gastown-nux", "gastown/polecats/nux"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			plan, err := buildHandoffPlan(tt.role)
+			if err != nil {
+				t.Fatalf("buildHandoffPlan(%q): %v", tt.role, err)
+			}
+			wantDir := filepath.Join(townRoot, tt.wantWorkDir)
+			if plan.Session != tt.wantSession || plan.WorkDir != wantDir {
+				t.Errorf("plan session/dir = %s %s, want %s %s", plan.Session, plan.WorkDir, tt.wantSession, wantDir)
+			}
+			if plan.Agent != "claude" || !plan.Resume || plan.ResumeFlag != "--resume" {
+				t.Errorf("plan agent = %s resume=%v %q, want claude with --resume", plan.Agent, plan.Resume, plan.ResumeFlag)
+			}
+			if !strings.HasPrefix(plan.RestartCommand, "cd "+config.ShellQuote(wantDir)+" && ") {
+				t.Errorf("restart command = %q, want it to cd to %s", plan.RestartCommand, wantDir)
+			}
+			if strings.Contains(plan.RestartCommand, "sk-secret") || !strings.Contains(plan.RestartCommand, "ANTHROPIC_API_KEY=<redacted>") {
+				t.Errorf("restart command = %q, want the API key redacted", plan.RestartCommand)
+			}
+		})
+	}
+}
+
+func TestBuildHandoffPlan_ResumeFlag(t *testing.T) {
+	setupHandoffPlanTown(t)
+	t.Cleanup(func() { handoffResume = "" })
+
+	handoffResume = "--continue"
+	plan, err := buildHandoffPlan("mayor")
+	if err != nil {
+		t.Fatalf("buildHandoffPlan: %v", err)
+	}
+	if !plan.Resume || plan.ResumeFlag != "--continue" {
+		t.Errorf("plan resume=%v %q, want the --continue override", plan.Resume, plan.ResumeFlag)
+	}
+
+	// The override also replaces a subcommand-style agent's resume flag
+	t.Setenv("GT_AGENT", "opencode")
+	if plan, err = buildHandoffPlan("mayor"); err != nil {
+		t.Fatalf("buildHandoffPlan: %v", err)
+	}
+	if !plan.Resume || plan.ResumeFlag != "--continue" {
+		t.Errorf("opencode plan resume=%v %q, want the --continue override", plan.Resume, plan.ResumeFlag)
+	}
+
+	// The successor session keeps the override for gt resume
+	exports := strings.Join(handoffPassthroughExports("opencode"), " ")
+	if !strings.Contains(exports, "GT_RESUME_FLAG=--continue") {
+		t.Errorf("exports %q missing GT_RESUME_FLAG=--continue", exports)
+	}
+}
+
+func TestBuildHandoffPlan_Errors(t *testing.T) {
+	setupHandoffPlanTown(t)
+
+	if _, err := buildHandoffPlan("crew"); err == nil || !strings.Contains(err.Error(), "cannot determine crew identity") {
+		t.Errorf("crew without rig context: err = %v, want crew identity error", err)
+	}
+	// No tmux to consult, so a near-miss is always a typo
+	if _, err := buildHandoffPlan("mayr"); err == nil || !strings.Contains(err.Error(), "did you mean 'mayor'") {
+		t.Errorf("typo: err = %v, want role suggestion", err)
+	}
+
+	t.Setenv("GT_AGENT", "no-such-agent")
+	if _, err := buildHandoffPlan("mayor"); err == nil {
+		t.Error("unknown GT_AGENT: want error")
+	}
+}
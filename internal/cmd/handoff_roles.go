@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// customRoleWarnings records the custom role errors already reported, so a
+// command that resolves several sessions warns about a bad role once.
+var customRoleWarnings = map[string]bool{}
+
+// customRoles returns the custom roles of the town the current directory is
+// in (see config.TownSettings.Roles), or nil outside a town.
+func customRoles() map[string]*config.CustomRoleConfig {
+	return customRolesIn(detectTownRootFromCwd())
+}
+
+// customRolesIn returns the custom roles of the town at townRoot, warning
+// about invalid ones.
+func customRolesIn(townRoot string) map[string]*config.CustomRoleConfig {
+	if townRoot == "" {
+		return nil
+	}
+	roles, err := config.LoadCustomRoles(townRoot)
+	if err != nil && !customRoleWarnings[err.Error()] {
+		customRoleWarnings[err.Error()] = true
+		style.PrintWarning("custom roles: %v", err)
+	}
+	return roles
+}
+
+// resolveCustomRoleTarget resolves a custom role address to its tmux
+// session: <role>, <rig>/<role>, <role>/<name> or <rig>/<role>/<name>,
+// depending on whether the role's session template has {rig} and {name}.
+// A missing rig comes from GT_RIG. ok is false if input addresses no
+// custom role.
+func resolveCustomRoleTarget(input string) (target string, ok bool, err error) {
+	roles := customRoles()
+	if len(roles) == 0 {
+		return "", false, nil
+	}
+	parts := strings.Split(input, "/")
+	for i, part := range parts {
+		name, found := config.LookupCustomRole(roles, part)
+		if !found {
+			continue
+		}
+		role := config.CustomRole{Role: name, Config: roles[name]}
+		before, after := parts[:i], parts[i+1:]
+		if len(before) > 1 || len(after) > 1 ||
+			(len(before) == 1 && !role.Config.RigScoped()) ||
+			(len(after) == 1 && !role.Config.Named()) {
+			continue
+		}
+
+		if role.Config.RigScoped() {
+			if len(before) == 1 {
+				role.Rig = before[0]
+			} else if role.Rig = os.Getenv("GT_RIG"); role.Rig == "" {
+				return "", true, fmt.Errorf("cannot determine rig for role %s - set GT_RIG or use <rig>/%s", name, part)
+			}
+		}
+		if role.Config.Named() {
+			if len(after) == 0 {
+				return "", true, fmt.Errorf("role %s requires a name: %s/<name>", name, strings.Join(parts[:i+1], "/"))
+			}
+			role.Name = after[0]
+		}
+		return role.SessionName(), true, nil
+	}
+	return "", false, nil
+}
+
+// customRoleNames returns the names and aliases of the custom roles, each
+// mapped to its role name, for typo suggestions.
+func customRoleNames(roles map[string]*config.CustomRoleConfig) map[string]string {
+	names := make(map[string]string)
+	for _, name := range slices.Sorted(maps.Keys(roles)) {
+		names[name] = name
+		for _, alias := range roles[name].Aliases {
+			names[alias] = name
+		}
+	}
+	return names
+}
+
+// buildCustomRoleRestartCommand is buildRestartCommandWithContext for a
+// session of a custom role. The role's restart command, if it has one,
+// replaces the agent command (and the startup beacon with it).
+func buildCustomRoleRestartCommand(role *config.CustomRole, townRoot, context string) (string, error) {
+	runtimeCmd := role.RestartCommand(townRoot)
+	currentAgent := os.Getenv("GT_AGENT")
+	if runtimeCmd == "" {
+		beacon := session.FormatStartupBeacon(session.BeaconConfig{
+			Recipient: role.Address(),
+			Sender:    "self",
+			Topic:     "handoff",
+			Context:   context,
+		})
+		agent := currentAgent
+		if agent == "" {
+			agent = role.Config.Agent
+		}
+		if model := os.Getenv("GT_MODEL"); agent != "" || model != "" {
+			var err error
+			runtimeCmd, err = config.GetRuntimeCommandWithPromptAndOverrides("", beacon, agent, model)
+			if err != nil {
+				return "", fmt.Errorf("resolving agent config: %w", err)
+			}
+		} else {
+			runtimeCmd = config.GetRuntimeCommandWithPrompt("", beacon)
+		}
+	}
+
+	env := role.Env(townRoot)
+	var exports []string
+	for _, k := range slices.Sorted(maps.Keys(env)) {
+		exports = append(exports, k+"="+config.ShellQuote(env[k]))
+	}
+	exports = append(exports, handoffPassthroughExports(currentAgent)...)
+	return fmt.Sprintf("cd %s && export %s && exec %s", config.ShellQuote(role.WorkDir(townRoot)), strings.Join(exports, " "), runtimeCmd), nil
+}
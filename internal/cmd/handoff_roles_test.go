@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// setupCustomRolesTown creates a town with custom roles in its settings and
+// changes into it.
+func setupCustomRolesTown(t *testing.T) string {
+	t.Helper()
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.Roles = map[string]*config.CustomRoleConfig{
+		"auditor":   {Session: "gt-{rig}-auditor", Aliases: []string{"aud"}},
+		"librarian": {Session: "hq-librarian", WorkDir: "{town}/library", RestartCommand: "librarian-agent --town {town}"},
+		"scribe":    {Session: "gt-{rig}-scribe-{name}"},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	return townRoot
+}
+
+func TestResolveRoleToSession_CustomRoles(t *testing.T) {
+	setupCustomRolesTown(t)
+	t.Setenv("GT_RIG", "gastown")
+	noSessions := func(string) bool { return false }
+
+	tests := []struct {
+		role string
+		want string
+	}{
+		{"auditor", "gt-gastown-auditor"},
+		{"aud", "gt-gastown-auditor"},
+		{"beads/auditor", "gt-beads-auditor"},
+		{"librarian", "hq-librarian"},
+		{"gastown/scribe/ann", "gt-gastown-scribe-ann"},
+		{"witness", "gt-gastown-witness"},
+		{"gastown/crew/auditor", "gt-gastown-crew-auditor"},
+	}
+	for _, tt := range tests {
+		got, err := resolveRoleToSessionWith(tt.role, noSessions)
+		if err != nil {
+			t.Errorf("resolveRoleToSessionWith(%q): %v", tt.role, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveRoleToSessionWith(%q) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+
+	if _, err := resolveRoleToSessionWith("scribe", noSessions); err == nil || !strings.Contains(err.Error(), "requires a name") {
+		t.Errorf("scribe without a name: got %v, want requires a name error", err)
+	}
+	if _, err := resolveRoleToSessionWith("auditr", noSessions); err == nil || !strings.Contains(err.Error(), "did you mean 'auditor'") {
+		t.Errorf("typo of a custom role: got %v, want a suggestion", err)
+	}
+}
+
+func TestResolveRoleToSession_CustomRoleNeedsRig(t *testing.T) {
+	setupCustomRolesTown(t)
+	t.Setenv("GT_RIG", "")
+
+	if _, err := resolveRoleToSessionWith("auditor", func(string) bool { return false }); err == nil || !strings.Contains(err.Error(), "GT_RIG") {
+		t.Errorf("got %v, want an error asking for GT_RIG", err)
+	}
+}
+
+func TestSessionWorkDir_CustomRoles(t *testing.T) {
+	townRoot := setupCustomRolesTown(t)
+
+	tests := []struct {
+		session string
+		want    string
+	}{
+		{"gt-gastown-auditor", townRoot + "/gastown/auditor"},
+		{"hq-librarian", townRoot + "/library"},
+		{"gt-gastown-scribe-ann", townRoot + "/gastown/scribe/ann"},
+		{"gt-gastown-crew-auditor", townRoot + "/gastown/crew/auditor"},
+		{"gt-gastown-Toast", townRoot + "/gastown/polecats/Toast"},
+	}
+	for _, tt := range tests {
+		got, err := sessionWorkDir(tt.session, townRoot)
+		if err != nil {
+			t.Errorf("sessionWorkDir(%q): %v", tt.session, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("sessionWorkDir(%q) = %q, want %q", tt.session, got, tt.want)
+		}
+	}
+}
+
+func TestBuildRestartCommand_CustomRoles(t *testing.T) {
+	townRoot := setupCustomRolesTown(t)
+	t.Setenv("GT_AGENT", "")
+
+	t.Run("restart command replaces the agent", func(t *testing.T) {
+		got, err := buildRestartCommand("hq-librarian")
+		if err != nil {
+			t.Fatalf("buildRestartCommand: %v", err)
+		}
+		for _, want := range []string{
+			"cd " + config.ShellQuote(townRoot+"/library") + " && ",
+			"GT_ROLE=librarian",
+			"exec librarian-agent --town " + townRoot,
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("restart command %q does not contain %q", got, want)
+			}
+		}
+	})
+
+	t.Run("default agent with the role's beacon", func(t *testing.T) {
+		got, err := buildRestartCommand("gt-gastown-auditor")
+		if err != nil {
+			t.Fatalf("buildRestartCommand: %v", err)
+		}
+		for _, want := range []string{
+			"cd " + config.ShellQuote(townRoot+"/gastown/auditor") + " && ",
+			"GT_ROLE=gastown/auditor",
+			"GT_RIG=gastown",
+			"gastown/auditor",
+			"claude",
+		} {
+			if !strings.Contains(got, want) {
+				t.Errorf("restart command %q does not contain %q", got, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/watchdog"
+)
+
+// runHandoffSchedule sets (--schedule) or removes (--unschedule) the
+// handoff schedule of the target session, or the current one.
+func runHandoffSchedule(args []string) error {
+	if handoffSchedule != "" && handoffUnschedule {
+		return fmt.Errorf("--schedule and --unschedule are mutually exclusive")
+	}
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return fmt.Errorf("cannot detect town root - run from within a Gas Town workspace")
+	}
+	target, err := handoffScheduleTarget(args)
+	if err != nil {
+		return err
+	}
+
+	if handoffUnschedule {
+		removed, err := watchdog.RemoveSchedule(townRoot, target)
+		if err != nil {
+			return err
+		}
+		if !removed {
+			fmt.Printf("%s has no handoff schedule\n", target)
+			return nil
+		}
+		fmt.Printf("%s Removed the handoff schedule of %s\n", style.Bold.Render("✓"), target)
+		return nil
+	}
+
+	h, err := watchdog.SetSchedule(townRoot, target, handoffSchedule)
+	if err != nil {
+		return err
+	}
+	next, _ := h.Next()
+	fmt.Printf("%s Scheduled handoffs of %s (%s), next at %s\n",
+		style.Bold.Render("✓"), target, h.Spec, next.Local().Format("2006-01-02 15:04"))
+	if running, _, _ := watchdog.IsRunning(townRoot); !running {
+		style.PrintWarning("the watchdog runs scheduled handoffs and is not running; start it with: gt watchdog start")
+	}
+	return nil
+}
+
+// handoffScheduleTarget resolves the session a schedule is for: the named
+// role or session, or the current tmux session.
+func handoffScheduleTarget(args []string) (string, error) {
+	if len(args) > 0 {
+		if looksLikeBeadID(args[0]) {
+			return "", fmt.Errorf("a schedule is for a session, not a bead: give a role or session name")
+		}
+		target, err := resolveRoleToSession(args[0])
+		if err != nil {
+			return "", fmt.Errorf("resolving role: %w", err)
+		}
+		return target, nil
+	}
+	if !tmux.IsInsideTmux() {
+		return "", fmt.Errorf("%w: name the role or session to schedule", ErrNotInTmux)
+	}
+	return getCurrentTmuxSession()
+}
+
+// runHandoffSchedules lists the town's handoff schedules (--schedules).
+func runHandoffSchedules() error {
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return fmt.Errorf("cannot detect town root - run from within a Gas Town workspace")
+	}
+	schedules, err := loadAllSchedules(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(schedules) == 0 {
+		fmt.Println(style.Dim.Render("No handoff schedules"))
+		return nil
+	}
+	printHandoffSchedules(schedules)
+	return nil
+}
+
+// loadAllSchedules returns the town's handoff schedules followed by its
+// pending one-off handoffs.
+func loadAllSchedules(townRoot string) ([]watchdog.HandoffSchedule, error) {
+	schedules, err := watchdog.LoadSchedules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := watchdog.LoadPending(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	return append(schedules, pending...), nil
+}
+
+// printHandoffSchedules prints one line per schedule: session, spec, and
+// the last and next scheduled handoffs.
+func printHandoffSchedules(schedules []watchdog.HandoffSchedule) {
+	const layout = "2006-01-02 15:04"
+	for _, h := range schedules {
+		last := "never"
+		if !h.LastRun.IsZero() {
+			last = h.LastRun.Local().Format(layout)
+		}
+		next := "never"
+		if t, err := h.Next(); err != nil {
+			next = "invalid: " + err.Error()
+		} else if !t.IsZero() {
+			next = t.Local().Format(layout)
+			if t.Before(time.Now()) {
+				next = "due"
+			}
+		}
+		fmt.Printf("  %-28s %-16s last %-16s next %s\n", h.Session, h.Label(), last, next)
+	}
+}
+
+// scheduledHandoff hands off h's session in a gt handoff subprocess, so a
+// scheduled handoff mails, logs and respawns exactly like a manual one.
+func scheduledHandoff(townRoot string, h watchdog.HandoffSchedule) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	reason := h.Reason
+	if reason == "" {
+		reason = "scheduled (" + h.Spec + ")"
+	}
+	cmd := exec.Command(gtPath, "handoff", h.Session, "--no-switch", "--reason", reason) //nolint:gosec // G204: our own binary
+	cmd.Dir = townRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
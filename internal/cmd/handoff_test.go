@@ -1,10 +1,23 @@
 package cmd
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -177,3 +190,992 @@ func TestDetectTownRootFromCwd_EnvFallback(t *testing.T) {
 		}
 	})
 }
+
+func TestCheckHandoffCooldown(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 0, 0, 0, time.Local)
+	history := []townlog.Event{
+		{Timestamp: now.Add(-time.Minute), Type: townlog.EventHandoff, Agent: "mayor"},
+		{Timestamp: now.Add(-3 * time.Second), Type: townlog.EventHandoff, Agent: "mayor"},
+		{Timestamp: now.Add(-2 * time.Second), Type: townlog.EventSpawn, Agent: "deacon"},
+	}
+
+	t.Run("recent handoff is rejected", func(t *testing.T) {
+		err := checkHandoffCooldown(history, "mayor", 10*time.Second, now)
+		if err == nil {
+			t.Fatal("checkHandoffCooldown() = nil, want cooldown error")
+		}
+		if !strings.Contains(err.Error(), "retry in 7s") {
+			t.Errorf("error %q should say how long until the cooldown expires", err)
+		}
+	})
+
+	t.Run("handoff past the window proceeds", func(t *testing.T) {
+		if err := checkHandoffCooldown(history, "mayor", 10*time.Second, now.Add(8*time.Second)); err != nil {
+			t.Errorf("checkHandoffCooldown() = %v, want nil", err)
+		}
+	})
+
+	t.Run("other agents are unaffected", func(t *testing.T) {
+		if err := checkHandoffCooldown(history, "deacon", 10*time.Second, now); err != nil {
+			t.Errorf("checkHandoffCooldown(deacon) = %v, want nil (only spawn events)", err)
+		}
+	})
+
+	t.Run("zero cooldown disables the check", func(t *testing.T) {
+		if err := checkHandoffCooldown(history, "mayor", 0, now); err != nil {
+			t.Errorf("checkHandoffCooldown(cooldown=0) = %v, want nil", err)
+		}
+	})
+}
+
+func TestCheckHandoffCooldown_FromTownLog(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := LogHandoff(townRoot, "gastown/crew/max", "cycling"); err != nil {
+		t.Fatalf("LogHandoff: %v", err)
+	}
+	history, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+
+	if err := checkHandoffCooldown(history, "gastown/crew/max", 10*time.Second, time.Now()); err == nil {
+		t.Error("expected cooldown error right after a logged handoff")
+	}
+	if err := checkHandoffCooldown(history, "gastown/crew/max", 10*time.Second, time.Now().Add(15*time.Second)); err != nil {
+		t.Errorf("expected handoff to proceed after the window, got %v", err)
+	}
+}
+
+func TestLoadHandoffCooldown(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := loadHandoffCooldown(townRoot); got != config.DefaultHandoffCooldown {
+		t.Errorf("loadHandoffCooldown() without settings = %v, want %v", got, config.DefaultHandoffCooldown)
+	}
+
+	settings := config.NewTownSettings()
+	settings.Handoff = &config.HandoffConfig{Cooldown: "30s"}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	if got := loadHandoffCooldown(townRoot); got != 30*time.Second {
+		t.Errorf("loadHandoffCooldown() = %v, want 30s", got)
+	}
+}
+
+// fakeTmuxRunner records tmux invocations and reports every session in
+// existing as present.
+type fakeTmuxRunner struct {
+	existing map[string]bool
+	calls    [][]string
+}
+
+func (f *fakeTmuxRunner) run(args ...string) (string, string, error) {
+	f.calls = append(f.calls, args)
+	switch args[0] {
+	case "has-session", "kill-session", "list-panes":
+		name := strings.TrimPrefix(args[2], "=")
+		if !f.existing[name] {
+			return "", "can't find session: " + name, errors.New("exit status 1")
+		}
+	}
+	return "", "", nil
+}
+
+func TestHandoffKillSession_ResolvesRoleThenKills(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	origForce := handoffForce
+	defer func() { handoffForce = origForce }()
+	handoffForce = true
+
+	target, err := resolveRoleToSession("mayor")
+	if err != nil {
+		t.Fatalf("resolveRoleToSession: %v", err)
+	}
+
+	fake := &fakeTmuxRunner{existing: map[string]bool{target: true}}
+	if err := handoffKillSession(tmux.NewTmux(tmux.WithRunner(fake.run)), target); err != nil {
+		t.Fatalf("handoffKillSession: %v", err)
+	}
+
+	last := strings.Join(fake.calls[len(fake.calls)-1], " ")
+	if want := "kill-session -t " + target; last != want {
+		t.Errorf("last tmux call = %q, want %q", last, want)
+	}
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Type != townlog.EventKill {
+		t.Errorf("expected a single kill event, got %+v", events)
+	}
+}
+
+func TestHandoffKillSession_DryRun(t *testing.T) {
+	origForce := handoffForce
+	defer func() { handoffForce = origForce }()
+	handoffForce = false // dry run must not prompt
+
+	var out bytes.Buffer
+	fake := &fakeTmuxRunner{existing: map[string]bool{"hq-deacon": true}}
+	tm := tmux.NewTmux(tmux.WithRunner(fake.run), tmux.WithDryRun(&out))
+	if err := handoffKillSession(tm, "hq-deacon"); err != nil {
+		t.Fatalf("handoffKillSession: %v", err)
+	}
+	for _, call := range fake.calls {
+		if call[0] == "kill-session" {
+			t.Errorf("dry run executed %v", call)
+		}
+	}
+	if !strings.Contains(out.String(), "Would execute: tmux kill-session -t hq-deacon") {
+		t.Errorf("dry run output = %q, want kill-session report", out.String())
+	}
+}
+
+func TestHandoffKillSession_NotFound(t *testing.T) {
+	origForce := handoffForce
+	defer func() { handoffForce = origForce }()
+	handoffForce = true
+
+	fake := &fakeTmuxRunner{}
+	err := handoffKillSession(tmux.NewTmux(tmux.WithRunner(fake.run)), "gt-nope-witness")
+	if !errors.Is(err, tmux.ErrSessionNotFound) {
+		t.Fatalf("expected ErrSessionNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "gt-nope-witness") {
+		t.Errorf("error %q should name the session", err)
+	}
+}
+
+func TestGraceTimeoutResolution(t *testing.T) {
+	townRoot := t.TempDir()
+	if got := loadHandoffConfig(townRoot).GetGraceTimeout(); got != config.DefaultHandoffGraceTimeout {
+		t.Errorf("grace timeout without settings = %v, want %v", got, config.DefaultHandoffGraceTimeout)
+	}
+
+	settings := config.NewTownSettings()
+	settings.Handoff = &config.HandoffConfig{GraceTimeout: "12s"}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	configured := loadHandoffConfig(townRoot).GetGraceTimeout()
+	if configured != 12*time.Second {
+		t.Errorf("grace timeout from settings = %v, want 12s", configured)
+	}
+
+	if got := resolveGraceTimeout(0, configured); got != 12*time.Second {
+		t.Errorf("resolveGraceTimeout(no flag) = %v, want 12s", got)
+	}
+	if got := resolveGraceTimeout(3*time.Second, configured); got != 3*time.Second {
+		t.Errorf("resolveGraceTimeout(--grace 3s) = %v, want flag to win", got)
+	}
+}
+
+func TestSuggestHandoffRole(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"mayr", "mayor"},
+		{"MAYRO", "mayor"},
+		{"deacn", "deacon"},
+		{"witnes", "witness"},
+		{"wtness", "witness"},
+		{"refinry", "refinery"},
+		{"crw", "crew"},
+		{"gt-gastown-crew-max", ""},
+		{"hq-mayor", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := suggestHandoffRole(tt.input); got != tt.want {
+				t.Errorf("suggestHandoffRole(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveRoleToSession_TypoSuggestsRole(t *testing.T) {
+	_, err := resolveRoleToSession("mayr")
+	if err == nil {
+		t.Fatal("expected error for typo'd role")
+	}
+	if want := "unknown role 'mayr', did you mean 'mayor'?"; err.Error() != want {
+		t.Errorf("error = %q, want %q", err, want)
+	}
+}
+
+func TestResolveLegacySession(t *testing.T) {
+	// Only the session started under the legacy prefix is running
+	exists := func(s string) bool { return s == "gt-gastown-witness" }
+
+	var got string
+	out := captureStdout(t, func() {
+		got = resolveLegacySession("acme-gastown-witness", "acme-", exists)
+	})
+	if got != "gt-gastown-witness" {
+		t.Errorf("resolveLegacySession() = %q, want the legacy session", got)
+	}
+	if !strings.Contains(out, "deprecated") || !strings.Contains(out, "acme-gastown-witness") {
+		t.Errorf("output = %q, want a deprecation note naming the new session", out)
+	}
+
+	out = captureStdout(t, func() {
+		got = resolveLegacySession("gt-gastown-witness", session.Prefix, exists)
+	})
+	if got != "gt-gastown-witness" || out != "" {
+		t.Errorf("current prefix: got %q with output %q, want it unchanged and silent", got, out)
+	}
+}
+
+func TestHandoffKillSession_ServerDown(t *testing.T) {
+	tm := tmux.NewTmux(tmux.WithRunner(func(args ...string) (string, string, error) {
+		return "", "no server running on /tmp/tmux-1000/default", errors.New("exit status 1")
+	}))
+
+	err := handoffKillSession(tm, "hq-mayor")
+	if !errors.Is(err, tmux.ErrNoServer) {
+		t.Fatalf("expected ErrNoServer, got %v", err)
+	}
+	if errors.Is(err, tmux.ErrSessionNotFound) {
+		t.Errorf("server-down error should not claim the session is missing: %v", err)
+	}
+}
+
+func TestResolveRestartCommand(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+
+	t.Run("unknown session type without override", func(t *testing.T) {
+		_, err := resolveRestartCommand("scratch", "")
+		if err == nil || !strings.Contains(err.Error(), "unknown session type") {
+			t.Fatalf("expected unknown session type error, got %v", err)
+		}
+	})
+
+	t.Run("override bypasses pattern matching", func(t *testing.T) {
+		got, err := resolveRestartCommand("scratch", "  cd /tmp && exec my-agent --flag  ")
+		if err != nil {
+			t.Fatalf("resolveRestartCommand: %v", err)
+		}
+		if want := "cd /tmp && exec my-agent --flag"; got != want {
+			t.Errorf("restart command = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("override used for known roles too", func(t *testing.T) {
+		got, err := resolveRestartCommand("hq-mayor", "exec custom")
+		if err != nil || got != "exec custom" {
+			t.Errorf("resolveRestartCommand(hq-mayor) = %q, %v; want override", got, err)
+		}
+	})
+}
+
+// slowTmuxRunner is a concurrency-safe fake that treats the sessions in
+// delays as running and sleeps for each session's delay on respawn-pane,
+// recording the peak number of respawns in flight.
+type slowTmuxRunner struct {
+	delays map[string]time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+	respawns []string
+}
+
+func (f *slowTmuxRunner) run(args ...string) (string, string, error) {
+	switch args[0] {
+	case "has-session":
+		name := strings.TrimPrefix(args[2], "=")
+		if _, ok := f.delays[name]; !ok {
+			return "", "can't find session: " + name, errors.New("exit status 1")
+		}
+	case "list-panes":
+		if args[len(args)-1] == "#{pane_id}" {
+			return "%" + args[2], "", nil
+		}
+	case "respawn-pane":
+		session := strings.TrimPrefix(args[3], "%")
+		f.mu.Lock()
+		f.inFlight++
+		if f.inFlight > f.peak {
+			f.peak = f.inFlight
+		}
+		f.mu.Unlock()
+
+		time.Sleep(f.delays[session])
+
+		f.mu.Lock()
+		f.inFlight--
+		f.respawns = append(f.respawns, session)
+		f.mu.Unlock()
+	}
+	return "", "", nil
+}
+
+func TestRunParallelHandoffs_AggregatesInSessionOrder(t *testing.T) {
+	// Later names finish first, so completion order is the reverse of
+	// session order.
+	fake := &slowTmuxRunner{delays: map[string]time.Duration{
+		"gt-a-crew-amy": 60 * time.Millisecond,
+		"gt-a-crew-bob": 40 * time.Millisecond,
+		"gt-a-witness":  20 * time.Millisecond,
+		"hq-mayor":      0,
+	}}
+	newTmux := func(w io.Writer) *tmux.Tmux { return tmux.NewTmux(tmux.WithRunner(fake.run)) }
+
+	tasks := []handoffTask{
+		{session: "hq-mayor", restartCmd: "exec mayor"},
+		{session: "gt-a-witness", restartCmd: "exec witness"},
+		{session: "gt-a-refinery", restartCmd: "exec refinery"}, // not running
+		{session: "gt-a-crew-bob", restartCmd: "exec bob"},
+		{session: "gt-a-crew-amy", restartCmd: "exec amy"},
+	}
+	var finished []string
+	results := runParallelHandoffs(tasks, 2, newTmux, func(r handoffResult) {
+		finished = append(finished, r.session) // Calls are serialized
+	})
+
+	var got []string
+	for _, r := range results {
+		got = append(got, r.session)
+	}
+	want := []string{"gt-a-crew-amy", "gt-a-crew-bob", "gt-a-refinery", "gt-a-witness", "hq-mayor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("result order = %v, want %v", got, want)
+	}
+	slices.Sort(finished)
+	if !reflect.DeepEqual(finished, want) {
+		t.Errorf("progress reported %v, want every session once", finished)
+	}
+
+	for _, r := range results {
+		if r.session == "gt-a-refinery" {
+			if !errors.Is(r.err, tmux.ErrSessionNotFound) {
+				t.Errorf("%s: err = %v, want ErrSessionNotFound", r.session, r.err)
+			}
+			continue
+		}
+		if r.err != nil {
+			t.Errorf("%s: unexpected error %v", r.session, r.err)
+		}
+		if !strings.Contains(r.output, "Handing off "+r.session) {
+			t.Errorf("%s: output %q missing its own progress line", r.session, r.output)
+		}
+	}
+
+	if len(fake.respawns) != 4 {
+		t.Errorf("respawned %v, want 4 sessions", fake.respawns)
+	}
+	if fake.peak > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", fake.peak)
+	}
+	if fake.peak < 2 {
+		t.Errorf("peak concurrency = %d, want respawns to overlap", fake.peak)
+	}
+}
+
+func TestRunParallelHandoffs_DryRunOutputIsDeterministic(t *testing.T) {
+	fake := &slowTmuxRunner{delays: map[string]time.Duration{
+		"gt-a-crew-amy": 30 * time.Millisecond,
+		"gt-a-crew-bob": 10 * time.Millisecond,
+		"hq-deacon":     0,
+	}}
+	newTmux := func(w io.Writer) *tmux.Tmux {
+		return tmux.NewTmux(tmux.WithRunner(fake.run), tmux.WithDryRun(w))
+	}
+	tasks := []handoffTask{
+		{session: "hq-deacon", restartCmd: "exec deacon"},
+		{session: "gt-a-crew-bob", restartCmd: "exec bob"},
+		{session: "gt-a-crew-amy", restartCmd: "exec amy"},
+	}
+
+	var first string
+	for i := 0; i < 3; i++ {
+		var out strings.Builder
+		for _, r := range runParallelHandoffs(tasks, 3, newTmux, nil) {
+			if r.err != nil {
+				t.Fatalf("%s: %v", r.session, r.err)
+			}
+			out.WriteString(r.output)
+		}
+		if i == 0 {
+			first = out.String()
+		} else if out.String() != first {
+			t.Fatalf("dry-run output differs between runs:\n%s\n---\n%s", first, out.String())
+		}
+	}
+
+	if len(fake.respawns) != 0 {
+		t.Errorf("dry run respawned %v", fake.respawns)
+	}
+	amy := strings.Index(first, "Would execute: tmux respawn-pane -k -t %gt-a-crew-amy exec amy")
+	bob := strings.Index(first, "Would execute: tmux respawn-pane -k -t %gt-a-crew-bob exec bob")
+	deacon := strings.Index(first, "Would execute: tmux respawn-pane -k -t %hq-deacon exec deacon")
+	if amy < 0 || bob < 0 || deacon < 0 || !(amy < bob && bob < deacon) {
+		t.Errorf("dry-run output not in session order:\n%s", first)
+	}
+}
+
+func TestHandoffAllTargets(t *testing.T) {
+	tm := tmux.NewTmux(tmux.WithRunner(func(args ...string) (string, string, error) {
+		return "hq-mayor\ngt-a-toast\nscratch\ngt-a-witness\nhq-deacon\ngt-a-crew-max", "", nil
+	}))
+
+	got, err := handoffAllTargets(tm, "gt-a-crew-max", handoffAllFilter{})
+	if err != nil {
+		t.Fatalf("handoffAllTargets: %v", err)
+	}
+	want := []string{"gt-a-witness", "hq-deacon", "hq-mayor"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("handoffAllTargets = %v, want %v (no polecats, self or foreign sessions)", got, want)
+	}
+}
+
+func TestHandoffAllTargets_Filter(t *testing.T) {
+	tm := tmux.NewTmux(tmux.WithRunner(func(args ...string) (string, string, error) {
+		return "hq-mayor\ngt-a-crew-max\ngt-a-crew-sam\ngt-a-witness\ngt-b-crew-joe\ngt-b-toast", "", nil
+	}))
+
+	tests := []struct {
+		filter handoffAllFilter
+		want   []string
+	}{
+		{handoffAllFilter{role: "crew"}, []string{"gt-a-crew-max", "gt-a-crew-sam", "gt-b-crew-joe"}},
+		{handoffAllFilter{rig: "a"}, []string{"gt-a-crew-max", "gt-a-crew-sam", "gt-a-witness"}},
+		{handoffAllFilter{role: "crew", rig: "b"}, []string{"gt-b-crew-joe"}},
+		{handoffAllFilter{role: "mayor"}, []string{"hq-mayor"}},
+		{handoffAllFilter{role: "refinery"}, nil},
+	}
+	for _, tt := range tests {
+		got, err := handoffAllTargets(tm, "", tt.filter)
+		if err != nil {
+			t.Fatalf("handoffAllTargets: %v", err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("handoffAllTargets(%+v) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestHandoffHistoryContext(t *testing.T) {
+	tests := []struct {
+		name                 string
+		subject, reason, cmd string
+		want                 string
+	}{
+		{"no reason", "", "", "exec claude", "cmd: exec claude"},
+		{"with reason", "", "context full", "exec claude", "reason: context full; cmd: exec claude"},
+		{"all fields", "Fix it", "agent stuck", "exec claude", "Fix it; reason: agent stuck; cmd: exec claude"},
+		{"reason newlines flattened", "", "context\nfull\r\n  again", "exec claude", "reason: context full again; cmd: exec claude"},
+		{"multi-line command flattened", "", "", "exec claude \"beacon\n\nCheck your hook\"", "cmd: exec claude \"beacon Check your hook\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := handoffHistoryContext(tt.subject, tt.reason, tt.cmd)
+			if got != tt.want {
+				t.Errorf("handoffHistoryContext() = %q, want %q", got, tt.want)
+			}
+			if strings.ContainsAny(got, "\r\n") {
+				t.Errorf("context %q spans multiple lines", got)
+			}
+		})
+	}
+}
+
+func TestHandoffHistoryRoundTrip(t *testing.T) {
+	townRoot := t.TempDir()
+	ts := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)
+	logger := townlog.NewLogger(townRoot)
+	for _, reason := range []string{"context\nfull", ""} {
+		err := logger.LogEvent(townlog.Event{
+			Timestamp: ts,
+			Type:      townlog.EventHandoff,
+			Agent:     "mayor",
+			Context:   handoffHistoryContext("", reason, "cd /town && exec claude"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	events, err := townlog.ReadEvents(townRoot)
+	if err != nil {
+		t.Fatalf("ReadEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2 (one line each)", len(events))
+	}
+
+	want := []string{
+		"2026-01-02 15:04:05  mayor  reason: context full\n    cd /town && exec claude",
+		"2026-01-02 15:04:05  mayor\n    cd /town && exec claude",
+	}
+	for i, e := range events {
+		if got := formatHandoffHistoryEntry(e); got != want[i] {
+			t.Errorf("entry %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestParseHandoffHistoryContext_LegacySubject(t *testing.T) {
+	subject, reason, cmd := parseHandoffHistoryContext("cycling")
+	if subject != "cycling" || reason != "" || cmd != "" {
+		t.Errorf("parse legacy context = (%q, %q, %q), want bare subject", subject, reason, cmd)
+	}
+}
+
+func TestRunHandoff_NotInTmux(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("GT_POLECAT", "")
+
+	err := runHandoff(handoffCmd, nil)
+	if !errors.Is(err, ErrNotInTmux) {
+		t.Fatalf("runHandoff() outside tmux = %v, want ErrNotInTmux", err)
+	}
+	if code, _ := ExitCode(withHandoffExitCode(err)); code != handoffExitNotInTmux {
+		t.Errorf("exit code = %d, want %d", code, handoffExitNotInTmux)
+	}
+}
+
+func TestRunHandoff_OutsideTmuxNeedsTarget(t *testing.T) {
+	t.Chdir(t.TempDir()) // not a town, so nothing reaches tmux
+	t.Setenv("TMUX", "")
+	t.Setenv("GT_POLECAT", "")
+	t.Setenv("GT_TOWN_ROOT", "")
+	t.Setenv("GT_ROOT", "")
+
+	// Hooking a bead hands off the current session, which needs tmux
+	if err := runHandoff(handoffCmd, []string{"gt-abc"}); !errors.Is(err, ErrNotInTmux) {
+		t.Fatalf("runHandoff(bead) outside tmux = %v, want ErrNotInTmux", err)
+	}
+	// A role target gets past the tmux check (and fails later, outside a town)
+	err := runHandoff(handoffCmd, []string{"gastown/witness"})
+	if err == nil || errors.Is(err, ErrNotInTmux) || !strings.Contains(err.Error(), "town root") {
+		t.Errorf("runHandoff(role) outside tmux = %v, want it to proceed to town detection", err)
+	}
+}
+
+func TestRunHandoff_MissingPaneEnv(t *testing.T) {
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+	t.Setenv("TMUX_PANE", "")
+	t.Setenv("GT_POLECAT", "")
+
+	if err := runHandoff(handoffCmd, nil); !errors.Is(err, ErrNotInTmux) {
+		t.Fatalf("runHandoff() without TMUX_PANE = %v, want ErrNotInTmux", err)
+	}
+}
+
+func TestSelfHandoffPane(t *testing.T) {
+	var calls []string
+	runner := func(args ...string) (string, string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "list-panes":
+			return "%3\n%4\n", "", nil
+		case "display-message":
+			return "%4", "", nil
+		}
+		return "", "", nil
+	}
+	tm := tmux.NewTmux(tmux.WithRunner(runner))
+
+	t.Run("matching pane", func(t *testing.T) {
+		calls = nil
+		pane, err := selfHandoffPane(tm, "%3", "hq-mayor")
+		if err != nil || pane != "%3" {
+			t.Fatalf("selfHandoffPane() = %q, %v; want %%3", pane, err)
+		}
+		if want := []string{"list-panes -s -t hq-mayor -F #{pane_id}"}; !reflect.DeepEqual(calls, want) {
+			t.Errorf("tmux calls = %q, want %q", calls, want)
+		}
+	})
+
+	t.Run("stale pane falls back to active pane", func(t *testing.T) {
+		calls = nil
+		pane, err := selfHandoffPane(tm, "%9", "hq-mayor")
+		if err != nil || pane != "%4" {
+			t.Fatalf("selfHandoffPane() = %q, %v; want %%4", pane, err)
+		}
+		if len(calls) != 2 || calls[1] != "display-message -p -t hq-mayor #{pane_id}" {
+			t.Errorf("tmux calls = %q, want list-panes then display-message", calls)
+		}
+	})
+
+	t.Run("session gone", func(t *testing.T) {
+		noSession := func(args ...string) (string, string, error) {
+			return "", "can't find session: hq-mayor", errors.New("exit status 1")
+		}
+		_, err := selfHandoffPane(tmux.NewTmux(tmux.WithRunner(noSession)), "%3", "hq-mayor")
+		if !errors.Is(err, tmux.ErrSessionNotFound) {
+			t.Errorf("selfHandoffPane() = %v, want ErrSessionNotFound", err)
+		}
+	})
+}
+
+func TestRespawnRemoteSession_FailureModes(t *testing.T) {
+	respawnFails := func(args ...string) (string, string, error) {
+		switch args[0] {
+		case "list-panes":
+			return "%1", "", nil
+		case "respawn-pane":
+			return "", "create pane failed", errors.New("exit status 1")
+		}
+		return "", "", nil
+	}
+	noSession := func(args ...string) (string, string, error) {
+		if args[0] == "has-session" {
+			return "", "can't find session: gt-a-witness", errors.New("exit status 1")
+		}
+		return "", "", nil
+	}
+	noServer := func(args ...string) (string, string, error) {
+		return "", "no server running on /tmp/tmux-1000/default", errors.New("exit status 1")
+	}
+
+	tests := []struct {
+		name     string
+		runner   tmux.Runner
+		wantErr  error
+		wantCode int
+	}{
+		{"respawn fails", respawnFails, ErrRespawnFailed, handoffExitRespawnFailed},
+		{"session missing", noSession, tmux.ErrSessionNotFound, handoffExitSessionNotFound},
+		{"server down", noServer, tmux.ErrNoServer, handoffExitSessionNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := respawnRemoteSession(tmux.NewTmux(tmux.WithRunner(tt.runner)), &out, "gt-a-witness", "exec claude")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("respawnRemoteSession() = %v, want %v", err, tt.wantErr)
+			}
+			if code, _ := ExitCode(withHandoffExitCode(err)); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWithHandoffExitCode(t *testing.T) {
+	if withHandoffExitCode(nil) != nil {
+		t.Error("nil error should stay nil")
+	}
+
+	other := errors.New("hooking bead: boom")
+	if got := withHandoffExitCode(other); got != other {
+		t.Errorf("unclassified error = %v, want it passed through unchanged", got)
+	}
+
+	wrapped := fmt.Errorf("%w: %w", ErrRespawnFailed, errors.New("tmux exploded"))
+	got := withHandoffExitCode(wrapped)
+	if got.Error() != wrapped.Error() {
+		t.Errorf("message = %q, want %q preserved", got.Error(), wrapped.Error())
+	}
+	if !errors.Is(got, ErrRespawnFailed) {
+		t.Error("exit-code error should still match its sentinel")
+	}
+}
+
+// readyAfterRunner fakes a respawned pane that shows prompt from the
+// readyAfter-th capture onwards (never, if readyAfter is 0).
+type readyAfterRunner struct {
+	prompt     string
+	readyAfter int
+
+	mu       sync.Mutex
+	captures int
+	sent     []string // literal send-keys text
+	sentAt   int      // captures seen when text was last sent
+}
+
+func (f *readyAfterRunner) run(args ...string) (string, string, error) {
+	switch args[0] {
+	case "capture-pane":
+	case "list-panes":
+		return "%1", "", nil
+	case "send-keys":
+		if args[3] == "-l" {
+			f.mu.Lock()
+			f.sent = append(f.sent, args[4])
+			f.sentAt = f.captures
+			f.mu.Unlock()
+		}
+		return "", "", nil
+	default:
+		return "", "", nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.captures++
+	if f.readyAfter > 0 && f.captures >= f.readyAfter {
+		return "Session restored\n" + f.prompt, "", nil
+	}
+	return "Reading instructions...", "", nil
+}
+
+func TestHandoffWaiter_ReadyAfterPolls(t *testing.T) {
+	fake := &readyAfterRunner{prompt: "❯ ", readyAfter: 4}
+	w := &handoffWaiter{marker: "❯ ", timeout: time.Second, interval: time.Millisecond}
+
+	var out bytes.Buffer
+	if err := w.await(tmux.NewTmux(tmux.WithRunner(fake.run)), &out, "gt-mayor"); err != nil {
+		t.Fatalf("await() = %v, want nil", err)
+	}
+	if fake.captures != 4 {
+		t.Errorf("captured pane %d times, want 4", fake.captures)
+	}
+	if !strings.Contains(out.String(), "gt-mayor is ready") {
+		t.Errorf("output = %q, want ready notice", out.String())
+	}
+}
+
+func TestHandoffWaiter_Timeout(t *testing.T) {
+	fake := &readyAfterRunner{prompt: "❯ "}
+	w := &handoffWaiter{marker: "❯ ", timeout: 20 * time.Millisecond, interval: time.Millisecond}
+
+	err := w.await(tmux.NewTmux(tmux.WithRunner(fake.run)), io.Discard, "gt-mayor")
+	if !errors.Is(err, ErrNotReady) {
+		t.Fatalf("await() = %v, want ErrNotReady", err)
+	}
+	if code, _ := ExitCode(withHandoffExitCode(err)); code != handoffExitNotReady {
+		t.Errorf("exit code = %d, want %d", code, handoffExitNotReady)
+	}
+	if fake.captures < 2 {
+		t.Errorf("captured pane %d times, want repeated polling", fake.captures)
+	}
+}
+
+func TestHandoffWaiter_NilAndDryRun(t *testing.T) {
+	var nilWaiter *handoffWaiter
+	if err := nilWaiter.await(tmux.NewTmux(), io.Discard, "gt-mayor"); err != nil {
+		t.Errorf("nil waiter await() = %v, want nil", err)
+	}
+
+	var out bytes.Buffer
+	w := &handoffWaiter{marker: "❯ ", timeout: time.Minute, interval: time.Millisecond}
+	if err := w.await(tmux.NewTmux(tmux.WithDryRun(&out)), &out, "gt-mayor"); err != nil {
+		t.Fatalf("dry-run await() = %v, want nil", err)
+	}
+	if want := "Would wait up to 1m0s for gt-mayor to become ready\n"; out.String() != want {
+		t.Errorf("dry-run output = %q, want %q", out.String(), want)
+	}
+}
+
+func TestHandoffWaiter_PrimingPrompt(t *testing.T) {
+	const prompt = "Resume the molecule on your hook."
+	tm := func(f *readyAfterRunner) *tmux.Tmux { return tmux.NewTmux(tmux.WithRunner(f.run)) }
+
+	t.Run("sent once after ready", func(t *testing.T) {
+		fake := &readyAfterRunner{prompt: "❯ ", readyAfter: 3}
+		w := &handoffWaiter{marker: "❯ ", prompt: prompt, timeout: time.Second, interval: time.Millisecond}
+		if err := w.await(tm(fake), io.Discard, "gt-mayor"); err != nil {
+			t.Fatalf("await() = %v, want nil", err)
+		}
+		if len(fake.sent) != 1 || fake.sent[0] != prompt {
+			t.Fatalf("sent = %q, want the priming prompt once", fake.sent)
+		}
+		if fake.sentAt != fake.readyAfter {
+			t.Errorf("prompt sent after %d captures, want after ready (%d)", fake.sentAt, fake.readyAfter)
+		}
+	})
+
+	t.Run("empty sends nothing", func(t *testing.T) {
+		fake := &readyAfterRunner{prompt: "❯ ", readyAfter: 1}
+		w := &handoffWaiter{marker: "❯ ", timeout: time.Second, interval: time.Millisecond}
+		if err := w.await(tm(fake), io.Discard, "gt-mayor"); err != nil {
+			t.Fatalf("await() = %v, want nil", err)
+		}
+		if len(fake.sent) != 0 {
+			t.Errorf("sent = %q, want nothing", fake.sent)
+		}
+	})
+
+	t.Run("rendered from prompt template", func(t *testing.T) {
+		town := t.TempDir()
+		dir := filepath.Join(town, session.PromptsDir)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		tmpl := "Patrol {{.Rig}} with {{.Agent}}. {{.Default}}"
+		if err := os.WriteFile(filepath.Join(dir, "witness.tmpl"), []byte(tmpl), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fake := &readyAfterRunner{prompt: "❯ ", readyAfter: 1}
+		w := &handoffWaiter{marker: "❯ ", prompt: prompt, townRoot: town, agent: "kimi", timeout: time.Second, interval: time.Millisecond}
+		if err := w.await(tm(fake), io.Discard, "gt-gastown-witness"); err != nil {
+			t.Fatalf("await() = %v, want nil", err)
+		}
+		want := "Patrol gastown with kimi. " + prompt
+		if len(fake.sent) != 1 || fake.sent[0] != want {
+			t.Errorf("sent = %q, want %q", fake.sent, want)
+		}
+	})
+
+	t.Run("not sent when never ready", func(t *testing.T) {
+		fake := &readyAfterRunner{prompt: "❯ "}
+		w := &handoffWaiter{marker: "❯ ", prompt: prompt, timeout: 10 * time.Millisecond, interval: time.Millisecond}
+		if err := w.await(tm(fake), io.Discard, "gt-mayor"); !errors.Is(err, ErrNotReady) {
+			t.Fatalf("await() = %v, want ErrNotReady", err)
+		}
+		if len(fake.sent) != 0 {
+			t.Errorf("sent = %q, want nothing", fake.sent)
+		}
+	})
+}
+
+func TestRunParallelHandoffs_WaitsForEachSession(t *testing.T) {
+	fake := &readyAfterRunner{prompt: "> ", readyAfter: 3}
+	w := &handoffWaiter{marker: "> ", timeout: time.Second, interval: time.Millisecond}
+	tasks := []handoffTask{{session: "gt-gastown-witness", restartCmd: "exec kimi", wait: w}}
+	newTmux := func(io.Writer) *tmux.Tmux { return tmux.NewTmux(tmux.WithRunner(fake.run)) }
+
+	results := runParallelHandoffs(tasks, 1, newTmux, nil)
+	if len(results) != 1 || results[0].err != nil {
+		t.Fatalf("results = %+v, want one success", results)
+	}
+	if !strings.Contains(results[0].output, "gt-gastown-witness is ready") {
+		t.Errorf("output = %q, want ready notice", results[0].output)
+	}
+}
+
+func TestHandoffRemoteSession_SwitchPerFlags(t *testing.T) {
+	t.Chdir(t.TempDir()) // not a town: nothing is logged
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	origWatch, origNoSwitch := handoffWatch, handoffNoSwitch
+	defer func() { handoffWatch, handoffNoSwitch = origWatch, origNoSwitch }()
+
+	tests := []struct {
+		name       string
+		watch      bool
+		noSwitch   bool
+		wantSwitch bool
+	}{
+		{"default", false, false, false},
+		{"watch", true, false, true},
+		{"no-switch", false, true, false},
+		{"no-switch overrides watch", true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handoffWatch, handoffNoSwitch = tt.watch, tt.noSwitch
+
+			var switched bool
+			runner := func(args ...string) (string, string, error) {
+				switch args[0] {
+				case "list-panes":
+					return "%1", "", nil
+				case "switch-client":
+					switched = true
+				}
+				return "", "", nil
+			}
+			tm := tmux.NewTmux(tmux.WithRunner(runner), tmux.WithKillGracePeriod(time.Millisecond))
+			if err := handoffRemoteSession(tm, "gt-gastown-witness", "exec claude", nil, handoffShouldSwitch()); err != nil {
+				t.Fatalf("handoffRemoteSession: %v", err)
+			}
+			if switched != tt.wantSwitch {
+				t.Errorf("switch-client invoked = %v, want %v", switched, tt.wantSwitch)
+			}
+		})
+	}
+}
+
+func TestBuildRestartCommand_QuotesShellValues(t *testing.T) {
+	townRoot := filepath.Join(t.TempDir(), "my town")
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(townRoot, "mayor", "town.json"), []byte(`{"name":"test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(townRoot)
+	t.Setenv("GT_AGENT", "")
+	t.Setenv("ANTHROPIC_API_KEY", `k$y"it's;`)
+
+	got, err := buildRestartCommand(getMayorSessionName())
+	if err != nil {
+		t.Fatalf("buildRestartCommand: %v", err)
+	}
+	for _, want := range []string{
+		"cd " + config.ShellQuote(townRoot+"/mayor") + " && ",
+		"GT_ROOT=" + config.ShellQuote(townRoot),
+		`ANTHROPIC_API_KEY='k$y"it'\''s;'`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("restart command missing %q:\n%s", want, got)
+		}
+	}
+}
+
+// fakeMux is a non-tmux Multiplexer that records respawns and kills.
+type fakeMux struct {
+	tmux.Multiplexer
+	sessions  []string
+	respawned map[string]string
+	killed    []string
+}
+
+func (m *fakeMux) HasSession(name string) (bool, error) {
+	return slices.Contains(m.sessions, name), nil
+}
+
+func (m *fakeMux) RespawnPane(pane, command string) error {
+	m.respawned[pane] = command
+	return nil
+}
+
+func (m *fakeMux) KillSession(name string) error {
+	m.killed = append(m.killed, name)
+	return nil
+}
+
+func TestHandoffMultiplexer(t *testing.T) {
+	t.Chdir(t.TempDir()) // not a town: nothing is logged
+	t.Setenv("GT_SESSION", "gt-gastown-crew-max")
+	origRestart, origKill, origForce := handoffRestart, handoffKill, handoffForce
+	defer func() { handoffRestart, handoffKill, handoffForce = origRestart, origKill, origForce }()
+	handoffRestart, handoffForce = "exec claude", true
+
+	mux := &fakeMux{sessions: []string{"gt-gastown-crew-max", "gt-gastown-witness"}, respawned: map[string]string{}}
+	if err := handoffMultiplexer(mux, []string{"gastown/witness"}); err != nil {
+		t.Fatalf("handoffMultiplexer(witness): %v", err)
+	}
+	if got := mux.respawned["gt-gastown-witness"]; got != "exec claude" {
+		t.Errorf("witness respawned with %q, want %q", got, "exec claude")
+	}
+
+	if err := handoffMultiplexer(mux, []string{"gastown/refinery"}); !errors.Is(err, tmux.ErrSessionNotFound) {
+		t.Errorf("handoffMultiplexer(missing) = %v, want ErrSessionNotFound", err)
+	}
+
+	handoffKill = true
+	if err := handoffMultiplexer(mux, []string{"gastown/witness"}); err != nil {
+		t.Fatalf("handoffMultiplexer --kill: %v", err)
+	}
+	if !slices.Equal(mux.killed, []string{"gt-gastown-witness"}) {
+		t.Errorf("killed = %q, want the witness", mux.killed)
+	}
+
+	t.Setenv("GT_SESSION", "")
+	t.Setenv("ZELLIJ_SESSION_NAME", "")
+	if err := handoffMultiplexer(mux, nil); !errors.Is(err, ErrNotInTmux) {
+		t.Errorf("handoffMultiplexer() without GT_SESSION = %v, want ErrNotInTmux", err)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -264,6 +265,8 @@ func runHook(_ *cobra.Command, args []string) error {
 	if err := events.LogFeed(events.TypeHook, agentID, events.HookPayload(beadID)); err != nil {
 		fmt.Fprintf(os.Stderr, "%s Warning: failed to log hook event: %v\n", style.Dim.Render("⚠"), err)
 	}
+	hooks.Fire("", hooks.BeadAssigned, map[string]string{"bead": beadID, "agent": agentID, "actor": agentID})
+	advanceBeadStage("", beadID, beads.StageAssigned, agentID, "gt hook")
 
 	return nil
 }
@@ -33,6 +33,9 @@ Hook types:
   PostToolUse      - Runs after tool execution
   Stop             - Runs when Claude session stops
 
+Gas Town's own lifecycle events (session-start, bead-completed, ...) run
+user scripts from .gastown/hooks/<event>.d/; see gt hooks events.
+
 Examples:
   gt hooks              # List all hooks in workspace
   gt hooks --verbose    # Show hook commands
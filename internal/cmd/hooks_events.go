@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var hooksEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "List lifecycle events and the scripts hooked to them",
+	Long: `List the Gas Town lifecycle events and the user scripts run on each.
+
+Scripts are the executable files in .gastown/hooks/<event>.d/, under the
+town root or your home directory, run in name order (home first). Each gets
+GT_HOOK_EVENT, GT_TOWN_ROOT and GT_HOOK_<FIELD> environment variables, and
+the event as JSON on stdin. A script has 30s; failures are reported but
+never fail the command that fired the event.
+
+Events:
+  session-start   An agent session primed (agent, session_id, role, rig, work_dir)
+  bead-assigned   Work was slung or hooked (bead, agent, actor, formula)
+  bead-completed  Work finished via gt done or a molecule's last step (bead, agent, branch, source)
+  handoff-context Print the outgoing session's summary for gt handoff --with-context (agent, session)
+  handoff         A session handed off (agent, session, subject, reason, self)
+  session-end     A session was shut down (session, agent, reason)
+  agent-crash     An agent exited unexpectedly (agent, session, exit_code, bead)
+  report-summary  Print a summary of a session's pane for gt witness report (rig, session, agent, status, tail)
+  witness-report  A witness report was written (rig, file, report)
+
+Examples:
+  mkdir -p ~/gt/.gastown/hooks/bead-completed.d
+  cp slack.sh ~/gt/.gastown/hooks/bead-completed.d/10-slack.sh
+  gt hooks events`,
+	Args: cobra.NoArgs,
+	RunE: runHooksEvents,
+}
+
+var hooksFireCmd = &cobra.Command{
+	Use:   "fire <event> [field=value...]",
+	Short: "Run the scripts hooked to an event, to test them",
+	Long: `Run the scripts hooked to a lifecycle event with the given payload
+fields, printing their output.
+
+Examples:
+  gt hooks fire bead-completed bead=gt-abc agent=gastown/crew/max`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runHooksFire,
+}
+
+func init() {
+	hooksCmd.AddCommand(hooksEventsCmd, hooksFireCmd)
+}
+
+func runHooksEvents(cmd *cobra.Command, args []string) error {
+	townRoot, _ := workspace.FindFromCwd()
+	for _, event := range hooks.Events {
+		scripts := hooks.Scripts(townRoot, event)
+		fmt.Printf("%s %s\n", style.Bold.Render(string(event)), style.Dim.Render(fmt.Sprintf("(%d)", len(scripts))))
+		for _, script := range scripts {
+			fmt.Printf("  %s\n", script)
+		}
+	}
+	return nil
+}
+
+func runHooksFire(cmd *cobra.Command, args []string) error {
+	event := hooks.Event(args[0])
+	if !event.Valid() {
+		names := make([]string, len(hooks.Events))
+		for i, e := range hooks.Events {
+			names[i] = string(e)
+		}
+		return fmt.Errorf("unknown event %q (want one of: %s)", args[0], strings.Join(names, ", "))
+	}
+	fields := make(map[string]string)
+	for _, arg := range args[1:] {
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok || k == "" {
+			return fmt.Errorf("invalid field %q (want field=value)", arg)
+		}
+		fields[k] = v
+	}
+
+	townRoot, _ := workspace.FindFromCwd()
+	scripts := hooks.Scripts(townRoot, event)
+	if len(scripts) == 0 {
+		fmt.Printf("No scripts hooked to %s\n", event)
+		return nil
+	}
+	payload := hooks.Payload{Event: event, Time: time.Now().UTC(), TownRoot: townRoot, Fields: fields}
+	var failed int
+	for _, script := range scripts {
+		fmt.Printf("%s %s\n", style.Bold.Render("▶"), script)
+		if err := hooks.Run(script, payload, os.Stdout); err != nil {
+			failed++
+			fmt.Printf("  %s %v\n", style.Error.Render("✗"), err)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d scripts failed", failed, len(scripts))
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/kimi"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -242,7 +243,15 @@ func installHookTo(worktreePath string, hookDef HookDefinition, dryRun bool) err
 		}
 	}
 
+	// Kimi agents read a translated copy of the same hooks
+	kimiSettingsPath := filepath.Join(worktreePath, ".kimi", "settings.json")
+	_, err := os.Stat(kimiSettingsPath)
+	hasKimi := err == nil
+
 	if dryRun {
+		if hasKimi {
+			relPath += " (and .kimi)"
+		}
 		fmt.Printf("  %s %s\n", style.Dim.Render("Would install to:"), relPath)
 		return nil
 	}
@@ -261,6 +270,14 @@ func installHookTo(worktreePath string, hookDef HookDefinition, dryRun bool) err
 	if err := os.WriteFile(settingsPath, data, 0600); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
+	if hasKimi {
+		for _, matcher := range hookDef.Matchers {
+			if err := kimi.AddHook(kimiSettingsPath, hookDef.Event, matcher, hookDef.Command); err != nil {
+				return fmt.Errorf("installing kimi hook: %w", err)
+			}
+		}
+		relPath += " (and .kimi)"
+	}
 
 	fmt.Printf("  %s %s\n", style.Success.Render("Installed to:"), relPath)
 	return nil
@@ -212,3 +212,18 @@ func TestDiscoverHooksCrewLevel(t *testing.T) {
 		t.Error("expected polecats-level hook to be discovered (testrig/polecats)")
 	}
 }
+
+func TestRunHooksFireArgs(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	if err := runHooksFire(hooksFireCmd, []string{"bead-finished"}); err == nil {
+		t.Error("unknown event accepted")
+	}
+	if err := runHooksFire(hooksFireCmd, []string{"bead-completed", "gt-abc"}); err == nil {
+		t.Error("field without = accepted")
+	}
+	if err := runHooksFire(hooksFireCmd, []string{"bead-completed", "bead=gt-abc"}); err != nil {
+		t.Errorf("runHooksFire with no scripts: %v", err)
+	}
+}
@@ -14,7 +14,10 @@ import (
 	"github.com/steveyegge/gastown/internal/style"
 )
 
-var initForce bool
+var (
+	initForce  bool
+	initWizard bool
+)
 
 var initCmd = &cobra.Command{
 	Use:     "init",
@@ -26,16 +29,30 @@ This creates the standard agent directories (polecats/, witness/, refinery/,
 mayor/) and updates .git/info/exclude to ignore them.
 
 The current directory must be a git repository. Use --force to reinitialize
-an existing rig structure.`,
+an existing rig structure.
+
+New to Gas Town? gt init --wizard sets up a town step by step: it checks
+which agent CLIs, tmux and git are installed, creates an HQ if you aren't
+in one, asks which agent to use by default, and starts the deacon and mayor.
+Answers are read from stdin, so piping in nothing takes every default.
+
+Examples:
+  gt init                  # Initialize this repo as a rig
+  gt init --wizard         # Set up Gas Town interactively`,
 	RunE: runInit,
 }
 
 func init() {
 	initCmd.Flags().BoolVarP(&initForce, "force", "f", false, "Reinitialize existing structure")
+	initCmd.Flags().BoolVarP(&initWizard, "wizard", "w", false, "Set up a town interactively: detect agents, write config, start the mayor and deacon")
 	rootCmd.AddCommand(initCmd)
 }
 
 func runInit(cmd *cobra.Command, args []string) error {
+	if initWizard {
+		return runInitWizard(cmd)
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return fmt.Errorf("getting current directory: %w", err)
@@ -44,7 +61,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	// Check if it's a git repository
 	g := git.NewGit(cwd)
 	if _, err := g.CurrentBranch(); err != nil {
-		return fmt.Errorf("not a git repository (run 'git init' first)")
+		return fmt.Errorf("not a git repository (run 'git init' first, or 'gt init --wizard' to set up a town)")
 	}
 
 	// Check if already initialized
@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/deacon"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// detectedAgent is an agent preset and where its CLI is on PATH ("" when
+// it isn't installed).
+type detectedAgent struct {
+	Name string
+	Path string
+}
+
+// initEnvironment is what gt init --wizard found on this machine.
+type initEnvironment struct {
+	Agents   []detectedAgent // Every preset, sorted by name
+	Tmux     string          // tmux -V output, "" when tmux isn't installed
+	GitRepo  string          // Top of the git repo around cwd, if any
+	TownRoot string          // Town around cwd, if any
+}
+
+// installedAgents returns the names of the agents whose CLI is on PATH.
+func (e initEnvironment) installedAgents() []string {
+	var names []string
+	for _, a := range e.Agents {
+		if a.Path != "" {
+			names = append(names, a.Name)
+		}
+	}
+	return names
+}
+
+// detectInitEnvironment looks for agent CLIs, tmux, and a git repo and town
+// around cwd.
+func detectInitEnvironment(cwd string) initEnvironment {
+	var env initEnvironment
+	env.TownRoot, _ = workspace.Find(cwd)
+	if env.TownRoot != "" {
+		// Custom agents of the town count too
+		_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(env.TownRoot))
+	}
+	for _, name := range config.ListAgentPresets() {
+		a := detectedAgent{Name: name}
+		if preset := config.GetAgentPresetByName(name); preset != nil && preset.Command != "" {
+			a.Path, _ = exec.LookPath(preset.Command)
+		}
+		env.Agents = append(env.Agents, a)
+	}
+	sort.Slice(env.Agents, func(i, j int) bool { return env.Agents[i].Name < env.Agents[j].Name })
+
+	if out, err := exec.Command("tmux", "-V").Output(); err == nil {
+		env.Tmux = strings.TrimSpace(string(out))
+	}
+	if root, err := findGitRoot(cwd); err == nil {
+		env.GitRepo = root
+	}
+	return env
+}
+
+// printInitEnvironment shows what the wizard found.
+func printInitEnvironment(env initEnvironment) {
+	fmt.Printf("%s\n", style.Bold.Render("Agent CLIs"))
+	for _, a := range env.Agents {
+		if a.Path != "" {
+			fmt.Printf("  %s %-12s %s\n", style.Success.Render("✓"), a.Name, style.Dim.Render(a.Path))
+		} else {
+			fmt.Printf("  %s %-12s %s\n", style.Dim.Render("·"), a.Name, style.Dim.Render("not installed"))
+		}
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Environment"))
+	if env.Tmux != "" {
+		fmt.Printf("  %s %s\n", style.Success.Render("✓"), env.Tmux)
+	} else {
+		fmt.Printf("  %s tmux not installed (agents run in tmux sessions)\n", style.Error.Render("✗"))
+	}
+	if env.GitRepo != "" {
+		fmt.Printf("  %s git repo at %s\n", style.Success.Render("✓"), env.GitRepo)
+	} else {
+		fmt.Printf("  %s not in a git repo\n", style.Dim.Render("·"))
+	}
+	if env.TownRoot != "" {
+		fmt.Printf("  %s town at %s\n", style.Success.Render("✓"), env.TownRoot)
+	} else {
+		fmt.Printf("  %s not in a town\n", style.Dim.Render("·"))
+	}
+	fmt.Println()
+}
+
+// prompter reads wizard answers, one per line. At the end of input every
+// question takes its default, so the wizard also runs unattended.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// line asks question and returns the answer, or def for an empty one.
+func (p *prompter) line(question, def string) string {
+	if def != "" {
+		_, _ = fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		_, _ = fmt.Fprintf(p.out, "%s: ", question)
+	}
+	answer, err := p.in.ReadString('\n')
+	if err != nil && answer == "" {
+		_, _ = fmt.Fprintln(p.out)
+	}
+	if answer = strings.TrimSpace(answer); answer == "" {
+		return def
+	}
+	return answer
+}
+
+// yesNo asks a yes/no question.
+func (p *prompter) yesNo(question string, def bool) bool {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		switch strings.ToLower(p.line(question+" ("+hint+")", "")) {
+		case "":
+			return def
+		case "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		}
+	}
+}
+
+// choose asks for one of options, by number or name, and returns it.
+func (p *prompter) choose(question string, options []string, def string) string {
+	for i, o := range options {
+		_, _ = fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+	}
+	for {
+		answer := p.line(question, def)
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1]
+		}
+		for _, o := range options {
+			if answer == o {
+				return o
+			}
+		}
+		_, _ = fmt.Fprintf(p.out, "  Pick 1-%d or a name from the list\n", len(options))
+	}
+}
+
+// defaultAgentChoice is the agent the wizard suggests: the town's current
+// default if it is installed, then claude, then the first installed agent.
+func defaultAgentChoice(installed []string, current string) string {
+	for _, want := range []string{current, "claude"} {
+		for _, name := range installed {
+			if want != "" && name == want {
+				return name
+			}
+		}
+	}
+	if len(installed) > 0 {
+		return installed[0]
+	}
+	return ""
+}
+
+// runInitWizard is gt init --wizard: it checks the machine, creates a town
+// if there isn't one, sets the default agent, and starts the deacon and
+// mayor.
+func runInitWizard(cmd *cobra.Command) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting current directory: %w", err)
+	}
+	p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: os.Stdout}
+
+	fmt.Printf("%s Setting up Gas Town\n\n", style.Bold.Render("⚙️"))
+	env := detectInitEnvironment(cwd)
+	printInitEnvironment(env)
+
+	installed := env.installedAgents()
+	if len(installed) == 0 {
+		var names []string
+		for _, a := range env.Agents {
+			names = append(names, a.Name)
+		}
+		return fmt.Errorf("no agent CLI found on PATH; install one of: %s", strings.Join(names, ", "))
+	}
+
+	// 1. Town
+	townRoot := env.TownRoot
+	if townRoot == "" {
+		home, _ := os.UserHomeDir()
+		path := p.line("Create a Gas Town HQ at", filepath.Join(home, "gt"))
+		if strings.HasPrefix(path, "~") {
+			path = filepath.Join(home, path[1:])
+		}
+		if townRoot, err = filepath.Abs(path); err != nil {
+			return fmt.Errorf("resolving path: %w", err)
+		}
+		if err := runGtInstall(townRoot); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	// 2. Default agent
+	settingsPath := config.TownSettingsPath(townRoot)
+	townSettings, err := config.LoadOrCreateTownSettings(settingsPath)
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	agent := p.choose("Default agent", installed, defaultAgentChoice(installed, townSettings.DefaultAgent))
+	townSettings.DefaultAgent = agent
+	if err := config.SaveTownSettings(settingsPath, townSettings); err != nil {
+		return fmt.Errorf("saving town settings: %w", err)
+	}
+	fmt.Printf("%s Default agent set to '%s' in %s\n\n", style.Bold.Render("✓"), agent, style.Dim.Render(settingsPath))
+
+	// 3. Sessions
+	started := false
+	if env.Tmux == "" {
+		style.PrintWarning("tmux is not installed; install it, then start the town with: gt up")
+	} else if p.yesNo("Start the deacon and mayor now?", true) {
+		started = startWizardSessions(townRoot)
+	}
+
+	fmt.Println()
+	fmt.Println("Next steps:")
+	step := 1
+	if started {
+		fmt.Printf("  %d. Talk to the mayor: %s\n", step, style.Dim.Render("gt mayor attach"))
+		step++
+	} else if env.Tmux != "" {
+		fmt.Printf("  %d. Start the town: %s\n", step, style.Dim.Render("cd "+townRoot+" && gt up"))
+		step++
+	}
+	if env.GitRepo != "" && env.GitRepo != townRoot {
+		fmt.Printf("  %d. Add this repo as a rig: %s\n", step, style.Dim.Render("gt rig quick-add "+env.GitRepo))
+	} else {
+		fmt.Printf("  %d. Add a project as a rig: %s\n", step, style.Dim.Render("gt rig add <name> <git-url>"))
+	}
+	return nil
+}
+
+// runGtInstall creates a town at path with gt install, showing its output.
+func runGtInstall(path string) error {
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	c := exec.Command(gtPath, "install", path) //nolint:gosec // G204: our own binary
+	c.Stdout, c.Stderr = os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("gt install %s: %w", path, err)
+	}
+	return nil
+}
+
+// startWizardSessions starts the deacon and mayor, reporting whether both
+// are running.
+func startWizardSessions(townRoot string) bool {
+	ok := true
+	deaconMgr := deacon.NewManager(townRoot)
+	if err := deaconMgr.Start(""); err != nil && err != deacon.ErrAlreadyRunning {
+		printStatus("Deacon", false, err.Error())
+		ok = false
+	} else {
+		printStatus("Deacon", true, deaconMgr.SessionName())
+	}
+	mayorMgr := mayor.NewManager(townRoot)
+	if err := mayorMgr.Start(""); err != nil && err != mayor.ErrAlreadyRunning {
+		printStatus("Mayor", false, err.Error())
+		ok = false
+	} else {
+		printStatus("Mayor", true, mayorMgr.SessionName())
+	}
+	return ok
+}
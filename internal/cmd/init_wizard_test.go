@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestPrompter(input string) *prompter {
+	return &prompter{in: bufio.NewReader(strings.NewReader(input)), out: io.Discard}
+}
+
+func TestPrompterChoose(t *testing.T) {
+	options := []string{"claude", "codex", "kimi"}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"\n", "codex"},              // Default
+		{"", "codex"},                // End of input
+		{"3\n", "kimi"},              // By number
+		{"claude\n", "claude"},       // By name
+		{"9\ngemini\n1\n", "claude"}, // Re-asked until valid
+		{"nope", "codex"},            // Invalid, then end of input
+	}
+	for _, tt := range tests {
+		if got := newTestPrompter(tt.input).choose("Default agent", options, "codex"); got != tt.want {
+			t.Errorf("choose(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPrompterYesNo(t *testing.T) {
+	tests := []struct {
+		input string
+		def   bool
+		want  bool
+	}{
+		{"\n", true, true},
+		{"", false, false},
+		{"n\n", true, false},
+		{"YES\n", false, true},
+		{"maybe\ny\n", false, true},
+	}
+	for _, tt := range tests {
+		if got := newTestPrompter(tt.input).yesNo("Start?", tt.def); got != tt.want {
+			t.Errorf("yesNo(%q, %v) = %v, want %v", tt.input, tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestDefaultAgentChoice(t *testing.T) {
+	tests := []struct {
+		installed []string
+		current   string
+		want      string
+	}{
+		{[]string{"claude", "kimi"}, "kimi", "kimi"},
+		{[]string{"claude", "kimi"}, "gemini", "claude"}, // Current default not installed
+		{[]string{"codex", "kimi"}, "", "codex"},
+		{nil, "claude", ""},
+	}
+	for _, tt := range tests {
+		if got := defaultAgentChoice(tt.installed, tt.current); got != tt.want {
+			t.Errorf("defaultAgentChoice(%v, %q) = %q, want %q", tt.installed, tt.current, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var layoutApplyForce bool
+
+var layoutCmd = &cobra.Command{
+	Use:     "layout",
+	GroupID: GroupWorkspace,
+	Short:   "Open multi-pane windows from layouts in town settings",
+	Long: `Open the same multi-pane window in a session every time, instead of
+splitting it by hand after attaching.
+
+Layouts are defined in settings/config.json under "layouts". Each one lists
+panes to split off the agent pane (pane 0), in order:
+
+  "layouts": {
+    "dev": {
+      "panes": [
+        {"split": "right", "size": 40, "command": "gt logs -f --agent {agent}"},
+        {"split": "below", "of": 1}
+      ]
+    }
+  },
+  "role_layouts": {"crew": "dev"}
+
+A pane splits "right" or "below" the pane given by "of" (default 0), taking
+"size" percent of it. "command" runs in the pane instead of a shell, with
+{session}, {workdir}, {town} and {agent} filled in. "arrange" applies a tmux
+preset layout (main-vertical, tiled, ...) at the end, and "focus" picks the
+pane left selected.
+
+role_layouts gives the layout new sessions of a role open with: gt crew at
+applies the crew layout when it creates a session.`,
+	RunE: requireSubcommand,
+}
+
+var layoutListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the town's layouts",
+	Args:  cobra.NoArgs,
+	RunE:  runLayoutList,
+}
+
+var layoutApplyCmd = &cobra.Command{
+	Use:   "apply <name> [session]",
+	Short: "Open a layout's panes in a session",
+	Long: `Open the panes of a layout in a session: the named role or session, or the
+current tmux session.
+
+A session whose window already has more than one pane is left alone, so
+applying a layout twice doesn't stack panes; --force opens them anyway.
+
+Examples:
+  gt layout apply dev                     # In the current session
+  gt layout apply dev gastown/crew/max    # In max's crew session
+  gt layout apply dev gt-gastown-crew-max --force`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runLayoutApply,
+}
+
+func init() {
+	layoutApplyCmd.Flags().BoolVarP(&layoutApplyForce, "force", "f", false, "Open the panes even if the window is already split")
+	layoutCmd.AddCommand(layoutListCmd)
+	layoutCmd.AddCommand(layoutApplyCmd)
+	rootCmd.AddCommand(layoutCmd)
+}
+
+func runLayoutList(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return fmt.Errorf("loading town settings: %w", err)
+	}
+	if len(settings.Layouts) == 0 {
+		fmt.Println(style.Dim.Render("No layouts defined (add them under \"layouts\" in settings/config.json)"))
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(settings.Layouts)) {
+		var roles []string
+		for _, role := range slices.Sorted(maps.Keys(settings.RoleLayouts)) {
+			if settings.RoleLayouts[role] == name {
+				roles = append(roles, role)
+			}
+		}
+		line := fmt.Sprintf("  %-16s %s", style.Bold.Render(name), describeLayout(settings.Layouts[name]))
+		if len(roles) > 0 {
+			line += "  " + style.Dim.Render("("+strings.Join(roles, ", ")+")")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// describeLayout summarizes a layout's panes for gt layout list.
+func describeLayout(l *config.PaneLayout) string {
+	if err := l.Validate(); err != nil {
+		return style.Error.Render("invalid: " + err.Error())
+	}
+	var panes []string
+	for _, p := range l.Panes {
+		what := "shell"
+		if p.Command != "" {
+			what = p.Command
+		}
+		panes = append(panes, fmt.Sprintf("%s of %d: %s", p.Split, p.Of, what))
+	}
+	return fmt.Sprintf("%d panes [%s]", len(l.Panes)+1, strings.Join(panes, "; "))
+}
+
+func runLayoutApply(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	layout, err := loadLayout(townRoot, args[0])
+	if err != nil {
+		return err
+	}
+
+	var session string
+	if len(args) > 1 {
+		if session, err = resolveRoleToSession(args[1]); err != nil {
+			return fmt.Errorf("resolving role: %w", err)
+		}
+	} else {
+		if !tmux.IsInsideTmux() {
+			return fmt.Errorf("%w: name the role or session to apply the layout to", ErrNotInTmux)
+		}
+		if session, err = getCurrentTmuxSession(); err != nil {
+			return err
+		}
+	}
+
+	t := tmux.NewTmux()
+	applied, err := applyPaneLayout(t, session, townRoot, layout, layoutApplyForce)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		fmt.Printf("%s is already split; use --force to open the panes anyway\n", session)
+		return nil
+	}
+	fmt.Printf("%s Applied layout %s to %s\n", style.Bold.Render("✓"), args[0], session)
+	return nil
+}
+
+// loadLayout returns the town's layout called name.
+func loadLayout(townRoot, name string) (*config.PaneLayout, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	layout := settings.Layouts[name]
+	if layout == nil {
+		return nil, fmt.Errorf("no layout %q (see gt layout list)", name)
+	}
+	if err := layout.Validate(); err != nil {
+		return nil, fmt.Errorf("layout %s: %w", name, err)
+	}
+	return layout, nil
+}
+
+// applyPaneLayout opens layout's panes in session's window, splitting off
+// its agent pane. A window that is already split is left alone unless
+// force is set; applied reports whether the panes were opened.
+func applyPaneLayout(t *tmux.Tmux, session, townRoot string, layout *config.PaneLayout, force bool) (applied bool, err error) {
+	existing, err := t.ListSessionPanes(session)
+	if err != nil {
+		return false, fmt.Errorf("listing panes of %s: %w", session, err)
+	}
+	if len(existing) > 1 && !force {
+		return false, nil
+	}
+	agentPane, err := t.GetPaneID(session)
+	if err != nil {
+		return false, err
+	}
+	workDir, _ := t.GetPaneWorkDir(agentPane)
+	agent, _ := t.GetEnvironment(session, "BD_ACTOR")
+	vars := strings.NewReplacer("{session}", session, "{workdir}", workDir, "{town}", townRoot, "{agent}", agent)
+
+	panes := []string{agentPane}
+	for i, p := range layout.Panes {
+		id, err := t.SplitPane(panes[p.Of], p.Split == config.SplitBelow, p.Size, workDir, vars.Replace(p.Command))
+		if err != nil {
+			return false, fmt.Errorf("opening pane %d: %w", i+1, err)
+		}
+		panes = append(panes, id)
+	}
+	if layout.Arrange != "" {
+		if err := t.SelectLayout(agentPane, layout.Arrange); err != nil {
+			return true, fmt.Errorf("arranging panes: %w", err)
+		}
+	}
+	if err := t.SelectPane(panes[layout.Focus]); err != nil {
+		return true, fmt.Errorf("selecting pane %d: %w", layout.Focus, err)
+	}
+	return true, nil
+}
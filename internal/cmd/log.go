@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/townlog"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -347,6 +349,14 @@ func runLogCrash(cmd *cobra.Command, args []string) error {
 	if err := logger.Log(eventType, crashAgent, context); err != nil {
 		return fmt.Errorf("logging event: %w", err)
 	}
+	if eventType == townlog.EventCrash {
+		hooks.Fire(townRoot, hooks.AgentCrash, map[string]string{
+			"agent":     crashAgent,
+			"session":   crashSession,
+			"exit_code": strconv.Itoa(crashExitCode),
+		})
+		notifyAgentCrash(townRoot, crashAgent, crashSession, context)
+	}
 
 	return nil
 }
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+// Logs command flags
+var (
+	logsRole   string
+	logsAgent  string
+	logsLevel  string
+	logsSince  string
+	logsTail   int
+	logsFollow bool
+	logsJSON   bool
+)
+
+var logsCmd = &cobra.Command{
+	Use:     "logs",
+	GroupID: GroupDiag,
+	Short:   "View the structured log of gt commands and daemons",
+	Long: `View the structured log every gt command, the daemon and the watchdog
+write to ~/.gastown/logs/gt.jsonl.
+
+Entries are tagged with the role and agent that wrote them (GT_ROLE), so a
+failed handoff or respawn can be traced after the fact: respawns, readiness
+waits, lifecycle restarts and failed commands are all recorded. Commands run
+by hand are tagged with role cli.
+
+GT_LOG_LEVEL sets what is recorded (debug, info, warn, error; default info),
+GT_LOG_DIR moves the log, and GT_LOG_STDERR=1 also prints entries to stderr.
+Unlike gt log (the town's agent lifecycle events), this is a debugging log.
+
+Examples:
+  gt logs                         # Last 50 entries
+  gt logs --role crew             # Only entries written by crew agents
+  gt logs --agent gastown/        # Only agents of the gastown rig
+  gt logs --level error --since 1h
+  gt logs -f --role daemon        # Follow the daemon
+  gt logs --json | jq .           # Raw entries`,
+	RunE: runLogs,
+}
+
+func init() {
+	logsCmd.Flags().StringVar(&logsRole, "role", "", "Filter by role (mayor, deacon, witness, refinery, crew, polecat, daemon, watchdog, cli)")
+	logsCmd.Flags().StringVarP(&logsAgent, "agent", "a", "", "Filter by agent prefix (e.g., gastown/, gastown/crew/max)")
+	logsCmd.Flags().StringVar(&logsLevel, "level", "", "Minimum level to show (debug, info, warn, error)")
+	logsCmd.Flags().StringVar(&logsSince, "since", "", "Show entries since duration (e.g., 1h, 30m, 24h)")
+	logsCmd.Flags().IntVarP(&logsTail, "tail", "n", 50, "Number of entries to show (0 for all)")
+	logsCmd.Flags().BoolVarP(&logsFollow, "follow", "f", false, "Follow new entries (like tail -f)")
+	logsCmd.Flags().BoolVar(&logsJSON, "json", false, "Print entries as JSON lines")
+
+	rootCmd.AddCommand(logsCmd)
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	filter := gtlog.Filter{Role: logsRole, Agent: logsAgent, Level: slog.LevelDebug}
+	if logsLevel != "" {
+		level, err := gtlog.ParseLevel(logsLevel)
+		if err != nil {
+			return err
+		}
+		filter.Level = level
+	}
+	if logsSince != "" {
+		duration, err := time.ParseDuration(logsSince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		filter.Since = time.Now().Add(-duration)
+	}
+
+	path := gtlog.Path()
+	entries, err := gtlog.Read(path, filter)
+	if err != nil {
+		return fmt.Errorf("reading log: %w", err)
+	}
+	if logsTail > 0 && len(entries) > logsTail {
+		entries = entries[len(entries)-logsTail:]
+	}
+	for _, e := range entries {
+		printLogEntry(e)
+	}
+
+	if !logsFollow {
+		if len(entries) == 0 && !logsJSON {
+			fmt.Printf("%s No log entries match (log: %s)\n", style.Dim.Render("○"), path)
+		}
+		return nil
+	}
+
+	if !logsJSON {
+		fmt.Printf("%s Following %s (Ctrl+C to stop)\n", style.Dim.Render("○"), path)
+	}
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	return gtlog.Follow(ctx, path, filter, 500*time.Millisecond, printLogEntry)
+}
+
+// printLogEntry prints one entry: a JSON line with --json, else a styled line.
+func printLogEntry(e gtlog.Entry) {
+	if logsJSON {
+		data, err := json.Marshal(logEntryJSON(e))
+		if err == nil {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	var level string
+	switch {
+	case e.Level >= slog.LevelError:
+		level = style.Error.Render("ERROR")
+	case e.Level >= slog.LevelWarn:
+		level = style.Warning.Render("WARN ")
+	case e.Level >= slog.LevelInfo:
+		level = "INFO "
+	default:
+		level = style.Dim.Render("DEBUG")
+	}
+	who := e.Role
+	if e.Agent != "" && e.Agent != e.Role {
+		who = e.Agent
+	}
+	line := fmt.Sprintf("%s %s %s %s", style.Dim.Render(e.Time.Local().Format("2006-01-02 15:04:05")), level, style.Bold.Render(who), e.Msg)
+	if attrs := e.AttrString(); attrs != "" {
+		line += " " + style.Dim.Render(attrs)
+	}
+	fmt.Println(line)
+}
+
+// logEntryJSON is the --json form of an entry.
+func logEntryJSON(e gtlog.Entry) map[string]any {
+	out := map[string]any{
+		"time":  e.Time,
+		"level": e.Level.String(),
+		"msg":   e.Msg,
+		"role":  e.Role,
+		"pid":   e.PID,
+	}
+	if e.Agent != "" {
+		out["agent"] = e.Agent
+	}
+	for k, v := range e.Attrs {
+		out[k] = v
+	}
+	return out
+}
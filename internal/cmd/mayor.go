@@ -29,6 +29,12 @@ The Mayor is the global coordinator for Gas Town:
 The Mayor is the primary interface between the human Overseer and the
 automated agents. When in doubt, escalate to the Mayor.
 
+FAILOVER:
+If the Mayor's session dies, the watchdog (gt watchdog run) takes over:
+it promotes a standby Mayor started with 'gt mayor standby', or else
+recreates the Mayor from the session manifest, resuming its conversation.
+'gt mayor stop' is deliberate and never triggers failover.
+
 Role shortcuts: "mayor" in mail/nudge addresses resolves to this agent.`,
 }
 
@@ -80,16 +86,50 @@ Stops the current session (if running) and starts a fresh one.`,
 	RunE: runMayorRestart,
 }
 
+var mayorStandbyStop bool
+
+var mayorStandbyCmd = &cobra.Command{
+	Use:   "standby",
+	Short: "Start a standby Mayor for failover",
+	Long: `Start a standby Mayor session (hq-mayor-standby).
+
+The standby is a second Mayor agent that stays idle. If the Mayor's
+session is lost, the watchdog promotes the standby: it is renamed to the
+Mayor's session, takes over the Mayor's session manifest entry, and is
+told to pick up coordination. Use 'gt mayor promote' to do this by hand.
+
+Examples:
+  gt mayor standby                # Start the standby
+  gt mayor standby --agent codex  # Run the standby with another agent
+  gt mayor standby --stop         # Stop the standby`,
+	RunE: runMayorStandby,
+}
+
+var mayorPromoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Promote the standby Mayor to Mayor",
+	Long: `Promote the standby Mayor to Mayor.
+
+Fails if the Mayor's session is still running: stop it first with
+'gt mayor stop'. The watchdog does this automatically when the Mayor's
+session is lost.`,
+	RunE: runMayorPromote,
+}
+
 func init() {
 	mayorCmd.AddCommand(mayorStartCmd)
 	mayorCmd.AddCommand(mayorStopCmd)
 	mayorCmd.AddCommand(mayorAttachCmd)
 	mayorCmd.AddCommand(mayorStatusCmd)
 	mayorCmd.AddCommand(mayorRestartCmd)
+	mayorCmd.AddCommand(mayorStandbyCmd)
+	mayorCmd.AddCommand(mayorPromoteCmd)
 
 	mayorStartCmd.Flags().StringVar(&mayorAgentOverride, "agent", "", "Agent alias to run the Mayor with (overrides town default)")
 	mayorAttachCmd.Flags().StringVar(&mayorAgentOverride, "agent", "", "Agent alias to run the Mayor with (overrides town default)")
 	mayorRestartCmd.Flags().StringVar(&mayorAgentOverride, "agent", "", "Agent alias to run the Mayor with (overrides town default)")
+	mayorStandbyCmd.Flags().StringVar(&mayorAgentOverride, "agent", "", "Agent alias to run the standby with (overrides town default)")
+	mayorStandbyCmd.Flags().BoolVar(&mayorStandbyStop, "stop", false, "Stop the standby instead of starting it")
 
 	rootCmd.AddCommand(mayorCmd)
 }
@@ -253,6 +293,9 @@ func runMayorStatus(cmd *cobra.Command, args []string) error {
 		style.Bold.Render("running"))
 	fmt.Printf("  Status: %s\n", status)
 	fmt.Printf("  Created: %s\n", info.Created)
+	if standby, _ := mgr.StandbyRunning(); standby {
+		fmt.Printf("  Standby: %s\n", mgr.StandbySessionName())
+	}
 	fmt.Printf("\nAttach with: %s\n", style.Dim.Render("gt mayor attach"))
 
 	return nil
@@ -272,3 +315,55 @@ func runMayorRestart(cmd *cobra.Command, args []string) error {
 	// Start fresh
 	return runMayorStart(cmd, args)
 }
+
+func runMayorStandby(cmd *cobra.Command, args []string) error {
+	mgr, err := getMayorManager()
+	if err != nil {
+		return err
+	}
+
+	if mayorStandbyStop {
+		if err := mgr.StopStandby(); err != nil {
+			if err == mayor.ErrNotRunning {
+				return fmt.Errorf("standby Mayor is not running")
+			}
+			return err
+		}
+		fmt.Printf("%s Standby Mayor stopped.\n", style.Bold.Render("✓"))
+		return nil
+	}
+
+	fmt.Println("Starting standby Mayor session...")
+	if err := mgr.StartStandby(mayorAgentOverride); err != nil {
+		if err == mayor.ErrAlreadyRunning {
+			return fmt.Errorf("standby Mayor already running (%s)", mgr.StandbySessionName())
+		}
+		return err
+	}
+
+	fmt.Printf("%s Standby Mayor started (%s). The watchdog promotes it if the Mayor is lost.\n",
+		style.Bold.Render("✓"), mgr.StandbySessionName())
+	return nil
+}
+
+func runMayorPromote(cmd *cobra.Command, args []string) error {
+	mgr, err := getMayorManager()
+	if err != nil {
+		return err
+	}
+
+	if err := mgr.Promote(); err != nil {
+		switch err {
+		case mayor.ErrNoStandby:
+			return fmt.Errorf("no standby Mayor running. Start one with: gt mayor standby")
+		case mayor.ErrAlreadyRunning:
+			return fmt.Errorf("Mayor session already running. Stop it first with: gt mayor stop")
+		}
+		return err
+	}
+
+	fmt.Printf("%s Standby Mayor promoted. Attach with: %s\n",
+		style.Bold.Render("✓"),
+		style.Dim.Render("gt mayor attach"))
+	return nil
+}
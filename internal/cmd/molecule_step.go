@@ -10,6 +10,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -587,6 +588,13 @@ func handleMoleculeComplete(cwd, townRoot, moleculeID string, dryRun bool) error
 		}
 	}
 
+	hooks.Fire(townRoot, hooks.BeadCompleted, map[string]string{
+		"bead":   moleculeID,
+		"agent":  agentID,
+		"source": "gt mol step done",
+	})
+	advanceBeadStage(townRoot, moleculeID, beads.StageDone, agentID, "gt mol step done")
+
 	// For polecats, use gt done to signal completion
 	if roleCtx.Role == RolePolecat {
 		fmt.Printf("%s Signaling completion to witness...\n", style.Bold.Render("📤"))
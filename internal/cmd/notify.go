@@ -1,11 +1,14 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -23,22 +26,55 @@ Notification levels:
 
 Without arguments, shows the current notification level.
 
+--test sends a test notification to every sink configured under "notify"
+in the town settings (or a rig's, with --rig): desktop notifications,
+Slack incoming webhooks and JSON webhooks. The sinks get the events they
+list - bead-done, merged, agent-stuck, handoff, agent-crash - as they
+happen:
+
+  "notify": {
+    "sinks": [
+      {"type": "slack", "url": "https://hooks.slack.com/services/...", "events": ["merged"]},
+      {"type": "webhook", "url": "https://example.com/gt", "events": ["agent-crash", "agent-stuck"]},
+      {"type": "desktop"}
+    ],
+    "stuck_after": "15m"
+  }
+
+agent-stuck is sent by the watchdog (gt watchdog start) once an agent has
+been waiting for input or stopped on an error for stuck_after.
+
 Examples:
   gt notify           # Show current level
   gt notify verbose   # Enable all notifications
   gt notify normal    # Default notification level
   gt notify muted     # Enable DND mode
+  gt notify --test --rig gastown  # Try the gastown rig's notification sinks
 
 Related: gt dnd - quick toggle for DND mode`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runNotify,
 }
 
+var (
+	notifyTest bool
+	notifyRig  string
+)
+
 func init() {
+	notifyCmd.Flags().BoolVar(&notifyTest, "test", false, "Send a test notification to the configured sinks")
+	notifyCmd.Flags().StringVar(&notifyRig, "rig", "", "With --test, use this rig's sinks")
 	rootCmd.AddCommand(notifyCmd)
 }
 
 func runNotify(cmd *cobra.Command, args []string) error {
+	if notifyTest {
+		if len(args) > 0 {
+			return fmt.Errorf("--test takes no level")
+		}
+		return runNotifyTest()
+	}
+
 	// Get current agent bead ID
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -129,3 +165,47 @@ func showNotificationLevelDescription(level string) {
 		fmt.Printf("  %s\n", style.Dim.Render("Silent mode: notifications batched for later review"))
 	}
 }
+
+// runNotifyTest sends a test notification to each sink configured for
+// notifyRig (or the town), whatever events it takes.
+func runNotifyTest() error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := notify.Config(townRoot, notifyRig)
+	if err != nil {
+		return err
+	}
+	if cfg == nil || len(cfg.Sinks) == 0 {
+		fmt.Println(style.Dim.Render("No notification sinks configured (see gt notify --help)"))
+		return nil
+	}
+
+	n := notify.Notification{
+		Event: "test",
+		Time:  time.Now().UTC(),
+		Rig:   notifyRig,
+		Title: "Test notification",
+		Body:  "gt notify --test",
+	}
+	var failed int
+	for i, sinkCfg := range cfg.Sinks {
+		sink, err := notify.NewSink(sinkCfg)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), notify.Timeout)
+			err = sink.Send(ctx, n)
+			cancel()
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("%s sink %d (%s): %v\n", style.ErrorPrefix, i, sinkCfg.Type, err)
+			continue
+		}
+		fmt.Printf("%s sink %d (%s)\n", style.SuccessPrefix, i, sinkCfg.Type)
+	}
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentstatus"
+	"github.com/steveyegge/gastown/internal/notify"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/watchdog"
+)
+
+// sessionRig returns the rig of the agent session sess, or "" for
+// town-level sessions and names that aren't agent sessions.
+func sessionRig(sess string) string {
+	if identity, err := session.ParseSessionName(sess); err == nil {
+		return identity.Rig
+	}
+	return ""
+}
+
+// notifyBeadDone sends bead-done for agent's completed bead.
+func notifyBeadDone(townRoot, rig, agent, bead, branch, mr string) {
+	title := agent + " finished its work"
+	if bead != "" {
+		title = agent + " finished " + bead
+	}
+	var body []string
+	if branch != "" {
+		body = append(body, "Branch: "+branch)
+	}
+	if mr != "" {
+		body = append(body, "Merge request: "+mr)
+	}
+	notify.Send(townRoot, notify.Notification{
+		Event:  notify.BeadDone,
+		Rig:    rig,
+		Title:  title,
+		Body:   strings.Join(body, "\n"),
+		Fields: map[string]string{"bead": bead, "agent": agent, "branch": branch, "mr": mr},
+	})
+}
+
+// notifyHandoff sends handoff for agent's session.
+func notifyHandoff(townRoot, agent, sess, subject, reason string) {
+	body := subject
+	if reason != "" {
+		body = strings.TrimSpace(body + "\nReason: " + reason)
+	}
+	notify.Send(townRoot, notify.Notification{
+		Event:  notify.Handoff,
+		Rig:    sessionRig(sess),
+		Title:  agent + " handed off",
+		Body:   body,
+		Fields: map[string]string{"agent": agent, "session": sess, "subject": subject, "reason": reason},
+	})
+}
+
+// notifyAgentCrash sends agent-crash for the agent in sess (its address,
+// found from sess if empty).
+func notifyAgentCrash(townRoot, agent, sess, detail string) {
+	if agent == "" {
+		_, agent = watchdogMailTarget(sess)
+	}
+	notify.Send(townRoot, notify.Notification{
+		Event:  notify.AgentCrash,
+		Rig:    sessionRig(sess),
+		Title:  agent + " crashed",
+		Body:   detail,
+		Fields: map[string]string{"agent": agent, "session": sess},
+	})
+}
+
+// notifyWatchdogStuck sends agent-stuck for an agent the watchdog has
+// found stuck for a.StuckFor.
+func notifyWatchdogStuck(townRoot string, a watchdog.Action) {
+	_, address := watchdogMailTarget(a.Session)
+	what := "waiting for input"
+	if a.Stuck == agentstatus.StatusError {
+		what = "stopped on an error"
+	}
+	notify.Send(townRoot, notify.Notification{
+		Event: notify.AgentStuck,
+		Rig:   sessionRig(a.Session),
+		Title: fmt.Sprintf("%s %s for %s", address, what, a.StuckFor.Round(time.Second)),
+		Body:  a.Line,
+		Fields: map[string]string{
+			"agent":   address,
+			"session": a.Session,
+			"status":  string(a.Stuck),
+			"for":     a.StuckFor.String(),
+		},
+	})
+}
+
+// notifyStuckAfter returns how long an agent in rig must be stuck before
+// agent-stuck is sent, or 0 if no sink takes it.
+func notifyStuckAfter(townRoot, rig string) time.Duration {
+	cfg, err := notify.Config(townRoot, rig)
+	if err != nil || cfg == nil {
+		return 0
+	}
+	for _, sink := range cfg.Sinks {
+		if sink.Wants(string(notify.AgentStuck)) {
+			return cfg.GetStuckAfter()
+		}
+	}
+	return 0
+}
@@ -2,6 +2,7 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -31,6 +33,7 @@ type SpawnedPolecatInfo struct {
 	// Internal fields for deferred session start
 	account string
 	agent   string
+	model   string
 }
 
 // AgentID returns the agent identifier (e.g., "gastown/polecats/Toast")
@@ -50,6 +53,7 @@ type SlingSpawnOptions struct {
 	Create   bool   // Create polecat if it doesn't exist (currently always true for sling)
 	HookBead string // Bead ID to set as hook_bead at spawn time (atomic assignment)
 	Agent    string // Agent override for this spawn (e.g., "gemini", "codex", "claude-haiku")
+	Model    string // Model override for the agent (e.g., "opus"), passed with its ModelFlag
 }
 
 // SpawnPolecatForSling creates a fresh polecat and optionally starts its session.
@@ -166,6 +170,7 @@ func SpawnPolecatForSling(rigName string, opts SlingSpawnOptions) (*SpawnedPolec
 		Pane:        "", // Empty until StartSession is called
 		account:     opts.Account,
 		agent:       opts.Agent,
+		model:       opts.Model,
 	}, nil
 }
 
@@ -211,15 +216,27 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	fmt.Printf("Starting session for %s/%s...\n", s.RigName, s.PolecatName)
 	startOpts := polecat.SessionStartOptions{
 		RuntimeConfigDir: claudeConfigDir,
+		Model:            s.model,
 	}
 	if s.agent != "" {
-		cmd, err := config.BuildPolecatStartupCommandWithAgentOverride(s.RigName, s.PolecatName, r.Path, "", s.agent)
+		envVars := config.AgentEnv(config.AgentEnvConfig{
+			Role:      "polecat",
+			Rig:       s.RigName,
+			AgentName: s.PolecatName,
+			TownRoot:  townRoot,
+			Model:     s.model,
+		})
+		cmd, err := config.BuildStartupCommandWithAgentOverride(envVars, r.Path, "", s.agent)
 		if err != nil {
 			return "", err
 		}
 		startOpts.Command = cmd
 	}
-	if err := polecatSessMgr.Start(s.PolecatName, startOpts); err != nil {
+	if err := polecatSessMgr.Start(s.PolecatName, startOpts); errors.Is(err, scheduler.ErrQueued) {
+		// Work is already hooked; the polecat finds it when the daemon starts it
+		fmt.Printf("%s %v\n", style.Dim.Render("⏳"), err)
+		return "", nil
+	} else if err != nil {
 		return "", fmt.Errorf("starting session: %w", err)
 	}
 
@@ -246,6 +263,33 @@ func (s *SpawnedPolecatInfo) StartSession() (string, error) {
 	return pane, nil
 }
 
+// RunHeadless runs the spawned polecat's agent in the foreground instead of
+// starting its session (gt sling --headless), returning once the agent exits.
+func (s *SpawnedPolecatInfo) RunHeadless(beadID string) error {
+	townRoot, r, err := getRig(s.RigName)
+	if err != nil {
+		return err
+	}
+	accountsPath := constants.MayorAccountsPath(townRoot)
+	claudeConfigDir, _, err := config.ResolveAccountConfigDir(accountsPath, s.account)
+	if err != nil {
+		return fmt.Errorf("resolving account: %w", err)
+	}
+
+	t := tmux.NewTmux()
+	polecatMgr := polecat.NewManager(r, git.NewGit(r.Path), t)
+	if err := polecatMgr.SetAgentState(s.PolecatName, "working"); err != nil {
+		fmt.Printf("Warning: could not update agent state: %v\n", err)
+	}
+
+	fmt.Printf("Running %s/%s headless...\n", s.RigName, s.PolecatName)
+	return polecat.NewSessionManager(t, r).RunHeadless(s.PolecatName, s.agent, polecat.SessionStartOptions{
+		Issue:            beadID,
+		RuntimeConfigDir: claudeConfigDir,
+		Model:            s.model,
+	}, os.Stdout, os.Stderr)
+}
+
 // IsRigName checks if a target string is a rig name (not a role or path).
 // Returns the rig name and true if it's a valid rig.
 func IsRigName(target string) (string, bool) {
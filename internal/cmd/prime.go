@@ -429,6 +429,9 @@ func checkSlungWork(ctx RoleContext) bool {
 
 	// Use the first hooked bead (agents typically have one)
 	hookedBead := hookedBeads[0]
+	if !primeDryRun {
+		advanceBeadStage(ctx.TownRoot, hookedBead.ID, beads.StageInProgress, agentID, "gt prime")
+	}
 
 	// Build the role announcement string
 	roleAnnounce := buildRoleAnnouncement(ctx)
@@ -11,7 +11,10 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -173,6 +176,43 @@ func emitSessionEvent(ctx RoleContext) {
 	// Emit the event
 	payload := events.SessionPayload(sessionID, actor, topic, ctx.WorkDir)
 	_ = events.LogFeed(events.TypeSessionStart, actor, payload)
+	hooks.Fire(ctx.TownRoot, hooks.SessionStart, map[string]string{
+		"agent":      actor,
+		"session_id": sessionID,
+		"role":       string(ctx.Role),
+		"rig":        ctx.Rig,
+		"work_dir":   ctx.WorkDir,
+	})
+	recordSessionManifest(ctx, sessionID, actor)
+}
+
+// recordSessionManifest notes this tmux session in the session manifest so
+// gt resume --all can recreate it, in the same agent conversation, after a
+// reboot. Sessions outside tmux, or without a real agent session ID, are
+// skipped.
+func recordSessionManifest(ctx RoleContext, sessionID, actor string) {
+	if os.Getenv("TMUX") == "" || sessionID == fmt.Sprintf("%s-%d", actor, os.Getpid()) {
+		return
+	}
+	tmuxSession, err := getCurrentTmuxSession()
+	if err != nil || tmuxSession == "" {
+		return
+	}
+	entry := session.ManifestEntry{
+		Session:        tmuxSession,
+		Role:           string(ctx.Role),
+		Rig:            ctx.Rig,
+		Agent:          os.Getenv("GT_AGENT"),
+		Model:          os.Getenv("GT_MODEL"),
+		ResumeFlag:     os.Getenv("GT_RESUME_FLAG"),
+		WorkDir:        ctx.WorkDir,
+		TownRoot:       ctx.TownRoot,
+		AgentSessionID: sessionID,
+	}
+	if tags, err := tmux.NewTmux().GetSessionTags(tmuxSession); err == nil && len(tags) > 0 {
+		entry.Tags = tags
+	}
+	_ = session.RecordSession(session.ManifestPath(), entry) // Non-fatal
 }
 
 // outputSessionMetadata prints a structured metadata line for seance discovery.
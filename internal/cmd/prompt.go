@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	promptRig   string
+	promptName  string
+	promptBead  string
+	promptAgent string
+	promptTopic string
+)
+
+var promptCmd = &cobra.Command{
+	Use:     "prompt",
+	GroupID: GroupConfig,
+	Short:   "Manage startup prompt templates",
+	RunE:    requireSubcommand,
+	Long: `Manage the templates of the prompt typed into a new session once its agent
+is ready (gt handoff --wait).
+
+Templates are Go text/template files under .gastown/prompts/, in the town
+root and in each rig. The first that exists is used:
+
+  <rig>/.gastown/prompts/<role>.tmpl
+  <town>/.gastown/prompts/<role>.tmpl
+  <rig>/.gastown/prompts/default.tmpl
+  <town>/.gastown/prompts/default.tmpl
+
+Without a template the agent's priming_prompt is sent. Templates can use:
+
+  {{.Town}}     Town root
+  {{.Rig}}      Rig name (empty for mayor/deacon)
+  {{.Role}}     mayor, deacon, witness, refinery, crew, polecat
+  {{.Name}}     Crew/polecat name
+  {{.Address}}  Agent address, e.g. gastown/crew/max
+  {{.Agent}}    Agent preset, e.g. claude or kimi
+  {{.Bead}}     Bead on the agent's hook, if any
+  {{.Topic}}    Why the session started, e.g. handoff
+  {{.Default}}  The agent's priming_prompt
+
+Commands:
+  gt prompt render <role|address>   Preview the prompt an agent would get`,
+}
+
+var promptRenderCmd = &cobra.Command{
+	Use:   "render <role|address>",
+	Short: "Preview the startup prompt an agent would get",
+	Long: `Render the startup prompt template of an agent and print it, with the
+template it came from.
+
+The agent is a role (with --rig and --name where the role needs them) or an
+address like gastown/crew/max. The bead defaults to the one on the agent's
+hook and the agent preset to the one the rig would start.
+
+Examples:
+  gt prompt render mayor
+  gt prompt render gastown/crew/max
+  gt prompt render crew --rig gastown --name max --bead gt-abc12
+  gt prompt render witness --rig gastown --agent kimi`,
+	Args: cobra.ExactArgs(1),
+	RunE: runPromptRender,
+}
+
+func init() {
+	promptRenderCmd.Flags().StringVar(&promptRig, "rig", "", "Rig of the agent")
+	promptRenderCmd.Flags().StringVar(&promptName, "name", "", "Crew/polecat name")
+	promptRenderCmd.Flags().StringVar(&promptBead, "bead", "", "Bead to render with (default: the agent's hooked bead)")
+	promptRenderCmd.Flags().StringVar(&promptAgent, "agent", "", "Agent preset to render with (default: the rig's agent)")
+	promptRenderCmd.Flags().StringVar(&promptTopic, "topic", "handoff", "Why the session started")
+
+	promptCmd.AddCommand(promptRenderCmd)
+	rootCmd.AddCommand(promptCmd)
+}
+
+func runPromptRender(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	data := session.PromptData{Town: townRoot, Rig: promptRig, Role: args[0], Name: promptName, Bead: promptBead, Topic: promptTopic}
+	if strings.Contains(args[0], "/") {
+		id, err := session.ParseAddress(args[0])
+		if err != nil {
+			return err
+		}
+		data.Rig, data.Role, data.Name = id.Rig, string(id.Role), id.Name
+	}
+	id := session.AgentIdentity{Role: session.Role(data.Role), Rig: data.Rig, Name: data.Name}
+	switch id.Role {
+	case session.RoleCrew, session.RolePolecat:
+		if id.Name == "" {
+			return fmt.Errorf("--name is required for %s", id.Role)
+		}
+		fallthrough
+	case session.RoleWitness, session.RoleRefinery:
+		if id.Rig == "" {
+			return fmt.Errorf("--rig is required for %s", id.Role)
+		}
+	}
+	data.Address = id.Address()
+
+	rigPath := ""
+	if data.Rig != "" {
+		rigPath = filepath.Join(townRoot, data.Rig)
+	}
+	rc, agent, err := config.ResolveAgentConfigWithOverride(townRoot, rigPath, promptAgent)
+	if err != nil {
+		return fmt.Errorf("resolving agent: %w", err)
+	}
+	data.Agent, data.Default = agent, rc.Resolved().PrimingPrompt
+
+	if data.Bead == "" && data.Address != "" {
+		if hooked := scanAllRigsForHookedBeads(townRoot, data.Address); len(hooked) > 0 {
+			data.Bead = hooked[0].ID
+		}
+	}
+
+	prompt, path, err := session.RenderStartupPrompt(data)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		fmt.Printf("%s\n", style.Dim.Render(fmt.Sprintf("No template for %s; %s's priming_prompt:", data.Role, data.Agent)))
+	} else {
+		fmt.Printf("%s\n", style.Dim.Render("Template: "+path))
+	}
+	if prompt == "" {
+		fmt.Printf("%s\n", style.Dim.Render("(empty: nothing is sent)"))
+		return nil
+	}
+	fmt.Printf("\n%s\n", prompt)
+	return nil
+}
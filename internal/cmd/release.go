@@ -61,6 +61,7 @@ func runRelease(cmd *cobra.Command, args []string) error {
 			failed++
 		} else {
 			fmt.Printf("%s Released %s → open\n", style.Bold.Render("✓"), id)
+			advanceBeadStage("", id, beads.StageQueued, "", "gt release")
 			released++
 		}
 	}
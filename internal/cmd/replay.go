@@ -0,0 +1,406 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	replaySince string
+	replayLines int
+	replayJSON  bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:     "replay <session|bead>",
+	GroupID: GroupDiag,
+	Short:   "Reconstruct what an agent did as one timeline",
+	Long: `Merge everything Gas Town recorded about a session or a bead into one
+chronological timeline, for post-mortems of a run nobody watched:
+
+  transcript  What the agent printed (gt transcript), dated by when it
+              was recorded
+  event       Town events: slings, hooks, handoffs, gt done, deaths
+  hook        Hook scripts run for it (.gastown/hooks, from gt logs)
+  stage       Bead lifecycle moves (gt bead status)
+  commit      Git commits in the agent's worktree
+
+For a session (a role, path or session name, as for gt transcript), the
+timeline covers the session's transcript, its agent's events and hooks,
+and the commits in its worktree since the transcript starts.
+
+For a bead, it covers the bead's lifecycle, events and hooks, the
+transcripts of the agents that held it while they held it, and the
+commits in their worktrees that mention the bead.
+
+Transcript output is shortened to its first --lines non-blank lines per
+entry; --lines 0 shows it all.
+
+Examples:
+  gt replay gastown/polecats/Toast
+  gt replay gt-abc12 --since 12h
+  gt replay gt-abc12 --json | jq 'select(.source == "commit")'`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replaySince, "since", "", "Only show entries since duration (e.g., 12h, 30m)")
+	replayCmd.Flags().IntVarP(&replayLines, "lines", "n", 3, "Transcript lines to show per entry (0 for all)")
+	replayCmd.Flags().BoolVar(&replayJSON, "json", false, "Print entries as JSON lines")
+
+	rootCmd.AddCommand(replayCmd)
+}
+
+// Replay timeline sources.
+const (
+	replayTranscript = "transcript"
+	replayEvent      = "event"
+	replayHook       = "hook"
+	replayStage      = "stage"
+	replayCommit     = "commit"
+)
+
+// replayItem is one entry on a replay timeline.
+type replayItem struct {
+	Time   time.Time `json:"time"`
+	Source string    `json:"source"`
+	Agent  string    `json:"agent,omitempty"`
+	Text   string    `json:"text"`
+}
+
+// replayWindow is a span of time; a zero end is open-ended.
+type replayWindow struct {
+	start, end time.Time
+}
+
+func (w replayWindow) contains(t time.Time) bool {
+	return !t.Before(w.start) && (w.end.IsZero() || !t.After(w.end))
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	if replayLines < 0 {
+		return fmt.Errorf("--lines must not be negative")
+	}
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	evs, err := events.Read(townRoot)
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+	logEntries, err := gtlog.Read(gtlog.Path(), gtlog.Filter{})
+	if err != nil {
+		return fmt.Errorf("reading gt log: %w", err)
+	}
+
+	target := args[0]
+	history, err := beads.NewLifecycle(townRoot).History(target)
+	if err != nil {
+		return fmt.Errorf("reading bead lifecycle: %w", err)
+	}
+	var items []replayItem
+	if len(history) > 0 || slices.ContainsFunc(evs, func(e events.Event) bool { return getPayloadString(e.Payload, "bead") == target }) {
+		items = beadReplay(townRoot, target, history, evs, logEntries)
+	} else {
+		sess, err := resolveTranscriptSession(target)
+		if err != nil {
+			return err
+		}
+		items = sessionReplay(townRoot, sess, evs, logEntries)
+	}
+
+	if replaySince != "" {
+		duration, err := time.ParseDuration(replaySince)
+		if err != nil {
+			return fmt.Errorf("invalid --since duration: %w", err)
+		}
+		since := time.Now().Add(-duration)
+		items = slices.DeleteFunc(items, func(item replayItem) bool { return item.Time.Before(since) })
+	}
+	sortReplay(items)
+
+	for _, item := range items {
+		printReplayItem(item)
+	}
+	if len(items) == 0 && !replayJSON {
+		fmt.Printf("%s Nothing recorded for %s\n", style.Dim.Render("○"), target)
+	}
+	return nil
+}
+
+// sessionReplay returns the timeline of sess: its transcript, the events
+// and hook firings of its agent, and the commits in its worktree since
+// the earliest of those.
+func sessionReplay(townRoot, sess string, evs []events.Event, logEntries []gtlog.Entry) []replayItem {
+	var address string
+	if identity, err := session.ParseSessionName(sess); err == nil {
+		address = identity.Address()
+	}
+	agent := address
+	if agent == "" {
+		agent = sess
+	}
+
+	items := transcriptItems(sess, agent, replayWindow{})
+	for _, e := range evs {
+		if (address != "" && e.Actor == address) || getPayloadString(e.Payload, "session") == sess {
+			items = append(items, eventItem(e))
+		}
+	}
+	for _, e := range logEntries {
+		fields := hookFields(e)
+		if fields == nil {
+			continue
+		}
+		if (address != "" && (e.Agent == address || fields["agent"] == address)) || fields["session"] == sess {
+			items = append(items, hookItem(e, fields))
+		}
+	}
+
+	var since time.Time
+	for _, item := range items {
+		if since.IsZero() || item.Time.Before(since) {
+			since = item.Time
+		}
+	}
+	if workDir, err := sessionWorkDir(sess, townRoot); err == nil && !since.IsZero() {
+		items = append(items, commitItems(workDir, agent, "--since="+since.Format(time.RFC3339))...)
+	}
+	return items
+}
+
+// beadReplay returns the timeline of bead: its lifecycle, events and hook
+// firings, the transcripts of the agents that held it while they did, and
+// the commits mentioning it in their worktrees.
+func beadReplay(townRoot, bead string, history []beads.Transition, evs []events.Event, logEntries []gtlog.Entry) []replayItem {
+	var items []replayItem
+	for _, t := range history {
+		text := fmt.Sprintf("%s → %s", t.From, t.To)
+		if t.Trigger != "" {
+			text += " (" + t.Trigger + ")"
+		}
+		if t.Note != "" {
+			text += ": " + t.Note
+		}
+		items = append(items, replayItem{Time: t.At, Source: replayStage, Agent: t.Agent, Text: text})
+	}
+	for _, e := range evs {
+		if getPayloadString(e.Payload, "bead") == bead {
+			items = append(items, eventItem(e))
+		}
+	}
+	for _, e := range logEntries {
+		if fields := hookFields(e); fields != nil && fields["bead"] == bead {
+			items = append(items, hookItem(e, fields))
+		}
+	}
+
+	seenWorkDirs := make(map[string]bool)
+	for agent, window := range holdWindows(history) {
+		identity, err := session.ParseAddress(agent)
+		if err != nil {
+			continue
+		}
+		sess := identity.SessionName()
+		items = append(items, transcriptItems(sess, agent, window)...)
+		if workDir, err := sessionWorkDir(sess, townRoot); err == nil && !seenWorkDirs[workDir] {
+			seenWorkDirs[workDir] = true
+			items = append(items, commitItems(workDir, agent, "--all", "--fixed-strings", "--grep="+bead)...)
+		}
+	}
+	return items
+}
+
+// holdWindows returns, for each agent that held the bead, the span from
+// when it first got the bead to when the bead last left it (open-ended if
+// it still holds it and the bead isn't done or failed).
+func holdWindows(history []beads.Transition) map[string]replayWindow {
+	windows := make(map[string]replayWindow)
+	for i, t := range history {
+		if t.Agent == "" {
+			continue
+		}
+		w, ok := windows[t.Agent]
+		if !ok {
+			w.start = t.At
+		}
+		w.end = time.Time{}
+		if i+1 < len(history) && history[i+1].Agent != t.Agent {
+			w.end = history[i+1].At
+		} else if t.To == beads.StageDone || t.To == beads.StageFailed {
+			w.end = t.At
+		}
+		windows[t.Agent] = w
+	}
+	return windows
+}
+
+// transcriptItems returns sess's transcript chunks in window, shortened
+// to --lines lines. Blank chunks (TUI redraws) are skipped.
+func transcriptItems(sess, agent string, window replayWindow) []replayItem {
+	chunks, err := transcript.Chunks(sess)
+	if err != nil {
+		return nil
+	}
+	var items []replayItem
+	for _, c := range chunks {
+		if !window.contains(c.Time) {
+			continue
+		}
+		if text := shortenTranscript(transcript.StripANSI(c.Text), replayLines); text != "" {
+			items = append(items, replayItem{Time: c.Time, Source: replayTranscript, Agent: agent, Text: text})
+		}
+	}
+	return items
+}
+
+// shortenTranscript returns the first n non-blank lines of text, trimmed,
+// with a count of the lines left out. n 0 keeps every line.
+func shortenTranscript(text string, n int) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if n > 0 && len(lines) > n {
+		more := len(lines) - n
+		lines = append(lines[:n], fmt.Sprintf("… %d more lines", more))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// eventItem returns the timeline entry for a town event.
+func eventItem(e events.Event) replayItem {
+	text := e.Type
+	keys := make([]string, 0, len(e.Payload))
+	for k := range e.Payload {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		text += fmt.Sprintf(" %s=%v", k, e.Payload[k])
+	}
+	return replayItem{Time: e.Time(), Source: replayEvent, Agent: e.Actor, Text: text}
+}
+
+// hookFields returns the payload fields of a hook firing log entry, or nil
+// if e isn't one.
+func hookFields(e gtlog.Entry) map[string]string {
+	if e.Msg != hooks.FiredMsg {
+		return nil
+	}
+	fields := make(map[string]string)
+	if raw, ok := e.Attrs["fields"].(map[string]any); ok {
+		for k, v := range raw {
+			if s, ok := v.(string); ok {
+				fields[k] = s
+			}
+		}
+	}
+	return fields
+}
+
+// hookItem returns the timeline entry for a hook firing.
+func hookItem(e gtlog.Entry, fields map[string]string) replayItem {
+	event, _ := e.Attrs["event"].(string)
+	script, _ := e.Attrs["script"].(string)
+	text := event + " → " + script
+	if errText, ok := e.Attrs["err"].(string); ok {
+		text += " failed: " + errText
+	}
+	agent := fields["agent"]
+	if agent == "" {
+		agent = e.Agent
+	}
+	return replayItem{Time: e.Time, Source: replayHook, Agent: agent, Text: text}
+}
+
+// commitItems returns the commits git log selects in workDir with args.
+func commitItems(workDir, agent string, args ...string) []replayItem {
+	gitArgs := append([]string{"log", "--format=%H%x1f%aI%x1f%s"}, args...)
+	c := exec.Command("git", gitArgs...)
+	c.Dir = workDir
+	out, err := c.Output()
+	if err != nil {
+		return nil
+	}
+	return parseCommitLog(string(out), agent)
+}
+
+// parseCommitLog parses git log --format=%H%x1f%aI%x1f%s output.
+func parseCommitLog(out, agent string) []replayItem {
+	var items []replayItem
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "\x1f", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, parts[1])
+		if err != nil {
+			continue
+		}
+		hash := parts[0]
+		if len(hash) > 8 {
+			hash = hash[:8]
+		}
+		items = append(items, replayItem{Time: at, Source: replayCommit, Agent: agent, Text: hash + " " + parts[2]})
+	}
+	return items
+}
+
+// sortReplay orders items by time. Entries at the same time keep their
+// order, so a transcript's chunks stay in sequence.
+func sortReplay(items []replayItem) {
+	slices.SortStableFunc(items, func(a, b replayItem) int { return a.Time.Compare(b.Time) })
+}
+
+// printReplayItem prints one entry: a JSON line with --json, else a styled
+// line with continuation lines indented.
+func printReplayItem(item replayItem) {
+	if replayJSON {
+		data, err := json.Marshal(item)
+		if err == nil {
+			fmt.Println(string(data))
+		}
+		return
+	}
+
+	var source string
+	switch item.Source {
+	case replayCommit:
+		source = style.Success.Render(fmt.Sprintf("%-10s", item.Source))
+	case replayStage, replayEvent:
+		source = style.Bold.Render(fmt.Sprintf("%-10s", item.Source))
+	case replayHook:
+		source = style.Warning.Render(fmt.Sprintf("%-10s", item.Source))
+	default:
+		source = style.Dim.Render(fmt.Sprintf("%-10s", item.Source))
+	}
+	prefix := fmt.Sprintf("%s %s ", style.Dim.Render(item.Time.Local().Format("2006-01-02 15:04:05")), source)
+	lines := strings.Split(item.Text, "\n")
+	agent := ""
+	if item.Agent != "" {
+		agent = style.Dim.Render(item.Agent) + " "
+	}
+	fmt.Println(prefix + agent + lines[0])
+	for _, line := range lines[1:] {
+		fmt.Println(strings.Repeat(" ", 31) + line)
+	}
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func TestShortenTranscript(t *testing.T) {
+	text := "\n  one\n\ntwo\nthree\nfour\n"
+	if got, want := shortenTranscript(text, 2), "one\ntwo\n… 2 more lines"; got != want {
+		t.Errorf("shortenTranscript(2) = %q, want %q", got, want)
+	}
+	if got, want := shortenTranscript(text, 0), "one\ntwo\nthree\nfour"; got != want {
+		t.Errorf("shortenTranscript(0) = %q, want %q", got, want)
+	}
+	if got := shortenTranscript("\n \n", 3); got != "" {
+		t.Errorf("shortenTranscript(blank) = %q, want empty", got)
+	}
+}
+
+func TestHoldWindows(t *testing.T) {
+	at := func(min int) time.Time { return time.Date(2026, 1, 2, 3, min, 0, 0, time.UTC) }
+	history := []beads.Transition{
+		{To: beads.StageAssigned, Agent: "gastown/polecats/Toast", At: at(0)},
+		{To: beads.StageInProgress, Agent: "gastown/polecats/Toast", At: at(1)},
+		{To: beads.StageQueued, At: at(5)},
+		{To: beads.StageAssigned, Agent: "gastown/crew/max", At: at(10)},
+		{To: beads.StageDone, Agent: "gastown/crew/max", At: at(20)},
+	}
+	windows := holdWindows(history)
+	if got, want := windows["gastown/polecats/Toast"], (replayWindow{start: at(0), end: at(5)}); got != want {
+		t.Errorf("Toast window = %v, want %v", got, want)
+	}
+	if got, want := windows["gastown/crew/max"], (replayWindow{start: at(10), end: at(20)}); got != want {
+		t.Errorf("max window = %v, want %v", got, want)
+	}
+
+	// Still held: open-ended
+	windows = holdWindows(history[:2])
+	if w := windows["gastown/polecats/Toast"]; !w.end.IsZero() || !w.contains(at(59)) {
+		t.Errorf("held window = %v, want open-ended", w)
+	}
+}
+
+func TestParseCommitLog(t *testing.T) {
+	out := "0123456789abcdef\x1f2026-01-02T03:04:05Z\x1fFix parser (gt-abc12)\nbogus line\n"
+	items := parseCommitLog(out, "gastown/crew/max")
+	if len(items) != 1 {
+		t.Fatalf("parseCommitLog() = %v, want 1 item", items)
+	}
+	if items[0].Text != "01234567 Fix parser (gt-abc12)" || items[0].Source != replayCommit {
+		t.Errorf("item = %+v", items[0])
+	}
+	if !items[0].Time.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("time = %v", items[0].Time)
+	}
+}
+
+func TestSortReplayKeepsTies(t *testing.T) {
+	t0 := time.Unix(100, 0)
+	items := []replayItem{
+		{Time: t0.Add(time.Second), Text: "later"},
+		{Time: t0, Text: "first"},
+		{Time: t0, Text: "second"},
+	}
+	sortReplay(items)
+	if items[0].Text != "first" || items[1].Text != "second" || items[2].Text != "later" {
+		t.Errorf("sortReplay() = %+v", items)
+	}
+}
@@ -16,8 +16,9 @@ import (
 var resumeCmd = &cobra.Command{
 	Use:     "resume",
 	GroupID: GroupWork,
-	Short:   "Resume from parked work or check for handoff messages",
-	Long: `Resume work that was parked on a gate, or check for handoff messages.
+	Short:   "Resume parked work, handoff messages, or sessions after a reboot",
+	Long: `Resume work that was parked on a gate, check for handoff messages, or
+recreate agent sessions after a reboot.
 
 By default, this command checks for parked work (from 'gt park') and whether
 its gate has cleared. If the gate is closed, it restores your work context.
@@ -34,7 +35,9 @@ The resume command:
 Examples:
   gt resume              # Check for and resume parked work
   gt resume --status     # Just show parked work status without resuming
-  gt resume --handoff    # Check inbox for handoff messages`,
+  gt resume --handoff    # Check inbox for handoff messages
+  gt resume --all        # Recreate recorded sessions after a reboot
+  gt resume --all -n     # Show what --all would recreate`,
 	RunE: runResume,
 }
 
@@ -42,12 +45,18 @@ var (
 	resumeStatusOnly bool
 	resumeJSON       bool
 	resumeHandoff    bool
+	resumeAll        bool
+	resumeDryRun     bool
+	resumeFlag       string
 )
 
 func init() {
 	resumeCmd.Flags().BoolVar(&resumeStatusOnly, "status", false, "Just show parked work status")
 	resumeCmd.Flags().BoolVar(&resumeJSON, "json", false, "Output as JSON")
 	resumeCmd.Flags().BoolVar(&resumeHandoff, "handoff", false, "Check for handoff messages instead of parked work")
+	resumeCmd.Flags().BoolVar(&resumeAll, "all", false, "Recreate every recorded agent session that isn't running")
+	resumeCmd.Flags().BoolVarP(&resumeDryRun, "dry-run", "n", false, "With --all, show what would be recreated")
+	resumeCmd.Flags().StringVar(&resumeFlag, "resume-flag", "", "With --all, resume agents with this flag instead of their preset's")
 	rootCmd.AddCommand(resumeCmd)
 }
 
@@ -61,6 +70,19 @@ type ResumeStatus struct {
 }
 
 func runResume(cmd *cobra.Command, args []string) error {
+	if resumeAll {
+		if resumeHandoff || resumeStatusOnly {
+			return fmt.Errorf("--all cannot be combined with --handoff or --status")
+		}
+		return runResumeAll()
+	}
+	if resumeDryRun {
+		return fmt.Errorf("--dry-run requires --all")
+	}
+	if resumeFlag != "" {
+		return fmt.Errorf("--resume-flag requires --all")
+	}
+
 	// If --handoff flag, check for handoff messages instead
 	if resumeHandoff {
 		return checkHandoffMessages()
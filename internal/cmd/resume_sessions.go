@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// sessionResume is how gt resume --all brings back one manifest entry.
+type sessionResume struct {
+	Entry   session.ManifestEntry
+	Agent   string            // Resolved agent preset
+	Env     map[string]string // Role identity, also set on the tmux session
+	Command string            // Run in the new session
+	Resumed bool              // Command continues the recorded conversation
+}
+
+// runResumeAll recreates every session in the manifest that isn't running,
+// resuming each agent's recorded conversation where the agent supports it.
+func runResumeAll() error {
+	path := session.ManifestPath()
+	entries, err := session.LoadManifest(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Printf("%s No sessions recorded in %s\n", style.Dim.Render("○"), path)
+		return nil
+	}
+
+	t := tmux.NewTmux()
+	var created, failed int
+	for _, e := range entries {
+		if running, _ := t.HasSession(e.Session); running {
+			fmt.Printf("%s %s %s\n", style.Dim.Render("○"), e.Session, style.Dim.Render("already running"))
+			continue
+		}
+		if _, err := os.Stat(e.WorkDir); err != nil {
+			// The worktree is gone (e.g. a nuked polecat); it can't come back
+			fmt.Printf("%s %s %s\n", style.WarningPrefix, e.Session, style.Dim.Render("work dir gone, forgetting: "+e.WorkDir))
+			if !resumeDryRun {
+				session.Forget(e.Session)
+			}
+			continue
+		}
+
+		if resumeFlag != "" {
+			e.ResumeFlag = resumeFlag
+		}
+		plan, err := planSessionResume(e)
+		if err != nil {
+			failed++
+			fmt.Printf("%s %s %v\n", style.ErrorPrefix, e.Session, err)
+			continue
+		}
+		how := "resuming " + plan.Agent + " conversation " + e.AgentSessionID
+		if !plan.Resumed {
+			how = "starting " + plan.Agent + " fresh (no resumable conversation)"
+		}
+		if resumeDryRun {
+			fmt.Printf("%s %s would be %s\n    %s\n", style.Dim.Render("→"), e.Session, how, style.Dim.Render(plan.Command))
+			continue
+		}
+		if err := startResumedSession(t, plan); err != nil {
+			failed++
+			fmt.Printf("%s %s %v\n", style.ErrorPrefix, e.Session, err)
+			continue
+		}
+		created++
+		fmt.Printf("%s %s %s\n", style.SuccessPrefix, e.Session, style.Dim.Render(how))
+	}
+
+	if !resumeDryRun {
+		fmt.Printf("\n%d session(s) recreated, %d failed\n", created, failed)
+	}
+	if failed > 0 {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// planSessionResume works out the environment and command that recreate e:
+// the agent's resume command for the recorded conversation, or a fresh
+// startup when the agent can't resume or no conversation was recorded.
+func planSessionResume(e session.ManifestEntry) (*sessionResume, error) {
+	identity, err := session.ParseSessionName(e.Session)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse session name: %w", err)
+	}
+	var rigPath string
+	if identity.Rig != "" {
+		rigPath = filepath.Join(e.TownRoot, identity.Rig)
+	}
+
+	plan := &sessionResume{Entry: e, Agent: e.Agent}
+	if plan.Agent == "" {
+		plan.Agent, _ = config.ResolveRoleAgentName(string(identity.Role), e.TownRoot, rigPath)
+	}
+	plan.Env = config.AgentEnv(config.AgentEnvConfig{
+		Role:      string(identity.Role),
+		Rig:       identity.Rig,
+		AgentName: identity.Name,
+		TownRoot:  e.TownRoot,
+	})
+	if e.Agent != "" {
+		plan.Env["GT_AGENT"] = e.Agent
+	}
+	// The model the session ran is passed to its resume as to a fresh start
+	var rc *config.RuntimeConfig
+	if e.Model != "" {
+		plan.Env["GT_MODEL"] = e.Model
+		rc = &config.RuntimeConfig{Model: e.Model}
+	}
+	// Likewise a resume flag override, which the resume itself uses
+	if e.ResumeFlag != "" {
+		plan.Env["GT_RESUME_FLAG"] = e.ResumeFlag
+		if rc == nil {
+			rc = &config.RuntimeConfig{}
+		}
+		rc.ResumeFlag = e.ResumeFlag
+	}
+
+	resume, err := config.BuildResumeCommandWithConfig(plan.Agent, e.AgentSessionID, rc)
+	if err != nil {
+		return nil, err
+	}
+	if resume != "" {
+		plan.Resumed = true
+		plan.Command = fmt.Sprintf("cd %s && %sexec %s", config.ShellQuote(e.WorkDir), config.ExportPrefix(plan.Env), resume)
+		return plan, nil
+	}
+
+	beacon := session.FormatStartupBeacon(session.BeaconConfig{
+		Recipient: identity.Address(),
+		Sender:    "self",
+		Topic:     "cold-start",
+	})
+	startup, err := config.BuildStartupCommandWithAgentOverride(plan.Env, rigPath, beacon, plan.Agent)
+	if err != nil {
+		return nil, fmt.Errorf("building startup command: %w", err)
+	}
+	plan.Command = fmt.Sprintf("cd %s && %s", config.ShellQuote(e.WorkDir), startup)
+	return plan, nil
+}
+
+// startResumedSession creates the tmux session for plan.
+func startResumedSession(t *tmux.Tmux, plan *sessionResume) error {
+	if err := t.NewSessionWithCommand(plan.Entry.Session, plan.Entry.WorkDir, plan.Command); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	// Set environment variables (non-fatal: session works without these)
+	for k, v := range plan.Env {
+		_ = t.SetEnvironment(plan.Entry.Session, k, v)
+	}
+	// Restore its gt tag labels (non-fatal, likewise)
+	for k, v := range plan.Entry.Tags {
+		_ = t.SetSessionTag(plan.Entry.Session, k, v)
+	}
+	return nil
+}
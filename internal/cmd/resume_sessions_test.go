@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+func TestPlanSessionResume(t *testing.T) {
+	town := t.TempDir()
+	e := session.ManifestEntry{
+		Session:        "gt-gastown-crew-max",
+		Role:           "crew",
+		Rig:            "gastown",
+		Agent:          "claude",
+		WorkDir:        town + "/gastown/crew/max",
+		TownRoot:       town,
+		AgentSessionID: "abc-123",
+	}
+
+	plan, err := planSessionResume(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Resumed || plan.Agent != "claude" {
+		t.Errorf("plan = %+v, want a claude resume", plan)
+	}
+	for _, want := range []string{"cd " + e.WorkDir + " && ", "GT_ROLE=gastown/crew/max", "GT_AGENT=claude", "--resume abc-123"} {
+		if !strings.Contains(plan.Command, want) {
+			t.Errorf("command %q missing %q", plan.Command, want)
+		}
+	}
+	if plan.Env["GT_ROOT"] != town || plan.Env["GT_CREW"] != "max" {
+		t.Errorf("env = %v", plan.Env)
+	}
+
+	// Without a recorded conversation the agent starts fresh
+	e.AgentSessionID = ""
+	plan, err = planSessionResume(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Resumed || strings.Contains(plan.Command, "--resume") {
+		t.Errorf("plan = %+v, want a fresh start", plan)
+	}
+	if !strings.Contains(plan.Command, "cold-start") {
+		t.Errorf("command %q missing the startup beacon", plan.Command)
+	}
+}
+
+func TestPlanSessionResumeKeepsModel(t *testing.T) {
+	town := t.TempDir()
+	e := session.ManifestEntry{
+		Session:        "gt-gastown-crew-max",
+		Role:           "crew",
+		Rig:            "gastown",
+		Agent:          "codex",
+		Model:          "o3",
+		WorkDir:        town + "/gastown/crew/max",
+		TownRoot:       town,
+		AgentSessionID: "abc-123",
+	}
+
+	plan, err := planSessionResume(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"GT_MODEL=o3", "resume abc-123", "-m o3"} {
+		if !strings.Contains(plan.Command, want) {
+			t.Errorf("command %q missing %q", plan.Command, want)
+		}
+	}
+	if plan.Env["GT_MODEL"] != "o3" {
+		t.Errorf("env = %v, want GT_MODEL=o3 for the session", plan.Env)
+	}
+}
+
+func TestPlanSessionResumeKeepsResumeFlag(t *testing.T) {
+	town := t.TempDir()
+	e := session.ManifestEntry{
+		Session:        "gt-gastown-crew-max",
+		Role:           "crew",
+		Rig:            "gastown",
+		Agent:          "claude",
+		ResumeFlag:     "--continue",
+		WorkDir:        town + "/gastown/crew/max",
+		TownRoot:       town,
+		AgentSessionID: "abc-123",
+	}
+
+	plan, err := planSessionResume(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(plan.Command, "--continue abc-123") || strings.Contains(plan.Command, "--resume abc-123") {
+		t.Errorf("command %q, want the session resumed with --continue", plan.Command)
+	}
+	if plan.Env["GT_RESUME_FLAG"] != "--continue" {
+		t.Errorf("env = %v, want GT_RESUME_FLAG=--continue for the session", plan.Env)
+	}
+}
+
+func TestPlanSessionResumeBadSessionName(t *testing.T) {
+	if _, err := planSessionResume(session.ManifestEntry{Session: "scratch", WorkDir: t.TempDir()}); err == nil {
+		t.Error("want an error for a non-agent session name")
+	}
+}
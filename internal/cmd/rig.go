@@ -37,7 +37,8 @@ A rig is a container for managing a project and its agents:
   - crew/<name>/   Human workspace(s)
   - witness/       Witness agent (no clone)
   - polecats/      Worker directories
-  - .beads/        Rig-level issue tracking`,
+  - .beads/        Rig-level issue tracking
+  - rig.yaml       Declared agents and crew (gt rig validate, gt rig apply)`,
 }
 
 var rigAddCmd = &cobra.Command{
@@ -0,0 +1,289 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/crew"
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/style"
+)
+
+var (
+	rigApplyDryRun bool
+	rigApplyPrune  bool
+)
+
+var rigValidateCmd = &cobra.Command{
+	Use:   "validate <rig>",
+	Short: "Check a rig's rig.yaml",
+	Long: `Check a rig's rig.yaml without changing anything.
+
+rig.yaml is read from the rig directory, or the root of its project
+checkout (mayor/rig). Misspelled keys, unknown agents and roles, agent
+presets without a command or with a bad resume_style, and malformed crew
+names are all reported at once.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigValidate,
+}
+
+var rigApplyCmd = &cobra.Command{
+	Use:   "apply <rig>",
+	Short: "Reconcile a rig with its rig.yaml",
+	Long: `Make a rig match its rig.yaml.
+
+apply validates the spec, then:
+  - writes agent and role_agents to the rig settings (settings/config.json)
+  - writes agents to the rig agent registry (settings/agents.json)
+  - creates declared crew workers that don't exist yet
+  - starts declared crew sessions that aren't running, with the spec's env
+  - stops sessions of crew declared with running: false
+
+Crew sessions running but not declared are reported, and stopped only with
+--prune. Crew workspaces are never removed.
+
+Examples:
+  gt rig apply gastown --dry-run
+  gt rig apply gastown --prune`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRigApply,
+}
+
+func init() {
+	rigApplyCmd.Flags().BoolVar(&rigApplyDryRun, "dry-run", false, "Show what would change without changing it")
+	rigApplyCmd.Flags().BoolVar(&rigApplyPrune, "prune", false, "Stop running crew sessions not declared in rig.yaml")
+
+	rigCmd.AddCommand(rigValidateCmd)
+	rigCmd.AddCommand(rigApplyCmd)
+}
+
+// loadRigSpec finds, parses and validates a rig's rig.yaml.
+func loadRigSpec(townRoot string, r *rig.Rig) (*config.RigSpec, string, error) {
+	path := config.FindRigSpec(r.Path)
+	if path == "" {
+		return nil, "", fmt.Errorf("rig %s has no %s", r.Name, config.RigSpecFile)
+	}
+	spec, err := config.LoadRigSpec(path)
+	if err != nil {
+		return nil, path, err
+	}
+	if err := spec.Validate(rigAgentKnown(townRoot, r.Path)); err != nil {
+		return nil, path, fmt.Errorf("%s: %w", path, err)
+	}
+	return spec, path, nil
+}
+
+// rigAgentKnown reports whether an agent name resolves for the rig: a
+// preset (built-in, user, town or rig registry) or a town/rig custom agent.
+func rigAgentKnown(townRoot, rigPath string) func(string) bool {
+	_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot))
+	_ = config.LoadRigAgentRegistry(config.RigAgentRegistryPath(rigPath))
+	custom := make(map[string]bool)
+	if ts, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil {
+		for name := range ts.Agents {
+			custom[name] = true
+		}
+	}
+	if rs, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil {
+		for name := range rs.Agents {
+			custom[name] = true
+		}
+	}
+	return func(name string) bool {
+		return custom[name] || config.IsKnownPreset(name)
+	}
+}
+
+func runRigValidate(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	spec, path, err := loadRigSpec(townRoot, r)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s is valid (%d agents, %d crew)\n", style.Success.Render("✓"), path, len(spec.Agents), len(spec.Crew))
+	return nil
+}
+
+// Crew actions of a rig apply.
+const (
+	rigActionAdd   = "add"
+	rigActionStart = "start"
+	rigActionStop  = "stop"
+	rigActionExtra = "undeclared" // Running but not declared; stopped with --prune
+)
+
+// rigApplyAction is one crew change a rig apply makes.
+type rigApplyAction struct {
+	Kind string
+	Crew config.CrewSpec
+}
+
+// planRigApply compares the declared crew with the existing workers and
+// running sessions, returning the crew changes in order: adds, starts,
+// stops, then undeclared sessions (sorted by name).
+func planRigApply(spec *config.RigSpec, existing []string, running map[string]bool) []rigApplyAction {
+	have := make(map[string]bool)
+	for _, name := range existing {
+		have[name] = true
+	}
+
+	var adds, starts, stops []rigApplyAction
+	declared := make(map[string]bool)
+	for _, c := range spec.Crew {
+		declared[c.Name] = true
+		if !have[c.Name] {
+			adds = append(adds, rigApplyAction{Kind: rigActionAdd, Crew: c})
+		}
+		switch {
+		case c.ShouldRun() && !running[c.Name]:
+			starts = append(starts, rigApplyAction{Kind: rigActionStart, Crew: c})
+		case !c.ShouldRun() && running[c.Name]:
+			stops = append(stops, rigApplyAction{Kind: rigActionStop, Crew: c})
+		}
+	}
+
+	var extra []string
+	for name, up := range running {
+		if up && !declared[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+
+	actions := append(append(adds, starts...), stops...)
+	for _, name := range extra {
+		actions = append(actions, rigApplyAction{Kind: rigActionExtra, Crew: config.CrewSpec{Name: name}})
+	}
+	return actions
+}
+
+func runRigApply(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	spec, path, err := loadRigSpec(townRoot, r)
+	if err != nil {
+		return err
+	}
+
+	crewMgr := crew.NewManager(r, git.NewGit(r.Path))
+	workers, err := crewMgr.List()
+	if err != nil {
+		return fmt.Errorf("listing crew: %w", err)
+	}
+	existing := make([]string, 0, len(workers))
+	running := make(map[string]bool)
+	for _, w := range workers {
+		existing = append(existing, w.Name)
+		if up, err := crewMgr.IsRunning(w.Name); err == nil && up {
+			running[w.Name] = true
+		}
+	}
+	actions := planRigApply(spec, existing, running)
+
+	prefix := ""
+	if rigApplyDryRun {
+		prefix = "Would "
+	}
+	fmt.Printf("%s Applying %s\n", style.Bold.Render("⚙"), path)
+
+	if !rigApplyDryRun {
+		if err := applyRigSpecSettings(r.Path, spec); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("  %swrite rig settings and %d agent presets\n", prefix, len(spec.Agents))
+
+	var failed int
+	for _, a := range actions {
+		var err error
+		switch a.Kind {
+		case rigActionAdd:
+			fmt.Printf("  %sadd crew %s\n", prefix, a.Crew.Name)
+			if !rigApplyDryRun {
+				_, err = crewMgr.Add(a.Crew.Name, false)
+			}
+		case rigActionStart:
+			fmt.Printf("  %sstart %s\n", prefix, crewMgr.SessionName(a.Crew.Name))
+			if !rigApplyDryRun {
+				err = crewMgr.Start(a.Crew.Name, crew.StartOptions{
+					AgentOverride: a.Crew.Agent,
+					Env:           spec.CrewEnv(a.Crew),
+				})
+			}
+		case rigActionStop:
+			fmt.Printf("  %sstop %s\n", prefix, crewMgr.SessionName(a.Crew.Name))
+			if !rigApplyDryRun {
+				if err = crewMgr.Stop(a.Crew.Name); errors.Is(err, crew.ErrSessionNotFound) {
+					err = nil
+				}
+			}
+		case rigActionExtra:
+			if !rigApplyPrune {
+				fmt.Printf("  %s %s is running but not declared (stop it with --prune)\n",
+					style.Warning.Render("!"), crewMgr.SessionName(a.Crew.Name))
+				continue
+			}
+			fmt.Printf("  %sstop undeclared %s\n", prefix, crewMgr.SessionName(a.Crew.Name))
+			if !rigApplyDryRun {
+				if err = crewMgr.Stop(a.Crew.Name); errors.Is(err, crew.ErrSessionNotFound) {
+					err = nil
+				}
+			}
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("    %s %v\n", style.Error.Render("✗"), err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d crew changes failed", failed, len(actions))
+	}
+	if !rigApplyDryRun {
+		fmt.Printf("%s Rig %s matches %s\n", style.Success.Render("✓"), r.Name, config.RigSpecFile)
+	}
+	return nil
+}
+
+// applyRigSpecSettings writes the spec's agent selection to the rig
+// settings and its presets to the rig agent registry, keeping whatever
+// else those files hold.
+func applyRigSpecSettings(rigPath string, spec *config.RigSpec) error {
+	settingsPath := config.RigSettingsPath(rigPath)
+	settings, err := config.LoadRigSettings(settingsPath)
+	if errors.Is(err, config.ErrNotFound) {
+		settings = config.NewRigSettings()
+	} else if err != nil {
+		return err
+	}
+	settings.Agent = spec.Agent
+	settings.RoleAgents = spec.RoleAgents
+	if err := config.SaveRigSettings(settingsPath, settings); err != nil {
+		return fmt.Errorf("saving rig settings: %w", err)
+	}
+
+	if len(spec.Agents) == 0 {
+		return nil
+	}
+	registryPath := config.RigAgentRegistryPath(rigPath)
+	registry, err := config.LoadAgentRegistryFile(registryPath)
+	if err != nil {
+		return err
+	}
+	for name, preset := range spec.Agents {
+		registry.Agents[name] = preset
+	}
+	if err := config.SaveAgentRegistry(registryPath, registry); err != nil {
+		return fmt.Errorf("saving rig agents: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestPlanRigApply(t *testing.T) {
+	stopped := false
+	spec := &config.RigSpec{Crew: []config.CrewSpec{
+		{Name: "max"},                     // exists, stopped -> start
+		{Name: "new"},                     // missing -> add, start
+		{Name: "jane", Running: &stopped}, // running -> stop
+		{Name: "joe"},                     // running -> nothing
+	}}
+	existing := []string{"max", "jane", "joe", "zed", "amy"}
+	running := map[string]bool{"jane": true, "joe": true, "zed": true, "amy": true}
+
+	var got []string
+	for _, a := range planRigApply(spec, existing, running) {
+		got = append(got, a.Kind+" "+a.Crew.Name)
+	}
+	want := []string{"add new", "start max", "start new", "stop jane", "undeclared amy", "undeclared zed"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("planRigApply() = %q, want %q", got, want)
+	}
+}
+
+func TestApplyRigSpecSettings(t *testing.T) {
+	rigPath := t.TempDir()
+	existing := config.NewRigSettings()
+	existing.Workflow = &config.WorkflowConfig{DefaultFormula: "ship"}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), existing); err != nil {
+		t.Fatal(err)
+	}
+
+	spec := &config.RigSpec{
+		Agent:      "kimi-fast",
+		RoleAgents: map[string]string{"witness": "claude"},
+		Agents:     map[string]*config.AgentPresetInfo{"kimi-fast": {Name: "kimi-fast", Command: "kimi"}},
+	}
+	if err := applyRigSpecSettings(rigPath, spec); err != nil {
+		t.Fatalf("applyRigSpecSettings: %v", err)
+	}
+
+	settings, err := config.LoadRigSettings(config.RigSettingsPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Agent != "kimi-fast" || settings.RoleAgents["witness"] != "claude" {
+		t.Errorf("settings = %+v, want the spec's agents", settings)
+	}
+	if settings.Workflow == nil || settings.Workflow.DefaultFormula != "ship" {
+		t.Error("applying the spec dropped unrelated rig settings")
+	}
+	registry, err := config.LoadAgentRegistryFile(config.RigAgentRegistryPath(rigPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preset := registry.Agents["kimi-fast"]; preset == nil || preset.Command != "kimi" {
+		t.Errorf("rig agents = %+v, want kimi-fast", registry.Agents)
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	rigHostPath  string
+	rigHostClear bool
+)
+
+var rigHostCmd = &cobra.Command{
+	Use:   "host <rig> [host]",
+	Short: "Show or set the SSH host a rig's sessions run on",
+	Long: `Show or set the remote machine a rig lives on.
+
+A rig with a host runs its sessions there: gt runs tmux on the host over
+ssh, lists its sessions with the rest, and attaches to them through ssh -t.
+The host is an ssh destination (user@host or a ~/.ssh/config alias) and
+must accept key authentication, since gt never prompts for a password.
+
+Working directories under the rig are passed to the host with the rig
+directory replaced by --path, for a rig checked out elsewhere on the host.
+Without --path the paths are the same on both machines.
+
+Examples:
+  gt rig host gastown                              # Show the host
+  gt rig host gastown build-box                    # Run sessions on build-box
+  gt rig host gastown me@build-box --path /srv/gt/gastown
+  gt rig host gastown --clear                      # Back to this machine`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runRigHost,
+}
+
+func init() {
+	rigHostCmd.Flags().StringVar(&rigHostPath, "path", "", "Rig directory on the host (default: same as local)")
+	rigHostCmd.Flags().BoolVar(&rigHostClear, "clear", false, "Run the rig's sessions on this machine again")
+
+	rigCmd.AddCommand(rigHostCmd)
+}
+
+func runRigHost(cmd *cobra.Command, args []string) error {
+	rigName := args[0]
+	if rigHostClear && len(args) > 1 {
+		return fmt.Errorf("--clear takes no host")
+	}
+	if rigHostPath != "" && len(args) < 2 {
+		return fmt.Errorf("--path requires a host")
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	rigsPath := constants.MayorRigsPath(townRoot)
+	rigsConfig, err := config.LoadRigsConfig(rigsPath)
+	if err != nil {
+		return fmt.Errorf("loading rigs config: %w", err)
+	}
+	entry, ok := rigsConfig.Rigs[rigName]
+	if !ok {
+		return fmt.Errorf("rig '%s' not found", rigName)
+	}
+
+	switch {
+	case rigHostClear:
+		entry.Host, entry.RemotePath = "", ""
+	case len(args) > 1:
+		entry.Host, entry.RemotePath = args[1], rigHostPath
+	default:
+		if entry.Host == "" {
+			fmt.Printf("%s Rig %s runs on this machine\n", style.Dim.Render("○"), rigName)
+			return nil
+		}
+		fmt.Printf("Rig %s runs on %s\n", rigName, style.Bold.Render(entry.Host))
+		if entry.RemotePath != "" {
+			fmt.Printf("  Path: %s\n", entry.RemotePath)
+		}
+		return nil
+	}
+
+	rigsConfig.Rigs[rigName] = entry
+	if err := config.SaveRigsConfig(rigsPath, rigsConfig); err != nil {
+		return fmt.Errorf("saving rigs config: %w", err)
+	}
+	if entry.Host == "" {
+		fmt.Printf("%s Rig %s now runs on this machine\n", style.Bold.Render("✓"), rigName)
+	} else {
+		fmt.Printf("%s Rig %s now runs on %s\n", style.Bold.Render("✓"), rigName, entry.Host)
+		fmt.Printf("  Running sessions stay where they are; restart them to move.\n")
+	}
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/config"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/ui"
 	"github.com/steveyegge/gastown/internal/version"
@@ -51,9 +53,17 @@ var beadsExemptCommands = map[string]bool{
 	"rig":        true,
 	"config":     true,
 	"install":    true,
+	"init":       true, // gt init --wizard runs before bd may be installed
 	"tap":        true,
 	"dnd":        true,
 	"krc":        true, // KRC doesn't require beads
+	"record":     true, // gt transcript record runs under tmux pipe-pane
+	"usage":      true,
+	"report":     true, // gt usage report only reads ~/.gt/usage.jsonl
+	"logs":       true, // gt logs only reads ~/.gastown/logs
+	"secret":     true,
+	"get":        true, // gt secret get runs at every agent launch
+	"at":         true, // attaching only needs tmux
 }
 
 // Commands exempt from the town root branch warning.
@@ -84,6 +94,9 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	// Initialize CLI theme (dark/light mode support)
 	initCLITheme()
 
+	// Open the structured log (gt logs); never blocks the command
+	initLogging(cmd, args)
+
 	// Get the root command name being run
 	cmdName := cmd.Name()
 
@@ -106,6 +119,38 @@ func persistentPreRun(cmd *cobra.Command, args []string) error {
 	return CheckBeadsVersion()
 }
 
+// initLogging opens the structured log for the command, tagged with the
+// agent running it (GT_ROLE), or daemon/watchdog for their run loops.
+func initLogging(cmd *cobra.Command, args []string) {
+	role, agent := logRole(os.Getenv("GT_ROLE"))
+	switch cmd.CommandPath() {
+	case "gt daemon run":
+		role, agent = "daemon", ""
+	case "gt watchdog run":
+		role, agent = "watchdog", ""
+	}
+	if err := gtlog.Init(role, agent); err != nil && os.Getenv("GT_DEBUG") != "" {
+		fmt.Fprintf(os.Stderr, "WARNING: structured log: %v\n", err)
+	}
+	gtlog.L().Debug("command started", "cmd", cmd.CommandPath(), "args", args)
+}
+
+// logRole returns the role and agent address for log entries from GT_ROLE
+// (e.g., "gastown/crew/max" is role crew). Without GT_ROLE, gt was run by
+// hand and the role is "cli".
+func logRole(gtRole string) (string, string) {
+	switch {
+	case gtRole == "":
+		return "cli", ""
+	case gtRole == "deacon/boot":
+		return "boot", gtRole
+	}
+	if id, err := session.ParseAddress(gtRole); err == nil {
+		return string(id.Role), gtRole
+	}
+	return gtRole, gtRole
+}
+
 // initCLITheme initializes the CLI color theme based on settings and environment.
 func initCLITheme() {
 	// Try to load town settings for CLITheme config
@@ -218,11 +263,16 @@ func checkStaleBinaryWarning() {
 // Execute runs the root command and returns an exit code.
 // The caller (main) should call os.Exit with this code.
 func Execute() int {
-	if err := rootCmd.Execute(); err != nil {
+	if cmd, err := rootCmd.ExecuteC(); err != nil {
 		// Check for silent exit (scripting commands that signal status via exit code)
 		if code, ok := IsSilentExit(err); ok {
 			return code
 		}
+		gtlog.L().Error("command failed", "cmd", cmd.CommandPath(), "err", err)
+		// Errors that carry a distinct exit code (already printed by cobra)
+		if code, ok := ExitCode(err); ok {
+			return code
+		}
 		// Other errors already printed by cobra
 		return 1
 	}
@@ -125,3 +125,22 @@ func TestCheckHelpFlag_EdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestLogRole(t *testing.T) {
+	tests := []struct {
+		gtRole, role, agent string
+	}{
+		{"", "cli", ""},
+		{"mayor", "mayor", "mayor"},
+		{"deacon/boot", "boot", "deacon/boot"},
+		{"gastown/witness", "witness", "gastown/witness"},
+		{"gastown/crew/max", "crew", "gastown/crew/max"},
+		{"gastown/polecats/Toast", "polecat", "gastown/polecats/Toast"},
+	}
+	for _, tt := range tests {
+		role, agent := logRole(tt.gtRole)
+		if role != tt.role || agent != tt.agent {
+			t.Errorf("logRole(%q) = (%q, %q), want (%q, %q)", tt.gtRole, role, agent, tt.role, tt.agent)
+		}
+	}
+}
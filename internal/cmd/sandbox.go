@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/sandbox"
+)
+
+var (
+	sandboxDir        string
+	sandboxWritable   []string
+	sandboxAllowHosts []string
+)
+
+var sandboxCmd = &cobra.Command{
+	Use:     "sandbox",
+	GroupID: GroupAgents,
+	Short:   "Confine agents' filesystem and network access",
+	Long: `Run agents in a sandbox: bubblewrap (bwrap) on Linux, sandbox-exec on
+macOS. A sandboxed agent can write only to its worktree, the temp
+directory and its own state directories (e.g. ~/.claude), and reach only
+its API hosts. Everything else on disk is read-only.
+
+Enable it on an agent with "sandbox" in its config:
+
+  "agents": {
+    "kimi-safe": {
+      "command": "kimi",
+      "args": ["--yolo"],
+      "sandbox": {
+        "enabled": true,
+        "writable": ["~/.cache/uv"],
+        "allow_hosts": ["github.com", "*.githubusercontent.com"]
+      }
+    }
+  }
+
+Built-in agents know their API hosts and state directories; "writable" and
+"allow_hosts" add to them. The agent's launch command is then wrapped in
+gt sandbox exec.`,
+	RunE: requireSubcommand,
+}
+
+var sandboxExecCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command in the sandbox",
+	Long: `Run a command in the sandbox, confined to --dir (default: the current
+directory) and the --writable paths.
+
+Network access is denied, except to --allow-host hosts, reached through an
+allow-list HTTP proxy that gt runs for the life of the command and points
+the command at with HTTPS_PROXY. On Linux bwrap can't filter by host, so
+with allowed hosts the sandbox shares the network and only clients that
+honor the proxy variables are held to the list; without any, the network
+is cut off entirely.
+
+Examples:
+  gt sandbox exec -- make test
+  gt sandbox exec --writable ~/.kimi --allow-host api.moonshot.ai -- kimi --yolo`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSandboxExec,
+}
+
+func init() {
+	sandboxExecCmd.Flags().StringVar(&sandboxDir, "dir", "", "Directory the command may write (default: current directory)")
+	sandboxExecCmd.Flags().StringArrayVar(&sandboxWritable, "writable", nil, "Further absolute or ~/ path the command may write (repeatable)")
+	sandboxExecCmd.Flags().StringArrayVar(&sandboxAllowHosts, "allow-host", nil, "Host the command may reach over HTTP(S), e.g. *.example.com (repeatable)")
+
+	sandboxCmd.AddCommand(sandboxExecCmd)
+	rootCmd.AddCommand(sandboxCmd)
+}
+
+func runSandboxExec(cmd *cobra.Command, args []string) error {
+	policy, err := sandboxPolicy()
+	if err != nil {
+		return err
+	}
+
+	env := os.Environ()
+	if len(sandboxAllowHosts) > 0 {
+		proxy, err := sandbox.StartProxy(sandboxAllowHosts)
+		if err != nil {
+			return fmt.Errorf("starting sandbox proxy: %w", err)
+		}
+		defer proxy.Close()
+		policy.Proxy = proxy.Addr()
+		env = append(env, sandbox.ProxyEnv(proxy.Addr())...)
+	}
+
+	argv, err := sandbox.Command(runtime.GOOS, policy, args)
+	if err != nil {
+		return err
+	}
+
+	// Run as a child rather than exec: the proxy lives in this process
+	c := exec.Command(argv[0], argv[1:]...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	c.Env = env
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return NewSilentExit(exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+// sandboxPolicy returns the policy for the --dir, --writable and
+// --allow-host flags.
+func sandboxPolicy() (sandbox.Policy, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return sandbox.Policy{}, err
+	}
+	dir, err := filepath.Abs(sandboxDir) // "" is the current directory
+	if err != nil {
+		return sandbox.Policy{}, err
+	}
+
+	var policy sandbox.Policy
+	for _, path := range append([]string{dir}, sandboxWritable...) {
+		expanded, err := sandbox.ExpandPath(path, home)
+		if err != nil {
+			return sandbox.Policy{}, err
+		}
+		policy.Writable = append(policy.Writable, expanded)
+	}
+	for _, host := range sandboxAllowHosts {
+		if err := sandbox.ValidHost(host); err != nil {
+			return sandbox.Policy{}, err
+		}
+	}
+	return policy, nil
+}
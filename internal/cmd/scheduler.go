@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/scheduler"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var schedulerCmd = &cobra.Command{
+	Use:     "scheduler",
+	GroupID: GroupConfig,
+	Short:   "Show agent concurrency caps and queued session starts",
+	Long: `Show the scheduler's agent caps, how many crew and polecat sessions are
+running, and the starts queued until a slot frees up.
+
+The scheduler is configured in settings/config.json (town-wide) and
+<rig>/settings/config.json (per rig):
+
+  "scheduler": {
+    "max_concurrent_agents": 4,  // cap on running crew + polecat sessions
+    "nice": 10,                  // CPU niceness of agent processes
+    "oom_score_adj": 500         // Linux: kill agents first when out of memory
+  }
+
+A crew or polecat start beyond a cap is queued instead of started; the
+daemon starts queued sessions, oldest first, as running agents exit. The
+mayor, deacon, witnesses and refineries aren't counted or queued. A rig's
+nice and oom_score_adj override the town's.
+
+Examples:
+  gt scheduler                        # Caps, running agents, queue
+  gt scheduler drain                  # Start what fits now, without the daemon
+  gt scheduler clear gt-gastown-Toast # Drop a queued start`,
+	Args: cobra.NoArgs,
+	RunE: runScheduler,
+}
+
+var schedulerDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Start queued sessions that fit under the caps now",
+	Args:  cobra.NoArgs,
+	RunE:  runSchedulerDrain,
+}
+
+var schedulerClearCmd = &cobra.Command{
+	Use:   "clear [session...]",
+	Short: "Remove queued starts (all of them if no session is given)",
+	RunE:  runSchedulerClear,
+}
+
+func init() {
+	schedulerCmd.AddCommand(schedulerDrainCmd, schedulerClearCmd)
+	rootCmd.AddCommand(schedulerCmd)
+}
+
+func runScheduler(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	sessions, err := tmux.NewTmux().ListSessions()
+	if err != nil {
+		sessions = nil // No tmux server: nothing running
+	}
+	queue, err := scheduler.Queue(townRoot)
+	if err != nil {
+		return err
+	}
+
+	limits := scheduler.LoadLimits(townRoot, "")
+	total, byRig := scheduler.Workers(sessions)
+	running := fmt.Sprintf("%d", total)
+	if limits.MaxAgents > 0 {
+		running = fmt.Sprintf("%d/%d", total, limits.MaxAgents)
+	}
+	fmt.Printf("%s %s agents running", style.Bold.Render("Scheduler:"), running)
+	if limits.Nice != 0 || limits.OOMScoreAdj != 0 {
+		fmt.Printf(" %s", style.Dim.Render(fmt.Sprintf("(nice %d, oom_score_adj %d)", limits.Nice, limits.OOMScoreAdj)))
+	}
+	fmt.Println()
+	for _, rig := range slices.Sorted(maps.Keys(byRig)) {
+		n := byRig[rig]
+		rigLimits := scheduler.LoadLimits(townRoot, filepath.Join(townRoot, rig))
+		count := fmt.Sprintf("%d", n)
+		if rigLimits.RigMaxAgents > 0 {
+			count = fmt.Sprintf("%d/%d", n, rigLimits.RigMaxAgents)
+		}
+		fmt.Printf("  %-20s %s\n", rig, count)
+	}
+	if !limits.Capped() && len(queue) == 0 {
+		fmt.Printf("%s\n", style.Dim.Render("No town-wide cap (set scheduler.max_concurrent_agents in settings/config.json)"))
+	}
+
+	fmt.Printf("\n%s\n", style.Bold.Render("Queued"))
+	if len(queue) == 0 {
+		fmt.Printf("  %s\n", style.Dim.Render("none"))
+	}
+	for _, req := range queue {
+		detail := fmt.Sprintf("%s, queued %s ago", req.Role, time.Since(req.QueuedAt).Round(time.Second))
+		if req.Issue != "" {
+			detail += ", issue " + req.Issue
+		}
+		fmt.Printf("  %s %s %s\n", style.Dim.Render("⏳"), req.Session(), style.Dim.Render(detail))
+	}
+	return nil
+}
+
+func runSchedulerDrain(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	gtPath, err := os.Executable()
+	if err != nil {
+		gtPath = "gt"
+	}
+	started, err := scheduler.Drain(townRoot, tmux.NewTmux().ListSessions, func(req scheduler.Request) error {
+		fmt.Printf("%s gt %s\n", style.Bold.Render("▶"), strings.Join(req.Args(), " "))
+		c := exec.Command(gtPath, req.Args()...) //nolint:gosec // G204: args are constructed internally
+		c.Dir = townRoot
+		c.Stdout = os.Stdout
+		c.Stderr = os.Stderr
+		return c.Run()
+	})
+	fmt.Printf("%d queued session(s) started\n", len(started))
+	return err
+}
+
+func runSchedulerClear(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	queue, err := scheduler.Queue(townRoot)
+	if err != nil {
+		return err
+	}
+	queued := make(map[string]bool, len(queue))
+	for _, req := range queue {
+		queued[req.Session()] = true
+		if len(args) == 0 || slices.Contains(args, req.Session()) {
+			if err := scheduler.Dequeue(townRoot, req.Session()); err != nil {
+				return err
+			}
+			fmt.Printf("%s Removed %s from the queue\n", style.SuccessPrefix, req.Session())
+		}
+	}
+	for _, name := range args {
+		if !queued[name] {
+			fmt.Printf("%s %s is not queued\n", style.WarningPrefix, name)
+		}
+	}
+	return nil
+}
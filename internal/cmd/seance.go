@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/workspace"
+	"golang.org/x/term"
 )
 
 var (
@@ -230,7 +231,12 @@ func runSeanceTalk(sessionID, prompt string) error {
 		return nil
 	}
 
-	// Interactive mode - just launch claude
+	// Interactive mode - just launch claude. It needs a terminal, so refuse
+	// up front when stdin isn't one instead of letting claude fail obscurely.
+	headless := !term.IsTerminal(int(os.Stdin.Fd()))
+	if _, err := config.ResolveLaunchMode(string(config.AgentClaude), headless); err != nil {
+		return fmt.Errorf("%w (use -p for a one-shot question)", err)
+	}
 	cmd := exec.Command("claude", args...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
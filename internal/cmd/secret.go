@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/secrets"
+	"github.com/steveyegge/gastown/internal/style"
+	"golang.org/x/term"
+)
+
+var secretCmd = &cobra.Command{
+	Use:     "secret",
+	GroupID: GroupConfig,
+	Short:   "Store agent API keys outside shell profiles",
+	Long: `Store API keys by name in the OS keychain (macOS Keychain, or libsecret
+on Linux), or in an encrypted file under ~/.gastown/secrets where there is
+no keychain. GT_SECRETS_BACKEND=keychain|file picks one explicitly.
+
+An agent config references secrets by name in "secrets", mapping the
+environment variable the agent expects to the secret holding it:
+
+  "agents": {
+    "kimi-work": {
+      "command": "kimi",
+      "args": ["--yolo"],
+      "secrets": {"MOONSHOT_API_KEY": "moonshot"}
+    }
+  }
+
+The key is read when the agent starts and set only in its environment, so
+it needn't be exported from a shell profile that every pane reads.
+
+Examples:
+  gt secret set moonshot            # Prompt for the value
+  pass show moonshot | gt secret set moonshot
+  gt secret list
+  gt secret get moonshot`,
+	RunE: requireSubcommand,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret",
+	Long: `Store a secret, replacing any previous value. The value is read from
+stdin: typed at a prompt without echo, or piped in. It is never taken as an
+argument, which would leave it in shell history.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretSet,
+}
+
+var secretGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret's value",
+	Long: `Print a secret's value, without a trailing newline. Agent launch
+commands run this to set the variables in an agent's "secrets".`,
+	Args: cobra.ExactArgs(1),
+	RunE: runSecretGet,
+}
+
+var secretListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored secret names",
+	Args:  cobra.NoArgs,
+	RunE:  runSecretList,
+}
+
+func init() {
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretGetCmd)
+	secretCmd.AddCommand(secretListCmd)
+	rootCmd.AddCommand(secretCmd)
+}
+
+func runSecretSet(cmd *cobra.Command, args []string) error {
+	store, err := secrets.Open()
+	if err != nil {
+		return err
+	}
+	value, err := readSecretValue(args[0])
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return fmt.Errorf("empty value; secret %s not stored", args[0])
+	}
+	if err := store.Set(args[0], value); err != nil {
+		return err
+	}
+	fmt.Printf("%s Stored secret %s (%s)\n", style.SuccessPrefix, args[0], store.Backend())
+	return nil
+}
+
+// readSecretValue reads a secret from the terminal without echo, or from
+// piped stdin, dropping the trailing newline either way.
+func readSecretValue(name string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		fmt.Fprintf(os.Stderr, "Value for %s: ", name)
+		value, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("reading value: %w", err)
+		}
+		return string(value), nil
+	}
+	value, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("reading value: %w", err)
+	}
+	return strings.TrimRight(string(value), "\r\n"), nil
+}
+
+func runSecretGet(cmd *cobra.Command, args []string) error {
+	store, err := secrets.Open()
+	if err != nil {
+		return err
+	}
+	value, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(value)
+	return nil
+}
+
+func runSecretList(cmd *cobra.Command, args []string) error {
+	store, err := secrets.Open()
+	if err != nil {
+		return err
+	}
+	names, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Printf("No secrets stored (%s backend, %s)\n", store.Backend(), secrets.Dir())
+		return nil
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/ui"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	selftestJSON         bool
+	selftestProbe        bool
+	selftestProbeTimeout time.Duration
+)
+
+var selftestCmd = &cobra.Command{
+	Use:     "selftest",
+	GroupID: GroupDiag,
+	Short:   "Verify agent presets and runtime command construction",
+	Long: `Run built-in assertions against the agent preset registry.
+
+For every registered agent preset (built-in, plus any from settings/agents.json
+when run inside a town) selftest checks that:
+  - the preset is well-formed (command, process names)
+  - the runtime config builds a launch command with the preset's args
+  - resume commands include the session ID (when resume is supported)
+  - MCP config is passed through, or rejected if unsupported
+
+With --probe, each agent CLI found on PATH is also invoked with --version.
+Agents that aren't installed are reported as skipped, not failed. A CLI that
+doesn't answer within --probe-timeout (default 5s) is killed and reported as
+timed out, so a hung agent binary can't stall the run.
+
+Exits non-zero if any check fails, so it can gate CI. Skips and timeouts
+don't fail the run.
+
+Examples:
+  gt selftest
+  gt selftest --probe
+  gt selftest --probe --probe-timeout 15s
+  gt selftest --json`,
+	RunE: runSelftest,
+}
+
+func init() {
+	selftestCmd.Flags().BoolVar(&selftestJSON, "json", false, "Output results as JSON")
+	selftestCmd.Flags().BoolVar(&selftestProbe, "probe", false, "Also run each installed agent CLI with --version")
+	selftestCmd.Flags().DurationVar(&selftestProbeTimeout, "probe-timeout", defaultSelftestProbeTimeout, "Give up on an agent CLI probe after this long")
+	rootCmd.AddCommand(selftestCmd)
+}
+
+// SelftestStatus is the outcome of a single selftest check.
+type SelftestStatus string
+
+const (
+	SelftestPass    SelftestStatus = "pass"
+	SelftestFail    SelftestStatus = "fail"
+	SelftestSkip    SelftestStatus = "skip"
+	SelftestTimeout SelftestStatus = "timeout"
+)
+
+// SelftestResult is the outcome of one named check.
+type SelftestResult struct {
+	Name    string         `json:"name"`
+	Status  SelftestStatus `json:"status"`
+	Message string         `json:"message,omitempty"`
+}
+
+// SelftestReport aggregates all check results.
+type SelftestReport struct {
+	OK       bool             `json:"ok"`
+	Passed   int              `json:"passed"`
+	Failed   int              `json:"failed"`
+	Skipped  int              `json:"skipped"`
+	TimedOut int              `json:"timed_out"`
+	Results  []SelftestResult `json:"results"`
+}
+
+// selftestCheck is a named assertion. run returns the status and an
+// explanatory message (used for failures and skips).
+type selftestCheck struct {
+	name string
+	run  func() (SelftestStatus, string)
+}
+
+func runSelftest(cmd *cobra.Command, args []string) error {
+	if selftestProbeTimeout <= 0 {
+		return fmt.Errorf("--probe-timeout must be positive")
+	}
+
+	// Include town-level custom presets when run inside a workspace.
+	if townRoot, err := workspace.FindFromCwd(); err == nil && townRoot != "" {
+		if err := config.LoadAgentRegistry(config.DefaultAgentRegistryPath(townRoot)); err != nil {
+			return fmt.Errorf("loading agent registry: %w", err)
+		}
+	}
+
+	report := runSelftestChecks(selftestChecks(selftestProbe, selftestProbeTimeout))
+
+	if selftestJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		printSelftestReport(report)
+	}
+
+	if !report.OK {
+		return NewSilentExit(1)
+	}
+	return nil
+}
+
+// runSelftestChecks runs every check and tallies the results.
+// A check that panics is recorded as a failure rather than aborting the run.
+func runSelftestChecks(checks []selftestCheck) *SelftestReport {
+	report := &SelftestReport{Results: make([]SelftestResult, 0, len(checks))}
+	for _, c := range checks {
+		status, msg := runSelftestCheck(c)
+		report.Results = append(report.Results, SelftestResult{Name: c.name, Status: status, Message: msg})
+		switch status {
+		case SelftestPass:
+			report.Passed++
+		case SelftestSkip:
+			report.Skipped++
+		case SelftestTimeout:
+			report.TimedOut++
+		default:
+			report.Failed++
+		}
+	}
+	report.OK = report.Failed == 0
+	return report
+}
+
+func runSelftestCheck(c selftestCheck) (status SelftestStatus, msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			status, msg = SelftestFail, fmt.Sprintf("panic: %v", r)
+		}
+	}()
+	return c.run()
+}
+
+func printSelftestReport(report *SelftestReport) {
+	fmt.Printf("%s\n\n", style.Bold.Render("Self-test"))
+	for _, r := range report.Results {
+		var icon string
+		switch r.Status {
+		case SelftestPass:
+			icon = style.SuccessPrefix
+		case SelftestSkip:
+			icon = style.Dim.Render(ui.IconSkip)
+		case SelftestTimeout:
+			icon = style.WarningPrefix
+		default:
+			icon = style.ErrorPrefix
+		}
+		fmt.Printf("  %s %s", icon, r.Name)
+		if r.Message != "" {
+			fmt.Printf("  %s", style.Dim.Render(r.Message))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n%d passed, %d failed, %d skipped", report.Passed, report.Failed, report.Skipped)
+	if report.TimedOut > 0 {
+		fmt.Printf(", %d timed out", report.TimedOut)
+	}
+	fmt.Println()
+}
+
+// selftestChecks returns the checks for every registered agent preset.
+// With probe, each installed agent CLI is also run, for at most probeTimeout.
+func selftestChecks(probe bool, probeTimeout time.Duration) []selftestCheck {
+	names := config.ListAgentPresets()
+	sort.Strings(names)
+
+	var checks []selftestCheck
+	for _, name := range names {
+		name := name
+		checks = append(checks,
+			selftestCheck{"preset/" + name, func() (SelftestStatus, string) { return checkPresetShape(name) }},
+			selftestCheck{"runtime/" + name, func() (SelftestStatus, string) { return checkPresetRuntime(name) }},
+			selftestCheck{"resume/" + name, func() (SelftestStatus, string) { return checkPresetResume(name) }},
+			selftestCheck{"mcp/" + name, func() (SelftestStatus, string) { return checkPresetMCP(name) }},
+		)
+		if probe {
+			command := config.GetAgentPresetByName(name).Command
+			checks = append(checks, selftestCheck{"probe/" + name, func() (SelftestStatus, string) {
+				return probeAgentCLI(command, probeTimeout)
+			}})
+		}
+	}
+	return checks
+}
+
+func checkPresetShape(name string) (SelftestStatus, string) {
+	p := config.GetAgentPresetByName(name)
+	switch {
+	case p == nil:
+		return SelftestFail, "preset not found"
+	case p.Command == "":
+		return SelftestFail, "empty command"
+	case len(p.ProcessNames) == 0:
+		return SelftestFail, "no process names for liveness detection"
+	case p.ResumeFlag != "" && p.ResumeStyle != "" && p.ResumeStyle != "flag" && p.ResumeStyle != "subcommand":
+		return SelftestFail, fmt.Sprintf("unknown resume style %q", p.ResumeStyle)
+	}
+	if err := config.ValidateResumeTemplate(p.ResumeTemplate); err != nil {
+		return SelftestFail, err.Error()
+	}
+	return SelftestPass, ""
+}
+
+func checkPresetRuntime(name string) (SelftestStatus, string) {
+	p := config.GetAgentPresetByName(name)
+	rc := config.RuntimeConfigFromPreset(config.AgentPreset(name))
+	cmdLine := rc.BuildCommand()
+	if !strings.Contains(cmdLine, p.Command) {
+		return SelftestFail, fmt.Sprintf("launch command %q missing %q", cmdLine, p.Command)
+	}
+	for _, arg := range p.Args {
+		if !strings.Contains(cmdLine, arg) {
+			return SelftestFail, fmt.Sprintf("launch command %q missing arg %q", cmdLine, arg)
+		}
+	}
+	return SelftestPass, ""
+}
+
+func checkPresetResume(name string) (SelftestStatus, string) {
+	p := config.GetAgentPresetByName(name)
+	if p.ResumeFlag == "" {
+		return SelftestSkip, "resume not supported"
+	}
+	const sessionID = "selftest-session"
+	resume := config.BuildResumeCommand(name, sessionID)
+	if !strings.Contains(resume, sessionID) || !strings.Contains(resume, p.ResumeFlag) {
+		return SelftestFail, fmt.Sprintf("resume command %q missing %s %s", resume, p.ResumeFlag, sessionID)
+	}
+	return SelftestPass, ""
+}
+
+func checkPresetMCP(name string) (SelftestStatus, string) {
+	p := config.GetAgentPresetByName(name)
+	rc := config.RuntimeConfigFromPreset(config.AgentPreset(name))
+	rc.MCPConfig = "/tmp/selftest-mcp.json"
+
+	err := rc.Validate()
+	if p.MCPConfigFlag == "" {
+		if !errors.Is(err, config.ErrMCPConfigUnsupported) {
+			return SelftestFail, fmt.Sprintf("expected ErrMCPConfigUnsupported, got %v", err)
+		}
+		return SelftestPass, ""
+	}
+	if err != nil {
+		return SelftestFail, err.Error()
+	}
+	if cmdLine := rc.BuildCommand(); !strings.Contains(cmdLine, p.MCPConfigFlag+" "+rc.MCPConfig) {
+		return SelftestFail, fmt.Sprintf("launch command %q missing %s", cmdLine, p.MCPConfigFlag)
+	}
+	return SelftestPass, ""
+}
+
+// defaultSelftestProbeTimeout bounds how long an agent CLI may take to
+// print its version (--probe-timeout).
+const defaultSelftestProbeTimeout = 5 * time.Second
+
+// probeAgentCLI runs `<command> --version`, skipping agents not on PATH.
+// The CLI is killed after timeout and reported as SelftestTimeout.
+func probeAgentCLI(command string, timeout time.Duration) (SelftestStatus, string) {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return SelftestSkip, command + " not installed"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, path, "--version")
+	// Don't wait on children that inherited the output pipe after the kill
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return SelftestTimeout, fmt.Sprintf("%s --version: probe timed out after %s", command, timeout)
+	}
+	if err != nil {
+		return SelftestFail, fmt.Sprintf("%s --version: %v", command, err)
+	}
+	return SelftestPass, strings.TrimSpace(firstLine(string(out)))
+}
+
+// firstLine returns s up to the first newline.
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunSelftestChecksAggregates(t *testing.T) {
+	checks := []selftestCheck{
+		{"ok", func() (SelftestStatus, string) { return SelftestPass, "" }},
+		{"missing", func() (SelftestStatus, string) { return SelftestSkip, "not installed" }},
+		{"broken", func() (SelftestStatus, string) { return SelftestFail, "bad args" }},
+		{"boom", func() (SelftestStatus, string) { panic("nil preset") }},
+	}
+
+	report := runSelftestChecks(checks)
+
+	if report.OK {
+		t.Error("report.OK = true, want false with failures")
+	}
+	if report.Passed != 1 || report.Skipped != 1 || report.Failed != 2 {
+		t.Errorf("counts = pass %d skip %d fail %d, want 1/1/2", report.Passed, report.Skipped, report.Failed)
+	}
+	if len(report.Results) != len(checks) {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(checks))
+	}
+	if r := report.Results[3]; r.Status != SelftestFail || !strings.Contains(r.Message, "nil preset") {
+		t.Errorf("panicking check = %+v, want fail with panic message", r)
+	}
+}
+
+func TestRunSelftestChecksSkipsDoNotFail(t *testing.T) {
+	report := runSelftestChecks([]selftestCheck{
+		{"ok", func() (SelftestStatus, string) { return SelftestPass, "" }},
+		{"probe", func() (SelftestStatus, string) { return SelftestSkip, "not installed" }},
+	})
+	if !report.OK {
+		t.Errorf("report.OK = false, want true when only skips: %+v", report)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.Contains(string(data), `"status":"skip"`) {
+		t.Errorf("JSON missing per-check status: %s", data)
+	}
+}
+
+func TestSelftestBuiltinPresetsPass(t *testing.T) {
+	report := runSelftestChecks(selftestChecks(false, defaultSelftestProbeTimeout))
+	for _, r := range report.Results {
+		if r.Status == SelftestFail {
+			t.Errorf("%s failed: %s", r.Name, r.Message)
+		}
+	}
+	if report.Passed == 0 {
+		t.Error("expected some checks to run")
+	}
+}
+
+func TestProbeAgentCLINotInstalled(t *testing.T) {
+	status, msg := probeAgentCLI("gt-selftest-no-such-agent", defaultSelftestProbeTimeout)
+	if status != SelftestSkip || !strings.Contains(msg, "not installed") {
+		t.Errorf("probeAgentCLI = %s %q, want skip/not installed", status, msg)
+	}
+}
+
+func TestProbeAgentCLITimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the fake agent")
+	}
+	agent := filepath.Join(t.TempDir(), "hung-agent")
+	if err := os.WriteFile(agent, []byte("#!/bin/sh\nsleep 30\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	status, msg := probeAgentCLI(agent, 100*time.Millisecond)
+	if status != SelftestTimeout || !strings.Contains(msg, "timed out after 100ms") {
+		t.Errorf("probeAgentCLI = %s %q, want timeout", status, msg)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("probe took %s, want it cut off near the deadline", elapsed)
+	}
+}
+
+func TestRunSelftestChecksTimeoutsAreDistinct(t *testing.T) {
+	report := runSelftestChecks([]selftestCheck{
+		{"ok", func() (SelftestStatus, string) { return SelftestPass, "" }},
+		{"probe", func() (SelftestStatus, string) { return SelftestTimeout, "probe timed out" }},
+	})
+	if !report.OK || report.TimedOut != 1 || report.Failed != 0 || report.Skipped != 0 {
+		t.Errorf("report = %+v, want OK with one timeout", report)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/suggest"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -34,14 +36,15 @@ var (
 
 var sessionCmd = &cobra.Command{
 	Use:     "session",
-	Aliases: []string{"sess"},
+	Aliases: []string{"sess", "sessions"},
 	GroupID: GroupAgents,
 	Short:   "Manage polecat sessions",
 	RunE:    requireSubcommand,
 	Long: `Manage tmux sessions for polecats.
 
 Sessions are tmux sessions running Claude for each polecat.
-Use the subcommands to start, stop, attach, and monitor sessions.
+Use the subcommands to start, stop, attach, and monitor sessions, and
+'reap' to clean up Gas Town sessions whose agent has died.
 
 TIP: To send messages to a running session, use 'gt nudge' (not 'session inject').
 The nudge command uses reliable delivery that works correctly with Claude Code.`,
@@ -79,7 +82,9 @@ var sessionAtCmd = &cobra.Command{
 	Short:   "Attach to a running session",
 	Long: `Attach to a running polecat session.
 
-Attaches the current terminal to the tmux session. Detach with Ctrl-B D.`,
+Attaches the current terminal to the tmux session. Detach with Ctrl-B D.
+Sessions of custom roles (see gt handoff --help) attach by role address,
+e.g. gt session at gastown/auditor.`,
 	Args: cobra.ExactArgs(1),
 	RunE: runSessionAttach,
 }
@@ -225,12 +230,12 @@ func parseAddress(addr string) (rigName, polecatName string, err error) {
 
 // getSessionManager creates a session manager for the given rig.
 func getSessionManager(rigName string) (*polecat.SessionManager, *rig.Rig, error) {
-	_, r, err := getRig(rigName)
+	townRoot, r, err := getRig(rigName)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	t := tmux.NewTmux()
+	t := tmux.ForRig(townRoot, r.Name)
 	polecatMgr := polecat.NewSessionManager(t, r)
 
 	return polecatMgr, r, nil
@@ -266,7 +271,10 @@ func runSessionStart(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Printf("Starting session for %s/%s...\n", rigName, polecatName)
-	if err := polecatMgr.Start(polecatName, opts); err != nil {
+	if err := polecatMgr.Start(polecatName, opts); errors.Is(err, scheduler.ErrQueued) {
+		fmt.Printf("%s %v\n", style.Dim.Render("⏳"), err)
+		return nil
+	} else if err != nil {
 		return fmt.Errorf("starting session: %w", err)
 	}
 
@@ -321,6 +329,17 @@ func runSessionStop(cmd *cobra.Command, args []string) error {
 }
 
 func runSessionAttach(cmd *cobra.Command, args []string) error {
+	// Custom roles from town settings attach by role address
+	if target, ok, err := resolveCustomRoleTarget(args[0]); ok {
+		if err != nil {
+			return err
+		}
+		if running, _ := tmux.NewTmux().HasSession(target); !running {
+			return fmt.Errorf("%w: %s", tmux.ErrSessionNotFound, target)
+		}
+		return attachToTmuxSession(target)
+	}
+
 	rigName, polecatName, err := parseAddress(args[0])
 	if err != nil {
 		return err
@@ -341,6 +360,7 @@ type SessionListItem struct {
 	Polecat   string `json:"polecat"`
 	SessionID string `json:"session_id"`
 	Running   bool   `json:"running"`
+	Host      string `json:"host,omitempty"`
 }
 
 func runSessionList(cmd *cobra.Command, args []string) error {
@@ -376,11 +396,15 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 		rigs = filtered
 	}
 
-	// Collect sessions from all rigs
-	t := tmux.NewTmux()
+	// Collect sessions from all rigs, each on its own host
 	var allSessions []SessionListItem
 
 	for _, r := range rigs {
+		t := tmux.ForRig(townRoot, r.Name)
+		var host string
+		if remote := t.Remote(); remote != nil {
+			host = remote.Host
+		}
 		polecatMgr := polecat.NewSessionManager(t, r)
 		infos, err := polecatMgr.List()
 		if err != nil {
@@ -393,6 +417,7 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 				Polecat:   info.Polecat,
 				SessionID: info.SessionID,
 				Running:   info.Running,
+				Host:      host,
 			})
 		}
 	}
@@ -415,7 +440,11 @@ func runSessionList(cmd *cobra.Command, args []string) error {
 		if !s.Running {
 			status = style.Dim.Render("○")
 		}
-		fmt.Printf("  %s %s/%s\n", status, s.Rig, s.Polecat)
+		if s.Host != "" {
+			fmt.Printf("  %s %s/%s %s\n", status, s.Rig, s.Polecat, style.Dim.Render("@"+s.Host))
+		} else {
+			fmt.Printf("  %s %s/%s\n", status, s.Rig, s.Polecat)
+		}
 		fmt.Printf("    %s\n", style.Dim.Render(s.SessionID))
 	}
 
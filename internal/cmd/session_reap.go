@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+var (
+	sessionReapDryRun bool
+	sessionReapAll    bool
+	sessionReapForce  bool
+)
+
+var sessionReapCmd = &cobra.Command{
+	Use:   "reap",
+	Short: "Kill Gas Town sessions whose agent has died",
+	Long: `Kill orphaned Gas Town tmux sessions (gt-*, hq-*).
+
+A session is orphaned when tmux is still alive but its agent process is not,
+typically after a crash. Liveness uses the session's GT_AGENT to know which
+process names to look for (Claude's by default).
+
+Crew sessions are human-managed and may be idle on purpose, so they are only
+reaped with --all. The session running this command is never reaped.
+
+Examples:
+  gt session reap            # Kill sessions with a dead agent
+  gt session reap --dry-run  # List what would be killed
+  gt session reap --all      # Kill every Gas Town session (asks first)`,
+	Args: cobra.NoArgs,
+	RunE: runSessionReap,
+}
+
+func init() {
+	sessionReapCmd.Flags().BoolVarP(&sessionReapDryRun, "dry-run", "n", false, "List sessions that would be reaped without killing them")
+	sessionReapCmd.Flags().BoolVar(&sessionReapAll, "all", false, "Reap every Gas Town session, live or not")
+	sessionReapCmd.Flags().BoolVarP(&sessionReapForce, "force", "f", false, "Skip the --all confirmation")
+	sessionCmd.AddCommand(sessionReapCmd)
+}
+
+func runSessionReap(cmd *cobra.Command, args []string) error {
+	t := tmux.NewTmux()
+
+	var sessions []string
+	for _, prefix := range []string{session.Prefix, session.HQPrefix} {
+		matched, err := t.ListSessionsWithPrefix(prefix)
+		if err != nil {
+			return fmt.Errorf("listing sessions: %w", err)
+		}
+		sessions = append(sessions, matched...)
+	}
+
+	var current string
+	if tmux.IsInsideTmux() {
+		current, _ = getCurrentTmuxSession()
+	}
+
+	candidates := reapCandidates(sessions, current, sessionReapAll, t.IsAgentAlive)
+	if len(candidates) == 0 {
+		fmt.Println("No sessions to reap")
+		return nil
+	}
+
+	if sessionReapDryRun {
+		fmt.Printf("Would reap %d session(s):\n", len(candidates))
+		for _, s := range candidates {
+			fmt.Printf("  %s\n", s)
+		}
+		return nil
+	}
+
+	if sessionReapAll && !sessionReapForce &&
+		!promptYesNo(fmt.Sprintf("Kill all %d Gas Town session(s), including live agents?", len(candidates))) {
+		fmt.Println("Aborted.")
+		return nil
+	}
+
+	townRoot := detectTownRootFromCwd()
+	myPID := strconv.Itoa(os.Getpid())
+	var reaped, failed []string
+	for _, s := range candidates {
+		if townRoot != "" {
+			_ = LogKill(townRoot, handoffAgentName(s), "gt session reap")
+		}
+		if err := t.KillSessionWithProcessesExcluding(s, []string{myPID}); err != nil {
+			style.PrintWarning("could not kill %s: %v", s, err)
+			failed = append(failed, s)
+			continue
+		}
+		fmt.Printf("%s Reaped %s\n", style.Bold.Render("💀"), s)
+		reaped = append(reaped, s)
+	}
+
+	fmt.Printf("\nReaped %d session(s)", len(reaped))
+	if len(failed) > 0 {
+		fmt.Printf(", %d failed", len(failed))
+	}
+	fmt.Println()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("could not reap: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// reapCandidates selects the sessions to reap, sorted by name: every Gas
+// Town session whose agent alive reports dead, or all of them with all.
+// Crew sessions are only selected with all; current is never selected.
+func reapCandidates(sessions []string, current string, all bool, alive func(string) bool) []string {
+	var candidates []string
+	for _, s := range sessions {
+		if s == "" || s == current {
+			continue
+		}
+		if !strings.HasPrefix(s, session.Prefix) && !strings.HasPrefix(s, session.HQPrefix) {
+			continue
+		}
+		if !all {
+			if identity, err := session.ParseSessionName(s); err == nil && identity.Role == session.RoleCrew {
+				continue
+			}
+			if alive(s) {
+				continue
+			}
+		}
+		candidates = append(candidates, s)
+	}
+	sort.Strings(candidates)
+	return candidates
+}
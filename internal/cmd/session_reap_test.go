@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReapCandidates(t *testing.T) {
+	sessions := []string{
+		"gt-gastown-witness",   // live
+		"gt-gastown-refinery",  // dead
+		"hq-deacon",            // dead
+		"hq-mayor",             // live, the caller's session
+		"gt-gastown-crew-max",  // dead, but crew
+		"gt-gastown-polecat-x", // dead polecat
+		"scratch",              // not Gas Town
+		"",
+	}
+	live := map[string]bool{"gt-gastown-witness": true, "hq-mayor": true}
+	alive := func(s string) bool { return live[s] }
+
+	tests := []struct {
+		name    string
+		current string
+		all     bool
+		want    []string
+	}{
+		{
+			name: "dead non-crew sessions only",
+			want: []string{"gt-gastown-polecat-x", "gt-gastown-refinery", "hq-deacon"},
+		},
+		{
+			name:    "current session is never reaped",
+			current: "hq-deacon",
+			want:    []string{"gt-gastown-polecat-x", "gt-gastown-refinery"},
+		},
+		{
+			name:    "all ignores liveness and includes crew",
+			current: "hq-mayor",
+			all:     true,
+			want:    []string{"gt-gastown-crew-max", "gt-gastown-polecat-x", "gt-gastown-refinery", "gt-gastown-witness", "hq-deacon"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reapCandidates(sessions, tt.current, tt.all, alive)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reapCandidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReapCandidates_AllLive(t *testing.T) {
+	got := reapCandidates([]string{"gt-gastown-witness", "hq-mayor"}, "", false, func(string) bool { return true })
+	if len(got) != 0 {
+		t.Errorf("reapCandidates() = %v, want none", got)
+	}
+}
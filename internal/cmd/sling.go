@@ -11,8 +11,10 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/mail"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -50,6 +52,30 @@ Spawning Options (when target is a rig):
   gt sling gp-abc greenplace --create               # Create polecat if missing
   gt sling gp-abc greenplace --force                # Ignore unread mail
   gt sling gp-abc greenplace --account work         # Use specific Claude account
+  gt sling gp-abc greenplace --agent codex --model o3  # Pick the agent and its model
+
+  --model is passed with each agent's own flag (--model for claude and kimi,
+  -m for codex) and stays with the session across handoffs and gt resume.
+
+Crew Worktrees (--worktree):
+  gt sling gt-abc greenplace/crew/max --worktree
+
+  Gives the bead its own git worktree in the crew member's clone
+  (.worktrees/gt-abc, branch crew/max/gt-abc) and restarts the member's
+  session there, so crews sharing a checkout don't trample each other.
+  Worktrees of closed beads are pruned; see 'gt crew worktrees'.
+
+Session Tags (--tag):
+  gt sling gt-abc gastown --tag team=backend
+
+  Tags the target's session key=value, for gt list --filter team=backend.
+
+Headless / CI (--headless):
+  gt sling gt-abc gastown --headless
+
+  Spawns a polecat and runs its agent in the foreground with no tmux, in the
+  agent's non-interactive mode: output streams to stdout and gt exits with
+  the agent's status once it finishes. Needs a rig target.
 
 Natural Language Args:
   gt sling gt-abc --args "patch release"
@@ -77,8 +103,22 @@ Batch Slinging:
   gt sling gt-abc gt-def gt-ghi gastown   # Sling multiple beads to a rig
 
   When multiple beads are provided with a rig target, each bead gets its own
-  polecat. This parallelizes work dispatch without running gt sling N times.`,
-	Args: cobra.MinimumNArgs(1),
+  polecat. This parallelizes work dispatch without running gt sling N times.
+
+Dependencies:
+  gt sling gt-ui gastown                 # Refused while gt-ui needs open beads
+  gt sling gt-ui gastown --ignore-deps   # Sling it anyway
+
+  Beads declare what they need done first with needs:/blocks: lines in
+  their description or bd dependencies (see 'gt bead graph'). A bead is
+  only slung once everything it needs is closed; batches are slung in
+  dependency order and hold back beads that are still waiting.`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if slingBatch != "" {
+			return cobra.MaximumNArgs(1)(cmd, args)
+		}
+		return cobra.MinimumNArgs(1)(cmd, args)
+	},
 	RunE: runSling,
 }
 
@@ -92,12 +132,19 @@ var (
 	slingHookRawBead bool     // --hook-raw-bead: hook raw bead without default formula (expert mode)
 
 	// Flags migrated for polecat spawning (used by sling for work assignment)
-	slingCreate   bool   // --create: create polecat if it doesn't exist
-	slingForce    bool   // --force: force spawn even if polecat has unread mail
-	slingAccount  string // --account: Claude Code account handle to use
-	slingAgent    string // --agent: override runtime agent for this sling/spawn
-	slingNoConvoy bool   // --no-convoy: skip auto-convoy creation
-	slingNoMerge  bool   // --no-merge: skip merge queue on completion (for upstream PRs/human review)
+	slingCreate   bool     // --create: create polecat if it doesn't exist
+	slingForce    bool     // --force: force spawn even if polecat has unread mail
+	slingAccount  string   // --account: Claude Code account handle to use
+	slingAgent    string   // --agent: override runtime agent for this sling/spawn
+	slingModel    string   // --model: model for the spawned polecat's agent
+	slingNoConvoy bool     // --no-convoy: skip auto-convoy creation
+	slingNoMerge  bool     // --no-merge: skip merge queue on completion (for upstream PRs/human review)
+	slingBatch    string   // --batch: file of bead IDs to spread across crew ("-" for stdin)
+	slingWorktree bool     // --worktree: give the bead its own worktree in the crew member's clone
+	slingTags     []string // --tag: key=value tags for the target's session
+	slingHeadless bool     // --headless: run the new polecat's agent in the foreground, without tmux
+
+	slingIgnoreDeps bool // --ignore-deps: sling beads whose dependencies aren't done
 )
 
 func init() {
@@ -113,9 +160,15 @@ func init() {
 	slingCmd.Flags().BoolVar(&slingForce, "force", false, "Force spawn even if polecat has unread mail")
 	slingCmd.Flags().StringVar(&slingAccount, "account", "", "Claude Code account handle to use")
 	slingCmd.Flags().StringVar(&slingAgent, "agent", "", "Override agent/runtime for this sling (e.g., claude, gemini, codex, or custom alias)")
+	slingCmd.Flags().StringVar(&slingModel, "model", "", "Model for a spawned polecat's agent (e.g., opus, gpt-5-codex), passed with the agent's model flag")
 	slingCmd.Flags().BoolVar(&slingNoConvoy, "no-convoy", false, "Skip auto-convoy creation for single-issue sling")
 	slingCmd.Flags().BoolVar(&slingHookRawBead, "hook-raw-bead", false, "Hook raw bead without default formula (expert mode)")
 	slingCmd.Flags().BoolVar(&slingNoMerge, "no-merge", false, "Skip merge queue on completion (keep work on feature branch for review)")
+	slingCmd.Flags().StringVar(&slingBatch, "batch", "", "Read bead IDs from a file (\"-\" for stdin) and spread them across the rig's crew")
+	slingCmd.Flags().BoolVar(&slingWorktree, "worktree", false, "Work the bead in its own git worktree of the crew member's clone (crew targets)")
+	slingCmd.Flags().StringArrayVar(&slingTags, "tag", nil, "Tag the target's session key=value (repeatable, see gt tag)")
+	slingCmd.Flags().BoolVar(&slingHeadless, "headless", false, "Run the polecat's agent in the foreground without tmux and exit with its status (rig targets, for CI)")
+	slingCmd.Flags().BoolVar(&slingIgnoreDeps, "ignore-deps", false, "Sling beads even if beads they need aren't done yet")
 
 	rootCmd.AddCommand(slingCmd)
 }
@@ -142,6 +195,15 @@ func runSling(cmd *cobra.Command, args []string) error {
 		args[i] = strings.TrimRight(args[i], "/")
 	}
 
+	// Batch file mode: gt sling --batch beads.txt [rig]
+	if slingBatch != "" {
+		rigName := ""
+		if len(args) > 0 {
+			rigName = args[0]
+		}
+		return runSlingBatchFile(slingBatch, rigName, townRoot, townBeadsDir)
+	}
+
 	// Batch mode detection: multiple beads with rig target
 	// Pattern: gt sling gt-abc gt-def gt-ghi gastown
 	// When len(args) > 2 and last arg is a rig, sling each bead to its own polecat
@@ -152,11 +214,31 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	return slingOne(args, townRoot, townBeadsDir)
+}
+
+// slingOne slings a single bead or formula (args[0]) to the target in
+// args[1], or to self if there is none.
+func slingOne(args []string, townRoot, townBeadsDir string) error {
+	var err error
+
 	// Determine mode based on flags and argument types
 	var beadID string
 	var formulaName string
 	attachedMoleculeID := ""
 
+	if slingHeadless {
+		if len(args) < 2 {
+			return fmt.Errorf("--headless needs a rig target to spawn a polecat in")
+		}
+		if _, isRig := IsRigName(args[1]); !isRig {
+			return fmt.Errorf("--headless needs a rig target, not %s", args[1])
+		}
+		if slingWorktree || len(slingTags) > 0 {
+			return fmt.Errorf("--headless runs without a session: --worktree and --tag don't apply")
+		}
+	}
+
 	if slingOnTarget != "" {
 		// Formula-on-bead mode: gt sling <formula> --on <bead>
 		formulaName = args[0]
@@ -180,6 +262,9 @@ func runSling(cmd *cobra.Command, args []string) error {
 			// Not a verified bead - try as standalone formula
 			if err := verifyFormulaExists(firstArg); err == nil {
 				// Standalone formula mode: gt sling <formula> [target]
+				if slingHeadless {
+					return fmt.Errorf("--headless slings beads, not standalone formulas")
+				}
 				return runSlingFormula(args)
 			}
 			// Not a formula either - check if it looks like a bead ID (routing issue workaround).
@@ -194,6 +279,13 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// Don't start work that can't land before its prerequisites
+	if !slingIgnoreDeps {
+		if err := checkBeadNeeds(townRoot, beadID); err != nil {
+			return err
+		}
+	}
+
 	// Determine target agent (self or specified)
 	var targetAgent string
 	var targetPane string
@@ -255,6 +347,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 					Create:   slingCreate,
 					HookBead: beadID, // Set atomically at spawn time
 					Agent:    slingAgent,
+					Model:    slingModel,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -287,6 +380,7 @@ func runSling(cmd *cobra.Command, args []string) error {
 							Create:   slingCreate,
 							HookBead: beadID,
 							Agent:    slingAgent,
+							Model:    slingModel,
 						}
 						spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 						if spawnErr != nil {
@@ -324,6 +418,14 @@ func runSling(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if slingWorktree && !strings.Contains(targetAgent, "/crew/") {
+		return fmt.Errorf("--worktree needs a crew target, not %s", targetAgent)
+	}
+	tags, err := parseTagArgs(slingTags)
+	if err != nil {
+		return err
+	}
+
 	// Display what we're doing
 	if formulaName != "" {
 		fmt.Printf("%s Slinging formula %s on %s to %s...\n", style.Bold.Render("🎯"), formulaName, beadID, targetAgent)
@@ -440,6 +542,16 @@ func runSling(cmd *cobra.Command, args []string) error {
 		if slingArgs != "" {
 			fmt.Printf("  args (in nudge): %s\n", slingArgs)
 		}
+		if slingWorktree {
+			fmt.Printf("Would move %s to a worktree for %s\n", targetAgent, beadID)
+		}
+		if len(tags) > 0 {
+			fmt.Printf("Would tag %s's session: %s\n", targetAgent, tags)
+		}
+		if slingHeadless {
+			fmt.Printf("Would run the polecat headless and exit with the agent's status\n")
+			return nil
+		}
 		fmt.Printf("Would inject start prompt to pane: %s\n", targetPane)
 		return nil
 	}
@@ -536,6 +648,8 @@ func runSling(cmd *cobra.Command, args []string) error {
 	// Log sling event to activity feed
 	actor := detectActor()
 	_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadID, targetAgent))
+	hooks.Fire(townRoot, hooks.BeadAssigned, map[string]string{"bead": beadID, "agent": targetAgent, "actor": actor})
+	advanceBeadStage(townRoot, beadID, beads.StageAssigned, targetAgent, "gt sling")
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Skip if hook was already set atomically during polecat spawn - avoids "agent bead not found"
@@ -592,6 +706,11 @@ func runSling(cmd *cobra.Command, args []string) error {
 		targetPane = pane
 	}
 
+	// Headless: run the new polecat's agent here instead of in a session
+	if slingHeadless {
+		return runSlingHeadless(newPolecatInfo, beadID)
+	}
+
 	// Start polecat session now that attached_molecule is set.
 	// This ensures polecat sees the molecule when gt prime runs on session start.
 	freshlySpawned := newPolecatInfo != nil
@@ -603,6 +722,26 @@ func runSling(cmd *cobra.Command, args []string) error {
 		targetPane = pane
 	}
 
+	// Move the crew member into the bead's own worktree; the restarted
+	// agent is nudged below once it's ready
+	if slingWorktree {
+		if err := slingToWorktree(targetAgent, beadID, townRoot); err != nil {
+			return fmt.Errorf("setting up worktree: %w", err)
+		}
+	}
+
+	if len(tags) > 0 {
+		if sess := getSessionFromPane(targetPane); sess != "" {
+			if err := updateSessionTags(tmux.NewTmux(), sess, tags, nil); err != nil {
+				style.PrintWarning("could not tag %s: %v", sess, err)
+			} else {
+				fmt.Printf("%s Tagged %s: %s\n", style.Bold.Render("✓"), sess, tags)
+			}
+		} else {
+			style.PrintWarning("no session to tag for %s", targetAgent)
+		}
+	}
+
 	// Try to inject the "start now" prompt (graceful if no tmux)
 	// Skip for freshly spawned polecats - SessionManager.Start() already sent StartupNudge.
 	if freshlySpawned {
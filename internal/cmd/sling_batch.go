@@ -1,14 +1,22 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 // runBatchSling handles slinging multiple beads to a rig.
@@ -21,20 +29,27 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 	}
 
+	// Sling prerequisites first, and hold back beads still waiting on others
+	townRoot := filepath.Dir(townBeadsDir)
+	total := len(beadIDs)
+	beadIDs, held := holdWaitingBeads(townRoot, beadIDs)
+
 	if slingDryRun {
-		fmt.Printf("%s Batch slinging %d beads to rig '%s':\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
+		fmt.Printf("%s Batch slinging %d beads to rig '%s':\n", style.Bold.Render("🎯"), total, rigName)
 		fmt.Printf("  Would cook mol-polecat-work formula once\n")
 		for _, beadID := range beadIDs {
 			fmt.Printf("  Would spawn polecat and apply mol-polecat-work to: %s\n", beadID)
 		}
+		for _, h := range held {
+			fmt.Printf("  Would hold %s: %s\n", h.ID, h.Reason)
+		}
 		return nil
 	}
 
-	fmt.Printf("%s Batch slinging %d beads to rig '%s'...\n", style.Bold.Render("🎯"), len(beadIDs), rigName)
+	fmt.Printf("%s Batch slinging %d beads to rig '%s'...\n", style.Bold.Render("🎯"), total, rigName)
 
 	// Issue #288: Auto-apply mol-polecat-work for batch sling
 	// Cook once before the loop for efficiency
-	townRoot := filepath.Dir(townBeadsDir)
 	formulaName := "mol-polecat-work"
 	formulaCooked := false
 
@@ -45,7 +60,10 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		success bool
 		errMsg  string
 	}
-	results := make([]slingResult, 0, len(beadIDs))
+	results := make([]slingResult, 0, total)
+	for _, h := range held {
+		results = append(results, slingResult{beadID: h.ID, success: false, errMsg: "held: " + h.Reason})
+	}
 
 	// Spawn a polecat for each bead and sling it
 	for i, beadID := range beadIDs {
@@ -72,6 +90,7 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 			Create:   slingCreate,
 			HookBead: beadID, // Set atomically at spawn time
 			Agent:    slingAgent,
+			Model:    slingModel,
 		}
 		spawnInfo, err := SpawnPolecatForSling(rigName, spawnOpts)
 		if err != nil {
@@ -138,6 +157,8 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		// Log sling event
 		actor := detectActor()
 		_ = events.LogFeed(events.TypeSling, actor, events.SlingPayload(beadToHook, targetAgent))
+		hooks.Fire(townRoot, hooks.BeadAssigned, map[string]string{"bead": beadToHook, "agent": targetAgent, "actor": actor})
+		advanceBeadStage(townRoot, beadToHook, beads.StageAssigned, targetAgent, "gt sling")
 
 		// Update agent bead state
 		updateAgentHookBead(targetAgent, beadToHook, hookWorkDir, townBeadsDir)
@@ -179,8 +200,8 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 		}
 	}
 
-	fmt.Printf("\n%s Batch sling complete: %d/%d succeeded\n", style.Bold.Render("📊"), successCount, len(beadIDs))
-	if successCount < len(beadIDs) {
+	fmt.Printf("\n%s Batch sling complete: %d/%d succeeded\n", style.Bold.Render("📊"), successCount, total)
+	if successCount < total {
 		for _, r := range results {
 			if !r.success {
 				fmt.Printf("  %s %s: %s\n", style.Dim.Render("✗"), r.beadID, r.errMsg)
@@ -190,3 +211,261 @@ func runBatchSling(beadIDs []string, rigName string, townBeadsDir string) error
 
 	return nil
 }
+
+// batchBead is a line of a --batch file: a bead ID and, optionally, the
+// agent the bead should be worked by.
+type batchBead struct {
+	ID    string
+	Agent string
+}
+
+// batchCrew is a running crew member that --batch can hand beads to.
+type batchCrew struct {
+	Name  string
+	Agent string // Agent the crew member's session runs
+}
+
+// batchAssignment is where --batch sends a bead. Crew is empty if no crew
+// member runs the agent the bead wants.
+type batchAssignment struct {
+	Bead   batchBead
+	Crew   *batchCrew
+	Status string
+	Held   bool // Waiting on beads it needs; not slung, but not a failure
+}
+
+// readBatchBeads parses a --batch file: one bead ID per line, optionally
+// followed by an agent name. Blank lines and # comments are skipped.
+func readBatchBeads(r io.Reader) ([]batchBead, error) {
+	var beadList []batchBead
+	seen := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch len(fields) {
+		case 0:
+			continue
+		case 1, 2:
+		default:
+			return nil, fmt.Errorf("line %d: want \"<bead> [agent]\", got %q", lineNo, strings.TrimSpace(line))
+		}
+		if prev, ok := seen[fields[0]]; ok {
+			return nil, fmt.Errorf("line %d: bead %s already listed on line %d", lineNo, fields[0], prev)
+		}
+		seen[fields[0]] = lineNo
+		b := batchBead{ID: fields[0]}
+		if len(fields) == 2 {
+			b.Agent = fields[1]
+		}
+		beadList = append(beadList, b)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return beadList, nil
+}
+
+// heldBatchBead is a --batch bead held back until the beads it needs are
+// done.
+type heldBatchBead struct {
+	bead batchBead
+	heldBead
+}
+
+// orderBatchBeads orders --batch beads for slinging and holds back those
+// waiting on dependencies (see holdWaitingBeads).
+func orderBatchBeads(townRoot string, beadList []batchBead) ([]batchBead, []heldBatchBead) {
+	byID := make(map[string]batchBead, len(beadList))
+	ids := make([]string, 0, len(beadList))
+	for _, b := range beadList {
+		byID[b.ID] = b
+		ids = append(ids, b.ID)
+	}
+	ready, held := holdWaitingBeads(townRoot, ids)
+	ordered := make([]batchBead, 0, len(ready))
+	for _, id := range ready {
+		ordered = append(ordered, byID[id])
+	}
+	heldBeads := make([]heldBatchBead, 0, len(held))
+	for _, h := range held {
+		heldBeads = append(heldBeads, heldBatchBead{bead: byID[h.ID], heldBead: h})
+	}
+	return ordered, heldBeads
+}
+
+// assignBatchBeads spreads beads across crew, giving each bead to the least
+// loaded crew member that runs its agent (the bead's own, else
+// defaultAgent; any agent if both are empty). Ties go to the earlier crew
+// member, so equal crews are filled round-robin.
+func assignBatchBeads(beadList []batchBead, crews []batchCrew, defaultAgent string) []batchAssignment {
+	load := make([]int, len(crews))
+	assignments := make([]batchAssignment, 0, len(beadList))
+	for _, b := range beadList {
+		agent := b.Agent
+		if agent == "" {
+			agent = defaultAgent
+		}
+		best := -1
+		for i, c := range crews {
+			if agent != "" && c.Agent != agent {
+				continue
+			}
+			if best < 0 || load[i] < load[best] {
+				best = i
+			}
+		}
+		if best < 0 {
+			assignments = append(assignments, batchAssignment{Bead: b, Status: fmt.Sprintf("skipped: no running crew uses %s", agent)})
+			continue
+		}
+		load[best]++
+		assignments = append(assignments, batchAssignment{Bead: b, Crew: &crews[best]})
+	}
+	return assignments
+}
+
+// loadBatchCrews returns the rig's crew members with running sessions and
+// the agent each runs: the session's GT_AGENT, else the rig's crew agent.
+// An empty rigName means the rig of the current directory or GT_RIG.
+func loadBatchCrews(rigName, townRoot string) (string, []batchCrew, error) {
+	if rigName == "" {
+		if inferred, err := inferRigFromCwd(townRoot); err == nil {
+			rigName = inferred
+		} else {
+			rigName = os.Getenv("GT_RIG")
+		}
+		if rigName == "" {
+			return "", nil, fmt.Errorf("could not determine rig: gt sling --batch <file> <rig>")
+		}
+	}
+	crewMgr, r, err := getCrewManager(rigName)
+	if err != nil {
+		return "", nil, err
+	}
+	workers, err := crewMgr.List()
+	if err != nil {
+		return "", nil, fmt.Errorf("listing crew: %w", err)
+	}
+
+	rigAgent, _ := config.ResolveRoleAgentName("crew", townRoot, r.Path)
+	t := tmux.NewTmux()
+	var crews []batchCrew
+	for _, w := range workers {
+		sessionName := crewMgr.SessionName(w.Name)
+		if running, _ := t.HasSession(sessionName); !running {
+			continue
+		}
+		agent, _ := t.GetEnvironment(sessionName, "GT_AGENT")
+		if agent == "" {
+			agent = rigAgent
+		}
+		crews = append(crews, batchCrew{Name: w.Name, Agent: agent})
+	}
+	return r.Name, crews, nil
+}
+
+// runSlingBatchFile handles gt sling --batch: it reads bead IDs from path
+// ("-" for stdin), slings each to a running crew member of the rig and
+// prints where each one went.
+func runSlingBatchFile(path, rigName, townRoot, townBeadsDir string) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("opening batch file: %w", err)
+		}
+		defer f.Close()
+		in = f
+	}
+	beadList, err := readBatchBeads(in)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if len(beadList) == 0 {
+		return fmt.Errorf("no bead IDs in %s", path)
+	}
+
+	rigName, crews, err := loadBatchCrews(rigName, townRoot)
+	if err != nil {
+		return err
+	}
+	if len(crews) == 0 {
+		return fmt.Errorf("no running crew in rig '%s' (start one with: gt crew start <name> --rig %s)", rigName, rigName)
+	}
+
+	// Sling prerequisites first; beads still waiting on others take no crew
+	beadList, held := orderBatchBeads(townRoot, beadList)
+	assignments := assignBatchBeads(beadList, crews, slingAgent)
+	for _, h := range held {
+		assignments = append(assignments, batchAssignment{Bead: h.bead, Status: "held: " + h.Reason, Held: true})
+	}
+	fmt.Printf("%s Batch slinging %d beads across %d crew in rig '%s'...\n", style.Bold.Render("🎯"), len(assignments), len(crews), rigName)
+
+	failed, heldCount := 0, 0
+	for i := range assignments {
+		a := &assignments[i]
+		if a.Held {
+			heldCount++
+			continue
+		}
+		if a.Crew == nil {
+			failed++
+			continue
+		}
+		target := fmt.Sprintf("%s/crew/%s", rigName, a.Crew.Name)
+		fmt.Printf("\n[%d/%d] %s → %s\n", i+1, len(assignments), a.Bead.ID, target)
+		if err := slingOne([]string{a.Bead.ID, target}, townRoot, townBeadsDir); err != nil {
+			msg, _, _ := strings.Cut(err.Error(), "\n")
+			if errors.Is(err, errBeadWaiting) {
+				heldCount++
+				a.Held = true
+				a.Status = "held: " + msg
+				fmt.Printf("  %s %s\n", style.Dim.Render("⏸"), msg)
+				continue
+			}
+			failed++
+			a.Status = "failed: " + msg
+			fmt.Printf("  %s %v\n", style.Dim.Render("✗"), err)
+			continue
+		}
+		if slingDryRun {
+			a.Status = "would sling"
+		} else {
+			a.Status = "slung"
+		}
+	}
+
+	fmt.Printf("\n%s Batch sling complete: %d/%d succeeded\n", style.Bold.Render("📊"), len(assignments)-failed-heldCount, len(assignments))
+	if err := printBatchSummary(os.Stdout, assignments); err != nil {
+		return err
+	}
+	if heldCount > 0 {
+		fmt.Printf("%d held until the beads they need are done (see gt bead graph)\n", heldCount)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d beads not slung", failed, len(assignments))
+	}
+	return nil
+}
+
+// printBatchSummary prints the bead, crew member, agent and outcome of each
+// --batch assignment.
+func printBatchSummary(out io.Writer, assignments []batchAssignment) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BEAD\tCREW\tAGENT\tSTATUS")
+	for _, a := range assignments {
+		crewName, agent := "-", a.Bead.Agent
+		if a.Crew != nil {
+			crewName, agent = a.Crew.Name, a.Crew.Agent
+		}
+		if agent == "" {
+			agent = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", a.Bead.ID, crewName, agent, a.Status)
+	}
+	return w.Flush()
+}
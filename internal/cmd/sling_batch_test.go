@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadBatchBeads(t *testing.T) {
+	input := `# sprint 12
+gt-abc
+  gt-def kimi   # needs the long context
+
+gt-ghi
+`
+	got, err := readBatchBeads(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("readBatchBeads: %v", err)
+	}
+	want := []batchBead{{ID: "gt-abc"}, {ID: "gt-def", Agent: "kimi"}, {ID: "gt-ghi"}}
+	if len(got) != len(want) {
+		t.Fatalf("readBatchBeads = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bead %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadBatchBeads_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"gt-abc kimi extra\n", "line 1"},
+		{"gt-abc\ngt-def\ngt-abc\n", "already listed on line 1"},
+	}
+	for _, tt := range tests {
+		_, err := readBatchBeads(strings.NewReader(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("readBatchBeads(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestAssignBatchBeads(t *testing.T) {
+	crews := []batchCrew{
+		{Name: "ann", Agent: "claude"},
+		{Name: "bob", Agent: "kimi"},
+		{Name: "cat", Agent: "claude"},
+	}
+	beadList := []batchBead{
+		{ID: "gt-1"},
+		{ID: "gt-2"},
+		{ID: "gt-3"},
+		{ID: "gt-4", Agent: "kimi"},
+		{ID: "gt-5"},
+		{ID: "gt-6", Agent: "gemini"},
+	}
+
+	got := assignBatchBeads(beadList, crews, "")
+	want := []string{"ann", "bob", "cat", "bob", "ann", ""}
+	for i, a := range got {
+		name := ""
+		if a.Crew != nil {
+			name = a.Crew.Name
+		}
+		if name != want[i] {
+			t.Errorf("%s assigned to %q, want %q", a.Bead.ID, name, want[i])
+		}
+	}
+	if !strings.Contains(got[5].Status, "no running crew uses gemini") {
+		t.Errorf("gt-6 status = %q, want a skip for gemini", got[5].Status)
+	}
+
+	t.Run("default agent", func(t *testing.T) {
+		for _, a := range assignBatchBeads(beadList[:3], crews, "kimi") {
+			if a.Crew == nil || a.Crew.Name != "bob" {
+				t.Errorf("%s assigned to %+v, want bob", a.Bead.ID, a.Crew)
+			}
+		}
+	})
+}
+
+func TestPrintBatchSummary(t *testing.T) {
+	crew := &batchCrew{Name: "ann", Agent: "claude"}
+	var buf bytes.Buffer
+	err := printBatchSummary(&buf, []batchAssignment{
+		{Bead: batchBead{ID: "gt-abc"}, Crew: crew, Status: "slung"},
+		{Bead: batchBead{ID: "gt-def", Agent: "gemini"}, Status: "skipped: no running crew uses gemini"},
+	})
+	if err != nil {
+		t.Fatalf("printBatchSummary: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("summary = %q, want a header and two rows", buf.String())
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "gt-abc ann claude slung" {
+		t.Errorf("row 1 = %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); fields[0] != "gt-def" || fields[1] != "-" || fields[2] != "gemini" {
+		t.Errorf("row 2 = %q", lines[2])
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
@@ -142,6 +143,7 @@ func runSlingFormula(args []string) error {
 					Account: slingAccount,
 					Create:  slingCreate,
 					Agent:   slingAgent,
+					Model:   slingModel,
 				}
 				spawnInfo, spawnErr := SpawnPolecatForSling(rigName, spawnOpts)
 				if spawnErr != nil {
@@ -233,6 +235,13 @@ func runSlingFormula(args []string) error {
 	payload := events.SlingPayload(wispRootID, targetAgent)
 	payload["formula"] = formulaName
 	_ = events.LogFeed(events.TypeSling, actor, payload)
+	hooks.Fire(townRoot, hooks.BeadAssigned, map[string]string{
+		"bead":    wispRootID,
+		"agent":   targetAgent,
+		"actor":   actor,
+		"formula": formulaName,
+	})
+	advanceBeadStage(townRoot, wispRootID, beads.StageAssigned, targetAgent, "gt sling")
 
 	// Update agent bead's hook_bead field (ZFC: agents track their current work)
 	// Note: formula slinging uses town root as workDir (no polecat-specific path)
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/style"
 	"github.com/steveyegge/gastown/internal/tmux"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
@@ -314,6 +316,28 @@ func injectStartPrompt(pane, beadID, subject, args string) error {
 	return t.NudgePane(pane, prompt)
 }
 
+// runSlingHeadless runs a freshly spawned polecat's agent in the foreground
+// (gt sling --headless) and exits with the agent's status.
+func runSlingHeadless(info *SpawnedPolecatInfo, beadID string) error {
+	err := info.RunHeadless(beadID)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code <= 0 {
+			code = 1 // Killed by a signal
+		}
+		fmt.Fprintf(os.Stderr, "%s %s exited with status %d\n", style.ErrorPrefix, info.AgentID(), code)
+		return NewSilentExit(code)
+	}
+	if err != nil {
+		return fmt.Errorf("running %s headless: %w", info.AgentID(), err)
+	}
+	if bead, err := getBeadInfo(beadID); err == nil {
+		fmt.Printf("%s %s finished; %s is %s\n", style.Bold.Render("✓"), info.AgentID(), beadID, bead.Status)
+	}
+	return nil
+}
+
 // getSessionFromPane extracts session name from a pane target.
 // Pane targets can be:
 // - "%9" (pane ID) - need to query tmux for session
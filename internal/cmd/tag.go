@@ -0,0 +1,291 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var (
+	tagRemove []string
+
+	listFilters []string
+	listRig     string
+	listJSON    bool
+)
+
+var tagCmd = &cobra.Command{
+	Use:     "tag <session> [key=value...]",
+	GroupID: GroupAgents,
+	Short:   "Show or set a session's tags",
+	Long: `Show or set key=value tags on an agent session, for grouping sessions
+beyond their names (gt list --filter team=backend).
+
+The session is a tmux session name or an agent address (gastown/crew/max,
+mayor). Tags are stored as tmux options of the session and recorded in the
+session manifest, so gt resume --all restores them. Tags can also be set
+when starting crew (gt crew start --tag) or slinging work (gt sling --tag).
+
+Keys are letters, digits, '-', '_' and '.'. Setting a tag again replaces it.
+
+Examples:
+  gt tag gastown/crew/max                      # Show max's tags
+  gt tag gastown/crew/max team=backend tier=1  # Set tags
+  gt tag gt-gastown-witness --remove tier      # Remove a tag`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTag,
+}
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	GroupID: GroupAgents,
+	Short:   "List agent sessions with their tags",
+	Long: `List every Gas Town agent session, on this machine and on remote rigs'
+hosts, with its tags (see gt tag).
+
+--filter selects sessions by tag and can be repeated; a session must pass
+every filter. A filter is key=value, key!=value, or a bare key for sessions
+that have the tag at all.
+
+Examples:
+  gt list
+  gt list --filter team=backend
+  gt list --filter team=backend --filter tier!=1 --rig gastown
+  gt list --filter oncall --json`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	tagCmd.Flags().StringArrayVar(&tagRemove, "remove", nil, "Remove a tag by key (repeatable)")
+
+	listCmd.Flags().StringArrayVar(&listFilters, "filter", nil, "Only sessions whose tags match key=value, key!=value or key (repeatable)")
+	listCmd.Flags().StringVar(&listRig, "rig", "", "Only sessions of this rig")
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output as JSON")
+
+	rootCmd.AddCommand(tagCmd)
+	rootCmd.AddCommand(listCmd)
+}
+
+func runTag(cmd *cobra.Command, args []string) error {
+	tags, err := parseTagArgs(args[1:])
+	if err != nil {
+		return err
+	}
+	for _, key := range tagRemove {
+		if _, ok := tags[key]; ok {
+			return fmt.Errorf("tag %s is both set and removed", key)
+		}
+	}
+
+	sess, err := resolveRoleToSession(args[0])
+	if err != nil {
+		return err
+	}
+	t := tmuxForSession(sess)
+	exists, err := t.HasSession(sess)
+	if err != nil {
+		return fmt.Errorf("checking session: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("session %s is not running", sess)
+	}
+
+	if len(tags) > 0 || len(tagRemove) > 0 {
+		if err := updateSessionTags(t, sess, tags, tagRemove); err != nil {
+			return err
+		}
+	}
+
+	current, err := t.GetSessionTags(sess)
+	if err != nil {
+		return fmt.Errorf("reading tags: %w", err)
+	}
+	if len(current) == 0 {
+		fmt.Printf("%s %s has no tags\n", style.Dim.Render("○"), sess)
+		return nil
+	}
+	fmt.Printf("%s %s\n", style.Bold.Render(sess), session.Tags(current))
+	return nil
+}
+
+// parseTagArgs parses key=value arguments.
+func parseTagArgs(args []string) (session.Tags, error) {
+	tags := make(session.Tags)
+	for _, arg := range args {
+		key, value, err := session.ParseTag(arg)
+		if err != nil {
+			return nil, err
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// updateSessionTags sets and removes tags of sess, then records the
+// resulting tags in the session manifest.
+func updateSessionTags(t *tmux.Tmux, sess string, set session.Tags, remove []string) error {
+	for k, v := range set {
+		if err := t.SetSessionTag(sess, k, v); err != nil {
+			return fmt.Errorf("setting tag %s: %w", k, err)
+		}
+	}
+	for _, k := range remove {
+		if err := t.UnsetSessionTag(sess, k); err != nil {
+			return fmt.Errorf("removing tag %s: %w", k, err)
+		}
+	}
+	if t.DryRun() {
+		return nil
+	}
+	if tags, err := t.GetSessionTags(sess); err == nil {
+		_ = session.SetSessionTags(session.ManifestPath(), sess, tags) // Non-fatal
+	}
+	return nil
+}
+
+// tmuxForSession returns a Tmux on the host sess runs on.
+func tmuxForSession(sess string) *tmux.Tmux {
+	if r, ok := sessionRemote(sess); ok {
+		return tmux.NewTmux(tmux.WithRemote(r))
+	}
+	return tmux.NewTmux()
+}
+
+// TaggedSession is a session in gt list output.
+type TaggedSession struct {
+	Session string       `json:"session"`
+	Address string       `json:"address,omitempty"`
+	Role    string       `json:"role,omitempty"`
+	Rig     string       `json:"rig,omitempty"`
+	Host    string       `json:"host,omitempty"`
+	Tags    session.Tags `json:"tags"`
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	var filters []session.TagFilter
+	for _, s := range listFilters {
+		f, err := session.ParseTagFilter(s)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, f)
+	}
+
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+
+	var matched []TaggedSession
+	for _, s := range listTaggedSessions(townRoot) {
+		if listRig != "" && s.Rig != listRig {
+			continue
+		}
+		if session.MatchTags(s.Tags, filters) {
+			matched = append(matched, s)
+		}
+	}
+
+	if listJSON {
+		if matched == nil {
+			matched = []TaggedSession{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(matched)
+	}
+	if len(matched) == 0 {
+		fmt.Println("No matching sessions.")
+		return nil
+	}
+	width := 0
+	for _, s := range matched {
+		width = max(width, len(s.Session))
+	}
+	for _, s := range matched {
+		who := s.Address
+		if s.Host != "" {
+			who += " " + style.Dim.Render("@"+s.Host)
+		}
+		line := fmt.Sprintf("  %-*s  %s", width, s.Session, who)
+		if len(s.Tags) > 0 {
+			line += "  " + style.Bold.Render(s.Tags.String())
+		}
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	return nil
+}
+
+// listTaggedSessions returns the town's agent sessions with their tags:
+// those on this machine, and those on each remote rig's host.
+func listTaggedSessions(townRoot string) []TaggedSession {
+	type source struct {
+		t    *tmux.Tmux
+		host string
+		rigs map[string]bool // Rigs whose sessions run there; nil for this machine
+	}
+	sources := []*source{{t: tmux.NewTmux()}}
+	if rigsConfig, err := config.LoadRigsConfig(constants.MayorRigsPath(townRoot)); err == nil {
+		byHost := make(map[string]*source)
+		for name := range rigsConfig.Rigs {
+			r, ok := tmux.RigRemote(townRoot, name)
+			if !ok {
+				continue
+			}
+			src, ok := byHost[r.Host]
+			if !ok {
+				src = &source{t: tmux.NewTmux(tmux.WithRemote(r)), host: r.Host, rigs: make(map[string]bool)}
+				byHost[r.Host] = src
+				sources = append(sources, src)
+			}
+			src.rigs[name] = true
+		}
+	}
+
+	var result []TaggedSession
+	for i, src := range sources {
+		names, err := src.t.ListSessions()
+		if err != nil {
+			if i == 0 {
+				continue // No local tmux server
+			}
+			style.PrintWarning("could not list sessions on %s: %v", src.host, err)
+			continue
+		}
+		for _, name := range names {
+			id, err := session.ParseSessionName(name)
+			if err != nil {
+				continue // Not a Gas Town session
+			}
+			if src.rigs != nil && !src.rigs[id.Rig] {
+				continue // Another town's session on a shared host
+			}
+			tags, _ := src.t.GetSessionTags(name)
+			if tags == nil {
+				tags = map[string]string{}
+			}
+			result = append(result, TaggedSession{
+				Session: name,
+				Address: id.Address(),
+				Role:    string(id.Role),
+				Rig:     id.Rig,
+				Host:    src.host,
+				Tags:    tags,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Session < result[j].Session })
+	return result
+}
@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+var (
+	transcriptRaw    bool
+	transcriptLines  int
+	transcriptFollow bool
+)
+
+var transcriptCmd = &cobra.Command{
+	Use:     "transcript",
+	GroupID: GroupDiag,
+	Short:   "Show what an agent session printed",
+	Long: `Show archived pane output of agent sessions.
+
+Every Gas Town tmux session pipes its pane output into
+~/.gastown/transcripts/<session>/ (or $GT_TRANSCRIPT_DIR). The log is
+rotated at 10MB and the last 5 rotations are kept, so the output of a
+session that was handed off or restarted is still there afterwards.
+
+Targets are resolved like gt handoff targets: a role (mayor, witness,
+crew), a path (gastown/crew/max), or a session name. Terminal escape
+sequences are stripped unless --raw is given.
+
+Examples:
+  gt transcript show mayor
+  gt transcript show gastown/crew/max --raw | less -R
+  gt transcript tail -f witness`,
+	RunE: requireSubcommand,
+}
+
+var transcriptShowCmd = &cobra.Command{
+	Use:   "show <role>",
+	Short: "Print a session's whole transcript",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTranscriptShow,
+}
+
+var transcriptTailCmd = &cobra.Command{
+	Use:   "tail <role>",
+	Short: "Print the end of a session's transcript",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTranscriptTail,
+}
+
+var transcriptRecordCmd = &cobra.Command{
+	Use:    "record <session>",
+	Short:  "Append stdin to a session's transcript",
+	Hidden: true, // Run by tmux pipe-pane (see transcript.Enable)
+	Args:   cobra.ExactArgs(1),
+	RunE:   runTranscriptRecord,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{transcriptShowCmd, transcriptTailCmd} {
+		c.Flags().BoolVar(&transcriptRaw, "raw", false, "Keep terminal escape sequences")
+	}
+	transcriptTailCmd.Flags().IntVarP(&transcriptLines, "lines", "n", 20, "Number of lines to print")
+	transcriptTailCmd.Flags().BoolVarP(&transcriptFollow, "follow", "f", false, "Keep printing output as it is recorded")
+
+	transcriptCmd.AddCommand(transcriptShowCmd, transcriptTailCmd, transcriptRecordCmd)
+	rootCmd.AddCommand(transcriptCmd)
+}
+
+// resolveTranscriptSession maps a target to the session whose transcript
+// to read. Transcripts outlive their sessions, so a near-miss of a role
+// name counts as a session when it has a transcript, not a tmux session.
+func resolveTranscriptSession(target string) (string, error) {
+	if transcript.Exists(target) {
+		return target, nil
+	}
+	return resolveRoleToSessionWith(target, transcript.Exists)
+}
+
+func runTranscriptShow(cmd *cobra.Command, args []string) error {
+	sess, err := resolveTranscriptSession(args[0])
+	if err != nil {
+		return err
+	}
+	files, err := transcript.Files(sess)
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		writeTranscript(os.Stdout, string(data))
+	}
+	return nil
+}
+
+func runTranscriptTail(cmd *cobra.Command, args []string) error {
+	if transcriptLines < 0 {
+		return fmt.Errorf("--lines must not be negative")
+	}
+	sess, err := resolveTranscriptSession(args[0])
+	if err != nil {
+		return err
+	}
+	files, err := transcript.Files(sess)
+	if err != nil {
+		return err
+	}
+	tail, err := transcriptTail(files, transcriptLines)
+	if err != nil {
+		return err
+	}
+	writeTranscript(os.Stdout, tail)
+	if !transcriptFollow {
+		return nil
+	}
+	return followTranscript(os.Stdout, filepath.Join(transcript.SessionDir(sess), transcript.LogName))
+}
+
+func runTranscriptRecord(cmd *cobra.Command, args []string) error {
+	w, err := transcript.NewWriter(transcript.SessionDir(args[0]), transcript.DefaultMaxBytes, transcript.DefaultKeep)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	_, err = io.Copy(w, os.Stdin)
+	return err
+}
+
+// writeTranscript prints text, stripped of escape sequences unless --raw.
+func writeTranscript(w io.Writer, text string) {
+	if !transcriptRaw {
+		text = transcript.StripANSI(text)
+	}
+	_, _ = io.WriteString(w, text)
+}
+
+// transcriptTail returns the last n lines across files (oldest first),
+// reading back from the newest file only as far as needed.
+func transcriptTail(files []string, n int) (string, error) {
+	var lines []string
+	for i := len(files) - 1; i >= 0 && len(lines) <= n; i-- {
+		data, err := os.ReadFile(files[i])
+		if err != nil {
+			return "", err
+		}
+		fileLines := strings.SplitAfter(string(data), "\n")
+		if last := len(fileLines) - 1; fileLines[last] == "" {
+			fileLines = fileLines[:last]
+		}
+		lines = append(fileLines, lines...)
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// followTranscript prints what is appended to the log at path until
+// interrupted, reopening it from the start when the recorder rotates it.
+func followTranscript(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		chunk, err := r.ReadString('\n')
+		if chunk != "" {
+			writeTranscript(w, chunk)
+		}
+		if err == nil {
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		time.Sleep(500 * time.Millisecond)
+
+		// Rotated: the path now names a new, empty log
+		cur, statErr := os.Stat(path)
+		open, openErr := f.Stat()
+		if statErr == nil && openErr == nil && !os.SameFile(cur, open) {
+			next, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_ = f.Close()
+			f = next
+			r.Reset(f)
+		}
+	}
+}
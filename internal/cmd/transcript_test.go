@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/transcript"
+)
+
+func TestTranscriptTail(t *testing.T) {
+	dir := t.TempDir()
+	older := filepath.Join(dir, transcript.LogName+".1")
+	current := filepath.Join(dir, transcript.LogName)
+	if err := os.WriteFile(older, []byte("one\ntwo\nthree\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(current, []byte("four\nfive"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, ""},
+		{2, "four\nfive"},
+		{4, "two\nthree\nfour\nfive"},
+		{10, "one\ntwo\nthree\nfour\nfive"},
+	}
+	for _, tt := range tests {
+		got, err := transcriptTail([]string{older, current}, tt.n)
+		if err != nil {
+			t.Fatalf("transcriptTail: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("transcriptTail(n=%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestResolveTranscriptSession(t *testing.T) {
+	t.Setenv(transcript.EnvDir, t.TempDir())
+	t.Setenv("GT_ROLE", "")
+	if err := os.MkdirAll(transcript.SessionDir("hq-mayor"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(transcript.SessionDir("hq-mayor"), transcript.LogName), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, target := range []string{"mayor", "hq-mayor"} {
+		got, err := resolveTranscriptSession(target)
+		if err != nil {
+			t.Fatalf("resolveTranscriptSession(%q): %v", target, err)
+		}
+		if got != "hq-mayor" {
+			t.Errorf("resolveTranscriptSession(%q) = %q, want hq-mayor", target, got)
+		}
+	}
+}
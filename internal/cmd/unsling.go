@@ -186,6 +186,7 @@ func runUnsling(cmd *cobra.Command, args []string) error {
 
 	// Log unhook event
 	_ = events.LogFeed(events.TypeUnhook, agentID, events.UnhookPayload(hookedBeadID))
+	advanceBeadStage(townRoot, hookedBeadID, beads.StageQueued, "", "gt unsling")
 
 	fmt.Printf("%s Work removed from hook\n", style.Bold.Render("✓"))
 	fmt.Printf("  Agent %s hook cleared (was: %s)\n", agentID, hookedBeadID)
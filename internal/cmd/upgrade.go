@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/selfupdate"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/watchdog"
+)
+
+var (
+	upgradeVersion          string
+	upgradeCheck            bool
+	upgradeForce            bool
+	upgradeRequireSignature bool
+	upgradeRollout          string
+)
+
+// upgradeTimeout bounds fetching the release and its assets.
+const upgradeTimeout = 5 * time.Minute
+
+var upgradeCmd = &cobra.Command{
+	Use:     "upgrade",
+	GroupID: GroupServices,
+	Short:   "Update gt to the latest release",
+	Long: `Download the latest gt release (or --version) from GitHub and replace
+this binary with it.
+
+The release archive for this platform is checked against the release's
+checksums.txt before anything is replaced. If the release also publishes a
+GPG signature of the checksums (checksums.txt.sig), it is verified with gpg
+against your keyring. A release without one is installed with a warning,
+as its checksums only show that the download is intact; --require-signature
+refuses such releases.
+The new binary must run "gt version" before it is installed, and the old
+one is kept next to it as gt.old.
+
+Agent sessions keep running the gt they started with until they respawn.
+--rollout schedules a handoff of every running agent session (polecats
+excepted: they are short-lived), spread evenly over the given window so the
+fleet doesn't restart at once. The watchdog runs them (gt watchdog start);
+see them with gt handoff --schedules.
+
+Examples:
+  gt upgrade --check             # Is there a newer release?
+  gt upgrade                     # Install the latest release
+  gt upgrade --version 0.6.1     # Install a specific release
+  gt upgrade --rollout 2h        # ...and respawn sessions over two hours
+  gt upgrade --rollout 0         # ...and respawn them all at the next poll`,
+	Args: cobra.NoArgs,
+	RunE: runUpgrade,
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeVersion, "version", "", "Release to install (default: latest)")
+	upgradeCmd.Flags().BoolVar(&upgradeCheck, "check", false, "Only report whether a newer release exists")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Install even if the release is not newer")
+	upgradeCmd.Flags().BoolVar(&upgradeRequireSignature, "require-signature", false, "Refuse releases without a valid GPG signature")
+	upgradeCmd.Flags().StringVar(&upgradeRollout, "rollout", "", "Hand off running agent sessions over this window (e.g. 2h; 0 for all at once)")
+
+	rootCmd.AddCommand(upgradeCmd)
+}
+
+func runUpgrade(cmd *cobra.Command, args []string) error {
+	var rollout time.Duration
+	if upgradeRollout != "" {
+		d, err := time.ParseDuration(upgradeRollout)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid --rollout %q: want a duration like 2h", upgradeRollout)
+		}
+		rollout = d
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), upgradeTimeout)
+	defer cancel()
+	client := &selfupdate.Client{}
+	var release *selfupdate.Release
+	var err error
+	if upgradeVersion != "" {
+		release, err = client.ByVersion(ctx, upgradeVersion)
+	} else {
+		release, err = client.Latest(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("fetching release: %w", err)
+	}
+
+	newer := selfupdate.Compare(release.Version(), Version) > 0
+	if upgradeCheck {
+		if newer {
+			fmt.Printf("gt %s is available (this is %s). Install it with: gt upgrade\n", release.Version(), Version)
+		} else {
+			fmt.Printf("gt %s is up to date (latest release: %s)\n", Version, release.Version())
+		}
+		return nil
+	}
+	if !newer && upgradeVersion == "" && !upgradeForce {
+		fmt.Printf("gt %s is up to date (latest release: %s)\n", Version, release.Version())
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	binary, err := fetchVerifiedBinary(ctx, client, release)
+	if err != nil {
+		return err
+	}
+	if err := checkNewBinary(exe, binary); err != nil {
+		return err
+	}
+	backup, err := selfupdate.Replace(exe, binary)
+	if err != nil {
+		return fmt.Errorf("replacing %s: %w", exe, err)
+	}
+	fmt.Printf("%s Upgraded gt %s → %s (%s)\n", style.Bold.Render("✓"), Version, release.Version(), exe)
+	fmt.Printf("  %s\n", style.Dim.Render("Previous binary kept at "+backup))
+
+	if upgradeRollout != "" {
+		return scheduleUpgradeRollout(release.Version(), rollout)
+	}
+	fmt.Printf("  %s\n", style.Dim.Render("Running sessions pick it up when they respawn (gt upgrade --rollout to hand them off)"))
+	return nil
+}
+
+// fetchVerifiedBinary downloads the release archive for this platform,
+// verifies it against the release's checksums (and their signature, if
+// any) and returns the gt binary in it.
+func fetchVerifiedBinary(ctx context.Context, client *selfupdate.Client, release *selfupdate.Release) ([]byte, error) {
+	name := selfupdate.ArchiveName(release.Version(), runtime.GOOS, runtime.GOARCH)
+	archiveAsset, ok := release.Asset(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", selfupdate.ErrNoAsset, name)
+	}
+	sumsAsset, ok := release.Asset(selfupdate.ChecksumsAsset)
+	if !ok {
+		return nil, fmt.Errorf("release %s has no %s; refusing to install an unverified binary", release.Tag, selfupdate.ChecksumsAsset)
+	}
+
+	sums, err := client.Download(ctx, sumsAsset)
+	if err != nil {
+		return nil, fmt.Errorf("downloading checksums: %w", err)
+	}
+	if sigAsset, ok := release.Asset(selfupdate.SignatureAsset); ok {
+		sig, err := client.Download(ctx, sigAsset)
+		if err != nil {
+			return nil, fmt.Errorf("downloading signature: %w", err)
+		}
+		if err := selfupdate.VerifySignature(sums, sig); err != nil {
+			return nil, err
+		}
+		fmt.Printf("%s Signature verified\n", style.Bold.Render("✓"))
+	} else if upgradeRequireSignature {
+		return nil, fmt.Errorf("%w: %s has no %s", selfupdate.ErrNoSignature, release.Tag, selfupdate.SignatureAsset)
+	} else {
+		// The checksums come from the same release as the archive, so they
+		// catch a corrupt download but not a tampered release
+		style.PrintWarning("%s has no %s: the checksum shows the download is intact, not that the release is authentic (--require-signature refuses unsigned releases)",
+			release.Tag, selfupdate.SignatureAsset)
+	}
+
+	fmt.Printf("Downloading %s...\n", name)
+	archive, err := client.Download(ctx, archiveAsset)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", name, err)
+	}
+	if err := selfupdate.VerifyChecksum(sums, name, archive); err != nil {
+		return nil, err
+	}
+	fmt.Printf("%s Checksum verified\n", style.Bold.Render("✓"))
+
+	binName := "gt"
+	if runtime.GOOS == "windows" {
+		binName = "gt.exe"
+	}
+	return selfupdate.ExtractBinary(name, archive, binName)
+}
+
+// checkNewBinary runs "version" with the new binary, written next to exe,
+// so a binary that can't start on this machine is never installed.
+func checkNewBinary(exe string, binary []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(exe), "."+filepath.Base(exe)+".*.check")
+	if err != nil {
+		return fmt.Errorf("writing next to %s: %w", exe, err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(binary)
+	if err == nil {
+		err = tmp.Chmod(0755)
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	out, err := exec.Command(tmp.Name(), "version").CombinedOutput() //nolint:gosec // G204: the verified release binary
+	if err != nil {
+		return fmt.Errorf("new binary does not run: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// scheduleUpgradeRollout sets a pending handoff of every running agent
+// session but polecats, spread evenly over window.
+func scheduleUpgradeRollout(version string, window time.Duration) error {
+	townRoot := detectTownRootFromCwd()
+	if townRoot == "" {
+		return fmt.Errorf("--rollout: cannot detect town root - run from within a Gas Town workspace")
+	}
+	targets, err := handoffAllTargets(tmux.NewTmux(), "", handoffAllFilter{})
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		fmt.Println(style.Dim.Render("No running agent sessions to roll out to"))
+		return nil
+	}
+
+	start := time.Now()
+	reason := "gt upgrade to " + version
+	var errs []error
+	for i, sess := range targets {
+		at := start.Add(window * time.Duration(i) / time.Duration(len(targets)))
+		if _, err := watchdog.SetPending(townRoot, sess, at, reason); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sess, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	fmt.Printf("%s Scheduled handoffs of %d session(s) over %s\n", style.Bold.Render("✓"), len(targets), window)
+	if running, _, _ := watchdog.IsRunning(townRoot); !running {
+		style.PrintWarning("the watchdog runs scheduled handoffs and is not running; start it with: gt watchdog start")
+	}
+	return nil
+}
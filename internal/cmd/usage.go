@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/usage"
+)
+
+var (
+	usageSince string
+	usageBy    string
+	usageJSON  bool
+
+	usageRecordSession    string
+	usageRecordAgent      string
+	usageRecordTranscript string
+)
+
+var usageCmd = &cobra.Command{
+	Use:     "usage",
+	GroupID: GroupDiag,
+	Short:   "Track token usage and cost per agent session",
+	Long: `Track token usage and cost of agent sessions across agents.
+
+Usage snapshots are appended to ~/.gt/usage.jsonl by 'gt costs record' (the
+Claude Code Stop hook) and by 'gt usage record', which agents without that
+hook (Kimi, Codex) can call with their transcript. Token counts are priced
+per model, Claude and Kimi models included.
+
+Examples:
+  gt usage report                      # Last 7 days, by agent
+  gt usage report --since 24h --by rig
+  gt usage report --since 30d --by day --json
+  gt usage record --agent kimi --transcript ~/.kimi/sessions/abc.jsonl`,
+	RunE: requireSubcommand,
+}
+
+var usageReportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Summarize token usage and cost",
+	Long: `Summarize recorded token usage and cost, grouped by session, agent,
+rig, role, model or day.
+
+Each transcript counts once, at its latest snapshot, so a session recorded
+at every stop isn't counted more than once.`,
+	Args: cobra.NoArgs,
+	RunE: runUsageReport,
+}
+
+var usageRecordCmd = &cobra.Command{
+	Use:   "record",
+	Short: "Record a session's token usage",
+	Long: `Record the token usage of a session's transcript to ~/.gt/usage.jsonl.
+
+Without --transcript, the latest Claude Code transcript of $GT_CWD (or the
+current directory) is read. The session is detected like 'gt costs record';
+the agent defaults to $GT_AGENT, then claude.`,
+	Args: cobra.NoArgs,
+	RunE: runUsageRecord,
+}
+
+func init() {
+	usageReportCmd.Flags().StringVar(&usageSince, "since", "7d", "Only include usage recorded within this duration (e.g. 24h, 7d)")
+	usageReportCmd.Flags().StringVar(&usageBy, "by", string(usage.ByAgent), "Group by session, agent, rig, role, model or day")
+	usageReportCmd.Flags().BoolVar(&usageJSON, "json", false, "Output as JSON")
+
+	usageRecordCmd.Flags().StringVar(&usageRecordSession, "session", "", "Session name (default: detected)")
+	usageRecordCmd.Flags().StringVar(&usageRecordAgent, "agent", "", "Agent that ran the session (default: $GT_AGENT or claude)")
+	usageRecordCmd.Flags().StringVar(&usageRecordTranscript, "transcript", "", "JSONL transcript to read usage from")
+
+	usageCmd.AddCommand(usageReportCmd, usageRecordCmd)
+	rootCmd.AddCommand(usageCmd)
+}
+
+// newUsageRecord builds the ledger record of a session's transcript, filling
+// role, rig and worker from the session name.
+func newUsageRecord(session, agent, transcriptPath string, tokens *usage.Tokens) usage.Record {
+	if agent == "" {
+		agent = "claude"
+	}
+	role, rig, worker := parseSessionName(session)
+	return usage.Record{
+		Session:    session,
+		Agent:      agent,
+		Role:       role,
+		Rig:        rig,
+		Worker:     worker,
+		Transcript: transcriptPath,
+		Tokens:     *tokens,
+		CostUSD:    usage.Cost(tokens),
+		RecordedAt: time.Now(),
+	}
+}
+
+func runUsageRecord(cmd *cobra.Command, args []string) error {
+	session := usageRecordSession
+	if session == "" {
+		session = os.Getenv("GT_SESSION")
+	}
+	if session == "" {
+		session = deriveSessionName()
+	}
+	if session == "" {
+		session = detectCurrentTmuxSession()
+	}
+	if session == "" {
+		return fmt.Errorf("--session flag required (or set GT_SESSION env var, or GT_RIG/GT_ROLE)")
+	}
+
+	var tokens *usage.Tokens
+	transcriptPath := usageRecordTranscript
+	var err error
+	if transcriptPath != "" {
+		tokens, err = usage.ParseTranscript(transcriptPath)
+	} else {
+		workDir := os.Getenv("GT_CWD")
+		if workDir == "" {
+			if workDir, err = os.Getwd(); err != nil {
+				return err
+			}
+		}
+		tokens, transcriptPath, err = extractUsageFromWorkDir(workDir)
+	}
+	if err != nil {
+		return fmt.Errorf("reading usage: %w", err)
+	}
+
+	agent := usageRecordAgent
+	if agent == "" {
+		agent = os.Getenv("GT_AGENT")
+	}
+	rec := newUsageRecord(session, agent, transcriptPath, tokens)
+	if err := usage.Append(usage.LogPath(), rec); err != nil {
+		return err
+	}
+	fmt.Printf("%s Recorded %s tokens ($%.2f) for %s\n",
+		style.Success.Render("✓"), formatTokenCount(tokens.Total()), rec.CostUSD, session)
+	return nil
+}
+
+func runUsageReport(cmd *cobra.Command, args []string) error {
+	by, err := usage.ParseDimension(usageBy)
+	if err != nil {
+		return err
+	}
+	window, err := parseDuration(usageSince)
+	if err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+
+	records, err := usage.Load(usage.LogPath(), time.Now().Add(-window))
+	if err != nil {
+		return fmt.Errorf("reading usage log: %w", err)
+	}
+	rows := usage.Aggregate(records, by)
+
+	if usageJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	printUsageReport(rows, by)
+	return nil
+}
+
+func printUsageReport(rows []usage.Row, by usage.Dimension) {
+	if len(rows) == 0 {
+		fmt.Println(style.Dim.Render("No usage recorded in this period"))
+		return
+	}
+
+	fmt.Printf("\n%s Token Usage by %s (since %s)\n\n", style.Bold.Render("📊"), by, usageSince)
+	fmt.Printf("%-25s %8s %10s %10s %10s %10s\n", strings.ToUpper(string(by[:1]))+string(by[1:]),
+		"Sessions", "Input", "Output", "Cached", "Cost")
+	fmt.Println(strings.Repeat("─", 78))
+
+	var total float64
+	var tokens usage.Tokens
+	for _, r := range rows {
+		fmt.Printf("%-25s %8d %10s %10s %10s %10s\n", r.Key, r.Sessions,
+			formatTokenCount(r.Tokens.Input+r.Tokens.CacheCreate),
+			formatTokenCount(r.Tokens.Output),
+			formatTokenCount(r.Tokens.CacheRead),
+			fmt.Sprintf("$%.2f", r.CostUSD))
+		total += r.CostUSD
+		tokens.Input += r.Tokens.Input
+		tokens.Output += r.Tokens.Output
+		tokens.CacheRead += r.Tokens.CacheRead
+		tokens.CacheCreate += r.Tokens.CacheCreate
+	}
+
+	fmt.Println(strings.Repeat("─", 78))
+	fmt.Printf("%s $%.2f (%s tokens)\n", style.Bold.Render("Total:"), total, formatTokenCount(tokens.Total()))
+}
+
+// formatTokenCount abbreviates a token count: 950, 12.3K, 4.1M.
+func formatTokenCount(n int) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fK", float64(n)/1_000)
+	}
+	return fmt.Sprintf("%d", n)
+}
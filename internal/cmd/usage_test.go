@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/usage"
+)
+
+func TestNewUsageRecord(t *testing.T) {
+	tokens := &usage.Tokens{Model: "kimi-k2-0905-preview", Input: 1_000_000}
+	rec := newUsageRecord("gt-web-crew-jane", "kimi", "/tmp/t.jsonl", tokens)
+	if rec.Agent != "kimi" || rec.Role != "crew" || rec.Rig != "web" || rec.Worker != "jane" {
+		t.Errorf("newUsageRecord() = %+v", rec)
+	}
+	if rec.CostUSD != 0.6 {
+		t.Errorf("CostUSD = %v, want 0.6", rec.CostUSD)
+	}
+	if rec := newUsageRecord("hq-mayor", "", "", tokens); rec.Agent != "claude" {
+		t.Errorf("default agent = %q, want claude", rec.Agent)
+	}
+}
+
+func TestFormatTokenCount(t *testing.T) {
+	for n, want := range map[int]string{950: "950", 12_300: "12.3K", 4_100_000: "4.1M"} {
+		if got := formatTokenCount(n); got != want {
+			t.Errorf("formatTokenCount(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
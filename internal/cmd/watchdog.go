@@ -0,0 +1,501 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/agentstatus"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/events"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/mayor"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
+	"github.com/steveyegge/gastown/internal/watchdog"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+var watchdogCmd = &cobra.Command{
+	Use:     "watchdog",
+	GroupID: GroupServices,
+	Short:   "Restart agents that die in their sessions",
+	RunE:    requireSubcommand,
+	Long: `Manage the dead-agent watchdog.
+
+The watchdog is a small background process that polls every agent session
+and notices when the agent has exited but the session is still open - a
+crew member that crashed overnight, say. What it does then is set in town
+settings (settings/config.json):
+
+  "watchdog": {
+    "policy": "resume",
+    "role_policies": {"mayor": "notify"},
+    "interval": "30s",
+    "max_restarts": 3,
+    "metrics_addr": "127.0.0.1:9464",
+    "report_interval": "1h"
+  }
+
+Policies:
+  resume   Restart the agent in its last conversation (fresh if it can't resume)
+  fresh    Restart the agent with a new conversation
+  notify   Only report the dead agent (feed event and mail)
+
+An agent must be dead on two polls in a row before it is handled. A session
+restarted max_restarts times within the hour is only reported after that.
+
+Live agents are watched as well: the watchdog reads the bottom of each
+agent's pane, and an agent showing a confirmation prompt or an error on two
+polls in a row is reported to its rig's witness (the mayor for town-level
+agents). What counts as waiting, working, done or an error is recognized
+per agent preset ("output_patterns" in agents.json).
+
+The watchdog also runs scheduled handoffs (gt handoff --schedule), and
+with report_interval set writes a witness report (gt witness report) of
+each rig with live crew or polecats once per interval.
+
+Mayor failover: if the mayor's session disappears entirely (without gt
+mayor stop) for two polls, the watchdog applies the mayor's policy. Under
+resume or fresh it promotes the standby mayor (gt mayor standby) if one is
+running, and otherwise recreates the mayor's session from the session
+manifest.
+
+With metrics_addr set, the watchdog serves Prometheus metrics at /metrics:
+agent sessions by role (alive or dead), deaths, restarts, stuck agents,
+failed restarts and handoffs per session, scheduled handoffs, handoffs by
+agent from the events feed, and bead lifecycle moves and beads per stage.`,
+}
+
+var watchdogStartCmd = &cobra.Command{
+	Use:   "start",
+	Short: "Start the watchdog in the background",
+	RunE:  runWatchdogStart,
+}
+
+var watchdogStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the watchdog",
+	RunE:  runWatchdogStop,
+}
+
+var watchdogStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show watchdog status and policy",
+	RunE:  runWatchdogStatus,
+}
+
+var watchdogRunCmd = &cobra.Command{
+	Use:   "run",
+	Short: "Run the watchdog in the foreground",
+	Long: `Run the watchdog in the foreground, logging to stdout.
+
+Use this under a service manager; gt watchdog start runs it in the
+background, logging to daemon/watchdog.log.`,
+	RunE: runWatchdogRun,
+}
+
+func init() {
+	watchdogCmd.AddCommand(watchdogStartCmd)
+	watchdogCmd.AddCommand(watchdogStopCmd)
+	watchdogCmd.AddCommand(watchdogStatusCmd)
+	watchdogCmd.AddCommand(watchdogRunCmd)
+
+	rootCmd.AddCommand(watchdogCmd)
+}
+
+// loadWatchdogConfig returns the town's watchdog settings, validated.
+func loadWatchdogConfig(townRoot string) (*config.WatchdogConfig, error) {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if err := settings.Watchdog.Validate(); err != nil {
+		return nil, err
+	}
+	return settings.Watchdog, nil
+}
+
+func runWatchdogStart(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	if _, err := loadWatchdogConfig(townRoot); err != nil {
+		return err
+	}
+	if running, pid, _ := watchdog.IsRunning(townRoot); running {
+		return fmt.Errorf("watchdog already running (PID %d)", pid)
+	}
+
+	gtPath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("finding executable: %w", err)
+	}
+	if err := os.MkdirAll(watchdog.Dir(townRoot), 0755); err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(watchdog.LogFile(townRoot), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening log file: %w", err)
+	}
+	defer logFile.Close()
+
+	runCmd := exec.Command(gtPath, "watchdog", "run")
+	runCmd.Dir = townRoot
+	runCmd.Stdout = logFile
+	runCmd.Stderr = logFile
+	if err := runCmd.Start(); err != nil {
+		return fmt.Errorf("starting watchdog: %w", err)
+	}
+
+	// Wait a moment for the watchdog to take its lock
+	time.Sleep(200 * time.Millisecond)
+	running, pid, err := watchdog.IsRunning(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking watchdog status: %w", err)
+	}
+	if !running {
+		return fmt.Errorf("watchdog failed to start (see %s)", watchdog.LogFile(townRoot))
+	}
+	fmt.Printf("%s Watchdog started (PID %d)\n", style.Bold.Render("✓"), pid)
+	return nil
+}
+
+func runWatchdogStop(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	pid, err := watchdog.Stop(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s Watchdog stopped (was PID %d)\n", style.Bold.Render("✓"), pid)
+	return nil
+}
+
+func runWatchdogStatus(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	running, pid, err := watchdog.IsRunning(townRoot)
+	if err != nil {
+		return fmt.Errorf("checking watchdog status: %w", err)
+	}
+	if running {
+		fmt.Printf("%s Watchdog is %s (PID %d)\n", style.Bold.Render("●"), style.Bold.Render("running"), pid)
+	} else {
+		fmt.Printf("%s Watchdog is not running\n", style.Dim.Render("○"))
+		fmt.Printf("\nStart with: %s\n", style.Dim.Render("gt watchdog start"))
+	}
+
+	cfg, err := loadWatchdogConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  Policy: %s\n", cfg.PolicyFor(""))
+	if cfg != nil {
+		for _, role := range slices.Sorted(maps.Keys(cfg.RolePolicies)) {
+			fmt.Printf("    %s: %s\n", role, cfg.RolePolicies[role])
+		}
+	}
+	fmt.Printf("  Interval: %s, at most %d restarts per session per hour\n", cfg.GetInterval(), cfg.GetMaxRestarts())
+	if d := cfg.GetReportInterval(); d > 0 {
+		fmt.Printf("  Witness reports: every %s\n", d)
+	}
+	if cfg != nil && cfg.MetricsAddr != "" {
+		fmt.Printf("  Metrics: http://%s/metrics\n", cfg.MetricsAddr)
+	}
+
+	schedules, err := loadAllSchedules(townRoot)
+	if err != nil {
+		return err
+	}
+	if len(schedules) > 0 {
+		fmt.Println("  Handoff schedules:")
+		printHandoffSchedules(schedules)
+	}
+	return nil
+}
+
+func runWatchdogRun(cmd *cobra.Command, args []string) error {
+	townRoot, err := workspace.FindFromCwdOrError()
+	if err != nil {
+		return fmt.Errorf("not in a Gas Town workspace: %w", err)
+	}
+	cfg, err := loadWatchdogConfig(townRoot)
+	if err != nil {
+		return err
+	}
+	release, err := watchdog.Lock(townRoot)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	logger := log.New(os.Stdout, "", log.LstdFlags)
+	t := tmux.NewTmux()
+	w := watchdog.New(townRoot, cfg, t)
+	w.Restart = func(sess string, fresh bool) error {
+		return restartDeadAgent(t, townRoot, sess, fresh)
+	}
+	w.Failover = func(fresh bool) (bool, error) {
+		return failoverMayor(t, townRoot, fresh)
+	}
+	w.Notify = func(a watchdog.Action) {
+		switch {
+		case a.StuckFor > 0:
+			notifyWatchdogStuck(townRoot, a)
+		case a.Stuck != "":
+			notifyStuckAgent(townRoot, a)
+		default:
+			notifyDeadAgent(townRoot, a)
+			notifyAgentCrash(townRoot, "", a.Session, a.String())
+		}
+	}
+	w.StuckAfter = func(rig string) time.Duration {
+		return notifyStuckAfter(townRoot, rig)
+	}
+	w.Handoff = func(h watchdog.HandoffSchedule) error {
+		return scheduledHandoff(townRoot, h)
+	}
+	if cfg.GetReportInterval() > 0 {
+		w.Report = func(rig string) error {
+			return scheduledWitnessReport(townRoot, rig, t, cfg.ReportLines)
+		}
+	}
+	if cfg != nil && cfg.MetricsAddr != "" {
+		w.Metrics = watchdog.NewMetrics(townRoot)
+		stop, err := serveWatchdogMetrics(cfg.MetricsAddr, w.Metrics)
+		if err != nil {
+			return err
+		}
+		defer stop()
+		logger.Printf("Serving metrics on http://%s/metrics", cfg.MetricsAddr)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	logger.Printf("Watchdog running (PID %d), polling every %s", os.Getpid(), cfg.GetInterval())
+	w.Run(ctx, func(a watchdog.Action) {
+		logger.Print(a)
+		logWatchdogAction(a)
+		if a.Restarted {
+			_ = events.LogFeed(events.TypeSessionDeath, "watchdog",
+				events.SessionDeathPayload(a.Session, a.Agent, "agent exited, restarted ("+a.Policy+")", "gt watchdog"))
+		}
+	}, func(err error) {
+		logger.Printf("Warning: %v", err)
+		gtlog.L().Warn("watchdog poll failed", "err", err)
+	})
+	logger.Println("Watchdog stopped")
+	return nil
+}
+
+// serveWatchdogMetrics serves m at /metrics on addr until the returned
+// func is called.
+func serveWatchdogMetrics(addr string, m *watchdog.Metrics) (func(), error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("serving metrics: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+	return func() { _ = srv.Close() }, nil
+}
+
+// logWatchdogAction records a to the structured log.
+func logWatchdogAction(a watchdog.Action) {
+	attrs := []any{"session", a.Session, "session_role", a.Role, "policy", a.Policy}
+	if a.Agent != "" {
+		attrs = append(attrs, "session_agent", a.Agent)
+	}
+	switch {
+	case a.Stuck != "":
+		gtlog.L().Warn("watchdog found stuck agent", append(attrs, "status", string(a.Stuck), "line", a.Line)...)
+	case a.Report != "" && a.Err != nil:
+		gtlog.L().Error("witness report failed", append(attrs, "rig", a.Report, "err", a.Err)...)
+	case a.Report != "":
+		gtlog.L().Info("witness report written", append(attrs, "rig", a.Report)...)
+	case a.Schedule != "" && a.Err != nil:
+		gtlog.L().Error("scheduled handoff failed", append(attrs, "schedule", a.Schedule, "err", a.Err)...)
+	case a.Schedule != "":
+		gtlog.L().Info("scheduled handoff", append(attrs, "schedule", a.Schedule)...)
+	case a.Err != nil:
+		gtlog.L().Error("watchdog restart failed", append(attrs, "err", a.Err)...)
+	case a.Restarted:
+		gtlog.L().Info("watchdog restarted agent", attrs...)
+	case a.Throttled:
+		gtlog.L().Warn("watchdog restart throttled", attrs...)
+	default:
+		gtlog.L().Info("watchdog found dead agent", attrs...)
+	}
+}
+
+// restartDeadAgent restarts the agent in sess's pane, resuming its recorded
+// conversation unless fresh is set.
+func restartDeadAgent(t *tmux.Tmux, townRoot, sess string, fresh bool) error {
+	entry, err := watchdogManifestEntry(t, townRoot, sess)
+	if err != nil {
+		return err
+	}
+	if fresh {
+		entry.AgentSessionID = ""
+	}
+	plan, err := planSessionResume(entry)
+	if err != nil {
+		return err
+	}
+	pane, err := t.GetPaneID(sess)
+	if err != nil {
+		return fmt.Errorf("getting pane: %w", err)
+	}
+	if err := t.RespawnPane(pane, plan.Command); err != nil {
+		return fmt.Errorf("respawning pane: %w", err)
+	}
+	// Set environment variables (non-fatal: session works without these)
+	for k, v := range plan.Env {
+		_ = t.SetEnvironment(sess, k, v)
+	}
+	return nil
+}
+
+// failoverMayor brings back a mayor whose session is gone: it promotes the
+// standby mayor (gt mayor standby) if one is running, or else recreates the
+// mayor's session from its manifest entry, resuming its conversation unless
+// fresh is set.
+func failoverMayor(t *tmux.Tmux, townRoot string, fresh bool) (promoted bool, err error) {
+	err = mayor.NewManager(townRoot).Promote()
+	if err == nil || !errors.Is(err, mayor.ErrNoStandby) {
+		return err == nil, err
+	}
+
+	entry, err := watchdogManifestEntry(t, townRoot, session.MayorSessionName())
+	if err != nil {
+		return false, err
+	}
+	if fresh {
+		entry.AgentSessionID = ""
+	}
+	plan, err := planSessionResume(entry)
+	if err != nil {
+		return false, err
+	}
+	if err := startResumedSession(t, plan); err != nil {
+		return false, err
+	}
+	// Apply Mayor theming (non-fatal, as in gt mayor start)
+	_ = t.ConfigureGasTownSession(entry.Session, tmux.MayorTheme(), "", "Mayor", "coordinator")
+	_ = transcript.Enable(t, entry.Session)
+	return false, nil
+}
+
+// watchdogManifestEntry returns what is known about sess: its session
+// manifest entry (see gt resume --all) or, for a session that was never
+// recorded, its identity, agent and pane directory.
+func watchdogManifestEntry(t *tmux.Tmux, townRoot, sess string) (session.ManifestEntry, error) {
+	if entries, err := session.LoadManifest(session.ManifestPath()); err == nil {
+		for _, e := range entries {
+			if e.Session == sess {
+				return e, nil
+			}
+		}
+	}
+
+	identity, err := session.ParseSessionName(sess)
+	if err != nil {
+		return session.ManifestEntry{}, fmt.Errorf("cannot parse session name: %w", err)
+	}
+	workDir, err := t.GetPaneWorkDir(sess)
+	if err != nil || workDir == "" {
+		return session.ManifestEntry{}, fmt.Errorf("getting working dir: %w", err)
+	}
+	agent, _ := t.GetEnvironment(sess, "GT_AGENT")
+	return session.ManifestEntry{
+		Session:  sess,
+		Role:     string(identity.Role),
+		Rig:      identity.Rig,
+		Agent:    agent,
+		WorkDir:  workDir,
+		TownRoot: townRoot,
+	}, nil
+}
+
+// notifyDeadAgent reports a dead agent the watchdog didn't restart: a feed
+// event, and mail to the rig's witness (the mayor for town-level agents and
+// for witnesses themselves).
+func notifyDeadAgent(townRoot string, a watchdog.Action) {
+	_ = events.LogFeed(events.TypeSessionDeath, "watchdog",
+		events.SessionDeathPayload(a.Session, a.Agent, a.String(), "gt watchdog"))
+
+	to, address := watchdogMailTarget(a.Session)
+	subject := "DEAD_AGENT: "
+	body := fmt.Sprintf("The agent in session %s has exited but the session is still open.\n\n%s\n\nRestart it with: gt handoff %s", a.Session, a, address)
+	if a.Missing {
+		// Read by the mayor once it is back
+		subject = "MAYOR_LOST: "
+		body = fmt.Sprintf("The mayor's session %s is gone.\n\n%s\n\nStart it with: gt mayor start (or gt resume --all to resume its conversation)", a.Session, a)
+	}
+	msg := &mail.Message{
+		From:     "gt-watchdog",
+		To:       to,
+		Subject:  subject + a.Session,
+		Body:     body,
+		Type:     mail.TypeNotification,
+		Priority: mail.PriorityHigh,
+	}
+	_ = mail.NewRouter(townRoot).Send(msg)
+}
+
+// notifyStuckAgent mails a live agent's stuck screen to whoever watches it
+// (see watchdogMailTarget).
+func notifyStuckAgent(townRoot string, a watchdog.Action) {
+	to, address := watchdogMailTarget(a.Session)
+	subject, what := "AGENT_WAITING: ", "is waiting for input"
+	if a.Stuck == agentstatus.StatusError {
+		subject, what = "AGENT_ERROR: ", "has stopped on an error"
+	}
+	body := fmt.Sprintf("The agent in session %s %s:\n\n    %s\n\nLook at its screen with: gt peek %s\nAnswer it with: gt nudge %s \"<reply>\"", a.Session, what, a.Line, address, address)
+	msg := &mail.Message{
+		From:     "gt-watchdog",
+		To:       to,
+		Subject:  subject + a.Session,
+		Body:     body,
+		Type:     mail.TypeNotification,
+		Priority: mail.PriorityHigh,
+	}
+	_ = mail.NewRouter(townRoot).Send(msg)
+}
+
+// watchdogMailTarget returns who the watchdog reports sess to - the rig's
+// witness, or the mayor for town-level agents and for witnesses themselves -
+// and the session's agent address.
+func watchdogMailTarget(sess string) (to, address string) {
+	to, address = "mayor/", sess
+	if identity, err := session.ParseSessionName(sess); err == nil {
+		address = identity.Address()
+		if identity.Rig != "" && identity.Role != session.RoleWitness {
+			to = identity.Rig + "/witness"
+		}
+	}
+	return to, address
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/steveyegge/gastown/internal/hooks"
+	"github.com/steveyegge/gastown/internal/style"
+	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/witness"
+)
+
+var (
+	witnessReportLines  int
+	witnessReportDryRun bool
+	witnessReportJSON   bool
+)
+
+var witnessReportCmd = &cobra.Command{
+	Use:   "report <rig>",
+	Short: "Summarize what the rig's crew and polecats are doing",
+	Long: `Write a status report of a rig's crew and polecat sessions.
+
+For each session the report captures the bottom of the pane, classifies
+what the agent is doing (working, waiting for input, stopped on an error,
+done) and summarizes it. The summary is what the report-summary hooks print
+for the pane (see gt hooks events), or the pane's last lines without them.
+
+The report is printed and appended to <rig>/witness/status.md, then the
+witness-report hooks fire with the report, to post it wherever you like.
+
+The watchdog writes reports on its own with report_interval set in the
+town's watchdog settings:
+
+  "watchdog": {
+    "report_interval": "1h",
+    "report_lines": 40
+  }
+
+Examples:
+  gt witness report greenplace
+  gt witness report greenplace --dry-run
+  gt witness report greenplace --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWitnessReport,
+}
+
+func init() {
+	witnessReportCmd.Flags().IntVarP(&witnessReportLines, "lines", "n", witness.DefaultReportLines, "Pane lines to capture per session")
+	witnessReportCmd.Flags().BoolVar(&witnessReportDryRun, "dry-run", false, "Print the report without saving it or firing hooks")
+	witnessReportCmd.Flags().BoolVar(&witnessReportJSON, "json", false, "Output as JSON")
+
+	witnessCmd.AddCommand(witnessReportCmd)
+}
+
+func runWitnessReport(cmd *cobra.Command, args []string) error {
+	townRoot, r, err := getRig(args[0])
+	if err != nil {
+		return err
+	}
+	report, err := buildWitnessReport(townRoot, r.Name, tmux.NewTmux(), witnessReportLines)
+	if err != nil {
+		return err
+	}
+
+	if witnessReportJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Print(report.Markdown())
+	}
+	if witnessReportDryRun {
+		return nil
+	}
+
+	path, err := saveWitnessReport(townRoot, report)
+	if err != nil {
+		return err
+	}
+	if !witnessReportJSON {
+		fmt.Printf("\n%s Appended to %s\n", style.Bold.Render("✓"), path)
+	}
+	return nil
+}
+
+// buildWitnessReport reports on rigName's sessions, summarizing each pane
+// with the report-summary hooks.
+func buildWitnessReport(townRoot, rigName string, source witness.ReportSource, lines int) (*witness.Report, error) {
+	return witness.BuildReport(rigName, source, lines, func(e witness.ReportEntry, tail string) string {
+		return hooks.Collect(townRoot, hooks.ReportSummary, map[string]string{
+			"rig":     rigName,
+			"session": e.Session,
+			"agent":   e.Agent,
+			"status":  string(e.Status),
+			"tail":    tail,
+		})
+	})
+}
+
+// saveWitnessReport appends report to its rig's status file and fires the
+// witness-report hooks. Returns the status file.
+func saveWitnessReport(townRoot string, report *witness.Report) (string, error) {
+	path := witness.ReportFile(filepath.Join(townRoot, report.Rig))
+	if err := witness.AppendReport(path, report); err != nil {
+		return "", err
+	}
+	hooks.Fire(townRoot, hooks.WitnessReport, map[string]string{
+		"rig":    report.Rig,
+		"file":   path,
+		"report": report.Markdown(),
+	})
+	return path, nil
+}
+
+// scheduledWitnessReport writes the report the watchdog has due for rig.
+func scheduledWitnessReport(townRoot, rig string, source witness.ReportSource, lines int) error {
+	report, err := buildWitnessReport(townRoot, rig, source, lines)
+	if err != nil {
+		return err
+	}
+	_, err = saveWitnessReport(townRoot, report)
+	return err
+}
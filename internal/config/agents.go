@@ -2,11 +2,18 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"sync"
+
+	"github.com/BurntSushi/toml"
 )
 
 // AgentPreset identifies a supported LLM agent runtime.
@@ -31,14 +38,21 @@ const (
 	AgentOpenCode AgentPreset = "opencode"
 	// AgentKimi is Kimi Code CLI (K2.5 and other models).
 	AgentKimi AgentPreset = "kimi"
+	// AgentAider is Aider, the git-native pair programming CLI.
+	AgentAider AgentPreset = "aider"
+	// AgentQwen is Qwen Code, Alibaba's coding CLI for Qwen models.
+	AgentQwen AgentPreset = "qwen"
 )
 
 // AgentPresetInfo contains the configuration details for an agent preset.
 // This extends the basic RuntimeConfig with agent-specific metadata.
 type AgentPresetInfo struct {
-	// Name is the preset identifier (e.g., "claude", "gemini", "codex", "cursor", "auggie", "amp", "kimi").
+	// Name is the preset identifier (e.g., "claude", "gemini", "codex", "cursor", "auggie", "amp", "kimi", "aider", "qwen").
 	Name AgentPreset `json:"name"`
 
+	// Description is a short human-readable summary shown in agent listings.
+	Description string `json:"description,omitempty"`
+
 	// Command is the CLI binary to invoke.
 	Command string `json:"command"`
 
@@ -46,7 +60,8 @@ type AgentPresetInfo struct {
 	Args []string `json:"args"`
 
 	// Env are environment variables to set when starting the agent.
-	// These are merged with the standard GT_* variables.
+	// These are merged with the standard GT_* variables and exported in
+	// the agent's command (see RuntimeConfig.Env).
 	// Used for agent-specific configuration like OPENCODE_PERMISSION.
 	Env map[string]string `json:"env,omitempty"`
 
@@ -61,14 +76,39 @@ type AgentPresetInfo struct {
 
 	// ResumeFlag is the flag/subcommand for resuming sessions.
 	// For claude/gemini: "--resume"
-	// For codex: "resume" (subcommand)
+	// For codex: "resume" (subcommand); may be several words ("threads continue")
 	ResumeFlag string `json:"resume_flag,omitempty"`
 
 	// ResumeStyle indicates how to invoke resume:
 	// "flag" - pass as --resume <id> argument
-	// "subcommand" - pass as 'codex resume <id>'
+	// "subcommand" - pass as 'codex resume <id>', right after the command
+	// and ahead of the preset's args and per-invocation flags
 	ResumeStyle string `json:"resume_style,omitempty"`
 
+	// ResumeTemplate, when set, lays out the resume command instead of
+	// ResumeStyle, for CLIs that want the session ID somewhere else.
+	// Placeholders: {command}, {args}, {resumeFlag}, {sessionID}; any other
+	// {name} is an error. E.g., "{command} {args} session {sessionID} --attach".
+	// ResumeFlag must still be set - it marks the agent as resumable.
+	ResumeTemplate string `json:"resume_template,omitempty"`
+
+	// YoloArgs are the args that make the agent skip permission prompts,
+	// each a flag or a flag and its value ("--approval-mode yolo"). An
+	// agent policy with allow_yolo false removes them.
+	YoloArgs []string `json:"yolo_args,omitempty"`
+
+	// YoloEnv are the Env variables that make the agent skip permission
+	// prompts, removed like YoloArgs.
+	YoloEnv []string `json:"yolo_env,omitempty"`
+
+	// SandboxHosts are the API hosts a sandboxed agent may reach
+	// ("*.example.com" allows subdomains).
+	SandboxHosts []string `json:"sandbox_hosts,omitempty"`
+
+	// SandboxWritable are the state directories (and files) outside its
+	// worktree a sandboxed agent may write, e.g. "~/.claude".
+	SandboxWritable []string `json:"sandbox_writable,omitempty"`
+
 	// SupportsHooks indicates if the agent supports hooks system.
 	SupportsHooks bool `json:"supports_hooks,omitempty"`
 
@@ -76,10 +116,77 @@ type AgentPresetInfo struct {
 	// Claude-only feature for seance command.
 	SupportsForkSession bool `json:"supports_fork_session,omitempty"`
 
+	// HooksDir is the agent's settings directory, where hook scripts live
+	// (e.g., ".claude", ".kimi").
+	HooksDir string `json:"hooks_dir,omitempty"`
+
+	// InstructionsFile is the role instructions filename the agent reads
+	// (e.g., "CLAUDE.md", "AGENTS.md").
+	InstructionsFile string `json:"instructions_file,omitempty"`
+
+	// MCPConfigFlag is the flag used to pass a Model Context Protocol server
+	// config file (e.g., "--mcp-config" for claude).
+	// Empty means the agent cannot be launched with an MCP config.
+	MCPConfigFlag string `json:"mcp_config_flag,omitempty"`
+
+	// ModelFlag is the flag used to select the model (e.g., "--model").
+	// Empty means the agent's model cannot be chosen at launch.
+	ModelFlag string `json:"model_flag,omitempty"`
+
+	// OutputJSONFlag is the flag (with value, if any) that switches the agent
+	// to structured/streaming JSON output (e.g., "--output-format stream-json").
+	// Empty means the agent cannot be launched in JSON output mode.
+	OutputJSONFlag string `json:"output_json_flag,omitempty"`
+
+	// PrimingPrompt is typed into a fresh session once the agent is ready
+	// (e.g., "Resume the molecule on your hook."). Empty sends nothing.
+	PrimingPrompt string `json:"priming_prompt,omitempty"`
+
+	// RequiresTTY marks agents whose CLI refuses to run without a terminal.
+	// They are always launched in a tmux pane, never as a detached process.
+	RequiresTTY bool `json:"requires_tty,omitempty"`
+
+	// OutputPatterns recognize from its pane whether the agent is working,
+	// waiting for input, done or stuck on an error (see package
+	// agentstatus). Nil uses generic patterns only.
+	OutputPatterns *OutputPatterns `json:"output_patterns,omitempty"`
+
 	// NonInteractive contains settings for non-interactive mode.
 	NonInteractive *NonInteractiveConfig `json:"non_interactive,omitempty"`
 }
 
+// OutputPatterns are Go regular expressions matched against single lines
+// at the bottom of an agent's pane. A kind left empty falls back to the
+// generic patterns for it.
+type OutputPatterns struct {
+	// Working matches while the agent is busy (a spinner, "esc to interrupt").
+	Working []string `json:"working,omitempty"`
+
+	// Waiting matches a confirmation or question the agent is blocked on.
+	Waiting []string `json:"waiting,omitempty"`
+
+	// Done matches the agent back at its prompt with nothing to do.
+	Done []string `json:"done,omitempty"`
+
+	// Error matches an error the agent stopped on (API failure, usage limit).
+	Error []string `json:"error,omitempty"`
+}
+
+// Validate checks every pattern compiles.
+func (p *OutputPatterns) Validate() error {
+	if p == nil {
+		return nil
+	}
+	for kind, patterns := range map[string][]string{"working": p.Working, "waiting": p.Waiting, "done": p.Done, "error": p.Error} {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("output_patterns.%s: %w", kind, err)
+			}
+		}
+	}
+	return nil
+}
+
 // NonInteractiveConfig contains settings for running agents non-interactively.
 type NonInteractiveConfig struct {
 	// Subcommand is the subcommand for non-interactive execution (e.g., "exec" for codex).
@@ -103,32 +210,88 @@ type AgentRegistry struct {
 }
 
 // CurrentAgentRegistryVersion is the current schema version.
+// Bump it with a new entry in agentRegistryMigrations when a field's
+// meaning changes, so older files are upgraded rather than misread.
 const CurrentAgentRegistryVersion = 1
 
+// agentRegistryMigrations upgrade a user registry one schema version at a
+// time: agentRegistryMigrations[v] migrates version v to v+1.
+var agentRegistryMigrations = []func(*AgentRegistry){
+	// 0 -> 1: files written before versioning have no version field;
+	// their fields already mean what they do in version 1.
+	func(*AgentRegistry) {},
+}
+
+// migrateAgentRegistry upgrades a registry loaded from path to
+// CurrentAgentRegistryVersion. Files from a newer gt are rejected with
+// ErrInvalidVersion rather than loaded with fields this version ignores.
+func migrateAgentRegistry(r *AgentRegistry, path string) error {
+	if r.Version > CurrentAgentRegistryVersion {
+		return fmt.Errorf("%w: %s is agent registry version %d, but this gt supports up to %d - upgrade gt to use it",
+			ErrInvalidVersion, path, r.Version, CurrentAgentRegistryVersion)
+	}
+	if r.Version < 0 {
+		return fmt.Errorf("%w: %s has negative agent registry version %d", ErrInvalidVersion, path, r.Version)
+	}
+	for r.Version < CurrentAgentRegistryVersion {
+		agentRegistryMigrations[r.Version](r)
+		r.Version++
+	}
+	return nil
+}
+
 // builtinPresets contains the default presets for supported agents.
 var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 	AgentClaude: {
 		Name:                AgentClaude,
+		Description:         "Anthropic Claude Code CLI with permission prompts skipped",
 		Command:             "claude",
 		Args:                []string{"--dangerously-skip-permissions"},
+		YoloArgs:            []string{"--dangerously-skip-permissions", "--permission-mode bypassPermissions"},
 		ProcessNames:        []string{"node", "claude"}, // Claude runs as Node.js
+		SandboxHosts:        []string{"api.anthropic.com", "statsig.anthropic.com"},
+		SandboxWritable:     []string{"~/.claude", "~/.claude.json"},
 		SessionIDEnv:        "CLAUDE_SESSION_ID",
 		ResumeFlag:          "--resume",
 		ResumeStyle:         "flag",
 		SupportsHooks:       true,
 		SupportsForkSession: true,
-		NonInteractive:      nil, // Claude is native non-interactive
+		HooksDir:            ".claude",
+		InstructionsFile:    "CLAUDE.md",
+		ModelFlag:           "--model",
+		MCPConfigFlag:       "--mcp-config",
+		OutputJSONFlag:      "--output-format stream-json",
+		RequiresTTY:         true,
+		OutputPatterns: &OutputPatterns{
+			Working: []string{`esc to interrupt`},
+			Waiting: []string{`Do you want to `, `^❯ 1\. Yes`},
+			Done:    []string{`^❯\s*$`},
+			Error:   []string{`API Error`, `(?i)usage limit reached`},
+		},
+		NonInteractive: nil, // Claude is native non-interactive
 	},
 	AgentGemini: {
 		Name:                AgentGemini,
+		Description:         "Google Gemini CLI in yolo approval mode",
 		Command:             "gemini",
 		Args:                []string{"--approval-mode", "yolo"},
+		YoloArgs:            []string{"--approval-mode yolo", "--yolo"},
 		ProcessNames:        []string{"gemini"}, // Gemini CLI binary
+		SandboxHosts:        []string{"generativelanguage.googleapis.com", "cloudcode-pa.googleapis.com", "oauth2.googleapis.com"},
+		SandboxWritable:     []string{"~/.gemini"},
 		SessionIDEnv:        "GEMINI_SESSION_ID",
 		ResumeFlag:          "--resume",
 		ResumeStyle:         "flag",
 		SupportsHooks:       true,
 		SupportsForkSession: false,
+		HooksDir:            ".gemini",
+		InstructionsFile:    "GEMINI.md",
+		ModelFlag:           "--model",
+		OutputPatterns: &OutputPatterns{
+			Working: []string{`esc to cancel`},
+			Waiting: []string{`Apply this change\?`, `Allow execution`, `Waiting for user confirmation`},
+			Error:   []string{`\[API Error`, `(?i)quota exceeded`},
+		},
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
@@ -136,14 +299,26 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 	},
 	AgentCodex: {
 		Name:                AgentCodex,
+		Description:         "OpenAI Codex CLI in yolo mode",
 		Command:             "codex",
 		Args:                []string{"--yolo"},
+		YoloArgs:            []string{"--yolo", "--dangerously-bypass-approvals-and-sandbox"},
 		ProcessNames:        []string{"codex"}, // Codex CLI binary
+		SandboxHosts:        []string{"api.openai.com", "chatgpt.com", "auth.openai.com"},
+		SandboxWritable:     []string{"~/.codex"},
 		SessionIDEnv:        "", // Codex captures from JSONL output
 		ResumeFlag:          "resume",
 		ResumeStyle:         "subcommand",
 		SupportsHooks:       false, // Use env/files instead
 		SupportsForkSession: false,
+		HooksDir:            ".codex",
+		InstructionsFile:    "AGENTS.md",
+		ModelFlag:           "-m",
+		OutputPatterns: &OutputPatterns{
+			Working: []string{`esc to interrupt`},
+			Waiting: []string{`Allow command\?`, `Approve (this )?(edit|command)`},
+			Error:   []string{`(?i)stream error`, `(?i)usage limit`},
+		},
 		NonInteractive: &NonInteractiveConfig{
 			Subcommand: "exec",
 			OutputFlag: "--json",
@@ -151,14 +326,18 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 	},
 	AgentCursor: {
 		Name:                AgentCursor,
+		Description:         "Cursor agent CLI in force mode",
 		Command:             "cursor-agent",
 		Args:                []string{"-f"}, // Force mode (YOLO equivalent), -p requires prompt
+		YoloArgs:            []string{"-f", "--force"},
 		ProcessNames:        []string{"cursor-agent"},
 		SessionIDEnv:        "", // Uses --resume with chatId directly
 		ResumeFlag:          "--resume",
 		ResumeStyle:         "flag",
 		SupportsHooks:       false, // TODO: verify hooks support
 		SupportsForkSession: false,
+		HooksDir:            ".cursor",
+		InstructionsFile:    "AGENTS.md",
 		NonInteractive: &NonInteractiveConfig{
 			PromptFlag: "-p",
 			OutputFlag: "--output-format json",
@@ -166,6 +345,7 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 	},
 	AgentAuggie: {
 		Name:                AgentAuggie,
+		Description:         "Augment Code Auggie CLI with indexing allowed",
 		Command:             "auggie",
 		Args:                []string{"--allow-indexing"},
 		ProcessNames:        []string{"auggie"},
@@ -174,32 +354,43 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 		ResumeStyle:         "flag",
 		SupportsHooks:       false,
 		SupportsForkSession: false,
+		HooksDir:            ".augment",
+		InstructionsFile:    "AGENTS.md",
 	},
 	AgentAmp: {
 		Name:                AgentAmp,
+		Description:         "Sourcegraph Amp CLI with all tools allowed",
 		Command:             "amp",
 		Args:                []string{"--dangerously-allow-all", "--no-ide"},
+		YoloArgs:            []string{"--dangerously-allow-all"},
 		ProcessNames:        []string{"amp"},
 		SessionIDEnv:        "",
 		ResumeFlag:          "threads continue",
 		ResumeStyle:         "subcommand", // 'amp threads continue <threadId>'
 		SupportsHooks:       false,
 		SupportsForkSession: false,
+		HooksDir:            ".amp",
+		InstructionsFile:    "AGENTS.md",
 	},
 	AgentOpenCode: {
-		Name:    AgentOpenCode,
-		Command: "opencode",
-		Args:    []string{}, // No CLI flags needed, YOLO via OPENCODE_PERMISSION env
+		Name:        AgentOpenCode,
+		Description: "OpenCode multi-model CLI, permissions via OPENCODE_PERMISSION",
+		Command:     "opencode",
+		Args:        []string{}, // No CLI flags needed, YOLO via OPENCODE_PERMISSION env
 		Env: map[string]string{
 			// Auto-approve all tool calls (equivalent to --dangerously-skip-permissions)
 			"OPENCODE_PERMISSION": `{"*":"allow"}`,
 		},
+		YoloEnv:             []string{"OPENCODE_PERMISSION"},
 		ProcessNames:        []string{"opencode", "node", "bun"}, // Runs as Node.js or Bun
 		SessionIDEnv:        "",                           // OpenCode manages sessions internally
-		ResumeFlag:          "",                           // No resume support yet
-		ResumeStyle:         "",
+		ResumeFlag:          "--session",
+		ResumeStyle:         "subcommand", // 'opencode --session <id>', ahead of any flags
 		SupportsHooks:       true,  // Uses .opencode/plugin/gastown.js
 		SupportsForkSession: false,
+		HooksDir:            ".opencode/plugin",
+		InstructionsFile:    "AGENTS.md",
+		ModelFlag:           "--model", // provider/model, e.g. anthropic/claude-sonnet-4
 		NonInteractive: &NonInteractiveConfig{
 			Subcommand: "run",
 			OutputFlag: "--format json",
@@ -207,15 +398,87 @@ var builtinPresets = map[AgentPreset]*AgentPresetInfo{
 	},
 	AgentKimi: {
 		Name:                AgentKimi,
+		Description:         "Moonshot Kimi K2.5 CLI in yolo mode",
 		Command:             "kimi",
 		Args:                []string{"--yolo"}, // YOLO mode for autonomous operation
+		YoloArgs:            []string{"--yolo"},
 		ProcessNames:        []string{"kimi"},   // Kimi CLI binary
+		SandboxHosts:        []string{"api.moonshot.ai", "api.moonshot.cn", "api.kimi.com"},
+		SandboxWritable:     []string{"~/.kimi"},
 		SessionIDEnv:        "KIMI_SESSION_ID",  // Kimi sets this for session tracking
 		ResumeFlag:          "--continue",       // Use --continue to resume sessions
 		ResumeStyle:         "flag",
 		SupportsHooks:       true,               // Supports hooks via .kimi/settings.json
 		SupportsForkSession: false,
-		NonInteractive:      nil, // Kimi is native non-interactive like Claude
+		HooksDir:            ".kimi",
+		InstructionsFile:    "AGENTS.md",
+		ModelFlag:           "--model",
+		MCPConfigFlag:       "--mcp-config-file",
+		OutputJSONFlag:      "--output-format stream-json",
+		RequiresTTY:         true,
+		OutputPatterns: &OutputPatterns{
+			Working: []string{`esc to interrupt`},
+			Done:    []string{`^>\s*$`},
+		},
+		NonInteractive: nil, // Kimi is native non-interactive like Claude
+	},
+	AgentAider: {
+		Name:        AgentAider,
+		Description: "Aider pair programmer with confirmations auto-accepted",
+		Command:     "aider",
+		// Aider reads no instructions file on its own; --read loads the
+		// conventions file (and the shared AGENTS.md) read-only, skipping
+		// either if the worktree lacks it. .aider* files are kept out of
+		// git via .git/info/exclude rather than by editing .gitignore.
+		Args: []string{"--yes-always", "--no-gitignore", "--no-check-update", "--read", "CONVENTIONS.md", "--read", "AGENTS.md"},
+		Env: map[string]string{
+			// Aider keeps one chat history per worktree; naming it
+			// explicitly gives gt a session ID to resume from.
+			"AIDER_CHAT_HISTORY_FILE": ".aider.chat.history.md",
+		},
+		YoloArgs:     []string{"--yes-always"},
+		ProcessNames: []string{"aider", "python", "python3"}, // Python entry point
+		SessionIDEnv: "AIDER_CHAT_HISTORY_FILE",
+		// The "session ID" is the worktree's chat history file:
+		// 'aider --restore-chat-history --chat-history-file <file>'
+		ResumeFlag:          "--restore-chat-history --chat-history-file",
+		ResumeStyle:         "flag",
+		SupportsHooks:       false, // gt prime is nudged in as /run commands
+		SupportsForkSession: false,
+		HooksDir:            ".aider",
+		InstructionsFile:    "CONVENTIONS.md",
+		ModelFlag:           "--model",
+		RequiresTTY:         true,
+		OutputPatterns: &OutputPatterns{
+			Waiting: []string{`\(Y\)es/\(N\)o`},
+			Done:    []string{`^>\s*$`},
+			Error:   []string{`litellm\.\w+Error`},
+		},
+		NonInteractive: &NonInteractiveConfig{
+			PromptFlag: "--message",
+		},
+	},
+	AgentQwen: {
+		Name:                AgentQwen,
+		Description:         "Alibaba Qwen Code CLI in yolo mode",
+		Command:             "qwen",
+		Args:                []string{"--yolo"},
+		YoloArgs:            []string{"--yolo"},
+		ProcessNames:        []string{"qwen", "node"}, // Qwen Code runs as Node.js
+		SandboxHosts:        []string{"dashscope.aliyuncs.com", "dashscope-intl.aliyuncs.com", "chat.qwen.ai"},
+		SandboxWritable:     []string{"~/.qwen"},
+		SessionIDEnv:        "QWEN_SESSION_ID",
+		ResumeFlag:          "resume",
+		ResumeStyle:         "subcommand", // 'qwen resume <session_id> --yolo'
+		SupportsHooks:       false,        // No hook installer yet: gt prime is nudged in
+		SupportsForkSession: false,
+		HooksDir:            ".qwen",
+		InstructionsFile:    "QWEN.md",
+		ModelFlag:           "--model",
+		NonInteractive: &NonInteractiveConfig{
+			PromptFlag: "-p",
+			OutputFlag: "--output-format json",
+		},
 	},
 }
 
@@ -245,9 +508,94 @@ func initRegistryLocked() {
 	for name, preset := range builtinPresets {
 		globalRegistry.Agents[string(name)] = preset
 	}
+	// Then the user's own, which town and rig registries can still override
+	if dir := UserAgentPresetDir(); dir != "" {
+		presets, errs := loadAgentPresetDir(dir)
+		for _, err := range errs {
+			fmt.Fprintf(os.Stderr, "warning: skipping agent preset: %v\n", err)
+		}
+		for _, preset := range presets {
+			globalRegistry.Agents[string(preset.Name)] = preset
+		}
+	}
 	registryInitialized = true
 }
 
+// EnvAgentPresetDir names the environment variable that overrides the
+// directory user agent presets are loaded from.
+const EnvAgentPresetDir = "GT_AGENT_PRESET_DIR"
+
+// UserAgentPresetDir returns the directory of per-user agent preset files,
+// $GT_AGENT_PRESET_DIR or ~/.gastown/agents.d. Empty if there is no home.
+func UserAgentPresetDir() string {
+	if dir := os.Getenv(EnvAgentPresetDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gastown", "agents.d")
+}
+
+// loadAgentPresetDir parses every *.toml file in dir as one agent preset,
+// in file name order. A missing dir yields nothing; files that fail to
+// parse are returned as errors and left out.
+func loadAgentPresetDir(dir string) ([]*AgentPresetInfo, []error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.toml"))
+	if err != nil {
+		return nil, []error{err}
+	}
+	sort.Strings(paths)
+
+	var presets []*AgentPresetInfo
+	var errs []error
+	for _, path := range paths {
+		preset, err := parseAgentPresetFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		presets = append(presets, preset)
+	}
+	return presets, errs
+}
+
+// parseAgentPresetFile reads a TOML agent preset. Keys are those of an
+// agents.json entry (command, args, resume_flag, hooks_dir, session_id_env,
+// ...); name defaults to the file name without .toml.
+func parseAgentPresetFile(path string) (*AgentPresetInfo, error) {
+	var raw map[string]any
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	// Round-trip through JSON so the keys and field rules match agents.json
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var preset AgentPresetInfo
+	if err := dec.Decode(&preset); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if preset.Name == "" {
+		preset.Name = AgentPreset(strings.TrimSuffix(filepath.Base(path), ".toml"))
+	}
+	if preset.Command == "" {
+		return nil, fmt.Errorf("%s: missing command", path)
+	}
+	if err := ValidateResumeTemplate(preset.ResumeTemplate); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := preset.OutputPatterns.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &preset, nil
+}
+
 // ensureRegistry ensures the registry is initialized for read operations.
 func ensureRegistry() {
 	registryMu.Lock()
@@ -277,7 +625,15 @@ func loadAgentRegistryFromPathLocked(path string) error {
 	if err := json.Unmarshal(data, &userRegistry); err != nil {
 		return err
 	}
+	if err := migrateAgentRegistry(&userRegistry, path); err != nil {
+		return err
+	}
 
+	for name, preset := range userRegistry.Agents {
+		if err := preset.OutputPatterns.Validate(); err != nil {
+			return fmt.Errorf("%s: agent %s: %w", path, name, err)
+		}
+	}
 	for name, preset := range userRegistry.Agents {
 		preset.Name = AgentPreset(name)
 		globalRegistry.Agents[name] = preset
@@ -352,11 +708,87 @@ func ListAgentPresets() []string {
 	return names
 }
 
+// Capability names accepted by AgentPresetInfo.Supports.
+const (
+	CapHooks      = "hooks"       // SupportsHooks
+	CapFork       = "fork"        // SupportsForkSession
+	CapResume     = "resume"      // ResumeFlag is set
+	CapMCP        = "mcp"         // MCPConfigFlag is set
+	CapJSONOutput = "json-output" // OutputJSONFlag is set
+)
+
+// AgentCapabilities returns every capability name, in display order.
+func AgentCapabilities() []string {
+	return []string{CapHooks, CapFork, CapResume, CapMCP, CapJSONOutput}
+}
+
+// Supports reports whether the preset has the named capability (one of
+// the Cap* constants). Unknown names are never supported.
+func (info *AgentPresetInfo) Supports(capability string) bool {
+	if info == nil {
+		return false
+	}
+	switch capability {
+	case CapHooks:
+		return info.SupportsHooks
+	case CapFork:
+		return info.SupportsForkSession
+	case CapResume:
+		return info.ResumeFlag != ""
+	case CapMCP:
+		return info.MCPConfigFlag != ""
+	case CapJSONOutput:
+		return info.OutputJSONFlag != ""
+	}
+	return false
+}
+
+// ListAgentPresetsWithCapability returns the sorted names of registered
+// presets that support capability.
+func ListAgentPresetsWithCapability(capability string) []string {
+	ensureRegistry()
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	var names []string
+	for name, info := range globalRegistry.Agents {
+		if info.Supports(capability) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 // DefaultAgentPreset returns the default agent preset (Claude).
 func DefaultAgentPreset() AgentPreset {
 	return AgentClaude
 }
 
+// EnvAgentExtraArgs names the environment variable whose shell-quoted
+// arguments are appended to every agent's launch args (e.g., org-wide
+// policy flags like --telemetry-off).
+const EnvAgentExtraArgs = "GT_AGENT_EXTRA_ARGS"
+
+// ExtraAgentArgs returns the arguments parsed from GT_AGENT_EXTRA_ARGS,
+// re-quoted one per element like Args (which are joined into a shell
+// command line). An unset or empty variable yields nil; a malformed one is
+// reported on stderr and ignored.
+func ExtraAgentArgs() []string {
+	value := os.Getenv(EnvAgentExtraArgs)
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	args, err := SplitShellArgs(value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: ignoring %s: %v\n", EnvAgentExtraArgs, err)
+		return nil
+	}
+	for i, arg := range args {
+		args[i] = ShellQuote(arg)
+	}
+	return args
+}
+
 // RuntimeConfigFromPreset creates a RuntimeConfig from an agent preset.
 // This provides the basic Command/Args/Env; additional fields from AgentPresetInfo
 // can be accessed separately for extended functionality.
@@ -377,9 +809,13 @@ func RuntimeConfigFromPreset(preset AgentPreset) *RuntimeConfig {
 	}
 
 	rc := &RuntimeConfig{
-		Command: info.Command,
-		Args:    append([]string(nil), info.Args...), // Copy to avoid mutation
-		Env:     envCopy,
+		Command:       info.Command,
+		Args:          mergeArgs(info.Args, ExtraAgentArgs()), // Copies; never mutates the preset
+		Env:           envCopy,
+		PrimingPrompt: info.PrimingPrompt,
+	}
+	if info.InstructionsFile != "" {
+		rc.Instructions = &RuntimeInstructionsConfig{File: info.InstructionsFile}
 	}
 
 	// Resolve command path for claude preset (handles alias installations)
@@ -395,30 +831,191 @@ func RuntimeConfigFromPreset(preset AgentPreset) *RuntimeConfig {
 // Returns the full command string including any YOLO/autonomous flags.
 // If sessionID is empty or the agent doesn't support resume, returns empty string.
 func BuildResumeCommand(agentName, sessionID string) string {
-	if sessionID == "" {
+	cmd, err := BuildResumeCommandWithConfig(agentName, sessionID, nil)
+	if err != nil {
 		return ""
 	}
+	return cmd
+}
+
+// BuildResumeCommandWithConfig builds a resume command like BuildResumeCommand,
+// additionally applying per-invocation options from rc (e.g., MCPConfig, JSONOutput).
+// rc may be nil. Returns an error if rc requests an option the agent doesn't support.
+// An rc.ResumeFlag replaces the preset's, and enables resume for a preset without one.
+func BuildResumeCommandWithConfig(agentName, sessionID string, rc *RuntimeConfig) (string, error) {
+	if strings.TrimSpace(sessionID) == "" {
+		return "", nil
+	}
 
 	info := GetAgentPresetByName(agentName)
-	if info == nil || info.ResumeFlag == "" {
-		return ""
+	if info == nil || (info.ResumeFlag == "" && (rc == nil || rc.ResumeFlag == "")) {
+		return "", nil
+	}
+	return buildResumeCommand(info, sessionID, rc)
+}
+
+// buildResumeCommand renders info's resume command for sessionID, applying
+// rc's per-invocation options. info must support resume.
+func buildResumeCommand(info *AgentPresetInfo, sessionID string, rc *RuntimeConfig) (string, error) {
+	agentName := string(info.Name)
+	resumeFlag := info.ResumeFlag
+	if rc != nil && rc.ResumeFlag != "" {
+		resumeFlag = rc.ResumeFlag
+	}
+
+	// Build base command with args, dropping empty ones so they can't
+	// leave doubled spaces in the command line handed to tmux
+	var args []string
+	for _, arg := range info.Args {
+		if strings.TrimSpace(arg) != "" {
+			args = append(args, arg)
+		}
+	}
+	// The agent policy rc was resolved under applies to its resumes too
+	if rc != nil && rc.policy != nil {
+		if rc.policyErr != nil {
+			return "", rc.policyErr
+		}
+		args, _ = rc.policy.rewriteArgs(info, rc.policyAgent, args)
+	}
+
+	if rc != nil && rc.MCPConfig != "" {
+		if info.MCPConfigFlag == "" {
+			return "", fmt.Errorf("%w: %s", ErrMCPConfigUnsupported, agentName)
+		}
+		args = mergeArgs(args, []string{info.MCPConfigFlag, ShellQuote(rc.MCPConfig)})
+	}
+	if rc != nil && rc.JSONOutput {
+		if info.OutputJSONFlag == "" {
+			return "", fmt.Errorf("%w: %s", ErrJSONOutputUnsupported, agentName)
+		}
+		args = mergeArgs(args, strings.Fields(info.OutputJSONFlag))
+	}
+	if rc != nil && rc.Model != "" {
+		if info.ModelFlag == "" {
+			return "", fmt.Errorf("%w: %s", ErrModelUnsupported, agentName)
+		}
+		args = mergeArgs(args, []string{info.ModelFlag, ShellQuote(rc.Model)})
 	}
 
-	// Build base command with args
-	args := append([]string(nil), info.Args...)
+	if info.ResumeTemplate != "" {
+		return renderResumeTemplate(info.ResumeTemplate,
+			resumeTemplateValues(info.Command, strings.Join(args, " "), resumeFlag, sessionID))
+	}
 
 	// Add resume based on style
 	switch info.ResumeStyle {
 	case "subcommand":
 		// e.g., "codex resume <session_id> --yolo"
-		return info.Command + " " + info.ResumeFlag + " " + sessionID + " " + strings.Join(args, " ")
+		return joinCommandWords(append([]string{info.Command, resumeFlag, sessionID}, args...)), nil
 	case "flag":
 		fallthrough
 	default:
 		// e.g., "claude --dangerously-skip-permissions --resume <session_id>"
-		args = append(args, info.ResumeFlag, sessionID)
-		return info.Command + " " + strings.Join(args, " ")
+		return joinCommandWords(append(append([]string{info.Command}, args...), resumeFlag, sessionID)), nil
+	}
+}
+
+// joinCommandWords joins the non-empty words with single spaces.
+func joinCommandWords(words []string) string {
+	var kept []string
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			kept = append(kept, w)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// ErrUnknownResumePlaceholder indicates a ResumeTemplate uses a placeholder
+// outside {command}, {args}, {resumeFlag} and {sessionID}.
+var ErrUnknownResumePlaceholder = errors.New("unknown resume template placeholder")
+
+// resumePlaceholderPattern matches a {name} placeholder in a ResumeTemplate.
+var resumePlaceholderPattern = regexp.MustCompile(`\{\w+\}`)
+
+// ValidateResumeTemplate returns ErrUnknownResumePlaceholder if tmpl uses an
+// unsupported placeholder. An empty template is valid.
+func ValidateResumeTemplate(tmpl string) error {
+	_, err := renderResumeTemplate(tmpl, resumeTemplateValues("", "", "", ""))
+	return err
+}
+
+// resumeTemplateValues maps each supported ResumeTemplate placeholder to its value.
+func resumeTemplateValues(command, args, resumeFlag, sessionID string) map[string]string {
+	return map[string]string{
+		"{command}":    command,
+		"{args}":       args,
+		"{resumeFlag}": resumeFlag,
+		"{sessionID}":  sessionID,
+	}
+}
+
+// renderResumeTemplate substitutes values (keyed by placeholder) into tmpl
+// word by word. Words that render empty, like {args} for an agent without
+// args, are dropped so they leave no double spaces.
+func renderResumeTemplate(tmpl string, values map[string]string) (string, error) {
+	var words []string
+	for _, word := range strings.Fields(tmpl) {
+		var unknown string
+		rendered := resumePlaceholderPattern.ReplaceAllStringFunc(word, func(placeholder string) string {
+			value, ok := values[placeholder]
+			if !ok && unknown == "" {
+				unknown = placeholder
+			}
+			return value
+		})
+		if unknown != "" {
+			return "", fmt.Errorf("%w: %s", ErrUnknownResumePlaceholder, unknown)
+		}
+		if rendered != "" {
+			words = append(words, rendered)
+		}
 	}
+	return strings.Join(words, " "), nil
+}
+
+// BuildResumeCommandFromEnv builds a resume command for the agent's current
+// session, reading the session ID from the preset's SessionIDEnv variable.
+// Returns an error if the agent has no SessionIDEnv, the variable is unset,
+// or the agent doesn't support resume.
+func BuildResumeCommandFromEnv(agentName string) (string, error) {
+	info := GetAgentPresetByName(agentName)
+	if info == nil || info.SessionIDEnv == "" {
+		return "", fmt.Errorf("%w: %s", ErrNoSessionIDEnv, agentName)
+	}
+
+	sessionID := os.Getenv(info.SessionIDEnv)
+	if sessionID == "" {
+		return "", fmt.Errorf("%w: %s", ErrSessionIDUnset, info.SessionIDEnv)
+	}
+
+	cmd, err := BuildResumeCommandWithConfig(agentName, sessionID, nil)
+	if err != nil {
+		return "", err
+	}
+	if cmd == "" {
+		return "", fmt.Errorf("%w: %s", ErrResumeUnsupported, agentName)
+	}
+	return cmd, nil
+}
+
+// BuildForkCommand builds a command that resumes a copy of an agent session,
+// leaving the original untouched. Returns ErrForkUnsupported if the agent
+// lacks SupportsForkSession or resume support.
+func BuildForkCommand(agentName, sessionID string) (string, error) {
+	info := GetAgentPresetByName(agentName)
+	if !info.Supports(CapFork) || !info.Supports(CapResume) {
+		return "", fmt.Errorf("%w: %s", ErrForkUnsupported, agentName)
+	}
+	if strings.TrimSpace(sessionID) == "" {
+		return "", fmt.Errorf("no session ID to fork")
+	}
+	cmd, err := buildResumeCommand(info, sessionID, nil)
+	if err != nil {
+		return "", err
+	}
+	return cmd + " --fork-session", nil
 }
 
 // SupportsSessionResume checks if an agent supports session resumption.
@@ -427,6 +1024,34 @@ func SupportsSessionResume(agentName string) bool {
 	return info != nil && info.ResumeFlag != ""
 }
 
+// LaunchMode is how an agent process is started.
+type LaunchMode string
+
+const (
+	// LaunchTerminal runs the agent attached to a terminal (a tmux pane).
+	LaunchTerminal LaunchMode = "terminal"
+	// LaunchHeadless runs the agent as a detached process with no TTY.
+	LaunchHeadless LaunchMode = "headless"
+)
+
+// ErrRequiresTTY indicates a headless launch was requested for an agent
+// whose preset has RequiresTTY set.
+var ErrRequiresTTY = errors.New("agent requires a TTY")
+
+// ResolveLaunchMode decides how to launch an agent. Agents launch on a
+// terminal unless headless is requested; a headless launch of an agent that
+// requires a TTY is refused with ErrRequiresTTY rather than left to fail
+// inside the agent CLI. Unknown agents are assumed not to need a TTY.
+func ResolveLaunchMode(agentName string, headless bool) (LaunchMode, error) {
+	if !headless {
+		return LaunchTerminal, nil
+	}
+	if info := GetAgentPresetByName(agentName); info != nil && info.RequiresTTY {
+		return "", fmt.Errorf("%w: %s cannot run headless; launch it in a tmux pane", ErrRequiresTTY, agentName)
+	}
+	return LaunchHeadless, nil
+}
+
 // GetSessionIDEnvVar returns the environment variable name for storing session IDs
 // for a given agent. Returns empty string if the agent doesn't use env vars for this.
 func GetSessionIDEnvVar(agentName string) string {
@@ -437,6 +1062,71 @@ func GetSessionIDEnvVar(agentName string) string {
 	return info.SessionIDEnv
 }
 
+// presetForRuntimeConfig returns the preset backing a RuntimeConfig.
+// Matches on the command binary first (RuntimeConfigFromPreset leaves Provider
+// empty), then on Provider. Returns nil for custom commands with no preset.
+func presetForRuntimeConfig(rc *RuntimeConfig) *AgentPresetInfo {
+	if rc == nil {
+		return nil
+	}
+	if rc.Command != "" {
+		base := filepath.Base(rc.Command)
+		if info := GetAgentPresetByName(base); info != nil && filepath.Base(info.Command) == base {
+			return info
+		}
+		ensureRegistry()
+		registryMu.RLock()
+		for _, info := range globalRegistry.Agents {
+			if filepath.Base(info.Command) == base {
+				registryMu.RUnlock()
+				return info
+			}
+		}
+		registryMu.RUnlock()
+	}
+	if rc.Provider != "" {
+		return GetAgentPresetByName(rc.Provider)
+	}
+	return nil
+}
+
+// GetAgentPresetByCommand returns the preset whose CLI binary or process names
+// match command (e.g., a tmux pane_current_command). A binary match wins over a
+// process-name match, and presets are tried in name order so process names
+// shared by several agents ("node") resolve deterministically.
+// Returns nil if no preset matches.
+func GetAgentPresetByCommand(command string) *AgentPresetInfo {
+	base := filepath.Base(strings.TrimSpace(command))
+	if base == "" || base == "." {
+		return nil
+	}
+
+	ensureRegistry()
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(globalRegistry.Agents))
+	for name := range globalRegistry.Agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if info := globalRegistry.Agents[name]; filepath.Base(info.Command) == base {
+			return info
+		}
+	}
+	for _, name := range names {
+		info := globalRegistry.Agents[name]
+		for _, pn := range info.ProcessNames {
+			if pn == base {
+				return info
+			}
+		}
+	}
+	return nil
+}
+
 // GetProcessNames returns the process names used to detect if an agent is running.
 // Used by tmux.IsAgentRunning to check pane_current_command.
 // Returns ["node"] for Claude (default) if agent is not found or has no ProcessNames.
@@ -462,10 +1152,12 @@ func (rc *RuntimeConfig) MergeWithPreset(preset AgentPreset) *RuntimeConfig {
 		return rc
 	}
 
-	result := &RuntimeConfig{
-		Command:       rc.Command,
-		Args:          append([]string(nil), rc.Args...),
-		InitialPrompt: rc.InitialPrompt,
+	result := rc.Clone()
+	if result.Hooks == nil {
+		result.Hooks = &RuntimeHooksConfig{}
+	}
+	if result.Instructions == nil {
+		result.Instructions = &RuntimeInstructionsConfig{}
 	}
 
 	// Apply preset defaults only if not overridden
@@ -475,6 +1167,16 @@ func (rc *RuntimeConfig) MergeWithPreset(preset AgentPreset) *RuntimeConfig {
 	if len(result.Args) == 0 {
 		result.Args = append([]string(nil), info.Args...)
 	}
+	result.Args = mergeArgs(result.Args, ExtraAgentArgs())
+	if result.Hooks.Dir == "" {
+		result.Hooks.Dir = info.HooksDir
+	}
+	if result.Instructions.File == "" {
+		result.Instructions.File = info.InstructionsFile
+	}
+	if result.PrimingPrompt == "" {
+		result.PrimingPrompt = info.PrimingPrompt
+	}
 
 	return result
 }
@@ -488,6 +1190,30 @@ func IsKnownPreset(name string) bool {
 	return ok
 }
 
+// LoadAgentRegistryFile reads the agent registry file at path on its own,
+// without merging it into the loaded presets. A missing file is an empty
+// registry.
+func LoadAgentRegistryFile(path string) (*AgentRegistry, error) {
+	registry := &AgentRegistry{Version: CurrentAgentRegistryVersion, Agents: make(map[string]*AgentPresetInfo)}
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from config
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, registry); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := migrateAgentRegistry(registry, path); err != nil {
+		return nil, err
+	}
+	if registry.Agents == nil {
+		registry.Agents = make(map[string]*AgentPresetInfo)
+	}
+	return registry, nil
+}
+
 // SaveAgentRegistry writes the agent registry to a file.
 func SaveAgentRegistry(path string, registry *AgentRegistry) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
@@ -510,6 +1236,7 @@ func NewExampleAgentRegistry() *AgentRegistry {
 			// Include one example custom agent
 			"my-custom-agent": {
 				Name:         "my-custom-agent",
+				Description:  "Example custom agent",
 				Command:      "my-agent-cli",
 				Args:         []string{"--autonomous", "--no-confirm"},
 				SessionIDEnv: "MY_AGENT_SESSION_ID",
@@ -2,8 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -17,7 +20,7 @@ func isClaudeCmd(cmd string) bool {
 func TestBuiltinPresets(t *testing.T) {
 	t.Parallel()
 	// Ensure all built-in presets are accessible
-	presets := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentKimi}
+	presets := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentKimi, AgentAider, AgentQwen}
 
 	for _, preset := range presets {
 		info := GetAgentPreset(preset)
@@ -50,9 +53,10 @@ func TestGetAgentPresetByName(t *testing.T) {
 		{"cursor", AgentCursor, false},
 		{"auggie", AgentAuggie, false},
 		{"amp", AgentAmp, false},
-		{"aider", "", true},               // Not built-in, can be added via config
+		{"aider", AgentAider, false},       // Built-in Aider pair programmer
 		{"opencode", AgentOpenCode, false}, // Built-in multi-model CLI agent
 		{"kimi", AgentKimi, false},         // Built-in Kimi Code CLI agent
+		{"qwen", AgentQwen, false},         // Built-in Qwen Code CLI agent
 		{"unknown", "", true},
 	}
 
@@ -85,6 +89,7 @@ func TestRuntimeConfigFromPreset(t *testing.T) {
 		{AgentAuggie, "auggie"},
 		{AgentAmp, "amp"},
 		{AgentKimi, "kimi"},
+		{AgentQwen, "qwen"},
 	}
 
 	for _, tt := range tests {
@@ -131,9 +136,10 @@ func TestIsKnownPreset(t *testing.T) {
 		{"cursor", true},
 		{"auggie", true},
 		{"amp", true},
-		{"aider", false},    // Not built-in, can be added via config
+		{"aider", true},     // Built-in Aider pair programmer
 		{"opencode", true},  // Built-in multi-model CLI agent
 		{"kimi", true},      // Built-in Kimi Code CLI agent
+		{"qwen", true},      // Built-in Qwen Code CLI agent
 		{"unknown", false},
 		{"chatgpt", false},
 	}
@@ -157,9 +163,10 @@ func TestLoadAgentRegistry(t *testing.T) {
 		Version: CurrentAgentRegistryVersion,
 		Agents: map[string]*AgentPresetInfo{
 			"my-agent": {
-				Name:    "my-agent",
-				Command: "my-agent-bin",
-				Args:    []string{"--auto"},
+				Name:        "my-agent",
+				Description: "My autonomous agent",
+				Command:     "my-agent-bin",
+				Args:        []string{"--auto"},
 			},
 		},
 	}
@@ -190,6 +197,9 @@ func TestLoadAgentRegistry(t *testing.T) {
 	if myAgent.Command != "my-agent-bin" {
 		t.Errorf("my-agent.Command = %v, want my-agent-bin", myAgent.Command)
 	}
+	if myAgent.Description != "My autonomous agent" {
+		t.Errorf("my-agent.Description = %q, want %q", myAgent.Description, "My autonomous agent")
+	}
 
 	// Check built-ins still accessible
 	claude := GetAgentPresetByName("claude")
@@ -201,6 +211,77 @@ func TestLoadAgentRegistry(t *testing.T) {
 	ResetRegistryForTesting()
 }
 
+func TestOutputPatterns(t *testing.T) {
+	for name, preset := range builtinPresets {
+		if err := preset.OutputPatterns.Validate(); err != nil {
+			t.Errorf("built-in %s: %v", name, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "agents.json")
+	content := `{"version": 1, "agents": {"mine": {"command": "mine-bin", "output_patterns": {"waiting": ["Proceed\\?"], "error": ["(oops"]}}}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ResetRegistryForTesting()
+	t.Cleanup(ResetRegistryForTesting)
+	if err := LoadAgentRegistry(path); err == nil || !strings.Contains(err.Error(), "output_patterns.error") {
+		t.Fatalf("LoadAgentRegistry() = %v, want the invalid error pattern reported", err)
+	}
+	if GetAgentPresetByName("mine") != nil {
+		t.Error("agents from a rejected file must not be registered")
+	}
+}
+
+func TestLoadAgentRegistry_SchemaVersion(t *testing.T) {
+	load := func(t *testing.T, content string) error {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "agents.json")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write test config: %v", err)
+		}
+		ResetRegistryForTesting()
+		t.Cleanup(ResetRegistryForTesting)
+		return LoadAgentRegistry(path)
+	}
+
+	t.Run("current version", func(t *testing.T) {
+		err := load(t, fmt.Sprintf(`{"version": %d, "agents": {"mine": {"command": "mine-bin"}}}`, CurrentAgentRegistryVersion))
+		if err != nil {
+			t.Fatalf("LoadAgentRegistry failed: %v", err)
+		}
+		if p := GetAgentPresetByName("mine"); p == nil || p.Command != "mine-bin" {
+			t.Errorf("custom agent = %+v, want mine-bin", p)
+		}
+	})
+
+	t.Run("too new", func(t *testing.T) {
+		err := load(t, fmt.Sprintf(`{"version": %d, "agents": {"mine": {"command": "mine-bin"}}}`, CurrentAgentRegistryVersion+1))
+		if !errors.Is(err, ErrInvalidVersion) {
+			t.Fatalf("LoadAgentRegistry() = %v, want ErrInvalidVersion", err)
+		}
+		if !strings.Contains(err.Error(), "upgrade gt") {
+			t.Errorf("error = %q, want an upgrade hint", err)
+		}
+		if GetAgentPresetByName("mine") != nil {
+			t.Error("agents from a rejected file must not be registered")
+		}
+	})
+
+	t.Run("unversioned file migrates", func(t *testing.T) {
+		if err := load(t, `{"agents": {"mine": {"command": "mine-bin", "resume_flag": "--resume"}}}`); err != nil {
+			t.Fatalf("LoadAgentRegistry failed: %v", err)
+		}
+		if p := GetAgentPresetByName("mine"); p == nil || p.ResumeFlag != "--resume" {
+			t.Errorf("migrated agent = %+v, want resume flag kept", p)
+		}
+	})
+
+	if len(agentRegistryMigrations) != CurrentAgentRegistryVersion {
+		t.Errorf("%d agent registry migrations for version %d, want one per version", len(agentRegistryMigrations), CurrentAgentRegistryVersion)
+	}
+}
+
 func TestAgentPresetYOLOFlags(t *testing.T) {
 	t.Parallel()
 	// Verify YOLO flags are set correctly for each E2E tested agent
@@ -269,6 +350,185 @@ func TestMergeWithPreset(t *testing.T) {
 	}
 }
 
+func TestRuntimeConfigFromPresetInstructionsFile(t *testing.T) {
+	t.Parallel()
+	for agent, want := range map[AgentPreset]string{
+		AgentClaude: "CLAUDE.md",
+		AgentGemini: "GEMINI.md",
+		AgentKimi:   "AGENTS.md",
+		AgentAider:  "CONVENTIONS.md",
+		AgentQwen:   "QWEN.md",
+	} {
+		if got := RuntimeConfigFromPreset(agent).Resolved().Instructions.File; got != want {
+			t.Errorf("RuntimeConfigFromPreset(%s) instructions file = %q, want %q", agent, got, want)
+		}
+	}
+}
+
+func writeAgentPresetFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoadAgentPresetDir(t *testing.T) {
+	t.Parallel()
+	dir := writeAgentPresetFiles(t, map[string]string{
+		"mycli.toml": `
+command = "mycli"
+args = ["--auto", "--quiet"]
+process_names = ["mycli"]
+session_id_env = "MYCLI_SESSION"
+resume_flag = "resume"
+resume_style = "subcommand"
+hooks_dir = ".mycli"
+
+[env]
+MYCLI_MODE = "yolo"
+`,
+		"renamed.toml": "name = \"other\"\ncommand = \"other-bin\"\n",
+		"typo.toml":    "command = \"x\"\nresume_flg = \"--resume\"\n",
+		"nocmd.toml":   "args = [\"--auto\"]\n",
+		"notes.txt":    "not a preset",
+	})
+
+	presets, errs := loadAgentPresetDir(dir)
+	if len(errs) != 2 {
+		t.Errorf("errors = %v, want the typo and missing-command files rejected", errs)
+	}
+	got := map[string]*AgentPresetInfo{}
+	for _, p := range presets {
+		got[string(p.Name)] = p
+	}
+	if len(got) != 2 || got["other"] == nil || got["other"].Command != "other-bin" {
+		t.Fatalf("presets = %v, want mycli and other", got)
+	}
+	my := got["mycli"]
+	if my == nil {
+		t.Fatal("mycli preset not loaded")
+	}
+	if my.Command != "mycli" || !slices.Equal(my.Args, []string{"--auto", "--quiet"}) ||
+		my.SessionIDEnv != "MYCLI_SESSION" || my.ResumeStyle != "subcommand" ||
+		my.HooksDir != ".mycli" || my.Env["MYCLI_MODE"] != "yolo" {
+		t.Errorf("mycli preset = %+v", my)
+	}
+
+	if presets, errs := loadAgentPresetDir(filepath.Join(dir, "missing")); len(presets) != 0 || len(errs) != 0 {
+		t.Errorf("missing dir: presets %v, errors %v; want neither", presets, errs)
+	}
+}
+
+func TestUserAgentPresetsInRegistry(t *testing.T) {
+	dir := writeAgentPresetFiles(t, map[string]string{
+		"mycli.toml": "command = \"mycli\"\nresume_flag = \"--resume\"\n",
+		"kimi.toml":  "command = \"kimi-nightly\"\n",
+	})
+	t.Setenv(EnvAgentPresetDir, dir)
+	ResetRegistryForTesting()
+	defer ResetRegistryForTesting()
+
+	if p := GetAgentPresetByName("mycli"); p == nil || p.Command != "mycli" {
+		t.Fatalf("GetAgentPresetByName(mycli) = %+v", p)
+	}
+	if !slices.Contains(ListAgentPresets(), "mycli") || !slices.Contains(ListAgentPresets(), "claude") {
+		t.Errorf("ListAgentPresets() = %v, want built-ins plus mycli", ListAgentPresets())
+	}
+	if got := BuildResumeCommand("mycli", "abc"); got != "mycli --resume abc" {
+		t.Errorf("BuildResumeCommand(mycli) = %q", got)
+	}
+	// A user preset replaces the built-in of the same name...
+	if got := GetAgentPresetByName("kimi").Command; got != "kimi-nightly" {
+		t.Errorf("kimi command = %q, want the user preset's", got)
+	}
+
+	// ...and a town registry still overrides the user preset
+	configPath := filepath.Join(t.TempDir(), "agents.json")
+	data, _ := json.Marshal(AgentRegistry{
+		Version: CurrentAgentRegistryVersion,
+		Agents:  map[string]*AgentPresetInfo{"mycli": {Command: "town-mycli"}},
+	})
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadAgentRegistry(configPath); err != nil {
+		t.Fatalf("LoadAgentRegistry: %v", err)
+	}
+	if got := GetAgentPresetByName("mycli").Command; got != "town-mycli" {
+		t.Errorf("mycli command = %q, want the town registry's", got)
+	}
+}
+
+func TestPrimingPromptFromPreset(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "agents.json")
+	registry := AgentRegistry{
+		Version: CurrentAgentRegistryVersion,
+		Agents: map[string]*AgentPresetInfo{
+			"primed": {Name: "primed", Command: "primed-bin", PrimingPrompt: "Resume the molecule on your hook."},
+		},
+	}
+	data, err := json.Marshal(registry)
+	if err != nil {
+		t.Fatalf("failed to marshal test config: %v", err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+	ResetRegistryForTesting()
+	defer ResetRegistryForTesting()
+	if err := LoadAgentRegistry(configPath); err != nil {
+		t.Fatalf("LoadAgentRegistry failed: %v", err)
+	}
+
+	if got := RuntimeConfigFromPreset("primed").PrimingPrompt; got != "Resume the molecule on your hook." {
+		t.Errorf("RuntimeConfigFromPreset(primed).PrimingPrompt = %q", got)
+	}
+	if got := (&RuntimeConfig{}).MergeWithPreset("primed").PrimingPrompt; got != "Resume the molecule on your hook." {
+		t.Errorf("empty config merge PrimingPrompt = %q, want preset's", got)
+	}
+	if got := (&RuntimeConfig{PrimingPrompt: "Check your inbox."}).MergeWithPreset("primed").PrimingPrompt; got != "Check your inbox." {
+		t.Errorf("user PrimingPrompt = %q, want it to override the preset", got)
+	}
+	if got := RuntimeConfigFromPreset(AgentClaude).PrimingPrompt; got != "" {
+		t.Errorf("claude PrimingPrompt = %q, want none by default", got)
+	}
+}
+
+func TestListAgentPresetsWithCapability(t *testing.T) {
+	ResetRegistryForTesting()
+	defer ResetRegistryForTesting()
+
+	tests := []struct {
+		capability string
+		want       []string
+	}{
+		{CapHooks, []string{"claude", "gemini", "kimi", "opencode"}},
+		{CapFork, []string{"claude"}},
+		{CapResume, []string{"aider", "amp", "auggie", "claude", "codex", "cursor", "gemini", "kimi", "opencode", "qwen"}},
+		{CapMCP, []string{"claude", "kimi"}},
+		{"teleport", nil},
+	}
+	for _, tt := range tests {
+		if got := ListAgentPresetsWithCapability(tt.capability); !slices.Equal(got, tt.want) {
+			t.Errorf("ListAgentPresetsWithCapability(%q) = %v, want %v", tt.capability, got, tt.want)
+		}
+	}
+
+	// kimi has hooks but cannot fork
+	kimi := GetAgentPreset(AgentKimi)
+	if !kimi.Supports(CapHooks) || kimi.Supports(CapFork) {
+		t.Errorf("kimi Supports(hooks)=%v Supports(fork)=%v, want true/false", kimi.Supports(CapHooks), kimi.Supports(CapFork))
+	}
+	var nilInfo *AgentPresetInfo
+	if nilInfo.Supports(CapHooks) {
+		t.Error("nil preset should support nothing")
+	}
+}
+
 func TestBuildResumeCommand(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -299,6 +559,13 @@ func TestBuildResumeCommand(t *testing.T) {
 			wantEmpty: false,
 			contains:  []string{"codex", "resume", "codex-sess-789", "--yolo"},
 		},
+		{
+			name:      "opencode subcommand style",
+			agentName: "opencode",
+			sessionID: "ses_abc",
+			wantEmpty: false,
+			contains:  []string{"opencode", "--session", "ses_abc"},
+		},
 		{
 			name:      "empty session ID",
 			agentName: "claude",
@@ -345,6 +612,7 @@ func TestSupportsSessionResume(t *testing.T) {
 		{"cursor", true},
 		{"auggie", true},
 		{"amp", true},
+		{"opencode", true},
 		{"unknown", false},
 	}
 
@@ -366,6 +634,8 @@ func TestGetSessionIDEnvVar(t *testing.T) {
 		{"claude", "CLAUDE_SESSION_ID"},
 		{"gemini", "GEMINI_SESSION_ID"},
 		{"kimi", "KIMI_SESSION_ID"}, // Kimi sets KIMI_SESSION_ID
+		{"aider", "AIDER_CHAT_HISTORY_FILE"},
+		{"qwen", "QWEN_SESSION_ID"},
 		{"codex", ""},    // Codex uses JSONL output instead
 		{"cursor", ""},   // Cursor uses --resume with chatId directly
 		{"auggie", ""},   // Auggie uses --resume directly
@@ -396,6 +666,8 @@ func TestGetProcessNames(t *testing.T) {
 		{"amp", []string{"amp"}},
 		{"opencode", []string{"opencode", "node", "bun"}},
 		{"kimi", []string{"kimi"}},
+		{"aider", []string{"aider", "python", "python3"}},
+		{"qwen", []string{"qwen", "node"}},
 		{"unknown", []string{"node", "claude"}}, // Falls back to Claude's process
 	}
 
@@ -418,7 +690,7 @@ func TestGetProcessNames(t *testing.T) {
 func TestListAgentPresetsMatchesConstants(t *testing.T) {
 	t.Parallel()
 	// Ensure all AgentPreset constants are returned by ListAgentPresets
-	allConstants := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentKimi}
+	allConstants := []AgentPreset{AgentClaude, AgentGemini, AgentCodex, AgentCursor, AgentAuggie, AgentAmp, AgentKimi, AgentAider, AgentQwen}
 	presets := ListAgentPresets()
 
 	// Convert to map for quick lookup
@@ -485,6 +757,11 @@ func TestAgentCommandGeneration(t *testing.T) {
 			wantCommand:  "kimi",
 			wantContains: []string{"--yolo"},
 		},
+		{
+			preset:       AgentQwen,
+			wantCommand:  "qwen",
+			wantContains: []string{"--yolo"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -881,6 +1158,39 @@ func TestKimiProviderDefaults(t *testing.T) {
 	}
 }
 
+func TestGeminiProviderDefaults(t *testing.T) {
+	t.Parallel()
+	rc := normalizeRuntimeConfig(&RuntimeConfig{Provider: "gemini"})
+
+	if rc.Command != "gemini" {
+		t.Errorf("Command = %q, want gemini", rc.Command)
+	}
+	if got := strings.Join(rc.Args, " "); got != "--approval-mode yolo" {
+		t.Errorf("Args = %v, want [--approval-mode yolo]", rc.Args)
+	}
+	if rc.PromptMode != "none" {
+		t.Errorf("PromptMode = %q, want none", rc.PromptMode)
+	}
+	if rc.Session.SessionIDEnv != "GEMINI_SESSION_ID" {
+		t.Errorf("SessionIDEnv = %q, want GEMINI_SESSION_ID", rc.Session.SessionIDEnv)
+	}
+	if rc.Hooks.Provider != "none" || rc.Hooks.Dir != ".gemini" || rc.Hooks.SettingsFile != "settings.json" {
+		t.Errorf("Hooks = %+v, want provider none in .gemini/settings.json", rc.Hooks)
+	}
+	if len(rc.Tmux.ProcessNames) != 1 || rc.Tmux.ProcessNames[0] != "gemini" {
+		t.Errorf("ProcessNames = %v, want [gemini]", rc.Tmux.ProcessNames)
+	}
+	if rc.Tmux.ReadyDelayMs != 8000 {
+		t.Errorf("ReadyDelayMs = %d, want 8000", rc.Tmux.ReadyDelayMs)
+	}
+	if rc.Instructions.File != "GEMINI.md" {
+		t.Errorf("Instructions.File = %q, want GEMINI.md", rc.Instructions.File)
+	}
+	if got := rc.BuildCommandWithPrompt("hello"); got != "gemini --approval-mode yolo" {
+		t.Errorf("BuildCommandWithPrompt = %q, want the prompt left to the startup nudge", got)
+	}
+}
+
 func TestKimiRuntimeConfigFromPreset(t *testing.T) {
 	t.Parallel()
 	rc := RuntimeConfigFromPreset(AgentKimi)
@@ -921,3 +1231,670 @@ func TestKimiBuildResumeCommand(t *testing.T) {
 		t.Errorf("BuildResumeCommand result missing session ID: %q", result)
 	}
 }
+
+func TestAiderAgentPreset(t *testing.T) {
+	t.Parallel()
+	info := GetAgentPreset(AgentAider)
+	if info == nil {
+		t.Fatal("aider preset not found")
+	}
+	if info.Command != "aider" || !slices.Contains(info.Args, "--yes-always") {
+		t.Errorf("aider command = %q %v, want aider --yes-always", info.Command, info.Args)
+	}
+	if info.SupportsHooks {
+		t.Error("aider should not claim hook support; gt prime is nudged in")
+	}
+
+	rc := RuntimeConfigFromPreset(AgentAider)
+	if got := rc.Env[info.SessionIDEnv]; got != ".aider.chat.history.md" {
+		t.Errorf("aider %s = %q, want the worktree chat history", info.SessionIDEnv, got)
+	}
+
+	// Positional args are files to Aider, so the beacon is nudged in
+	if got := (&RuntimeConfig{Provider: "aider"}).Resolved().PromptMode; got != "none" {
+		t.Errorf("aider PromptMode = %q, want none", got)
+	}
+}
+
+func TestAiderBuildResumeCommand(t *testing.T) {
+	t.Parallel()
+	got := BuildResumeCommand("aider", ".aider.chat.history.md")
+	if !strings.HasPrefix(got, "aider --yes-always ") {
+		t.Errorf("BuildResumeCommand(aider) = %q, want the preset args first", got)
+	}
+	if !strings.HasSuffix(got, " --restore-chat-history --chat-history-file .aider.chat.history.md") {
+		t.Errorf("BuildResumeCommand(aider) = %q, want the history restored from the worktree", got)
+	}
+}
+
+func TestQwenAgentPreset(t *testing.T) {
+	t.Parallel()
+	info := GetAgentPreset(AgentQwen)
+	if info == nil {
+		t.Fatal("qwen preset not found")
+	}
+	if info.Command != "qwen" || !slices.Contains(info.Args, "--yolo") {
+		t.Errorf("qwen command = %q %v, want qwen --yolo", info.Command, info.Args)
+	}
+	if info.HooksDir != ".qwen" || info.SupportsHooks {
+		t.Errorf("qwen hooks = %q (supported %v), want .qwen with gt prime nudged in", info.HooksDir, info.SupportsHooks)
+	}
+
+	// Qwen Code is not Gemini: its own binary, env and instructions
+	gemini := GetAgentPreset(AgentGemini)
+	if info.Command == gemini.Command || info.SessionIDEnv == gemini.SessionIDEnv || info.InstructionsFile == gemini.InstructionsFile {
+		t.Errorf("qwen preset duplicates gemini's: %+v", info)
+	}
+
+	if got := defaultRuntimeCommand("qwen"); got != "qwen" {
+		t.Errorf("defaultRuntimeCommand(qwen) = %q, want qwen", got)
+	}
+	if got := defaultPromptMode("qwen"); got != "none" {
+		t.Errorf("defaultPromptMode(qwen) = %q, want none", got)
+	}
+	if got := defaultSessionIDEnv("qwen"); got != "QWEN_SESSION_ID" {
+		t.Errorf("defaultSessionIDEnv(qwen) = %q, want QWEN_SESSION_ID", got)
+	}
+}
+
+func TestQwenBuildResumeCommand(t *testing.T) {
+	t.Parallel()
+	if got, want := BuildResumeCommand("qwen", "sess-42"), "qwen resume sess-42 --yolo"; got != want {
+		t.Errorf("BuildResumeCommand(qwen) = %q, want %q", got, want)
+	}
+}
+
+// Subcommand-style agents take the resume words and session ID right after
+// the command; preset args and per-invocation flags follow the ID.
+func TestBuildResumeCommandSubcommandOrder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		agent string
+		rc    *RuntimeConfig
+		want  string
+	}{
+		{"codex", "codex", nil, "codex resume abc --yolo"},
+		{"codex with model", "codex", &RuntimeConfig{Model: "o3"}, "codex resume abc --yolo -m o3"},
+		{"qwen with model", "qwen", &RuntimeConfig{Model: "qwen3-coder"}, "qwen resume abc --yolo --model qwen3-coder"},
+		{"amp multi-word resume", "amp", nil, "amp threads continue abc --dangerously-allow-all --no-ide"},
+		{"opencode", "opencode", nil, "opencode --session abc"},
+		{"opencode with model", "opencode", &RuntimeConfig{Model: "anthropic/claude-sonnet-4"}, "opencode --session abc --model anthropic/claude-sonnet-4"},
+		{"opencode model needing quotes", "opencode", &RuntimeConfig{Model: "my model"}, "opencode --session abc --model 'my model'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildResumeCommandWithConfig(tt.agent, "abc", tt.rc)
+			if err != nil {
+				t.Fatalf("BuildResumeCommandWithConfig() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildResumeCommandWithConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := BuildResumeCommandWithConfig("opencode", "abc", &RuntimeConfig{JSONOutput: true}); !errors.Is(err, ErrJSONOutputUnsupported) {
+		t.Errorf("opencode JSON output: err = %v, want ErrJSONOutputUnsupported", err)
+	}
+}
+
+func TestBuildCommandWithMCPConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		rc   *RuntimeConfig
+		want string
+	}{
+		{
+			name: "kimi appends flag after preset args",
+			rc:   &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, MCPConfig: "/etc/mcp.json"},
+			want: "kimi --yolo --mcp-config-file /etc/mcp.json",
+		},
+		{
+			name: "path with spaces is quoted",
+			rc:   &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, MCPConfig: "/my dir/mcp.json"},
+			want: "kimi --yolo --mcp-config-file '/my dir/mcp.json'",
+		},
+		{
+			name: "claude full path resolves preset",
+			rc:   &RuntimeConfig{Command: "/home/u/.claude/local/claude", Args: []string{"--dangerously-skip-permissions"}, MCPConfig: "mcp.json"},
+			want: "/home/u/.claude/local/claude --dangerously-skip-permissions --mcp-config mcp.json",
+		},
+		{
+			name: "no MCP config leaves command unchanged",
+			rc:   &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}},
+			want: "kimi --yolo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rc.Validate(); err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+			if got := tt.rc.BuildCommand(); got != tt.want {
+				t.Errorf("BuildCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMCPConfigUnsupportedAgent(t *testing.T) {
+	t.Parallel()
+	rc := RuntimeConfigFromPreset(AgentCodex)
+	rc.MCPConfig = "/etc/mcp.json"
+
+	if err := rc.Validate(); !errors.Is(err, ErrMCPConfigUnsupported) {
+		t.Errorf("Validate() = %v, want ErrMCPConfigUnsupported", err)
+	}
+
+	if _, err := BuildResumeCommandWithConfig("codex", "sess-1", rc); !errors.Is(err, ErrMCPConfigUnsupported) {
+		t.Errorf("BuildResumeCommandWithConfig(codex) error = %v, want ErrMCPConfigUnsupported", err)
+	}
+}
+
+func TestBuildCommandWithJSONOutput(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		rc   *RuntimeConfig
+		want string
+	}{
+		{
+			name: "claude appends stream-json flag",
+			rc:   &RuntimeConfig{Command: "claude", Args: []string{"--dangerously-skip-permissions"}, JSONOutput: true},
+			want: "claude --dangerously-skip-permissions --output-format stream-json",
+		},
+		{
+			name: "existing output format is replaced, not duplicated",
+			rc:   &RuntimeConfig{Command: "kimi", Args: []string{"--yolo", "--output-format", "text"}, JSONOutput: true},
+			want: "kimi --yolo --output-format stream-json",
+		},
+		{
+			name: "combined with MCP config",
+			rc:   &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, MCPConfig: "/etc/mcp.json", JSONOutput: true},
+			want: "kimi --yolo --mcp-config-file /etc/mcp.json --output-format stream-json",
+		},
+		{
+			name: "JSON output off leaves command unchanged",
+			rc:   &RuntimeConfig{Command: "claude", Args: []string{"--dangerously-skip-permissions"}},
+			want: "claude --dangerously-skip-permissions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rc.Validate(); err != nil {
+				t.Fatalf("Validate() = %v, want nil", err)
+			}
+			if got := tt.rc.BuildCommand(); got != tt.want {
+				t.Errorf("BuildCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONOutputUnsupportedAgent(t *testing.T) {
+	t.Parallel()
+	rc := RuntimeConfigFromPreset(AgentCodex)
+	rc.JSONOutput = true
+
+	if err := rc.Validate(); !errors.Is(err, ErrJSONOutputUnsupported) {
+		t.Errorf("Validate() = %v, want ErrJSONOutputUnsupported", err)
+	}
+
+	if _, err := BuildResumeCommandWithConfig("codex", "sess-1", rc); !errors.Is(err, ErrJSONOutputUnsupported) {
+		t.Errorf("BuildResumeCommandWithConfig(codex) error = %v, want ErrJSONOutputUnsupported", err)
+	}
+
+	// Unsupported options are omitted from the command rather than guessed.
+	if got := rc.BuildCommand(); strings.Contains(got, "json") {
+		t.Errorf("BuildCommand() = %q, want no JSON flag", got)
+	}
+}
+
+func TestBuildResumeCommandWithMCPConfig(t *testing.T) {
+	t.Parallel()
+	rc := &RuntimeConfig{MCPConfig: "/etc/mcp.json"}
+	got, err := BuildResumeCommandWithConfig("kimi", "sess-42", rc)
+	if err != nil {
+		t.Fatalf("BuildResumeCommandWithConfig(kimi) error = %v", err)
+	}
+	want := "kimi --yolo --mcp-config-file /etc/mcp.json --continue sess-42"
+	if got != want {
+		t.Errorf("BuildResumeCommandWithConfig(kimi) = %q, want %q", got, want)
+	}
+
+	// Without options it matches BuildResumeCommand exactly.
+	plain, err := BuildResumeCommandWithConfig("kimi", "sess-42", nil)
+	if err != nil {
+		t.Fatalf("BuildResumeCommandWithConfig(kimi, nil) error = %v", err)
+	}
+	if plain != BuildResumeCommand("kimi", "sess-42") {
+		t.Errorf("BuildResumeCommandWithConfig(kimi, nil) = %q, want %q", plain, BuildResumeCommand("kimi", "sess-42"))
+	}
+}
+
+func TestBuildResumeCommandFromEnv(t *testing.T) {
+	t.Setenv("KIMI_SESSION_ID", "kimi-sess-7")
+
+	got, err := BuildResumeCommandFromEnv("kimi")
+	if err != nil {
+		t.Fatalf("BuildResumeCommandFromEnv(kimi) error = %v", err)
+	}
+	want := "kimi --yolo --continue kimi-sess-7"
+	if got != want {
+		t.Errorf("BuildResumeCommandFromEnv(kimi) = %q, want %q", got, want)
+	}
+}
+
+func TestBuildForkCommand(t *testing.T) {
+	got, err := BuildForkCommand("claude", "sess-123")
+	if err != nil {
+		t.Fatalf("BuildForkCommand(claude) error = %v", err)
+	}
+	if !strings.HasSuffix(got, " --resume sess-123 --fork-session") {
+		t.Errorf("BuildForkCommand(claude) = %q, want a --fork-session resume", got)
+	}
+
+	for _, agent := range []string{"kimi", "codex", "nonexistent"} {
+		if _, err := BuildForkCommand(agent, "sess-123"); !errors.Is(err, ErrForkUnsupported) {
+			t.Errorf("BuildForkCommand(%s) error = %v, want ErrForkUnsupported", agent, err)
+		}
+	}
+	if _, err := BuildForkCommand("claude", ""); err == nil {
+		t.Error("BuildForkCommand(claude, \"\") succeeded without a session ID")
+	}
+}
+
+func TestBuildResumeCommandFromEnv_Errors(t *testing.T) {
+	t.Setenv("KIMI_SESSION_ID", "")
+
+	tests := []struct {
+		agent   string
+		wantErr error
+	}{
+		{"kimi", ErrSessionIDUnset},
+		{"codex", ErrNoSessionIDEnv},
+		{"nonexistent", ErrNoSessionIDEnv},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.agent, func(t *testing.T) {
+			got, err := BuildResumeCommandFromEnv(tt.agent)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BuildResumeCommandFromEnv(%s) error = %v, want %v", tt.agent, err, tt.wantErr)
+			}
+			if got != "" {
+				t.Errorf("BuildResumeCommandFromEnv(%s) = %q, want empty", tt.agent, got)
+			}
+		})
+	}
+
+	_, err := BuildResumeCommandFromEnv("kimi")
+	if err == nil || !strings.Contains(err.Error(), "KIMI_SESSION_ID") {
+		t.Errorf("unset-var error %v should name KIMI_SESSION_ID", err)
+	}
+}
+
+func TestBuiltinPresetsHaveDescriptions(t *testing.T) {
+	t.Parallel()
+	for name, info := range builtinPresets {
+		if info.Description == "" {
+			t.Errorf("builtin preset %s has no Description", name)
+		}
+	}
+}
+
+func TestMergeWithPresetHooksAndInstructions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		preset       AgentPreset
+		wantHooksDir string
+		wantInstFile string
+	}{
+		{AgentClaude, ".claude", "CLAUDE.md"},
+		{AgentCodex, ".codex", "AGENTS.md"},
+		{AgentKimi, ".kimi", "AGENTS.md"},
+		{AgentQwen, ".qwen", "QWEN.md"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.preset), func(t *testing.T) {
+			merged := (&RuntimeConfig{}).MergeWithPreset(tt.preset)
+			if merged.Hooks == nil || merged.Hooks.Dir != tt.wantHooksDir {
+				t.Errorf("MergeWithPreset(%s).Hooks = %+v, want Dir %q", tt.preset, merged.Hooks, tt.wantHooksDir)
+			}
+			if merged.Instructions == nil || merged.Instructions.File != tt.wantInstFile {
+				t.Errorf("MergeWithPreset(%s).Instructions = %+v, want File %q", tt.preset, merged.Instructions, tt.wantInstFile)
+			}
+		})
+	}
+}
+
+func TestMergeWithPresetKeepsUserHooksAndInstructions(t *testing.T) {
+	t.Parallel()
+	user := &RuntimeConfig{
+		Hooks:        &RuntimeHooksConfig{Dir: ".custom"},
+		Instructions: &RuntimeInstructionsConfig{File: "ROLE.md"},
+	}
+	merged := user.MergeWithPreset(AgentKimi)
+	if merged.Hooks.Dir != ".custom" || merged.Instructions.File != "ROLE.md" {
+		t.Errorf("user overrides lost: hooks %+v, instructions %+v", merged.Hooks, merged.Instructions)
+	}
+
+	merged.Hooks.Dir = ".mutated"
+	if user.Hooks.Dir != ".custom" {
+		t.Error("MergeWithPreset result aliases the input Hooks config")
+	}
+}
+
+func TestBuiltinPresetsHaveHooksDirAndInstructions(t *testing.T) {
+	t.Parallel()
+	for name, info := range builtinPresets {
+		if info.HooksDir == "" {
+			t.Errorf("builtin preset %s has no HooksDir", name)
+		}
+		if info.InstructionsFile == "" {
+			t.Errorf("builtin preset %s has no InstructionsFile", name)
+		}
+	}
+}
+
+func TestResolveLaunchMode(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		agent    string
+		headless bool
+		want     LaunchMode
+		wantErr  error
+	}{
+		{"claude", false, LaunchTerminal, nil},
+		{"kimi", false, LaunchTerminal, nil},
+		{"claude", true, "", ErrRequiresTTY},
+		{"kimi", true, "", ErrRequiresTTY},
+		{"codex", true, LaunchHeadless, nil},
+		{"unknown-agent", true, LaunchHeadless, nil},
+	}
+	for _, tt := range tests {
+		got, err := ResolveLaunchMode(tt.agent, tt.headless)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ResolveLaunchMode(%q, %v) error = %v, want %v", tt.agent, tt.headless, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ResolveLaunchMode(%q, %v) = %q, want %q", tt.agent, tt.headless, got, tt.want)
+		}
+	}
+}
+
+func TestResolveLaunchModeHeadlessErrorNamesAgent(t *testing.T) {
+	t.Parallel()
+	_, err := ResolveLaunchMode("kimi", true)
+	if err == nil || !strings.Contains(err.Error(), "kimi cannot run headless") {
+		t.Errorf("error = %v, want it to name the agent and explain headless is refused", err)
+	}
+}
+
+func TestInteractivePresetsRequireTTY(t *testing.T) {
+	t.Parallel()
+	for _, name := range []AgentPreset{AgentClaude, AgentKimi} {
+		if !builtinPresets[name].RequiresTTY {
+			t.Errorf("builtin preset %s should require a TTY", name)
+		}
+	}
+}
+
+func TestGetAgentPresetByCommand(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		command string
+		want    AgentPreset // empty means nil
+	}{
+		{"claude", AgentClaude},
+		{"node", AgentClaude}, // shared with opencode; name order picks claude
+		{"bun", AgentOpenCode},
+		{"/usr/local/bin/kimi", AgentKimi},
+		{"cursor-agent", AgentCursor},
+		{"gemini", AgentGemini},
+		{"qwen", AgentQwen},
+		{"bash", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		info := GetAgentPresetByCommand(tt.command)
+		var got AgentPreset
+		if info != nil {
+			got = info.Name
+		}
+		if got != tt.want {
+			t.Errorf("GetAgentPresetByCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestExtraAgentArgs(t *testing.T) {
+	t.Setenv(EnvAgentExtraArgs, "")
+	if got := ExtraAgentArgs(); got != nil {
+		t.Errorf("ExtraAgentArgs() with empty env = %q, want nil", got)
+	}
+	rc := RuntimeConfigFromPreset(AgentKimi)
+	if want := GetAgentPreset(AgentKimi).Args; strings.Join(rc.Args, " ") != strings.Join(want, " ") {
+		t.Errorf("Args with empty env = %q, want preset args %q", rc.Args, want)
+	}
+
+	t.Setenv(EnvAgentExtraArgs, "--telemetry-off")
+	rc = RuntimeConfigFromPreset(AgentKimi)
+	if got, want := rc.BuildCommand(), "kimi --yolo --telemetry-off"; got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+	if len(GetAgentPreset(AgentKimi).Args) != 1 {
+		t.Errorf("preset args mutated: %q", GetAgentPreset(AgentKimi).Args)
+	}
+}
+
+func TestExtraAgentArgs_QuotedValue(t *testing.T) {
+	t.Setenv(EnvAgentExtraArgs, `--note 'hello world' --telemetry-off`)
+
+	want := []string{"--note", "'hello world'", "--telemetry-off"}
+	if got := ExtraAgentArgs(); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("ExtraAgentArgs() = %q, want %q", got, want)
+	}
+	rc := (&RuntimeConfig{Command: "kimi"}).MergeWithPreset(AgentKimi)
+	if got, want := rc.BuildCommand(), "kimi --yolo --note 'hello world' --telemetry-off"; got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestExtraAgentArgs_Malformed(t *testing.T) {
+	t.Setenv(EnvAgentExtraArgs, `--note 'unterminated`)
+	if got := ExtraAgentArgs(); got != nil {
+		t.Errorf("ExtraAgentArgs() with malformed env = %q, want nil", got)
+	}
+}
+
+func TestResumeTemplateMatchesResumeStyles(t *testing.T) {
+	t.Parallel()
+	// Each style's layout, expressed as the equivalent template.
+	styleTemplates := map[string]string{
+		"flag":       "{command} {args} {resumeFlag} {sessionID}",
+		"subcommand": "{command} {resumeFlag} {sessionID} {args}",
+	}
+	rc := &RuntimeConfig{MCPConfig: "/etc/mcp.json"}
+
+	for _, name := range ListAgentPresets() {
+		info := GetAgentPresetByName(name)
+		if info.ResumeFlag == "" {
+			continue
+		}
+		style := info.ResumeStyle
+		if style == "" {
+			style = "flag"
+		}
+		templated := *info
+		templated.ResumeTemplate = styleTemplates[style]
+
+		for _, opts := range []*RuntimeConfig{nil, rc} {
+			want, wantErr := buildResumeCommand(info, "sess-1", opts)
+			got, err := buildResumeCommand(&templated, "sess-1", opts)
+			if fmt.Sprint(err) != fmt.Sprint(wantErr) {
+				t.Errorf("%s: template error = %v, style error = %v", name, err, wantErr)
+				continue
+			}
+			if got != want {
+				t.Errorf("%s: template = %q, style = %q", name, got, want)
+			}
+		}
+	}
+}
+
+func TestResumeTemplateCustomLayout(t *testing.T) {
+	t.Parallel()
+	info := &AgentPresetInfo{
+		Name:           "oddball",
+		Command:        "oddball",
+		ResumeFlag:     "--attach",
+		ResumeTemplate: "{command} session={sessionID} {args} {resumeFlag}",
+	}
+
+	got, err := buildResumeCommand(info, "abc", nil)
+	if err != nil {
+		t.Fatalf("buildResumeCommand() error = %v", err)
+	}
+	// Empty {args} leaves no double space
+	if want := "oddball session=abc --attach"; got != want {
+		t.Errorf("buildResumeCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildResumeCommandWithConfig_ResumeFlagOverride(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		agent string
+		rc    *RuntimeConfig
+		want  string
+	}{
+		{"preset flag by default", "claude", nil, "claude --dangerously-skip-permissions --resume abc"},
+		{"empty override keeps preset", "claude", &RuntimeConfig{}, "claude --dangerously-skip-permissions --resume abc"},
+		{"override replaces flag", "claude", &RuntimeConfig{ResumeFlag: "--continue"}, "claude --dangerously-skip-permissions --continue abc"},
+		{"override in subcommand style", "codex", &RuntimeConfig{ResumeFlag: "continue"}, "codex continue abc --yolo"},
+		{"override in opencode subcommand style", "opencode", &RuntimeConfig{ResumeFlag: "--continue"}, "opencode --continue abc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildResumeCommandWithConfig(tt.agent, "abc", tt.rc)
+			if err != nil {
+				t.Fatalf("BuildResumeCommandWithConfig() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("BuildResumeCommandWithConfig() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildResumeCommandWhitespace(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		style string
+		args  []string
+		want  string
+	}{
+		{"flag style", "flag", []string{"--yolo"}, "mini --yolo --resume abc"},
+		{"flag style, no args", "flag", nil, "mini --resume abc"},
+		{"flag style, empty args", "", []string{"", " "}, "mini --resume abc"},
+		{"subcommand style", "subcommand", []string{"--yolo", "--quiet"}, "mini --resume abc --yolo --quiet"},
+		{"subcommand style, no args", "subcommand", nil, "mini --resume abc"},
+		{"subcommand style, empty args", "subcommand", []string{"", "--yolo", ""}, "mini --resume abc --yolo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := &AgentPresetInfo{Name: "mini", Command: "mini", Args: tt.args, ResumeFlag: "--resume", ResumeStyle: tt.style}
+			got, err := buildResumeCommand(info, "abc", nil)
+			if err != nil {
+				t.Fatalf("buildResumeCommand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildResumeCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for _, sessionID := range []string{"", "  "} {
+		if got := BuildResumeCommand("claude", sessionID); got != "" {
+			t.Errorf("BuildResumeCommand(claude, %q) = %q, want empty", sessionID, got)
+		}
+		if got := BuildResumeCommand("codex", sessionID); got != "" {
+			t.Errorf("BuildResumeCommand(codex, %q) = %q, want empty", sessionID, got)
+		}
+	}
+}
+
+func TestResumeTemplateUnknownPlaceholder(t *testing.T) {
+	t.Parallel()
+	info := &AgentPresetInfo{
+		Name:           "oddball",
+		Command:        "oddball",
+		ResumeFlag:     "--attach",
+		ResumeTemplate: "{command} {resumeFlag} {sessionId}",
+	}
+
+	if _, err := buildResumeCommand(info, "abc", nil); !errors.Is(err, ErrUnknownResumePlaceholder) {
+		t.Errorf("buildResumeCommand() error = %v, want ErrUnknownResumePlaceholder", err)
+	}
+	if err := ValidateResumeTemplate(info.ResumeTemplate); !errors.Is(err, ErrUnknownResumePlaceholder) || !strings.Contains(err.Error(), "{sessionId}") {
+		t.Errorf("ValidateResumeTemplate() = %v, want ErrUnknownResumePlaceholder naming {sessionId}", err)
+	}
+	for _, valid := range []string{"", "{command} {args} {resumeFlag} {sessionID}"} {
+		if err := ValidateResumeTemplate(valid); err != nil {
+			t.Errorf("ValidateResumeTemplate(%q) = %v, want nil", valid, err)
+		}
+	}
+}
+
+func TestBuildHeadlessArgs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		rc   *RuntimeConfig
+		want []string
+	}{
+		{
+			name: "claude takes --print",
+			rc:   &RuntimeConfig{Command: "claude", Args: []string{"--dangerously-skip-permissions"}},
+			want: []string{"claude", "--dangerously-skip-permissions", "--print", "Work slung"},
+		},
+		{
+			name: "kimi is native like claude",
+			rc:   &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, Model: "kimi-k2"},
+			want: []string{"kimi", "--yolo", "--model", "kimi-k2", "--print", "Work slung"},
+		},
+		{
+			name: "subcommand before args",
+			rc:   RuntimeConfigFromPreset(AgentCodex),
+			want: []string{"codex", "exec", "--yolo", "Work slung"},
+		},
+		{
+			name: "prompt flag",
+			rc:   RuntimeConfigFromPreset(AgentGemini),
+			want: []string{"gemini", "--approval-mode", "yolo", "-p", "Work slung"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rc.BuildHeadlessArgs("Work slung")
+			if err != nil {
+				t.Fatalf("BuildHeadlessArgs() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("BuildHeadlessArgs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := (&RuntimeConfig{Provider: "generic", Command: "/opt/bin/homegrown"}).BuildHeadlessArgs("x"); err == nil {
+		t.Error("BuildHeadlessArgs() for a command with no preset succeeded, want an error")
+	}
+}
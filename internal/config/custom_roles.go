@@ -0,0 +1,197 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CustomRole is a custom role (see TownSettings.Roles) resolved for one
+// rig and name. Rig is empty for town-level roles and Name for roles whose
+// session has no {name}.
+type CustomRole struct {
+	Role   string
+	Rig    string
+	Name   string
+	Config *CustomRoleConfig
+}
+
+// LoadCustomRoles returns the custom roles defined in the town's settings.
+// Invalid roles are left out and reported in the returned error, so callers
+// may warn and carry on with the valid ones.
+func LoadCustomRoles(townRoot string) (map[string]*CustomRoleConfig, error) {
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	if len(settings.Roles) == 0 {
+		return nil, nil
+	}
+
+	roles := make(map[string]*CustomRoleConfig, len(settings.Roles))
+	claimed := make(map[string]string) // alias -> role
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(settings.Roles)) {
+		cfg := settings.Roles[name]
+		if err := ValidateCustomRole(name, cfg); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, alias := range cfg.Aliases {
+			if other, ok := claimed[alias]; ok {
+				errs = append(errs, fmt.Errorf("role %q: alias %q is already used by role %q", name, alias, other))
+			}
+			claimed[alias] = name
+		}
+		roles[name] = cfg
+	}
+	return roles, errors.Join(errs...)
+}
+
+// ValidateCustomRole checks a custom role definition.
+func ValidateCustomRole(name string, cfg *CustomRoleConfig) error {
+	if name == "" || strings.ContainsAny(name, "/ ") {
+		return fmt.Errorf("invalid role name %q", name)
+	}
+	if isValidRoleName(name) {
+		return fmt.Errorf("role %q: built-in roles can't be redefined (use roles/%s.toml to override it)", name, name)
+	}
+	if cfg == nil || cfg.Session == "" {
+		return fmt.Errorf("role %q: session template is required", name)
+	}
+	if !strings.Contains(cfg.Session, name) && !strings.Contains(cfg.Session, "{role}") {
+		return fmt.Errorf("role %q: session template %q must contain the role name or {role}", name, cfg.Session)
+	}
+	for _, alias := range cfg.Aliases {
+		if alias == "" || strings.ContainsAny(alias, "/ ") || isValidRoleName(alias) {
+			return fmt.Errorf("role %q: invalid alias %q", name, alias)
+		}
+	}
+	return nil
+}
+
+// LookupCustomRole returns the custom role input names, by role name or
+// alias (case-insensitive).
+func LookupCustomRole(roles map[string]*CustomRoleConfig, input string) (string, bool) {
+	input = strings.ToLower(input)
+	names := slices.Sorted(maps.Keys(roles))
+	for _, name := range names {
+		if strings.ToLower(name) == input {
+			return name, true
+		}
+	}
+	for _, name := range names {
+		for _, alias := range roles[name].Aliases {
+			if strings.ToLower(alias) == input {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// RigScoped reports whether the role runs per rig (its session has {rig}).
+func (c *CustomRoleConfig) RigScoped() bool {
+	return strings.Contains(c.Session, "{rig}")
+}
+
+// Named reports whether the role has several named members (its session
+// has {name}).
+func (c *CustomRoleConfig) Named() bool {
+	return strings.Contains(c.Session, "{name}")
+}
+
+// SessionName returns the role's tmux session name.
+func (r CustomRole) SessionName() string {
+	return ExpandPattern(r.Config.Session, "", r.Rig, r.Name, r.Role)
+}
+
+// WorkDir returns the role's working directory in the town at townRoot.
+func (r CustomRole) WorkDir(townRoot string) string {
+	pattern := r.Config.WorkDir
+	if pattern == "" {
+		pattern = "{town}"
+		if r.Config.RigScoped() {
+			pattern += "/{rig}"
+		}
+		pattern += "/{role}"
+		if r.Config.Named() {
+			pattern += "/{name}"
+		}
+	}
+	return ExpandPattern(pattern, townRoot, r.Rig, r.Name, r.Role)
+}
+
+// RestartCommand returns the role's restart command with its placeholders
+// expanded, or "" if it has none.
+func (r CustomRole) RestartCommand(townRoot string) string {
+	return ExpandPattern(r.Config.RestartCommand, townRoot, r.Rig, r.Name, r.Role)
+}
+
+// Address returns the role's mail-style address: <rig>/<role>[/<name>],
+// or <role>[/<name>] for town-level roles.
+func (r CustomRole) Address() string {
+	parts := []string{r.Role}
+	if r.Rig != "" {
+		parts = append([]string{r.Rig}, parts...)
+	}
+	if r.Name != "" {
+		parts = append(parts, r.Name)
+	}
+	return strings.Join(parts, "/")
+}
+
+// Env returns the identity environment variables for the role's sessions,
+// like AgentEnv does for built-in roles. GT_ROOT is set if townRoot isn't
+// empty.
+func (r CustomRole) Env(townRoot string) map[string]string {
+	env := map[string]string{
+		"GT_ROLE":         r.Address(),
+		"BD_ACTOR":        r.Address(),
+		"GIT_AUTHOR_NAME": r.Address(),
+	}
+	if r.Rig != "" {
+		env["GT_RIG"] = r.Rig
+	}
+	if townRoot != "" {
+		env["GT_ROOT"] = townRoot
+	}
+	return env
+}
+
+// MatchCustomRoleSession returns the custom role whose session template
+// produces sessionName, with the rig and name filled in from it.
+func MatchCustomRoleSession(roles map[string]*CustomRoleConfig, sessionName string) (*CustomRole, bool) {
+	for _, name := range slices.Sorted(maps.Keys(roles)) {
+		cfg := roles[name]
+		re := sessionTemplateRegexp(cfg.Session, name)
+		m := re.FindStringSubmatch(sessionName)
+		if m == nil {
+			continue
+		}
+		role := &CustomRole{Role: name, Config: cfg}
+		if i := re.SubexpIndex("rig"); i > 0 {
+			role.Rig = m[i]
+		}
+		if i := re.SubexpIndex("name"); i > 0 {
+			role.Name = m[i]
+		}
+		return role, true
+	}
+	return nil, false
+}
+
+// sessionTemplateRegexp compiles a session template into a regexp matching
+// the sessions it produces. The first {rig} and {name} are captured.
+func sessionTemplateRegexp(template, role string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(strings.ReplaceAll(template, "{role}", role))
+	for _, group := range []string{"rig", "name"} {
+		placeholder := regexp.QuoteMeta("{" + group + "}")
+		pattern = strings.Replace(pattern, placeholder, "(?P<"+group+">.+?)", 1)
+		pattern = strings.ReplaceAll(pattern, placeholder, ".+?")
+	}
+	return regexp.MustCompile("^" + pattern + "$")
+}
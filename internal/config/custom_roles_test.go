@@ -0,0 +1,133 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func testCustomRoles() map[string]*CustomRoleConfig {
+	return map[string]*CustomRoleConfig{
+		"auditor":   {Session: "gt-{rig}-auditor", Aliases: []string{"aud"}},
+		"librarian": {Session: "hq-librarian", WorkDir: "{town}/library"},
+		"scribe":    {Session: "gt-{rig}-scribe-{name}"},
+	}
+}
+
+func TestLoadCustomRoles(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := NewTownSettings()
+	settings.Roles = map[string]*CustomRoleConfig{
+		"auditor": {Session: "gt-{rig}-auditor", Aliases: []string{"aud"}},
+		"witness": {Session: "gt-{rig}-witness"},
+		"empty":   {},
+		"wide":    {Session: "gt-{rig}-{name}"},
+		"checker": {Session: "gt-{rig}-checker", Aliases: []string{"aud"}},
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+
+	roles, err := LoadCustomRoles(townRoot)
+	if err == nil {
+		t.Fatal("expected errors for the invalid roles")
+	}
+	for _, want := range []string{`"witness"`, `"empty"`, `"wide"`, `alias "aud"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+	if len(roles) != 2 || roles["auditor"] == nil || roles["checker"] == nil {
+		t.Errorf("roles = %v, want only auditor and checker", roles)
+	}
+}
+
+func TestLoadCustomRoles_None(t *testing.T) {
+	roles, err := LoadCustomRoles(t.TempDir())
+	if err != nil || roles != nil {
+		t.Errorf("LoadCustomRoles without settings = %v, %v; want nil, nil", roles, err)
+	}
+}
+
+func TestLookupCustomRole(t *testing.T) {
+	roles := testCustomRoles()
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"auditor", "auditor", true},
+		{"Auditor", "auditor", true},
+		{"aud", "auditor", true},
+		{"librarian", "librarian", true},
+		{"wit", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := LookupCustomRole(roles, tt.input)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("LookupCustomRole(%q) = %q, %v; want %q, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMatchCustomRoleSession(t *testing.T) {
+	roles := testCustomRoles()
+	tests := []struct {
+		session  string
+		wantRole string
+		wantRig  string
+		wantName string
+	}{
+		{"gt-gastown-auditor", "auditor", "gastown", ""},
+		{"gt-my-rig-auditor", "auditor", "my-rig", ""},
+		{"hq-librarian", "librarian", "", ""},
+		{"gt-gastown-scribe-ann", "scribe", "gastown", "ann"},
+		{"gt-gastown-auditor-2", "", "", ""},
+		{"gt-gastown-Toast", "", "", ""},
+	}
+	for _, tt := range tests {
+		role, ok := MatchCustomRoleSession(roles, tt.session)
+		if tt.wantRole == "" {
+			if ok {
+				t.Errorf("MatchCustomRoleSession(%q) = %+v, want no match", tt.session, role)
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("MatchCustomRoleSession(%q) did not match, want %s", tt.session, tt.wantRole)
+			continue
+		}
+		if role.Role != tt.wantRole || role.Rig != tt.wantRig || role.Name != tt.wantName {
+			t.Errorf("MatchCustomRoleSession(%q) = %s/%s/%s, want %s/%s/%s", tt.session,
+				role.Role, role.Rig, role.Name, tt.wantRole, tt.wantRig, tt.wantName)
+		}
+		if got := role.SessionName(); got != tt.session {
+			t.Errorf("SessionName() = %q, want %q", got, tt.session)
+		}
+	}
+}
+
+func TestCustomRole_WorkDirAndAddress(t *testing.T) {
+	roles := testCustomRoles()
+	tests := []struct {
+		role        CustomRole
+		wantWorkDir string
+		wantAddress string
+	}{
+		{CustomRole{Role: "auditor", Rig: "gastown", Config: roles["auditor"]}, "/town/gastown/auditor", "gastown/auditor"},
+		{CustomRole{Role: "librarian", Config: roles["librarian"]}, "/town/library", "librarian"},
+		{CustomRole{Role: "scribe", Rig: "gastown", Name: "ann", Config: roles["scribe"]}, "/town/gastown/scribe/ann", "gastown/scribe/ann"},
+	}
+	for _, tt := range tests {
+		if got := tt.role.WorkDir("/town"); got != tt.wantWorkDir {
+			t.Errorf("%s WorkDir() = %q, want %q", tt.role.Role, got, tt.wantWorkDir)
+		}
+		if got := tt.role.Address(); got != tt.wantAddress {
+			t.Errorf("%s Address() = %q, want %q", tt.role.Role, got, tt.wantAddress)
+		}
+	}
+
+	env := CustomRole{Role: "auditor", Rig: "gastown", Config: roles["auditor"]}.Env("/town")
+	if env["GT_ROLE"] != "gastown/auditor" || env["GT_RIG"] != "gastown" || env["GT_ROOT"] != "/town" {
+		t.Errorf("Env() = %v", env)
+	}
+}
@@ -35,6 +35,10 @@ type AgentEnvConfig struct {
 	// BeadsNoDaemon sets BEADS_NO_DAEMON=1 if true
 	// Used for polecats that should bypass the beads daemon
 	BeadsNoDaemon bool
+
+	// Model is the model override for the agent (gt sling --model).
+	// Sets GT_MODEL, which startup commands pass to the agent's ModelFlag.
+	Model string
 }
 
 // AgentEnv returns all environment variables for an agent based on the config.
@@ -113,6 +117,10 @@ func AgentEnv(cfg AgentEnvConfig) map[string]string {
 		env["GT_SESSION_ID_ENV"] = cfg.SessionIDEnv
 	}
 
+	if cfg.Model != "" {
+		env["GT_MODEL"] = cfg.Model
+	}
+
 	return env
 }
 
@@ -152,6 +160,65 @@ func ShellQuote(s string) string {
 	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
 }
 
+// SplitShellArgs splits s into arguments the way a POSIX shell would,
+// honoring single quotes, double quotes and backslash escapes. No expansion
+// is performed. It is the inverse of joining ShellQuote'd arguments.
+func SplitShellArgs(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune // the open quote character, or 0
+		escaped bool
+	)
+	for _, c := range s {
+		switch {
+		case escaped:
+			// Inside double quotes a backslash only escapes \ " $ `
+			if quote == '"' && c != '\\' && c != '"' && c != '$' && c != '`' {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\\':
+			escaped, inArg = true, true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				current.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inArg = c, true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(c)
+			inArg = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", s)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in %q", quote, s)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
 // ExportPrefix builds an export statement prefix for shell commands.
 // Returns a string like "export GT_ROLE=mayor BD_ACTOR=mayor && "
 // The keys are sorted for deterministic output.
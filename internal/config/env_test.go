@@ -1,6 +1,7 @@
 package config
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -270,6 +271,43 @@ func TestShellQuote(t *testing.T) {
 	}
 }
 
+func TestSplitShellArgs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"--verbose", []string{"--verbose"}},
+		{"  --model  opus ", []string{"--model", "opus"}},
+		{`--note 'hello world' --tag "a b"`, []string{"--note", "hello world", "--tag", "a b"}},
+		{`--path a\ b`, []string{"--path", "a b"}},
+		{`--empty ''`, []string{"--empty", ""}},
+		{`"it's" 'say "hi"'`, []string{"it's", `say "hi"`}},
+	}
+
+	for _, tt := range tests {
+		got, err := SplitShellArgs(tt.input)
+		if err != nil {
+			t.Errorf("SplitShellArgs(%q) error = %v", tt.input, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("SplitShellArgs(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestSplitShellArgs_Errors(t *testing.T) {
+	t.Parallel()
+	for _, input := range []string{`--note 'open`, `--note "open`, `trailing\`} {
+		if got, err := SplitShellArgs(input); err == nil {
+			t.Errorf("SplitShellArgs(%q) = %q, want error", input, got)
+		}
+	}
+}
+
 func TestExportPrefix(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
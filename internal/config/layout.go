@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Pane layout split directions.
+const (
+	SplitRight = "right" // Side by side
+	SplitBelow = "below" // Stacked
+)
+
+// layoutArrangements are tmux's preset layouts, accepted as
+// PaneLayout.Arrange.
+var layoutArrangements = []string{"even-horizontal", "even-vertical", "main-horizontal", "main-vertical", "tiled"}
+
+// PaneLayout describes extra panes to open beside a session's agent pane,
+// so attaching to a workspace gives the same multi-pane window every time.
+// Layouts are defined in town settings under "layouts" and applied with
+// gt layout apply, or by gt crew at for the role's layout.
+type PaneLayout struct {
+	// Panes are split off in order. The agent pane is pane 0, and the
+	// first pane listed here is pane 1.
+	Panes []LayoutPane `json:"panes"`
+
+	// Arrange is a tmux preset layout applied once all panes exist
+	// ("main-vertical", "tiled", ...). Default: keep the split sizes.
+	Arrange string `json:"arrange,omitempty"`
+
+	// Focus is the pane left selected. Default: 0, the agent pane.
+	Focus int `json:"focus,omitempty"`
+}
+
+// LayoutPane is one pane of a PaneLayout.
+type LayoutPane struct {
+	// Split is where the pane opens relative to the pane it splits:
+	// "right" or "below".
+	Split string `json:"split"`
+
+	// Of is the pane to split, by index. Default: 0, the agent pane.
+	Of int `json:"of,omitempty"`
+
+	// Size is the share of the split pane it takes, in percent (1-99).
+	// Default: half.
+	Size int `json:"size,omitempty"`
+
+	// Command runs in the pane instead of a shell. Placeholders:
+	// {session}, {workdir} (the agent pane's directory), {town} and {agent}
+	// (the session's agent address, e.g. gastown/crew/max).
+	// Example: "gt logs -f --agent {agent}"
+	Command string `json:"command,omitempty"`
+}
+
+// Validate checks the layout's panes refer to panes that exist when they
+// are split.
+func (l *PaneLayout) Validate() error {
+	if l == nil || len(l.Panes) == 0 {
+		return fmt.Errorf("layout has no panes")
+	}
+	for i, p := range l.Panes {
+		n := i + 1
+		if p.Split != SplitRight && p.Split != SplitBelow {
+			return fmt.Errorf("pane %d: invalid split %q (want %s or %s)", n, p.Split, SplitRight, SplitBelow)
+		}
+		if p.Of < 0 || p.Of >= n {
+			return fmt.Errorf("pane %d: of %d is not an earlier pane (0-%d)", n, p.Of, n-1)
+		}
+		if p.Size < 0 || p.Size > 99 {
+			return fmt.Errorf("pane %d: size %d is not a percentage (1-99)", n, p.Size)
+		}
+	}
+	if l.Arrange != "" && !slices.Contains(layoutArrangements, l.Arrange) {
+		return fmt.Errorf("invalid arrange %q (want one of %s)", l.Arrange, strings.Join(layoutArrangements, ", "))
+	}
+	if l.Focus < 0 || l.Focus > len(l.Panes) {
+		return fmt.Errorf("focus %d is not a pane (0-%d)", l.Focus, len(l.Panes))
+	}
+	return nil
+}
+
+// RoleLayout returns the name of the layout for role's sessions, or "".
+func (s *TownSettings) RoleLayout(role string) string {
+	if s == nil {
+		return ""
+	}
+	return s.RoleLayouts[role]
+}
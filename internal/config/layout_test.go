@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPaneLayoutValidate(t *testing.T) {
+	valid := &PaneLayout{
+		Panes: []LayoutPane{
+			{Split: SplitRight, Size: 40, Command: "gt logs -f --agent {agent}"},
+			{Split: SplitBelow, Of: 1},
+		},
+		Arrange: "main-vertical",
+		Focus:   2,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		layout *PaneLayout
+		want   string
+	}{
+		{"no panes", &PaneLayout{}, "no panes"},
+		{"bad split", &PaneLayout{Panes: []LayoutPane{{Split: "left"}}}, "invalid split"},
+		{"later pane", &PaneLayout{Panes: []LayoutPane{{Split: SplitRight, Of: 1}}}, "not an earlier pane"},
+		{"size", &PaneLayout{Panes: []LayoutPane{{Split: SplitRight, Size: 100}}}, "percentage"},
+		{"arrange", &PaneLayout{Panes: []LayoutPane{{Split: SplitRight}}, Arrange: "grid"}, "invalid arrange"},
+		{"focus", &PaneLayout{Panes: []LayoutPane{{Split: SplitRight}}, Focus: 2}, "not a pane"},
+	}
+	for _, tt := range tests {
+		if err := tt.layout.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Validate() = %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidateTownConfigLayouts(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestFile(t, TownSettingsPath(townRoot), `{
+  "type": "town-settings", "version": 1,
+  "layouts": {
+    "dev": {"panes": [{"split": "right"}]},
+    "broken": {"panes": [{"split": "below", "of": 3}]}
+  },
+  "role_layouts": {"crew": "dev", "polecat": "missing"}
+}`)
+	issues, _ := ValidateTownConfig(townRoot)
+	got := make(map[string]bool)
+	for _, i := range issues {
+		got[i.Field] = true
+	}
+	if !got["layouts.broken"] || !got["role_layouts.polecat"] || len(issues) != 2 {
+		t.Errorf("issues = %v, want layouts.broken and role_layouts.polecat", issues)
+	}
+}
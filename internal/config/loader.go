@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/store"
 )
 
 var (
@@ -65,7 +66,7 @@ func SaveTownConfig(path string, config *TownConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := store.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -109,7 +110,7 @@ func SaveRigsConfig(path string, config *RigsConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	if err := store.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -178,7 +179,7 @@ func SaveRigConfig(path string, config *RigConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -278,6 +279,9 @@ func LoadRigSettings(path string) (*RigSettings, error) {
 	if err := json.Unmarshal(data, &settings); err != nil {
 		return nil, fmt.Errorf("parsing settings: %w", err)
 	}
+	if err := migrateRigSettings(&settings, path); err != nil {
+		return nil, err
+	}
 
 	if err := validateRigSettings(&settings); err != nil {
 		return nil, err
@@ -301,7 +305,7 @@ func SaveRigSettings(path string, settings *RigSettings) error {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -345,7 +349,7 @@ func SaveMayorConfig(path string, config *MayorConfig) error {
 		return fmt.Errorf("encoding config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing config: %w", err)
 	}
 
@@ -413,7 +417,7 @@ func SaveDaemonPatrolConfig(path string, config *DaemonPatrolConfig) error {
 		return fmt.Errorf("encoding daemon patrol config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: config files don't contain secrets
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing daemon patrol config: %w", err)
 	}
 
@@ -479,7 +483,7 @@ func SaveAccountsConfig(path string, config *AccountsConfig) error {
 		return fmt.Errorf("encoding accounts config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: accounts config doesn't contain sensitive credentials
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing accounts config: %w", err)
 	}
 
@@ -626,7 +630,7 @@ func SaveMessagingConfig(path string, config *MessagingConfig) error {
 		return fmt.Errorf("encoding messaging config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: messaging config doesn't contain secrets
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing messaging config: %w", err)
 	}
 
@@ -754,6 +758,9 @@ func LoadOrCreateTownSettings(path string) (*TownSettings, error) {
 	if err := json.Unmarshal(data, &settings); err != nil {
 		return nil, err
 	}
+	if err := migrateTownSettings(&settings, path); err != nil {
+		return nil, err
+	}
 	return &settings, nil
 }
 
@@ -775,7 +782,7 @@ func SaveTownSettings(path string, settings *TownSettings) error {
 		return fmt.Errorf("encoding settings: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: settings files don't contain secrets
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing settings: %w", err)
 	}
 
@@ -802,18 +809,18 @@ func ResolveAgentConfig(townRoot, rigPath string) *RuntimeConfig {
 		rigSettings = nil
 	}
 
-	// Backwards compatibility: if Runtime is set directly, use it
-	if rigSettings != nil && rigSettings.Runtime != nil {
-		rc := rigSettings.Runtime
-		return fillRuntimeDefaults(rc)
-	}
-
-	// Load town settings for agent lookup
+	// Load town settings for agent lookup and the agent policy
 	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
 	if err != nil {
 		townSettings = NewTownSettings()
 	}
 
+	// Backwards compatibility: if Runtime is set directly, use it
+	if rigSettings != nil && rigSettings.Runtime != nil {
+		rc := rigSettings.Runtime
+		return withAgentPolicy(fillRuntimeDefaults(rc), "", townSettings, rigSettings)
+	}
+
 	// Load custom agent registry if it exists
 	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
 
@@ -844,18 +851,18 @@ func ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride string) (*R
 		rigSettings = nil
 	}
 
-	// Backwards compatibility: if Runtime is set directly, use it (but still report agentOverride if present)
-	if rigSettings != nil && rigSettings.Runtime != nil && agentOverride == "" {
-		rc := rigSettings.Runtime
-		return fillRuntimeDefaults(rc), "", nil
-	}
-
-	// Load town settings for agent lookup
+	// Load town settings for agent lookup and the agent policy
 	townSettings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
 	if err != nil {
 		townSettings = NewTownSettings()
 	}
 
+	// Backwards compatibility: if Runtime is set directly, use it (but still report agentOverride if present)
+	if rigSettings != nil && rigSettings.Runtime != nil && agentOverride == "" {
+		rc := rigSettings.Runtime
+		return withAgentPolicy(fillRuntimeDefaults(rc), "", townSettings, rigSettings), "", nil
+	}
+
 	// Load custom agent registry if it exists
 	_ = LoadAgentRegistry(DefaultAgentRegistryPath(townRoot))
 
@@ -879,18 +886,18 @@ func ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride string) (*R
 		// Check rig-level custom agents first
 		if rigSettings != nil && rigSettings.Agents != nil {
 			if custom, ok := rigSettings.Agents[agentName]; ok && custom != nil {
-				return fillRuntimeDefaults(custom), agentName, nil
+				return withAgentPolicy(fillRuntimeDefaults(custom), agentName, townSettings, rigSettings), agentName, nil
 			}
 		}
 		// Then check town-level custom agents
 		if townSettings.Agents != nil {
 			if custom, ok := townSettings.Agents[agentName]; ok && custom != nil {
-				return fillRuntimeDefaults(custom), agentName, nil
+				return withAgentPolicy(fillRuntimeDefaults(custom), agentName, townSettings, rigSettings), agentName, nil
 			}
 		}
 		// Then check built-in presets
 		if preset := GetAgentPresetByName(agentName); preset != nil {
-			return RuntimeConfigFromPreset(AgentPreset(agentName)), agentName, nil
+			return withAgentPolicy(RuntimeConfigFromPreset(AgentPreset(agentName)), agentName, townSettings, rigSettings), agentName, nil
 		}
 		return nil, "", fmt.Errorf("agent '%s' not found", agentName)
 	}
@@ -1048,30 +1055,30 @@ func ResolveRoleAgentName(role, townRoot, rigPath string) (agentName string, isR
 	return "claude", false
 }
 
-// lookupAgentConfig looks up an agent by name.
+// lookupAgentConfig looks up an agent by name, under the town's and rig's agent policy.
 // Checks rig-level custom agents first, then town's custom agents, then built-in presets from agents.go.
 func lookupAgentConfig(name string, townSettings *TownSettings, rigSettings *RigSettings) *RuntimeConfig {
 	// First check rig's custom agents (NEW - fix for rig-level agent support)
 	if rigSettings != nil && rigSettings.Agents != nil {
 		if custom, ok := rigSettings.Agents[name]; ok && custom != nil {
-			return fillRuntimeDefaults(custom)
+			return withAgentPolicy(fillRuntimeDefaults(custom), name, townSettings, rigSettings)
 		}
 	}
 
 	// Then check town's custom agents (existing)
 	if townSettings != nil && townSettings.Agents != nil {
 		if custom, ok := townSettings.Agents[name]; ok && custom != nil {
-			return fillRuntimeDefaults(custom)
+			return withAgentPolicy(fillRuntimeDefaults(custom), name, townSettings, rigSettings)
 		}
 	}
 
 	// Check built-in presets from agents.go
 	if preset := GetAgentPresetByName(name); preset != nil {
-		return RuntimeConfigFromPreset(AgentPreset(name))
+		return withAgentPolicy(RuntimeConfigFromPreset(AgentPreset(name)), name, townSettings, rigSettings)
 	}
 
 	// Fallback to claude defaults
-	return DefaultRuntimeConfig()
+	return withAgentPolicy(DefaultRuntimeConfig(), "", townSettings, rigSettings)
 }
 
 // fillRuntimeDefaults fills in default values for empty RuntimeConfig fields.
@@ -1089,61 +1096,7 @@ func fillRuntimeDefaults(rc *RuntimeConfig) *RuntimeConfig {
 		return DefaultRuntimeConfig()
 	}
 
-	// Create result with scalar fields (strings are immutable in Go)
-	result := &RuntimeConfig{
-		Provider:      rc.Provider,
-		Command:       rc.Command,
-		InitialPrompt: rc.InitialPrompt,
-		PromptMode:    rc.PromptMode,
-	}
-
-	// Deep copy Args slice to avoid sharing backing array
-	if rc.Args != nil {
-		result.Args = make([]string, len(rc.Args))
-		copy(result.Args, rc.Args)
-	}
-
-	// Deep copy Env map
-	if len(rc.Env) > 0 {
-		result.Env = make(map[string]string, len(rc.Env))
-		for k, v := range rc.Env {
-			result.Env[k] = v
-		}
-	}
-
-	// Deep copy nested structs (nil checks prevent panic on access)
-	if rc.Session != nil {
-		result.Session = &RuntimeSessionConfig{
-			SessionIDEnv: rc.Session.SessionIDEnv,
-			ConfigDirEnv: rc.Session.ConfigDirEnv,
-		}
-	}
-
-	if rc.Hooks != nil {
-		result.Hooks = &RuntimeHooksConfig{
-			Provider:     rc.Hooks.Provider,
-			Dir:          rc.Hooks.Dir,
-			SettingsFile: rc.Hooks.SettingsFile,
-		}
-	}
-
-	if rc.Tmux != nil {
-		result.Tmux = &RuntimeTmuxConfig{
-			ReadyPromptPrefix: rc.Tmux.ReadyPromptPrefix,
-			ReadyDelayMs:      rc.Tmux.ReadyDelayMs,
-		}
-		// Deep copy ProcessNames slice
-		if rc.Tmux.ProcessNames != nil {
-			result.Tmux.ProcessNames = make([]string, len(rc.Tmux.ProcessNames))
-			copy(result.Tmux.ProcessNames, rc.Tmux.ProcessNames)
-		}
-	}
-
-	if rc.Instructions != nil {
-		result.Instructions = &RuntimeInstructionsConfig{
-			File: rc.Instructions.File,
-		}
-	}
+	result := rc.Clone()
 
 	// Apply defaults for required fields
 	if result.Command == "" {
@@ -1184,6 +1137,9 @@ func GetRuntimeCommandWithAgentOverride(rigPath, agentOverride string) (string,
 		if resolveErr != nil {
 			return "", resolveErr
 		}
+		if err := rc.Validate(); err != nil {
+			return "", err
+		}
 		return rc.BuildCommand(), nil
 	}
 
@@ -1192,6 +1148,9 @@ func GetRuntimeCommandWithAgentOverride(rigPath, agentOverride string) (string,
 	if err != nil {
 		return "", err
 	}
+	if err := rc.Validate(); err != nil {
+		return "", err
+	}
 	return rc.BuildCommand(), nil
 }
 
@@ -1212,21 +1171,32 @@ func GetRuntimeCommandWithPrompt(rigPath, prompt string) string {
 // GetRuntimeCommandWithPromptAndAgentOverride returns the full command with an initial prompt,
 // using agentOverride if non-empty.
 func GetRuntimeCommandWithPromptAndAgentOverride(rigPath, prompt, agentOverride string) (string, error) {
+	return GetRuntimeCommandWithPromptAndOverrides(rigPath, prompt, agentOverride, "")
+}
+
+// GetRuntimeCommandWithPromptAndOverrides is like GetRuntimeCommandWithPromptAndAgentOverride,
+// additionally selecting model if non-empty.
+func GetRuntimeCommandWithPromptAndOverrides(rigPath, prompt, agentOverride, model string) (string, error) {
+	var rc *RuntimeConfig
 	if rigPath == "" {
 		townRoot, err := findTownRootFromCwd()
 		if err != nil {
-			return DefaultRuntimeConfig().BuildCommandWithPrompt(prompt), nil
+			rc = DefaultRuntimeConfig()
+		} else if rc, _, err = ResolveAgentConfigWithOverride(townRoot, "", agentOverride); err != nil {
+			return "", err
 		}
-		rc, _, resolveErr := ResolveAgentConfigWithOverride(townRoot, "", agentOverride)
-		if resolveErr != nil {
-			return "", resolveErr
+	} else {
+		townRoot := filepath.Dir(rigPath)
+		var err error
+		if rc, _, err = ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride); err != nil {
+			return "", err
 		}
-		return rc.BuildCommandWithPrompt(prompt), nil
 	}
-
-	townRoot := filepath.Dir(rigPath)
-	rc, _, err := ResolveAgentConfigWithOverride(townRoot, rigPath, agentOverride)
-	if err != nil {
+	if model != "" {
+		rc = rc.Clone()
+		rc.Model = model
+	}
+	if err := rc.Validate(); err != nil {
 		return "", err
 	}
 	return rc.BuildCommandWithPrompt(prompt), nil
@@ -1301,6 +1271,7 @@ func extractSimpleRole(gtRole string) string {
 // If envVars contains GT_ROLE, the function uses role-based agent resolution
 // (ResolveRoleAgentConfig) to select the appropriate agent for the role.
 // This enables per-role model selection via role_agents in settings.
+// If it contains GT_MODEL, that model is passed with the agent's ModelFlag.
 func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) string {
 	var rc *RuntimeConfig
 	var townRoot string
@@ -1371,10 +1342,16 @@ func BuildStartupCommand(envVars map[string]string, rigPath, prompt string) stri
 	}
 
 	// Add runtime command
+	// rc.Env is in the exports; don't repeat it in the agent command
+	launch := rc.Clone()
+	launch.Env = nil
+	if model := envVars["GT_MODEL"]; model != "" {
+		launch.Model = model
+	}
 	if prompt != "" {
-		cmd += rc.BuildCommandWithPrompt(prompt)
+		cmd += launch.BuildCommandWithPrompt(prompt)
 	} else {
-		cmd += rc.BuildCommand()
+		cmd += launch.BuildCommand()
 	}
 
 	return cmd
@@ -1403,6 +1380,9 @@ func PrependEnv(command string, envVars map[string]string) string {
 //  1. agentOverride (explicit override)
 //  2. role_agents[GT_ROLE] (if GT_ROLE is in envVars)
 //  3. Default agent resolution (rig's Agent → town's DefaultAgent → "claude")
+//
+// A GT_MODEL in envVars overrides the agent's model; ErrModelUnsupported is
+// returned if the agent has no ModelFlag.
 func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, prompt, agentOverride string) (string, error) {
 	var rc *RuntimeConfig
 	var townRoot string
@@ -1457,6 +1437,16 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 	if rc.Session != nil && rc.Session.SessionIDEnv != "" {
 		resolvedEnv["GT_SESSION_ID_ENV"] = rc.Session.SessionIDEnv
 	}
+	// GT_MODEL selects the model; it stays in the exports so handoff and
+	// resume can preserve it
+	if model := envVars["GT_MODEL"]; model != "" {
+		rc = rc.Clone()
+		rc.Model = model
+	}
+	if err := rc.Validate(); err != nil {
+		return "", err
+	}
+
 	// Record agent override so handoff can preserve it
 	if agentOverride != "" {
 		resolvedEnv["GT_AGENT"] = agentOverride
@@ -1482,10 +1472,13 @@ func BuildStartupCommandWithAgentOverride(envVars map[string]string, rigPath, pr
 		cmd = "exec env " + strings.Join(exports, " ") + " "
 	}
 
+	// rc.Env is in the exports; don't repeat it in the agent command
+	launch := rc.Clone()
+	launch.Env = nil
 	if prompt != "" {
-		cmd += rc.BuildCommandWithPrompt(prompt)
+		cmd += launch.BuildCommandWithPrompt(prompt)
 	} else {
-		cmd += rc.BuildCommand()
+		cmd += launch.BuildCommand()
 	}
 
 	return cmd, nil
@@ -1682,7 +1675,7 @@ func SaveEscalationConfig(path string, config *EscalationConfig) error {
 		return fmt.Errorf("encoding escalation config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec // G306: escalation config doesn't contain secrets
+	if err := store.WriteFile(path, data, 0644); err != nil {
 		return fmt.Errorf("writing escalation config: %w", err)
 	}
 
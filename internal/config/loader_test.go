@@ -1,15 +1,19 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/constants"
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/secrets"
 )
 
 // skipIfAgentBinaryMissing skips the test if any of the specified agent binaries
@@ -841,6 +845,79 @@ func TestRuntimeConfigDefaults(t *testing.T) {
 	}
 }
 
+func TestResolveInstructions(t *testing.T) {
+	t.Parallel()
+	write := func(t *testing.T, dir, file string) {
+		t.Helper()
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("# instructions\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	claude := &RuntimeConfig{Provider: "claude"}
+
+	t.Run("first match", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, "CLAUDE.md")
+		write(t, dir, "AGENTS.md")
+		got, err := ResolveInstructions(claude, dir)
+		if err != nil || got != filepath.Join(dir, "CLAUDE.md") {
+			t.Errorf("ResolveInstructions() = %q, %v; want CLAUDE.md", got, err)
+		}
+	})
+
+	t.Run("fallback match", func(t *testing.T) {
+		dir := t.TempDir()
+		write(t, dir, GastownInstructionsFile)
+		got, err := ResolveInstructions(claude, dir)
+		if err != nil || got != filepath.Join(dir, GastownInstructionsFile) {
+			t.Errorf("ResolveInstructions() = %q, %v; want %s", got, err, GastownInstructionsFile)
+		}
+
+		write(t, dir, SharedInstructionsFile)
+		got, err = ResolveInstructions(claude, dir)
+		if err != nil || got != filepath.Join(dir, SharedInstructionsFile) {
+			t.Errorf("ResolveInstructions() = %q, %v; want shared %s before %s", got, err, SharedInstructionsFile, GastownInstructionsFile)
+		}
+	})
+
+	t.Run("none found", func(t *testing.T) {
+		dir := t.TempDir()
+		// A directory with a candidate's name is not an instruction file
+		if err := os.Mkdir(filepath.Join(dir, "CLAUDE.md"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		_, err := ResolveInstructions(claude, dir)
+		if !errors.Is(err, ErrInstructionsNotFound) {
+			t.Fatalf("ResolveInstructions() error = %v, want ErrInstructionsNotFound", err)
+		}
+		if want := "searched CLAUDE.md, AGENTS.md, .gastown/instructions.md"; !strings.Contains(err.Error(), want) {
+			t.Errorf("error = %q, want it to contain %q", err, want)
+		}
+	})
+}
+
+func TestInstructionCandidates(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		rc   *RuntimeConfig
+		want []string
+	}{
+		{&RuntimeConfig{Provider: "claude"}, []string{"CLAUDE.md", "AGENTS.md", ".gastown/instructions.md"}},
+		{&RuntimeConfig{Provider: "kimi"}, []string{"AGENTS.md", ".gastown/instructions.md"}},
+		{&RuntimeConfig{Instructions: &RuntimeInstructionsConfig{File: "TEAM.md"}}, []string{"TEAM.md", "AGENTS.md", ".gastown/instructions.md"}},
+		{nil, []string{"CLAUDE.md", "AGENTS.md", ".gastown/instructions.md"}},
+	}
+	for _, tt := range tests {
+		if got := tt.rc.InstructionCandidates(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("InstructionCandidates(%+v) = %v, want %v", tt.rc, got, tt.want)
+		}
+	}
+}
+
 func TestRuntimeConfigBuildCommand(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -3515,3 +3592,538 @@ func TestBuildStartupCommandWithAgentOverride_NoGTAgentWhenNoOverride(t *testing
 		t.Errorf("expected no GT_AGENT in command when no override, got: %q", cmd)
 	}
 }
+
+func TestBuildStartupCommandWithAgentOverride_GTModel(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+	if err := SaveTownSettings(TownSettingsPath(townRoot), NewTownSettings()); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), NewRigSettings()); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+	env := AgentEnv(AgentEnvConfig{Role: "polecat", Rig: "testrig", AgentName: "Toast", TownRoot: townRoot, Model: "o3"})
+
+	// Each agent gets the model in its own CLI syntax, and GT_MODEL is
+	// exported so handoff and resume can preserve it
+	for agent, want := range map[string]string{"claude": "--model o3", "kimi": "--model o3", "codex": "-m o3"} {
+		cmd, err := BuildStartupCommandWithAgentOverride(env, rigPath, "", agent)
+		if err != nil {
+			t.Fatalf("%s: BuildStartupCommandWithAgentOverride: %v", agent, err)
+		}
+		if !strings.Contains(cmd, " "+want) || !strings.Contains(cmd, "GT_MODEL=o3") {
+			t.Errorf("%s: command %q, want %q and GT_MODEL=o3", agent, cmd, want)
+		}
+	}
+
+	if _, err := BuildStartupCommandWithAgentOverride(env, rigPath, "", "amp"); !errors.Is(err, ErrModelUnsupported) {
+		t.Errorf("amp: error = %v, want ErrModelUnsupported", err)
+	}
+}
+
+func TestBuildCommandLoginShell(t *testing.T) {
+	t.Parallel()
+	rc := &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, LoginShell: true}
+	if got, want := rc.BuildCommand(), "${SHELL:-/bin/sh} -l -c 'kimi --yolo'"; got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+
+	// The prompt must end up inside the -c string, not after it.
+	got := rc.BuildCommandWithPrompt("it's ready")
+	want := `${SHELL:-/bin/sh} -l -c 'kimi --yolo "it'\''s ready"'`
+	if got != want {
+		t.Errorf("BuildCommandWithPrompt() = %q, want %q", got, want)
+	}
+
+	plain := &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}}
+	if got := plain.BuildCommand(); got != "kimi --yolo" {
+		t.Errorf("BuildCommand() without LoginShell = %q, want unwrapped command", got)
+	}
+}
+
+func TestBuildCommandNice(t *testing.T) {
+	// Not parallel: swaps launchGOOS
+	orig := launchGOOS
+	t.Cleanup(func() { launchGOOS = orig })
+
+	launchGOOS = "linux"
+	rc := &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, Nice: 10}
+	if got, want := rc.BuildCommand(), "nice -n 10 kimi --yolo"; got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+	if got, want := rc.BuildCommandWithPrompt("go"), `nice -n 10 kimi --yolo "go"`; got != want {
+		t.Errorf("BuildCommandWithPrompt() = %q, want %q", got, want)
+	}
+	// nice wraps the login shell, so the whole shell runs deprioritized
+	rc.LoginShell = true
+	if got, want := rc.BuildCommand(), "nice -n 10 ${SHELL:-/bin/sh} -l -c 'kimi --yolo'"; got != want {
+		t.Errorf("BuildCommand() with LoginShell = %q, want %q", got, want)
+	}
+
+	launchGOOS = "windows"
+	rc = &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, Nice: 10}
+	if got := rc.BuildCommand(); got != "kimi --yolo" {
+		t.Errorf("BuildCommand() on windows = %q, want no nice prefix", got)
+	}
+}
+
+func TestValidateNice(t *testing.T) {
+	t.Parallel()
+	for _, n := range []int{-20, 0, 19} {
+		if err := (&RuntimeConfig{Command: "kimi", Nice: n}).Validate(); err != nil {
+			t.Errorf("Validate(nice=%d) = %v, want nil", n, err)
+		}
+	}
+	for _, n := range []int{-21, 20} {
+		if err := (&RuntimeConfig{Command: "kimi", Nice: n}).Validate(); !errors.Is(err, ErrInvalidNice) {
+			t.Errorf("Validate(nice=%d) = %v, want ErrInvalidNice", n, err)
+		}
+	}
+}
+
+func TestBuildCommandSandbox(t *testing.T) {
+	// Not parallel: swaps launchGOOS
+	orig := launchGOOS
+	t.Cleanup(func() { launchGOOS = orig })
+
+	launchGOOS = "linux"
+	rc := &RuntimeConfig{
+		Command: "kimi",
+		Args:    []string{"--yolo"},
+		Nice:    10,
+		Sandbox: &SandboxConfig{Enabled: true, Writable: []string{"~/.cache/uv"}, AllowHosts: []string{"github.com"}},
+	}
+	want := "nice -n 10 gt sandbox exec --writable ~/.kimi --writable ~/.cache/uv" +
+		" --allow-host api.moonshot.ai --allow-host api.moonshot.cn --allow-host api.kimi.com --allow-host github.com" +
+		" -- /bin/sh -c 'kimi --yolo'"
+	if got := rc.BuildCommand(); got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+
+	rc = &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, Sandbox: &SandboxConfig{Enabled: false}}
+	if got := rc.BuildCommand(); got != "kimi --yolo" {
+		t.Errorf("BuildCommand() with sandbox disabled = %q, want unwrapped command", got)
+	}
+
+	launchGOOS = "windows"
+	rc = &RuntimeConfig{Command: "kimi", Args: []string{"--yolo"}, Sandbox: &SandboxConfig{Enabled: true}}
+	if got := rc.BuildCommand(); got != "kimi --yolo" {
+		t.Errorf("BuildCommand() on windows = %q, want no sandbox", got)
+	}
+}
+
+func TestValidateSandbox(t *testing.T) {
+	t.Parallel()
+	ok := &RuntimeConfig{Command: "kimi", Sandbox: &SandboxConfig{Enabled: true, Writable: []string{"~/.cache", "/opt/data"}, AllowHosts: []string{"*.github.com"}}}
+	if err := ok.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := (&RuntimeConfig{Command: "kimi", Sandbox: &SandboxConfig{Writable: []string{"cache"}}}).Validate(); !errors.Is(err, sandbox.ErrInvalidPath) {
+		t.Errorf("Validate(relative writable) = %v, want ErrInvalidPath", err)
+	}
+	if err := (&RuntimeConfig{Command: "kimi", Sandbox: &SandboxConfig{AllowHosts: []string{"https://github.com"}}}).Validate(); !errors.Is(err, sandbox.ErrInvalidHost) {
+		t.Errorf("Validate(URL host) = %v, want ErrInvalidHost", err)
+	}
+}
+
+func TestBuildCommandLoginShellQuotingSurvivesShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	t.Parallel()
+
+	rc := &RuntimeConfig{Command: "printf", Args: []string{"'%s\\n'"}, PromptMode: "arg", LoginShell: true}
+	prompt := `it's "quoted" $HOME; echo injected`
+	cmd := exec.Command("sh", "-c", rc.BuildCommandWithPrompt(prompt))
+	cmd.Env = append(os.Environ(), "SHELL=/bin/sh")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("running %q: %v", rc.BuildCommandWithPrompt(prompt), err)
+	}
+	if got := strings.TrimSpace(string(out)); got != prompt {
+		t.Errorf("login-shell output = %q, want prompt passed through intact %q", got, prompt)
+	}
+}
+
+func TestBuildCommandEnv(t *testing.T) {
+	t.Parallel()
+	rc := &RuntimeConfig{
+		Command: "kimi",
+		Args:    []string{"--yolo"},
+		Env:     map[string]string{"KIMI_API_BASE": "https://api.example", "ANTHROPIC_MODEL": "it's fast"},
+	}
+	want := `env ANTHROPIC_MODEL='it'\''s fast' KIMI_API_BASE=https://api.example kimi --yolo`
+	if got := rc.BuildCommand(); got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+	if got := rc.BuildCommandWithPrompt("go"); got != want+` "go"` {
+		t.Errorf("BuildCommandWithPrompt() = %q, want %q", got, want+` "go"`)
+	}
+
+	// The env prefix goes inside the login shell, after its profile runs
+	rc.LoginShell = true
+	if got := rc.BuildCommand(); !strings.HasPrefix(got, "${SHELL:-/bin/sh} -l -c 'env ANTHROPIC_MODEL=") {
+		t.Errorf("BuildCommand() with LoginShell = %q, want env inside the -c string", got)
+	}
+}
+
+func TestBuildStartupCommand_AgentEnvExportedOnce(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "testrig")
+
+	townSettings := NewTownSettings()
+	townSettings.DefaultAgent = "kimi-turbo"
+	townSettings.Agents = map[string]*RuntimeConfig{
+		"kimi-turbo": {Command: "kimi", Args: []string{}, Env: map[string]string{"KIMI_MODEL": "k2-turbo"}},
+	}
+	if err := SaveTownSettings(TownSettingsPath(townRoot), townSettings); err != nil {
+		t.Fatalf("SaveTownSettings: %v", err)
+	}
+	if err := SaveRigSettings(RigSettingsPath(rigPath), NewRigSettings()); err != nil {
+		t.Fatalf("SaveRigSettings: %v", err)
+	}
+
+	cmd := BuildStartupCommand(map[string]string{"GT_ROLE": "witness"}, rigPath, "")
+	if n := strings.Count(cmd, "KIMI_MODEL=k2-turbo"); n != 1 {
+		t.Errorf("KIMI_MODEL exported %d times, want once: %q", n, cmd)
+	}
+	if !strings.HasPrefix(cmd, "exec env ") || strings.Contains(cmd, " env KIMI_MODEL") {
+		t.Errorf("agent env should join the exec env exports: %q", cmd)
+	}
+}
+
+func TestValidateEnvNames(t *testing.T) {
+	t.Parallel()
+	for _, name := range []string{"ANTHROPIC_MODEL", "_x", "a1"} {
+		if err := (&RuntimeConfig{Command: "kimi", Env: map[string]string{name: "v"}}).Validate(); err != nil {
+			t.Errorf("Validate(env %q) = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "1A", "A-B", "A B", "A;rm"} {
+		if err := (&RuntimeConfig{Command: "kimi", Env: map[string]string{name: "v"}}).Validate(); !errors.Is(err, ErrInvalidEnvName) {
+			t.Errorf("Validate(env %q) = %v, want ErrInvalidEnvName", name, err)
+		}
+	}
+}
+
+func TestBuildCommandSecrets(t *testing.T) {
+	t.Parallel()
+	rc := &RuntimeConfig{
+		Command: "kimi",
+		Args:    []string{"--yolo"},
+		Env:     map[string]string{"KIMI_MODEL": "k2"},
+		Secrets: map[string]string{"MOONSHOT_API_KEY": "moonshot"},
+	}
+	want := `env KIMI_MODEL=k2 MOONSHOT_API_KEY="$(gt secret get moonshot)" kimi --yolo`
+	if got := rc.BuildCommand(); got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+
+	// Clone must not share the map with the original
+	clone := rc.Clone()
+	clone.Secrets["OTHER"] = "other"
+	if _, ok := rc.Secrets["OTHER"]; ok {
+		t.Error("Clone() shares Secrets with the original")
+	}
+}
+
+func TestValidateSecrets(t *testing.T) {
+	t.Parallel()
+	if err := (&RuntimeConfig{Command: "kimi", Secrets: map[string]string{"MOONSHOT_API_KEY": "moonshot.work-1"}}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	if err := (&RuntimeConfig{Command: "kimi", Secrets: map[string]string{"A-B": "moonshot"}}).Validate(); !errors.Is(err, ErrInvalidEnvName) {
+		t.Errorf("Validate(secret env A-B) = %v, want ErrInvalidEnvName", err)
+	}
+	for _, name := range []string{"", "a b", "x$(rm)", "x;y"} {
+		if err := (&RuntimeConfig{Command: "kimi", Secrets: map[string]string{"KEY": name}}).Validate(); !errors.Is(err, secrets.ErrInvalidName) {
+			t.Errorf("Validate(secret %q) = %v, want secrets.ErrInvalidName", name, err)
+		}
+	}
+}
+
+func TestHandoffConfigGetGraceTimeout(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		config   *HandoffConfig
+		expected time.Duration
+	}{
+		{
+			name:     "default when nil",
+			config:   nil,
+			expected: DefaultHandoffGraceTimeout,
+		},
+		{
+			name:     "default when empty",
+			config:   &HandoffConfig{},
+			expected: DefaultHandoffGraceTimeout,
+		},
+		{
+			name:     "30 seconds",
+			config:   &HandoffConfig{GraceTimeout: "30s"},
+			expected: 30 * time.Second,
+		},
+		{
+			name:     "invalid duration falls back to default",
+			config:   &HandoffConfig{GraceTimeout: "soon"},
+			expected: DefaultHandoffGraceTimeout,
+		},
+		{
+			name:     "zero falls back to default",
+			config:   &HandoffConfig{GraceTimeout: "0s"},
+			expected: DefaultHandoffGraceTimeout,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.config.GetGraceTimeout()
+			if got != tt.expected {
+				t.Errorf("GetGraceTimeout() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWatchdogConfig(t *testing.T) {
+	t.Parallel()
+
+	var unset *WatchdogConfig
+	if got := unset.PolicyFor("crew"); got != WatchdogResume {
+		t.Errorf("nil PolicyFor(crew) = %q, want %q", got, WatchdogResume)
+	}
+	if unset.GetInterval() != DefaultWatchdogInterval || unset.GetMaxRestarts() != DefaultWatchdogMaxRestarts {
+		t.Errorf("nil config: interval %v, max restarts %d; want defaults", unset.GetInterval(), unset.GetMaxRestarts())
+	}
+
+	cfg := &WatchdogConfig{
+		Policy:         WatchdogFresh,
+		RolePolicies:   map[string]string{"mayor": WatchdogNotify},
+		Interval:       "2m",
+		MaxRestarts:    5,
+		ReportInterval: "1h",
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if got := cfg.PolicyFor("mayor"); got != WatchdogNotify {
+		t.Errorf("PolicyFor(mayor) = %q, want %q", got, WatchdogNotify)
+	}
+	if got := cfg.PolicyFor("crew"); got != WatchdogFresh {
+		t.Errorf("PolicyFor(crew) = %q, want %q", got, WatchdogFresh)
+	}
+	if cfg.GetInterval() != 2*time.Minute || cfg.GetMaxRestarts() != 5 {
+		t.Errorf("interval %v, max restarts %d; want 2m, 5", cfg.GetInterval(), cfg.GetMaxRestarts())
+	}
+	if cfg.GetReportInterval() != time.Hour || unset.GetReportInterval() != 0 {
+		t.Errorf("report interval %v (unset %v); want 1h (0)", cfg.GetReportInterval(), unset.GetReportInterval())
+	}
+
+	for _, bad := range []*WatchdogConfig{
+		{Policy: "reboot"},
+		{RolePolicies: map[string]string{"crew": "later"}},
+		{Interval: "soon"},
+		{MaxRestarts: -1},
+		{MetricsAddr: "9464"},
+		{ReportInterval: "10s"},
+		{ReportLines: -1},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want an error", bad)
+		}
+	}
+}
+
+func TestNotifyConfig(t *testing.T) {
+	t.Parallel()
+
+	var unset *NotifyConfig
+	if err := unset.Validate(); err != nil || unset.GetStuckAfter() != DefaultNotifyStuckAfter {
+		t.Errorf("nil config: Validate %v, stuck after %v; want nil, default", err, unset.GetStuckAfter())
+	}
+
+	cfg := &NotifyConfig{
+		Sinks: []NotifySink{
+			{Type: NotifySinkSlack, URL: "https://hooks.slack.com/services/T/B/x", Events: []string{"merged"}},
+			{Type: NotifySinkDesktop},
+		},
+		StuckAfter: "15m",
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if cfg.GetStuckAfter() != 15*time.Minute {
+		t.Errorf("stuck after %v, want 15m", cfg.GetStuckAfter())
+	}
+	if !cfg.Sinks[0].Wants("merged") || cfg.Sinks[0].Wants("handoff") || !cfg.Sinks[1].Wants("handoff") {
+		t.Error("Wants: want a sink without events to take them all, and one with events only those")
+	}
+
+	for _, bad := range []*NotifyConfig{
+		{Sinks: []NotifySink{{Type: "pager"}}},
+		{Sinks: []NotifySink{{Type: NotifySinkSlack}}},
+		{Sinks: []NotifySink{{Type: NotifySinkWebhook, URL: "ftp://example.com"}}},
+		{Sinks: []NotifySink{{Type: NotifySinkDesktop, Events: []string{"done"}}}},
+		{StuckAfter: "0s"},
+		{StuckAfter: "later"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) = nil, want error", bad)
+		}
+	}
+}
+
+func TestMergeArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		base      []string
+		overrides []string
+		want      []string
+	}{
+		{
+			name:      "valued flag replaces existing occurrence",
+			base:      []string{"--yolo", "--model", "k1", "--verbose"},
+			overrides: []string{"--model", "k2"},
+			want:      []string{"--yolo", "--model", "k2", "--verbose"},
+		},
+		{
+			name:      "valued flag replaces --flag=value form",
+			base:      []string{"--model=k1", "--yolo"},
+			overrides: []string{"--model", "k2"},
+			want:      []string{"--model", "k2", "--yolo"},
+		},
+		{
+			name:      "equals override replaces spaced form",
+			base:      []string{"--mcp-config", "/a.json"},
+			overrides: []string{"--mcp-config=/b.json"},
+			want:      []string{"--mcp-config=/b.json"},
+		},
+		{
+			name:      "new flag is appended",
+			base:      []string{"--yolo"},
+			overrides: []string{"--mcp-config-file", "/m.json"},
+			want:      []string{"--yolo", "--mcp-config-file", "/m.json"},
+		},
+		{
+			name:      "boolean flag already present is not duplicated",
+			base:      []string{"--dangerously-skip-permissions"},
+			overrides: []string{"--dangerously-skip-permissions"},
+			want:      []string{"--dangerously-skip-permissions"},
+		},
+		{
+			name:      "boolean override keeps following base arg",
+			base:      []string{"--yolo", "--model", "k1"},
+			overrides: []string{"--yolo", "--debug"},
+			want:      []string{"--yolo", "--model", "k1", "--debug"},
+		},
+		{
+			name:      "nil base",
+			base:      nil,
+			overrides: []string{"--model", "k2"},
+			want:      []string{"--model", "k2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := append([]string(nil), tt.base...)
+			got := mergeArgs(base, tt.overrides)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeArgs(%v, %v) = %v, want %v", tt.base, tt.overrides, got, tt.want)
+			}
+			if !reflect.DeepEqual(base, tt.base) && tt.base != nil {
+				t.Errorf("mergeArgs modified base: %v", base)
+			}
+		})
+	}
+}
+
+func TestBuildCommandMCPConfigNotDuplicated(t *testing.T) {
+	t.Parallel()
+	rc := &RuntimeConfig{
+		Command:   "kimi",
+		Args:      []string{"--yolo", "--mcp-config-file", "/old.json"},
+		MCPConfig: "/new.json",
+	}
+	got := rc.BuildCommand()
+	want := "kimi --yolo --mcp-config-file /new.json"
+	if got != want {
+		t.Errorf("BuildCommand() = %q, want %q", got, want)
+	}
+}
+
+func TestRuntimeConfigClone(t *testing.T) {
+	t.Parallel()
+	orig := &RuntimeConfig{
+		Provider:      "kimi",
+		Command:       "kimi",
+		Args:          []string{"--yolo"},
+		Env:           map[string]string{"A": "1"},
+		InitialPrompt: "hi",
+		PromptMode:    "arg",
+		Session:       &RuntimeSessionConfig{SessionIDEnv: "KIMI_SESSION_ID", ConfigDirEnv: "KIMI_CONFIG_DIR"},
+		Hooks:         &RuntimeHooksConfig{Provider: "kimi", Dir: ".kimi", SettingsFile: "settings.json"},
+		Tmux:          &RuntimeTmuxConfig{ProcessNames: []string{"kimi"}, ReadyPromptPrefix: "> ", ReadyDelayMs: 100},
+		Instructions:  &RuntimeInstructionsConfig{File: "AGENTS.md"},
+		MCPConfig:     "/tmp/mcp.json",
+		LoginShell:    true,
+	}
+	snapshot := *orig
+	snapshot.Args = append([]string(nil), orig.Args...)
+	snapshot.Env = map[string]string{"A": "1"}
+	session, hooks, tmux, instructions := *orig.Session, *orig.Hooks, *orig.Tmux, *orig.Instructions
+	tmux.ProcessNames = append([]string(nil), orig.Tmux.ProcessNames...)
+
+	clone := orig.Clone()
+	if !reflect.DeepEqual(clone, orig) {
+		t.Fatalf("Clone() = %+v, want equal to original", clone)
+	}
+
+	// Mutate every slice, map and pointer field on the clone.
+	clone.Args[0] = "--changed"
+	clone.Args = append(clone.Args, "--extra")
+	clone.Env["A"] = "2"
+	clone.Env["B"] = "3"
+	clone.Session.SessionIDEnv = "X"
+	clone.Hooks.Dir = ".x"
+	clone.Tmux.ProcessNames[0] = "x"
+	clone.Tmux.ReadyDelayMs = 1
+	clone.Instructions.File = "X.md"
+	clone.Command = "x"
+
+	if !reflect.DeepEqual(orig.Args, snapshot.Args) || !reflect.DeepEqual(orig.Env, snapshot.Env) {
+		t.Errorf("original args/env mutated: %v %v", orig.Args, orig.Env)
+	}
+	if *orig.Session != session || *orig.Hooks != hooks || *orig.Instructions != instructions {
+		t.Errorf("original nested configs mutated: %+v %+v %+v", orig.Session, orig.Hooks, orig.Instructions)
+	}
+	if !reflect.DeepEqual(*orig.Tmux, tmux) {
+		t.Errorf("original tmux config mutated: %+v", orig.Tmux)
+	}
+	if orig.Command != snapshot.Command {
+		t.Errorf("original command mutated: %q", orig.Command)
+	}
+}
+
+func TestRuntimeConfigClonePreservesNil(t *testing.T) {
+	t.Parallel()
+	if (*RuntimeConfig)(nil).Clone() != nil {
+		t.Error("Clone() of nil config should be nil")
+	}
+	clone := (&RuntimeConfig{Command: "claude", Args: []string{}}).Clone()
+	if clone.Args == nil || len(clone.Args) != 0 {
+		t.Errorf("empty Args should stay empty and non-nil, got %#v", clone.Args)
+	}
+	if clone.Env != nil || clone.Session != nil || clone.Hooks != nil || clone.Tmux != nil || clone.Instructions != nil {
+		t.Errorf("nil fields should stay nil, got %+v", clone)
+	}
+}
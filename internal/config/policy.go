@@ -0,0 +1,216 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrAgentPolicy indicates an agent command breaks the agent policy of an
+// "enforce": "reject" town or rig.
+var ErrAgentPolicy = errors.New("agent command violates agent policy")
+
+// Agent policy enforcement modes.
+const (
+	PolicyRewrite = "rewrite" // Drop denied args and add required ones
+	PolicyReject  = "reject"  // Refuse to start an agent that breaks the policy
+)
+
+// AgentPolicy restricts the flags agents are started with, so a town can
+// enforce safer defaults than the presets' (which skip permission prompts)
+// without redefining every agent. The town's policy applies to all its
+// rigs; a rig's policy overrides it field by field.
+//
+// Example:
+//
+//	"agent_policy": {
+//	  "allow_yolo": false,
+//	  "deny_args": ["--no-sandbox"],
+//	  "required_args": {"codex": ["--sandbox", "workspace-write"]},
+//	  "enforce": "reject"
+//	}
+type AgentPolicy struct {
+	// AllowYolo permits the args and env presets use to skip permission
+	// prompts (their yolo_args and yolo_env, e.g.
+	// --dangerously-skip-permissions). Default: true.
+	AllowYolo *bool `json:"allow_yolo,omitempty"`
+
+	// DenyArgs are further args agents may not be started with. An entry
+	// is a flag ("--no-sandbox", also matching --no-sandbox=...) or a flag
+	// and its value ("--approval-mode yolo").
+	DenyArgs []string `json:"deny_args,omitempty"`
+
+	// RequiredArgs are args an agent must be started with, keyed by agent
+	// name, preset name, or "*" for every agent. A required flag replaces
+	// the agent's own value for it (e.g. a sandbox mode).
+	// Example: {"codex": ["--sandbox", "workspace-write"]}
+	RequiredArgs map[string][]string `json:"required_args,omitempty"`
+
+	// Enforce is what happens to a command that breaks the policy:
+	// "rewrite" drops denied args and adds required ones; "reject" refuses
+	// to start the agent. Commands built where the error can't be reported
+	// are rewritten instead. Default: "rewrite"
+	Enforce string `json:"enforce,omitempty"`
+}
+
+// Validate checks the enforcement mode and arg entries.
+func (p *AgentPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.Enforce != "" && p.Enforce != PolicyRewrite && p.Enforce != PolicyReject {
+		return fmt.Errorf("invalid agent policy enforce %q (want rewrite or reject)", p.Enforce)
+	}
+	for _, arg := range p.DenyArgs {
+		if words := strings.Fields(arg); len(words) == 0 || len(words) > 2 || !isFlagArg(words[0]) {
+			return fmt.Errorf("invalid agent policy deny_args entry %q (want a flag, optionally with its value)", arg)
+		}
+	}
+	for _, agent := range slices.Sorted(maps.Keys(p.RequiredArgs)) {
+		if len(p.RequiredArgs[agent]) == 0 {
+			return fmt.Errorf("agent policy required_args for %s is empty", agent)
+		}
+	}
+	return nil
+}
+
+// yoloAllowed reports whether the policy permits the presets' yolo args.
+func (p *AgentPolicy) yoloAllowed() bool {
+	return p == nil || p.AllowYolo == nil || *p.AllowYolo
+}
+
+// mergeAgentPolicy returns the town policy overridden by the rig's: rig
+// fields that are set replace the town's, deny_args add up, and rig
+// required_args replace the town's for the same agent. Nil if neither is
+// set.
+func mergeAgentPolicy(town, rig *AgentPolicy) *AgentPolicy {
+	if rig == nil {
+		return town
+	}
+	if town == nil {
+		return rig
+	}
+	merged := &AgentPolicy{
+		AllowYolo:    town.AllowYolo,
+		DenyArgs:     append(slices.Clone(town.DenyArgs), rig.DenyArgs...),
+		RequiredArgs: maps.Clone(town.RequiredArgs),
+		Enforce:      town.Enforce,
+	}
+	if rig.AllowYolo != nil {
+		merged.AllowYolo = rig.AllowYolo
+	}
+	if rig.Enforce != "" {
+		merged.Enforce = rig.Enforce
+	}
+	if len(rig.RequiredArgs) > 0 && merged.RequiredArgs == nil {
+		merged.RequiredArgs = make(map[string][]string, len(rig.RequiredArgs))
+	}
+	maps.Copy(merged.RequiredArgs, rig.RequiredArgs)
+	return merged
+}
+
+// withAgentPolicy applies the town's agent policy, as overridden by the
+// rig's, to rc resolved for the agent called name (empty if unnamed).
+func withAgentPolicy(rc *RuntimeConfig, name string, town *TownSettings, rig *RigSettings) *RuntimeConfig {
+	var townPolicy, rigPolicy *AgentPolicy
+	if town != nil {
+		townPolicy = town.AgentPolicy
+	}
+	if rig != nil {
+		rigPolicy = rig.AgentPolicy
+	}
+	return mergeAgentPolicy(townPolicy, rigPolicy).apply(name, rc)
+}
+
+// apply returns a copy of rc rewritten to follow the policy. Under
+// "reject", a config that had to be rewritten also fails Validate with
+// ErrAgentPolicy, so callers that check refuse to start it.
+func (p *AgentPolicy) apply(name string, rc *RuntimeConfig) *RuntimeConfig {
+	if p == nil || rc == nil {
+		return rc
+	}
+	info := presetForRuntimeConfig(rc)
+	out := rc.Clone()
+	out.policy = p
+	out.policyAgent = name
+
+	var violations []string
+	out.Args, violations = p.rewriteArgs(info, name, out.Args)
+	if !p.yoloAllowed() && info != nil {
+		for _, key := range info.YoloEnv {
+			if _, ok := out.Env[key]; ok {
+				delete(out.Env, key)
+				violations = append(violations, "env "+key)
+			}
+		}
+	}
+	if len(violations) > 0 && p.Enforce == PolicyReject {
+		agent := name
+		if agent == "" {
+			agent = rc.Command
+		}
+		out.policyErr = fmt.Errorf("%w: %s: %s", ErrAgentPolicy, agent, strings.Join(violations, ", "))
+	}
+	return out
+}
+
+// rewriteArgs drops the args the policy denies to the agent called name
+// (backed by preset info, which may be nil) and adds the ones it requires.
+// It returns the new args and what was changed.
+func (p *AgentPolicy) rewriteArgs(info *AgentPresetInfo, name string, args []string) ([]string, []string) {
+	deny := slices.Clone(p.DenyArgs)
+	if !p.yoloAllowed() && info != nil {
+		deny = append(deny, info.YoloArgs...)
+	}
+
+	var violations []string
+	kept := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if n := deniedArgLen(deny, args[i:]); n > 0 {
+			violations = append(violations, strings.Join(args[i:i+n], " "))
+			i += n - 1
+			continue
+		}
+		kept = append(kept, args[i])
+	}
+
+	var keys []string
+	keys = append(keys, "*")
+	if info != nil {
+		keys = append(keys, string(info.Name))
+	}
+	if name != "" {
+		keys = append(keys, name)
+	}
+	var required []string
+	for _, key := range slices.Compact(keys) {
+		required = append(required, p.RequiredArgs[key]...)
+	}
+	if len(required) > 0 {
+		merged := mergeArgs(kept, required)
+		if !slices.Equal(merged, kept) {
+			violations = append(violations, "missing "+strings.Join(required, " "))
+		}
+		kept = merged
+	}
+	return kept, violations
+}
+
+// deniedArgLen returns how many of the leading args a deny entry matches:
+// 1 for a flag (or flag=value), 2 for a flag and value entry, 0 if none.
+func deniedArgLen(deny []string, args []string) int {
+	for _, entry := range deny {
+		words := strings.Fields(entry)
+		switch {
+		case len(words) == 1 && (args[0] == words[0] || strings.HasPrefix(args[0], words[0]+"=")):
+			return 1
+		case len(words) == 2 && args[0] == words[0]+"="+words[1]:
+			return 1
+		case len(words) == 2 && len(args) > 1 && args[0] == words[0] && args[1] == words[1]:
+			return 2
+		}
+	}
+	return 0
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAgentPolicyValidate(t *testing.T) {
+	valid := &AgentPolicy{
+		DenyArgs:     []string{"--no-sandbox", "--approval-mode yolo"},
+		RequiredArgs: map[string][]string{"codex": {"--sandbox", "workspace-write"}},
+		Enforce:      PolicyReject,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+
+	tests := []struct {
+		name   string
+		policy *AgentPolicy
+		want   string
+	}{
+		{"enforce", &AgentPolicy{Enforce: "block"}, "invalid agent policy enforce"},
+		{"deny value only", &AgentPolicy{DenyArgs: []string{"yolo"}}, "deny_args"},
+		{"deny three words", &AgentPolicy{DenyArgs: []string{"--a b c"}}, "deny_args"},
+		{"empty required", &AgentPolicy{RequiredArgs: map[string][]string{"codex": nil}}, "required_args for codex"},
+	}
+	for _, tt := range tests {
+		if err := tt.policy.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Validate() = %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestAgentPolicyApply(t *testing.T) {
+	noYolo := false
+	tests := []struct {
+		name   string
+		policy *AgentPolicy
+		agent  string
+		args   []string
+		want   []string
+	}{
+		{"no policy", nil, "claude", nil, []string{"--dangerously-skip-permissions"}},
+		{"yolo allowed", &AgentPolicy{}, "claude", nil, []string{"--dangerously-skip-permissions"}},
+		{"claude yolo denied", &AgentPolicy{AllowYolo: &noYolo}, "claude", nil, []string{}},
+		{"gemini flag and value", &AgentPolicy{AllowYolo: &noYolo}, "gemini", nil, []string{}},
+		{"other args kept", &AgentPolicy{AllowYolo: &noYolo}, "amp", nil, []string{"--no-ide"}},
+		{"deny args", &AgentPolicy{DenyArgs: []string{"--no-ide"}}, "amp", nil, []string{"--dangerously-allow-all"}},
+		{"deny flag=value", &AgentPolicy{DenyArgs: []string{"--sandbox"}}, "codex", []string{"--yolo", "--sandbox=none"}, []string{"--yolo"}},
+		{
+			"required args replace the agent's",
+			&AgentPolicy{AllowYolo: &noYolo, RequiredArgs: map[string][]string{"codex": {"--sandbox", "workspace-write"}}},
+			"codex", []string{"--yolo", "--sandbox", "danger-full-access"},
+			[]string{"--sandbox", "workspace-write"},
+		},
+		{
+			"required args for every agent",
+			&AgentPolicy{RequiredArgs: map[string][]string{"*": {"--verbose"}}},
+			"kimi", nil, []string{"--yolo", "--verbose"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := RuntimeConfigFromPreset(AgentPreset(tt.agent))
+			if tt.args != nil {
+				rc.Args = tt.args
+			}
+			got := tt.policy.apply(tt.agent, rc)
+			if !slices.Equal(got.Args, tt.want) {
+				t.Errorf("apply() args = %q, want %q", got.Args, tt.want)
+			}
+			if err := got.Validate(); err != nil {
+				t.Errorf("Validate() = %v, want nil under rewrite", err)
+			}
+		})
+	}
+
+	// Yolo env goes too, and the preset is untouched
+	rc := (&AgentPolicy{AllowYolo: &noYolo}).apply("opencode", RuntimeConfigFromPreset(AgentOpenCode))
+	if _, ok := rc.Env["OPENCODE_PERMISSION"]; ok {
+		t.Errorf("opencode env = %v, want OPENCODE_PERMISSION removed", rc.Env)
+	}
+	if _, ok := RuntimeConfigFromPreset(AgentOpenCode).Env["OPENCODE_PERMISSION"]; !ok {
+		t.Error("apply() modified the opencode preset")
+	}
+}
+
+func TestAgentPolicyReject(t *testing.T) {
+	noYolo := false
+	policy := &AgentPolicy{AllowYolo: &noYolo, Enforce: PolicyReject}
+
+	rc := policy.apply("claude", RuntimeConfigFromPreset(AgentClaude))
+	err := rc.Validate()
+	if !errors.Is(err, ErrAgentPolicy) || !strings.Contains(err.Error(), "--dangerously-skip-permissions") {
+		t.Errorf("Validate() = %v, want ErrAgentPolicy naming the flag", err)
+	}
+	// Still rewritten, for callers that can't report the error
+	if len(rc.Args) != 0 {
+		t.Errorf("rejected args = %q, want them rewritten too", rc.Args)
+	}
+	if _, err := BuildResumeCommandWithConfig("claude", "abc", rc); !errors.Is(err, ErrAgentPolicy) {
+		t.Errorf("resume err = %v, want ErrAgentPolicy", err)
+	}
+
+	// A config the policy doesn't change passes
+	if err := policy.apply("auggie", RuntimeConfigFromPreset(AgentAuggie)).Validate(); err != nil {
+		t.Errorf("auggie Validate() = %v, want nil", err)
+	}
+}
+
+func TestAgentPolicyResumeCommand(t *testing.T) {
+	noYolo := false
+	policy := &AgentPolicy{AllowYolo: &noYolo, RequiredArgs: map[string][]string{"codex": {"--sandbox", "workspace-write"}}}
+	tests := []struct {
+		agent string
+		want  string
+	}{
+		{"claude", "claude --resume abc"},
+		{"codex", "codex resume abc --sandbox workspace-write"},
+	}
+	for _, tt := range tests {
+		rc := policy.apply(tt.agent, RuntimeConfigFromPreset(AgentPreset(tt.agent)))
+		got, err := BuildResumeCommandWithConfig(tt.agent, "abc", rc)
+		if err != nil {
+			t.Fatalf("BuildResumeCommandWithConfig(%s) error = %v", tt.agent, err)
+		}
+		if got != tt.want {
+			t.Errorf("BuildResumeCommandWithConfig(%s) = %q, want %q", tt.agent, got, tt.want)
+		}
+	}
+}
+
+func TestMergeAgentPolicy(t *testing.T) {
+	yes, no := true, false
+	town := &AgentPolicy{
+		AllowYolo:    &no,
+		DenyArgs:     []string{"--no-sandbox"},
+		RequiredArgs: map[string][]string{"codex": {"--sandbox", "read-only"}, "*": {"--verbose"}},
+		Enforce:      PolicyReject,
+	}
+	rig := &AgentPolicy{
+		AllowYolo:    &yes,
+		DenyArgs:     []string{"--web"},
+		RequiredArgs: map[string][]string{"codex": {"--sandbox", "workspace-write"}},
+	}
+
+	got := mergeAgentPolicy(town, rig)
+	if !got.yoloAllowed() || got.Enforce != PolicyReject {
+		t.Errorf("merged allow_yolo=%v enforce=%q, want the rig's allow_yolo and the town's enforce", got.yoloAllowed(), got.Enforce)
+	}
+	if !slices.Equal(got.DenyArgs, []string{"--no-sandbox", "--web"}) {
+		t.Errorf("merged deny_args = %q, want both", got.DenyArgs)
+	}
+	if !slices.Equal(got.RequiredArgs["codex"], rig.RequiredArgs["codex"]) || len(got.RequiredArgs["*"]) != 1 {
+		t.Errorf("merged required_args = %v, want the rig's codex and the town's *", got.RequiredArgs)
+	}
+	if town.RequiredArgs["codex"][1] != "read-only" {
+		t.Error("mergeAgentPolicy() modified the town policy")
+	}
+	if mergeAgentPolicy(town, nil) != town || mergeAgentPolicy(nil, rig) != rig || mergeAgentPolicy(nil, nil) != nil {
+		t.Error("mergeAgentPolicy() with one side nil should return the other")
+	}
+}
+
+func TestResolveAgentConfigAgentPolicy(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestFile(t, TownSettingsPath(townRoot), `{
+  "type": "town-settings", "version": 1,
+  "agent_policy": {"allow_yolo": false, "enforce": "reject"}
+}`)
+	strict := filepath.Join(townRoot, "strict")
+	lax := filepath.Join(townRoot, "lax")
+	writeTestFile(t, RigSettingsPath(strict), `{"type": "rig-settings", "version": 1, "agent": "kimi"}`)
+	writeTestFile(t, RigSettingsPath(lax), `{"type": "rig-settings", "version": 1, "agent": "kimi",
+  "agent_policy": {"allow_yolo": true}}`)
+
+	rc := ResolveAgentConfig(townRoot, strict)
+	if slices.Contains(rc.Args, "--yolo") || !errors.Is(rc.Validate(), ErrAgentPolicy) {
+		t.Errorf("strict rig: args %q, Validate() = %v, want --yolo rejected", rc.Args, rc.Validate())
+	}
+	if _, err := BuildStartupCommandWithAgentOverride(nil, strict, "", "kimi"); !errors.Is(err, ErrAgentPolicy) {
+		t.Errorf("strict rig startup: err = %v, want ErrAgentPolicy", err)
+	}
+	// Builders that can't fail get the rewritten command
+	if cmd := BuildStartupCommand(nil, strict, ""); strings.Contains(cmd, "--yolo") {
+		t.Errorf("strict rig startup command = %q, want --yolo dropped", cmd)
+	}
+
+	rc = ResolveAgentConfig(townRoot, lax)
+	if !slices.Contains(rc.Args, "--yolo") || rc.Validate() != nil {
+		t.Errorf("lax rig: args %q, Validate() = %v, want the rig to allow --yolo", rc.Args, rc.Validate())
+	}
+}
+
+func TestValidateTownConfigAgentPolicy(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestFile(t, TownSettingsPath(townRoot), `{
+  "type": "town-settings", "version": 1,
+  "agent_policy": {"enforce": "block"}
+}`)
+	issues, _ := ValidateTownConfig(townRoot)
+	if len(issues) != 1 || issues[0].Field != "agent_policy" {
+		t.Errorf("issues = %v, want agent_policy", issues)
+	}
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RigSpecFile is the declarative rig configuration file name.
+const RigSpecFile = "rig.yaml"
+
+// ErrInvalidRigSpec indicates a rig.yaml that fails validation.
+var ErrInvalidRigSpec = errors.New("invalid rig spec")
+
+// RigSpec is the declared state of a rig, read from rig.yaml:
+//
+//	agent: kimi                 # default agent for the rig
+//	role_agents:
+//	  witness: claude
+//	agents:                     # rig-level presets, keyed like agents.json
+//	  kimi-fast:
+//	    command: kimi
+//	    args: [--yolo, --model, kimi-k2-turbo]
+//	    resume_flag: --continue
+//	    resume_style: flag
+//	    supports_hooks: true    # hook overrides
+//	    hooks_dir: .kimi
+//	env:
+//	  RUST_LOG: info
+//	crew:
+//	  - name: max
+//	    agent: kimi-fast
+//	    env: {FEATURE: search}
+//	  - name: jane
+//	    running: false
+type RigSpec struct {
+	// Agent is the rig's default agent (RigSettings.Agent).
+	Agent string `json:"agent,omitempty"`
+
+	// RoleAgents maps roles to agents (RigSettings.RoleAgents).
+	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// Agents are presets written to the rig agent registry
+	// (<rig>/settings/agents.json).
+	Agents map[string]*AgentPresetInfo `json:"agents,omitempty"`
+
+	// Env is set in every crew session the spec starts.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Crew lists the rig's crew workers.
+	Crew []CrewSpec `json:"crew,omitempty"`
+}
+
+// CrewSpec is one declared crew worker.
+type CrewSpec struct {
+	Name string `json:"name"`
+
+	// Agent overrides the crew role's agent for this worker.
+	Agent string `json:"agent,omitempty"`
+
+	// Env is merged over RigSpec.Env for this worker's session.
+	Env map[string]string `json:"env,omitempty"`
+
+	// Running declares whether the worker's session should run. Default true.
+	Running *bool `json:"running,omitempty"`
+}
+
+// ShouldRun reports whether the crew worker's session should be running.
+func (c CrewSpec) ShouldRun() bool {
+	return c.Running == nil || *c.Running
+}
+
+// FindRigSpec returns the rig.yaml for the rig at rigPath: in the rig
+// directory itself, else at the root of the rig's project checkout
+// (mayor/rig), where it can be committed with the code. Empty if neither.
+func FindRigSpec(rigPath string) string {
+	for _, path := range []string{
+		filepath.Join(rigPath, RigSpecFile),
+		filepath.Join(rigPath, "mayor", "rig", RigSpecFile),
+	} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// LoadRigSpec parses a rig.yaml. Unknown keys are errors, so a misspelled
+// key is caught rather than ignored; call Validate for the values.
+func LoadRigSpec(path string) (*RigSpec, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from the rig
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	// Round-trip through JSON so agent keys and field rules match agents.json
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+	var spec RigSpec
+	if err := dec.Decode(&spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for name, preset := range spec.Agents {
+		if preset == nil {
+			return nil, fmt.Errorf("%s: agent %q is empty", path, name)
+		}
+		preset.Name = AgentPreset(name)
+	}
+	return &spec, nil
+}
+
+// rigSpecRoles are the roles role_agents may name.
+var rigSpecRoles = []string{"witness", "refinery", "polecat", "crew"}
+
+// Validate checks the spec's values. knownAgent reports whether an agent
+// name is defined outside the spec (a preset, or a town custom agent); the
+// spec's own agents always count. All problems are reported together.
+func (s *RigSpec) Validate(knownAgent func(string) bool) error {
+	var problems []string
+	addf := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	checkAgent := func(where, name string) {
+		if name == "" {
+			return
+		}
+		if _, ok := s.Agents[name]; ok || knownAgent(name) {
+			return
+		}
+		addf("%s: unknown agent %q", where, name)
+	}
+
+	checkAgent("agent", s.Agent)
+	for _, role := range slices.Sorted(maps.Keys(s.RoleAgents)) {
+		if !slices.Contains(rigSpecRoles, role) {
+			addf("role_agents: unknown role %q (want one of: %s)", role, strings.Join(rigSpecRoles, ", "))
+		}
+		checkAgent("role_agents."+role, s.RoleAgents[role])
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(s.Agents)) {
+		preset := s.Agents[name]
+		where := "agents." + name
+		if preset.Command == "" {
+			addf("%s: missing command", where)
+		}
+		switch preset.ResumeStyle {
+		case "", "flag", "subcommand":
+		default:
+			addf("%s: unknown resume_style %q (want flag or subcommand)", where, preset.ResumeStyle)
+		}
+		if preset.ResumeStyle != "" && preset.ResumeFlag == "" {
+			addf("%s: resume_style needs a resume_flag", where)
+		}
+		if err := ValidateResumeTemplate(preset.ResumeTemplate); err != nil {
+			addf("%s: %v", where, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, c := range s.Crew {
+		where := fmt.Sprintf("crew[%d]", i)
+		switch {
+		case c.Name == "":
+			addf("%s: missing name", where)
+		case strings.ContainsAny(c.Name, "-. /\\"):
+			addf("%s: name %q must not contain hyphens, dots, spaces or slashes", where, c.Name)
+		case seen[c.Name]:
+			addf("%s: crew %q declared twice", where, c.Name)
+		}
+		seen[c.Name] = true
+		checkAgent(where+".agent", c.Agent)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%w:\n  %s", ErrInvalidRigSpec, strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// CrewEnv returns the environment of crew worker c's session: the spec's
+// env with the worker's merged over it.
+func (s *RigSpec) CrewEnv(c CrewSpec) map[string]string {
+	return MergeEnv(s.Env, c.Env)
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRigSpec(t *testing.T, dir, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, RigSpecFile)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRigSpec(t *testing.T) {
+	path := writeRigSpec(t, t.TempDir(), `
+agent: kimi-fast
+role_agents:
+  witness: claude
+agents:
+  kimi-fast:
+    command: kimi
+    args: [--yolo]
+    resume_flag: --continue
+    resume_style: flag
+    hooks_dir: .kimi
+env:
+  RUST_LOG: info
+crew:
+  - name: max
+    env: {RUST_LOG: debug, FEATURE: search}
+  - name: jane
+    agent: claude
+    running: false
+`)
+	spec, err := LoadRigSpec(path)
+	if err != nil {
+		t.Fatalf("LoadRigSpec: %v", err)
+	}
+	if err := spec.Validate(IsKnownPreset); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if preset := spec.Agents["kimi-fast"]; preset.Name != "kimi-fast" || preset.HooksDir != ".kimi" {
+		t.Errorf("agents.kimi-fast = %+v", preset)
+	}
+	if len(spec.Crew) != 2 || !spec.Crew[0].ShouldRun() || spec.Crew[1].ShouldRun() {
+		t.Errorf("crew = %+v, want max running and jane stopped", spec.Crew)
+	}
+	env := spec.CrewEnv(spec.Crew[0])
+	if env["RUST_LOG"] != "debug" || env["FEATURE"] != "search" {
+		t.Errorf("CrewEnv(max) = %v, want crew env over rig env", env)
+	}
+}
+
+func TestLoadRigSpecUnknownKey(t *testing.T) {
+	path := writeRigSpec(t, t.TempDir(), "crew:\n  - name: max\n    agnet: kimi\n")
+	if _, err := LoadRigSpec(path); err == nil || !strings.Contains(err.Error(), "agnet") {
+		t.Errorf("LoadRigSpec() error = %v, want the misspelled key named", err)
+	}
+}
+
+func TestRigSpecValidate(t *testing.T) {
+	spec := &RigSpec{
+		Agent:      "kimmi",
+		RoleAgents: map[string]string{"witnes": "claude"},
+		Agents: map[string]*AgentPresetInfo{
+			"broken": {ResumeStyle: "positional"},
+		},
+		Crew: []CrewSpec{{Name: "max"}, {Name: "max"}, {Name: "bad-name"}, {Name: "jane", Agent: "nope"}},
+	}
+	err := spec.Validate(IsKnownPreset)
+	if !errors.Is(err, ErrInvalidRigSpec) {
+		t.Fatalf("Validate() error = %v, want ErrInvalidRigSpec", err)
+	}
+	for _, want := range []string{
+		`agent: unknown agent "kimmi"`,
+		`unknown role "witnes"`,
+		"agents.broken: missing command",
+		`unknown resume_style "positional"`,
+		"resume_style needs a resume_flag",
+		`crew "max" declared twice`,
+		`name "bad-name"`,
+		`crew[3].agent: unknown agent "nope"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Validate() error missing %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestFindRigSpec(t *testing.T) {
+	rigPath := t.TempDir()
+	if got := FindRigSpec(rigPath); got != "" {
+		t.Errorf("FindRigSpec() = %q, want none", got)
+	}
+	checkout := filepath.Join(rigPath, "mayor", "rig")
+	if err := os.MkdirAll(checkout, 0755); err != nil {
+		t.Fatal(err)
+	}
+	inCheckout := writeRigSpec(t, checkout, "agent: claude\n")
+	if got := FindRigSpec(rigPath); got != inCheckout {
+		t.Errorf("FindRigSpec() = %q, want %q", got, inCheckout)
+	}
+	inRig := writeRigSpec(t, rigPath, "agent: claude\n")
+	if got := FindRigSpec(rigPath); got != inRig {
+		t.Errorf("FindRigSpec() = %q, want the rig directory's %q first", got, inRig)
+	}
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// townSettingsMigrations upgrade town settings one schema version at a time:
+// townSettingsMigrations[v] migrates version v to v+1. Bump
+// CurrentTownSettingsVersion with a new entry when a field's meaning changes.
+var townSettingsMigrations = []func(*TownSettings){
+	// 0 -> 1: files written before versioning may lack the type field.
+	func(s *TownSettings) {
+		if s.Type == "" {
+			s.Type = "town-settings"
+		}
+	},
+}
+
+// rigSettingsMigrations upgrade rig settings one schema version at a time,
+// like townSettingsMigrations.
+var rigSettingsMigrations = []func(*RigSettings){
+	// 0 -> 1: files written before versioning may lack the type field.
+	func(s *RigSettings) {
+		if s.Type == "" {
+			s.Type = "rig-settings"
+		}
+	},
+}
+
+// migrateTownSettings upgrades settings loaded from path to
+// CurrentTownSettingsVersion. Files from a newer gt are rejected with
+// ErrInvalidVersion rather than loaded with fields this version ignores.
+func migrateTownSettings(s *TownSettings, path string) error {
+	if err := checkSchemaVersion(path, "town settings", s.Version, CurrentTownSettingsVersion); err != nil {
+		return err
+	}
+	for s.Version < CurrentTownSettingsVersion {
+		townSettingsMigrations[s.Version](s)
+		s.Version++
+	}
+	return nil
+}
+
+// migrateRigSettings upgrades settings loaded from path to
+// CurrentRigSettingsVersion, like migrateTownSettings.
+func migrateRigSettings(s *RigSettings, path string) error {
+	if err := checkSchemaVersion(path, "rig settings", s.Version, CurrentRigSettingsVersion); err != nil {
+		return err
+	}
+	for s.Version < CurrentRigSettingsVersion {
+		rigSettingsMigrations[s.Version](s)
+		s.Version++
+	}
+	return nil
+}
+
+// checkSchemaVersion returns ErrInvalidVersion if version can't be migrated
+// to current.
+func checkSchemaVersion(path, kind string, version, current int) error {
+	if version > current {
+		return fmt.Errorf("%w: %s is %s version %d, but this gt supports up to %d - upgrade gt to use it",
+			ErrInvalidVersion, path, kind, version, current)
+	}
+	if version < 0 {
+		return fmt.Errorf("%w: %s has negative %s version %d", ErrInvalidVersion, path, kind, version)
+	}
+	return nil
+}
+
+// SchemaIssue is a problem ValidateTownConfig found in a config file.
+type SchemaIssue struct {
+	Path    string // The config file
+	Field   string // JSON path of the offending field, e.g. "role_agents.crew"; empty for the whole file
+	Problem string
+	Fix     string // What to change, if there's a clear answer
+}
+
+// String formats the issue as "path: field: problem (fix)".
+func (i SchemaIssue) String() string {
+	s := i.Path + ": "
+	if i.Field != "" {
+		s += i.Field + ": "
+	}
+	s += i.Problem
+	if i.Fix != "" {
+		s += " (" + i.Fix + ")"
+	}
+	return s
+}
+
+// SchemaMigration is a config file stored at an older schema version. It is
+// migrated whenever it is loaded; MigrateConfigFiles rewrites it.
+type SchemaMigration struct {
+	Path        string
+	Type        string // "town-settings", "rig-settings" or "agent-registry"
+	FromVersion int
+	ToVersion   int
+}
+
+// hookProviders are the accepted RuntimeHooksConfig.Provider values.
+var hookProviders = []string{"claude", "kimi", "none", "opencode"}
+
+// validResumeStyles are the accepted AgentPresetInfo.ResumeStyle values.
+var validResumeStyles = []string{"flag", "subcommand"}
+
+// ValidateTownConfig checks the config files of the town at townRoot - town
+// settings, the agent registry and each registered rig's settings - for
+// problems that would otherwise only show up when an agent is spawned: agent
+// names that resolve to nothing, agent definitions gt can't launch or resume,
+// and files from a newer gt. It also reports files stored at an older schema
+// version.
+func ValidateTownConfig(townRoot string) ([]SchemaIssue, []SchemaMigration) {
+	v := &configValidator{known: make(map[string]bool)}
+	for name := range builtinPresets {
+		v.known[string(name)] = true
+	}
+
+	registryPath := DefaultAgentRegistryPath(townRoot)
+	if registry, err := LoadAgentRegistryFile(registryPath); err != nil {
+		v.issue(registryPath, "", err.Error(), "")
+	} else {
+		v.checkRegistry(registryPath, registry)
+	}
+
+	townPath := TownSettingsPath(townRoot)
+	var town TownSettings
+	if v.readJSON(townPath, &town) {
+		v.noteVersion(townPath, "town-settings", town.Version, CurrentTownSettingsVersion)
+		if err := checkSchemaVersion(townPath, "town settings", town.Version, CurrentTownSettingsVersion); err != nil {
+			v.issue(townPath, "version", err.Error(), "")
+		}
+		if town.Type != "town-settings" && town.Type != "" {
+			v.issue(townPath, "type", fmt.Sprintf("is %q", town.Type), `set it to "town-settings"`)
+		}
+		v.checkTownSettings(townPath, &town)
+	}
+
+	rigs, err := LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		rigs = &RigsConfig{}
+	}
+	for _, rigName := range slices.Sorted(maps.Keys(rigs.Rigs)) {
+		rigPath := RigSettingsPath(filepath.Join(townRoot, rigName))
+		var rig RigSettings
+		if !v.readJSON(rigPath, &rig) {
+			continue
+		}
+		v.noteVersion(rigPath, "rig-settings", rig.Version, CurrentRigSettingsVersion)
+		if err := validateRigSettings(&rig); err != nil {
+			v.issue(rigPath, "", err.Error(), "")
+		}
+		v.checkRigSettings(rigPath, &rig)
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool { return v.issues[i].Path < v.issues[j].Path })
+	return v.issues, v.migrations
+}
+
+// MigrateConfigFiles rewrites each migration's file at the current schema
+// version.
+func MigrateConfigFiles(migrations []SchemaMigration) error {
+	for _, m := range migrations {
+		var err error
+		switch m.Type {
+		case "agent-registry":
+			var registry *AgentRegistry
+			if registry, err = LoadAgentRegistryFile(m.Path); err == nil {
+				err = SaveAgentRegistry(m.Path, registry)
+			}
+		case "rig-settings":
+			var settings *RigSettings
+			if settings, err = LoadRigSettings(m.Path); err == nil {
+				err = SaveRigSettings(m.Path, settings)
+			}
+		case "town-settings":
+			var settings *TownSettings
+			if settings, err = LoadOrCreateTownSettings(m.Path); err == nil {
+				err = SaveTownSettings(m.Path, settings)
+			}
+		default:
+			err = fmt.Errorf("unknown config type %q", m.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("migrating %s: %w", m.Path, err)
+		}
+	}
+	return nil
+}
+
+// configValidator accumulates the findings of ValidateTownConfig.
+type configValidator struct {
+	known      map[string]bool // Agent names defined anywhere in the town
+	issues     []SchemaIssue
+	migrations []SchemaMigration
+}
+
+func (v *configValidator) issue(path, field, problem, fix string) {
+	v.issues = append(v.issues, SchemaIssue{Path: path, Field: field, Problem: problem, Fix: fix})
+}
+
+// readJSON parses the file at path into dst as stored, without migrating
+// it, reporting unreadable and malformed files. It returns false for a
+// missing or unparsable file.
+func (v *configValidator) readJSON(path string, dst any) bool {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is constructed internally
+	if os.IsNotExist(err) {
+		return false
+	}
+	if err != nil {
+		v.issue(path, "", err.Error(), "")
+		return false
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		v.issue(path, "", "invalid JSON: "+err.Error(), "")
+		return false
+	}
+	return true
+}
+
+func (v *configValidator) noteVersion(path, typ string, version, current int) {
+	if version >= 0 && version < current {
+		v.migrations = append(v.migrations, SchemaMigration{Path: path, Type: typ, FromVersion: version, ToVersion: current})
+	}
+}
+
+// checkRegistry checks the agent definitions in settings/agents.json.
+func (v *configValidator) checkRegistry(path string, registry *AgentRegistry) {
+	// LoadAgentRegistryFile has migrated registry; the stored version
+	// is in the file
+	var stored AgentRegistry
+	if v.readJSON(path, &stored) {
+		v.noteVersion(path, "agent-registry", stored.Version, CurrentAgentRegistryVersion)
+	}
+	for _, name := range slices.Sorted(maps.Keys(registry.Agents)) {
+		v.known[name] = true
+		info := registry.Agents[name]
+		field := "agents." + name
+		if info == nil {
+			v.issue(path, field, "is null", "remove it or give it a command")
+			continue
+		}
+		if info.Command == "" {
+			v.issue(path, field+".command", "is missing", "set the agent's executable")
+		}
+		if info.ResumeStyle != "" && !slices.Contains(validResumeStyles, info.ResumeStyle) {
+			v.issue(path, field+".resume_style", fmt.Sprintf("invalid resume style %q", info.ResumeStyle),
+				`use "flag" or "subcommand", or resume_template for anything else`)
+		}
+		if info.ResumeStyle != "" && info.ResumeFlag == "" && info.ResumeTemplate == "" {
+			v.issue(path, field+".resume_flag", "is missing, so resume_style has nothing to pass",
+				"set the flag or subcommand that resumes a session")
+		}
+		if err := ValidateResumeTemplate(info.ResumeTemplate); err != nil {
+			v.issue(path, field+".resume_template", err.Error(),
+				"use only {command}, {args}, {resumeFlag} and {sessionID}")
+		}
+		if info.SupportsHooks && info.HooksDir == "" {
+			v.issue(path, field+".hooks_dir", "is missing but supports_hooks is set, so hooks can't be installed",
+				`set the directory the agent reads hook settings from (e.g. ".claude")`)
+		}
+	}
+}
+
+// checkAgents checks custom agent definitions (the "agents" map of town or
+// rig settings).
+func (v *configValidator) checkAgents(path string, agents map[string]*RuntimeConfig) {
+	for _, name := range slices.Sorted(maps.Keys(agents)) {
+		rc := agents[name]
+		field := "agents." + name
+		if rc == nil {
+			v.issue(path, field, "is null", "remove it or give it a command")
+			continue
+		}
+		if err := rc.Validate(); err != nil {
+			v.issue(path, field, err.Error(), "")
+		}
+		hooks := rc.Resolved().Hooks
+		switch {
+		case !slices.Contains(hookProviders, hooks.Provider):
+			// Neither installed nor treated as hookless, so the agent starts
+			// without hooks and without the gt prime fallback
+			v.issue(path, field+".hooks.provider", fmt.Sprintf("unknown hooks provider %q", hooks.Provider),
+				"use one of "+strings.Join(hookProviders, ", "))
+		case hooks.Provider != "none" && hooks.Dir == "":
+			v.issue(path, field+".hooks.dir", fmt.Sprintf("is missing for hooks provider %q", hooks.Provider),
+				`set it, or set hooks.provider to "none"`)
+		}
+	}
+}
+
+// checkAgentRef reports an agent name that isn't built in or defined in the
+// registry, the town settings or (for rig settings) the rig settings.
+func (v *configValidator) checkAgentRef(path, field, name string, local map[string]*RuntimeConfig) {
+	if name == "" || v.known[name] {
+		return
+	}
+	if _, ok := local[name]; ok {
+		return
+	}
+	known := maps.Clone(v.known)
+	for n := range local {
+		known[n] = true
+	}
+	v.issue(path, field, fmt.Sprintf("unknown agent %q", name),
+		"use one of "+strings.Join(slices.Sorted(maps.Keys(known)), ", ")+", or define it under \"agents\"")
+}
+
+func (v *configValidator) checkTownSettings(path string, s *TownSettings) {
+	v.checkAgents(path, s.Agents)
+	for name := range s.Agents {
+		v.known[name] = true
+	}
+	v.checkAgentRef(path, "default_agent", s.DefaultAgent, nil)
+	for _, role := range slices.Sorted(maps.Keys(s.RoleAgents)) {
+		v.checkAgentRef(path, "role_agents."+role, s.RoleAgents[role], nil)
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Roles)) {
+		if err := ValidateCustomRole(name, s.Roles[name]); err != nil {
+			v.issue(path, "roles."+name, err.Error(), "")
+			continue
+		}
+		v.checkAgentRef(path, "roles."+name+".agent", s.Roles[name].Agent, nil)
+	}
+	if err := s.Watchdog.Validate(); err != nil {
+		v.issue(path, "watchdog", err.Error(), "")
+	}
+	if err := s.Notify.Validate(); err != nil {
+		v.issue(path, "notify", err.Error(), "")
+	}
+	if err := s.AgentPolicy.Validate(); err != nil {
+		v.issue(path, "agent_policy", err.Error(), "")
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Layouts)) {
+		if err := s.Layouts[name].Validate(); err != nil {
+			v.issue(path, "layouts."+name, err.Error(), "")
+		}
+	}
+	for _, role := range slices.Sorted(maps.Keys(s.RoleLayouts)) {
+		if name := s.RoleLayouts[role]; s.Layouts[name] == nil {
+			v.issue(path, "role_layouts."+role, fmt.Sprintf("unknown layout %q", name), "define it under \"layouts\"")
+		}
+	}
+}
+
+func (v *configValidator) checkRigSettings(path string, s *RigSettings) {
+	v.checkAgents(path, s.Agents)
+	v.checkAgentRef(path, "agent", s.Agent, s.Agents)
+	for _, role := range slices.Sorted(maps.Keys(s.RoleAgents)) {
+		v.checkAgentRef(path, "role_agents."+role, s.RoleAgents[role], s.Agents)
+	}
+	if err := s.Runtime.Validate(); err != nil {
+		v.issue(path, "runtime", err.Error(), "")
+	}
+	if err := s.Notify.Validate(); err != nil {
+		v.issue(path, "notify", err.Error(), "")
+	}
+	if err := s.AgentPolicy.Validate(); err != nil {
+		v.issue(path, "agent_policy", err.Error(), "")
+	}
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateTownConfig(t *testing.T) {
+	townRoot := t.TempDir()
+	writeTestFile(t, TownSettingsPath(townRoot), `{
+  "default_agent": "kimmi",
+  "agents": {
+    "kimi-fast": {"command": "kimi", "args": ["--yolo"]},
+    "no-dir": {"command": "my-cli", "provider": "codex", "hooks": {"provider": "claude"}},
+    "odd-hooks": {"command": "my-cli", "hooks": {"provider": "custom"}}
+  },
+  "role_agents": {"crew": "kimi-fast", "witness": "my-agent"}
+}`)
+	writeTestFile(t, DefaultAgentRegistryPath(townRoot), `{"version": 1, "agents": {
+  "my-agent": {"command": "my-agent", "resume_flag": "--resume", "resume_style": "positional", "supports_hooks": true}
+}}`)
+	writeTestFile(t, filepath.Join(townRoot, "mayor", "rigs.json"), `{"version": 1, "rigs": {"gastown": {"git_url": "x"}}}`)
+	writeTestFile(t, RigSettingsPath(filepath.Join(townRoot, "gastown")), `{
+  "type": "rig-settings", "version": 1,
+  "agents": {"local": {"command": "claude"}},
+  "agent": "local",
+  "role_agents": {"polecat": "gone"}
+}`)
+
+	issues, migrations := ValidateTownConfig(townRoot)
+
+	got := make(map[string]string)
+	for _, i := range issues {
+		got[filepath.Base(filepath.Dir(filepath.Dir(i.Path)))+" "+i.Field] = i.String()
+	}
+	town := filepath.Base(townRoot)
+	want := []string{
+		town + " default_agent",
+		town + " agents.no-dir.hooks.dir",
+		town + " agents.odd-hooks.hooks.provider",
+		town + " agents.my-agent.resume_style",
+		town + " agents.my-agent.hooks_dir",
+		"gastown role_agents.polecat",
+	}
+	for _, key := range want {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing issue for %s; got %v", key, got)
+		}
+	}
+	if len(issues) != len(want) {
+		t.Errorf("got %d issues, want %d: %v", len(issues), len(want), got)
+	}
+	if msg := got[town+" default_agent"]; !strings.Contains(msg, `unknown agent "kimmi"`) || !strings.Contains(msg, "kimi-fast") {
+		t.Errorf("default_agent issue = %q, want the unknown name and the known agents", msg)
+	}
+
+	if len(migrations) != 1 || migrations[0].Type != "town-settings" || migrations[0].FromVersion != 0 {
+		t.Fatalf("migrations = %+v, want the unversioned town settings", migrations)
+	}
+	if err := MigrateConfigFiles(migrations); err != nil {
+		t.Fatalf("MigrateConfigFiles: %v", err)
+	}
+	if _, migrations := ValidateTownConfig(townRoot); len(migrations) != 0 {
+		t.Errorf("migrations after MigrateConfigFiles = %+v, want none", migrations)
+	}
+	settings, err := LoadOrCreateTownSettings(TownSettingsPath(townRoot))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.Type != "town-settings" || settings.Version != CurrentTownSettingsVersion || settings.DefaultAgent != "kimmi" {
+		t.Errorf("migrated settings = %+v", settings)
+	}
+}
+
+func TestLoadSettingsSchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	townPath := filepath.Join(dir, "town.json")
+	writeTestFile(t, townPath, `{"default_agent": "kimi"}`)
+	town, err := LoadOrCreateTownSettings(townPath)
+	if err != nil {
+		t.Fatalf("LoadOrCreateTownSettings: %v", err)
+	}
+	if town.Version != CurrentTownSettingsVersion || town.Type != "town-settings" {
+		t.Errorf("unversioned town settings loaded as version %d type %q", town.Version, town.Type)
+	}
+
+	writeTestFile(t, townPath, `{"type": "town-settings", "version": 99}`)
+	if _, err := LoadOrCreateTownSettings(townPath); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("LoadOrCreateTownSettings(newer) = %v, want ErrInvalidVersion", err)
+	}
+
+	rigPath := filepath.Join(dir, "rig.json")
+	writeTestFile(t, rigPath, `{"type": "rig-settings", "version": 99}`)
+	if _, err := LoadRigSettings(rigPath); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("LoadRigSettings(newer) = %v, want ErrInvalidVersion", err)
+	}
+
+	if len(townSettingsMigrations) != CurrentTownSettingsVersion {
+		t.Errorf("%d town settings migrations for version %d, want one per version", len(townSettingsMigrations), CurrentTownSettingsVersion)
+	}
+	if len(rigSettingsMigrations) != CurrentRigSettingsVersion {
+		t.Errorf("%d rig settings migrations for version %d, want one per version", len(rigSettingsMigrations), CurrentRigSettingsVersion)
+	}
+}
@@ -32,6 +32,9 @@ func TestMain(m *testing.M) {
 		}
 	}
 
+	// Keep the developer's own ~/.gastown/agents.d out of the registry
+	_ = os.Setenv(EnvAgentPresetDir, stubDir)
+
 	originalPath := os.Getenv("PATH")
 	_ = os.Setenv("PATH", stubDir+string(os.PathListSeparator)+originalPath)
 
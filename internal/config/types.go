@@ -2,11 +2,52 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/steveyegge/gastown/internal/sandbox"
+	"github.com/steveyegge/gastown/internal/secrets"
+)
+
+// ErrMCPConfigUnsupported indicates an MCP config was requested for an agent
+// whose preset has no MCPConfigFlag.
+var ErrMCPConfigUnsupported = errors.New("agent does not support MCP config")
+
+// ErrJSONOutputUnsupported indicates JSON output was requested for an agent
+// whose preset has no OutputJSONFlag.
+var ErrJSONOutputUnsupported = errors.New("agent does not support JSON output")
+
+// ErrModelUnsupported indicates a model was requested for an agent whose
+// preset has no ModelFlag.
+var ErrModelUnsupported = errors.New("agent does not support model selection")
+
+// ErrInstructionsNotFound indicates none of an agent's instruction file
+// candidates exist in the workspace.
+var ErrInstructionsNotFound = errors.New("no instruction file found")
+
+// ErrInvalidNice indicates a Nice value outside nice(1)'s -20..19 range.
+var ErrInvalidNice = errors.New("nice value out of range")
+
+// ErrInvalidEnvName indicates an Env key that isn't a valid shell variable name.
+var ErrInvalidEnvName = errors.New("invalid environment variable name")
+
+// Errors returned when resuming an agent session from its environment.
+var (
+	ErrNoSessionIDEnv    = errors.New("agent has no session ID environment variable")
+	ErrSessionIDUnset    = errors.New("session ID environment variable not set")
+	ErrResumeUnsupported = errors.New("agent does not support session resume")
+	ErrForkUnsupported   = errors.New("agent does not support session fork")
 )
 
 // TownConfig represents the main town identity (mayor/town.json).
@@ -68,6 +109,370 @@ type TownSettings struct {
 	// Agent addresses like "gastown/crew/jack" become "gastown.crew.jack@{domain}".
 	// Default: "gastown.local"
 	AgentEmailDomain string `json:"agent_email_domain,omitempty"`
+
+	// Handoff configures gt handoff behavior.
+	Handoff *HandoffConfig `json:"handoff,omitempty"`
+
+	// Scheduler caps concurrent agent sessions and lowers their priority.
+	Scheduler *SchedulerConfig `json:"scheduler,omitempty"`
+
+	// Watchdog configures how gt watchdog handles agents that die in
+	// their sessions.
+	Watchdog *WatchdogConfig `json:"watchdog,omitempty"`
+
+	// Notify sends events (bead done, merges, stuck or crashed agents,
+	// handoffs) to the desktop, Slack or webhooks. Rig settings can
+	// replace it for a rig's events.
+	Notify *NotifyConfig `json:"notify,omitempty"`
+
+	// Roles defines custom roles beyond the built-in ones, keyed by role
+	// name. gt handoff, gt session at and the health checks resolve them
+	// like built-in roles.
+	// Example: {"auditor": {"session": "gt-{rig}-auditor"}}
+	Roles map[string]*CustomRoleConfig `json:"roles,omitempty"`
+
+	// Layouts defines pane layouts by name, for gt layout apply.
+	// Example: {"dev": {"panes": [{"split": "right", "size": 40,
+	// "command": "gt logs -f --agent {agent}"}, {"split": "below", "of": 1}]}}
+	Layouts map[string]*PaneLayout `json:"layouts,omitempty"`
+
+	// RoleLayouts maps role names to the layout new sessions of the role
+	// open with (currently used by gt crew at).
+	// Example: {"crew": "dev"}
+	RoleLayouts map[string]string `json:"role_layouts,omitempty"`
+
+	// AgentPolicy restricts the flags agents are started with, e.g. no
+	// --dangerously-skip-permissions. Rigs can override it.
+	AgentPolicy *AgentPolicy `json:"agent_policy,omitempty"`
+}
+
+// CustomRoleConfig defines a custom role's session naming and restart.
+type CustomRoleConfig struct {
+	// Session is the tmux session name template. Placeholders: {rig},
+	// {name} and {role}. A template without {rig} is a town-level role.
+	// It must contain the role name (or {role}) so its sessions can't be
+	// mistaken for polecats.
+	// Example: "gt-{rig}-auditor", "hq-librarian"
+	Session string `json:"session"`
+
+	// WorkDir is the role's working directory template, with {town} as
+	// well. Default: "{town}/{rig}/{role}" ("{town}/{role}" for town-level
+	// roles), with "/{name}" appended when the session has a {name}.
+	WorkDir string `json:"work_dir,omitempty"`
+
+	// Agent is the agent preset or custom agent sessions of the role run.
+	// Default: the town's default agent.
+	Agent string `json:"agent,omitempty"`
+
+	// RestartCommand replaces the agent command when gt handoff respawns a
+	// session of the role. It runs in WorkDir with the same placeholders.
+	RestartCommand string `json:"restart_command,omitempty"`
+
+	// Aliases are extra names the role is addressed by (e.g. "aud").
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// SchedulerConfig limits the crew and polecat sessions a town (or, in rig
+// settings, a rig) runs at once. A start beyond the cap is queued, and the
+// daemon starts queued sessions as running ones exit.
+type SchedulerConfig struct {
+	// MaxConcurrentAgents caps the crew and polecat sessions running at once.
+	// Town-level agents (mayor, deacon, witness, refinery) don't count and
+	// are never queued. 0 means no cap.
+	MaxConcurrentAgents int `json:"max_concurrent_agents,omitempty"`
+
+	// Nice is the CPU niceness (-20..19) agent processes run at. Positive
+	// values yield the CPU to the rest of the machine. A rig's setting
+	// overrides the town's. 0 leaves the default priority.
+	Nice int `json:"nice,omitempty"`
+
+	// OOMScoreAdj (-1000..1000) makes agents likelier to be killed first
+	// when the machine runs out of memory. Linux only; a rig's setting
+	// overrides the town's. 0 leaves the default.
+	OOMScoreAdj int `json:"oom_score_adj,omitempty"`
+}
+
+// DefaultHandoffCooldown is the minimum time between handoffs of the same session.
+const DefaultHandoffCooldown = 10 * time.Second
+
+// DefaultHandoffGraceTimeout is how long handoff waits for processes to exit
+// after SIGTERM before escalating to SIGKILL.
+const DefaultHandoffGraceTimeout = 5 * time.Second
+
+// HandoffConfig represents handoff settings for a town.
+type HandoffConfig struct {
+	// Cooldown is the minimum time between handoffs of the same session.
+	// Protects sessions from runaway scripts that hand off in a loop.
+	// Format: Go duration string (e.g., "10s", "1m"). "0s" disables the check.
+	// Default: "10s"
+	Cooldown string `json:"cooldown,omitempty"`
+
+	// GraceTimeout is how long to wait for agent processes to exit after
+	// SIGTERM before sending SIGKILL when handoff kills a session or pane.
+	// Format: Go duration string (e.g., "5s", "30s").
+	// Default: "5s"
+	GraceTimeout string `json:"grace_timeout,omitempty"`
+}
+
+// GetCooldown returns the handoff cooldown as a time.Duration.
+// Returns DefaultHandoffCooldown if not configured or invalid.
+func (c *HandoffConfig) GetCooldown() time.Duration {
+	if c == nil || c.Cooldown == "" {
+		return DefaultHandoffCooldown
+	}
+	d, err := time.ParseDuration(c.Cooldown)
+	if err != nil || d < 0 {
+		return DefaultHandoffCooldown
+	}
+	return d
+}
+
+// GetGraceTimeout returns the handoff grace timeout as a time.Duration.
+// Returns DefaultHandoffGraceTimeout if not configured or invalid.
+func (c *HandoffConfig) GetGraceTimeout() time.Duration {
+	if c == nil || c.GraceTimeout == "" {
+		return DefaultHandoffGraceTimeout
+	}
+	d, err := time.ParseDuration(c.GraceTimeout)
+	if err != nil || d <= 0 {
+		return DefaultHandoffGraceTimeout
+	}
+	return d
+}
+
+// Watchdog policies: what gt watchdog does when an agent has exited but its
+// session is still open.
+const (
+	WatchdogResume = "resume" // Restart the agent in its last conversation
+	WatchdogFresh  = "fresh"  // Restart the agent with a new conversation
+	WatchdogNotify = "notify" // Only report the dead agent
+)
+
+// DefaultWatchdogInterval is how often gt watchdog polls agent sessions.
+const DefaultWatchdogInterval = 30 * time.Second
+
+// DefaultWatchdogMaxRestarts is how many times an hour gt watchdog restarts
+// the same session before it only notifies.
+const DefaultWatchdogMaxRestarts = 3
+
+// WatchdogConfig represents gt watchdog settings for a town.
+type WatchdogConfig struct {
+	// Policy is what to do with a dead agent: "resume", "fresh" or
+	// "notify". Resume starts fresh for agents that can't resume.
+	// Default: "resume"
+	Policy string `json:"policy,omitempty"`
+
+	// RolePolicies overrides Policy per role.
+	// Example: {"mayor": "notify", "polecat": "fresh"}
+	RolePolicies map[string]string `json:"role_policies,omitempty"`
+
+	// Interval is how often sessions are polled.
+	// Format: Go duration string (e.g., "30s", "2m").
+	// Default: "30s"
+	Interval string `json:"interval,omitempty"`
+
+	// MaxRestarts caps the restarts of one session in an hour; past it the
+	// watchdog only notifies, so an agent that crashes on start isn't
+	// restarted in a loop. Default: 3
+	MaxRestarts int `json:"max_restarts,omitempty"`
+
+	// MetricsAddr is the host:port the watchdog serves Prometheus metrics
+	// on, at /metrics. Empty disables the metrics endpoint.
+	// Example: "127.0.0.1:9464"
+	MetricsAddr string `json:"metrics_addr,omitempty"`
+
+	// ReportInterval is how often the watchdog writes a witness report of
+	// each rig with crew or polecat sessions (see gt witness report).
+	// Format: Go duration string (e.g., "1h"). Empty disables reports.
+	ReportInterval string `json:"report_interval,omitempty"`
+
+	// ReportLines is how much of each pane a witness report captures.
+	// Default: 40
+	ReportLines int `json:"report_lines,omitempty"`
+}
+
+// Validate checks the watchdog policies and intervals.
+func (c *WatchdogConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if !validWatchdogPolicy(c.Policy) {
+		return fmt.Errorf("invalid watchdog policy %q (want resume, fresh or notify)", c.Policy)
+	}
+	for role, policy := range c.RolePolicies {
+		if !validWatchdogPolicy(policy) {
+			return fmt.Errorf("invalid watchdog policy %q for role %s (want resume, fresh or notify)", policy, role)
+		}
+	}
+	if c.Interval != "" {
+		if d, err := time.ParseDuration(c.Interval); err != nil || d <= 0 {
+			return fmt.Errorf("invalid watchdog interval %q", c.Interval)
+		}
+	}
+	if c.MaxRestarts < 0 {
+		return fmt.Errorf("watchdog max_restarts must not be negative")
+	}
+	if c.MetricsAddr != "" {
+		if _, port, err := net.SplitHostPort(c.MetricsAddr); err != nil || port == "" {
+			return fmt.Errorf("invalid watchdog metrics_addr %q (want host:port)", c.MetricsAddr)
+		}
+	}
+	if c.ReportInterval != "" {
+		if d, err := time.ParseDuration(c.ReportInterval); err != nil || d < time.Minute {
+			return fmt.Errorf("invalid watchdog report_interval %q (want a duration of at least 1m)", c.ReportInterval)
+		}
+	}
+	if c.ReportLines < 0 {
+		return fmt.Errorf("watchdog report_lines must not be negative")
+	}
+	return nil
+}
+
+func validWatchdogPolicy(policy string) bool {
+	switch policy {
+	case "", WatchdogResume, WatchdogFresh, WatchdogNotify:
+		return true
+	}
+	return false
+}
+
+// PolicyFor returns the watchdog policy for a role.
+// Returns WatchdogResume if not configured.
+func (c *WatchdogConfig) PolicyFor(role string) string {
+	if c == nil {
+		return WatchdogResume
+	}
+	if policy := c.RolePolicies[role]; policy != "" {
+		return policy
+	}
+	if c.Policy != "" {
+		return c.Policy
+	}
+	return WatchdogResume
+}
+
+// GetInterval returns the watchdog poll interval as a time.Duration.
+// Returns DefaultWatchdogInterval if not configured or invalid.
+func (c *WatchdogConfig) GetInterval() time.Duration {
+	if c == nil || c.Interval == "" {
+		return DefaultWatchdogInterval
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return DefaultWatchdogInterval
+	}
+	return d
+}
+
+// GetReportInterval returns how often witness reports are written.
+// Returns 0 (no reports) if not configured or invalid.
+func (c *WatchdogConfig) GetReportInterval() time.Duration {
+	if c == nil || c.ReportInterval == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(c.ReportInterval)
+	if err != nil || d < time.Minute {
+		return 0
+	}
+	return d
+}
+
+// GetMaxRestarts returns the hourly restart cap for one session.
+// Returns DefaultWatchdogMaxRestarts if not configured.
+func (c *WatchdogConfig) GetMaxRestarts() int {
+	if c == nil || c.MaxRestarts == 0 {
+		return DefaultWatchdogMaxRestarts
+	}
+	return c.MaxRestarts
+}
+
+// Notification sink types.
+const (
+	NotifySinkDesktop = "desktop" // notify-send on Linux, osascript on macOS
+	NotifySinkSlack   = "slack"   // A Slack incoming webhook
+	NotifySinkWebhook = "webhook" // POST the notification as JSON
+)
+
+// NotifyEvents lists the events notifications can be sent for (see
+// internal/notify).
+var NotifyEvents = []string{"bead-done", "merged", "agent-stuck", "handoff", "agent-crash"}
+
+// DefaultNotifyStuckAfter is how long an agent must be stuck before an
+// agent-stuck notification is sent.
+const DefaultNotifyStuckAfter = 10 * time.Minute
+
+// NotifyConfig routes Gas Town events to notification sinks. In rig
+// settings it replaces the town's for the rig's events.
+type NotifyConfig struct {
+	// Sinks are where notifications go. Each gets every event it lists.
+	Sinks []NotifySink `json:"sinks,omitempty"`
+
+	// StuckAfter is how long an agent must have been waiting for input or
+	// stopped on an error before agent-stuck is sent (gt watchdog finds
+	// stuck agents). Format: Go duration string (e.g., "15m").
+	// Default: "10m"
+	StuckAfter string `json:"stuck_after,omitempty"`
+}
+
+// NotifySink is one notification destination.
+type NotifySink struct {
+	// Type is "desktop", "slack" or "webhook".
+	Type string `json:"type"`
+
+	// URL is the Slack incoming webhook or the webhook endpoint.
+	URL string `json:"url,omitempty"`
+
+	// Events limits the sink to these events (see NotifyEvents).
+	// Empty means all of them.
+	// Example: ["merged", "agent-crash"]
+	Events []string `json:"events,omitempty"`
+}
+
+// Validate checks the sinks and the stuck threshold.
+func (c *NotifyConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	for i, sink := range c.Sinks {
+		switch sink.Type {
+		case NotifySinkDesktop:
+		case NotifySinkSlack, NotifySinkWebhook:
+			if u, err := url.Parse(sink.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return fmt.Errorf("notify sink %d: invalid url %q (want an http(s) URL)", i, sink.URL)
+			}
+		default:
+			return fmt.Errorf("notify sink %d: invalid type %q (want desktop, slack or webhook)", i, sink.Type)
+		}
+		for _, event := range sink.Events {
+			if !slices.Contains(NotifyEvents, event) {
+				return fmt.Errorf("notify sink %d: unknown event %q (want one of %s)", i, event, strings.Join(NotifyEvents, ", "))
+			}
+		}
+	}
+	if c.StuckAfter != "" {
+		if d, err := time.ParseDuration(c.StuckAfter); err != nil || d <= 0 {
+			return fmt.Errorf("invalid notify stuck_after %q", c.StuckAfter)
+		}
+	}
+	return nil
+}
+
+// GetStuckAfter returns how long an agent must be stuck before agent-stuck
+// is sent. Returns DefaultNotifyStuckAfter if not configured or invalid.
+func (c *NotifyConfig) GetStuckAfter() time.Duration {
+	if c == nil || c.StuckAfter == "" {
+		return DefaultNotifyStuckAfter
+	}
+	d, err := time.ParseDuration(c.StuckAfter)
+	if err != nil || d <= 0 {
+		return DefaultNotifyStuckAfter
+	}
+	return d
+}
+
+// Wants reports whether the sink takes event.
+func (s NotifySink) Wants(event string) bool {
+	return len(s.Events) == 0 || slices.Contains(s.Events, event)
 }
 
 // NewTownSettings creates a new TownSettings with defaults.
@@ -167,6 +572,14 @@ type RigEntry struct {
 	LocalRepo   string       `json:"local_repo,omitempty"`
 	AddedAt     time.Time    `json:"added_at"`
 	BeadsConfig *BeadsConfig `json:"beads,omitempty"`
+
+	// Host is the ssh destination (user@host or a ~/.ssh/config alias) the
+	// rig's sessions run on. Empty for a rig on this machine.
+	Host string `json:"host,omitempty"`
+
+	// RemotePath is the rig directory on Host. Empty means the same path as
+	// the rig's local directory.
+	RemotePath string `json:"remote_path,omitempty"`
 }
 
 // BeadsConfig represents beads configuration for a rig.
@@ -216,6 +629,8 @@ type RigSettings struct {
 	Namepool   *NamepoolConfig   `json:"namepool,omitempty"`    // polecat name pool settings
 	Crew       *CrewConfig       `json:"crew,omitempty"`        // crew startup settings
 	Workflow   *WorkflowConfig   `json:"workflow,omitempty"`    // workflow settings
+	Scheduler  *SchedulerConfig  `json:"scheduler,omitempty"`   // per-rig agent cap and priority
+	Notify     *NotifyConfig     `json:"notify,omitempty"`      // replaces the town's notification sinks for this rig
 	Runtime    *RuntimeConfig    `json:"runtime,omitempty"`     // LLM runtime settings (deprecated: use Agent)
 
 	// Agent selects which agent preset to use for this rig.
@@ -236,6 +651,9 @@ type RigSettings struct {
 	// Overrides TownSettings.RoleAgents for this specific rig.
 	// Example: {"witness": "claude-haiku", "polecat": "claude-sonnet"}
 	RoleAgents map[string]string `json:"role_agents,omitempty"`
+
+	// AgentPolicy overrides the town's agent policy for this rig.
+	AgentPolicy *AgentPolicy `json:"agent_policy,omitempty"`
 }
 
 // CrewConfig represents crew workspace settings for a rig.
@@ -257,7 +675,8 @@ type CrewConfig struct {
 // without modifying startup code.
 type RuntimeConfig struct {
 	// Provider selects runtime-specific defaults and integration behavior.
-	// Known values: "claude", "codex", "generic". Default: "claude".
+	// Known values: "claude", "codex", "opencode", "kimi", "gemini", "aider",
+	// "qwen", "generic". Default: "claude".
 	Provider string `json:"provider,omitempty"`
 
 	// Command is the CLI command to invoke (e.g., "claude", "aider").
@@ -270,10 +689,19 @@ type RuntimeConfig struct {
 	Args []string `json:"args"`
 
 	// Env are environment variables to set when starting the agent.
-	// These are merged with the standard GT_* variables.
-	// Used for agent-specific configuration like OPENCODE_PERMISSION.
+	// These are merged with the standard GT_* variables, and BuildCommand
+	// sets them with an env prefix, so every launch of the agent gets them.
+	// Used for agent-specific configuration like OPENCODE_PERMISSION, or
+	// a custom agent's model override (e.g. {"ANTHROPIC_MODEL": "..."}).
 	Env map[string]string `json:"env,omitempty"`
 
+	// Secrets maps environment variable names to gt secret names, e.g.
+	// {"MOONSHOT_API_KEY": "moonshot"}. Like Env they're set on every launch
+	// of the agent, but each value is read from the secrets store (gt secret
+	// get) when the agent starts, so the key itself never appears in config,
+	// the launch command or the pane's shell.
+	Secrets map[string]string `json:"secrets,omitempty"`
+
 	// InitialPrompt is an optional first message to send after startup.
 	// For claude, this is passed as the prompt argument.
 	// Empty by default (hooks handle context).
@@ -281,7 +709,7 @@ type RuntimeConfig struct {
 
 	// PromptMode controls how prompts are passed to the runtime.
 	// Supported values: "arg" (append prompt arg), "none" (ignore prompt).
-	// Default: "arg" for claude/generic, "none" for codex.
+	// Default: "arg" for claude/generic, "none" for codex/gemini.
 	PromptMode string `json:"prompt_mode,omitempty"`
 
 	// Session config controls environment integration for runtime session IDs.
@@ -295,6 +723,78 @@ type RuntimeConfig struct {
 
 	// Instructions controls the per-workspace instruction file name.
 	Instructions *RuntimeInstructionsConfig `json:"instructions,omitempty"`
+
+	// MCPConfig is the path to a Model Context Protocol server config file.
+	// Passed to the agent via its preset's MCPConfigFlag.
+	// Empty by default (agent uses its own MCP configuration).
+	MCPConfig string `json:"mcp_config,omitempty"`
+
+	// JSONOutput launches the agent in its structured/streaming JSON output
+	// mode, via its preset's OutputJSONFlag, for programmatic consumption.
+	JSONOutput bool `json:"json_output,omitempty"`
+
+	// Model selects the agent's model via its preset's ModelFlag.
+	// Empty by default (agent uses its own default model).
+	Model string `json:"model,omitempty"`
+
+	// WorkingDir pins the agent to a specific worktree: the session is
+	// started in this directory. Empty means the launcher's default.
+	WorkingDir string `json:"working_dir,omitempty"`
+
+	// PrimingPrompt is sent into the session once the agent is ready, to
+	// kick off work (e.g., "Resume the molecule on your hook."). Overrides
+	// the preset's; empty means no prompt is sent.
+	PrimingPrompt string `json:"priming_prompt,omitempty"`
+
+	// ResumeFlag overrides the preset's ResumeFlag for this invocation, for
+	// agent CLI versions that renamed it. Empty means the preset's flag.
+	ResumeFlag string `json:"resume_flag,omitempty"`
+
+	// LoginShell wraps the agent command in the user's login shell
+	// ($SHELL -l -c '<command>') so it inherits PATH entries set in shell
+	// profiles. Fixes "command not found" when tmux starts a non-login shell.
+	LoginShell bool `json:"login_shell,omitempty"`
+
+	// Nice launches the agent at this niceness (nice -n) so background
+	// sessions yield the CPU to foreground work. 0 leaves priority alone;
+	// negative values usually need root. Ignored, with a warning, on Windows.
+	Nice int `json:"nice,omitempty"`
+
+	// Sandbox confines the agent's filesystem writes to its worktree and
+	// its network access to allow-listed API hosts (see gt sandbox exec).
+	Sandbox *SandboxConfig `json:"sandbox,omitempty"`
+
+	// policy is the agent policy the config was resolved under, applied
+	// again to resume commands; policyAgent is the agent name it was
+	// resolved for. policyErr is the violation Validate reports under an
+	// "enforce": "reject" policy.
+	policy      *AgentPolicy
+	policyAgent string
+	policyErr   error
+}
+
+// SandboxConfig confines an agent launched with bwrap (Linux) or
+// sandbox-exec (macOS). The agent may write only to the directory it is
+// started in, the temp directory, its preset's state directories and
+// Writable, and reach only its preset's API hosts and AllowHosts.
+//
+// Example:
+//
+//	"sandbox": {
+//	  "enabled": true,
+//	  "writable": ["~/.cache/uv"],
+//	  "allow_hosts": ["github.com", "*.githubusercontent.com"]
+//	}
+type SandboxConfig struct {
+	// Enabled wraps the agent command in the sandbox.
+	Enabled bool `json:"enabled"`
+
+	// Writable are further absolute or ~/ paths the agent may write.
+	Writable []string `json:"writable,omitempty"`
+
+	// AllowHosts are further hosts the agent may reach over HTTP(S);
+	// "*.example.com" allows subdomains.
+	AllowHosts []string `json:"allow_hosts,omitempty"`
 }
 
 // RuntimeSessionConfig configures how Gas Town discovers runtime session IDs.
@@ -336,6 +836,46 @@ type RuntimeTmuxConfig struct {
 type RuntimeInstructionsConfig struct {
 	// File is the instruction filename (e.g., "CLAUDE.md", "AGENTS.md").
 	File string `json:"file,omitempty"`
+
+	// Seed is a file whose contents seed File in a new crew workspace that
+	// lacks one. Relative paths resolve against the town root. Empty seeds
+	// a short stub.
+	Seed string `json:"seed,omitempty"`
+}
+
+// Shared instruction files searched after an agent's own, so one file can
+// serve every agent in a repo.
+const (
+	SharedInstructionsFile  = "AGENTS.md"
+	GastownInstructionsFile = ".gastown/instructions.md"
+)
+
+// InstructionCandidates returns the instruction files searched for rc, in
+// order: its own Instructions.File (or the provider default), then
+// SharedInstructionsFile, then GastownInstructionsFile, without duplicates.
+func (rc *RuntimeConfig) InstructionCandidates() []string {
+	candidates := []string{rc.Resolved().Instructions.File}
+	for _, file := range []string{SharedInstructionsFile, GastownInstructionsFile} {
+		if file != candidates[0] {
+			candidates = append(candidates, file)
+		}
+	}
+	return candidates
+}
+
+// ResolveInstructions returns the path of the first of rc's
+// InstructionCandidates that exists as a file in dir (symlinks are
+// followed). Fails with ErrInstructionsNotFound naming the candidates
+// searched if none do.
+func ResolveInstructions(rc *RuntimeConfig, dir string) (string, error) {
+	candidates := rc.InstructionCandidates()
+	for _, file := range candidates {
+		path := filepath.Join(dir, file)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w in %s (searched %s)", ErrInstructionsNotFound, dir, strings.Join(candidates, ", "))
 }
 
 // DefaultRuntimeConfig returns a RuntimeConfig with sensible defaults.
@@ -343,19 +883,140 @@ func DefaultRuntimeConfig() *RuntimeConfig {
 	return normalizeRuntimeConfig(&RuntimeConfig{Provider: "claude"})
 }
 
-// BuildCommand returns the full command line string.
-// For use with tmux SendKeys.
-func (rc *RuntimeConfig) BuildCommand() string {
-	resolved := normalizeRuntimeConfig(rc)
+// Clone returns a deep copy of rc: slices, maps and nested configs are
+// copied, so the clone can be mutated without affecting rc. Nil-ness of
+// each field is preserved. Clone of a nil config is nil.
+func (rc *RuntimeConfig) Clone() *RuntimeConfig {
+	if rc == nil {
+		return nil
+	}
 
-	cmd := resolved.Command
-	args := resolved.Args
+	clone := *rc
+	if rc.Args != nil {
+		clone.Args = append([]string{}, rc.Args...)
+	}
+	if rc.Env != nil {
+		clone.Env = make(map[string]string, len(rc.Env))
+		for k, v := range rc.Env {
+			clone.Env[k] = v
+		}
+	}
+	if rc.Secrets != nil {
+		clone.Secrets = make(map[string]string, len(rc.Secrets))
+		for k, v := range rc.Secrets {
+			clone.Secrets[k] = v
+		}
+	}
+	if rc.Session != nil {
+		session := *rc.Session
+		clone.Session = &session
+	}
+	if rc.Hooks != nil {
+		hooks := *rc.Hooks
+		clone.Hooks = &hooks
+	}
+	if rc.Tmux != nil {
+		tmux := *rc.Tmux
+		if rc.Tmux.ProcessNames != nil {
+			tmux.ProcessNames = append([]string{}, rc.Tmux.ProcessNames...)
+		}
+		clone.Tmux = &tmux
+	}
+	if rc.Instructions != nil {
+		instructions := *rc.Instructions
+		clone.Instructions = &instructions
+	}
+	if rc.Sandbox != nil {
+		sandbox := *rc.Sandbox
+		sandbox.Writable = slices.Clone(rc.Sandbox.Writable)
+		sandbox.AllowHosts = slices.Clone(rc.Sandbox.AllowHosts)
+		clone.Sandbox = &sandbox
+	}
+	return &clone
+}
 
-	// Combine command and args
-	if len(args) > 0 {
-		return cmd + " " + strings.Join(args, " ")
+// Resolved returns a copy of rc with all provider defaults filled in
+// (session env vars, hooks, tmux heuristics, instructions file), matching
+// what BuildCommand uses at launch. rc itself is not modified.
+func (rc *RuntimeConfig) Resolved() *RuntimeConfig {
+	return normalizeRuntimeConfig(fillRuntimeDefaults(rc))
+}
+
+// Validate checks that the agent backing this config supports every
+// option requested on it. Unsupported options are reported as errors
+// rather than silently dropped from the generated command.
+func (rc *RuntimeConfig) Validate() error {
+	if rc == nil {
+		return nil
 	}
-	return cmd
+	if rc.policyErr != nil {
+		return rc.policyErr
+	}
+	if rc.MCPConfig != "" {
+		info := presetForRuntimeConfig(rc)
+		if info == nil || info.MCPConfigFlag == "" {
+			return fmt.Errorf("%w: %s", ErrMCPConfigUnsupported, rc.Command)
+		}
+	}
+	if rc.JSONOutput {
+		info := presetForRuntimeConfig(rc)
+		if info == nil || info.OutputJSONFlag == "" {
+			return fmt.Errorf("%w: %s", ErrJSONOutputUnsupported, rc.Command)
+		}
+	}
+	if rc.Model != "" {
+		info := presetForRuntimeConfig(rc)
+		if info == nil || info.ModelFlag == "" {
+			return fmt.Errorf("%w: %s", ErrModelUnsupported, rc.Command)
+		}
+	}
+	if rc.Nice < -20 || rc.Nice > 19 {
+		return fmt.Errorf("%w: %d (want -20..19)", ErrInvalidNice, rc.Nice)
+	}
+	if rc.Sandbox != nil {
+		for _, path := range rc.Sandbox.Writable {
+			if err := sandbox.ValidPath(path); err != nil {
+				return err
+			}
+		}
+		for _, host := range rc.Sandbox.AllowHosts {
+			if err := sandbox.ValidHost(host); err != nil {
+				return err
+			}
+		}
+	}
+	for k := range rc.Env {
+		if !isEnvName(k) {
+			return fmt.Errorf("%w: %q", ErrInvalidEnvName, k)
+		}
+	}
+	for k, name := range rc.Secrets {
+		if !isEnvName(k) {
+			return fmt.Errorf("%w: %q", ErrInvalidEnvName, k)
+		}
+		if !secrets.ValidName(name) {
+			return fmt.Errorf("%w: %q (for %s)", secrets.ErrInvalidName, name, k)
+		}
+	}
+	return nil
+}
+
+// isEnvName reports whether name is a valid shell variable name.
+func isEnvName(name string) bool {
+	for i, c := range name {
+		if c != '_' && !('A' <= c && c <= 'Z') && !('a' <= c && c <= 'z') && (i == 0 || !('0' <= c && c <= '9')) {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// BuildCommand returns the full command line string.
+// For use with tmux SendKeys. Env is set with an env(1) prefix.
+// Options the agent doesn't support are omitted; call Validate first to detect them.
+func (rc *RuntimeConfig) BuildCommand() string {
+	resolved := normalizeRuntimeConfig(rc)
+	return resolved.wrapCommand(resolved.envPrefix() + resolved.commandLine())
 }
 
 // BuildCommandWithPrompt returns the full command line with an initial prompt.
@@ -363,7 +1024,7 @@ func (rc *RuntimeConfig) BuildCommand() string {
 // If prompt is provided, it overrides the config's InitialPrompt.
 func (rc *RuntimeConfig) BuildCommandWithPrompt(prompt string) string {
 	resolved := normalizeRuntimeConfig(rc)
-	base := resolved.BuildCommand()
+	base := resolved.commandLine()
 
 	// Use provided prompt or fall back to config
 	p := prompt
@@ -372,17 +1033,132 @@ func (rc *RuntimeConfig) BuildCommandWithPrompt(prompt string) string {
 	}
 
 	if p == "" || resolved.PromptMode == "none" {
-		return base
+		return resolved.wrapCommand(resolved.envPrefix() + base)
 	}
 
 	// Quote the prompt for shell safety
-	return base + " " + quoteForShell(p)
+	return resolved.wrapCommand(resolved.envPrefix() + base + " " + quoteForShell(p))
+}
+
+// envPrefix returns an env(1) invocation setting Env and Secrets, sorted by
+// name, to put before the command line ("" without either). It goes inside the launch
+// wrappers so a login shell's profile can't override it.
+func (rc *RuntimeConfig) envPrefix() string {
+	if len(rc.Env) == 0 && len(rc.Secrets) == 0 {
+		return ""
+	}
+	assignments := make([]string, 0, len(rc.Env)+len(rc.Secrets))
+	for k, v := range rc.Env {
+		assignments = append(assignments, k+"="+ShellQuote(v))
+	}
+	for k, name := range rc.Secrets {
+		// Expanded by the pane's shell at launch; names are shell-safe (Validate)
+		assignments = append(assignments, k+`="$(gt secret get `+name+`)"`)
+	}
+	sort.Strings(assignments)
+	return "env " + strings.Join(assignments, " ") + " "
+}
+
+// SecretEnv reads Secrets from the secrets store, for launches that exec
+// the agent directly instead of through a shell that runs gt secret get.
+func (rc *RuntimeConfig) SecretEnv() (map[string]string, error) {
+	if rc == nil || len(rc.Secrets) == 0 {
+		return nil, nil
+	}
+	store, err := secrets.Open()
+	if err != nil {
+		return nil, err
+	}
+	env := make(map[string]string, len(rc.Secrets))
+	for k, name := range rc.Secrets {
+		value, err := store.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("secret for %s: %w", k, err)
+		}
+		env[k] = value
+	}
+	return env, nil
+}
+
+// commandLine joins the command and args of a normalized config.
+func (rc *RuntimeConfig) commandLine() string {
+	cmd := rc.Command
+	args := rc.optionArgs(rc.Args, true)
+
+	// Combine command and args
+	if len(args) > 0 {
+		return cmd + " " + strings.Join(args, " ")
+	}
+	return cmd
+}
+
+// wrapCommand applies launch wrappers (e.g., LoginShell, Sandbox, Nice) around a command line.
+func (rc *RuntimeConfig) wrapCommand(cmd string) string {
+	if rc.LoginShell {
+		// $SHELL is expanded by the pane's shell at launch time.
+		cmd = "${SHELL:-/bin/sh} -l -c " + ShellQuote(cmd)
+	}
+	if rc.Sandbox != nil && rc.Sandbox.Enabled {
+		if launchGOOS == "windows" {
+			sandboxUnsupportedWarning.Do(func() {
+				fmt.Fprintln(os.Stderr, "warning: ignoring sandbox: not supported on Windows")
+			})
+		} else {
+			cmd = rc.sandboxPrefix() + "/bin/sh -c " + ShellQuote(cmd)
+		}
+	}
+	if rc.Nice != 0 {
+		if launchGOOS == "windows" {
+			niceUnsupportedWarning.Do(func() {
+				fmt.Fprintf(os.Stderr, "warning: ignoring nice=%d: not supported on Windows\n", rc.Nice)
+			})
+		} else {
+			cmd = fmt.Sprintf("nice -n %d %s", rc.Nice, cmd)
+		}
+	}
+	return cmd
+}
+
+// launchGOOS is the platform agent commands are built for; a variable so
+// tests can build Windows commands.
+var launchGOOS = runtime.GOOS
+
+// niceUnsupportedWarning warns once per process that Nice is ignored.
+var niceUnsupportedWarning sync.Once
+
+// sandboxUnsupportedWarning warns once per process that Sandbox is ignored.
+var sandboxUnsupportedWarning sync.Once
+
+// sandboxPrefix returns the gt sandbox exec invocation, up to the "--"
+// ahead of the command, confining the agent to the preset's state
+// directories and API hosts plus the Sandbox config's. The sandbox's
+// worktree is the directory the pane starts in (WorkingDir if set).
+func (rc *RuntimeConfig) sandboxPrefix() string {
+	var writable, hosts []string
+	if info := presetForRuntimeConfig(rc); info != nil {
+		writable = append(writable, info.SandboxWritable...)
+		hosts = append(hosts, info.SandboxHosts...)
+	}
+	writable = append(writable, rc.Sandbox.Writable...)
+	hosts = append(hosts, rc.Sandbox.AllowHosts...)
+
+	prefix := "gt sandbox exec"
+	if rc.WorkingDir != "" {
+		prefix += " --dir " + ShellQuote(rc.WorkingDir)
+	}
+	for _, path := range writable {
+		prefix += " --writable " + ShellQuote(path)
+	}
+	for _, host := range hosts {
+		prefix += " --allow-host " + ShellQuote(host)
+	}
+	return prefix + " -- "
 }
 
 // BuildArgsWithPrompt returns the runtime command and args suitable for exec.
 func (rc *RuntimeConfig) BuildArgsWithPrompt(prompt string) []string {
 	resolved := normalizeRuntimeConfig(rc)
-	args := append([]string{resolved.Command}, resolved.Args...)
+	args := append([]string{resolved.Command}, resolved.optionArgs(resolved.Args, false)...)
 
 	p := prompt
 	if p == "" {
@@ -396,6 +1172,105 @@ func (rc *RuntimeConfig) BuildArgsWithPrompt(prompt string) []string {
 	return args
 }
 
+// BuildHeadlessArgs returns the runtime command and args for a one-shot,
+// non-interactive run of prompt (gt sling --headless), suitable for exec.
+// The preset's NonInteractive settings place the subcommand and prompt
+// flag; presets without them are natively non-interactive like Claude and
+// take --print. Commands with no preset have no known headless form.
+func (rc *RuntimeConfig) BuildHeadlessArgs(prompt string) ([]string, error) {
+	resolved := normalizeRuntimeConfig(rc)
+	info := presetForRuntimeConfig(resolved)
+	if info == nil {
+		return nil, fmt.Errorf("no non-interactive mode known for %s; define it as an agent with non_interactive settings", resolved.Command)
+	}
+
+	args := []string{resolved.Command}
+	promptFlag := []string{"--print"}
+	if ni := info.NonInteractive; ni != nil {
+		args = append(args, strings.Fields(ni.Subcommand)...)
+		promptFlag = strings.Fields(ni.PromptFlag)
+	}
+	args = append(args, resolved.optionArgs(resolved.Args, false)...)
+	args = append(args, promptFlag...)
+	return append(args, prompt), nil
+}
+
+// optionArgs returns base with the preset flags for per-invocation options
+// (e.g., MCPConfig, JSONOutput, Model) appended. Values are shell-quoted when shell is true.
+// The base slice is never mutated.
+func (rc *RuntimeConfig) optionArgs(base []string, shell bool) []string {
+	quote := func(s string) string { return s }
+	if shell {
+		quote = ShellQuote
+	}
+	args := append([]string(nil), base...)
+	if rc.MCPConfig != "" {
+		if info := presetForRuntimeConfig(rc); info != nil && info.MCPConfigFlag != "" {
+			args = mergeArgs(args, []string{info.MCPConfigFlag, quote(rc.MCPConfig)})
+		}
+	}
+	if rc.JSONOutput {
+		if info := presetForRuntimeConfig(rc); info != nil && info.OutputJSONFlag != "" {
+			args = mergeArgs(args, strings.Fields(info.OutputJSONFlag))
+		}
+	}
+	if rc.Model != "" {
+		if info := presetForRuntimeConfig(rc); info != nil && info.ModelFlag != "" {
+			args = mergeArgs(args, []string{info.ModelFlag, quote(rc.Model)})
+		}
+	}
+	return args
+}
+
+// mergeArgs applies override flags to base without duplicating them.
+// An override flag already present in base replaces that occurrence (and its
+// value, if the override carries one); new flags and bare arguments are
+// appended. A flag is treated as valued when the next override token doesn't
+// start with "-", or when written as --flag=value. base is not modified.
+func mergeArgs(base, overrides []string) []string {
+	result := append([]string(nil), base...)
+	for i := 0; i < len(overrides); i++ {
+		group := overrides[i : i+1]
+		if isFlagArg(overrides[i]) && !strings.Contains(overrides[i], "=") &&
+			i+1 < len(overrides) && !isFlagArg(overrides[i+1]) {
+			group = overrides[i : i+2]
+			i++
+		}
+		result = replaceArgGroup(result, group)
+	}
+	return result
+}
+
+// replaceArgGroup replaces the first occurrence of group's flag in args with
+// group, or appends group if the flag isn't present (or group is a bare value).
+func replaceArgGroup(args, group []string) []string {
+	if !isFlagArg(group[0]) {
+		return append(args, group...)
+	}
+	flag, _, _ := strings.Cut(group[0], "=")
+	valued := len(group) == 2 || strings.Contains(group[0], "=")
+
+	for i, arg := range args {
+		name, _, hasEq := strings.Cut(arg, "=")
+		if name != flag {
+			continue
+		}
+		end := i + 1
+		if valued && !hasEq && end < len(args) && !isFlagArg(args[end]) {
+			end++ // drop the old value too
+		}
+		merged := append([]string(nil), args[:i]...)
+		merged = append(merged, group...)
+		return append(merged, args[end:]...)
+	}
+	return append(args, group...)
+}
+
+// isFlagArg reports whether arg looks like a command-line flag.
+func isFlagArg(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-'
+}
+
 func normalizeRuntimeConfig(rc *RuntimeConfig) *RuntimeConfig {
 	if rc == nil {
 		rc = &RuntimeConfig{}
@@ -480,6 +1355,12 @@ func defaultRuntimeCommand(provider string) string {
 		return "opencode"
 	case "kimi":
 		return "kimi"
+	case "gemini":
+		return "gemini"
+	case "aider":
+		return "aider"
+	case "qwen":
+		return "qwen"
 	case "generic":
 		return ""
 	default:
@@ -518,6 +1399,12 @@ func defaultRuntimeArgs(provider string) []string {
 		return []string{"--dangerously-skip-permissions"}
 	case "kimi":
 		return []string{"--yolo"}
+	case "gemini":
+		return []string{"--approval-mode", "yolo"}
+	case "aider":
+		return []string{"--yes-always", "--no-gitignore", "--no-check-update", "--read", "CONVENTIONS.md", "--read", "AGENTS.md"}
+	case "qwen":
+		return []string{"--yolo"}
 	default:
 		return nil
 	}
@@ -531,6 +1418,16 @@ func defaultPromptMode(provider string) string {
 		return "none"
 	case "kimi":
 		return "arg"
+	case "gemini":
+		// A positional prompt makes Gemini CLI answer once and exit;
+		// the beacon is nudged into the interactive session instead.
+		return "none"
+	case "aider":
+		// Aider takes positional arguments as files to edit.
+		return "none"
+	case "qwen":
+		// Qwen Code is a Gemini CLI fork: a positional prompt answers once.
+		return "none"
 	default:
 		return "arg"
 	}
@@ -543,6 +1440,15 @@ func defaultSessionIDEnv(provider string) string {
 	if provider == "kimi" {
 		return "KIMI_SESSION_ID"
 	}
+	if provider == "gemini" {
+		return "GEMINI_SESSION_ID"
+	}
+	if provider == "aider" {
+		return "AIDER_CHAT_HISTORY_FILE"
+	}
+	if provider == "qwen" {
+		return "QWEN_SESSION_ID"
+	}
 	return ""
 }
 
@@ -564,6 +1470,10 @@ func defaultHooksProvider(provider string) string {
 		return "opencode"
 	case "kimi":
 		return "kimi"
+	case "gemini":
+		// No Gemini hook installer yet: leave hooks off so startup falls
+		// back to nudging gt prime into the session.
+		return "none"
 	default:
 		return "none"
 	}
@@ -577,6 +1487,10 @@ func defaultHooksDir(provider string) string {
 		return ".opencode/plugin"
 	case "kimi":
 		return ".kimi"
+	case "gemini":
+		return ".gemini"
+	case "qwen":
+		return ".qwen"
 	default:
 		return ""
 	}
@@ -590,6 +1504,8 @@ func defaultHooksFile(provider string) string {
 		return "gastown.js"
 	case "kimi":
 		return "settings.json"
+	case "gemini", "qwen":
+		return "settings.json"
 	default:
 		return ""
 	}
@@ -607,6 +1523,17 @@ func defaultProcessNames(provider, command string) []string {
 	if provider == "kimi" {
 		return []string{"kimi"}
 	}
+	if provider == "gemini" {
+		return []string{"gemini"}
+	}
+	if provider == "qwen" {
+		return []string{"qwen", "node"}
+	}
+	if provider == "aider" {
+		// Aider is a Python entry point; pane_current_command shows
+		// "aider" or the interpreter depending on how it was installed.
+		return []string{"aider", "python", "python3"}
+	}
 	if command != "" {
 		return []string{filepath.Base(command)}
 	}
@@ -622,6 +1549,9 @@ func defaultReadyPromptPrefix(provider string) string {
 		// Kimi uses > as the prompt character
 		return "> "
 	}
+	if provider == "aider" {
+		return "> "
+	}
 	return ""
 }
 
@@ -643,6 +1573,19 @@ func defaultReadyDelayMs(provider string) int {
 		// because its TUI uses special characters for the prompt.
 		return 8000
 	}
+	if provider == "gemini" {
+		// Gemini draws its prompt inside a bordered input box, so
+		// prefix matching is unreliable; wait for the TUI instead.
+		return 8000
+	}
+	if provider == "qwen" {
+		// Qwen Code shares Gemini CLI's bordered input box.
+		return 8000
+	}
+	if provider == "aider" {
+		// Aider builds its repo map before showing the prompt.
+		return 5000
+	}
 	return 0
 }
 
@@ -656,6 +1599,15 @@ func defaultInstructionsFile(provider string) string {
 	if provider == "kimi" {
 		return "AGENTS.md"
 	}
+	if provider == "gemini" {
+		return "GEMINI.md"
+	}
+	if provider == "qwen" {
+		return "QWEN.md"
+	}
+	if provider == "aider" {
+		return "CONVENTIONS.md"
+	}
 	return "CLAUDE.md"
 }
 
@@ -11,11 +11,14 @@ import (
 
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -27,6 +30,8 @@ var (
 	ErrInvalidCrewName = errors.New("invalid crew name")
 	ErrSessionRunning  = errors.New("session already running")
 	ErrSessionNotFound = errors.New("session not found")
+	ErrPaused          = errors.New("crew worker is paused")
+	ErrNotPaused       = errors.New("crew worker is not paused")
 )
 
 // StartOptions configures crew session startup.
@@ -51,6 +56,20 @@ type StartOptions struct {
 
 	// AgentOverride specifies an alternate agent alias (e.g., for testing).
 	AgentOverride string
+
+	// Env holds extra environment variables for the session (e.g., from
+	// rig.yaml). The standard GT_* variables take precedence.
+	Env map[string]string
+}
+
+// AddOptions configures crew workspace creation.
+type AddOptions struct {
+	// CreateBranch creates and checks out a crew/<name> working branch.
+	CreateBranch bool
+
+	// AgentOverride specifies the agent alias whose instructions file is
+	// seeded (overrides the rig/town default for crew).
+	AgentOverride string
 }
 
 // validateCrewName checks that a crew name is safe and valid.
@@ -96,6 +115,24 @@ func (m *Manager) crewDir(name string) string {
 	return filepath.Join(m.rig.Path, "crew", name)
 }
 
+// WorktreePathFor returns the git worktree of crew member crew in the rig
+// at rigPath (<rig>/crew/<name>). It fails with ErrCrewNotFound if the
+// directory is missing or is not a git checkout.
+func WorktreePathFor(rigPath, crew string) (string, error) {
+	if err := validateCrewName(crew); err != nil {
+		return "", err
+	}
+	path := filepath.Join(rigPath, "crew", crew)
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%w: no worktree for %s at %s", ErrCrewNotFound, crew, path)
+	}
+	if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+		return "", fmt.Errorf("%w: %s is not a git worktree", ErrCrewNotFound, path)
+	}
+	return path, nil
+}
+
 // stateFile returns the state file path for a crew worker.
 func (m *Manager) stateFile(name string) string {
 	return filepath.Join(m.crewDir(name), "state.json")
@@ -114,6 +151,12 @@ func (m *Manager) exists(name string) bool {
 
 // Add creates a new crew worker with a clone of the rig.
 func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
+	return m.AddWithOptions(name, AddOptions{CreateBranch: createBranch})
+}
+
+// AddWithOptions creates a new crew worker with a clone of the rig, seeding
+// the instructions file of the agent it will run.
+func (m *Manager) AddWithOptions(name string, opts AddOptions) (*CrewWorker, error) {
 	if err := validateCrewName(name); err != nil {
 		return nil, err
 	}
@@ -147,7 +190,7 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 	branchName := m.rig.DefaultBranch()
 
 	// Optionally create a working branch
-	if createBranch {
+	if opts.CreateBranch {
 		branchName = fmt.Sprintf("crew/%s", name)
 		if err := crewGit.CreateBranch(branchName); err != nil {
 			_ = os.RemoveAll(crewPath) // best-effort cleanup
@@ -196,10 +239,15 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 	// NOTE: Slash commands (.claude/commands/) are provisioned at town level by gt install.
 	// All agents inherit them via Claude's directory traversal - no per-workspace copies needed.
 
-	// NOTE: We intentionally do NOT write to CLAUDE.md here.
-	// Gas Town context is injected ephemerally via SessionStart hook (gt prime).
-	// Writing to CLAUDE.md would overwrite project instructions and leak
-	// Gas Town internals into the project repo when workers commit/push.
+	// Seed the agent's instructions file (CLAUDE.md, AGENTS.md, ...) if the
+	// repo doesn't have one. Gas Town context is still injected ephemerally
+	// via SessionStart hook (gt prime): an existing file is never touched,
+	// and a seeded one is git-excluded so it can't leak into the project.
+	townRoot := filepath.Dir(m.rig.Path)
+	if err := m.seedInstructions(crewPath, townRoot, opts.AgentOverride); err != nil {
+		// Non-fatal - log warning but continue
+		fmt.Printf("Warning: could not seed instructions file: %v\n", err)
+	}
 
 	// Create crew worker state
 	now := time.Now()
@@ -221,6 +269,81 @@ func (m *Manager) Add(name string, createBranch bool) (*CrewWorker, error) {
 	return crew, nil
 }
 
+// defaultInstructionsSeed is written to a new crew workspace's instructions
+// file when the agent config names no Seed.
+const defaultInstructionsSeed = `# Crew workspace instructions
+
+Gas Town context is loaded at session start by ` + "`gt prime`" + `.
+Add notes for this workspace here; this file is git-excluded.
+`
+
+// seedInstructions creates the instructions file of the crew agent (or
+// agentOverride) in crewPath when missing, from its Instructions.Seed or
+// defaultInstructionsSeed, and adds it to the clone's .git/info/exclude.
+func (m *Manager) seedInstructions(crewPath, townRoot, agentOverride string) error {
+	rc := config.ResolveRoleAgentConfig("crew", townRoot, m.rig.Path)
+	if agentOverride != "" {
+		var err error
+		if rc, _, err = config.ResolveAgentConfigWithOverride(townRoot, m.rig.Path, agentOverride); err != nil {
+			return err
+		}
+	}
+	instructions := rc.Resolved().Instructions
+
+	path := filepath.Join(crewPath, instructions.File)
+	if _, err := os.Lstat(path); err == nil {
+		return nil // the repo's own instructions win
+	}
+
+	content := []byte(defaultInstructionsSeed)
+	if seed := instructions.Seed; seed != "" {
+		if !filepath.IsAbs(seed) {
+			seed = filepath.Join(townRoot, seed)
+		}
+		data, err := os.ReadFile(seed)
+		if err != nil {
+			return fmt.Errorf("reading instructions seed: %w", err)
+		}
+		content = data
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	return excludeFromGit(crewPath, "/"+filepath.ToSlash(instructions.File))
+}
+
+// excludeFromGit appends pattern to the clone's .git/info/exclude unless
+// it is already listed.
+func excludeFromGit(clonePath, pattern string) error {
+	excludePath := filepath.Join(clonePath, ".git", "info", "exclude")
+	data, err := os.ReadFile(excludePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	if err := os.MkdirAll(filepath.Dir(excludePath), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(excludePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.WriteString(pattern + "\n")
+	return err
+}
+
 // Remove deletes a crew worker.
 func (m *Manager) Remove(name string, force bool) error {
 	if err := validateCrewName(name); err != nil {
@@ -438,10 +561,9 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	}
 
 	// Get or create the crew worker
-	worker, err := m.Get(name)
+	_, err := m.Get(name)
 	if err == ErrCrewNotFound {
-		worker, err = m.Add(name, false) // No feature branch for crew
-		if err != nil {
+		if _, err := m.Add(name, false); err != nil { // No feature branch for crew
 			return fmt.Errorf("creating crew workspace: %w", err)
 		}
 	} else if err != nil {
@@ -476,12 +598,26 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		}
 	}
 
+	// Queue the start if the town or rig is at its agent cap
+	townRoot := filepath.Dir(m.rig.Path)
+	limits := scheduler.LoadLimits(townRoot, m.rig.Path)
+	req := scheduler.Request{Rig: m.rig.Name, Role: "crew", Name: name}
+	release, err := limits.Admit(townRoot, req, t.ListSessions)
+	defer release()
+	if err != nil {
+		return err
+	}
+
 	// Ensure runtime settings exist in crew/ (not crew/<name>/) so we don't
 	// write into the source repo. Claude walks up the tree to find settings.
 	// All crew members share the same settings file.
 	crewBaseDir := filepath.Join(m.rig.Path, "crew")
-	townRoot := filepath.Dir(m.rig.Path)
 	runtimeConfig := config.ResolveRoleAgentConfig("crew", townRoot, m.rig.Path)
+	workDir, err := WorktreePathFor(m.rig.Path, name)
+	if err != nil {
+		return err
+	}
+	runtimeConfig.WorkingDir = workDir
 	if err := runtime.EnsureSettingsForRole(crewBaseDir, "crew", runtimeConfig); err != nil {
 		return fmt.Errorf("ensuring runtime settings: %w", err)
 	}
@@ -510,12 +646,20 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 	if opts.Interactive {
 		claudeCmd = strings.Replace(claudeCmd, " --dangerously-skip-permissions", "", 1)
 	}
+	claudeCmd = config.PrependEnv(claudeCmd, opts.Env)
 
 	// Create session with command directly to avoid send-keys race condition.
 	// See: https://github.com/anthropics/gastown/issues/280
-	if err := t.NewSessionWithCommand(sessionID, worker.ClonePath, claudeCmd); err != nil {
+	if err := t.NewSessionWithCommand(sessionID, runtimeConfig.WorkingDir, claudeCmd); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
+	release()
+	_ = scheduler.LowerPane(limits, sessionID, t.GetPanePID) // Non-fatal
+
+	// A new session isn't paused, whatever became of the old one
+	if err := m.setPaused(name, nil); err != nil {
+		return err
+	}
 
 	// Set environment variables (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
@@ -527,13 +671,14 @@ func (m *Manager) Start(name string, opts StartOptions) error {
 		RuntimeConfigDir: opts.ClaudeConfigDir,
 		BeadsNoDaemon:    true,
 	})
-	for k, v := range envVars {
+	for k, v := range config.MergeEnv(opts.Env, envVars) {
 		_ = t.SetEnvironment(sessionID, k, v)
 	}
 
 	// Apply rig-based theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
 	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, name, "crew")
+	_ = transcript.Enable(t, sessionID) // Non-fatal, likewise
 
 	// Set up C-b n/p keybindings for crew session cycling (non-fatal)
 	_ = t.SetCrewCycleBindings(sessionID)
@@ -570,6 +715,12 @@ func (m *Manager) Stop(name string) error {
 	if err := t.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	hooks.Fire(filepath.Dir(m.rig.Path), hooks.SessionEnd, map[string]string{
+		"session": sessionID,
+		"agent":   fmt.Sprintf("%s/crew/%s", m.rig.Name, name),
+		"reason":  "crew stop",
+	})
+	session.Forget(sessionID)
 
 	return nil
 }
@@ -581,3 +732,110 @@ func (m *Manager) IsRunning(name string) (bool, error) {
 	return t.HasSession(sessionID)
 }
 
+// Pause interrupts the agent in a crew member's session, leaving it idle
+// at its prompt with its context, and marks the session and the member's
+// state paused. Nudges to a paused session are refused (see
+// tmux.ErrSessionPaused), so nothing wakes the agent until Resume.
+func (m *Manager) Pause(name string) error {
+	if err := validateCrewName(name); err != nil {
+		return err
+	}
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+	since, err := m.PausedSince(name)
+	if err != nil {
+		return err
+	}
+	if since != nil {
+		return fmt.Errorf("%w (since %s)", ErrPaused, since.Local().Format(time.Kitchen))
+	}
+
+	t := tmux.NewTmux()
+	sessionID := m.SessionName(name)
+	now := time.Now().UTC()
+	if err := t.SetEnvironment(sessionID, tmux.PausedEnv, now.Format(time.RFC3339)); err != nil {
+		return fmt.Errorf("marking session paused: %w", err)
+	}
+	if err := t.InterruptSession(sessionID); err != nil {
+		_ = t.UnsetEnvironment(sessionID, tmux.PausedEnv)
+		return fmt.Errorf("interrupting agent: %w", err)
+	}
+	return m.setPaused(name, &now)
+}
+
+// resumePrompt is the nudge that sets a resumed crew agent going again.
+const resumePrompt = "[gt crew resume] You were paused and are resumed. Continue the work you were doing when you were interrupted."
+
+// Resume clears the pause of a crew member's session and nudges its agent
+// to carry on where it was interrupted.
+func (m *Manager) Resume(name string) error {
+	if err := validateCrewName(name); err != nil {
+		return err
+	}
+	if _, err := m.Get(name); err != nil {
+		return err
+	}
+	since, err := m.PausedSince(name)
+	if since == nil {
+		// Nothing to resume; don't leave the state saying otherwise
+		if clearErr := m.setPaused(name, nil); clearErr != nil {
+			return clearErr
+		}
+		if err != nil {
+			return err
+		}
+		return ErrNotPaused
+	}
+
+	t := tmux.NewTmux()
+	sessionID := m.SessionName(name)
+	if err := t.UnsetEnvironment(sessionID, tmux.PausedEnv); err != nil {
+		return fmt.Errorf("clearing pause: %w", err)
+	}
+	if err := m.setPaused(name, nil); err != nil {
+		return err
+	}
+	if err := t.NudgeSession(sessionID, resumePrompt); err != nil {
+		return fmt.Errorf("nudging agent: %w", err)
+	}
+	return nil
+}
+
+// PausedSince returns when a crew member's session was paused, or nil if
+// it isn't. It fails with ErrSessionNotFound if there is no session.
+func (m *Manager) PausedSince(name string) (*time.Time, error) {
+	t := tmux.NewTmux()
+	sessionID := m.SessionName(name)
+	running, err := t.HasSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("checking session: %w", err)
+	}
+	if !running {
+		return nil, ErrSessionNotFound
+	}
+	value, err := t.GetEnvironment(sessionID, tmux.PausedEnv)
+	if err != nil || value == "" {
+		return nil, nil // Unset
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", tmux.PausedEnv, err)
+	}
+	return &since, nil
+}
+
+// setPaused records in a crew member's state when it was paused, or that
+// it isn't (nil).
+func (m *Manager) setPaused(name string, at *time.Time) error {
+	worker, err := m.loadState(name)
+	if err != nil {
+		return err
+	}
+	if worker.PausedAt == nil && at == nil {
+		return nil
+	}
+	worker.PausedAt = at
+	worker.UpdatedAt = time.Now()
+	return m.saveState(worker)
+}
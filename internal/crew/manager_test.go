@@ -1,13 +1,17 @@
 package crew
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/tmux"
 )
 
 func TestManagerAddAndGet(t *testing.T) {
@@ -69,8 +73,7 @@ func TestManagerAddAndGet(t *testing.T) {
 		t.Error("mail directory was not created")
 	}
 
-	// NOTE: CLAUDE.md is NOT created by Add() - it's injected via SessionStart hook
-	// See manager.go line 107-110 for why we skip CLAUDE.md creation
+	// The default (claude) agent's CLAUDE.md is seeded; see TestManagerAddSeedsInstructions
 
 	stateFile := filepath.Join(crewDir, "state.json")
 	if _, err := os.Stat(stateFile); os.IsNotExist(err) {
@@ -392,8 +395,181 @@ func TestManagerGetWithStaleStateName(t *testing.T) {
 	}
 }
 
+func TestWorktreePathFor(t *testing.T) {
+	rigPath := t.TempDir()
+	worktree := filepath.Join(rigPath, "crew", "alice")
+	if err := os.MkdirAll(filepath.Join(worktree, ".git"), 0755); err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+	// A directory without .git is not a usable worktree.
+	if err := os.MkdirAll(filepath.Join(rigPath, "crew", "bob"), 0755); err != nil {
+		t.Fatalf("failed to create crew dir: %v", err)
+	}
+
+	got, err := WorktreePathFor(rigPath, "alice")
+	if err != nil {
+		t.Fatalf("WorktreePathFor(alice) failed: %v", err)
+	}
+	if got != worktree {
+		t.Errorf("WorktreePathFor(alice) = %q, want %q", got, worktree)
+	}
+
+	for _, name := range []string{"bob", "carol"} {
+		if _, err := WorktreePathFor(rigPath, name); !errors.Is(err, ErrCrewNotFound) {
+			t.Errorf("WorktreePathFor(%s) error = %v, want ErrCrewNotFound", name, err)
+		}
+	}
+
+	if _, err := WorktreePathFor(rigPath, "../alice"); !errors.Is(err, ErrInvalidCrewName) {
+		t.Errorf("WorktreePathFor(../alice) error = %v, want ErrInvalidCrewName", err)
+	}
+}
+
+func TestManagerAddSeedsInstructions(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "test-rig")
+	if err := os.MkdirAll(rigPath, 0755); err != nil {
+		t.Fatalf("failed to create rig dir: %v", err)
+	}
+	bareRepoPath := filepath.Join(townRoot, "bare-repo.git")
+	if err := runCmd("git", "init", "--bare", bareRepoPath); err != nil {
+		t.Fatalf("failed to create bare repo: %v", err)
+	}
+
+	// A custom agent whose instructions come from a town-level seed file
+	if err := os.WriteFile(filepath.Join(townRoot, "seed.md"), []byte("seeded\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	settings := config.NewTownSettings()
+	settings.Agents["kimi-seeded"] = &config.RuntimeConfig{
+		Provider:     "kimi",
+		Command:      "kimi",
+		Instructions: &config.RuntimeInstructionsConfig{Seed: "seed.md"},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewManager(&rig.Rig{Name: "test-rig", Path: rigPath, GitURL: bareRepoPath}, git.NewGit(rigPath))
+
+	tests := []struct {
+		name        string
+		agent       string
+		wantFile    string
+		wantNot     string
+		wantContent string
+	}{
+		{"claude", "", "CLAUDE.md", "AGENTS.md", defaultInstructionsSeed},
+		{"kimi", "kimi", "AGENTS.md", "CLAUDE.md", defaultInstructionsSeed},
+		{"seeded", "kimi-seeded", "AGENTS.md", "CLAUDE.md", "seeded\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			worker, err := mgr.AddWithOptions(tt.name, AddOptions{AgentOverride: tt.agent})
+			if err != nil {
+				t.Fatalf("AddWithOptions failed: %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(worker.ClonePath, tt.wantFile))
+			if err != nil {
+				t.Fatalf("%s was not seeded: %v", tt.wantFile, err)
+			}
+			if string(data) != tt.wantContent {
+				t.Errorf("%s = %q, want %q", tt.wantFile, data, tt.wantContent)
+			}
+			if _, err := os.Stat(filepath.Join(worker.ClonePath, tt.wantNot)); !os.IsNotExist(err) {
+				t.Errorf("%s should not be created for agent %q", tt.wantNot, tt.agent)
+			}
+			exclude, err := os.ReadFile(filepath.Join(worker.ClonePath, ".git", "info", "exclude"))
+			if err != nil || !strings.Contains(string(exclude), "\n/"+tt.wantFile+"\n") {
+				t.Errorf(".git/info/exclude = %q (%v), want /%s listed", exclude, err, tt.wantFile)
+			}
+		})
+	}
+}
+
+func TestSeedInstructionsKeepsExistingFile(t *testing.T) {
+	townRoot := t.TempDir()
+	rigPath := filepath.Join(townRoot, "test-rig")
+	crewPath := filepath.Join(rigPath, "crew", "dave")
+	if err := os.MkdirAll(filepath.Join(crewPath, ".git", "info"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	existing := filepath.Join(crewPath, "CLAUDE.md")
+	if err := os.WriteFile(existing, []byte("project rules\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mgr := NewManager(&rig.Rig{Name: "test-rig", Path: rigPath}, git.NewGit(rigPath))
+	if err := mgr.seedInstructions(crewPath, townRoot, ""); err != nil {
+		t.Fatalf("seedInstructions failed: %v", err)
+	}
+	if data, _ := os.ReadFile(existing); string(data) != "project rules\n" {
+		t.Errorf("CLAUDE.md = %q, want it untouched", data)
+	}
+	// A tracked project file must not be excluded
+	if _, err := os.Stat(filepath.Join(crewPath, ".git", "info", "exclude")); !os.IsNotExist(err) {
+		t.Errorf("exclude file written for an existing instructions file")
+	}
+}
+
 // Helper to run commands
 func runCmd(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	return cmd.Run()
 }
+
+func TestManagerPauseAndResume(t *testing.T) {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		t.Skip("tmux not installed")
+	}
+
+	rigPath := filepath.Join(t.TempDir(), "pauserig")
+	if err := os.MkdirAll(filepath.Join(rigPath, "crew", "max"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	mgr := NewManager(&rig.Rig{Name: "pauserig", Path: rigPath}, git.NewGit(rigPath))
+
+	if err := mgr.Pause("max"); !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Pause without a session = %v, want ErrSessionNotFound", err)
+	}
+
+	tm := tmux.NewTmux()
+	sessionID := mgr.SessionName("max")
+	_ = tm.KillSession(sessionID)
+	if err := tm.NewSessionWithCommand(sessionID, "", "cat"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionID) }()
+
+	if err := mgr.Pause("max"); err != nil {
+		t.Fatalf("Pause: %v", err)
+	}
+	if err := mgr.Pause("max"); !errors.Is(err, ErrPaused) {
+		t.Errorf("second Pause = %v, want ErrPaused", err)
+	}
+	if since, err := mgr.PausedSince("max"); err != nil || since == nil {
+		t.Errorf("PausedSince = %v, %v; want paused", since, err)
+	}
+	if worker, _ := mgr.Get("max"); worker.PausedAt == nil {
+		t.Error("state not marked paused")
+	}
+	if err := tm.NudgeSession(sessionID, "wake up"); !errors.Is(err, tmux.ErrSessionPaused) {
+		t.Errorf("NudgeSession while paused = %v, want ErrSessionPaused", err)
+	}
+
+	if err := mgr.Resume("max"); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if since, _ := mgr.PausedSince("max"); since != nil {
+		t.Errorf("PausedSince after Resume = %v, want nil", since)
+	}
+	if worker, _ := mgr.Get("max"); worker.PausedAt != nil {
+		t.Error("state still marked paused")
+	}
+	if err := mgr.Resume("max"); !errors.Is(err, ErrNotPaused) {
+		t.Errorf("second Resume = %v, want ErrNotPaused", err)
+	}
+	if pane, _ := tm.CapturePane(sessionID, 20); !strings.Contains(pane, "You were paused") || strings.Contains(pane, "wake up") {
+		t.Errorf("pane %q: want the resume nudge, and not the one sent while paused", pane)
+	}
+}
@@ -22,6 +22,10 @@ type CrewWorker struct {
 
 	// UpdatedAt is when the crew worker was last updated.
 	UpdatedAt time.Time `json:"updated_at"`
+
+	// PausedAt is when the crew worker's session was paused (gt crew
+	// pause), or nil if it is not paused.
+	PausedAt *time.Time `json:"paused_at,omitempty"`
 }
 
 // Summary provides a concise view of crew worker status.
@@ -23,11 +23,16 @@ import (
 	"github.com/steveyegge/gastown/internal/deacon"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/feed"
+	"github.com/steveyegge/gastown/internal/hooks"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/polecat"
 	"github.com/steveyegge/gastown/internal/refinery"
 	"github.com/steveyegge/gastown/internal/rig"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/util"
 	"github.com/steveyegge/gastown/internal/wisp"
 	"github.com/steveyegge/gastown/internal/witness"
@@ -118,6 +123,7 @@ func New(config *Config) (*Daemon, error) {
 // Run starts the daemon main loop.
 func (d *Daemon) Run() error {
 	d.logger.Printf("Daemon starting (PID %d)", os.Getpid())
+	gtlog.L().Info("daemon starting", "town", d.config.TownRoot)
 
 	// Acquire exclusive lock to prevent multiple daemons from running.
 	// This prevents the TOCTOU race condition where multiple concurrent starts
@@ -319,6 +325,9 @@ func (d *Daemon) heartbeat(state *State) {
 	// This is a safety net - Deacon patrol also does this more frequently.
 	d.cleanupOrphanedProcesses()
 
+	// 13. Start queued crew/polecat sessions as agent slots free up
+	d.startQueuedSessions()
+
 	// Update state
 	state.LastHeartbeat = time.Now()
 	state.HeartbeatCount++
@@ -766,6 +775,7 @@ func (d *Daemon) processLifecycleRequests() {
 // shutdown performs graceful shutdown.
 func (d *Daemon) shutdown(state *State) error { //nolint:unparam // error return kept for future use
 	d.logger.Println("Daemon shutting down")
+	gtlog.L().Info("daemon shutting down")
 
 	// Stop feed curator
 	if d.curator != nil {
@@ -1082,6 +1092,18 @@ func (d *Daemon) checkPolecatHealth(rigName, polecatName string) {
 
 	// Track this death for mass death detection
 	d.recordSessionDeath(sessionName)
+	hooks.Fire(d.config.TownRoot, hooks.AgentCrash, map[string]string{
+		"agent":   fmt.Sprintf("%s/polecats/%s", rigName, polecatName),
+		"session": sessionName,
+		"bead":    info.HookBead,
+	})
+	notify.Send(d.config.TownRoot, notify.Notification{
+		Event:  notify.AgentCrash,
+		Rig:    rigName,
+		Title:  fmt.Sprintf("%s/polecats/%s crashed", rigName, polecatName),
+		Body:   fmt.Sprintf("Its session died with %s hooked; restarting it.", info.HookBead),
+		Fields: map[string]string{"agent": fmt.Sprintf("%s/polecats/%s", rigName, polecatName), "session": sessionName, "bead": info.HookBead},
+	})
 
 	// Auto-restart the polecat
 	if err := d.restartPolecatSession(rigName, polecatName, sessionName); err != nil {
@@ -1194,6 +1216,9 @@ func (d *Daemon) restartPolecatSession(rigName, polecatName, sessionName string)
 	theme := tmux.AssignTheme(rigName)
 	_ = d.tmux.ConfigureGasTownSession(sessionName, theme, rigName, polecatName, "polecat")
 
+	// Record the pane to the session's transcript
+	_ = transcript.Enable(d.tmux, sessionName)
+
 	// Set pane-died hook for future crash detection
 	agentID := fmt.Sprintf("%s/%s", rigName, polecatName)
 	_ = d.tmux.SetPaneDiedHook(sessionName, agentID)
@@ -1235,6 +1260,26 @@ Manual intervention may be required.`,
 	}
 }
 
+// startQueuedSessions starts sessions queued by the scheduler's
+// max_concurrent_agents cap, oldest first, while slots are free.
+func (d *Daemon) startQueuedSessions() {
+	started, err := scheduler.Drain(d.config.TownRoot, d.tmux.ListSessions, func(req scheduler.Request) error {
+		cmd := exec.Command("gt", req.Args()...) //nolint:gosec // G204: args are constructed internally
+		cmd.Dir = d.config.TownRoot
+		cmd.Env = os.Environ() // Inherit PATH to find gt executable
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	})
+	for _, req := range started {
+		d.logger.Printf("Started queued session %s (queued %s ago)", req.Session(), time.Since(req.QueuedAt).Round(time.Second))
+	}
+	if err != nil {
+		d.logger.Printf("Error starting queued sessions: %v", err)
+	}
+}
+
 // cleanupOrphanedProcesses kills orphaned claude subagent processes.
 // These are Task tool subagents that didn't clean up after completion.
 // Detection uses TTY column: processes with TTY "?" have no controlling terminal.
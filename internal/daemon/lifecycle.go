@@ -13,9 +13,11 @@ import (
 	"github.com/steveyegge/gastown/internal/beads"
 	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/constants"
+	gtlog "github.com/steveyegge/gastown/internal/log"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // BeadsMessage represents a message from gt mail inbox --json.
@@ -94,6 +96,7 @@ func (d *Daemon) ProcessLifecycleRequests() {
 
 		if err := d.executeLifecycleAction(request); err != nil {
 			d.logger.Printf("Error executing lifecycle action: %v", err)
+			gtlog.L().Error("lifecycle action failed", "action", request.Action, "from", request.From, "err", err)
 			continue
 		}
 	}
@@ -206,6 +209,7 @@ func (d *Daemon) executeLifecycleAction(request *LifecycleRequest) error {
 			return fmt.Errorf("restarting session: %w", err)
 		}
 		d.logger.Printf("Restarted session %s", sessionName)
+		gtlog.L().Info("restarted session", "session", sessionName, "action", request.Action, "from", request.From)
 		return nil
 
 	default:
@@ -548,9 +552,11 @@ func (d *Daemon) applySessionTheme(sessionName string, parsed *ParsedIdentity) {
 	if parsed.RoleType == "mayor" {
 		theme := tmux.MayorTheme()
 		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, "", "Mayor", "coordinator")
+		_ = transcript.Enable(d.tmux, sessionName)
 	} else if parsed.RigName != "" {
 		theme := tmux.AssignTheme(parsed.RigName)
 		_ = d.tmux.ConfigureGasTownSession(sessionName, theme, parsed.RigName, parsed.RoleType, parsed.RoleType)
+		_ = transcript.Enable(d.tmux, sessionName)
 	}
 }
 
@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// AgentTTYCheck verifies that agents marked RequiresTTY can be given one.
+// Gas Town provides a terminal by launching agents in tmux panes, so any
+// configured agent that requires a TTY needs tmux to be installed.
+type AgentTTYCheck struct {
+	BaseCheck
+	lookPath func(file string) (string, error) // overridable for tests
+}
+
+// NewAgentTTYCheck creates a new agent TTY check.
+func NewAgentTTYCheck() *AgentTTYCheck {
+	return &AgentTTYCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "agent-tty",
+			CheckDescription: "Check agents that require a TTY can launch in tmux",
+			CheckCategory:    CategoryConfig,
+		},
+		lookPath: exec.LookPath,
+	}
+}
+
+// Run checks the town's default and per-role agents against tmux availability.
+func (c *AgentTTYCheck) Run(ctx *CheckContext) *CheckResult {
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(ctx.TownRoot))
+	if err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusWarning,
+			Message: "Could not load town settings",
+			Details: []string{err.Error()},
+		}
+	}
+	_ = config.LoadAgentRegistry(config.DefaultAgentRegistryPath(ctx.TownRoot))
+
+	agents := ttyAgents(settings)
+	if len(agents) == 0 {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusOK,
+			Message: "No configured agents require a TTY",
+		}
+	}
+
+	if _, err := c.lookPath("tmux"); err != nil {
+		return &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: fmt.Sprintf("tmux not found; agents requiring a TTY: %s", strings.Join(agents, ", ")),
+			Details: []string{
+				"These agents refuse to run headless and are launched in tmux panes",
+			},
+			FixHint: "Install tmux: apt install tmux (Debian/Ubuntu) or brew install tmux (macOS)",
+		}
+	}
+
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: fmt.Sprintf("tmux available for agents requiring a TTY: %s", strings.Join(agents, ", ")),
+	}
+}
+
+// ttyAgents returns the configured agents (default and per-role) whose
+// preset requires a TTY, sorted and deduplicated.
+func ttyAgents(settings *config.TownSettings) []string {
+	names := map[string]bool{}
+	add := func(name string) {
+		if name == "" {
+			name = string(config.DefaultAgentPreset())
+		}
+		if preset := config.GetAgentPresetByName(name); preset != nil && preset.RequiresTTY {
+			names[name] = true
+		}
+	}
+
+	add(settings.DefaultAgent)
+	for _, agent := range settings.RoleAgents {
+		add(agent)
+	}
+
+	agents := make([]string, 0, len(names))
+	for name := range names {
+		agents = append(agents, name)
+	}
+	sort.Strings(agents)
+	return agents
+}
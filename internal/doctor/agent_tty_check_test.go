@@ -0,0 +1,73 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAgentTTYCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		settings    string // settings/config.json contents; empty means none
+		haveTmux    bool
+		wantStatus  CheckStatus
+		wantMessage string
+	}{
+		{
+			name:        "default claude with tmux",
+			haveTmux:    true,
+			wantStatus:  StatusOK,
+			wantMessage: "tmux available for agents requiring a TTY: claude",
+		},
+		{
+			name:        "default claude without tmux",
+			wantStatus:  StatusError,
+			wantMessage: "tmux not found; agents requiring a TTY: claude",
+		},
+		{
+			name:        "role agents are included",
+			settings:    `{"type":"town-settings","version":1,"default_agent":"codex","role_agents":{"crew":"kimi","witness":"codex"}}`,
+			wantStatus:  StatusError,
+			wantMessage: "kimi",
+		},
+		{
+			name:        "no agents need a TTY",
+			settings:    `{"type":"town-settings","version":1,"default_agent":"codex"}`,
+			wantStatus:  StatusOK,
+			wantMessage: "No configured agents require a TTY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			townRoot := t.TempDir()
+			if tt.settings != "" {
+				if err := os.MkdirAll(filepath.Join(townRoot, "settings"), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(townRoot, "settings", "config.json"), []byte(tt.settings), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			check := NewAgentTTYCheck()
+			check.lookPath = func(file string) (string, error) {
+				if tt.haveTmux {
+					return "/usr/bin/" + file, nil
+				}
+				return "", errors.New("not found")
+			}
+
+			result := check.Run(&CheckContext{TownRoot: townRoot})
+			if result.Status != tt.wantStatus {
+				t.Errorf("status = %v, want %v (%s)", result.Status, tt.wantStatus, result.Message)
+			}
+			if !strings.Contains(result.Message, tt.wantMessage) {
+				t.Errorf("message = %q, want it to contain %q", result.Message, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -206,31 +206,21 @@ func NewLegacyGastownCheck() *LegacyGastownCheck {
 // Run checks for legacy .gastown/ directories.
 func (c *LegacyGastownCheck) Run(ctx *CheckContext) *CheckResult {
 	var found []string
+	c.legacyDirs = nil // Cached for Fix
 
 	// Check town-level .gastown/
 	townGastown := filepath.Join(ctx.TownRoot, ".gastown")
-	if info, err := os.Stat(townGastown); err == nil && info.IsDir() {
+	if isLegacyGastownDir(townGastown) {
 		found = append(found, ".gastown/ (town root)")
+		c.legacyDirs = append(c.legacyDirs, townGastown)
 	}
 
 	// Check each rig for .gastown/
-	rigs := c.findRigs(ctx.TownRoot)
-	for _, rig := range rigs {
+	for _, rig := range c.findRigs(ctx.TownRoot) {
 		rigGastown := filepath.Join(rig, ".gastown")
-		if info, err := os.Stat(rigGastown); err == nil && info.IsDir() {
+		if isLegacyGastownDir(rigGastown) {
 			relPath, _ := filepath.Rel(ctx.TownRoot, rig)
 			found = append(found, fmt.Sprintf("%s/.gastown/", relPath))
-		}
-	}
-
-	// Cache for Fix
-	c.legacyDirs = nil
-	if info, err := os.Stat(townGastown); err == nil && info.IsDir() {
-		c.legacyDirs = append(c.legacyDirs, townGastown)
-	}
-	for _, rig := range rigs {
-		rigGastown := filepath.Join(rig, ".gastown")
-		if info, err := os.Stat(rigGastown); err == nil && info.IsDir() {
 			c.legacyDirs = append(c.legacyDirs, rigGastown)
 		}
 	}
@@ -252,16 +242,50 @@ func (c *LegacyGastownCheck) Run(ctx *CheckContext) *CheckResult {
 	}
 }
 
-// Fix removes legacy .gastown/ directories.
+// Fix removes legacy .gastown/ directories, keeping startup prompt
+// templates (.gastown/prompts/) and hook scripts (.gastown/hooks/), which
+// are still in use.
 func (c *LegacyGastownCheck) Fix(ctx *CheckContext) error {
 	for _, dir := range c.legacyDirs {
-		if err := os.RemoveAll(dir); err != nil {
-			return fmt.Errorf("failed to remove %s: %w", dir, err)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", dir, err)
 		}
+		for _, entry := range entries {
+			if gastownCurrentDirs[entry.Name()] {
+				continue
+			}
+			if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", filepath.Join(dir, entry.Name()), err)
+			}
+		}
+		_ = os.Remove(dir) // Only succeeds if nothing was kept
 	}
 	return nil
 }
 
+// gastownCurrentDirs are the .gastown/ entries that aren't legacy: startup
+// prompt templates (session.PromptsDir) and hook scripts (hooks.Dir).
+var gastownCurrentDirs = map[string]bool{
+	"prompts": true,
+	"hooks":   true,
+}
+
+// isLegacyGastownDir reports whether dir is a .gastown/ directory holding
+// anything besides prompt templates and hook scripts.
+func isLegacyGastownDir(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !gastownCurrentDirs[entry.Name()] {
+			return true
+		}
+	}
+	return false
+}
+
 // findRigs returns rig directories within the town.
 func (c *LegacyGastownCheck) findRigs(townRoot string) []string {
 	return findAllRigs(townRoot)
@@ -440,3 +440,78 @@ func TestCustomTypesCheck_ParsesOutputWithNotePrefix(t *testing.T) {
 		t.Errorf("After parsing, missing types: %v", missing)
 	}
 }
+
+func TestLegacyGastownCheck_KeepsPromptTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	gastown := filepath.Join(tmpDir, ".gastown")
+	prompts := filepath.Join(gastown, "prompts")
+	if err := os.MkdirAll(prompts, 0755); err != nil {
+		t.Fatal(err)
+	}
+	tmpl := filepath.Join(prompts, "crew.tmpl")
+	if err := os.WriteFile(tmpl, []byte("{{.Default}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewLegacyGastownCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("prompts only: status = %v, want StatusOK", result.Status)
+	}
+
+	legacy := filepath.Join(gastown, "config.json")
+	if err := os.WriteFile(legacy, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusWarning {
+		t.Fatalf("with legacy file: status = %v, want StatusWarning", result.Status)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy file still exists after fix")
+	}
+	if _, err := os.Stat(tmpl); err != nil {
+		t.Errorf("prompt template removed by fix: %v", err)
+	}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Errorf("after fix: status = %v, want StatusOK", result.Status)
+	}
+}
+
+func TestLegacyGastownCheck_KeepsHooks(t *testing.T) {
+	tmpDir := t.TempDir()
+	gastown := filepath.Join(tmpDir, ".gastown")
+	hookDir := filepath.Join(gastown, "hooks", "session-start.d")
+	if err := os.MkdirAll(hookDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(hookDir, "10-notify.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	check := NewLegacyGastownCheck()
+	ctx := &CheckContext{TownRoot: tmpDir}
+	if result := check.Run(ctx); result.Status != StatusOK {
+		t.Fatalf("hooks only: status = %v, want StatusOK", result.Status)
+	}
+
+	legacy := filepath.Join(gastown, "config.json")
+	if err := os.WriteFile(legacy, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if result := check.Run(ctx); result.Status != StatusWarning {
+		t.Fatalf("with legacy file: status = %v, want StatusWarning", result.Status)
+	}
+	if err := check.Fix(ctx); err != nil {
+		t.Fatalf("Fix failed: %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy file still exists after fix")
+	}
+	if _, err := os.Stat(script); err != nil {
+		t.Errorf("hook script removed by fix: %v", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/steveyegge/gastown/internal/config"
 	"github.com/steveyegge/gastown/internal/events"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
@@ -85,6 +86,9 @@ func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 	mayorSession := session.MayorSessionName()
 	deaconSession := session.DeaconSessionName()
 
+	// Sessions of the town's custom roles are valid too
+	customRoles, _ := config.LoadCustomRoles(ctx.TownRoot)
+
 	// Check each session
 	var orphans []string
 	var validCount int
@@ -99,7 +103,7 @@ func (c *OrphanSessionCheck) Run(ctx *CheckContext) *CheckResult {
 			continue
 		}
 
-		if c.isValidSession(sess, validRigs, mayorSession, deaconSession) {
+		if _, custom := session.ParseCustomRoleSession(sess, customRoles); custom || c.isValidSession(sess, validRigs, mayorSession, deaconSession) {
 			validCount++
 		} else {
 			orphans = append(orphans, sess)
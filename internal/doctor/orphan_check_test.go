@@ -6,6 +6,8 @@ import (
 	"reflect"
 	"runtime"
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // mockSessionLister allows deterministic testing of orphan session detection.
@@ -389,6 +391,28 @@ func TestOrphanSessionCheck_HQSessions(t *testing.T) {
 	}
 }
 
+func TestOrphanSessionCheck_CustomRoleSessions(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0o755); err != nil {
+		t.Fatalf("create mayor dir: %v", err)
+	}
+	settings := config.NewTownSettings()
+	settings.Roles = map[string]*config.CustomRoleConfig{
+		"librarian": {Session: "hq-librarian"},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+
+	lister := &mockSessionLister{sessions: []string{"hq-librarian", "hq-scratch"}}
+	check := NewOrphanSessionCheckWithSessionLister(lister)
+	check.Run(&CheckContext{TownRoot: townRoot})
+
+	if len(check.orphanSessions) != 1 || check.orphanSessions[0] != "hq-scratch" {
+		t.Errorf("orphans = %v, want only hq-scratch", check.orphanSessions)
+	}
+}
+
 // TestOrphanSessionCheck_Run_Deterministic tests the full Run path with a mock session
 // lister, ensuring deterministic behavior without depending on real tmux state.
 func TestOrphanSessionCheck_Run_Deterministic(t *testing.T) {
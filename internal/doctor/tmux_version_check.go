@@ -0,0 +1,52 @@
+package doctor
+
+import (
+	"errors"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// TmuxVersionCheck verifies that the installed tmux is new enough for Gas
+// Town (tmux.MinVersion).
+type TmuxVersionCheck struct {
+	BaseCheck
+}
+
+// NewTmuxVersionCheck creates a new tmux version check.
+func NewTmuxVersionCheck() *TmuxVersionCheck {
+	return &TmuxVersionCheck{
+		BaseCheck: BaseCheck{
+			CheckName:        "tmux-version",
+			CheckDescription: "Check tmux is installed and at least version " + tmux.MinVersion,
+			CheckCategory:    CategoryInfrastructure,
+		},
+	}
+}
+
+// Run checks the installed tmux version.
+func (c *TmuxVersionCheck) Run(ctx *CheckContext) *CheckResult {
+	t := tmux.NewTmux()
+	if err := t.CheckVersion(); err != nil {
+		result := &CheckResult{
+			Name:    c.Name(),
+			Status:  StatusError,
+			Message: "tmux not found",
+			Details: []string{err.Error()},
+			FixHint: "Install tmux " + tmux.MinVersion + " or newer: apt install tmux (Debian/Ubuntu) or brew install tmux (macOS)",
+		}
+		if errors.Is(err, tmux.ErrVersionUnsupported) {
+			result.Message = "tmux is too old"
+		}
+		return result
+	}
+
+	version, err := t.Version()
+	if err != nil {
+		version = "development build"
+	}
+	return &CheckResult{
+		Name:    c.Name(),
+		Status:  StatusOK,
+		Message: "tmux " + version,
+	}
+}
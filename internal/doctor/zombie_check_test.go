@@ -65,6 +65,9 @@ func TestZombieSessionCheck_FixProtectsCrewSessions(t *testing.T) {
 	}
 
 	ctx := &CheckContext{TownRoot: t.TempDir()}
+	// Fix logs a session death event to the town found from the working
+	// directory; run from the temp town so the log isn't written into the repo
+	t.Chdir(ctx.TownRoot)
 
 	// Fix should skip crew sessions due to safeguard
 	// (We can't fully test this without mocking tmux, but the safeguard is in place)
@@ -13,6 +13,7 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/workspace"
 )
 
@@ -144,6 +145,7 @@ func (m *SessionManager) Start(dogName string, opts SessionStartOptions) error {
 	// Apply dog theming
 	theme := tmux.DogTheme()
 	_ = m.tmux.ConfigureGasTownSession(sessionID, theme, "", dogName, "dog")
+	_ = transcript.Enable(m.tmux, sessionID)
 
 	// Wait for Claude to start
 	if err := m.tmux.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
@@ -189,6 +191,7 @@ func (m *SessionManager) Stop(dogName string, force bool) error {
 	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	session.Forget(sessionID)
 
 	return nil
 }
@@ -5,6 +5,7 @@
 package events
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -46,6 +47,9 @@ const (
 	TypeBoot    = "boot"
 	TypeHalt    = "halt"
 
+	// Hooked work given back by its agent (gt bead handback)
+	TypeHandback = "handback"
+
 	// Session events (for seance discovery)
 	TypeSessionStart = "session_start"
 	TypeSessionEnd   = "session_end"
@@ -136,6 +140,37 @@ func write(event Event) error {
 	return nil
 }
 
+// Read returns the events in the town's raw events log, oldest first.
+// Malformed lines are skipped; a missing log has no events.
+func Read(townRoot string) ([]Event, error) {
+	f, err := os.Open(filepath.Join(townRoot, EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Time parses the event's timestamp. Zero if it is malformed.
+func (e Event) Time() time.Time {
+	t, _ := time.Parse(time.RFC3339, e.Timestamp)
+	return t
+}
+
 // Payload helpers for common event structures.
 
 // SlingPayload creates a payload for sling events.
@@ -264,6 +299,22 @@ func UnhookPayload(beadID string) map[string]interface{} {
 	}
 }
 
+// HandbackPayload creates a payload for handback events. to and agent are
+// where and with which agent the bead was slung again, if it was.
+func HandbackPayload(beadID, reason, to, agent string) map[string]interface{} {
+	p := map[string]interface{}{
+		"bead":   beadID,
+		"reason": reason,
+	}
+	if to != "" {
+		p["to"] = to
+	}
+	if agent != "" {
+		p["agent"] = agent
+	}
+	return p
+}
+
 // KillPayload creates a payload for kill events.
 func KillPayload(rig, target, reason string) map[string]interface{} {
 	return map[string]interface{}{
@@ -0,0 +1,317 @@
+// Package health probes Gas Town agent sessions.
+//
+// For every gt-*/hq-* tmux session, and every session of a custom role
+// (config.TownSettings.Roles), it checks that the agent process is still
+// running in the pane, that the pane has shown output recently, that the
+// agent isn't stuck on a prompt or an error (package agentstatus), and that
+// the session carries its identity environment variables. Worker lock files
+// left behind by sessions that no longer exist are reported alongside.
+package health
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentstatus"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/lock"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// Status is the overall health of one session.
+type Status string
+
+const (
+	StatusHealthy  Status = "healthy"
+	StatusDegraded Status = "degraded" // agent alive, but a probe failed
+	StatusDead     Status = "dead"     // no agent process in the pane
+)
+
+// Probe names, as reported in Probe.Name.
+const (
+	ProbeProcess  = "process"
+	ProbeActivity = "activity"
+	ProbeOutput   = "output"
+	ProbeEnv      = "env"
+)
+
+// DefaultIdleAfter is how long a pane may go without output before its
+// agent is reported as not responding.
+const DefaultIdleAfter = time.Hour
+
+// Probe is the outcome of one check against a session.
+type Probe struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// SessionHealth is the health of one agent session.
+type SessionHealth struct {
+	Session string  `json:"session"`
+	Role    string  `json:"role"`
+	Rig     string  `json:"rig,omitempty"`
+	Name    string  `json:"name,omitempty"`
+	Status  Status  `json:"status"`
+	Probes  []Probe `json:"probes"`
+
+	// Activity is what the agent's pane shows it doing; empty when the
+	// agent is dead.
+	Activity agentstatus.Status `json:"activity,omitempty"`
+}
+
+// StaleLock is a worker lock whose owning process and session are both gone.
+type StaleLock struct {
+	WorkerDir string `json:"worker_dir"`
+	PID       int    `json:"pid"`
+	Session   string `json:"session,omitempty"`
+}
+
+// Report is the result of probing every agent session in a town.
+type Report struct {
+	Sessions   []SessionHealth `json:"sessions"`
+	StaleLocks []StaleLock     `json:"stale_locks,omitempty"`
+}
+
+// Unhealthy returns the number of sessions that are not StatusHealthy.
+func (r *Report) Unhealthy() int {
+	n := 0
+	for _, s := range r.Sessions {
+		if s.Status != StatusHealthy {
+			n++
+		}
+	}
+	return n
+}
+
+// SessionSource abstracts the tmux queries the probes need, for testing.
+type SessionSource interface {
+	ListSessions() ([]string, error)
+	ListSessionIDs() (map[string]string, error)
+	IsAgentAlive(session string) bool
+	GetAllEnvironment(session string) (map[string]string, error)
+	GetSessionInfo(session string) (*tmux.SessionInfo, error)
+	CapturePane(session string, lines int) (string, error)
+}
+
+// Checker probes the agent sessions of one town.
+type Checker struct {
+	// IdleAfter is how long a pane may be silent before the activity
+	// probe fails. Zero disables the probe.
+	IdleAfter time.Duration
+
+	townRoot string
+	source   SessionSource
+	now      func() time.Time
+}
+
+// NewChecker creates a checker for the town at townRoot backed by tmux.
+func NewChecker(townRoot string) *Checker {
+	return NewCheckerWithSource(townRoot, tmux.NewTmux())
+}
+
+// NewCheckerWithSource creates a checker with a custom session source (for testing).
+func NewCheckerWithSource(townRoot string, source SessionSource) *Checker {
+	return &Checker{
+		IdleAfter: DefaultIdleAfter,
+		townRoot:  townRoot,
+		source:    source,
+		now:       time.Now,
+	}
+}
+
+// Run probes every Gas Town session and scans the town for stale locks.
+// Sessions are sorted by name.
+func (c *Checker) Run() (*Report, error) {
+	sessions, err := c.source.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing tmux sessions: %w", err)
+	}
+	sort.Strings(sessions)
+
+	var roles map[string]*config.CustomRoleConfig
+	if c.townRoot != "" {
+		roles, _ = config.LoadCustomRoles(c.townRoot)
+	}
+	report := &Report{Sessions: []SessionHealth{}}
+	for _, sess := range sessions {
+		if role, ok := session.ParseCustomRoleSession(sess, roles); ok {
+			identity := &session.AgentIdentity{Role: session.Role(role.Role), Rig: role.Rig, Name: role.Name}
+			report.Sessions = append(report.Sessions, c.Check(sess, identity))
+			continue
+		}
+		if !strings.HasPrefix(sess, session.Prefix) && !strings.HasPrefix(sess, session.HQPrefix) {
+			continue
+		}
+		identity, err := session.ParseSessionName(sess)
+		if err != nil {
+			continue // Not an agent session (e.g. a helper session)
+		}
+		report.Sessions = append(report.Sessions, c.Check(sess, identity))
+	}
+
+	if c.townRoot != "" {
+		if report.StaleLocks, err = c.staleLocks(sessions); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// Check probes a single session.
+func (c *Checker) Check(sess string, identity *session.AgentIdentity) SessionHealth {
+	h := SessionHealth{
+		Session: sess,
+		Role:    string(identity.Role),
+		Rig:     identity.Rig,
+		Name:    identity.Name,
+	}
+
+	alive := c.source.IsAgentAlive(sess)
+	process := Probe{Name: ProbeProcess, OK: alive}
+	if !alive {
+		process.Detail = "agent process not running in pane"
+	}
+	h.Probes = append(h.Probes, process)
+	if c.IdleAfter > 0 {
+		h.Probes = append(h.Probes, c.probeActivity(sess))
+	}
+	if alive {
+		h.Probes = append(h.Probes, c.probeOutput(sess, &h))
+	}
+	h.Probes = append(h.Probes, c.probeEnv(sess, identity))
+
+	switch {
+	case !alive:
+		h.Status = StatusDead
+	case !allOK(h.Probes):
+		h.Status = StatusDegraded
+	default:
+		h.Status = StatusHealthy
+	}
+	return h
+}
+
+// probeActivity fails when the pane has produced no output for IdleAfter.
+func (c *Checker) probeActivity(sess string) Probe {
+	p := Probe{Name: ProbeActivity}
+	info, err := c.source.GetSessionInfo(sess)
+	if err != nil {
+		p.Detail = fmt.Sprintf("could not read session info: %v", err)
+		return p
+	}
+	// session_activity is a Unix timestamp; older tmux may not report it
+	secs, err := strconv.ParseInt(strings.TrimSpace(info.Activity), 10, 64)
+	if err != nil || secs <= 0 {
+		p.OK = true
+		p.Detail = "activity time not reported by tmux"
+		return p
+	}
+	idle := c.now().Sub(time.Unix(secs, 0))
+	if idle > c.IdleAfter {
+		p.Detail = fmt.Sprintf("no pane output for %s", idle.Truncate(time.Minute))
+		return p
+	}
+	p.OK = true
+	return p
+}
+
+// probeOutput fails when the agent's pane shows it waiting for input or
+// stopped on an error, and records what the pane shows in h.Activity.
+func (c *Checker) probeOutput(sess string, h *SessionHealth) Probe {
+	p := Probe{Name: ProbeOutput}
+	pane, err := c.source.CapturePane(sess, 50)
+	if err != nil {
+		p.Detail = fmt.Sprintf("could not capture pane: %v", err)
+		return p
+	}
+	env, _ := c.source.GetAllEnvironment(sess)
+	result := agentstatus.ForAgent(env["GT_AGENT"]).Classify(pane)
+	h.Activity = result.Status
+	switch result.Status {
+	case agentstatus.StatusWaiting:
+		p.Detail = "waiting for input: " + result.Line
+	case agentstatus.StatusError:
+		p.Detail = "stopped on an error: " + result.Line
+	default:
+		p.OK = true
+		p.Detail = string(result.Status)
+	}
+	return p
+}
+
+// identityEnvVars are the AgentEnv variables a session needs to know
+// who it is; the rest (GIT_AUTHOR_NAME etc.) are checked by gt doctor.
+var identityEnvVars = []string{"GT_ROLE", "GT_RIG", "GT_POLECAT", "GT_CREW"}
+
+// probeEnv fails when the session is missing any identity variable that
+// config.AgentEnv sets for its role.
+func (c *Checker) probeEnv(sess string, identity *session.AgentIdentity) Probe {
+	p := Probe{Name: ProbeEnv}
+	actual, err := c.source.GetAllEnvironment(sess)
+	if err != nil {
+		p.Detail = fmt.Sprintf("could not read env vars: %v", err)
+		return p
+	}
+
+	// Custom roles have no AgentEnv variables, so their probe always passes
+	expected := config.AgentEnvSimple(string(identity.Role), identity.Rig, identity.Name)
+	var missing []string
+	for _, key := range identityEnvVars {
+		if _, want := expected[key]; !want {
+			continue
+		}
+		if actual[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		p.Detail = "missing " + strings.Join(missing, ", ")
+		return p
+	}
+	p.OK = true
+	return p
+}
+
+// staleLocks returns the town's worker locks whose PID is dead and whose
+// session is no longer running. A dead PID alone is normal: the process
+// that took the lock exits once the agent is launched in tmux.
+func (c *Checker) staleLocks(sessions []string) ([]StaleLock, error) {
+	locks, err := lock.FindAllLocks(c.townRoot)
+	if err != nil {
+		return nil, fmt.Errorf("scanning for locks: %w", err)
+	}
+
+	live := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		live[s] = true
+	}
+	// Locks may record the session ID ($N) rather than its name
+	ids, _ := c.source.ListSessionIDs()
+	for _, id := range ids {
+		live[id] = true
+	}
+
+	var stale []StaleLock
+	for workerDir, info := range locks {
+		if !info.IsStale() || (info.SessionID != "" && live[info.SessionID]) {
+			continue
+		}
+		stale = append(stale, StaleLock{WorkerDir: workerDir, PID: info.PID, Session: info.SessionID})
+	}
+	sort.Slice(stale, func(i, j int) bool { return stale[i].WorkerDir < stale[j].WorkerDir })
+	return stale, nil
+}
+
+func allOK(probes []Probe) bool {
+	for _, p := range probes {
+		if !p.OK {
+			return false
+		}
+	}
+	return true
+}
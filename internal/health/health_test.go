@@ -0,0 +1,267 @@
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+type fakeSource struct {
+	sessions []string
+	ids      map[string]string
+	alive    map[string]bool
+	env      map[string]map[string]string
+	activity map[string]time.Time
+	panes    map[string]string
+	listErr  error
+}
+
+func (f *fakeSource) ListSessions() ([]string, error)            { return f.sessions, f.listErr }
+func (f *fakeSource) ListSessionIDs() (map[string]string, error) { return f.ids, nil }
+func (f *fakeSource) IsAgentAlive(session string) bool           { return f.alive[session] }
+
+func (f *fakeSource) GetAllEnvironment(session string) (map[string]string, error) {
+	return f.env[session], nil
+}
+
+func (f *fakeSource) GetSessionInfo(session string) (*tmux.SessionInfo, error) {
+	info := &tmux.SessionInfo{Name: session}
+	if at, ok := f.activity[session]; ok {
+		info.Activity = strconv.FormatInt(at.Unix(), 10)
+	}
+	return info, nil
+}
+
+func (f *fakeSource) CapturePane(session string, lines int) (string, error) {
+	return f.panes[session], nil
+}
+
+var testNow = time.Date(2026, 1, 2, 15, 0, 0, 0, time.UTC)
+
+func newTestChecker(townRoot string, src *fakeSource) *Checker {
+	c := NewCheckerWithSource(townRoot, src)
+	c.now = func() time.Time { return testNow }
+	return c
+}
+
+func probe(h SessionHealth, name string) Probe {
+	for _, p := range h.Probes {
+		if p.Name == name {
+			return p
+		}
+	}
+	return Probe{}
+}
+
+func TestCheckerRun(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"hq-mayor", "gt-web-witness", "gt-web-crew-jane", "scratch"},
+		alive:    map[string]bool{"hq-mayor": true, "gt-web-crew-jane": true},
+		env: map[string]map[string]string{
+			"hq-mayor":         {"GT_ROLE": "mayor"},
+			"gt-web-witness":   {"GT_ROLE": "web/witness", "GT_RIG": "web"},
+			"gt-web-crew-jane": {"GT_ROLE": "web/crew/jane", "GT_RIG": "web"},
+		},
+		activity: map[string]time.Time{
+			"hq-mayor":         testNow.Add(-5 * time.Minute),
+			"gt-web-witness":   testNow.Add(-5 * time.Minute),
+			"gt-web-crew-jane": testNow.Add(-3 * time.Hour),
+		},
+	}
+
+	report, err := newTestChecker("", src).Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Sessions) != 3 {
+		t.Fatalf("got %d sessions, want 3 (non-Gas Town sessions skipped): %+v", len(report.Sessions), report.Sessions)
+	}
+
+	byName := map[string]SessionHealth{}
+	for _, s := range report.Sessions {
+		byName[s.Session] = s
+	}
+
+	if got := byName["hq-mayor"]; got.Status != StatusHealthy || got.Role != "mayor" {
+		t.Errorf("mayor = %+v, want healthy", got)
+	}
+	if got := byName["gt-web-witness"]; got.Status != StatusDead || got.Rig != "web" {
+		t.Errorf("witness = %+v, want dead", got)
+	}
+
+	jane := byName["gt-web-crew-jane"]
+	if jane.Status != StatusDegraded || jane.Name != "jane" {
+		t.Errorf("crew = %+v, want degraded", jane)
+	}
+	if p := probe(jane, ProbeActivity); p.OK || p.Detail != "no pane output for 3h0m0s" {
+		t.Errorf("activity probe = %+v, want idle for 3h", p)
+	}
+	if p := probe(jane, ProbeEnv); p.OK || p.Detail != "missing GT_CREW" {
+		t.Errorf("env probe = %+v, want missing GT_CREW", p)
+	}
+
+	if report.Unhealthy() != 2 {
+		t.Errorf("Unhealthy() = %d, want 2", report.Unhealthy())
+	}
+}
+
+func TestCheckerRun_CustomRoles(t *testing.T) {
+	townRoot := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Roles = map[string]*config.CustomRoleConfig{
+		"auditor":   {Session: "gt-{rig}-auditor"},
+		"librarian": {Session: "hq-librarian"},
+	}
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+	src := &fakeSource{
+		sessions: []string{"gt-web-auditor", "hq-librarian"},
+		alive:    map[string]bool{"gt-web-auditor": true, "hq-librarian": true},
+		activity: map[string]time.Time{
+			"gt-web-auditor": testNow,
+			"hq-librarian":   testNow,
+		},
+	}
+
+	report, err := newTestChecker(townRoot, src).Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(report.Sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2: %+v", len(report.Sessions), report.Sessions)
+	}
+	auditor, librarian := report.Sessions[0], report.Sessions[1]
+	if auditor.Role != "auditor" || auditor.Rig != "web" || auditor.Status != StatusHealthy {
+		t.Errorf("auditor = %+v, want a healthy auditor of rig web", auditor)
+	}
+	if librarian.Role != "librarian" || librarian.Status != StatusHealthy {
+		t.Errorf("librarian = %+v, want a healthy librarian (no polecat env expected)", librarian)
+	}
+}
+
+func TestCheckerIdleDisabled(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"hq-deacon"},
+		alive:    map[string]bool{"hq-deacon": true},
+		env:      map[string]map[string]string{"hq-deacon": {"GT_ROLE": "deacon"}},
+		activity: map[string]time.Time{"hq-deacon": testNow.Add(-48 * time.Hour)},
+	}
+	c := newTestChecker("", src)
+	c.IdleAfter = 0
+
+	report, err := c.Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	got := report.Sessions[0]
+	if got.Status != StatusHealthy {
+		t.Errorf("status = %s, want healthy with the activity probe disabled", got.Status)
+	}
+	if p := probe(got, ProbeActivity); p.Name != "" {
+		t.Errorf("activity probe ran with IdleAfter=0: %+v", p)
+	}
+}
+
+func TestCheckerActivityNotReported(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"hq-mayor"},
+		alive:    map[string]bool{"hq-mayor": true},
+		env:      map[string]map[string]string{"hq-mayor": {"GT_ROLE": "mayor"}},
+	}
+	report, err := newTestChecker("", src).Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := report.Sessions[0]; got.Status != StatusHealthy {
+		t.Errorf("status = %s, want healthy when tmux omits session_activity", got.Status)
+	}
+}
+
+func TestCheckerListError(t *testing.T) {
+	src := &fakeSource{listErr: errors.New("boom")}
+	if _, err := newTestChecker("", src).Run(); err == nil {
+		t.Fatal("Run succeeded, want the list error")
+	}
+}
+
+func writeLock(t *testing.T, workerDir string, pid int, sessionID string) {
+	t.Helper()
+	dir := filepath.Join(workerDir, ".runtime")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(map[string]any{"pid": pid, "acquired_at": testNow, "session_id": sessionID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "agent.lock"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckerStaleLocks(t *testing.T) {
+	townRoot := t.TempDir()
+	const deadPID = 1 << 22 // above the default Linux pid_max
+	writeLock(t, filepath.Join(townRoot, "web", "polecats", "gone"), deadPID, "gt-web-gone")
+	writeLock(t, filepath.Join(townRoot, "web", "polecats", "live"), deadPID, "gt-web-live")
+	writeLock(t, filepath.Join(townRoot, "web", "polecats", "byid"), deadPID, "$7")
+	writeLock(t, filepath.Join(townRoot, "web", "crew", "jane"), os.Getpid(), "gt-web-crew-jane")
+
+	src := &fakeSource{
+		sessions: []string{"gt-web-live"},
+		ids:      map[string]string{"gt-web-live": "$7"},
+		alive:    map[string]bool{"gt-web-live": true},
+	}
+	report, err := newTestChecker(townRoot, src).Run()
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if len(report.StaleLocks) != 1 {
+		t.Fatalf("stale locks = %+v, want only the lock of the session that is gone", report.StaleLocks)
+	}
+	got := report.StaleLocks[0]
+	if got.WorkerDir != filepath.Join(townRoot, "web", "polecats", "gone") || got.PID != deadPID || got.Session != "gt-web-gone" {
+		t.Errorf("stale lock = %+v", got)
+	}
+}
+
+func TestCheckOutput(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"gt-web-crew-jane", "gt-web-crew-max", "gt-web-crew-sam"},
+		alive:    map[string]bool{"gt-web-crew-jane": true, "gt-web-crew-max": true},
+		env: map[string]map[string]string{
+			"gt-web-crew-jane": {"GT_ROLE": "crew", "GT_RIG": "web", "GT_CREW": "jane"},
+			"gt-web-crew-max":  {"GT_ROLE": "crew", "GT_RIG": "web", "GT_CREW": "max", "GT_AGENT": "aider"},
+		},
+		panes: map[string]string{
+			"gt-web-crew-jane": "✻ Thinking… (esc to interrupt)\n❯",
+			"gt-web-crew-max":  "Add file to the chat? (Y)es/(N)o [Yes]:",
+		},
+	}
+	c := newTestChecker("", src)
+	c.IdleAfter = 0
+	report, err := c.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jane, max, sam := report.Sessions[0], report.Sessions[1], report.Sessions[2]
+
+	if jane.Status != StatusHealthy || jane.Activity != "working" {
+		t.Errorf("jane = %s, %s; want healthy and working", jane.Status, jane.Activity)
+	}
+	if p := probe(max, ProbeOutput); max.Status != StatusDegraded || p.OK || p.Detail != "waiting for input: Add file to the chat? (Y)es/(N)o [Yes]:" {
+		t.Errorf("max = %s, output probe %+v; want degraded, waiting for input", max.Status, p)
+	}
+	if p := probe(sam, ProbeOutput); sam.Activity != "" || p.Name != "" {
+		t.Errorf("dead sam: activity %q, output probe %+v; want neither", sam.Activity, p)
+	}
+}
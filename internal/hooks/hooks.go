@@ -0,0 +1,222 @@
+// Package hooks runs user scripts on Gas Town lifecycle events.
+//
+// Scripts live in an <event>.d directory under .gastown/hooks, in the town
+// root or the user's home directory:
+//
+//	~/gt/.gastown/hooks/
+//	  bead-completed.d/
+//	    10-slack.sh      <- Run first
+//	    20-metrics.py    <- Run second
+//	  agent-crash.d/
+//	    page-me.sh
+//
+// Every executable file in the directory is run, in name order, home
+// directory scripts before town ones. A script gets the event as environment
+// variables (GT_HOOK_EVENT, GT_TOWN_ROOT, and GT_HOOK_<FIELD> for each
+// payload field) and as JSON on stdin. Hooks are best-effort: a failing or
+// slow script is reported on stderr and never fails the gt command that
+// fired it.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Event is a lifecycle event scripts can hook.
+type Event string
+
+// Lifecycle events.
+const (
+	SessionStart  Event = "session-start"  // An agent session primed
+	SessionEnd    Event = "session-end"    // An agent session was shut down
+	Handoff       Event = "handoff"        // A session handed off to a fresh one
+	BeadAssigned  Event = "bead-assigned"  // Work was slung or hooked to an agent
+	BeadCompleted Event = "bead-completed" // An agent finished its work (gt done)
+	AgentCrash    Event = "agent-crash"    // An agent exited unexpectedly
+	// HandoffContext scripts print the outgoing session's summary for
+	// gt handoff --with-context (see Collect)
+	HandoffContext Event = "handoff-context"
+	// ReportSummary scripts print a summary of a session's pane tail for
+	// the witness report (see Collect)
+	ReportSummary Event = "report-summary"
+	WitnessReport Event = "witness-report" // A witness report was written
+)
+
+// Events lists every lifecycle event, in lifecycle order.
+var Events = []Event{SessionStart, BeadAssigned, BeadCompleted, HandoffContext, Handoff, SessionEnd, AgentCrash, ReportSummary, WitnessReport}
+
+// Valid reports whether e is a known event.
+func (e Event) Valid() bool {
+	return slices.Contains(Events, e)
+}
+
+// Dir is the hooks directory, relative to the town root or home directory.
+const Dir = ".gastown/hooks"
+
+// EnvEvent is set to the event in a hook script's environment. gt commands
+// run by a hook script don't fire hooks themselves, so a script can't
+// trigger itself.
+const EnvEvent = "GT_HOOK_EVENT"
+
+// Timeout bounds each script. Scripts with slow work should background it.
+var Timeout = 30 * time.Second
+
+// Payload is what a hook script receives as JSON on stdin.
+type Payload struct {
+	Event    Event             `json:"event"`
+	Time     time.Time         `json:"time"`
+	TownRoot string            `json:"town_root,omitempty"`
+	Fields   map[string]string `json:"fields,omitempty"`
+}
+
+// Scripts returns the executable scripts for event: those in the home
+// directory, then the town's (townRoot may be empty), each in name order.
+func Scripts(townRoot string, event Event) []string {
+	var roots []string
+	if home, err := os.UserHomeDir(); err == nil {
+		roots = append(roots, home)
+	}
+	if townRoot != "" && !slices.Contains(roots, townRoot) {
+		roots = append(roots, townRoot)
+	}
+
+	var scripts []string
+	for _, root := range roots {
+		dir := filepath.Join(root, Dir, string(event)+".d")
+		entries, err := os.ReadDir(dir) // Sorted by name
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			info, err := e.Info()
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			scripts = append(scripts, filepath.Join(dir, e.Name()))
+		}
+	}
+	return scripts
+}
+
+// Fire runs the scripts hooked to event with the payload fields. An empty
+// townRoot is found from the working directory. Script output and failures
+// go to stderr; Fire never fails.
+func Fire(townRoot string, event Event, fields map[string]string) {
+	if os.Getenv(EnvEvent) != "" {
+		return
+	}
+	if townRoot == "" {
+		townRoot, _ = workspace.FindFromCwd()
+	}
+	scripts := Scripts(townRoot, event)
+	if len(scripts) == 0 {
+		return
+	}
+	payload := Payload{Event: event, Time: time.Now().UTC(), TownRoot: townRoot, Fields: fields}
+	for _, script := range scripts {
+		err := Run(script, payload, os.Stderr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s hook %s failed: %v\n", event, filepath.Base(script), err)
+		}
+		logFired(payload, script, err)
+	}
+}
+
+// Collect runs the scripts hooked to event like Fire, but returns what
+// they print on stdout, concatenated in run order. Their stderr and
+// failures go to stderr. A failed script's output is dropped.
+func Collect(townRoot string, event Event, fields map[string]string) string {
+	if os.Getenv(EnvEvent) != "" {
+		return ""
+	}
+	if townRoot == "" {
+		townRoot, _ = workspace.FindFromCwd()
+	}
+	payload := Payload{Event: event, Time: time.Now().UTC(), TownRoot: townRoot, Fields: fields}
+	var collected strings.Builder
+	for _, script := range Scripts(townRoot, event) {
+		var out bytes.Buffer
+		err := run(script, payload, &out, os.Stderr)
+		logFired(payload, script, err)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s hook %s failed: %v\n", event, filepath.Base(script), err)
+			continue
+		}
+		collected.Write(out.Bytes())
+	}
+	return collected.String()
+}
+
+// FiredMsg is the gt log message recorded for each hook script run, with
+// the event, script and payload fields as attributes (see gt replay).
+const FiredMsg = "hook fired"
+
+// logFired records a script run in the gt log.
+func logFired(payload Payload, script string, err error) {
+	attrs := []any{"event", string(payload.Event), "script", filepath.Base(script), "fields", payload.Fields}
+	if err != nil {
+		gtlog.L().Warn(FiredMsg, append(attrs, "err", err.Error())...)
+		return
+	}
+	gtlog.L().Info(FiredMsg, attrs...)
+}
+
+// Run runs one hook script with payload, writing its output to out.
+func Run(script string, payload Payload, out io.Writer) error {
+	return run(script, payload, out, out)
+}
+
+func run(script string, payload Payload, stdout, stderr io.Writer) error {
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, script) //nolint:gosec // G204: scripts are the user's own
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Dir = payload.TownRoot
+	cmd.Env = append(os.Environ(), Env(payload)...)
+	// A script that backgrounds work may leave its output open
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", Timeout)
+		}
+		return err
+	}
+	return nil
+}
+
+// Env returns the environment variables describing payload: GT_HOOK_EVENT,
+// GT_TOWN_ROOT and GT_HOOK_<FIELD> for each field, upper-cased with dashes
+// as underscores (bead -> GT_HOOK_BEAD).
+func Env(payload Payload) []string {
+	env := []string{EnvEvent + "=" + string(payload.Event)}
+	if payload.TownRoot != "" {
+		env = append(env, "GT_TOWN_ROOT="+payload.TownRoot)
+	}
+	for _, k := range slices.Sorted(maps.Keys(payload.Fields)) {
+		name := strings.ToUpper(strings.ReplaceAll(k, "-", "_"))
+		env = append(env, "GT_HOOK_"+name+"="+payload.Fields[k])
+	}
+	return env
+}
@@ -0,0 +1,128 @@
+package hooks
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeScript(t *testing.T, root string, event Event, name, body string, mode os.FileMode) string {
+	t.Helper()
+	dir := filepath.Join(root, Dir, string(event)+".d")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestScriptsOrder(t *testing.T) {
+	home, town := t.TempDir(), t.TempDir()
+	t.Setenv("HOME", home)
+
+	homeScript := writeScript(t, home, BeadCompleted, "50-home.sh", "", 0755)
+	town20 := writeScript(t, town, BeadCompleted, "20-metrics.sh", "", 0755)
+	town10 := writeScript(t, town, BeadCompleted, "10-slack.sh", "", 0755)
+	writeScript(t, town, BeadCompleted, "README", "", 0644) // Not executable
+	writeScript(t, town, AgentCrash, "page.sh", "", 0755)   // Another event
+
+	got := Scripts(town, BeadCompleted)
+	if want := []string{homeScript, town10, town20}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Scripts() = %q, want %q", got, want)
+	}
+	if got := Scripts(town, Handoff); len(got) != 0 {
+		t.Errorf("Scripts(handoff) = %q, want none", got)
+	}
+}
+
+func TestFirePassesPayload(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	town := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvEvent, "")
+	out := filepath.Join(t.TempDir(), "out")
+	writeScript(t, town, BeadCompleted, "record.sh",
+		`echo "$GT_HOOK_EVENT $GT_HOOK_BEAD $GT_HOOK_AGENT_ID $PWD" > `+out+"\ncat >> "+out+"\n", 0755)
+
+	Fire(town, BeadCompleted, map[string]string{"bead": "gt-abc", "agent-id": "gastown/crew/max"})
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	envLine, stdin, _ := strings.Cut(string(data), "\n")
+	if want := "bead-completed gt-abc gastown/crew/max " + town; envLine != want {
+		t.Errorf("hook env = %q, want %q", envLine, want)
+	}
+	var payload Payload
+	if err := json.Unmarshal([]byte(stdin), &payload); err != nil {
+		t.Fatalf("hook stdin %q: %v", stdin, err)
+	}
+	if payload.Event != BeadCompleted || payload.TownRoot != town || payload.Fields["bead"] != "gt-abc" {
+		t.Errorf("hook payload = %+v", payload)
+	}
+}
+
+func TestFireSkippedInsideHook(t *testing.T) {
+	town := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvEvent, string(Handoff))
+	out := filepath.Join(t.TempDir(), "out")
+	writeScript(t, town, BeadAssigned, "record.sh", "touch "+out+"\n", 0755)
+
+	Fire(town, BeadAssigned, nil)
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("hook fired from inside another hook")
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	orig := Timeout
+	Timeout = 100 * time.Millisecond
+	defer func() { Timeout = orig }()
+
+	script := writeScript(t, t.TempDir(), AgentCrash, "slow.sh", "exec sleep 5\n", 0755)
+	start := time.Now()
+	err := Run(script, Payload{Event: AgentCrash}, os.Stderr)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run() = %v, want a timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Run() took %s, want it cut off", elapsed)
+	}
+}
+
+func TestCollectReturnsStdout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script")
+	}
+	town := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(EnvEvent, "")
+	writeScript(t, town, HandoffContext, "10-first.sh", "echo \"first $GT_HOOK_SESSION\"\necho noise >&2\n", 0755)
+	writeScript(t, town, HandoffContext, "20-broken.sh", "echo partial\nexit 1\n", 0755)
+	writeScript(t, town, HandoffContext, "30-last.sh", "echo last\n", 0755)
+
+	got := Collect(town, HandoffContext, map[string]string{"session": "hq-mayor"})
+	if want := "first hq-mayor\nlast\n"; got != want {
+		t.Errorf("Collect() = %q, want %q", got, want)
+	}
+
+	t.Setenv(EnvEvent, string(Handoff))
+	if got := Collect(town, HandoffContext, nil); got != "" {
+		t.Errorf("Collect() inside a hook = %q, want nothing", got)
+	}
+}
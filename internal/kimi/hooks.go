@@ -0,0 +1,187 @@
+// Package kimi provides Kimi CLI hook configuration.
+//
+// Gas Town's hook definitions are written for Claude Code (see package
+// claude and gt hooks install). Kimi reads its hooks from .kimi/settings.json
+// with its own event and tool names, so the definitions are translated
+// here rather than kept as a second, drifting copy:
+//
+//	{
+//	  "hooks": {
+//	    "session_start": [{"command": "gt prime --hook"}],
+//	    "pre_tool_use": [{"matcher": "Shell(gh pr create*)", "command": "gt tap guard pr-workflow"}]
+//	  }
+//	}
+package kimi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+// ErrUnsupportedEvent indicates a Claude Code hook event Kimi has no
+// equivalent for.
+var ErrUnsupportedEvent = errors.New("hook event not supported by kimi")
+
+// eventNames maps Claude Code hook events to Kimi's.
+var eventNames = map[string]string{
+	"SessionStart":     "session_start",
+	"SessionEnd":       "session_end",
+	"UserPromptSubmit": "user_prompt_submit",
+	"PreToolUse":       "pre_tool_use",
+	"PostToolUse":      "post_tool_use",
+	"PreCompact":       "pre_compact",
+	"Stop":             "stop",
+	"SubagentStop":     "subagent_stop",
+	"Notification":     "notification",
+}
+
+// toolNames maps Claude Code tool names, as used in hook matchers, to
+// Kimi's. Tools not listed keep their name.
+var toolNames = map[string]string{
+	"Bash":      "Shell",
+	"Read":      "ReadFile",
+	"Write":     "WriteFile",
+	"Edit":      "StrReplaceFile",
+	"MultiEdit": "StrReplaceFile",
+	"WebFetch":  "FetchURL",
+	"WebSearch": "SearchWeb",
+}
+
+// Hook is a Kimi hook: Command runs on the event for subjects Matcher
+// selects (empty selects everything).
+type Hook struct {
+	Matcher string `json:"matcher,omitempty"`
+	Command string `json:"command"`
+	Timeout int    `json:"timeout,omitempty"`
+}
+
+// Event returns Kimi's name for a Claude Code hook event. Fails with
+// ErrUnsupportedEvent if Kimi has none.
+func Event(claudeEvent string) (string, error) {
+	name, ok := eventNames[claudeEvent]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedEvent, claudeEvent)
+	}
+	return name, nil
+}
+
+// Matcher translates a Claude Code hook matcher ("Bash(gh pr create*)",
+// "Write|Edit") to Kimi's tool names ("Shell(gh pr create*)",
+// "WriteFile|StrReplaceFile").
+func Matcher(claudeMatcher string) string {
+	alternatives := strings.Split(claudeMatcher, "|")
+	for i, alt := range alternatives {
+		tool, args, hasArgs := strings.Cut(alt, "(")
+		if name, ok := toolNames[strings.TrimSpace(tool)]; ok {
+			tool = name
+		}
+		if hasArgs {
+			tool += "(" + args
+		}
+		alternatives[i] = tool
+	}
+	return strings.Join(alternatives, "|")
+}
+
+// Translate converts Claude Code hook definitions to Kimi hooks, keyed by
+// Kimi event name. Events Kimi lacks are skipped and returned, sorted, so
+// callers can warn about them.
+func Translate(hooks map[string][]claude.HookMatcher) (map[string][]Hook, []string) {
+	translated := make(map[string][]Hook)
+	var skipped []string
+	for event, matchers := range hooks {
+		name, err := Event(event)
+		if err != nil {
+			skipped = append(skipped, event)
+			continue
+		}
+		for _, m := range matchers {
+			for _, h := range m.Hooks {
+				if h.Type != "command" || h.Command == "" {
+					continue
+				}
+				translated[name] = append(translated[name], Hook{Matcher: Matcher(m.Matcher), Command: h.Command, Timeout: h.Timeout})
+			}
+		}
+	}
+	slices.Sort(skipped)
+	return translated, skipped
+}
+
+// EnsureSettingsForRoleAt ensures Kimi's settings file exists with Gas
+// Town's hooks for role, translated from the Claude Code definitions.
+// If the file already exists, it's left unchanged.
+func EnsureSettingsForRoleAt(workDir, role, settingsDir, settingsFile string) error {
+	if settingsDir == "" || settingsFile == "" {
+		return nil
+	}
+	settingsPath := filepath.Join(workDir, settingsDir, settingsFile)
+	if _, err := os.Stat(settingsPath); err == nil {
+		return nil
+	}
+
+	claudeHooks, err := claude.Hooks(claude.RoleTypeFor(role))
+	if err != nil {
+		return err
+	}
+	hooks, _ := Translate(claudeHooks)
+	return writeSettings(settingsPath, map[string]json.RawMessage{}, hooks)
+}
+
+// AddHook adds a hook for a Claude Code event and matcher to the Kimi
+// settings file at path, translating both, and keeps the file's other
+// settings. A hook with the same matcher and command isn't added twice.
+func AddHook(path, claudeEvent, claudeMatcher, command string) error {
+	name, err := Event(claudeEvent)
+	if err != nil {
+		return err
+	}
+
+	settings := make(map[string]json.RawMessage)
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("parsing existing settings: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	hooks := make(map[string][]Hook)
+	if raw, ok := settings["hooks"]; ok {
+		if err := json.Unmarshal(raw, &hooks); err != nil {
+			return fmt.Errorf("parsing existing hooks: %w", err)
+		}
+	}
+
+	hook := Hook{Matcher: Matcher(claudeMatcher), Command: command}
+	if !slices.Contains(hooks[name], hook) {
+		hooks[name] = append(hooks[name], hook)
+	}
+	return writeSettings(path, settings, hooks)
+}
+
+// writeSettings writes settings with its hooks replaced by hooks.
+func writeSettings(path string, settings map[string]json.RawMessage, hooks map[string][]Hook) error {
+	raw, err := json.Marshal(hooks)
+	if err != nil {
+		return err
+	}
+	settings["hooks"] = raw
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating settings directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("writing settings: %w", err)
+	}
+	return nil
+}
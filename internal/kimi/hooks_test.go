@@ -0,0 +1,118 @@
+package kimi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/claude"
+)
+
+func TestMatcher(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"":                    "",
+		"Bash(gh pr create*)": "Shell(gh pr create*)",
+		"Write|Edit":          "WriteFile|StrReplaceFile",
+		"mcp__github":         "mcp__github",
+	}
+	for in, want := range tests {
+		if got := Matcher(in); got != want {
+			t.Errorf("Matcher(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	hooks := map[string][]claude.HookMatcher{
+		"SessionStart": {{Hooks: []claude.Hook{{Type: "command", Command: "gt prime --hook"}}}},
+		"PreToolUse": {{
+			Matcher: "Bash(git switch -c*)",
+			Hooks:   []claude.Hook{{Type: "command", Command: "gt tap guard pr-workflow", Timeout: 10}},
+		}},
+		"FutureEvent": {{Hooks: []claude.Hook{{Type: "command", Command: "true"}}}},
+	}
+	got, skipped := Translate(hooks)
+	want := map[string][]Hook{
+		"session_start": {{Command: "gt prime --hook"}},
+		"pre_tool_use":  {{Matcher: "Shell(git switch -c*)", Command: "gt tap guard pr-workflow", Timeout: 10}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Translate() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(skipped, []string{"FutureEvent"}) {
+		t.Errorf("Translate() skipped = %v, want [FutureEvent]", skipped)
+	}
+}
+
+func TestTranslateCanonicalHooks(t *testing.T) {
+	t.Parallel()
+	// Every event Gas Town hooks must have a Kimi equivalent
+	for _, roleType := range []claude.RoleType{claude.Autonomous, claude.Interactive} {
+		hooks, err := claude.Hooks(roleType)
+		if err != nil {
+			t.Fatalf("claude.Hooks(%s): %v", roleType, err)
+		}
+		if _, skipped := Translate(hooks); len(skipped) > 0 {
+			t.Errorf("%s hooks without a kimi event: %v", roleType, skipped)
+		}
+	}
+}
+
+func TestEnsureSettingsForRoleAtKeepsExisting(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".kimi", "settings.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"theme": "dark"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := EnsureSettingsForRoleAt(dir, "crew", ".kimi", "settings.json"); err != nil {
+		t.Fatalf("EnsureSettingsForRoleAt: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != `{"theme": "dark"}` {
+		t.Errorf("existing settings overwritten: %s", data)
+	}
+}
+
+func TestAddHook(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(`{"theme": "dark"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := AddHook(path, "PreToolUse", "Bash(gh pr create*)", "gt tap guard pr-workflow"); err != nil {
+			t.Fatalf("AddHook: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var settings struct {
+		Theme string            `json:"theme"`
+		Hooks map[string][]Hook `json:"hooks"`
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Theme != "dark" {
+		t.Errorf("other settings lost: %s", data)
+	}
+	want := []Hook{{Matcher: "Shell(gh pr create*)", Command: "gt tap guard pr-workflow"}}
+	if !reflect.DeepEqual(settings.Hooks["pre_tool_use"], want) {
+		t.Errorf("pre_tool_use = %+v, want %+v (once)", settings.Hooks["pre_tool_use"], want)
+	}
+
+	if err := AddHook(path, "FutureEvent", "", "true"); !errors.Is(err, ErrUnsupportedEvent) {
+		t.Errorf("AddHook(unknown event) = %v, want ErrUnsupportedEvent", err)
+	}
+}
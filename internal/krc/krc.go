@@ -73,6 +73,7 @@ func DefaultConfig() *Config {
 			"done":          14 * 24 * time.Hour, // 14 days
 			"hook":          14 * 24 * time.Hour, // 14 days
 			"unhook":        14 * 24 * time.Hour, // 14 days
+			"handback":      14 * 24 * time.Hour, // 14 days
 
 			// Death events - keep for forensics
 			"session_death": 30 * 24 * time.Hour, // 30 days
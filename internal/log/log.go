@@ -0,0 +1,201 @@
+// Package log is Gas Town's structured log. Every gt command and daemon
+// writes JSON lines (log/slog) to one file, ~/.gastown/logs/gt.jsonl, each
+// tagged with the role and agent that wrote it, so a failed handoff or
+// respawn can be traced afterwards with gt logs instead of rerunning it.
+//
+// The level defaults to info and is set with GT_LOG_LEVEL (debug, info,
+// warn, error). GT_LOG_DIR moves the log, and GT_LOG_STDERR=1 also writes
+// entries as text to stderr. Logging never fails a command: if the file
+// can't be opened, entries are dropped.
+package log
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Environment variables that configure the log.
+const (
+	EnvLevel  = "GT_LOG_LEVEL"
+	EnvDir    = "GT_LOG_DIR"
+	EnvStderr = "GT_LOG_STDERR"
+)
+
+// FileName is the log file in Dir.
+const FileName = "gt.jsonl"
+
+// maxSize is the size at which the log is rotated to gt.jsonl.1, replacing
+// the previous rotation.
+const maxSize = 10 << 20
+
+var (
+	mu     sync.Mutex
+	logger = slog.New(discardHandler{})
+	file   *os.File
+)
+
+// Dir returns the log directory: GT_LOG_DIR, or ~/.gastown/logs.
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "gastown-logs")
+	}
+	return filepath.Join(home, ".gastown", "logs")
+}
+
+// Path returns the log file.
+func Path() string {
+	return filepath.Join(Dir(), FileName)
+}
+
+// RotatedPath returns where the log is moved when it is rotated.
+func RotatedPath() string {
+	return Path() + ".1"
+}
+
+// ParseLevel parses a level name: debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q (want debug, info, warn or error)", s)
+	}
+	return level, nil
+}
+
+// Init opens the log for this process, tagging its entries with role (e.g.,
+// "crew", "daemon") and agent (e.g., "gastown/crew/max"). Calling it again
+// replaces the tags. Until Init, L discards everything.
+func Init(role, agent string) error {
+	level := slog.LevelInfo
+	var levelErr error
+	if s := os.Getenv(EnvLevel); s != "" {
+		level, levelErr = ParseLevel(s)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var handlers []slog.Handler
+	if file == nil {
+		f, err := open(Path())
+		if err != nil {
+			return errors.Join(levelErr, err)
+		}
+		file = f
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	handlers = append(handlers, slog.NewJSONHandler(file, opts))
+	if os.Getenv(EnvStderr) != "" {
+		handlers = append(handlers, slog.NewTextHandler(os.Stderr, opts))
+	}
+
+	var h slog.Handler = fanoutHandler(handlers)
+	if len(handlers) == 1 {
+		h = handlers[0]
+	}
+	attrs := []any{"role", role, "pid", os.Getpid()}
+	if agent != "" {
+		attrs = append(attrs, "agent", agent)
+	}
+	logger = slog.New(h).With(attrs...)
+	return levelErr
+}
+
+// open opens the log at path for appending, rotating it first if it has
+// grown past maxSize.
+func open(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating log directory: %w", err)
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxSize {
+		_ = os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is the log file
+	if err != nil {
+		return nil, fmt.Errorf("opening log: %w", err)
+	}
+	return f, nil
+}
+
+// L returns the process's logger.
+func L() *slog.Logger {
+	mu.Lock()
+	defer mu.Unlock()
+	return logger
+}
+
+// Close closes the log file. Later entries are discarded.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	logger = slog.New(discardHandler{})
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	return err
+}
+
+// SetOutput sends the log to w instead of the file, for tests.
+func SetOutput(w io.Writer, level slog.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	logger = slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// discardHandler drops every entry.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (d discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return d }
+func (d discardHandler) WithGroup(string) slog.Handler           { return d }
+
+// fanoutHandler sends each entry to every handler that wants it.
+type fanoutHandler []slog.Handler
+
+func (f fanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanoutHandler) WithGroup(name string) slog.Handler {
+	out := make(fanoutHandler, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInitWritesTaggedEntries(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	t.Setenv(EnvLevel, "warn")
+	t.Cleanup(func() { _ = Close() })
+
+	if err := Init("crew", "gastown/crew/max"); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	L().Info("dropped below warn")
+	L().Error("handoff respawn failed", "session", "gt-gastown-crew-max")
+
+	entries, err := Read(Path(), Filter{})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1: %+v", len(entries), entries)
+	}
+	e := entries[0]
+	if e.Msg != "handoff respawn failed" || e.Level != slog.LevelError {
+		t.Errorf("entry = %q at %v", e.Msg, e.Level)
+	}
+	if e.Role != "crew" || e.Agent != "gastown/crew/max" || e.PID != os.Getpid() {
+		t.Errorf("tags = role %q agent %q pid %d", e.Role, e.Agent, e.PID)
+	}
+	if got := e.AttrString(); got != "session=gt-gastown-crew-max" {
+		t.Errorf("AttrString() = %q", got)
+	}
+}
+
+func TestInitBadLevel(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	t.Setenv(EnvLevel, "loud")
+	t.Cleanup(func() { _ = Close() })
+
+	if err := Init("cli", ""); err == nil || !strings.Contains(err.Error(), "loud") {
+		t.Errorf("Init() error = %v, want invalid level", err)
+	}
+	// Still logs, at info
+	L().Info("kept")
+	if entries, _ := Read(Path(), Filter{}); len(entries) != 1 {
+		t.Errorf("got %d entries, want 1", len(entries))
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	now := time.Now()
+	e := Entry{Time: now, Level: slog.LevelWarn, Role: "crew", Agent: "gastown/crew/max"}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"zero", Filter{}, true},
+		{"role", Filter{Role: "crew"}, true},
+		{"other role", Filter{Role: "witness"}, false},
+		{"agent prefix", Filter{Agent: "gastown/"}, true},
+		{"other agent", Filter{Agent: "beads/"}, false},
+		{"level below", Filter{Level: slog.LevelInfo}, true},
+		{"level above", Filter{Level: slog.LevelError}, false},
+		{"since before", Filter{Since: now.Add(-time.Minute)}, true},
+		{"since after", Filter{Since: now.Add(time.Minute)}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(e); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadIncludesRotated(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvDir, dir)
+	line := func(msg string) string {
+		var buf bytes.Buffer
+		slog.New(slog.NewJSONHandler(&buf, nil)).Info(msg, "role", "daemon")
+		return buf.String()
+	}
+	if err := os.WriteFile(RotatedPath(), []byte(line("old")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(Path(), []byte(line("new")+"not json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := Read(Path(), Filter{Role: "daemon"})
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Msg != "old" || entries[1].Msg != "new" {
+		t.Errorf("entries = %+v, want old then new", entries)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	t.Cleanup(func() { _ = Close() })
+	if err := Init("daemon", ""); err != nil {
+		t.Fatal(err)
+	}
+	L().Info("before follow")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got := make(chan string, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- Follow(ctx, Path(), Filter{Level: slog.LevelWarn}, time.Millisecond, func(e Entry) {
+			got <- e.Msg
+		})
+	}()
+
+	time.Sleep(20 * time.Millisecond) // Let Follow take the starting offset
+	L().Info("filtered out")
+	L().Warn("restart throttled")
+
+	select {
+	case msg := <-got:
+		if msg != "restart throttled" {
+			t.Errorf("followed %q, want only the new warning", msg)
+		}
+	case <-ctx.Done():
+		t.Fatal("Follow saw no entry")
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Follow() error = %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package log
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is one line of the log.
+type Entry struct {
+	Time  time.Time
+	Level slog.Level
+	Msg   string
+	Role  string
+	Agent string
+	PID   int
+
+	// Attrs holds the entry's other attributes.
+	Attrs map[string]any
+}
+
+// ParseEntry parses a log line. It reports false for lines that aren't
+// entries.
+func ParseEntry(line []byte) (Entry, bool) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return Entry{}, false
+	}
+	var e Entry
+	ts, _ := raw[slog.TimeKey].(string)
+	t, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return Entry{}, false
+	}
+	e.Time = t
+	if s, ok := raw[slog.LevelKey].(string); ok {
+		_ = e.Level.UnmarshalText([]byte(s))
+	}
+	e.Msg, _ = raw[slog.MessageKey].(string)
+	e.Role, _ = raw["role"].(string)
+	e.Agent, _ = raw["agent"].(string)
+	if pid, ok := raw["pid"].(float64); ok {
+		e.PID = int(pid)
+	}
+	for _, key := range []string{slog.TimeKey, slog.LevelKey, slog.MessageKey, "role", "agent", "pid"} {
+		delete(raw, key)
+	}
+	if len(raw) > 0 {
+		e.Attrs = raw
+	}
+	return e, true
+}
+
+// AttrString formats the entry's attributes as sorted key=value pairs.
+func (e Entry) AttrString() string {
+	keys := make([]string, 0, len(e.Attrs))
+	for k := range e.Attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v, err := json.Marshal(e.Attrs[k])
+		if err != nil {
+			continue
+		}
+		s := string(v)
+		if str, ok := e.Attrs[k].(string); ok && !strings.ContainsAny(str, " \t\"=") && str != "" {
+			s = str
+		}
+		parts = append(parts, k+"="+s)
+	}
+	return strings.Join(parts, " ")
+}
+
+// Filter selects log entries. The zero Filter matches everything.
+type Filter struct {
+	Role  string     // role, e.g. "crew" or "daemon"
+	Agent string     // agent address prefix, e.g. "gastown/"
+	Level slog.Level // minimum level
+	Since time.Time
+}
+
+// Match reports whether e passes the filter.
+func (f Filter) Match(e Entry) bool {
+	if f.Role != "" && e.Role != f.Role {
+		return false
+	}
+	if f.Agent != "" && !strings.HasPrefix(e.Agent, f.Agent) {
+		return false
+	}
+	if e.Level < f.Level {
+		return false
+	}
+	return f.Since.IsZero() || !e.Time.Before(f.Since)
+}
+
+// Read returns the entries of the log at path that match f, oldest first,
+// including those already rotated to path.1. A missing log is empty.
+func Read(path string, f Filter) ([]Entry, error) {
+	var entries []Entry
+	for _, p := range []string{path + ".1", path} {
+		file, err := os.Open(p) //nolint:gosec // G304: path is the log file
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if e, ok := ParseEntry(scanner.Bytes()); ok && f.Match(e) {
+				entries = append(entries, e)
+			}
+		}
+		err = scanner.Err()
+		_ = file.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// Follow calls fn with each entry matching f that is appended to the log at
+// path from now on, polling every interval until ctx is done. A rotation
+// of the log is followed into the new file.
+func Follow(ctx context.Context, path string, f Filter, interval time.Duration, fn func(Entry)) error {
+	var offset int64
+	if info, err := os.Stat(path); err == nil {
+		offset = info.Size()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var partial []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			offset, partial = 0, nil
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if info.Size() < offset {
+			offset, partial = 0, nil // Rotated
+		}
+		if info.Size() == offset {
+			continue
+		}
+		data, err := readFrom(path, offset)
+		if err != nil {
+			return err
+		}
+		offset += int64(len(data))
+		data = append(partial, data...)
+		lines := strings.Split(string(data), "\n")
+		partial = []byte(lines[len(lines)-1]) // Not yet terminated
+		for _, line := range lines[:len(lines)-1] {
+			if e, ok := ParseEntry([]byte(line)); ok && f.Match(e) {
+				fn(e)
+			}
+		}
+	}
+}
+
+// readFrom reads the file at path from offset to its end.
+func readFrom(path string, offset int64) ([]byte, error) {
+	file, err := os.Open(path) //nolint:gosec // G304: path is the log file
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
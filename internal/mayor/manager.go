@@ -12,12 +12,14 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // Common errors
 var (
 	ErrNotRunning     = errors.New("mayor not running")
 	ErrAlreadyRunning = errors.New("mayor already running")
+	ErrNoStandby      = errors.New("no standby mayor running")
 )
 
 // Manager handles mayor lifecycle operations.
@@ -43,6 +45,11 @@ func (m *Manager) SessionName() string {
 	return SessionName()
 }
 
+// StandbySessionName returns the tmux session name for the standby mayor.
+func (m *Manager) StandbySessionName() string {
+	return session.MayorStandbySessionName()
+}
+
 // mayorDir returns the working directory for the mayor.
 func (m *Manager) mayorDir() string {
 	return filepath.Join(m.townRoot, "mayor")
@@ -51,8 +58,35 @@ func (m *Manager) mayorDir() string {
 // Start starts the mayor session.
 // agentOverride optionally specifies a different agent alias to use.
 func (m *Manager) Start(agentOverride string) error {
+	// Build startup beacon with explicit instructions (matches gt handoff behavior)
+	// This ensures the agent has clear context immediately, not after nudges arrive
+	beacon := session.FormatStartupBeacon(session.BeaconConfig{
+		Recipient: "mayor",
+		Sender:    "human",
+		Topic:     "cold-start",
+	})
+	return m.start(m.SessionName(), "Mayor", beacon, agentOverride)
+}
+
+// StartStandby starts a standby mayor: a second mayor session that idles
+// until the watchdog promotes it (see Promote) because the mayor's session
+// is gone. agentOverride optionally specifies a different agent alias to use.
+func (m *Manager) StartStandby(agentOverride string) error {
+	beacon := session.FormatStartupBeacon(session.BeaconConfig{
+		Recipient: "mayor",
+		Sender:    "human",
+		Topic:     "standby",
+		Context: "You are the STANDBY mayor. Another mayor is coordinating the town. " +
+			"Do not act on hooks, mail, or convoys. Stay idle until you are promoted: " +
+			"a failover message will tell you when you have taken over.",
+	})
+	return m.start(m.StandbySessionName(), "Mayor (standby)", beacon, agentOverride)
+}
+
+// start creates a mayor session named sessionID, in the mayor directory,
+// with beacon as the agent's initial prompt.
+func (m *Manager) start(sessionID, worker, beacon, agentOverride string) error {
 	t := tmux.NewTmux()
-	sessionID := m.SessionName()
 
 	// Check if session already exists
 	running, _ := t.HasSession(sessionID)
@@ -79,14 +113,6 @@ func (m *Manager) Start(agentOverride string) error {
 		return fmt.Errorf("ensuring runtime settings: %w", err)
 	}
 
-	// Build startup beacon with explicit instructions (matches gt handoff behavior)
-	// This ensures the agent has clear context immediately, not after nudges arrive
-	beacon := session.FormatStartupBeacon(session.BeaconConfig{
-		Recipient: "mayor",
-		Sender:    "human",
-		Topic:     "cold-start",
-	})
-
 	// Build startup command WITH the beacon prompt - the startup hook handles 'gt prime' automatically
 	// Export GT_ROLE and BD_ACTOR in the command since tmux SetEnvironment only affects new panes
 	startupCmd, err := config.BuildAgentStartupCommandWithAgentOverride("mayor", "", m.townRoot, "", beacon, agentOverride)
@@ -113,7 +139,8 @@ func (m *Manager) Start(agentOverride string) error {
 
 	// Apply Mayor theming (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.MayorTheme()
-	_ = t.ConfigureGasTownSession(sessionID, theme, "", "Mayor", "coordinator")
+	_ = t.ConfigureGasTownSession(sessionID, theme, "", worker, "coordinator")
+	_ = transcript.Enable(t, sessionID) // Non-fatal, likewise
 
 	// Wait for Claude to start - fatal if Claude fails to launch
 	if err := t.WaitForCommand(sessionID, constants.SupportedShells, constants.ClaudeStartTimeout); err != nil {
@@ -133,10 +160,74 @@ func (m *Manager) Start(agentOverride string) error {
 	return nil
 }
 
+// Promote makes the standby mayor the mayor: its session is renamed to the
+// mayor's, takes over the mayor's entry in the session manifest, and is
+// told it has taken over, and which conversation the lost mayor was in.
+// Returns ErrNoStandby if no standby agent is running and ErrAlreadyRunning
+// if the mayor's session exists.
+func (m *Manager) Promote() error {
+	t := tmux.NewTmux()
+	standby, sessionID := m.StandbySessionName(), m.SessionName()
+
+	if running, _ := t.HasSession(standby); !running || !t.IsAgentAlive(standby) {
+		return ErrNoStandby
+	}
+	if running, _ := t.HasSession(sessionID); running {
+		return ErrAlreadyRunning
+	}
+
+	// Note the lost mayor's conversation before its entry is replaced
+	var previous string
+	manifest := session.ManifestPath()
+	if entries, err := session.LoadManifest(manifest); err == nil {
+		for _, e := range entries {
+			if e.Session == sessionID {
+				previous = e.AgentSessionID
+			}
+		}
+	}
+
+	if err := t.RenameSession(standby, sessionID); err != nil {
+		return fmt.Errorf("renaming standby session: %w", err)
+	}
+	_ = session.HandOverSession(manifest, standby, sessionID) // Non-fatal
+
+	// Re-theme: the status line still names the standby (non-fatal)
+	_ = t.ConfigureGasTownSession(sessionID, tmux.MayorTheme(), "", "Mayor", "coordinator")
+
+	context := "You have been promoted from standby: the mayor's session was lost and you are now the mayor."
+	if previous != "" {
+		context += fmt.Sprintf(" Ask the previous mayor what was in flight with `gt seance --talk %s`.", previous)
+	}
+	beacon := session.FormatStartupBeacon(session.BeaconConfig{
+		Recipient: "mayor",
+		Sender:    "watchdog",
+		Topic:     "failover",
+		Context: context + "\n\nCheck your hook and mail, then resume coordinating:\n" +
+			"1. `gt hook` - shows hooked work (if any)\n" +
+			"2. `gt mail inbox` - check for messages\n" +
+			"3. `gt convoy list` - see work in flight",
+	})
+	if err := t.NudgeSession(sessionID, beacon); err != nil {
+		return fmt.Errorf("nudging promoted mayor: %w", err)
+	}
+	return nil
+}
+
 // Stop stops the mayor session.
 func (m *Manager) Stop() error {
+	return m.stop(m.SessionName())
+}
+
+// StopStandby stops the standby mayor session.
+func (m *Manager) StopStandby() error {
+	return m.stop(m.StandbySessionName())
+}
+
+// stop stops the mayor session sessionID and forgets it, so the watchdog
+// doesn't take it for lost.
+func (m *Manager) stop(sessionID string) error {
 	t := tmux.NewTmux()
-	sessionID := m.SessionName()
 
 	// Check if session exists
 	running, err := t.HasSession(sessionID)
@@ -155,6 +246,7 @@ func (m *Manager) Stop() error {
 	if err := t.KillSession(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	session.Forget(sessionID)
 
 	return nil
 }
@@ -165,6 +257,12 @@ func (m *Manager) IsRunning() (bool, error) {
 	return t.HasSession(m.SessionName())
 }
 
+// StandbyRunning checks if the standby mayor session is active.
+func (m *Manager) StandbyRunning() (bool, error) {
+	t := tmux.NewTmux()
+	return t.HasSession(m.StandbySessionName())
+}
+
 // Status returns information about the mayor session.
 func (m *Manager) Status() (*tmux.SessionInfo, error) {
 	t := tmux.NewTmux()
@@ -0,0 +1,256 @@
+// Package notify tells people about Gas Town events: a bead finished, the
+// refinery merged one, an agent is stuck or crashed, a session handed off.
+//
+// Sinks are configured under "notify" in the town settings, and a rig's
+// settings can replace them for that rig's events:
+//
+//	"notify": {
+//	  "sinks": [
+//	    {"type": "slack", "url": "https://hooks.slack.com/services/...", "events": ["merged"]},
+//	    {"type": "desktop"}
+//	  ],
+//	  "stuck_after": "15m"
+//	}
+//
+// Notifications are best-effort, like hook scripts: a failing or slow sink
+// is reported on stderr and in the gt log, and never fails the gt command
+// that sent it.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	gtlog "github.com/steveyegge/gastown/internal/log"
+	"github.com/steveyegge/gastown/internal/workspace"
+)
+
+// Event is something notifications can be sent for.
+type Event string
+
+// Notification events. Their names are config.NotifyEvents.
+const (
+	BeadDone   Event = "bead-done"   // An agent finished its bead (gt done)
+	Merged     Event = "merged"      // The refinery merged a merge request
+	AgentStuck Event = "agent-stuck" // An agent has been waiting or erroring for stuck_after
+	Handoff    Event = "handoff"     // A session handed off to a fresh one
+	AgentCrash Event = "agent-crash" // An agent exited unexpectedly
+)
+
+// Timeout bounds each sink's delivery.
+var Timeout = 10 * time.Second
+
+// ErrDesktopUnsupported is returned by the desktop sink on platforms it
+// can't notify on.
+var ErrDesktopUnsupported = errors.New("desktop notifications are not supported on this platform")
+
+// Notification is one event, as webhooks receive it.
+type Notification struct {
+	Event  Event             `json:"event"`
+	Time   time.Time         `json:"time"`
+	Rig    string            `json:"rig,omitempty"` // Empty for town-level events
+	Title  string            `json:"title"`
+	Body   string            `json:"body,omitempty"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// Sink delivers notifications somewhere.
+type Sink interface {
+	Send(ctx context.Context, n Notification) error
+}
+
+// NewSink returns the sink cfg describes.
+func NewSink(cfg config.NotifySink) (Sink, error) {
+	switch cfg.Type {
+	case config.NotifySinkDesktop:
+		return Desktop{}, nil
+	case config.NotifySinkSlack:
+		return &Slack{WebhookURL: cfg.URL}, nil
+	case config.NotifySinkWebhook:
+		return &Webhook{URL: cfg.URL}, nil
+	}
+	return nil, fmt.Errorf("unknown notify sink type %q", cfg.Type)
+}
+
+// Config returns the notification settings for rig's events (the town's
+// for an empty rig): the rig's own if it has any, else the town's. nil
+// means none are configured.
+func Config(townRoot, rig string) (*config.NotifyConfig, error) {
+	if rig != "" {
+		settings, err := config.LoadRigSettings(config.RigSettingsPath(filepath.Join(townRoot, rig)))
+		if err != nil && !errors.Is(err, config.ErrNotFound) {
+			return nil, fmt.Errorf("loading %s settings: %w", rig, err)
+		}
+		if settings != nil && settings.Notify != nil {
+			return settings.Notify, settings.Notify.Validate()
+		}
+	}
+	settings, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot))
+	if err != nil {
+		return nil, fmt.Errorf("loading town settings: %w", err)
+	}
+	return settings.Notify, settings.Notify.Validate()
+}
+
+// Send delivers n to every sink configured for its rig that takes its
+// event. An empty townRoot is found from the working directory. Failures
+// go to stderr and the gt log; Send never fails.
+func Send(townRoot string, n Notification) {
+	if townRoot == "" {
+		townRoot, _ = workspace.FindFromCwd()
+		if townRoot == "" {
+			return
+		}
+	}
+	if n.Time.IsZero() {
+		n.Time = time.Now().UTC()
+	}
+	cfg, err := Config(townRoot, n.Rig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s notification not sent: %v\n", n.Event, err)
+		return
+	}
+	if cfg == nil {
+		return
+	}
+	for _, sinkCfg := range cfg.Sinks {
+		if !sinkCfg.Wants(string(n.Event)) {
+			continue
+		}
+		err := deliver(sinkCfg, n)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s notification to %s failed: %v\n", n.Event, sinkCfg.Type, err)
+		}
+		logSent(n, sinkCfg.Type, err)
+	}
+}
+
+// deliver sends n to the sink cfg describes, within Timeout.
+func deliver(cfg config.NotifySink, n Notification) error {
+	sink, err := NewSink(cfg)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	return sink.Send(ctx, n)
+}
+
+// SentMsg is the gt log message recorded for each notification delivery.
+const SentMsg = "notification sent"
+
+// logSent records a delivery in the gt log.
+func logSent(n Notification, sink string, err error) {
+	attrs := []any{"event", string(n.Event), "sink", sink, "rig", n.Rig, "title", n.Title}
+	if err != nil {
+		gtlog.L().Warn(SentMsg, append(attrs, "err", err.Error())...)
+		return
+	}
+	gtlog.L().Info(SentMsg, attrs...)
+}
+
+// Desktop shows notifications on the local desktop, with notify-send on
+// Linux and osascript on macOS.
+type Desktop struct{}
+
+// Send shows n as a desktop notification.
+func (Desktop) Send(ctx context.Context, n Notification) error {
+	args, err := desktopCommand(runtime.GOOS, n)
+	if err != nil {
+		return err
+	}
+	out, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput() //nolint:gosec // G204: fixed commands; n is passed as arguments
+	if err != nil {
+		return fmt.Errorf("%s: %v: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// desktopCommand returns the command that shows n on goos.
+func desktopCommand(goos string, n Notification) ([]string, error) {
+	title := "Gas Town: " + n.Title
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"notify-send", "--app-name=gt", title, n.Body}, nil
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(n.Body), appleScriptString(title))
+		return []string{"osascript", "-e", script}, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrDesktopUnsupported, goos)
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Slack posts notifications to a Slack incoming webhook.
+type Slack struct {
+	WebhookURL string
+	HTTP       *http.Client // nil for http.DefaultClient
+}
+
+// Send posts n to the webhook as a message.
+func (s *Slack) Send(ctx context.Context, n Notification) error {
+	text := "*" + n.Title + "*"
+	if n.Body != "" {
+		text += "\n" + n.Body
+	}
+	return post(ctx, s.HTTP, s.WebhookURL, map[string]string{"text": text})
+}
+
+// Webhook posts notifications as JSON to a URL.
+type Webhook struct {
+	URL  string
+	HTTP *http.Client // nil for http.DefaultClient
+}
+
+// Send posts n to the URL.
+func (w *Webhook) Send(ctx context.Context, n Notification) error {
+	return post(ctx, w.HTTP, w.URL, n)
+}
+
+// post sends v as JSON to url, failing on a non-2xx response.
+func post(ctx context.Context, client *http.Client, url string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gt-notify")
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		// Not the url.Error: webhook URLs carry their credentials
+		var urlErr *neturl.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("POST: %w", urlErr.Err)
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("POST: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// recorder is a webhook endpoint that records what it is posted.
+type recorder struct {
+	mu     sync.Mutex
+	bodies []string
+	status int
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	r.bodies = append(r.bodies, string(body))
+	r.mu.Unlock()
+	if r.status != 0 {
+		w.WriteHeader(r.status)
+	}
+}
+
+func (r *recorder) got() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.bodies...)
+}
+
+func TestEventsMatchConfig(t *testing.T) {
+	got := []string{string(BeadDone), string(Merged), string(AgentStuck), string(Handoff), string(AgentCrash)}
+	if !reflect.DeepEqual(got, config.NotifyEvents) {
+		t.Errorf("events = %q, config.NotifyEvents = %q", got, config.NotifyEvents)
+	}
+}
+
+func TestSlackAndWebhook(t *testing.T) {
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+	n := Notification{Event: Merged, Rig: "gastown", Title: "Merged gt-abc", Body: "commit 1234567", Fields: map[string]string{"bead": "gt-abc"}}
+
+	ctx := context.Background()
+	if err := (&Slack{WebhookURL: srv.URL}).Send(ctx, n); err != nil {
+		t.Fatalf("Slack.Send: %v", err)
+	}
+	if err := (&Webhook{URL: srv.URL}).Send(ctx, n); err != nil {
+		t.Fatalf("Webhook.Send: %v", err)
+	}
+
+	bodies := rec.got()
+	if len(bodies) != 2 {
+		t.Fatalf("got %d posts, want 2", len(bodies))
+	}
+	var msg map[string]string
+	if err := json.Unmarshal([]byte(bodies[0]), &msg); err != nil || msg["text"] != "*Merged gt-abc*\ncommit 1234567" {
+		t.Errorf("slack post = %s (%v)", bodies[0], err)
+	}
+	var posted Notification
+	if err := json.Unmarshal([]byte(bodies[1]), &posted); err != nil || !reflect.DeepEqual(posted, n) {
+		t.Errorf("webhook post = %s (%v), want %+v", bodies[1], err, n)
+	}
+
+	rec.status = http.StatusForbidden
+	if err := (&Webhook{URL: srv.URL}).Send(ctx, n); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Send to a failing endpoint = %v, want the 403", err)
+	}
+}
+
+func TestPostErrorHidesURL(t *testing.T) {
+	secret := "http://127.0.0.1:1/services/T000/B000/secret-token"
+	err := (&Slack{WebhookURL: secret}).Send(context.Background(), Notification{Title: "x"})
+	if err == nil {
+		t.Fatal("Send to a closed port = nil error")
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("error %q leaks the webhook URL", err)
+	}
+}
+
+func TestDesktopCommand(t *testing.T) {
+	n := Notification{Title: `Agent "max" stuck`, Body: "waiting for input"}
+	got, err := desktopCommand("linux", n)
+	if err != nil || !reflect.DeepEqual(got, []string{"notify-send", "--app-name=gt", `Gas Town: Agent "max" stuck`, "waiting for input"}) {
+		t.Errorf("linux = %q, %v", got, err)
+	}
+	got, err = desktopCommand("darwin", n)
+	if err != nil || got[0] != "osascript" || got[2] != `display notification "waiting for input" with title "Gas Town: Agent \"max\" stuck"` {
+		t.Errorf("darwin = %q, %v", got, err)
+	}
+	if _, err := desktopCommand("plan9", n); !errors.Is(err, ErrDesktopUnsupported) {
+		t.Errorf("plan9 error = %v, want ErrDesktopUnsupported", err)
+	}
+}
+
+func TestSendRoutesByRigAndEvent(t *testing.T) {
+	townRec, rigRec := &recorder{}, &recorder{}
+	townSrv, rigSrv := httptest.NewServer(townRec), httptest.NewServer(rigRec)
+	defer townSrv.Close()
+	defer rigSrv.Close()
+
+	town := t.TempDir()
+	settings := config.NewTownSettings()
+	settings.Notify = &config.NotifyConfig{Sinks: []config.NotifySink{
+		{Type: config.NotifySinkWebhook, URL: townSrv.URL, Events: []string{"agent-crash"}},
+	}}
+	if err := config.SaveTownSettings(config.TownSettingsPath(town), settings); err != nil {
+		t.Fatal(err)
+	}
+	rigSettings := config.NewRigSettings()
+	rigSettings.Notify = &config.NotifyConfig{Sinks: []config.NotifySink{
+		{Type: config.NotifySinkSlack, URL: rigSrv.URL},
+	}}
+	if err := config.SaveRigSettings(config.RigSettingsPath(filepath.Join(town, "gastown")), rigSettings); err != nil {
+		t.Fatal(err)
+	}
+
+	Send(town, Notification{Event: AgentCrash, Title: "mayor crashed"})                // Town sink
+	Send(town, Notification{Event: Handoff, Title: "mayor handed off"})                // Filtered out
+	Send(town, Notification{Event: Merged, Rig: "gastown", Title: "merged gt-abc"})    // The rig's sinks replace the town's
+	Send(town, Notification{Event: AgentCrash, Rig: "beads", Title: "beads/crew/joe"}) // A rig without its own
+
+	if got := townRec.got(); len(got) != 2 || !strings.Contains(got[0], "mayor crashed") || !strings.Contains(got[1], "beads/crew/joe") {
+		t.Errorf("town sink got %q", got)
+	}
+	if got := rigRec.got(); len(got) != 1 || !strings.Contains(got[0], "merged gt-abc") {
+		t.Errorf("rig sink got %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -15,8 +16,10 @@ import (
 	"github.com/steveyegge/gastown/internal/constants"
 	"github.com/steveyegge/gastown/internal/rig"
 	"github.com/steveyegge/gastown/internal/runtime"
+	"github.com/steveyegge/gastown/internal/scheduler"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 )
 
 // debugSession logs non-fatal errors during session startup when GT_DEBUG_SESSION=1.
@@ -64,6 +67,11 @@ type SessionStartOptions struct {
 	// RuntimeConfigDir is resolved config directory for the runtime account.
 	// If set, this is injected as an environment variable.
 	RuntimeConfigDir string
+
+	// Model overrides the agent's model (gt sling --model). It is set as
+	// GT_MODEL so handoffs and resumes keep it. A custom Command must
+	// apply it itself.
+	Model string
 }
 
 // SessionInfo contains information about a running polecat session.
@@ -171,7 +179,18 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		}
 	}
 
+	// Queue the start if the town or rig is at its agent cap
+	townRoot := filepath.Dir(m.rig.Path)
+	limits := scheduler.LoadLimits(townRoot, m.rig.Path)
+	req := scheduler.Request{Rig: m.rig.Name, Role: "polecat", Name: polecat, Issue: opts.Issue}
+	release, err := limits.Admit(townRoot, req, m.tmux.ListSessions)
+	defer release()
+	if err != nil {
+		return err
+	}
+
 	runtimeConfig := config.LoadRuntimeConfig(m.rig.Path)
+	runtimeConfig.WorkingDir = workDir
 
 	// Ensure runtime settings exist in polecat's home directory (polecats/<name>/).
 	// This keeps settings out of the git worktree while allowing runtime to find them
@@ -200,7 +219,18 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	beacon := session.FormatStartupBeacon(beaconConfig)
 
 	command := opts.Command
-	if command == "" {
+	if command == "" && opts.Model != "" {
+		env := config.AgentEnv(config.AgentEnvConfig{
+			Role:      "polecat",
+			Rig:       m.rig.Name,
+			AgentName: polecat,
+			TownRoot:  townRoot,
+			Model:     opts.Model,
+		})
+		if command, err = config.BuildStartupCommandWithAgentOverride(env, m.rig.Path, beacon, ""); err != nil {
+			return err
+		}
+	} else if command == "" {
 		command = config.BuildPolecatStartupCommand(m.rig.Name, polecat, m.rig.Path, beacon)
 	}
 	// Prepend runtime config dir env if needed
@@ -213,10 +243,11 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	if err := m.tmux.NewSessionWithCommand(sessionID, workDir, command); err != nil {
 		return fmt.Errorf("creating session: %w", err)
 	}
+	release()
+	debugSession("LowerPane", scheduler.LowerPane(limits, sessionID, m.tmux.GetPanePID))
 
 	// Set environment (non-fatal: session works without these)
 	// Use centralized AgentEnv for consistency across all role startup paths
-	townRoot := filepath.Dir(m.rig.Path)
 	envVars := config.AgentEnv(config.AgentEnvConfig{
 		Role:             "polecat",
 		Rig:              m.rig.Name,
@@ -224,6 +255,7 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 		TownRoot:         townRoot,
 		RuntimeConfigDir: opts.RuntimeConfigDir,
 		BeadsNoDaemon:    true,
+		Model:            opts.Model,
 	})
 	for k, v := range envVars {
 		debugSession("SetEnvironment "+k, m.tmux.SetEnvironment(sessionID, k, v))
@@ -241,6 +273,9 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	theme := tmux.AssignTheme(m.rig.Name)
 	debugSession("ConfigureGasTownSession", m.tmux.ConfigureGasTownSession(sessionID, theme, m.rig.Name, polecat, "polecat"))
 
+	// Record the pane to the session's transcript (non-fatal)
+	debugSession("EnableTranscript", transcript.Enable(m.tmux, sessionID))
+
 	// Set pane-died hook for crash detection (non-fatal)
 	agentID := fmt.Sprintf("%s/%s", m.rig.Name, polecat)
 	debugSession("SetPaneDiedHook", m.tmux.SetPaneDiedHook(sessionID, agentID))
@@ -293,6 +328,93 @@ func (m *SessionManager) Start(polecat string, opts SessionStartOptions) error {
 	return nil
 }
 
+// RunHeadless runs a polecat's agent in the foreground instead of in a tmux
+// session (gt sling --headless): a single non-interactive invocation in the
+// polecat's worktree, prompted with the startup beacon and work
+// instructions, writing to stdout and stderr. agent overrides the rig's
+// default agent. It returns the agent's *exec.ExitError if it exits
+// non-zero. Headless runs are not admitted against agent caps, which count
+// tmux sessions.
+func (m *SessionManager) RunHeadless(polecat, agent string, opts SessionStartOptions, stdout, stderr io.Writer) error {
+	if !m.hasPolecat(polecat) {
+		return fmt.Errorf("%w: %s", ErrPolecatNotFound, polecat)
+	}
+	workDir := opts.WorkDir
+	if workDir == "" {
+		workDir = m.clonePath(polecat)
+	}
+	if opts.Issue != "" {
+		if err := m.validateIssue(opts.Issue, workDir); err != nil {
+			return err
+		}
+	}
+
+	townRoot := filepath.Dir(m.rig.Path)
+	runtimeConfig := config.LoadRuntimeConfig(m.rig.Path)
+	if agent != "" {
+		rc, _, err := config.ResolveAgentConfigWithOverride(townRoot, m.rig.Path, agent)
+		if err != nil {
+			return err
+		}
+		runtimeConfig = rc
+	}
+	if opts.Model != "" {
+		runtimeConfig = runtimeConfig.Clone()
+		runtimeConfig.Model = opts.Model
+		if err := runtimeConfig.Validate(); err != nil {
+			return err
+		}
+	}
+	runtimeConfig.WorkingDir = workDir
+	if err := runtime.EnsureSettingsForRole(m.polecatDir(polecat), "polecat", runtimeConfig); err != nil {
+		return fmt.Errorf("ensuring runtime settings: %w", err)
+	}
+
+	// Everything a tmux start nudges in later goes into the one prompt
+	fallbackInfo := runtime.GetStartupFallbackInfo(runtimeConfig)
+	prompt := session.FormatStartupBeacon(session.BeaconConfig{
+		Recipient:               fmt.Sprintf("%s/polecats/%s", m.rig.Name, polecat),
+		Sender:                  "witness",
+		Topic:                   "assigned",
+		MolID:                   opts.Issue,
+		IncludePrimeInstruction: fallbackInfo.IncludePrimeInBeacon,
+		ExcludeWorkInstructions: fallbackInfo.SendStartupNudge,
+	})
+	if fallbackInfo.SendStartupNudge {
+		prompt += "\n\n" + runtime.StartupNudgeContent()
+	}
+	args, err := runtimeConfig.BuildHeadlessArgs(prompt)
+	if err != nil {
+		return err
+	}
+
+	env := config.AgentEnv(config.AgentEnvConfig{
+		Role:             "polecat",
+		Rig:              m.rig.Name,
+		AgentName:        polecat,
+		TownRoot:         townRoot,
+		RuntimeConfigDir: opts.RuntimeConfigDir,
+		BeadsNoDaemon:    true,
+		Model:            opts.Model,
+	})
+	if runtimeConfig.Session != nil && runtimeConfig.Session.ConfigDirEnv != "" && opts.RuntimeConfigDir != "" {
+		env[runtimeConfig.Session.ConfigDirEnv] = opts.RuntimeConfigDir
+	}
+	secretEnv, err := runtimeConfig.SecretEnv()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(args[0], args[1:]...) //nolint:gosec // G204: the agent command comes from town config
+	cmd.Dir = workDir
+	cmd.Env = os.Environ()
+	for k, v := range config.MergeEnv(runtimeConfig.Env, secretEnv, env) {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
 // Stop terminates a polecat session.
 func (m *SessionManager) Stop(polecat string, force bool) error {
 	sessionID := m.SessionName(polecat)
@@ -316,6 +438,7 @@ func (m *SessionManager) Stop(polecat string, force bool) error {
 	if err := m.tmux.KillSessionWithProcesses(sessionID); err != nil {
 		return fmt.Errorf("killing session: %w", err)
 	}
+	session.Forget(sessionID)
 
 	return nil
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/steveyegge/gastown/internal/convoy"
 	"github.com/steveyegge/gastown/internal/git"
 	"github.com/steveyegge/gastown/internal/mail"
+	"github.com/steveyegge/gastown/internal/notify"
 	"github.com/steveyegge/gastown/internal/protocol"
 	"github.com/steveyegge/gastown/internal/rig"
 )
@@ -457,6 +458,7 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close source issue %s: %v\n", mrFields.SourceIssue, err)
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Closed source issue: %s\n", mrFields.SourceIssue)
+			e.advanceSourceIssue(mrFields.SourceIssue, beads.StageDone, "merged in "+mr.ID)
 
 			// Redundant convoy observer: check if merged issue is tracked by a convoy
 			logger := func(format string, args ...interface{}) {
@@ -492,6 +494,7 @@ func (e *Engineer) handleSuccess(mr *beads.Issue, result ProcessResult) {
 
 	// 5. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
+	e.notifyMerged(mr.ID, mrFields.SourceIssue, mrFields.Branch, result.MergeCommit)
 }
 
 // handleFailure handles a failed merge request.
@@ -571,6 +574,7 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: failed to close source issue %s: %v\n", mr.SourceIssue, err)
 		} else {
 			_, _ = fmt.Fprintf(e.output, "[Engineer] Closed source issue: %s\n", mr.SourceIssue)
+			e.advanceSourceIssue(mr.SourceIssue, beads.StageDone, "merged in "+mr.ID)
 
 			// Redundant convoy observer: check if merged issue is tracked by a convoy
 			logger := func(format string, args ...interface{}) {
@@ -598,6 +602,28 @@ func (e *Engineer) HandleMRInfoSuccess(mr *MRInfo, result ProcessResult) {
 
 	// 3. Log success
 	_, _ = fmt.Fprintf(e.output, "[Engineer] ✓ Merged: %s (commit: %s)\n", mr.ID, result.MergeCommit)
+	e.notifyMerged(mr.ID, mr.SourceIssue, mr.Branch, result.MergeCommit)
+}
+
+// notifyMerged sends the merged notification for a merged MR.
+func (e *Engineer) notifyMerged(mrID, sourceIssue, branch, commit string) {
+	title := fmt.Sprintf("%s/refinery merged %s", e.rig.Name, mrID)
+	if sourceIssue != "" {
+		title = fmt.Sprintf("%s/refinery merged %s", e.rig.Name, sourceIssue)
+	}
+	notify.Send(filepath.Dir(e.rig.Path), notify.Notification{
+		Event: notify.Merged,
+		Rig:   e.rig.Name,
+		Title: title,
+		Body:  fmt.Sprintf("%s into %s (commit %s)", branch, e.config.TargetBranch, commit),
+		Fields: map[string]string{
+			"mr":     mrID,
+			"bead":   sourceIssue,
+			"branch": branch,
+			"target": e.config.TargetBranch,
+			"commit": commit,
+		},
+	})
 }
 
 // HandleMRInfoFailure handles a failed merge from MRInfo.
@@ -948,3 +974,13 @@ func (e *Engineer) ReleaseMR(mrID string) error {
 		Assignee: &empty,
 	})
 }
+
+// advanceSourceIssue records a merge queue outcome in the source issue's
+// lifecycle. It never fails the merge; errors are only reported.
+func (e *Engineer) advanceSourceIssue(issue string, to beads.Stage, note string) {
+	townRoot := filepath.Dir(e.rig.Path)
+	agent := e.rig.Name + "/refinery"
+	if _, err := beads.NewLifecycle(townRoot).Advance(issue, to, agent, "refinery", note); err != nil {
+		_, _ = fmt.Fprintf(e.output, "[Engineer] Warning: lifecycle of %s: %v\n", issue, err)
+	}
+}
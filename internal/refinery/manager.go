@@ -19,6 +19,7 @@ import (
 	"github.com/steveyegge/gastown/internal/runtime"
 	"github.com/steveyegge/gastown/internal/session"
 	"github.com/steveyegge/gastown/internal/tmux"
+	"github.com/steveyegge/gastown/internal/transcript"
 	"github.com/steveyegge/gastown/internal/util"
 )
 
@@ -173,6 +174,7 @@ func (m *Manager) Start(foreground bool, agentOverride string) error {
 	// Apply theme (non-fatal: theming failure doesn't affect operation)
 	theme := tmux.AssignTheme(m.rig.Name)
 	_ = t.ConfigureGasTownSession(sessionID, theme, m.rig.Name, "refinery", "refinery")
+	_ = transcript.Enable(t, sessionID) // Non-fatal, likewise
 
 	// Accept bypass permissions warning dialog if it appears.
 	// Must be before WaitForRuntimeReady to avoid race where dialog blocks prompt detection.
@@ -206,7 +208,11 @@ func (m *Manager) Stop() error {
 	}
 
 	// Kill the tmux session
-	return t.KillSession(sessionID)
+	if err := t.KillSession(sessionID); err != nil {
+		return err
+	}
+	session.Forget(sessionID)
+	return nil
 }
 
 // Queue returns the current merge queue.
@@ -593,6 +599,13 @@ func (m *Manager) RejectMR(idOrBranch string, reason string, notify bool) (*Merg
 	}
 	mr.Error = reason
 
+	if mr.IssueID != "" {
+		lc := beads.NewLifecycle(filepath.Dir(m.rig.Path))
+		if _, err := lc.Advance(mr.IssueID, beads.StageFailed, m.rig.Name+"/refinery", "gt refinery reject", reason); err != nil {
+			_, _ = fmt.Fprintf(m.output, "Warning: lifecycle of %s: %v\n", mr.IssueID, err)
+		}
+	}
+
 	// Optionally notify worker
 	if notify {
 		m.notifyWorkerRejected(mr, reason)
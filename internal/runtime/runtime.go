@@ -3,11 +3,14 @@ package runtime
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/steveyegge/gastown/internal/aider"
 	"github.com/steveyegge/gastown/internal/claude"
 	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/kimi"
 	"github.com/steveyegge/gastown/internal/opencode"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
@@ -18,6 +21,13 @@ func EnsureSettingsForRole(workDir, role string, rc *config.RuntimeConfig) error
 		rc = config.DefaultRuntimeConfig()
 	}
 
+	// Aider has no hooks; keep its history and cache files out of git
+	if isAider(rc) {
+		if err := aider.EnsureGitExcludeAt(rc.WorkingDir); err != nil {
+			return err
+		}
+	}
+
 	if rc.Hooks == nil {
 		return nil
 	}
@@ -27,6 +37,8 @@ func EnsureSettingsForRole(workDir, role string, rc *config.RuntimeConfig) error
 		return claude.EnsureSettingsForRoleAt(workDir, role, rc.Hooks.Dir, rc.Hooks.SettingsFile)
 	case "opencode":
 		return opencode.EnsurePluginAt(workDir, rc.Hooks.Dir, rc.Hooks.SettingsFile)
+	case "kimi":
+		return kimi.EnsureSettingsForRoleAt(workDir, role, rc.Hooks.Dir, rc.Hooks.SettingsFile)
 	default:
 		return nil
 	}
@@ -69,10 +81,20 @@ func StartupFallbackCommands(role string, rc *config.RuntimeConfig) []string {
 		command += " && gt mail check --inject"
 	}
 	command += " && gt nudge deacon session-started"
+	if isAider(rc) {
+		// Aider sends plain input to the model; /run executes it instead
+		command = aider.RunPrefix + command
+	}
 
 	return []string{command}
 }
 
+// isAider reports whether rc runs Aider, by provider or by command for
+// configs resolved from the aider preset.
+func isAider(rc *config.RuntimeConfig) bool {
+	return rc.Provider == string(config.AgentAider) || filepath.Base(rc.Command) == "aider"
+}
+
 // RunStartupFallback sends the startup fallback commands via tmux.
 func RunStartupFallback(t *tmux.Tmux, sessionID, role string, rc *config.RuntimeConfig) error {
 	commands := StartupFallbackCommands(role, rc)
@@ -2,6 +2,8 @@ package runtime
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -186,6 +188,18 @@ func TestStartupFallbackCommands_NilConfig(t *testing.T) {
 	}
 }
 
+func TestStartupFallbackCommands_Aider(t *testing.T) {
+	for _, rc := range []*config.RuntimeConfig{
+		(&config.RuntimeConfig{Provider: "aider"}).Resolved(),
+		config.RuntimeConfigFromPreset(config.AgentAider),
+	} {
+		commands := StartupFallbackCommands("polecat", rc)
+		if len(commands) != 1 || !strings.HasPrefix(commands[0], "/run gt prime") {
+			t.Errorf("StartupFallbackCommands() for aider = %v, want gt prime behind /run", commands)
+		}
+	}
+}
+
 func TestStartupFallbackCommands_AutonomousRole(t *testing.T) {
 	rc := &config.RuntimeConfig{
 		Hooks: &config.RuntimeHooksConfig{
@@ -285,6 +299,24 @@ func TestEnsureSettingsForRole_UnknownProvider(t *testing.T) {
 	}
 }
 
+func TestEnsureSettingsForRole_Kimi(t *testing.T) {
+	dir := t.TempDir()
+	rc := &config.RuntimeConfig{
+		Hooks: &config.RuntimeHooksConfig{Provider: "kimi", Dir: ".kimi", SettingsFile: "settings.json"},
+	}
+
+	if err := EnsureSettingsForRole(dir, "polecat", rc); err != nil {
+		t.Fatalf("EnsureSettingsForRole() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".kimi", "settings.json"))
+	if err != nil {
+		t.Fatalf("kimi settings not written: %v", err)
+	}
+	if !strings.Contains(string(data), `"session_start"`) {
+		t.Errorf("kimi settings = %s, want translated session_start hook", data)
+	}
+}
+
 func TestGetStartupFallbackInfo_HooksWithPrompt(t *testing.T) {
 	// Claude: hooks enabled, prompt mode "arg"
 	rc := &config.RuntimeConfig{
@@ -0,0 +1,123 @@
+package sandbox
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// dialTimeout bounds connecting to an allowed host.
+const dialTimeout = 30 * time.Second
+
+// Proxy is an HTTP proxy on loopback that only reaches allow-listed hosts:
+// CONNECT tunnels (HTTPS) and plain HTTP requests to any other host are
+// refused with 403. Sandboxed agents are pointed at it with ProxyEnv.
+type Proxy struct {
+	hosts    []string
+	listener net.Listener
+	server   *http.Server
+}
+
+// StartProxy starts a proxy allowing hosts on a free loopback port.
+func StartProxy(hosts []string) (*Proxy, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	p := &Proxy{hosts: hosts, listener: listener}
+	p.server = &http.Server{Handler: p, ReadHeaderTimeout: dialTimeout}
+	go func() { _ = p.server.Serve(listener) }()
+	return p, nil
+}
+
+// Addr returns the proxy's host:port.
+func (p *Proxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+// Close stops the proxy. Open tunnels are cut when the process exits.
+func (p *Proxy) Close() error {
+	return p.server.Close()
+}
+
+// ProxyEnv returns the environment variables that send HTTP clients
+// through the proxy at addr.
+func ProxyEnv(addr string) []string {
+	url := "http://" + addr
+	return []string{
+		"HTTPS_PROXY=" + url, "https_proxy=" + url,
+		"HTTP_PROXY=" + url, "http_proxy=" + url,
+		"ALL_PROXY=" + url, "all_proxy=" + url,
+		"NO_PROXY=", "no_proxy=",
+	}
+}
+
+// ServeHTTP tunnels CONNECT requests and forwards plain HTTP requests to
+// allowed hosts.
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.URL.Hostname()
+	if r.Method == http.MethodConnect {
+		host, _, _ = net.SplitHostPort(r.Host)
+	}
+	if !HostAllowed(host, p.hosts) {
+		http.Error(w, "gt sandbox: host not allowed: "+host, http.StatusForbidden)
+		return
+	}
+	if r.Method == http.MethodConnect {
+		p.tunnel(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "gt sandbox: not a proxy request", http.StatusBadRequest)
+		return
+	}
+
+	out := r.Clone(r.Context())
+	out.RequestURI = ""
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+	for k, values := range resp.Header {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = io.Copy(w, resp.Body)
+}
+
+// tunnel connects the client to r.Host and copies bytes both ways.
+func (p *Proxy) tunnel(w http.ResponseWriter, r *http.Request) {
+	upstream, err := net.DialTimeout("tcp", r.Host, dialTimeout)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "gt sandbox: tunneling not supported", http.StatusInternalServerError)
+		return
+	}
+	client, buf, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		client.Close()
+		upstream.Close()
+		return
+	}
+	go func() {
+		// Bytes the client sent after the CONNECT header are buffered
+		_, _ = io.Copy(upstream, buf)
+		upstream.Close()
+	}()
+	_, _ = io.Copy(client, upstream)
+	client.Close()
+}
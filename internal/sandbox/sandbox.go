@@ -0,0 +1,170 @@
+// Package sandbox confines agent processes, so agents running with
+// permission prompts skipped can't write outside their worktree or reach
+// arbitrary hosts. Writes are limited to the worktree, the temp directory
+// and any state directories the agent needs; network access is denied, or
+// limited to allow-listed hosts through an HTTP proxy (see Proxy). The
+// process is wrapped in bubblewrap (bwrap) on Linux and sandbox-exec on
+// macOS.
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrUnsupported = errors.New("sandboxing not supported on this platform")
+	ErrInvalidHost = errors.New("invalid sandbox host")
+	ErrInvalidPath = errors.New("invalid sandbox path")
+)
+
+// Policy is what a sandboxed process may touch.
+type Policy struct {
+	// Writable are the directories and files the process may write, besides
+	// the temp directory. Everything else is read-only. Paths that don't
+	// exist are skipped.
+	Writable []string
+
+	// Proxy is the address (host:port) of the allow-list proxy the process
+	// reaches the network through. Empty denies network access.
+	Proxy string
+}
+
+// Command returns the argv that runs argv under p on goos, using bwrap on
+// Linux and sandbox-exec on macOS. Fails with ErrUnsupported elsewhere, and
+// if the sandbox tool isn't installed.
+func Command(goos string, p Policy, argv []string) ([]string, error) {
+	switch goos {
+	case "linux":
+		if _, err := exec.LookPath("bwrap"); err != nil {
+			return nil, fmt.Errorf("%w: bwrap not found (install bubblewrap)", ErrUnsupported)
+		}
+		return append(bwrapArgs(p), argv...), nil
+	case "darwin":
+		if _, err := exec.LookPath("sandbox-exec"); err != nil {
+			return nil, fmt.Errorf("%w: sandbox-exec not found", ErrUnsupported)
+		}
+		return append([]string{"sandbox-exec", "-p", seatbeltProfile(p)}, argv...), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, goos)
+	}
+}
+
+// bwrapArgs returns the bwrap invocation, up to the "--" ahead of the
+// sandboxed command. The root is mounted read-only with the writable paths
+// bound back over it. bwrap can't filter by host, so with a proxy the
+// network namespace is shared and the proxy is found through the proxy
+// environment variables; without one it is unshared, leaving only loopback.
+func bwrapArgs(p Policy) []string {
+	args := []string{
+		"bwrap",
+		"--die-with-parent",
+		"--ro-bind", "/", "/",
+		"--dev", "/dev",
+		"--proc", "/proc",
+	}
+	for _, path := range writablePaths(p) {
+		args = append(args, "--bind-try", path, path)
+	}
+	if p.Proxy == "" {
+		args = append(args, "--unshare-net")
+	}
+	return append(args, "--")
+}
+
+// seatbeltProfile returns the sandbox-exec profile: everything is allowed
+// except writes outside the writable paths and network access other than
+// local sockets and the proxy.
+func seatbeltProfile(p Policy) string {
+	var b strings.Builder
+	b.WriteString("(version 1)\n(allow default)\n")
+	b.WriteString("(deny file-write*)\n(allow file-write*\n  (subpath \"/dev\")")
+	for _, path := range writablePaths(p) {
+		fmt.Fprintf(&b, "\n  (subpath %s)", seatbeltQuote(path))
+	}
+	b.WriteString(")\n")
+	b.WriteString("(deny network*)\n(allow network* (local unix-socket) (remote unix-socket))\n")
+	if p.Proxy != "" {
+		if _, port, ok := strings.Cut(p.Proxy, ":"); ok {
+			fmt.Fprintf(&b, "(allow network-outbound (remote ip \"localhost:%s\"))\n", port)
+		}
+	}
+	return b.String()
+}
+
+// seatbeltQuote quotes s as a sandbox profile string.
+func seatbeltQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// writablePaths returns the temp directory and p.Writable, with symlinks
+// resolved (sandbox-exec matches real paths; macOS's temp directory is
+// under the /var symlink) and without duplicates.
+func writablePaths(p Policy) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range append([]string{os.TempDir()}, p.Writable...) {
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			path = real
+		}
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// ValidPath checks a writable entry: an absolute path, or one under ~/.
+func ValidPath(path string) error {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %q (want an absolute or ~/ path)", ErrInvalidPath, path)
+	}
+	return nil
+}
+
+// ExpandPath returns path with a leading ~ expanded to home. Fails with
+// ErrInvalidPath for relative paths.
+func ExpandPath(path, home string) (string, error) {
+	if err := ValidPath(path); err != nil {
+		return "", err
+	}
+	if path == "~" {
+		path = home
+	} else if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		path = filepath.Join(home, rest)
+	}
+	return filepath.Clean(path), nil
+}
+
+// ValidHost checks an allow-list entry: a host name, optionally with a
+// leading "*." matching its subdomains ("*.moonshot.ai").
+func ValidHost(host string) error {
+	name := strings.TrimPrefix(host, "*.")
+	if name == "" || strings.ContainsAny(name, "/:*@ ") || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+		return fmt.Errorf("%w: %q (want a host name like api.anthropic.com or *.moonshot.ai)", ErrInvalidHost, host)
+	}
+	return nil
+}
+
+// HostAllowed reports whether host (without port) matches an allow-list
+// entry. "*.example.com" matches subdomains of example.com, not
+// example.com itself. Matching ignores case.
+func HostAllowed(host string, allow []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, entry := range allow {
+		entry = strings.ToLower(entry)
+		if suffix, ok := strings.CutPrefix(entry, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if host == entry {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package sandbox
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHostAllowed(t *testing.T) {
+	t.Parallel()
+	allow := []string{"api.anthropic.com", "*.moonshot.ai"}
+	tests := []struct {
+		host string
+		want bool
+	}{
+		{"api.anthropic.com", true},
+		{"API.Anthropic.com.", true},
+		{"anthropic.com", false},
+		{"evil-api.anthropic.com", false},
+		{"api.moonshot.ai", true},
+		{"a.b.moonshot.ai", true},
+		{"moonshot.ai", false},
+		{"notmoonshot.ai", false},
+		{"example.com", false},
+	}
+	for _, tt := range tests {
+		if got := HostAllowed(tt.host, allow); got != tt.want {
+			t.Errorf("HostAllowed(%q) = %v, want %v", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestValidHost(t *testing.T) {
+	t.Parallel()
+	for _, host := range []string{"api.anthropic.com", "*.moonshot.ai", "localhost"} {
+		if err := ValidHost(host); err != nil {
+			t.Errorf("ValidHost(%q) = %v, want nil", host, err)
+		}
+	}
+	for _, host := range []string{"", "*", "https://api.anthropic.com", "api.anthropic.com:443", "a.*.com", ".com"} {
+		if err := ValidHost(host); !errors.Is(err, ErrInvalidHost) {
+			t.Errorf("ValidHost(%q) = %v, want ErrInvalidHost", host, err)
+		}
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	t.Parallel()
+	home := filepath.FromSlash("/home/me")
+	tests := map[string]string{
+		"~":          home,
+		"~/.kimi":    filepath.Join(home, ".kimi"),
+		"/var/cache": filepath.FromSlash("/var/cache"),
+	}
+	for in, want := range tests {
+		if got, err := ExpandPath(in, home); err != nil || got != want {
+			t.Errorf("ExpandPath(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := ExpandPath("relative/dir", home); !errors.Is(err, ErrInvalidPath) {
+		t.Errorf("ExpandPath(relative) = %v, want ErrInvalidPath", err)
+	}
+}
+
+func TestBwrapArgs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	args := bwrapArgs(Policy{Writable: []string{dir}})
+	if args[0] != "bwrap" || args[len(args)-1] != "--" {
+		t.Fatalf("bwrapArgs() = %v, want bwrap ... --", args)
+	}
+	joined := strings.Join(args, " ")
+	real, _ := filepath.EvalSymlinks(dir)
+	for _, want := range []string{"--ro-bind / /", "--bind-try " + real + " " + real, "--unshare-net"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("bwrapArgs() = %q, missing %q", joined, want)
+		}
+	}
+
+	// With a proxy the network is shared so the proxy can be reached
+	if args := bwrapArgs(Policy{Proxy: "127.0.0.1:4000"}); slices.Contains(args, "--unshare-net") {
+		t.Errorf("bwrapArgs() with proxy = %v, want shared network", args)
+	}
+}
+
+func TestSeatbeltProfile(t *testing.T) {
+	t.Parallel()
+	profile := seatbeltProfile(Policy{Writable: []string{`/work/a "b"`}, Proxy: "127.0.0.1:4000"})
+	for _, want := range []string{
+		"(deny file-write*)",
+		`(subpath "/work/a \"b\"")`,
+		"(deny network*)",
+		`(allow network-outbound (remote ip "localhost:4000"))`,
+	} {
+		if !strings.Contains(profile, want) {
+			t.Errorf("seatbeltProfile() = %q, missing %q", profile, want)
+		}
+	}
+	if profile := seatbeltProfile(Policy{}); strings.Contains(profile, "network-outbound") {
+		t.Errorf("seatbeltProfile() without proxy allows outbound network: %q", profile)
+	}
+}
+
+func TestCommandUnsupported(t *testing.T) {
+	t.Parallel()
+	if _, err := Command("windows", Policy{}, []string{"kimi"}); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Command(windows) = %v, want ErrUnsupported", err)
+	}
+}
+
+func TestProxyAllowList(t *testing.T) {
+	t.Parallel()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer upstream.Close()
+
+	proxy, err := StartProxy([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer proxy.Close()
+	proxyURL, _ := url.Parse("http://" + proxy.Addr())
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	resp, err := client.Get(upstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET allowed host: status %d, want 200", resp.StatusCode)
+	}
+
+	// localhost isn't on the list, though it is the same server
+	denied := strings.Replace(upstream.URL, "127.0.0.1", "localhost", 1)
+	resp, err = client.Get(denied)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("GET denied host: status %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestProxyEnv(t *testing.T) {
+	t.Parallel()
+	env := ProxyEnv("127.0.0.1:4000")
+	if !slices.Contains(env, "HTTPS_PROXY=http://127.0.0.1:4000") {
+		t.Errorf("ProxyEnv() = %v, missing HTTPS_PROXY", env)
+	}
+}
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Lower applies l's niceness and OOM score to the process pid (an agent
+// pane's process), which its children inherit. Both settings only lower a
+// process's standing, which needs no privileges. Unsupported settings on
+// the current platform are skipped.
+func Lower(pid int, l Limits) error {
+	var errs []error
+	if l.Nice != 0 {
+		if err := setNice(pid, l.Nice); err != nil {
+			errs = append(errs, fmt.Errorf("setting niceness %d: %w", l.Nice, err))
+		}
+	}
+	if l.OOMScoreAdj != 0 {
+		path := fmt.Sprintf("/proc/%d/oom_score_adj", pid)
+		err := os.WriteFile(path, []byte(strconv.Itoa(l.OOMScoreAdj)), 0644)
+		if err != nil && !errors.Is(err, os.ErrNotExist) { // No procfs: not Linux
+			errs = append(errs, fmt.Errorf("setting OOM score %d: %w", l.OOMScoreAdj, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// LowerPane applies l to the pane process of a tmux session, looked up with
+// panePID (e.g. Tmux.GetPanePID). It does nothing when l sets no priority.
+func LowerPane(l Limits, sessionName string, panePID func(string) (string, error)) error {
+	if l.Nice == 0 && l.OOMScoreAdj == 0 {
+		return nil
+	}
+	out, err := panePID(sessionName)
+	if err != nil {
+		return fmt.Errorf("getting pane PID: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(out))
+	if err != nil {
+		return fmt.Errorf("parsing pane PID %q: %w", out, err)
+	}
+	return Lower(pid, l)
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package scheduler
+
+import "syscall"
+
+func setNice(pid, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+}
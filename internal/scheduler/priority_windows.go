@@ -0,0 +1,8 @@
+//go:build windows
+
+package scheduler
+
+// setNice is a no-op on Windows, which has priority classes, not niceness.
+func setNice(pid, nice int) error {
+	return nil
+}
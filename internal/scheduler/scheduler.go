@@ -0,0 +1,264 @@
+// Package scheduler rate-limits agent session starts.
+//
+// The scheduler settings (town settings/config.json, or a rig's
+// settings/config.json) cap how many crew and polecat sessions run at once.
+// A start beyond the cap is recorded in the town's spawn queue instead, and
+// the daemon starts queued sessions, oldest first, as slots free up. The
+// settings also set the CPU niceness and OOM score of agent processes.
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/store"
+)
+
+// ErrQueued indicates a session start was queued because the town or rig
+// is at its concurrency cap.
+var ErrQueued = errors.New("queued until an agent slot frees up")
+
+// spawnLockTimeout is how long a start waits for other admitted starts to
+// create their sessions.
+const spawnLockTimeout = 30 * time.Second
+
+// Limits are the effective scheduler settings for one rig.
+type Limits struct {
+	MaxAgents    int // Town-wide cap on running workers; 0 means none
+	RigMaxAgents int // Cap on the rig's running workers; 0 means none
+	Nice         int
+	OOMScoreAdj  int
+}
+
+// Capped reports whether any concurrency cap is set.
+func (l Limits) Capped() bool {
+	return l.MaxAgents > 0 || l.RigMaxAgents > 0
+}
+
+// LoadLimits resolves the scheduler settings for the rig at rigPath in the
+// town at townRoot. Missing or unreadable settings mean no limits.
+func LoadLimits(townRoot, rigPath string) Limits {
+	var l Limits
+	if town, err := config.LoadOrCreateTownSettings(config.TownSettingsPath(townRoot)); err == nil && town.Scheduler != nil {
+		l.MaxAgents = town.Scheduler.MaxConcurrentAgents
+		l.Nice = town.Scheduler.Nice
+		l.OOMScoreAdj = town.Scheduler.OOMScoreAdj
+	}
+	if rigPath != "" {
+		if rig, err := config.LoadRigSettings(config.RigSettingsPath(rigPath)); err == nil && rig.Scheduler != nil {
+			l.RigMaxAgents = rig.Scheduler.MaxConcurrentAgents
+			if rig.Scheduler.Nice != 0 {
+				l.Nice = rig.Scheduler.Nice
+			}
+			if rig.Scheduler.OOMScoreAdj != 0 {
+				l.OOMScoreAdj = rig.Scheduler.OOMScoreAdj
+			}
+		}
+	}
+	return l
+}
+
+// Request is a crew or polecat session start.
+type Request struct {
+	Rig      string    `json:"rig"`
+	Role     string    `json:"role"` // crew or polecat
+	Name     string    `json:"name"`
+	Issue    string    `json:"issue,omitempty"` // Polecat's hooked issue
+	QueuedAt time.Time `json:"queued_at"`
+}
+
+// Session returns the tmux session the request starts.
+func (r Request) Session() string {
+	if r.Role == string(session.RoleCrew) {
+		return session.CrewSessionName(r.Rig, r.Name)
+	}
+	return session.PolecatSessionName(r.Rig, r.Name)
+}
+
+// Args returns the gt command line that starts the request.
+func (r Request) Args() []string {
+	if r.Role == string(session.RoleCrew) {
+		return []string{"crew", "start", r.Rig, r.Name}
+	}
+	args := []string{"session", "start", r.Rig + "/" + r.Name}
+	if r.Issue != "" {
+		args = append(args, "--issue", r.Issue)
+	}
+	return args
+}
+
+// Workers counts the running crew and polecat sessions among sessions, in
+// total and by rig.
+func Workers(sessions []string) (total int, byRig map[string]int) {
+	byRig = make(map[string]int)
+	for _, s := range sessions {
+		identity, err := session.ParseSessionName(s)
+		if err != nil || (identity.Role != session.RoleCrew && identity.Role != session.RolePolecat) {
+			continue
+		}
+		total++
+		byRig[identity.Rig]++
+	}
+	return total, byRig
+}
+
+// Check returns an error naming the cap a new worker on rig would exceed,
+// or nil if it may start now.
+func (l Limits) Check(rig string, sessions []string) error {
+	total, byRig := Workers(sessions)
+	if l.MaxAgents > 0 && total >= l.MaxAgents {
+		return fmt.Errorf("%d/%d agents running in town", total, l.MaxAgents)
+	}
+	if l.RigMaxAgents > 0 && byRig[rig] >= l.RigMaxAgents {
+		return fmt.Errorf("%d/%d agents running in rig %s", byRig[rig], l.RigMaxAgents, rig)
+	}
+	return nil
+}
+
+// Admit decides whether req may start now. At capacity, req is added to
+// the town's queue and the returned error wraps ErrQueued. sessions lists
+// the running tmux sessions.
+//
+// An admitted caller holds the town's spawn lock, so concurrent starts
+// can't all claim the same free slot; it must call release once its
+// session exists or its start fails. release may be called more than once.
+func (l Limits) Admit(townRoot string, req Request, sessions func() ([]string, error)) (release func(), err error) {
+	release = func() {}
+	if !l.Capped() {
+		return release, nil
+	}
+	unlock, err := store.Lock(filepath.Join(townRoot, ".runtime", "spawn"), spawnLockTimeout)
+	if err != nil {
+		return release, err
+	}
+	var once sync.Once
+	release = func() { once.Do(unlock) }
+
+	running, err := sessions()
+	if err != nil {
+		release()
+		return release, fmt.Errorf("listing sessions: %w", err)
+	}
+	capErr := l.Check(req.Rig, running)
+	if capErr == nil {
+		return release, nil
+	}
+	release()
+	if err := Enqueue(townRoot, req); err != nil {
+		return release, fmt.Errorf("queueing %s (%v): %w", req.Session(), capErr, err)
+	}
+	return release, fmt.Errorf("%s %w (%v)", req.Session(), ErrQueued, capErr)
+}
+
+// QueuePath returns the town's spawn queue.
+func QueuePath(townRoot string) string {
+	return filepath.Join(townRoot, ".runtime", "spawn-queue.json")
+}
+
+// Queue returns the queued requests, oldest first. A missing queue is empty.
+func Queue(townRoot string) ([]Request, error) {
+	return readQueue(QueuePath(townRoot))
+}
+
+// Enqueue adds req to the end of the town's queue, stamping its queue
+// time. A session that is already queued keeps its place, taking req's
+// issue if it has one.
+func Enqueue(townRoot string, req Request) error {
+	req.QueuedAt = time.Now().UTC()
+	return updateQueue(QueuePath(townRoot), func(q []Request) []Request {
+		for i := range q {
+			if q[i].Session() == req.Session() {
+				if req.Issue != "" {
+					q[i].Issue = req.Issue
+				}
+				return q
+			}
+		}
+		return append(q, req)
+	})
+}
+
+// Dequeue removes a session's request from the town's queue.
+func Dequeue(townRoot, sessionName string) error {
+	path := QueuePath(townRoot)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return updateQueue(path, func(q []Request) []Request {
+		kept := q[:0]
+		for _, r := range q {
+			if r.Session() != sessionName {
+				kept = append(kept, r)
+			}
+		}
+		return kept
+	})
+}
+
+// Drain starts queued requests, oldest first, while their caps allow.
+// sessions lists the running tmux sessions; start runs one request. Each
+// request is dequeued before it starts, so a failed start isn't retried
+// forever. Requests whose session is already running are dropped.
+func Drain(townRoot string, sessions func() ([]string, error), start func(Request) error) (started []Request, err error) {
+	queue, err := Queue(townRoot)
+	if err != nil || len(queue) == 0 {
+		return nil, err
+	}
+	running, err := sessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	isRunning := make(map[string]bool, len(running))
+	for _, s := range running {
+		isRunning[s] = true
+	}
+
+	var errs []error
+	for _, req := range queue {
+		name := req.Session()
+		if isRunning[name] {
+			_ = Dequeue(townRoot, name)
+			continue
+		}
+		if LoadLimits(townRoot, filepath.Join(townRoot, req.Rig)).Check(req.Rig, running) != nil {
+			continue // Still full; a later request for a quieter rig may fit
+		}
+		if err := Dequeue(townRoot, name); err != nil {
+			return started, err
+		}
+		if err := start(req); err != nil {
+			errs = append(errs, fmt.Errorf("starting %s: %w", name, err))
+			continue
+		}
+		started = append(started, req)
+		running = append(running, name)
+		isRunning[name] = true
+	}
+	return started, errors.Join(errs...)
+}
+
+// updateQueue applies fn to the queue at path under its lock.
+func updateQueue(path string, fn func([]Request) []Request) error {
+	err := store.Update(path, func(q *[]Request) (bool, error) {
+		*q = fn(*q)
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("updating spawn queue: %w", err)
+	}
+	return nil
+}
+
+func readQueue(path string) ([]Request, error) {
+	var q []Request
+	if _, err := store.Read(path, &q); err != nil {
+		return nil, fmt.Errorf("spawn queue: %w", err)
+	}
+	return q, nil
+}
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func writeTownScheduler(t *testing.T, townRoot string, sc *config.SchedulerConfig) {
+	t.Helper()
+	settings := config.NewTownSettings()
+	settings.Scheduler = sc
+	if err := config.SaveTownSettings(config.TownSettingsPath(townRoot), settings); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func listed(names ...string) func() ([]string, error) {
+	return func() ([]string, error) { return names, nil }
+}
+
+func TestWorkers(t *testing.T) {
+	total, byRig := Workers([]string{
+		"gt-gastown-crew-max",
+		"gt-gastown-Toast",
+		"gt-beads-Nux",
+		"gt-gastown-witness",
+		"gt-gastown-refinery",
+		"hq-mayor",
+		"scratch",
+	})
+	if total != 3 || byRig["gastown"] != 2 || byRig["beads"] != 1 {
+		t.Errorf("Workers = %d, %v; want 3, gastown:2 beads:1", total, byRig)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	running := []string{"gt-gastown-crew-max", "gt-gastown-Toast", "gt-beads-Nux"}
+	tests := []struct {
+		name    string
+		limits  Limits
+		rig     string
+		wantErr bool
+	}{
+		{"uncapped", Limits{}, "gastown", false},
+		{"under town cap", Limits{MaxAgents: 4}, "gastown", false},
+		{"at town cap", Limits{MaxAgents: 3}, "beads", true},
+		{"at rig cap", Limits{RigMaxAgents: 2}, "gastown", true},
+		{"other rig under rig cap", Limits{RigMaxAgents: 2}, "beads", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Check(tt.rig, running)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%s) = %v, wantErr %v", tt.rig, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadLimitsRigOverrides(t *testing.T) {
+	town := t.TempDir()
+	writeTownScheduler(t, town, &config.SchedulerConfig{MaxConcurrentAgents: 4, Nice: 5, OOMScoreAdj: 300})
+	rigPath := filepath.Join(town, "gastown")
+	rig := config.NewRigSettings()
+	rig.Scheduler = &config.SchedulerConfig{MaxConcurrentAgents: 2, Nice: 15}
+	if err := config.SaveRigSettings(config.RigSettingsPath(rigPath), rig); err != nil {
+		t.Fatal(err)
+	}
+
+	got := LoadLimits(town, rigPath)
+	want := Limits{MaxAgents: 4, RigMaxAgents: 2, Nice: 15, OOMScoreAdj: 300}
+	if got != want {
+		t.Errorf("LoadLimits = %+v, want %+v", got, want)
+	}
+	if got := LoadLimits(t.TempDir(), ""); got.Capped() {
+		t.Errorf("LoadLimits without settings = %+v, want no caps", got)
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	town := t.TempDir()
+	req := Request{Rig: "gastown", Role: "crew", Name: "max"}
+
+	release, err := Limits{MaxAgents: 2}.Admit(town, req, listed("gt-gastown-Toast"))
+	if err != nil {
+		t.Fatalf("Admit under cap: %v", err)
+	}
+	release()
+	release() // Safe to call twice
+
+	_, err = Limits{MaxAgents: 1}.Admit(town, req, listed("gt-gastown-Toast"))
+	if !errors.Is(err, ErrQueued) {
+		t.Fatalf("Admit at cap = %v, want ErrQueued", err)
+	}
+	q, err := Queue(town)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q) != 1 || q[0].Session() != "gt-gastown-crew-max" || q[0].QueuedAt.IsZero() {
+		t.Errorf("queue = %+v, want one stamped gt-gastown-crew-max", q)
+	}
+}
+
+func TestEnqueueDedupesAndDequeue(t *testing.T) {
+	town := t.TempDir()
+	if err := Dequeue(town, "gt-gastown-Toast"); err != nil {
+		t.Fatalf("Dequeue on a missing queue: %v", err)
+	}
+	for _, req := range []Request{
+		{Rig: "gastown", Role: "polecat", Name: "Toast"},
+		{Rig: "gastown", Role: "crew", Name: "max"},
+		{Rig: "gastown", Role: "polecat", Name: "Toast", Issue: "gt-123"},
+	} {
+		if err := Enqueue(town, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q, _ := Queue(town)
+	if len(q) != 2 || q[0].Name != "Toast" || q[0].Issue != "gt-123" {
+		t.Fatalf("queue = %+v, want Toast (gt-123) then max", q)
+	}
+
+	if err := Dequeue(town, "gt-gastown-Toast"); err != nil {
+		t.Fatal(err)
+	}
+	q, _ = Queue(town)
+	if len(q) != 1 || q[0].Name != "max" {
+		t.Errorf("queue after Dequeue = %+v, want only max", q)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	town := t.TempDir()
+	writeTownScheduler(t, town, &config.SchedulerConfig{MaxConcurrentAgents: 2})
+	for _, req := range []Request{
+		{Rig: "gastown", Role: "crew", Name: "max"},    // Already running: dropped
+		{Rig: "gastown", Role: "polecat", Name: "Nux"}, // Fits
+		{Rig: "beads", Role: "crew", Name: "joe"},      // Town cap reached again
+	} {
+		if err := Enqueue(town, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var ran []string
+	started, err := Drain(town, listed("gt-gastown-crew-max"), func(r Request) error {
+		ran = append(ran, r.Session())
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(started) != 1 || len(ran) != 1 || ran[0] != "gt-gastown-Nux" {
+		t.Errorf("started %v, want only gt-gastown-Nux", ran)
+	}
+	q, _ := Queue(town)
+	if len(q) != 1 || q[0].Session() != "gt-beads-crew-joe" {
+		t.Errorf("queue after Drain = %+v, want only gt-beads-crew-joe", q)
+	}
+}
+
+func TestRequestArgs(t *testing.T) {
+	crew := Request{Rig: "gastown", Role: "crew", Name: "max"}
+	if got := crew.Args(); len(got) != 4 || got[0] != "crew" || got[3] != "max" {
+		t.Errorf("crew Args = %v", got)
+	}
+	polecat := Request{Rig: "gastown", Role: "polecat", Name: "Toast", Issue: "gt-123"}
+	want := []string{"session", "start", "gastown/Toast", "--issue", "gt-123"}
+	got := polecat.Args()
+	if len(got) != len(want) {
+		t.Fatalf("polecat Args = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("polecat Args = %v, want %v", got, want)
+			break
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+// FileBackend keeps secrets in dir/secrets.enc, a JSON map encrypted with
+// AES-256-GCM under a random key in dir/secrets.key. Both files are 0600:
+// this protects against configs, logs and pane history leaking keys, not
+// against someone who can read the user's home directory.
+type FileBackend struct {
+	dir string
+}
+
+// NewFileBackend returns a file backend storing its files in dir.
+func NewFileBackend(dir string) *FileBackend {
+	return &FileBackend{dir: dir}
+}
+
+// Name returns "file".
+func (f *FileBackend) Name() string {
+	return "file"
+}
+
+// Get returns the value of secret name.
+func (f *FileBackend) Get(name string) (string, error) {
+	values, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	value, ok := values[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+// Set stores value as secret name, creating the key on first use.
+// Callers serialize Sets (Store holds its lock).
+func (f *FileBackend) Set(name, value string) error {
+	values, err := f.read()
+	if err != nil {
+		return err
+	}
+	values[name] = value
+	return f.write(values)
+}
+
+func (f *FileBackend) dataPath() string {
+	return filepath.Join(f.dir, "secrets.enc")
+}
+
+func (f *FileBackend) keyPath() string {
+	return filepath.Join(f.dir, "secrets.key")
+}
+
+func (f *FileBackend) read() (map[string]string, error) {
+	values := make(map[string]string)
+	data, err := os.ReadFile(f.dataPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets: %w", err)
+	}
+	key, err := os.ReadFile(f.keyPath())
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets key: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, fmt.Errorf("secrets file %s is truncated", f.dataPath())
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting secrets (wrong key for %s?): %w", f.dataPath(), err)
+	}
+	if err := json.Unmarshal(plain, &values); err != nil {
+		return nil, fmt.Errorf("parsing secrets: %w", err)
+	}
+	return values, nil
+}
+
+func (f *FileBackend) write(values map[string]string) error {
+	if err := os.MkdirAll(f.dir, 0700); err != nil {
+		return fmt.Errorf("creating secrets directory: %w", err)
+	}
+	key, err := f.loadOrCreateKey()
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+	if err := util.AtomicWriteFile(f.dataPath(), gcm.Seal(nonce, nonce, plain, nil), 0600); err != nil {
+		return fmt.Errorf("writing secrets: %w", err)
+	}
+	return nil
+}
+
+// loadOrCreateKey returns the file key, generating it if there is none yet.
+// A key is never replaced: that would orphan the existing secrets.
+func (f *FileBackend) loadOrCreateKey() ([]byte, error) {
+	key, err := os.ReadFile(f.keyPath())
+	if err == nil {
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("reading secrets key: %w", err)
+	}
+	if _, err := os.Stat(f.dataPath()); err == nil {
+		return nil, fmt.Errorf("secrets key %s is missing; secrets in %s can't be decrypted", f.keyPath(), f.dataPath())
+	}
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generating secrets key: %w", err)
+	}
+	if err := util.AtomicWriteFile(f.keyPath(), key, 0600); err != nil {
+		return nil, fmt.Errorf("writing secrets key: %w", err)
+	}
+	return key, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainService is the service every gt secret is filed under.
+const keychainService = "gastown"
+
+// keychain stores secrets in the OS keychain through its CLI: security(1)
+// on macOS, secret-tool(1) (libsecret) on Linux.
+type keychain struct {
+	tool string
+}
+
+// newKeychain returns the OS keychain backend, or nil if this system has
+// no usable keychain (no CLI, or no D-Bus session on Linux, e.g. over SSH).
+func newKeychain() *keychain {
+	var tool string
+	switch runtime.GOOS {
+	case "darwin":
+		tool = "security"
+	case "linux":
+		if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return nil
+		}
+		tool = "secret-tool"
+	default:
+		return nil
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return nil
+	}
+	return &keychain{tool: tool}
+}
+
+// Name returns "keychain".
+func (k *keychain) Name() string {
+	return "keychain"
+}
+
+// Get returns the value of secret name.
+func (k *keychain) Get(name string) (string, error) {
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		cmd = exec.Command("security", "find-generic-password", "-s", keychainService, "-a", name, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", keychainService, "account", name)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		// security exits 44 for a missing item; secret-tool exits 1 silently
+		if errors.As(err, &exitErr) && (exitErr.ExitCode() == 44 || stderr.Len() == 0) {
+			return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+		return "", fmt.Errorf("reading %s from keychain: %s", name, strings.TrimSpace(stderr.String()))
+	}
+	// security -w adds a newline; secret-tool prints the value as stored
+	if k.tool == "security" {
+		return strings.TrimSuffix(string(out), "\n"), nil
+	}
+	return string(out), nil
+}
+
+// Set stores value as secret name. secret-tool reads the value from stdin;
+// security(1) only takes it as an argument, so on macOS it is briefly
+// visible to other processes of the same user.
+func (k *keychain) Set(name, value string) error {
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keychainService, "-a", name, "-w", value)
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", keychainService+": "+name, "service", keychainService, "account", name)
+		cmd.Stdin = strings.NewReader(value)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("storing %s in keychain: %s", name, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+// Package secrets stores agent API keys by name, so they can be injected
+// into an agent's environment at spawn instead of living in shell profiles
+// that every pane reads. Values go in the OS keychain where there is one,
+// or in an encrypted file under ~/.gastown/secrets otherwise.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/util"
+)
+
+const (
+	// EnvDir overrides the secrets directory, ~/.gastown/secrets.
+	EnvDir = "GT_SECRETS_DIR"
+
+	// EnvBackend forces a backend: "keychain" or "file".
+	EnvBackend = "GT_SECRETS_BACKEND"
+)
+
+var (
+	ErrNotFound       = errors.New("secret not found")
+	ErrInvalidName    = errors.New("invalid secret name")
+	ErrNoKeychain     = errors.New("no OS keychain available")
+	ErrUnknownBackend = errors.New("unknown secrets backend")
+)
+
+// lockTimeout is how long to wait for another gt process to finish
+// updating the store.
+const lockTimeout = 5 * time.Second
+
+// Backend holds secret values.
+type Backend interface {
+	// Name identifies the backend in gt secret output, e.g. "keychain".
+	Name() string
+	// Get returns the value of secret name, or ErrNotFound.
+	Get(name string) (string, error)
+	// Set stores value as secret name, replacing any previous value.
+	Set(name, value string) error
+}
+
+// Store is a Backend plus an index of the secret names it holds, so names
+// can be listed without reading (or being able to read) any values.
+type Store struct {
+	dir     string
+	backend Backend
+}
+
+// Dir returns the secrets directory: GT_SECRETS_DIR, or ~/.gastown/secrets.
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "gastown-secrets")
+	}
+	return filepath.Join(home, ".gastown", "secrets")
+}
+
+// Open returns the store in Dir, backed by the OS keychain when one is
+// available and by an encrypted file otherwise. GT_SECRETS_BACKEND picks
+// the backend explicitly.
+func Open() (*Store, error) {
+	dir := Dir()
+	switch os.Getenv(EnvBackend) {
+	case "":
+		if kc := newKeychain(); kc != nil {
+			return NewStore(dir, kc), nil
+		}
+		return NewStore(dir, NewFileBackend(dir)), nil
+	case "keychain":
+		kc := newKeychain()
+		if kc == nil {
+			return nil, ErrNoKeychain
+		}
+		return NewStore(dir, kc), nil
+	case "file":
+		return NewStore(dir, NewFileBackend(dir)), nil
+	default:
+		return nil, fmt.Errorf("%w: %q (want keychain or file)", ErrUnknownBackend, os.Getenv(EnvBackend))
+	}
+}
+
+// NewStore returns a store keeping its name index in dir and values in backend.
+func NewStore(dir string, backend Backend) *Store {
+	return &Store{dir: dir, backend: backend}
+}
+
+// Backend returns the name of the store's backend.
+func (s *Store) Backend() string {
+	return s.backend.Name()
+}
+
+// Get returns the value of secret name.
+func (s *Store) Get(name string) (string, error) {
+	if !ValidName(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return s.backend.Get(name)
+}
+
+// Set stores value as secret name and records the name in the index.
+func (s *Store) Set(name, value string) error {
+	if !ValidName(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return s.withLock(func() error {
+		if err := s.backend.Set(name, value); err != nil {
+			return err
+		}
+		names, err := s.readIndex()
+		if err != nil {
+			return err
+		}
+		for _, n := range names {
+			if n == name {
+				return nil
+			}
+		}
+		names = append(names, name)
+		sort.Strings(names)
+		return s.writeIndex(names)
+	})
+}
+
+// List returns the names of the stored secrets, sorted.
+func (s *Store) List() ([]string, error) {
+	return s.readIndex()
+}
+
+// ValidName reports whether name can name a secret: letters, digits, '.',
+// '_' and '-', so names are safe in shell commands and keychain queries.
+func ValidName(name string) bool {
+	for _, c := range name {
+		if c != '.' && c != '_' && c != '-' && !('a' <= c && c <= 'z') && !('A' <= c && c <= 'Z') && !('0' <= c && c <= '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func (s *Store) indexPath() string {
+	return filepath.Join(s.dir, "names.json")
+}
+
+func (s *Store) readIndex() ([]string, error) {
+	data, err := os.ReadFile(s.indexPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading secrets index: %w", err)
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("parsing secrets index %s: %w", s.indexPath(), err)
+	}
+	return names, nil
+}
+
+func (s *Store) writeIndex(names []string) error {
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := util.AtomicWriteFile(s.indexPath(), data, 0600); err != nil {
+		return fmt.Errorf("writing secrets index: %w", err)
+	}
+	return nil
+}
+
+// withLock runs fn holding the store's lock, so concurrent gt secret set
+// calls don't lose each other's index or file updates.
+func (s *Store) withLock(fn func() error) error {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("creating secrets directory: %w", err)
+	}
+	lock := flock.New(filepath.Join(s.dir, ".lock"))
+	ctx, cancel := context.WithTimeout(context.Background(), lockTimeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, 50*time.Millisecond)
+	if err != nil {
+		return fmt.Errorf("locking secrets: %w", err)
+	}
+	if !locked {
+		return fmt.Errorf("timeout waiting for secrets lock")
+	}
+	defer func() { _ = lock.Unlock() }()
+	return fn()
+}
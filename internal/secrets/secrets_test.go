@@ -0,0 +1,112 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store := NewStore(dir, NewFileBackend(dir))
+
+	if _, err := store.Get("moonshot"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(missing) = %v, want ErrNotFound", err)
+	}
+	if err := store.Set("moonshot", "sk-one"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("openai", "sk-two"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("moonshot", "sk-three"); err != nil {
+		t.Fatalf("Set (replace): %v", err)
+	}
+
+	got, err := store.Get("moonshot")
+	if err != nil || got != "sk-three" {
+		t.Errorf("Get(moonshot) = %q, %v; want sk-three", got, err)
+	}
+	names, err := store.List()
+	if err != nil || !slices.Equal(names, []string{"moonshot", "openai"}) {
+		t.Errorf("List() = %v, %v; want [moonshot openai]", names, err)
+	}
+
+	// Values never reach disk in the clear
+	data, err := os.ReadFile(filepath.Join(dir, "secrets.enc"))
+	if err != nil {
+		t.Fatalf("reading secrets file: %v", err)
+	}
+	if strings.Contains(string(data), "sk-") {
+		t.Error("secrets file contains a plaintext value")
+	}
+	for _, f := range []string{"secrets.enc", "secrets.key", "names.json"} {
+		info, err := os.Stat(filepath.Join(dir, f))
+		if err != nil {
+			t.Fatalf("stat %s: %v", f, err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Errorf("%s mode = %o, want 600", f, perm)
+		}
+	}
+}
+
+func TestFileBackendMissingKey(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	backend := NewFileBackend(dir)
+	if err := backend.Set("moonshot", "sk-one"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := os.Remove(filepath.Join(dir, "secrets.key")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A lost key must not be silently replaced, orphaning the file
+	if err := backend.Set("openai", "sk-two"); err == nil {
+		t.Error("Set with a missing key succeeded, want an error")
+	}
+	if _, err := backend.Get("moonshot"); err == nil {
+		t.Error("Get with a missing key succeeded, want an error")
+	}
+}
+
+func TestStoreRejectsInvalidNames(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store := NewStore(dir, NewFileBackend(dir))
+	for _, name := range []string{"", "a b", "$(x)", "a/b"} {
+		if err := store.Set(name, "v"); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Set(%q) = %v, want ErrInvalidName", name, err)
+		}
+		if _, err := store.Get(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Get(%q) = %v, want ErrInvalidName", name, err)
+		}
+	}
+	if !ValidName("moonshot.work-1_a") {
+		t.Error("ValidName(moonshot.work-1_a) = false, want true")
+	}
+}
+
+func TestOpenBackendOverride(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(EnvDir, dir)
+
+	t.Setenv(EnvBackend, "file")
+	store, err := Open()
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if store.Backend() != "file" {
+		t.Errorf("Backend() = %q, want file", store.Backend())
+	}
+
+	t.Setenv(EnvBackend, "vault")
+	if _, err := Open(); !errors.Is(err, ErrUnknownBackend) {
+		t.Errorf("Open(vault) = %v, want ErrUnknownBackend", err)
+	}
+}
@@ -0,0 +1,318 @@
+// Package selfupdate replaces the running gt binary with a GitHub release.
+//
+// Releases are built by GoReleaser (.goreleaser.yml): one archive per
+// platform, gastown_<version>_<os>_<arch>.tar.gz (.zip on Windows), and a
+// checksums.txt of their SHA-256 sums. An archive is only installed if its
+// sum matches checksums.txt. If the release also carries a detached GPG
+// signature of the checksums (checksums.txt.sig), it is checked with gpg
+// against the keys in the user's keyring.
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repo is the GitHub repository gt releases are published to.
+const Repo = "steveyegge/gastown"
+
+// APIBase is the GitHub API endpoint; tests point it at a fake server.
+var APIBase = "https://api.github.com"
+
+// ChecksumsAsset and SignatureAsset are the release assets that hold the
+// archives' sums and the signature of those sums.
+const (
+	ChecksumsAsset = "checksums.txt"
+	SignatureAsset = "checksums.txt.sig"
+)
+
+// maxDownload caps an asset download, so a bad URL can't fill the disk.
+const maxDownload = 200 << 20
+
+var (
+	ErrNoAsset          = errors.New("release has no archive for this platform")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+	ErrNoSignature      = errors.New("release is not signed")
+	ErrBadSignature     = errors.New("signature verification failed")
+)
+
+// Client fetches releases. The zero value uses http.DefaultClient.
+type Client struct {
+	HTTP *http.Client
+}
+
+// Release is a published gt release.
+type Release struct {
+	Tag    string  `json:"tag_name"` // "v0.6.0"
+	Assets []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+}
+
+// Version returns the release's version without the leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.Tag, "v")
+}
+
+// Asset returns the asset named name.
+func (r *Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// ArchiveName returns the name of the release archive of version for
+// goos/goarch.
+func ArchiveName(version, goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("gastown_%s_%s_%s%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// Latest returns the newest published release.
+func (c *Client) Latest(ctx context.Context) (*Release, error) {
+	return c.release(ctx, APIBase+"/repos/"+Repo+"/releases/latest")
+}
+
+// ByVersion returns the release of version ("0.6.0" or "v0.6.0").
+func (c *Client) ByVersion(ctx context.Context, version string) (*Release, error) {
+	return c.release(ctx, APIBase+"/repos/"+Repo+"/releases/tags/v"+strings.TrimPrefix(version, "v"))
+}
+
+func (c *Client) release(ctx context.Context, url string) (*Release, error) {
+	data, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	var r Release
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parsing release: %w", err)
+	}
+	if r.Tag == "" {
+		return nil, fmt.Errorf("release has no tag")
+	}
+	return &r, nil
+}
+
+// Download fetches a release asset.
+func (c *Client) Download(ctx context.Context, a Asset) ([]byte, error) {
+	return c.get(ctx, a.URL)
+}
+
+func (c *Client) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "gt-upgrade")
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxDownload+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxDownload {
+		return nil, fmt.Errorf("GET %s: larger than %d bytes", url, maxDownload)
+	}
+	return data, nil
+}
+
+// VerifyChecksum checks data, the asset named name, against its SHA-256
+// sum in checksums (sha256sum format: "<hex>  <name>" per line).
+func VerifyChecksum(checksums []byte, name string, data []byte) error {
+	for _, line := range strings.Split(string(checksums), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || strings.TrimPrefix(fields[1], "*") != name {
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if !strings.EqualFold(fields[0], hex.EncodeToString(sum[:])) {
+			return fmt.Errorf("%w: %s", ErrChecksumMismatch, name)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s not listed in %s", ErrChecksumMismatch, name, ChecksumsAsset)
+}
+
+// VerifySignature checks sig, a detached GPG signature, over checksums with
+// gpg and the user's keyring.
+func VerifySignature(checksums, sig []byte) error {
+	gpg, err := exec.LookPath("gpg")
+	if err != nil {
+		return fmt.Errorf("%w: gpg not found", ErrBadSignature)
+	}
+	dir, err := os.MkdirTemp("", "gt-upgrade-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	dataPath, sigPath := filepath.Join(dir, ChecksumsAsset), filepath.Join(dir, SignatureAsset)
+	if err := os.WriteFile(dataPath, checksums, 0600); err != nil {
+		return err
+	}
+	if err := os.WriteFile(sigPath, sig, 0600); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, gpg, "--batch", "--verify", sigPath, dataPath).CombinedOutput() //nolint:gosec // G204: fixed arguments
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBadSignature, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ExtractBinary returns the file named binary ("gt" or "gt.exe") from a
+// release archive named name (.tar.gz or .zip).
+func ExtractBinary(name string, archive []byte, binary string) ([]byte, error) {
+	if strings.HasSuffix(name, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != binary || f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer rc.Close()
+			return io.ReadAll(io.LimitReader(rc, maxDownload))
+		}
+		return nil, fmt.Errorf("%s has no %s", name, binary)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s has no %s", name, binary)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == binary {
+			return io.ReadAll(io.LimitReader(tr, maxDownload))
+		}
+	}
+}
+
+// Replace installs data as the executable at exe, keeping the old binary
+// as exe+".old" for rollback. The new binary is written next to exe and
+// renamed into place, so exe is never a partial file; moving the old one
+// aside first also works on Windows, where a running binary can be renamed
+// but not replaced.
+func Replace(exe string, data []byte) (backup string, err error) {
+	info, err := os.Stat(exe)
+	if err != nil {
+		return "", err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(exe), "."+filepath.Base(exe)+".*.new")
+	if err != nil {
+		return "", fmt.Errorf("writing next to %s: %w", exe, err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Chmod(info.Mode().Perm() | 0111); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+
+	backup = exe + ".old"
+	_ = os.Remove(backup)
+	if err := os.Rename(exe, backup); err != nil {
+		return "", fmt.Errorf("moving %s aside: %w", exe, err)
+	}
+	if err := os.Rename(tmp.Name(), exe); err != nil {
+		_ = os.Rename(backup, exe)
+		return "", fmt.Errorf("installing %s: %w", exe, err)
+	}
+	return backup, nil
+}
+
+// Compare compares two versions ("0.6.0", "v0.10.1-rc1"), numerically by
+// major, minor and patch. A pre-release sorts before its release.
+// Returns -1 if a < b, 0 if a == b, 1 if a > b.
+func Compare(a, b string) int {
+	an, apre := parseVersion(a)
+	bn, bpre := parseVersion(b)
+	for i := range an {
+		if an[i] != bn[i] {
+			if an[i] < bn[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case apre == bpre:
+		return 0
+	case apre == "":
+		return 1
+	case bpre == "":
+		return -1
+	case apre < bpre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// parseVersion splits "vX.Y.Z-pre" into its numbers and pre-release.
+func parseVersion(v string) ([3]int, string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, pre, _ := strings.Cut(v, "-")
+	var n [3]int
+	for i, part := range strings.SplitN(v, ".", 3) {
+		n[i], _ = strconv.Atoi(part)
+	}
+	return n, pre
+}
@@ -0,0 +1,176 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReleaseDownloadAndVerify(t *testing.T) {
+	name := ArchiveName("v0.6.0", "linux", "amd64")
+	archive := tarGz(t, map[string]string{"LICENSE": "mit", "gt": "new gt"})
+	sum := sha256.Sum256(archive)
+	sums := fmt.Sprintf("%s  %s\ndeadbeef  other.tar.gz\n", hex.EncodeToString(sum[:]), name)
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/" + Repo + "/releases/latest", "/repos/" + Repo + "/releases/tags/v0.6.0":
+			fmt.Fprintf(w, `{"tag_name":"v0.6.0","assets":[{"name":%q,"browser_download_url":"%s/a"},{"name":"checksums.txt","browser_download_url":"%s/sums"}]}`, name, srv.URL, srv.URL)
+		case "/a":
+			_, _ = w.Write(archive)
+		case "/sums":
+			fmt.Fprint(w, sums)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	old := APIBase
+	APIBase = srv.URL
+	defer func() { APIBase = old }()
+
+	ctx := context.Background()
+	c := &Client{}
+	release, err := c.Latest(ctx)
+	if err != nil {
+		t.Fatalf("Latest: %v", err)
+	}
+	if release.Version() != "0.6.0" {
+		t.Errorf("Version() = %q, want 0.6.0", release.Version())
+	}
+	if _, err := c.ByVersion(ctx, "0.6.0"); err != nil {
+		t.Errorf("ByVersion: %v", err)
+	}
+	if _, err := c.ByVersion(ctx, "9.9.9"); err == nil {
+		t.Error("ByVersion(missing) = nil error")
+	}
+
+	asset, ok := release.Asset(name)
+	if !ok {
+		t.Fatalf("no asset %s", name)
+	}
+	data, err := c.Download(ctx, asset)
+	if err != nil {
+		t.Fatalf("Download: %v", err)
+	}
+	if err := VerifyChecksum([]byte(sums), name, data); err != nil {
+		t.Errorf("VerifyChecksum: %v", err)
+	}
+	if err := VerifyChecksum([]byte(sums), name, append(data, 0)); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("VerifyChecksum(tampered) = %v, want ErrChecksumMismatch", err)
+	}
+	if err := VerifyChecksum([]byte(sums), "missing.tar.gz", data); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("VerifyChecksum(unlisted) = %v, want ErrChecksumMismatch", err)
+	}
+
+	bin, err := ExtractBinary(name, data, "gt")
+	if err != nil || string(bin) != "new gt" {
+		t.Errorf("ExtractBinary = %q, %v; want the gt binary", bin, err)
+	}
+}
+
+func TestExtractBinaryZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("gastown/gt.exe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write([]byte("windows gt"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	bin, err := ExtractBinary("gastown_0.6.0_windows_amd64.zip", buf.Bytes(), "gt.exe")
+	if err != nil || string(bin) != "windows gt" {
+		t.Errorf("ExtractBinary = %q, %v", bin, err)
+	}
+	if _, err := ExtractBinary("gastown_0.6.0_windows_amd64.zip", buf.Bytes(), "gt"); err == nil {
+		t.Error("ExtractBinary(absent) = nil error")
+	}
+}
+
+func TestReplace(t *testing.T) {
+	exe := filepath.Join(t.TempDir(), "gt")
+	if err := os.WriteFile(exe, []byte("old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	backup, err := Replace(exe, []byte("new"))
+	if err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if got, _ := os.ReadFile(exe); string(got) != "new" {
+		t.Errorf("exe = %q, want new", got)
+	}
+	if got, _ := os.ReadFile(backup); string(got) != "old" {
+		t.Errorf("backup = %q, want old", got)
+	}
+	if info, _ := os.Stat(exe); info.Mode().Perm()&0100 == 0 {
+		t.Errorf("exe mode = %v, want executable", info.Mode())
+	}
+	entries, _ := os.ReadDir(filepath.Dir(exe))
+	if len(entries) != 2 {
+		t.Errorf("dir has %d entries, want exe and backup only", len(entries))
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.6.0", "0.5.0", 1},
+		{"v0.10.0", "0.9.9", 1},
+		{"0.5.0", "v0.5.0", 0},
+		{"0.6.0-rc1", "0.6.0", -1},
+		{"0.6.0-rc2", "0.6.0-rc1", 1},
+		{"1.0", "1.0.1", -1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	if got := ArchiveName("v0.6.0", "darwin", "arm64"); got != "gastown_0.6.0_darwin_arm64.tar.gz" {
+		t.Errorf("ArchiveName(darwin) = %q", got)
+	}
+	if got := ArchiveName("0.6.0", "windows", "amd64"); got != "gastown_0.6.0_windows_amd64.zip" {
+		t.Errorf("ArchiveName(windows) = %q", got)
+	}
+}
@@ -0,0 +1,31 @@
+package session
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// CrewSessions returns the names of crew members with a running session in
+// the given rig (e.g., "max" for gt-<rig>-crew-max), sorted alphabetically.
+func CrewSessions(rig string) ([]string, error) {
+	return crewSessions(tmux.NewTmux(), rig)
+}
+
+func crewSessions(t *tmux.Tmux, rig string) ([]string, error) {
+	prefix := CrewSessionName(rig, "")
+	sessions, err := t.ListSessionsWithPrefix(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, s := range sessions {
+		if name := strings.TrimPrefix(s, prefix); name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
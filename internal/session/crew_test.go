@@ -0,0 +1,62 @@
+package session
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func fakeSessionList(sessions ...string) *tmux.Tmux {
+	return tmux.NewTmux(tmux.WithRunner(func(args ...string) (string, string, error) {
+		if args[0] != "list-sessions" {
+			return "", "", errors.New("unexpected tmux command: " + strings.Join(args, " "))
+		}
+		return strings.Join(sessions, "\n") + "\n", "", nil
+	}))
+}
+
+func TestCrewSessions(t *testing.T) {
+	tm := fakeSessionList(
+		"hq-mayor",
+		"gt-gastown-crew-max",
+		"gt-gastown-witness",
+		"gt-beads-crew-jack",
+		"gt-gastown-crew-alice",
+		"gt-gastown-furiosa",
+		"gt-gastown-dev-crew-bob",
+	)
+
+	got, err := crewSessions(tm, "gastown")
+	if err != nil {
+		t.Fatalf("crewSessions: %v", err)
+	}
+	want := []string{"alice", "max"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("crewSessions(gastown) = %v, want %v", got, want)
+	}
+
+	got, err = crewSessions(tm, "beads")
+	if err != nil {
+		t.Fatalf("crewSessions: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"jack"}) {
+		t.Errorf("crewSessions(beads) = %v, want [jack]", got)
+	}
+}
+
+func TestCrewSessionsNoServer(t *testing.T) {
+	tm := tmux.NewTmux(tmux.WithRunner(func(args ...string) (string, string, error) {
+		return "", "no server running on /tmp/tmux-1000/default", errors.New("exit status 1")
+	}))
+
+	got, err := crewSessions(tm, "gastown")
+	if err != nil {
+		t.Fatalf("crewSessions: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("crewSessions with no server = %v, want empty", got)
+	}
+}
@@ -4,6 +4,8 @@ package session
 import (
 	"fmt"
 	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 // Role represents the type of Gas Town agent.
@@ -22,7 +24,7 @@ const (
 type AgentIdentity struct {
 	Role Role   // mayor, deacon, witness, refinery, crew, polecat
 	Rig  string // rig name (empty for mayor/deacon)
-	Name string // crew/polecat name (empty for mayor/deacon/witness/refinery)
+	Name string // crew/polecat name; "standby" for the standby mayor, else empty
 }
 
 // ParseAddress parses a mail-style address into an AgentIdentity.
@@ -82,6 +84,7 @@ func ParseAddress(address string) (*AgentIdentity, error) {
 //
 // Session name formats:
 //   - hq-mayor → Role: mayor (town-level, one per machine)
+//   - hq-mayor-standby → Role: mayor, Name: standby (gt mayor standby)
 //   - hq-deacon → Role: deacon (town-level, one per machine)
 //   - gt-<rig>-witness → Role: witness, Rig: <rig>
 //   - gt-<rig>-refinery → Role: refinery, Rig: <rig>
@@ -98,6 +101,9 @@ func ParseSessionName(session string) (*AgentIdentity, error) {
 		if suffix == "mayor" {
 			return &AgentIdentity{Role: RoleMayor}, nil
 		}
+		if suffix == "mayor-standby" {
+			return &AgentIdentity{Role: RoleMayor, Name: "standby"}, nil
+		}
 		if suffix == "deacon" {
 			return &AgentIdentity{Role: RoleDeacon}, nil
 		}
@@ -148,10 +154,28 @@ func ParseSessionName(session string) (*AgentIdentity, error) {
 	return &AgentIdentity{Role: RolePolecat, Rig: rig, Name: name}, nil
 }
 
+// ParseCustomRoleSession matches a tmux session name against the town's
+// custom roles (config.TownSettings.Roles). Custom roles take precedence
+// over the polecat fallback of ParseSessionName, but never over the other
+// built-in roles: a template like gt-{rig}-auditor would otherwise also
+// match gt-<rig>-crew-auditor.
+func ParseCustomRoleSession(session string, roles map[string]*config.CustomRoleConfig) (*config.CustomRole, bool) {
+	if len(roles) == 0 {
+		return nil, false
+	}
+	if identity, err := ParseSessionName(session); err == nil && identity.Role != RolePolecat {
+		return nil, false
+	}
+	return config.MatchCustomRoleSession(roles, session)
+}
+
 // SessionName returns the tmux session name for this identity.
 func (a *AgentIdentity) SessionName() string {
 	switch a.Role {
 	case RoleMayor:
+		if a.Name == "standby" {
+			return MayorStandbySessionName()
+		}
 		return MayorSessionName()
 	case RoleDeacon:
 		return DeaconSessionName()
@@ -2,6 +2,8 @@ package session
 
 import (
 	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
 )
 
 func TestParseSessionName(t *testing.T) {
@@ -19,6 +21,12 @@ func TestParseSessionName(t *testing.T) {
 			session:  "hq-mayor",
 			wantRole: RoleMayor,
 		},
+		{
+			name:     "standby mayor",
+			session:  "hq-mayor-standby",
+			wantRole: RoleMayor,
+			wantName: "standby",
+		},
 		{
 			name:     "deacon",
 			session:  "hq-deacon",
@@ -231,6 +239,7 @@ func TestParseSessionName_RoundTrip(t *testing.T) {
 	// Test that parsing then reconstructing gives the same result
 	sessions := []string{
 		"hq-mayor",
+		"hq-mayor-standby",
 		"hq-deacon",
 		"gt-gastown-witness",
 		"gt-foo-bar-refinery",
@@ -251,6 +260,37 @@ func TestParseSessionName_RoundTrip(t *testing.T) {
 	}
 }
 
+func TestParseCustomRoleSession(t *testing.T) {
+	roles := map[string]*config.CustomRoleConfig{
+		"auditor":   {Session: "gt-{rig}-auditor"},
+		"librarian": {Session: "hq-librarian"},
+	}
+	tests := []struct {
+		session string
+		want    string // matched role's address; "" for no match
+	}{
+		{"gt-gastown-auditor", "gastown/auditor"},
+		{"hq-librarian", "librarian"},
+		{"gt-gastown-crew-auditor", ""}, // Crew member named auditor
+		{"gt-gastown-witness", ""},
+		{"gt-gastown-Toast", ""},
+	}
+	for _, tt := range tests {
+		role, ok := ParseCustomRoleSession(tt.session, roles)
+		got := ""
+		if ok {
+			got = role.Address()
+		}
+		if got != tt.want {
+			t.Errorf("ParseCustomRoleSession(%q) = %q, want %q", tt.session, got, tt.want)
+		}
+	}
+
+	if _, ok := ParseCustomRoleSession("gt-gastown-auditor", nil); ok {
+		t.Error("matched without custom roles")
+	}
+}
+
 func TestParseAddress(t *testing.T) {
 	tests := []struct {
 		name    string
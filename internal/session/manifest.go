@@ -0,0 +1,173 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/store"
+)
+
+// ManifestEntry records how to bring one agent session back: who it was,
+// where it ran, and which agent conversation it was in.
+type ManifestEntry struct {
+	Session        string    `json:"session"`               // tmux session name, e.g. gt-gastown-crew-max
+	Role           string    `json:"role"`                  // e.g. crew
+	Rig            string    `json:"rig,omitempty"`         // empty for town-level roles
+	Agent          string    `json:"agent,omitempty"`       // agent preset, e.g. claude; empty for the default
+	Model          string    `json:"model,omitempty"`       // model override, e.g. opus; empty for the agent's default
+	ResumeFlag     string    `json:"resume_flag,omitempty"` // resume flag override (gt handoff --resume-flag); empty for the preset's
+	WorkDir        string    `json:"work_dir"`              // where the agent ran
+	TownRoot       string    `json:"town_root"`             // the town the session belongs to
+	AgentSessionID string    `json:"agent_session_id"`      // the agent's conversation ID, for resume
+	Tags           Tags      `json:"tags,omitempty"`        // gt tag labels, restored on resume
+	UpdatedAt      time.Time `json:"updated_at"`            // when the entry was last recorded
+}
+
+// manifestFile is the on-disk layout of the session manifest.
+type manifestFile struct {
+	Sessions map[string]ManifestEntry `json:"sessions"`
+}
+
+// ManifestPath returns the session manifest, ~/.gastown/state/sessions.json.
+func ManifestPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "gt-sessions.json")
+	}
+	return filepath.Join(home, ".gastown", "state", "sessions.json")
+}
+
+// LoadManifest returns the entries in the manifest at path, sorted by
+// session name. A missing manifest is empty.
+func LoadManifest(path string) ([]ManifestEntry, error) {
+	m, err := readManifest(path)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]ManifestEntry, 0, len(m.Sessions))
+	for _, e := range m.Sessions {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Session < entries[j].Session })
+	return entries, nil
+}
+
+// RecordSession adds or replaces e's entry in the manifest at path,
+// stamping it with the current time. An entry without tags keeps the tags
+// already recorded for the session.
+func RecordSession(path string, e ManifestEntry) error {
+	if e.Session == "" {
+		return fmt.Errorf("manifest entry has no session name")
+	}
+	e.UpdatedAt = time.Now().UTC()
+	return updateManifest(path, func(m *manifestFile) bool {
+		if e.Tags == nil {
+			e.Tags = m.Sessions[e.Session].Tags
+		}
+		m.Sessions[e.Session] = e
+		return true
+	})
+}
+
+// SetSessionTags replaces the tags recorded for a session in the manifest
+// at path. A session without an entry yet gets its tags when gt prime
+// records it.
+func SetSessionTags(path, sessionName string, tags Tags) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return updateManifest(path, func(m *manifestFile) bool {
+		e, ok := m.Sessions[sessionName]
+		if !ok {
+			return false
+		}
+		e.Tags = tags
+		m.Sessions[sessionName] = e
+		return true
+	})
+}
+
+// ForgetSession removes a session from the manifest at path, so that a
+// deliberately stopped session isn't resumed. Forgetting an unrecorded
+// session is a no-op.
+func ForgetSession(path, sessionName string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil // Nothing recorded; don't create the state directory
+	}
+	return updateManifest(path, func(m *manifestFile) bool {
+		if _, ok := m.Sessions[sessionName]; !ok {
+			return false
+		}
+		delete(m.Sessions, sessionName)
+		return true
+	})
+}
+
+// HandOverSession records in the manifest at path that the session
+// oldName now runs as newName (a renamed tmux session, e.g. a promoted
+// standby mayor): oldName's entry replaces newName's. If oldName has no
+// entry, newName's is dropped, so its old conversation isn't resumed in
+// place of the running one.
+func HandOverSession(path, oldName, newName string) error {
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return updateManifest(path, func(m *manifestFile) bool {
+		e, ok := m.Sessions[oldName]
+		if !ok {
+			_, had := m.Sessions[newName]
+			delete(m.Sessions, newName)
+			return had
+		}
+		delete(m.Sessions, oldName)
+		e.Session = newName
+		e.UpdatedAt = time.Now().UTC()
+		m.Sessions[newName] = e
+		return true
+	})
+}
+
+// Forget removes a stopped session from the default manifest. Errors are
+// ignored: a stale entry only means gt resume --all offers the session back.
+func Forget(sessionName string) {
+	_ = ForgetSession(ManifestPath(), sessionName)
+}
+
+// updateManifest applies fn to the manifest under its lock and writes it
+// back if fn reports a change.
+func updateManifest(path string, fn func(*manifestFile) bool) error {
+	err := store.Update(path, func(m *manifestFile) (bool, error) {
+		if m.Sessions == nil {
+			m.Sessions = make(map[string]ManifestEntry)
+		}
+		return fn(m), nil
+	})
+	if err != nil {
+		return fmt.Errorf("updating session manifest: %w", err)
+	}
+	return nil
+}
+
+func readManifest(path string) (*manifestFile, error) {
+	m := &manifestFile{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		m.Sessions = make(map[string]ManifestEntry)
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading session manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parsing session manifest %s: %w", path, err)
+	}
+	if m.Sessions == nil {
+		m.Sessions = make(map[string]ManifestEntry)
+	}
+	return m, nil
+}
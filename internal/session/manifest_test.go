@@ -0,0 +1,147 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestManifestRecordAndForget(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "sessions.json")
+
+	entries, err := LoadManifest(path)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("LoadManifest(missing) = %v, %v; want empty", entries, err)
+	}
+
+	crew := ManifestEntry{Session: "gt-gastown-crew-max", Role: "crew", Rig: "gastown", Agent: "claude",
+		WorkDir: "/town/gastown/crew/max", TownRoot: "/town", AgentSessionID: "abc-123"}
+	mayor := ManifestEntry{Session: "hq-mayor", Role: "mayor", WorkDir: "/town/mayor", TownRoot: "/town", AgentSessionID: "def-456"}
+	for _, e := range []ManifestEntry{mayor, crew} {
+		if err := RecordSession(path, e); err != nil {
+			t.Fatalf("RecordSession(%s): %v", e.Session, err)
+		}
+	}
+
+	// Re-recording replaces the entry
+	crew.AgentSessionID = "abc-789"
+	if err := RecordSession(path, crew); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Session != "gt-gastown-crew-max" || entries[1].Session != "hq-mayor" {
+		t.Fatalf("LoadManifest() = %+v, want crew then mayor", entries)
+	}
+	if entries[0].AgentSessionID != "abc-789" || entries[0].UpdatedAt.IsZero() {
+		t.Errorf("crew entry = %+v, want the re-recorded session ID and a timestamp", entries[0])
+	}
+
+	if err := ForgetSession(path, "hq-mayor"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ForgetSession(path, "gt-never-recorded"); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ = LoadManifest(path)
+	if len(entries) != 1 || entries[0].Session != "gt-gastown-crew-max" {
+		t.Errorf("after forget, LoadManifest() = %+v", entries)
+	}
+}
+
+func TestForgetSessionMissingManifest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	if err := ForgetSession(filepath.Join(dir, "sessions.json"), "hq-mayor"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Error("ForgetSession created the state directory for a missing manifest")
+	}
+}
+
+func TestRecordSessionConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	names := []string{"gt-a-witness", "gt-a-refinery", "gt-b-witness", "gt-b-refinery", "hq-mayor", "hq-deacon"}
+
+	var wg sync.WaitGroup
+	for _, name := range names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			if err := RecordSession(path, ManifestEntry{Session: name}); err != nil {
+				t.Errorf("RecordSession(%s): %v", name, err)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	entries, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(names) {
+		t.Errorf("got %d entries, want %d: concurrent updates were lost", len(entries), len(names))
+	}
+}
+
+func TestManifestKeepsTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+
+	// Tagging an unrecorded session is a no-op
+	if err := SetSessionTags(path, "gt-gastown-crew-max", Tags{"team": "backend"}); err != nil {
+		t.Fatal(err)
+	}
+
+	e := ManifestEntry{Session: "gt-gastown-crew-max", Role: "crew", Rig: "gastown", AgentSessionID: "abc-123"}
+	if err := RecordSession(path, e); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetSessionTags(path, e.Session, Tags{"team": "backend"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-recording without tags (gt prime) keeps them
+	e.AgentSessionID = "abc-789"
+	if err := RecordSession(path, e); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := LoadManifest(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Tags["team"] != "backend" || entries[0].AgentSessionID != "abc-789" {
+		t.Errorf("LoadManifest() = %+v, want the new session ID with the tags kept", entries)
+	}
+}
+
+func TestHandOverSession(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	for _, e := range []ManifestEntry{
+		{Session: "hq-mayor", Role: "mayor", AgentSessionID: "old-mayor"},
+		{Session: "hq-mayor-standby", Role: "mayor", AgentSessionID: "standby"},
+	} {
+		if err := RecordSession(path, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := HandOverSession(path, "hq-mayor-standby", "hq-mayor"); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := LoadManifest(path)
+	if len(entries) != 1 || entries[0].Session != "hq-mayor" || entries[0].AgentSessionID != "standby" {
+		t.Fatalf("after hand-over, LoadManifest() = %+v, want hq-mayor running the standby's conversation", entries)
+	}
+
+	// An unrecorded old session drops the new one's stale entry
+	if err := HandOverSession(path, "hq-mayor-standby", "hq-mayor"); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := LoadManifest(path); len(entries) != 0 {
+		t.Errorf("LoadManifest() = %+v, want the stale hq-mayor entry dropped", entries)
+	}
+}
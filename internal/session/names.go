@@ -3,20 +3,46 @@ package session
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Prefix is the common prefix for rig-level Gas Town tmux sessions.
 const Prefix = "gt-"
 
+// LegacyPrefix is the original rig-level session prefix. When Prefix
+// differs from it, sessions started before the change are still found
+// through ResolveLegacyPrefix.
+const LegacyPrefix = "gt-"
+
 // HQPrefix is the prefix for town-level services (Mayor, Deacon).
 const HQPrefix = "hq-"
 
+// ResolveLegacyPrefix returns the session to use for name, a session name
+// under prefix. It is name itself unless only the LegacyPrefix form of it
+// exists, in which case that form is returned with legacy set, so callers can
+// keep using sessions created before a prefix change and flag them.
+func ResolveLegacyPrefix(name, prefix string, exists func(string) bool) (resolved string, legacy bool) {
+	if prefix == LegacyPrefix || !strings.HasPrefix(name, prefix) || exists(name) {
+		return name, false
+	}
+	if old := LegacyPrefix + strings.TrimPrefix(name, prefix); exists(old) {
+		return old, true
+	}
+	return name, false
+}
+
 // MayorSessionName returns the session name for the Mayor agent.
 // One mayor per machine - multi-town requires containers/VMs for isolation.
 func MayorSessionName() string {
 	return HQPrefix + "mayor"
 }
 
+// MayorStandbySessionName returns the session name for the standby Mayor
+// (gt mayor standby), which takes over if the Mayor's session is lost.
+func MayorStandbySessionName() string {
+	return HQPrefix + "mayor-standby"
+}
+
 // DeaconSessionName returns the session name for the Deacon agent.
 // One deacon per machine - multi-town requires containers/VMs for isolation.
 func DeaconSessionName() string {
@@ -106,3 +106,27 @@ func TestPrefix(t *testing.T) {
 		t.Errorf("Prefix = %q, want %q", Prefix, want)
 	}
 }
+
+func TestResolveLegacyPrefix(t *testing.T) {
+	live := map[string]bool{"gt-gastown-witness": true, "acme-gastown-refinery": true, "gt-gastown-refinery": true}
+	exists := func(s string) bool { return live[s] }
+
+	tests := []struct {
+		name       string
+		prefix     string
+		want       string
+		wantLegacy bool
+	}{
+		{"acme-gastown-witness", "acme-", "gt-gastown-witness", true},      // only the legacy session exists
+		{"acme-gastown-refinery", "acme-", "acme-gastown-refinery", false}, // the new one wins
+		{"acme-gastown-crew-max", "acme-", "acme-gastown-crew-max", false}, // neither exists
+		{"hq-mayor", "acme-", "hq-mayor", false},                           // not under prefix
+		{"gt-gastown-crew-max", LegacyPrefix, "gt-gastown-crew-max", false},
+	}
+	for _, tt := range tests {
+		got, legacy := ResolveLegacyPrefix(tt.name, tt.prefix, exists)
+		if got != tt.want || legacy != tt.wantLegacy {
+			t.Errorf("ResolveLegacyPrefix(%q, %q) = %q, %v; want %q, %v", tt.name, tt.prefix, got, legacy, tt.want, tt.wantLegacy)
+		}
+	}
+}
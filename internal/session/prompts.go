@@ -0,0 +1,88 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// PromptsDir holds startup prompt templates, in the town root and in each
+// rig: <role>.tmpl for one role, default.tmpl for every role.
+const PromptsDir = ".gastown/prompts"
+
+// PromptData is what a startup prompt template is rendered with.
+//
+// Example template (.gastown/prompts/crew.tmpl):
+//
+//	You are {{.Name}}, crew on {{.Rig}} running {{.Agent}}.
+//	{{if .Bead}}Work on {{.Bead}} now.{{else}}{{.Default}}{{end}}
+type PromptData struct {
+	Town    string // town root
+	Rig     string // rig name (empty for mayor/deacon)
+	Role    string // mayor, deacon, witness, refinery, crew, polecat
+	Name    string // crew/polecat name
+	Address string // e.g., "gastown/crew/max"
+	Agent   string // agent preset, e.g., "claude" or "kimi"
+	Bead    string // bead on the agent's hook, if any
+	Topic   string // why the session started, e.g., "handoff"
+	Default string // the prompt sent without a template (priming_prompt)
+}
+
+// PromptTemplatePath returns the template for role in rig, most specific
+// first: the rig's <role>.tmpl, the town's <role>.tmpl, the rig's
+// default.tmpl, then the town's default.tmpl. Returns "" if there is none.
+func PromptTemplatePath(townRoot, rig, role string) string {
+	var dirs []string
+	if rig != "" {
+		dirs = append(dirs, filepath.Join(townRoot, rig, PromptsDir))
+	}
+	dirs = append(dirs, filepath.Join(townRoot, PromptsDir))
+
+	for _, file := range []string{role + ".tmpl", "default.tmpl"} {
+		if file == ".tmpl" {
+			continue // No role
+		}
+		for _, dir := range dirs {
+			path := filepath.Join(dir, file)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+// RenderPromptTemplate renders the template at path with data. Unknown
+// fields are errors, so a typo doesn't silently send an empty prompt.
+func RenderPromptTemplate(path string, data PromptData) (string, error) {
+	text, err := os.ReadFile(path) //nolint:gosec // G304: path is from PromptTemplatePath
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return "", fmt.Errorf("parsing %s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering %s: %w", path, err)
+	}
+	return string(bytes.TrimSpace(buf.Bytes())), nil
+}
+
+// RenderStartupPrompt returns the startup prompt for the agent described by
+// data: its template rendered, or data.Default if it has none. The path of
+// the template used is returned too, empty for the default.
+func RenderStartupPrompt(data PromptData) (string, string, error) {
+	path := PromptTemplatePath(data.Town, data.Rig, data.Role)
+	if path == "" {
+		return data.Default, "", nil
+	}
+	prompt, err := RenderPromptTemplate(path, data)
+	if err != nil {
+		return "", path, err
+	}
+	return prompt, path, nil
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePrompt(t *testing.T, dir, file, text string) string {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPromptTemplatePath(t *testing.T) {
+	town := t.TempDir()
+	townDir := filepath.Join(town, PromptsDir)
+	rigDir := filepath.Join(town, "gastown", PromptsDir)
+
+	if got := PromptTemplatePath(town, "gastown", "crew"); got != "" {
+		t.Fatalf("no templates: got %q, want \"\"", got)
+	}
+
+	townDefault := writePrompt(t, townDir, "default.tmpl", "town default")
+	if got := PromptTemplatePath(town, "gastown", "crew"); got != townDefault {
+		t.Errorf("got %q, want town default %q", got, townDefault)
+	}
+	rigDefault := writePrompt(t, rigDir, "default.tmpl", "rig default")
+	if got := PromptTemplatePath(town, "gastown", "crew"); got != rigDefault {
+		t.Errorf("got %q, want rig default %q", got, rigDefault)
+	}
+	townCrew := writePrompt(t, townDir, "crew.tmpl", "town crew")
+	if got := PromptTemplatePath(town, "gastown", "crew"); got != townCrew {
+		t.Errorf("got %q, want town role template %q", got, townCrew)
+	}
+	rigCrew := writePrompt(t, rigDir, "crew.tmpl", "rig crew")
+	if got := PromptTemplatePath(town, "gastown", "crew"); got != rigCrew {
+		t.Errorf("got %q, want rig role template %q", got, rigCrew)
+	}
+
+	// Town-level roles only see the town's templates
+	if got := PromptTemplatePath(town, "", "mayor"); got != townDefault {
+		t.Errorf("mayor: got %q, want town default %q", got, townDefault)
+	}
+	// Another rig falls back to the town
+	if got := PromptTemplatePath(town, "beads", "crew"); got != townCrew {
+		t.Errorf("other rig: got %q, want town role template %q", got, townCrew)
+	}
+}
+
+func TestRenderStartupPrompt(t *testing.T) {
+	town := t.TempDir()
+	data := PromptData{
+		Town:    town,
+		Rig:     "gastown",
+		Role:    "crew",
+		Name:    "max",
+		Agent:   "kimi",
+		Bead:    "gt-abc12",
+		Default: "Check your hook.",
+	}
+
+	prompt, path, err := RenderStartupPrompt(data)
+	if err != nil || path != "" || prompt != data.Default {
+		t.Fatalf("no template: got (%q, %q, %v), want the default", prompt, path, err)
+	}
+
+	want := writePrompt(t, filepath.Join(town, "gastown", PromptsDir), "crew.tmpl",
+		"{{.Name}} on {{.Rig}} ({{.Agent}}): {{if .Bead}}work on {{.Bead}}{{else}}{{.Default}}{{end}}\n")
+	prompt, path, err = RenderStartupPrompt(data)
+	if err != nil {
+		t.Fatalf("RenderStartupPrompt() error = %v", err)
+	}
+	if path != want {
+		t.Errorf("path = %q, want %q", path, want)
+	}
+	if prompt != "max on gastown (kimi): work on gt-abc12" {
+		t.Errorf("prompt = %q", prompt)
+	}
+
+	data.Bead = ""
+	if prompt, _, _ := RenderStartupPrompt(data); prompt != "max on gastown (kimi): Check your hook." {
+		t.Errorf("without bead: prompt = %q", prompt)
+	}
+
+	writePrompt(t, filepath.Join(town, "gastown", PromptsDir), "crew.tmpl", "{{.Beed}}")
+	if _, _, err := RenderStartupPrompt(data); err == nil || !strings.Contains(err.Error(), "crew.tmpl") {
+		t.Errorf("unknown field: err = %v, want an error naming the template", err)
+	}
+}
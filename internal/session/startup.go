@@ -3,6 +3,7 @@ package session
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -35,6 +36,10 @@ type BeaconConfig struct {
 	// Used for non-hook agents where gt prime must complete first.
 	// Default (false) preserves backward compatible behavior.
 	ExcludeWorkInstructions bool
+
+	// Context is the predecessor's summary, carried over by
+	// gt handoff --with-context. It follows the beacon line verbatim.
+	Context string
 }
 
 // FormatStartupBeacon builds the formatted startup beacon message.
@@ -65,6 +70,10 @@ func FormatStartupBeacon(cfg BeaconConfig) string {
 	beacon := fmt.Sprintf("[GAS TOWN] %s <- %s • %s • %s",
 		cfg.Recipient, cfg.Sender, timestamp, topic)
 
+	if context := strings.TrimSpace(cfg.Context); context != "" {
+		beacon += "\n\n" + context
+	}
+
 	// For non-hook agents, add "Run gt prime" instruction since there's no
 	// SessionStart hook to do it automatically. Work instructions will
 	// come as a separate nudge after gt prime completes.
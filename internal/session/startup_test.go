@@ -122,6 +122,18 @@ func TestFormatStartupBeacon(t *testing.T) {
 				"gt prime",
 			},
 		},
+		{
+			name: "handoff with context",
+			cfg: BeaconConfig{
+				Recipient: "gastown/crew/max",
+				Sender:    "self",
+				Topic:     "handoff",
+				Context:   "  Predecessor summary:\nRefactored the parser; tests pass.\n",
+			},
+			wantSub: []string{
+				"• handoff\n\nPredecessor summary:\nRefactored the parser; tests pass.\n\nCheck your hook and mail",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,95 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Tags are a session's key=value labels (gt tag), for grouping sessions
+// beyond their names, e.g. team=backend.
+type Tags map[string]string
+
+// ParseTag parses a key=value tag. Keys are letters, digits, '-', '_' and
+// '.'; the value may be empty.
+func ParseTag(s string) (string, string, error) {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return "", "", fmt.Errorf("invalid tag %q: want key=value", s)
+	}
+	if err := validateTagKey(key); err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+// validateTagKey rejects keys that can't name a tmux user option.
+func validateTagKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("tag key is empty")
+	}
+	for _, r := range key {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return fmt.Errorf("invalid tag key %q: use letters, digits, '-', '_' and '.'", key)
+		}
+	}
+	return nil
+}
+
+// String formats the tags as sorted key=value pairs.
+func (t Tags) String() string {
+	keys := make([]string, 0, len(t))
+	for k := range t {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + t[k]
+	}
+	return strings.Join(parts, " ")
+}
+
+// TagFilter selects sessions by tag: key=value, key!=value, or a bare key
+// for any session that has the tag.
+type TagFilter struct {
+	Key    string
+	Value  string
+	Negate bool // key!=value
+	Exists bool // bare key
+}
+
+// ParseTagFilter parses a --filter value.
+func ParseTagFilter(s string) (TagFilter, error) {
+	if key, value, ok := strings.Cut(s, "!="); ok {
+		return TagFilter{Key: key, Value: value, Negate: true}, validateTagKey(key)
+	}
+	if key, value, ok := strings.Cut(s, "="); ok {
+		return TagFilter{Key: key, Value: value}, validateTagKey(key)
+	}
+	return TagFilter{Key: s, Exists: true}, validateTagKey(s)
+}
+
+// Match reports whether tags pass the filter. A session without the tag
+// passes key!=value.
+func (f TagFilter) Match(tags Tags) bool {
+	value, ok := tags[f.Key]
+	switch {
+	case f.Exists:
+		return ok
+	case f.Negate:
+		return !ok || value != f.Value
+	default:
+		return ok && value == f.Value
+	}
+}
+
+// MatchTags reports whether tags pass every filter.
+func MatchTags(tags Tags, filters []TagFilter) bool {
+	for _, f := range filters {
+		if !f.Match(tags) {
+			return false
+		}
+	}
+	return true
+}
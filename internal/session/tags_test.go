@@ -0,0 +1,54 @@
+package session
+
+import "testing"
+
+func TestParseTag(t *testing.T) {
+	key, value, err := ParseTag("team=backend")
+	if err != nil || key != "team" || value != "backend" {
+		t.Errorf("ParseTag(team=backend) = (%q, %q, %v)", key, value, err)
+	}
+	if _, value, err := ParseTag("note="); err != nil || value != "" {
+		t.Errorf("ParseTag(note=) = (%q, %v), want an empty value", value, err)
+	}
+	for _, bad := range []string{"team", "=backend", "te am=x", "team:x=y"} {
+		if _, _, err := ParseTag(bad); err == nil {
+			t.Errorf("ParseTag(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestTagFilters(t *testing.T) {
+	tags := Tags{"team": "backend", "tier": "1"}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"team=backend", true},
+		{"team=frontend", false},
+		{"team!=frontend", true},
+		{"team!=backend", false},
+		{"oncall!=yes", true}, // Untagged passes !=
+		{"tier", true},
+		{"oncall", false},
+	}
+	for _, tt := range tests {
+		f, err := ParseTagFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseTagFilter(%q): %v", tt.filter, err)
+		}
+		if got := f.Match(tags); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	all := []TagFilter{{Key: "team", Value: "backend"}, {Key: "tier", Exists: true}}
+	if !MatchTags(tags, all) || MatchTags(Tags{"team": "backend"}, all) {
+		t.Errorf("MatchTags() must require every filter")
+	}
+	if _, err := ParseTagFilter("bad key=x"); err == nil {
+		t.Errorf("ParseTagFilter(bad key=x) succeeded, want an error")
+	}
+	if got := tags.String(); got != "team=backend tier=1" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/steveyegge/gastown/internal/events"
+	"github.com/steveyegge/gastown/internal/hooks"
 	"github.com/steveyegge/gastown/internal/tmux"
 )
 
@@ -66,12 +67,14 @@ func stopTownSessionInternal(t *tmux.Tmux, ts TownSession, force bool) (bool, er
 	}
 	_ = events.LogFeed(events.TypeSessionDeath, ts.SessionID,
 		events.SessionDeathPayload(ts.SessionID, ts.Name, reason, "gt down"))
+	hooks.Fire("", hooks.SessionEnd, map[string]string{"session": ts.SessionID, "agent": ts.Name, "reason": reason})
 
 	// Kill the session.
 	// Use KillSessionWithProcesses to ensure all descendant processes are killed.
 	if err := t.KillSessionWithProcesses(ts.SessionID); err != nil {
 		return false, fmt.Errorf("killing %s session: %w", ts.Name, err)
 	}
+	Forget(ts.SessionID)
 
 	return true, nil
 }
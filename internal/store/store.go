@@ -0,0 +1,161 @@
+// Package store reads and writes the JSON state files that gt commands in
+// different sessions share: session manifests, spawn queues, handoff
+// schedules, the bead lifecycle log.
+//
+// Writers take an advisory lock on a sidecar <file>.lock (flock on Unix,
+// LockFileEx on Windows), so a read-modify-write by one process can't lose
+// another's update. Writes go to a temporary file of their own in the same
+// directory, synced and renamed over the file, so readers, locked or not,
+// always see one whole version and a crash mid-write leaves the old one.
+//
+//	err := store.Update(path, func(m *map[string]Entry) (bool, error) {
+//		(*m)[name] = e
+//		return true, nil
+//	})
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofrs/flock"
+)
+
+// ErrLockTimeout is returned when a file's lock isn't free in time.
+var ErrLockTimeout = errors.New("timeout waiting for lock")
+
+// DefaultLockTimeout bounds WithLock's and Update's wait for a file's
+// lock. State updates hold it for milliseconds, so waiting longer means a
+// stuck process.
+const DefaultLockTimeout = 5 * time.Second
+
+// lockRetryDelay is how often a busy lock is retried.
+const lockRetryDelay = 50 * time.Millisecond
+
+// renameAttempts and renameRetryDelay bound the retries of a failed
+// rename: Windows refuses to replace a file another process has open.
+const (
+	renameAttempts   = 5
+	renameRetryDelay = 20 * time.Millisecond
+)
+
+// LockPath returns the lock file guarding path.
+func LockPath(path string) string {
+	return path + ".lock"
+}
+
+// Lock takes the exclusive lock on path, retrying for up to timeout, and
+// returns the func that releases it.
+func Lock(path string, timeout time.Duration) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	lock := flock.New(LockPath(path))
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	locked, err := lock.TryLockContext(ctx, lockRetryDelay)
+	if !locked {
+		if err == nil || errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %s", ErrLockTimeout, filepath.Base(path))
+		}
+		return nil, fmt.Errorf("locking %s: %w", filepath.Base(path), err)
+	}
+	return func() { _ = lock.Unlock() }, nil
+}
+
+// WithLock runs fn holding the lock on path.
+func WithLock(path string, fn func() error) error {
+	unlock, err := Lock(path, DefaultLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return fn()
+}
+
+// Read decodes the JSON file at path into v, reporting whether it exists.
+// A missing file leaves v untouched.
+func Read(path string, v any) (bool, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // G304: state paths are constructed internally
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// Write writes v to path as indented JSON, atomically.
+func Write(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFile(path, data, 0644)
+}
+
+// WriteFile writes data to path atomically: to a temporary file of its own
+// in the same directory, synced, then renamed over path. Concurrent writers
+// never interleave; the last rename wins.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if err := writeTemp(tmp, data, perm); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	for attempt := 1; ; attempt++ {
+		err = os.Rename(tmp.Name(), path)
+		if err == nil {
+			return nil
+		}
+		if attempt == renameAttempts {
+			_ = os.Remove(tmp.Name())
+			return err
+		}
+		time.Sleep(renameRetryDelay)
+	}
+}
+
+// writeTemp writes data to f with perm, syncs and closes it.
+func writeTemp(f *os.File, data []byte, perm os.FileMode) error {
+	_, err := f.Write(data)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Update applies fn to the JSON state at path under its lock: the file is
+// decoded into a new T (the zero T if it doesn't exist yet), and written
+// back if fn reports a change. Errors from fn are returned as is.
+func Update[T any](path string, fn func(*T) (bool, error)) error {
+	return WithLock(path, func() error {
+		var v T
+		if _, err := Read(path, &v); err != nil {
+			return err
+		}
+		changed, err := fn(&v)
+		if err != nil || !changed {
+			return err
+		}
+		return Write(path, &v)
+	})
+}
@@ -0,0 +1,131 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "counts.json")
+
+	// Separate flock handles conflict like separate processes do
+	const writers, rounds = 8, 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*rounds)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			for j := 0; j < rounds; j++ {
+				errs <- Update(path, func(m *map[string]int) (bool, error) {
+					if *m == nil {
+						*m = make(map[string]int)
+					}
+					(*m)["total"]++
+					(*m)[fmt.Sprintf("writer-%d", n)]++
+					return true, nil
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+	}
+
+	var m map[string]int
+	if ok, err := Read(path, &m); !ok || err != nil {
+		t.Fatalf("Read = %v, %v", ok, err)
+	}
+	if m["total"] != writers*rounds {
+		t.Errorf("total = %d, want %d: updates were lost", m["total"], writers*rounds)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temp file left behind: %s", e.Name())
+		}
+	}
+}
+
+func TestUpdateUnchangedOrFailedSkipsWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+
+	if err := Update(path, func(v *[]string) (bool, error) { return false, nil }); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("unchanged update wrote %s", path)
+	}
+
+	boom := errors.New("boom")
+	err := Update(path, func(v *[]string) (bool, error) {
+		*v = append(*v, "x")
+		return true, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Errorf("Update error = %v, want fn's error", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("failed update wrote %s", path)
+	}
+}
+
+func TestReadMissingAndCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	v := []string{"keep"}
+	if ok, err := Read(filepath.Join(dir, "missing.json"), &v); ok || err != nil || len(v) != 1 {
+		t.Errorf("Read(missing) = %v, %v (v=%v); want false, nil, v untouched", ok, err, v)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(corrupt, &v); err == nil {
+		t.Error("Read(corrupt) = nil error, want a parse error")
+	}
+	if err := Update(corrupt, func(v *[]string) (bool, error) { return true, nil }); err == nil {
+		t.Error("Update(corrupt) = nil error, want the parse error instead of overwriting")
+	}
+}
+
+func TestLockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	unlock, err := Lock(path, time.Second)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	if _, err := Lock(path, 100*time.Millisecond); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("second Lock error = %v, want ErrLockTimeout", err)
+	}
+	unlock()
+	again, err := Lock(path, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Lock after unlock: %v", err)
+	}
+	again()
+}
+
+func TestWriteFilePerm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secret.json")
+	if err := WriteFile(path, []byte("{}"), 0600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm&0077 != 0 && os.PathSeparator == '/' {
+		t.Errorf("perm = %o, want 0600", perm)
+	}
+}
@@ -4,6 +4,8 @@ package style
 
 import (
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/steveyegge/gastown/internal/ui"
@@ -53,6 +55,11 @@ var (
 // PrintWarning prints a warning message with consistent formatting.
 // The format and args work like fmt.Printf.
 func PrintWarning(format string, args ...interface{}) {
+	FprintWarning(os.Stdout, format, args...)
+}
+
+// FprintWarning is PrintWarning writing to w.
+func FprintWarning(w io.Writer, format string, args ...interface{}) {
 	msg := fmt.Sprintf(format, args...)
-	fmt.Printf("%s %s\n", Warning.Render(ui.IconWarn+" Warning:"), msg)
+	fmt.Fprintf(w, "%s %s\n", Warning.Render(ui.IconWarn+" Warning:"), msg)
 }
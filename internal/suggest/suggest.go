@@ -83,6 +83,26 @@ func FindSimilar(target string, candidates []string, maxResults int) []string {
 	return result
 }
 
+// Closest returns the candidate with the smallest edit distance to target,
+// if that distance is at most maxDist and shorter than target itself
+// (so very short inputs don't match everything). Ties go to the earlier
+// candidate. Comparison is case-insensitive.
+func Closest(target string, candidates []string, maxDist int) (string, bool) {
+	target = strings.ToLower(target)
+
+	best, bestDist := "", -1
+	for _, c := range candidates {
+		dist := levenshteinDistance(target, strings.ToLower(c))
+		if dist > maxDist || dist >= len(target) {
+			continue
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = c, dist
+		}
+	}
+	return best, bestDist >= 0
+}
+
 // similarity calculates a similarity score between two strings.
 // Higher is more similar. Uses a combination of techniques:
 // - Prefix matching (high weight)
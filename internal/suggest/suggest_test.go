@@ -99,6 +99,30 @@ func TestLevenshteinDistance(t *testing.T) {
 	}
 }
 
+func TestClosest(t *testing.T) {
+	candidates := []string{"mayor", "deacon", "witness"}
+	tests := []struct {
+		target string
+		want   string
+		wantOK bool
+	}{
+		{"mayr", "mayor", true},
+		{"DEACN", "deacon", true},
+		{"witnes", "witness", true},
+		{"polecat", "", false},
+		{"m", "", false}, // too short to be a confident typo
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			got, ok := Closest(tt.target, candidates, 2)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Closest(%q) = %q, %v; want %q, %v", tt.target, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
 func TestFormatSuggestion(t *testing.T) {
 	msg := FormatSuggestion("Polecat", "Tosat", []string{"Toast", "Ghost"}, "Create with: gt polecat add Tosat")
 
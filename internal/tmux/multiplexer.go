@@ -0,0 +1,58 @@
+package tmux
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Multiplexer is the session management Gas Town needs from a terminal
+// multiplexer. *Tmux implements it, as does *Zellij for users who run
+// zellij instead of tmux and *WindowsTerminal for Windows without WSL.
+type Multiplexer interface {
+	// NewSessionWithCommand creates a detached session running command in workDir.
+	NewSessionWithCommand(name, workDir, command string) error
+	HasSession(name string) (bool, error)
+	ListSessions() ([]string, error)
+	KillSession(name string) error
+	// RespawnPane replaces the process in pane with command.
+	RespawnPane(pane, command string) error
+	// SendKeys types keys into the session and presses Enter.
+	SendKeys(session, keys string) error
+	// AttachSession takes over this terminal until the user detaches.
+	AttachSession(session string) error
+}
+
+var (
+	_ Multiplexer = (*Tmux)(nil)
+	_ Multiplexer = (*Zellij)(nil)
+	_ Multiplexer = (*WindowsTerminal)(nil)
+)
+
+// EnvMultiplexer selects the multiplexer backend: "tmux" (the default),
+// "zellij" or "wt" (Windows Terminal).
+const EnvMultiplexer = "GT_MULTIPLEXER"
+
+// NewMultiplexer returns the backend selected by GT_MULTIPLEXER, configured
+// with opts. An unknown backend is an error rather than a silent tmux fallback.
+// Unset, it is tmux, except on Windows without tmux on PATH.
+func NewMultiplexer(opts ...Option) (Multiplexer, error) {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv(EnvMultiplexer)))
+	if backend == "" && runtime.GOOS == "windows" {
+		if _, err := exec.LookPath("tmux"); err != nil {
+			backend = "wt"
+		}
+	}
+	switch backend {
+	case "", "tmux":
+		return NewTmux(opts...), nil
+	case "zellij":
+		return NewZellij(opts...), nil
+	case "wt", "windows-terminal":
+		return NewWindowsTerminal(opts...), nil
+	default:
+		return nil, fmt.Errorf("unknown %s %q (want tmux, zellij or wt)", EnvMultiplexer, backend)
+	}
+}
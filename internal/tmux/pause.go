@@ -0,0 +1,29 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PausedEnv is the session environment variable set while a session is
+// paused (gt crew pause), to when it was paused.
+const PausedEnv = "GT_PAUSED"
+
+// ErrSessionPaused is returned by NudgeSession and NudgePane for a paused
+// session: nudges would wake the agent.
+var ErrSessionPaused = errors.New("session is paused")
+
+// InterruptSession interrupts what the agent in a session is doing, as
+// Escape does in the agent's prompt, leaving it idle with its context.
+func (t *Tmux) InterruptSession(session string) error {
+	_, err := t.run("send-keys", "-t", session, "Escape")
+	return err
+}
+
+// checkNotPaused fails with ErrSessionPaused if target's session is paused.
+func (t *Tmux) checkNotPaused(target string) error {
+	if paused, _ := t.GetEnvironment(target, PausedEnv); paused != "" {
+		return fmt.Errorf("%w since %s: %s (gt crew resume continues it)", ErrSessionPaused, paused, target)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package tmux
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// Remote is the SSH host a rig's sessions run on (config.RigEntry.Host).
+// Every tmux command of a Tmux with a Remote runs on the host over ssh, and
+// attaching goes through ssh -t.
+type Remote struct {
+	// Host is the ssh destination: user@host or a ~/.ssh/config alias.
+	Host string
+
+	// LocalRoot and RemoteRoot map working directories between the two
+	// machines: a path under LocalRoot is passed to tmux as the same path
+	// under RemoteRoot, and pane paths come back the other way. Unset, paths
+	// are the same on both.
+	LocalRoot  string
+	RemoteRoot string
+}
+
+// WithRemote runs tmux on r's host. A Runner set with WithRunner still takes
+// precedence, for tests.
+func WithRemote(r Remote) Option {
+	return func(t *Tmux) {
+		if r.Host != "" {
+			t.remote = &r
+		}
+	}
+}
+
+// Remote returns the host the wrapper runs tmux on, or nil for this machine.
+func (t *Tmux) Remote() *Remote {
+	return t.remote
+}
+
+// ForRig returns a Tmux for the sessions of rigName in the town at townRoot:
+// on the rig's host if it has one in mayor/rigs.json, here otherwise.
+func ForRig(townRoot, rigName string, opts ...Option) *Tmux {
+	if r, ok := RigRemote(townRoot, rigName); ok {
+		opts = append(opts, WithRemote(r))
+	}
+	return NewTmux(opts...)
+}
+
+// RigRemote returns the SSH host of rigName in the town at townRoot, if the
+// rig is remote.
+func RigRemote(townRoot, rigName string) (Remote, bool) {
+	if townRoot == "" || rigName == "" {
+		return Remote{}, false
+	}
+	rigs, err := config.LoadRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"))
+	if err != nil {
+		return Remote{}, false
+	}
+	entry, ok := rigs.Rigs[rigName]
+	if !ok || entry.Host == "" {
+		return Remote{}, false
+	}
+	return Remote{
+		Host:       entry.Host,
+		LocalRoot:  filepath.Join(townRoot, rigName),
+		RemoteRoot: entry.RemotePath,
+	}, true
+}
+
+// RemotePath maps a local path to the host.
+func (r *Remote) RemotePath(local string) string {
+	return swapRoot(local, r.LocalRoot, r.RemoteRoot)
+}
+
+// LocalPath maps a path on the host back to this machine.
+func (r *Remote) LocalPath(remote string) string {
+	return swapRoot(remote, r.RemoteRoot, r.LocalRoot)
+}
+
+// swapRoot replaces the from prefix of path with to.
+func swapRoot(path, from, to string) string {
+	if from == "" || to == "" || path == "" {
+		return path
+	}
+	if path == from {
+		return to
+	}
+	if rest, ok := strings.CutPrefix(path, strings.TrimSuffix(from, "/")+"/"); ok {
+		return strings.TrimSuffix(to, "/") + "/" + rest
+	}
+	return path
+}
+
+// SSHArgs returns the ssh arguments that run command on the host. Each word
+// of command is shell-quoted, since ssh hands the joined words to the remote
+// shell. tty allocates a terminal, for interactive commands.
+func (r *Remote) SSHArgs(tty bool, command ...string) []string {
+	args := []string{"-o", "BatchMode=yes"}
+	if tty {
+		args = []string{"-t"}
+	}
+	args = append(args, r.Host)
+	for _, word := range command {
+		if word == "" {
+			args = append(args, "''") // Would vanish from the joined command
+			continue
+		}
+		args = append(args, config.ShellQuote(word))
+	}
+	return args
+}
+
+// output runs command on the host and returns its stdout and stderr.
+func (r *Remote) output(command ...string) (string, string, error) {
+	cmd := exec.Command("ssh", r.SSHArgs(false, command...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+// runner runs tmux on the host.
+func (r *Remote) runner(args ...string) (string, string, error) {
+	return r.output(append([]string{"tmux"}, args...)...)
+}
+
+// interactive runs tmux on the host on this process's terminal.
+func (r *Remote) interactive(args ...string) error {
+	cmd := exec.Command("ssh", r.SSHArgs(true, append([]string{"tmux"}, args...)...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// remotePath and localPath map working directories for a remote wrapper
+// and return them unchanged otherwise.
+func (t *Tmux) remotePath(path string) string {
+	if t.remote == nil {
+		return path
+	}
+	return t.remote.RemotePath(path)
+}
+
+func (t *Tmux) localPath(path string) string {
+	if t.remote == nil {
+		return path
+	}
+	return t.remote.LocalPath(path)
+}
@@ -0,0 +1,110 @@
+package tmux
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+func TestSwapRoot(t *testing.T) {
+	tests := []struct {
+		path, from, to, want string
+	}{
+		{"/town/gastown/crew/max", "/town/gastown", "/srv/gastown", "/srv/gastown/crew/max"},
+		{"/town/gastown", "/town/gastown", "/srv/gastown", "/srv/gastown"},
+		{"/town/gastown/", "/town/gastown/", "/srv/gastown/", "/srv/gastown/"},
+		{"/town/gastown-other", "/town/gastown", "/srv/gastown", "/town/gastown-other"},
+		{"/elsewhere", "/town/gastown", "/srv/gastown", "/elsewhere"},
+		{"/town/gastown/crew", "/town/gastown", "", "/town/gastown/crew"},
+		{"", "/town/gastown", "/srv/gastown", ""},
+	}
+	for _, tt := range tests {
+		if got := swapRoot(tt.path, tt.from, tt.to); got != tt.want {
+			t.Errorf("swapRoot(%q, %q, %q) = %q, want %q", tt.path, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestRemoteSSHArgs(t *testing.T) {
+	r := &Remote{Host: "me@build-box"}
+
+	got := r.SSHArgs(false, "tmux", "list-panes", "-t", "gt-gastown-crew-max", "-F", "#{pane_id}", "")
+	want := []string{"-o", "BatchMode=yes", "me@build-box", "tmux", "list-panes", "-t", "gt-gastown-crew-max", "-F", "'#{pane_id}'", "''"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SSHArgs = %q, want %q", got, want)
+	}
+
+	got = r.SSHArgs(true, "tmux", "attach-session", "-t", "gt-gastown-crew-max")
+	want = []string{"-t", "me@build-box", "tmux", "attach-session", "-t", "gt-gastown-crew-max"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SSHArgs(tty) = %q, want %q", got, want)
+	}
+}
+
+func TestRemoteMapsWorkDirs(t *testing.T) {
+	var ran [][]string
+	tm := NewTmux(
+		WithRunner(func(args ...string) (string, string, error) {
+			if args[0] == "-V" {
+				return "tmux 3.3a\n", "", nil
+			}
+			ran = append(ran, args)
+			return "/srv/gastown/crew/max\n", "", nil
+		}),
+		WithRemote(Remote{Host: "build-box", LocalRoot: "/town/gastown", RemoteRoot: "/srv/gastown"}),
+	)
+
+	if err := tm.NewSession("gt-gastown-crew-max", "/town/gastown/crew/max"); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	if got := strings.Join(ran[0], " "); !strings.Contains(got, "-c /srv/gastown/crew/max") {
+		t.Errorf("new-session args = %q, want the remote work dir", got)
+	}
+
+	dir, err := tm.GetPaneWorkDir("gt-gastown-crew-max")
+	if err != nil {
+		t.Fatalf("GetPaneWorkDir: %v", err)
+	}
+	if dir != "/town/gastown/crew/max" {
+		t.Errorf("GetPaneWorkDir = %q, want the local path", dir)
+	}
+}
+
+func TestRigRemote(t *testing.T) {
+	townRoot := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(townRoot, "mayor"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rigs := &config.RigsConfig{
+		Version: 1,
+		Rigs: map[string]config.RigEntry{
+			"gastown": {GitURL: "https://example.com/gastown.git", Host: "build-box", RemotePath: "/srv/gastown"},
+			"beads":   {GitURL: "https://example.com/beads.git"},
+		},
+	}
+	if err := config.SaveRigsConfig(filepath.Join(townRoot, "mayor", "rigs.json"), rigs); err != nil {
+		t.Fatal(err)
+	}
+
+	r, ok := RigRemote(townRoot, "gastown")
+	if !ok {
+		t.Fatal("RigRemote(gastown) = false, want true")
+	}
+	want := Remote{Host: "build-box", LocalRoot: filepath.Join(townRoot, "gastown"), RemoteRoot: "/srv/gastown"}
+	if r != want {
+		t.Errorf("RigRemote(gastown) = %+v, want %+v", r, want)
+	}
+	if _, ok := RigRemote(townRoot, "beads"); ok {
+		t.Error("RigRemote(beads) = true for a local rig")
+	}
+	if ForRig(townRoot, "beads").Remote() != nil {
+		t.Error("ForRig(beads) is remote")
+	}
+	if got := ForRig(townRoot, "gastown").Remote(); got == nil || got.Host != "build-box" {
+		t.Errorf("ForRig(gastown).Remote() = %+v, want build-box", got)
+	}
+}
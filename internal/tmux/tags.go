@@ -0,0 +1,49 @@
+package tmux
+
+import (
+	"strconv"
+	"strings"
+)
+
+// tagOptionPrefix prefixes the tmux user options holding a session's tags
+// (gt tag): tag team=backend is the session option @gt-tag-team.
+const tagOptionPrefix = "@gt-tag-"
+
+// SetSessionTag sets tag key of session to value.
+func (t *Tmux) SetSessionTag(session, key, value string) error {
+	_, err := t.runMutating("set-option", "-t", session, tagOptionPrefix+key, value)
+	return err
+}
+
+// UnsetSessionTag removes tag key from session.
+func (t *Tmux) UnsetSessionTag(session, key string) error {
+	_, err := t.runMutating("set-option", "-u", "-t", session, tagOptionPrefix+key)
+	return err
+}
+
+// GetSessionTags returns the tags of session.
+func (t *Tmux) GetSessionTags(session string) (map[string]string, error) {
+	out, err := t.run("show-options", "-t", session)
+	if err != nil {
+		return nil, err
+	}
+	return parseTagOptions(out), nil
+}
+
+// parseTagOptions picks the tags out of show-options output, where each
+// line is "<option> <value>" and values with spaces are double-quoted.
+func parseTagOptions(out string) map[string]string {
+	tags := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), tagOptionPrefix)
+		if !ok {
+			continue
+		}
+		key, value, _ := strings.Cut(rest, " ")
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		tags[key] = value
+	}
+	return tags
+}
@@ -0,0 +1,59 @@
+package tmux
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSessionTags(t *testing.T) {
+	options := map[string]string{"@other": "x"}
+	var calls []string
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		calls = append(calls, strings.Join(args, " "))
+		switch args[0] {
+		case "set-option":
+			if args[1] == "-u" {
+				delete(options, args[4])
+			} else {
+				options[args[3]] = args[4]
+			}
+		case "show-options":
+			var out strings.Builder
+			for k, v := range options {
+				if strings.Contains(v, " ") {
+					v = `"` + v + `"`
+				}
+				out.WriteString(k + " " + v + "\n")
+			}
+			return out.String(), "", nil
+		}
+		return "", "", nil
+	}))
+
+	if err := tm.SetSessionTag("gt-gastown-crew-max", "team", "backend"); err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.SetSessionTag("gt-gastown-crew-max", "note", "on call"); err != nil {
+		t.Fatal(err)
+	}
+	if calls[0] != "set-option -t gt-gastown-crew-max @gt-tag-team backend" {
+		t.Errorf("set call = %q", calls[0])
+	}
+
+	tags, err := tm.GetSessionTags("gt-gastown-crew-max")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"team": "backend", "note": "on call"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("GetSessionTags() = %v, want %v", tags, want)
+	}
+
+	if err := tm.UnsetSessionTag("gt-gastown-crew-max", "note"); err != nil {
+		t.Fatal(err)
+	}
+	tags, _ = tm.GetSessionTags("gt-gastown-crew-max")
+	if want := map[string]string{"team": "backend"}; !reflect.DeepEqual(tags, want) {
+		t.Errorf("after unset, GetSessionTags() = %v, want %v", tags, want)
+	}
+}
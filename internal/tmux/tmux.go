@@ -5,9 +5,12 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -30,47 +33,158 @@ var (
 	ErrNoServer        = errors.New("no tmux server running")
 	ErrSessionExists   = errors.New("session already exists")
 	ErrSessionNotFound = errors.New("session not found")
+	ErrUnknownAgent    = errors.New("pane is running an unrecognized agent command")
 )
 
 // Tmux wraps tmux operations.
-type Tmux struct{}
+type Tmux struct {
+	runner    Runner
+	killGrace time.Duration
+	dryRun    io.Writer
+	remote    *Remote
+}
+
+// Runner executes a tmux command with the given arguments and returns its
+// raw stdout and stderr. The default runner shells out to the tmux binary;
+// tests substitute a fake to exercise command construction without a server.
+type Runner func(args ...string) (stdout, stderr string, err error)
+
+// Option configures a Tmux wrapper.
+type Option func(*Tmux)
+
+// WithRunner overrides how tmux commands are executed.
+func WithRunner(r Runner) Option {
+	return func(t *Tmux) {
+		t.runner = r
+	}
+}
+
+// WithKillGracePeriod overrides how long process-killing operations wait
+// after SIGTERM before escalating to SIGKILL. Non-positive values keep the default.
+func WithKillGracePeriod(d time.Duration) Option {
+	return func(t *Tmux) {
+		t.killGrace = d
+	}
+}
+
+// WithDryRun makes mutating operations (respawn, kill, switch-client, and
+// friends) write the tmux command they would run to w and return nil instead
+// of executing it. Read-only queries still run normally.
+func WithDryRun(w io.Writer) Option {
+	return func(t *Tmux) {
+		t.dryRun = w
+	}
+}
 
 // NewTmux creates a new Tmux wrapper.
-func NewTmux() *Tmux {
-	return &Tmux{}
+func NewTmux(opts ...Option) *Tmux {
+	t := &Tmux{}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
-// run executes a tmux command and returns stdout.
-func (t *Tmux) run(args ...string) (string, error) {
+// execRunner runs the real tmux binary.
+func execRunner(args ...string) (string, string, error) {
 	cmd := exec.Command("tmux", args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
 	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+// run executes a tmux command and returns stdout.
+func (t *Tmux) run(args ...string) (string, error) {
+	runner := t.runner
+	if runner == nil && t.remote != nil {
+		runner = t.remote.runner
+	}
+	if runner == nil {
+		runner = execRunner
+	}
+
+	stdout, stderr, err := runner(args...)
 	if err != nil {
-		return "", t.wrapError(err, stderr.String(), args)
+		return "", t.wrapError(err, stderr, args)
+	}
+
+	return strings.TrimSpace(stdout), nil
+}
+
+// DryRun reports whether mutating operations are only being printed.
+func (t *Tmux) DryRun() bool {
+	return t.dryRun != nil
+}
+
+// runMutating executes a tmux command that changes server state.
+// In dry-run mode it reports the command instead of running it.
+func (t *Tmux) runMutating(args ...string) (string, error) {
+	if t.dryRun != nil {
+		fmt.Fprintf(t.dryRun, "Would execute: tmux %s\n", strings.Join(args, " "))
+		return "", nil
 	}
+	return t.run(args...)
+}
 
-	return strings.TrimSpace(stdout.String()), nil
+// runInteractive runs a tmux command on this process's terminal, for
+// commands like attach-session that take it over until the user detaches.
+// An injected Runner is used instead when set, and dry-run only prints.
+func (t *Tmux) runInteractive(args ...string) error {
+	if t.dryRun != nil || t.runner != nil {
+		_, err := t.runMutating(args...)
+		return err
+	}
+	if t.remote != nil {
+		if err := t.remote.interactive(args...); err != nil {
+			return t.wrapError(err, "", args)
+		}
+		return nil
+	}
+	cmd := exec.Command("tmux", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return t.wrapError(err, "", args)
+	}
+	return nil
+}
+
+// QuoteArg escapes s for tmux's command-line parser, which treats an
+// argument ending in ";" as a command separator and strips the ";". The
+// trailing ";" is written as "\;", which tmux turns back into ";". Use it for
+// shell commands passed through to a pane so they reach the shell intact.
+func QuoteArg(s string) string {
+	if strings.HasSuffix(s, ";") {
+		return s[:len(s)-1] + `\;`
+	}
+	return s
 }
 
-// wrapError wraps tmux errors with context.
+// wrapError wraps tmux errors with context. The trimmed stderr text is kept
+// in the message, including for the sentinel errors, so failures like
+// "can't find pane: %9" stay actionable.
 func (t *Tmux) wrapError(err error, stderr string, args []string) error {
 	stderr = strings.TrimSpace(stderr)
 
 	// Detect specific error types
-	if strings.Contains(stderr, "no server running") ||
-		strings.Contains(stderr, "error connecting to") ||
-		strings.Contains(stderr, "no current target") {
-		return ErrNoServer
-	}
-	if strings.Contains(stderr, "duplicate session") {
-		return ErrSessionExists
-	}
-	if strings.Contains(stderr, "session not found") ||
-		strings.Contains(stderr, "can't find session") {
-		return ErrSessionNotFound
+	var sentinel error
+	switch {
+	case strings.Contains(stderr, "no server running"),
+		strings.Contains(stderr, "error connecting to"),
+		strings.Contains(stderr, "no current target"):
+		sentinel = ErrNoServer
+	case strings.Contains(stderr, "duplicate session"):
+		sentinel = ErrSessionExists
+	case strings.Contains(stderr, "session not found"),
+		strings.Contains(stderr, "can't find session"):
+		sentinel = ErrSessionNotFound
+	}
+	if sentinel != nil {
+		return fmt.Errorf("%w (tmux: %s)", sentinel, stderr)
 	}
 
 	if stderr != "" {
@@ -79,11 +193,15 @@ func (t *Tmux) wrapError(err error, stderr string, args []string) error {
 	return fmt.Errorf("tmux %s: %w", args[0], err)
 }
 
-// NewSession creates a new detached tmux session.
+// NewSession creates a new detached tmux session. It fails with
+// ErrVersionUnsupported on a tmux older than MinVersion.
 func (t *Tmux) NewSession(name, workDir string) error {
+	if err := t.CheckVersion(); err != nil {
+		return err
+	}
 	args := []string{"new-session", "-d", "-s", name}
 	if workDir != "" {
-		args = append(args, "-c", workDir)
+		args = append(args, "-c", t.remotePath(workDir))
 	}
 	_, err := t.run(args...)
 	return err
@@ -94,13 +212,17 @@ func (t *Tmux) NewSession(name, workDir string) error {
 // or the command arrives before the shell prompt. The command runs directly as the
 // initial process of the pane.
 // See: https://github.com/anthropics/gastown/issues/280
+// It fails with ErrVersionUnsupported on a tmux older than MinVersion.
 func (t *Tmux) NewSessionWithCommand(name, workDir, command string) error {
+	if err := t.CheckVersion(); err != nil {
+		return err
+	}
 	args := []string{"new-session", "-d", "-s", name}
 	if workDir != "" {
-		args = append(args, "-c", workDir)
+		args = append(args, "-c", t.remotePath(workDir))
 	}
 	// Add the command as the last argument - tmux runs it as the pane's initial process
-	args = append(args, command)
+	args = append(args, QuoteArg(command))
 	_, err := t.run(args...)
 	return err
 }
@@ -141,16 +263,28 @@ func (t *Tmux) EnsureSessionFresh(name, workDir string) error {
 }
 
 // KillSession terminates a tmux session.
+// Returns an error wrapping ErrSessionNotFound if the session does not exist.
 func (t *Tmux) KillSession(name string) error {
-	_, err := t.run("kill-session", "-t", name)
+	_, err := t.runMutating("kill-session", "-t", name)
+	if errors.Is(err, ErrSessionNotFound) {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
 	return err
 }
 
-// processKillGracePeriod is how long to wait after SIGTERM before sending SIGKILL.
+// processKillGracePeriod is the default wait after SIGTERM before sending SIGKILL.
 // 2 seconds gives processes time to clean up gracefully. The previous 100ms was too short
 // and caused Claude processes to become orphans when they couldn't shut down in time.
 const processKillGracePeriod = 2 * time.Second
 
+// killGracePeriod returns the SIGTERM-to-SIGKILL wait for this wrapper.
+func (t *Tmux) killGracePeriod() time.Duration {
+	if t.killGrace > 0 {
+		return t.killGrace
+	}
+	return processKillGracePeriod
+}
+
 // KillSessionWithProcesses explicitly kills all processes in a session before terminating it.
 // This prevents orphan processes that survive tmux kill-session due to SIGHUP being ignored.
 //
@@ -169,6 +303,15 @@ const processKillGracePeriod = 2 * time.Second
 //
 // This ensures Claude processes and all their children are properly terminated.
 func (t *Tmux) KillSessionWithProcesses(name string) error {
+	if t.dryRun != nil {
+		fmt.Fprintf(t.dryRun, "Would kill processes in session %s\n", name)
+		return t.KillSession(name)
+	}
+	if t.remote != nil {
+		// Pane PIDs are the host's; kill-session hangs up its processes
+		return t.KillSession(name)
+	}
+
 	// Get the pane PID
 	pid, err := t.GetPanePID(name)
 	if err != nil {
@@ -202,7 +345,7 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 		}
 
 		// Wait for graceful shutdown (2s gives processes time to clean up)
-		time.Sleep(processKillGracePeriod)
+		time.Sleep(t.killGracePeriod())
 
 		// Send SIGKILL to any remaining descendants
 		for _, dpid := range descendants {
@@ -211,7 +354,7 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 
 		// Kill the pane process itself (may have called setsid() and detached)
 		_ = exec.Command("kill", "-TERM", pid).Run()
-		time.Sleep(processKillGracePeriod)
+		time.Sleep(t.killGracePeriod())
 		_ = exec.Command("kill", "-KILL", pid).Run()
 	}
 
@@ -219,7 +362,7 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 	// Ignore "session not found" - killing the pane process may have already
 	// caused tmux to destroy the session automatically
 	err = t.KillSession(name)
-	if err == ErrSessionNotFound {
+	if errors.Is(err, ErrSessionNotFound) {
 		return nil
 	}
 	return err
@@ -230,6 +373,15 @@ func (t *Tmux) KillSessionWithProcesses(name string) error {
 // the calling process (e.g., gt done) is running inside the session it's terminating.
 // Without exclusion, the caller would be killed before completing the cleanup.
 func (t *Tmux) KillSessionWithProcessesExcluding(name string, excludePIDs []string) error {
+	if t.dryRun != nil {
+		fmt.Fprintf(t.dryRun, "Would kill processes in session %s\n", name)
+		return t.KillSession(name)
+	}
+	if t.remote != nil {
+		// Pane PIDs are the host's; kill-session hangs up its processes
+		return t.KillSession(name)
+	}
+
 	// Build exclusion set for O(1) lookup
 	exclude := make(map[string]bool)
 	for _, pid := range excludePIDs {
@@ -279,7 +431,7 @@ func (t *Tmux) KillSessionWithProcessesExcluding(name string, excludePIDs []stri
 		}
 
 		// Wait for graceful shutdown (2s gives processes time to clean up)
-		time.Sleep(processKillGracePeriod)
+		time.Sleep(t.killGracePeriod())
 
 		// Send SIGKILL to any remaining non-excluded processes
 		for _, dpid := range killList {
@@ -290,7 +442,7 @@ func (t *Tmux) KillSessionWithProcessesExcluding(name string, excludePIDs []stri
 		// Only if not excluded
 		if !exclude[pid] {
 			_ = exec.Command("kill", "-TERM", pid).Run()
-			time.Sleep(processKillGracePeriod)
+			time.Sleep(t.killGracePeriod())
 			_ = exec.Command("kill", "-KILL", pid).Run()
 		}
 	}
@@ -299,7 +451,7 @@ func (t *Tmux) KillSessionWithProcessesExcluding(name string, excludePIDs []stri
 	// Ignore "session not found" - if we killed all non-excluded processes,
 	// tmux may have already destroyed the session automatically
 	err = t.KillSession(name)
-	if err == ErrSessionNotFound {
+	if errors.Is(err, ErrSessionNotFound) {
 		return nil
 	}
 	return err
@@ -371,6 +523,15 @@ func getProcessGroupMembers(pgid string) []string {
 // This ensures Claude processes and all their children are properly terminated
 // before respawning the pane.
 func (t *Tmux) KillPaneProcesses(pane string) error {
+	if t.dryRun != nil {
+		fmt.Fprintf(t.dryRun, "Would kill processes in pane %s\n", pane)
+		return nil
+	}
+	if t.remote != nil {
+		// Pane PIDs are the host's; respawn-pane -k hangs up its processes
+		return nil
+	}
+
 	// Get the pane PID
 	pid, err := t.GetPanePID(pane)
 	if err != nil {
@@ -402,7 +563,7 @@ func (t *Tmux) KillPaneProcesses(pane string) error {
 	}
 
 	// Wait for graceful shutdown (2s gives processes time to clean up)
-	time.Sleep(processKillGracePeriod)
+	time.Sleep(t.killGracePeriod())
 
 	// Send SIGKILL to any remaining descendants
 	for _, dpid := range descendants {
@@ -412,7 +573,7 @@ func (t *Tmux) KillPaneProcesses(pane string) error {
 	// Kill the pane process itself (may have called setsid() and detached,
 	// or may have no children like Claude Code)
 	_ = exec.Command("kill", "-TERM", pid).Run()
-	time.Sleep(processKillGracePeriod)
+	time.Sleep(t.killGracePeriod())
 	_ = exec.Command("kill", "-KILL", pid).Run()
 
 	return nil
@@ -427,6 +588,15 @@ func (t *Tmux) KillPaneProcesses(pane string) error {
 // survive. After this function returns, RespawnPane's -k flag will send SIGHUP to
 // clean up the remaining processes.
 func (t *Tmux) KillPaneProcessesExcluding(pane string, excludePIDs []string) error {
+	if t.dryRun != nil {
+		fmt.Fprintf(t.dryRun, "Would kill processes in pane %s\n", pane)
+		return nil
+	}
+	if t.remote != nil {
+		// Pane PIDs are the host's; respawn-pane -k hangs up its processes
+		return nil
+	}
+
 	// Build exclusion set for O(1) lookup
 	exclude := make(map[string]bool)
 	for _, pid := range excludePIDs {
@@ -505,10 +675,29 @@ func (t *Tmux) SetExitEmpty(on bool) error {
 
 // IsAvailable checks if tmux is installed and can be invoked.
 func (t *Tmux) IsAvailable() bool {
+	if t.remote != nil {
+		_, _, err := t.remote.output("tmux", "-V")
+		return err == nil
+	}
 	cmd := exec.Command("tmux", "-V")
 	return cmd.Run() == nil
 }
 
+// IsServerRunning reports whether a tmux server is reachable, independent of
+// whether the caller is inside a tmux client (see IsInsideTmux).
+// Returns false with a nil error when no server is running; other failures
+// (e.g., tmux not installed) are returned as errors.
+func (t *Tmux) IsServerRunning() (bool, error) {
+	_, err := t.run("list-sessions", "-F", "#{session_name}")
+	if err != nil {
+		if errors.Is(err, ErrNoServer) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // HasSession checks if a session exists (exact match).
 // Uses "=" prefix for exact matching, preventing prefix matches
 // (e.g., "gt-deacon-boot" won't match when checking for "gt-deacon").
@@ -523,6 +712,12 @@ func (t *Tmux) HasSession(name string) (bool, error) {
 	return true, nil
 }
 
+// CurrentSession returns the name of the session the calling client is
+// attached to (resolved by tmux from $TMUX).
+func (t *Tmux) CurrentSession() (string, error) {
+	return t.run("display-message", "-p", "#{session_name}")
+}
+
 // ListSessions returns all session names.
 func (t *Tmux) ListSessions() ([]string, error) {
 	out, err := t.run("list-sessions", "-F", "#{session_name}")
@@ -540,6 +735,22 @@ func (t *Tmux) ListSessions() ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
+// ListSessionsWithPrefix returns the names of all sessions starting with prefix.
+func (t *Tmux) ListSessionsWithPrefix(prefix string) ([]string, error) {
+	sessions, err := t.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []string
+	for _, s := range sessions {
+		if strings.HasPrefix(s, prefix) {
+			matched = append(matched, s)
+		}
+	}
+	return matched, nil
+}
+
 // SessionSet provides O(1) session existence checks by caching session names.
 // Use this when you need to check multiple sessions to avoid N+1 subprocess calls.
 type SessionSet struct {
@@ -760,11 +971,16 @@ func (t *Tmux) WakePaneIfDetached(target string) {
 // If multiple goroutines try to nudge the same session concurrently, they will
 // queue up and execute one at a time. This prevents garbled input when
 // SessionStart hooks and nudges arrive simultaneously.
+//
+// Paused sessions (gt crew pause) aren't nudged: ErrSessionPaused.
 func (t *Tmux) NudgeSession(session, message string) error {
 	// Serialize nudges to this session to prevent interleaving
 	lock := getSessionNudgeLock(session)
 	lock.Lock()
 	defer lock.Unlock()
+	if err := t.checkNotPaused(session); err != nil {
+		return err
+	}
 
 	// 1. Send text in literal mode (handles special characters)
 	if _, err := t.run("send-keys", "-t", session, "-l", message); err != nil {
@@ -805,6 +1021,9 @@ func (t *Tmux) NudgePane(pane, message string) error {
 	lock := getSessionNudgeLock(pane)
 	lock.Lock()
 	defer lock.Unlock()
+	if err := t.checkNotPaused(pane); err != nil {
+		return err
+	}
 
 	// 1. Send text in literal mode (handles special characters)
 	if _, err := t.run("send-keys", "-t", pane, "-l", message); err != nil {
@@ -886,6 +1105,39 @@ func (t *Tmux) GetPaneCommand(session string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// PaneCommand returns the command running in target's active pane, where
+// target may be a session, window or pane ID. Returns "bash", "node", "kimi", etc.
+func (t *Tmux) PaneCommand(target string) (string, error) {
+	return t.run("display-message", "-p", "-t", target, "#{pane_current_command}")
+}
+
+// DetectAgent identifies the agent running in target from its pane command.
+// Several agents share process names (e.g., "node"), so when the session's
+// GT_AGENT names an agent whose process names include the pane command, that
+// agent wins. Returns a nil preset and no error when the pane is at a shell
+// prompt; an unrecognized command yields ErrUnknownAgent.
+func (t *Tmux) DetectAgent(target string) (*config.AgentPresetInfo, error) {
+	cmd, err := t.PaneCommand(target)
+	if err != nil {
+		return nil, err
+	}
+	if cmd == "" || slices.Contains(constants.SupportedShells, cmd) {
+		return nil, nil
+	}
+
+	if agentName, err := t.GetEnvironment(target, "GT_AGENT"); err == nil {
+		if info := config.GetAgentPresetByName(agentName); info != nil &&
+			(filepath.Base(info.Command) == cmd || slices.Contains(info.ProcessNames, cmd)) {
+			return info, nil
+		}
+	}
+
+	if info := config.GetAgentPresetByCommand(cmd); info != nil {
+		return info, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownAgent, cmd)
+}
+
 // GetPaneID returns the pane identifier for a session's first pane.
 // Returns a pane ID like "%0" that can be used with RespawnPane.
 func (t *Tmux) GetPaneID(session string) (string, error) {
@@ -900,13 +1152,41 @@ func (t *Tmux) GetPaneID(session string) (string, error) {
 	return lines[0], nil
 }
 
+// ListSessionPanes returns the IDs of every pane in session, across all
+// of its windows.
+func (t *Tmux) ListSessionPanes(session string) ([]string, error) {
+	out, err := t.run("list-panes", "-s", "-t", session, "-F", "#{pane_id}")
+	if err != nil {
+		return nil, err
+	}
+	var panes []string
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			panes = append(panes, line)
+		}
+	}
+	return panes, nil
+}
+
+// ActivePaneID returns the ID of session's active pane (in its active window).
+func (t *Tmux) ActivePaneID(session string) (string, error) {
+	out, err := t.run("display-message", "-p", "-t", session, "#{pane_id}")
+	if err != nil {
+		return "", err
+	}
+	if out == "" {
+		return "", fmt.Errorf("no active pane in session %s", session)
+	}
+	return out, nil
+}
+
 // GetPaneWorkDir returns the current working directory of a pane.
 func (t *Tmux) GetPaneWorkDir(session string) (string, error) {
 	out, err := t.run("list-panes", "-t", session, "-F", "#{pane_current_path}")
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(out), nil
+	return t.localPath(strings.TrimSpace(out)), nil
 }
 
 // GetPanePID returns the PID of the pane's main process.
@@ -918,6 +1198,22 @@ func (t *Tmux) GetPanePID(session string) (string, error) {
 	return strings.TrimSpace(out), nil
 }
 
+// hasChildWithNames is hasChildWithNames on the wrapper's machine: pane
+// PIDs of a remote wrapper are looked up on its host.
+func (t *Tmux) hasChildWithNames(pid string, names []string) bool {
+	if t.remote == nil {
+		return hasChildWithNames(pid, names)
+	}
+	if len(names) == 0 {
+		return false
+	}
+	out, _, err := t.remote.output("pgrep", "-P", pid, "-l")
+	if err != nil {
+		return false
+	}
+	return childListHasNames(out, names)
+}
+
 // hasChildWithNames checks if a process has a child matching any of the given names.
 // Used when the pane command is a shell (bash, zsh) that launched an agent.
 func hasChildWithNames(pid string, names []string) bool {
@@ -930,13 +1226,19 @@ func hasChildWithNames(pid string, names []string) bool {
 	if err != nil {
 		return false
 	}
+	return childListHasNames(string(out), names)
+}
+
+// childListHasNames reports whether pgrep -l output lists a process with
+// one of names.
+func childListHasNames(out string, names []string) bool {
 	// Build a set of names for fast lookup
 	nameSet := make(map[string]bool, len(names))
 	for _, n := range names {
 		nameSet[n] = true
 	}
 	// Check if any child matches
-	lines := strings.Split(string(out), "\n")
+	lines := strings.Split(out, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" {
@@ -1011,11 +1313,19 @@ func (t *Tmux) CapturePaneLines(session string, lines int) ([]string, error) {
 	return strings.Split(out, "\n"), nil
 }
 
-// AttachSession attaches to an existing session.
-// Note: This replaces the current process with tmux attach.
+// AttachSession attaches this terminal to an existing session, blocking
+// until the user detaches.
 func (t *Tmux) AttachSession(session string) error {
-	_, err := t.run("attach-session", "-t", session)
-	return err
+	return t.runInteractive("attach-session", "-t", session)
+}
+
+// AttachOrSwitch moves the user's view to target: switch-client when
+// running inside a tmux client, attach-session on this terminal otherwise.
+func (t *Tmux) AttachOrSwitch(target string) error {
+	if IsInsideTmux() {
+		return t.SwitchClient(target)
+	}
+	return t.AttachSession(target)
 }
 
 // SelectWindow selects a window by index.
@@ -1024,12 +1334,56 @@ func (t *Tmux) SelectWindow(session string, index int) error {
 	return err
 }
 
+// SplitPane splits pane and returns the new pane's ID. The new pane opens
+// to the right of pane (below when below is set), taking percent of it
+// (half when 0), in workDir. It runs command, or a shell when command is
+// empty. pane stays the active pane.
+func (t *Tmux) SplitPane(pane string, below bool, percent int, workDir, command string) (string, error) {
+	args := []string{"split-window", "-d", "-P", "-F", "#{pane_id}", "-t", pane}
+	if below {
+		args = append(args, "-v")
+	} else {
+		args = append(args, "-h")
+	}
+	if percent > 0 && t.supports(minSplitPercentVersion) {
+		args = append(args, "-l", fmt.Sprintf("%d%%", percent))
+	} else if percent > 0 {
+		args = append(args, "-p", strconv.Itoa(percent))
+	}
+	if workDir != "" {
+		args = append(args, "-c", t.remotePath(workDir))
+	}
+	if command != "" {
+		args = append(args, QuoteArg(command))
+	}
+	return t.runMutating(args...)
+}
+
+// SelectLayout arranges the panes of target's window with a tmux preset
+// layout (e.g. "main-vertical", "tiled").
+func (t *Tmux) SelectLayout(target, layout string) error {
+	_, err := t.runMutating("select-layout", "-t", target, layout)
+	return err
+}
+
+// SelectPane makes pane the active pane of its window.
+func (t *Tmux) SelectPane(pane string) error {
+	_, err := t.runMutating("select-pane", "-t", pane)
+	return err
+}
+
 // SetEnvironment sets an environment variable in the session.
 func (t *Tmux) SetEnvironment(session, key, value string) error {
 	_, err := t.run("set-environment", "-t", session, key, value)
 	return err
 }
 
+// UnsetEnvironment removes an environment variable from the session.
+func (t *Tmux) UnsetEnvironment(session, key string) error {
+	_, err := t.runMutating("set-environment", "-u", "-t", session, key)
+	return err
+}
+
 // GetEnvironment gets an environment variable from the session.
 func (t *Tmux) GetEnvironment(session, key string) (string, error) {
 	out, err := t.run("show-environment", "-t", session, key)
@@ -1066,9 +1420,32 @@ func (t *Tmux) GetAllEnvironment(session string) (map[string]string, error) {
 	return env, nil
 }
 
-// RenameSession renames a session.
+// RenameSession renames session oldName to newName in place, keeping its
+// panes and processes (e.g., for crew renames and prefix migrations).
+// newName must match validSessionNameRe: tmux silently rewrites ":" and "."
+// in session names, and anything else would break session name parsing.
+// Returns ErrSessionNotFound if oldName doesn't exist and ErrSessionExists
+// if newName is taken.
 func (t *Tmux) RenameSession(oldName, newName string) error {
-	_, err := t.run("rename-session", "-t", oldName, newName)
+	if !validSessionNameRe.MatchString(newName) {
+		return fmt.Errorf("invalid session name %q: must match %s", newName, validSessionNameRe.String())
+	}
+	exists, err := t.HasSession(oldName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, oldName)
+	}
+	if oldName == newName {
+		return nil
+	}
+	if taken, err := t.HasSession(newName); err != nil {
+		return err
+	} else if taken {
+		return fmt.Errorf("%w: %s", ErrSessionExists, newName)
+	}
+	_, err = t.runMutating("rename-session", "-t", "="+oldName, newName)
 	return err
 }
 
@@ -1170,7 +1547,7 @@ func (t *Tmux) IsRuntimeRunning(session string, processNames []string) bool {
 		if cmd == shell {
 			pid, err := t.GetPanePID(session)
 			if err == nil && pid != "" {
-				return hasChildWithNames(pid, processNames)
+				return t.hasChildWithNames(pid, processNames)
 			}
 			break
 		}
@@ -1276,23 +1653,31 @@ func (t *Tmux) WaitForRuntimeReady(session string, rc *config.RuntimeConfig, tim
 		return nil
 	}
 
+	return t.WaitForReadyPrompt(session, rc.Tmux.ReadyPromptPrefix, timeout, 200*time.Millisecond)
+}
+
+// WaitForReadyPrompt captures the last lines of the session's pane every
+// interval until one starts with prefix (a bare prefix with its trailing
+// space trimmed also matches, for an empty prompt line). Returns an error
+// once timeout elapses without a match.
+func (t *Tmux) WaitForReadyPrompt(session, prefix string, timeout, interval time.Duration) error {
+	trimmedPrefix := strings.TrimSpace(prefix)
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
 		// Capture last few lines of the pane
 		lines, err := t.CapturePaneLines(session, 10)
 		if err != nil {
-			time.Sleep(200 * time.Millisecond)
+			time.Sleep(interval)
 			continue
 		}
 		// Look for runtime prompt indicator at start of line
 		for _, line := range lines {
 			trimmed := strings.TrimSpace(line)
-			prefix := strings.TrimSpace(rc.Tmux.ReadyPromptPrefix)
-			if strings.HasPrefix(trimmed, rc.Tmux.ReadyPromptPrefix) || (prefix != "" && trimmed == prefix) {
+			if strings.HasPrefix(trimmed, prefix) || (trimmedPrefix != "" && trimmed == trimmedPrefix) {
 				return nil
 			}
 		}
-		time.Sleep(200 * time.Millisecond)
+		time.Sleep(interval)
 	}
 	return fmt.Errorf("timeout waiting for runtime prompt")
 }
@@ -1435,6 +1820,19 @@ func (t *Tmux) ConfigureGasTownSession(session string, theme Theme, rig, worker,
 	return nil
 }
 
+// PipePane pipes the session's pane output to the shell command command
+// (e.g., the transcript recorder, see transcript.Enable). -o only opens a
+// pipe when none is open, so calling it again keeps the existing pipe
+// instead of toggling it off. The session name is validated because
+// callers put it into command.
+func (t *Tmux) PipePane(session, command string) error {
+	if !validSessionNameRe.MatchString(session) {
+		return fmt.Errorf("invalid session name %q", session)
+	}
+	_, err := t.run("pipe-pane", "-o", "-t", session, command)
+	return err
+}
+
 // EnableMouseMode enables mouse support and clipboard integration for a tmux session.
 // This allows clicking to select panes/windows, scrolling with mouse wheel,
 // and dragging to resize panes. Hold Shift for native terminal text selection.
@@ -1460,8 +1858,14 @@ func IsInsideTmux() bool {
 func (t *Tmux) SetMailClickBinding(session string) error {
 	// Bind left-click on status-right to show mail popup
 	// The popup runs gt mail peek and closes on any key
+	peek := "gt mail peek || echo 'No unread mail'"
+	if !t.supports(minPopupVersion) {
+		// No popups: show the output in the pane until a key is pressed
+		_, err := t.run("bind-key", "-T", "root", "MouseDown1StatusRight", "run-shell", peek)
+		return err
+	}
 	_, err := t.run("bind-key", "-T", "root", "MouseDown1StatusRight",
-		"display-popup", "-E", "-w", "60", "-h", "15", "gt mail peek || echo 'No unread mail'")
+		"display-popup", "-E", "-w", "60", "-h", "15", peek)
 	return err
 }
 
@@ -1469,20 +1873,22 @@ func (t *Tmux) SetMailClickBinding(session string) error {
 // This is used for "hot reload" of agent sessions - instantly restart in place.
 // The pane parameter should be a pane ID (e.g., "%0") or session:window.pane format.
 func (t *Tmux) RespawnPane(pane, command string) error {
-	_, err := t.run("respawn-pane", "-k", "-t", pane, command)
+	_, err := t.runMutating("respawn-pane", "-k", "-t", pane, QuoteArg(command))
 	return err
 }
 
 // RespawnPaneWithWorkDir kills all processes in a pane and starts a new command
 // in the specified working directory. Use this when the pane's current working
-// directory may have been deleted.
+// directory may have been deleted. respawn-pane -c needs tmux 2.6, which
+// MinVersion guarantees.
 func (t *Tmux) RespawnPaneWithWorkDir(pane, workDir, command string) error {
+	workDir = t.remotePath(workDir)
 	args := []string{"respawn-pane", "-k", "-t", pane}
 	if workDir != "" {
 		args = append(args, "-c", workDir)
 	}
-	args = append(args, command)
-	_, err := t.run(args...)
+	args = append(args, QuoteArg(command))
+	_, err := t.runMutating(args...)
 	return err
 }
 
@@ -1490,7 +1896,7 @@ func (t *Tmux) RespawnPaneWithWorkDir(pane, workDir, command string) error {
 // This resets copy-mode display from [0/N] to [0/0].
 // The pane parameter should be a pane ID (e.g., "%0") or session:window.pane format.
 func (t *Tmux) ClearHistory(pane string) error {
-	_, err := t.run("clear-history", "-t", pane)
+	_, err := t.runMutating("clear-history", "-t", pane)
 	return err
 }
 
@@ -1503,14 +1909,18 @@ func (t *Tmux) SetRemainOnExit(pane string, on bool) error {
 	if !on {
 		value = "off"
 	}
-	_, err := t.run("set-option", "-t", pane, "remain-on-exit", value)
+	setOption := "set-option"
+	if !t.supports(minPaneOptionsVersion) {
+		setOption = "set-window-option" // A window option before pane options
+	}
+	_, err := t.runMutating(setOption, "-t", pane, "remain-on-exit", value)
 	return err
 }
 
 // SwitchClient switches the current tmux client to a different session.
 // Used after remote recycle to move the user's view to the recycled session.
 func (t *Tmux) SwitchClient(targetSession string) error {
-	_, err := t.run("switch-client", "-t", targetSession)
+	_, err := t.runMutating("switch-client", "-t", targetSession)
 	return err
 }
 
@@ -1,9 +1,13 @@
 package tmux
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -120,7 +124,7 @@ func TestDuplicateSession(t *testing.T) {
 
 	// Try to create duplicate
 	err := tm.NewSession(sessionName, "")
-	if err != ErrSessionExists {
+	if !errors.Is(err, ErrSessionExists) {
 		t.Errorf("expected ErrSessionExists, got %v", err)
 	}
 }
@@ -208,9 +212,45 @@ func TestWrapError(t *testing.T) {
 
 	for _, tt := range tests {
 		err := tm.wrapError(nil, tt.stderr, []string{"test"})
-		if err != tt.want {
+		if !errors.Is(err, tt.want) {
 			t.Errorf("wrapError(%q) = %v, want %v", tt.stderr, err, tt.want)
 		}
+		if !strings.Contains(err.Error(), tt.stderr) {
+			t.Errorf("wrapError(%q) = %q, want stderr in message", tt.stderr, err)
+		}
+	}
+}
+
+func TestRunnerStderrInErrors(t *testing.T) {
+	const stderr = "can't find pane: %9\n"
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		return "", stderr, errors.New("exit status 1")
+	}))
+
+	calls := map[string]func() error{
+		"RespawnPane": func() error { return tm.RespawnPane("%9", "claude") },
+		"HasSession": func() error {
+			_, err := tm.HasSession("gt-mayor")
+			return err
+		},
+		"GetPaneID": func() error {
+			_, err := tm.GetPaneID("gt-mayor")
+			return err
+		},
+		"CurrentSession": func() error {
+			_, err := tm.CurrentSession()
+			return err
+		},
+	}
+	for name, call := range calls {
+		err := call()
+		if err == nil {
+			t.Errorf("%s: expected error", name)
+			continue
+		}
+		if !strings.Contains(err.Error(), "tmux") || !strings.Contains(err.Error(), "can't find pane: %9") {
+			t.Errorf("%s error = %q, want tmux stderr text", name, err)
+		}
 	}
 }
 
@@ -1059,3 +1099,428 @@ func TestKillPaneProcessesExcluding_FiltersPIDs(t *testing.T) {
 		}
 	}
 }
+
+func TestKillSessionNotFound(t *testing.T) {
+	var got []string
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		got = args
+		return "", "can't find session: gt-missing", fmt.Errorf("exit status 1")
+	}))
+
+	err := tm.KillSession("gt-missing")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("KillSession error = %v, want ErrSessionNotFound", err)
+	}
+	if !strings.Contains(err.Error(), "gt-missing") {
+		t.Errorf("KillSession error %q should name the session", err)
+	}
+	if want := "kill-session -t gt-missing"; strings.Join(got, " ") != want {
+		t.Errorf("tmux args = %q, want %q", strings.Join(got, " "), want)
+	}
+}
+
+func TestDryRunReportsMutations(t *testing.T) {
+	var ran [][]string
+	var out strings.Builder
+	tm := NewTmux(
+		WithRunner(func(args ...string) (string, string, error) {
+			ran = append(ran, args)
+			return "", "", nil
+		}),
+		WithDryRun(&out),
+	)
+
+	if !tm.DryRun() {
+		t.Fatal("DryRun() = false, want true")
+	}
+	if err := tm.RespawnPane("%5", "gt prime"); err != nil {
+		t.Fatalf("RespawnPane: %v", err)
+	}
+	if err := tm.SwitchClient("hq-mayor"); err != nil {
+		t.Fatalf("SwitchClient: %v", err)
+	}
+	if err := tm.KillSession("gt-gastown-witness"); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	if err := tm.KillPaneProcesses("%5"); err != nil {
+		t.Fatalf("KillPaneProcesses: %v", err)
+	}
+
+	if len(ran) != 0 {
+		t.Errorf("dry run executed tmux commands: %v", ran)
+	}
+	want := "Would execute: tmux respawn-pane -k -t %5 gt prime\n" +
+		"Would execute: tmux switch-client -t hq-mayor\n" +
+		"Would execute: tmux kill-session -t gt-gastown-witness\n" +
+		"Would kill processes in pane %5\n"
+	if out.String() != want {
+		t.Errorf("dry run output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestDryRunStillRunsQueries(t *testing.T) {
+	var ran [][]string
+	tm := NewTmux(
+		WithRunner(func(args ...string) (string, string, error) {
+			ran = append(ran, args)
+			return "", "", nil
+		}),
+		WithDryRun(io.Discard),
+	)
+
+	if has, err := tm.HasSession("hq-mayor"); err != nil || !has {
+		t.Fatalf("HasSession = %v, %v; want true, nil", has, err)
+	}
+	if len(ran) != 1 || ran[0][0] != "has-session" {
+		t.Errorf("expected has-session to run in dry-run mode, ran %v", ran)
+	}
+}
+
+func TestIsServerRunning(t *testing.T) {
+	tests := []struct {
+		name        string
+		stdout      string
+		stderr      string
+		err         error
+		wantRunning bool
+		wantErr     bool
+	}{
+		{"server up", "hq-mayor\n", "", nil, true, false},
+		{"server down", "", "no server running on /tmp/tmux-1000/default", fmt.Errorf("exit status 1"), false, false},
+		{"tmux broken", "", "", fmt.Errorf("exec: \"tmux\": executable file not found"), false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+				return tt.stdout, tt.stderr, tt.err
+			}))
+			running, err := tm.IsServerRunning()
+			if running != tt.wantRunning || (err != nil) != tt.wantErr {
+				t.Errorf("IsServerRunning() = %v, %v; want %v, err=%v", running, err, tt.wantRunning, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDetectAgent(t *testing.T) {
+	tests := []struct {
+		name    string
+		paneCmd string
+		gtAgent string // empty means unset in the session environment
+		want    string // expected preset name; empty means nil
+		wantErr error
+	}{
+		{name: "claude via node", paneCmd: "node", want: "claude"},
+		{name: "claude binary", paneCmd: "claude", want: "claude"},
+		{name: "kimi", paneCmd: "kimi", want: "kimi"},
+		{name: "cursor by process name", paneCmd: "cursor-agent", want: "cursor"},
+		{name: "GT_AGENT disambiguates node", paneCmd: "node", gtAgent: "opencode", want: "opencode"},
+		{name: "stale GT_AGENT ignored", paneCmd: "kimi", gtAgent: "claude", want: "kimi"},
+		{name: "shell means no agent", paneCmd: "zsh"},
+		{name: "empty command", paneCmd: ""},
+		{name: "unknown command", paneCmd: "vim", wantErr: ErrUnknownAgent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+				switch args[0] {
+				case "display-message":
+					return tt.paneCmd + "\n", "", nil
+				case "show-environment":
+					if tt.gtAgent == "" {
+						return "", "unknown variable: GT_AGENT", fmt.Errorf("exit status 1")
+					}
+					return "GT_AGENT=" + tt.gtAgent, "", nil
+				}
+				return "", "", fmt.Errorf("unexpected tmux call %v", args)
+			}))
+
+			info, err := tm.DetectAgent("%1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DetectAgent() error = %v, want %v", err, tt.wantErr)
+			}
+			var got string
+			if info != nil {
+				got = string(info.Name)
+			}
+			if got != tt.want {
+				t.Errorf("DetectAgent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaneCommand(t *testing.T) {
+	var gotArgs []string
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		gotArgs = args
+		return "kimi\n", "", nil
+	}))
+	cmd, err := tm.PaneCommand("gt-a-crew-max")
+	if err != nil || cmd != "kimi" {
+		t.Fatalf("PaneCommand() = %q, %v; want kimi", cmd, err)
+	}
+	want := "display-message -p -t gt-a-crew-max #{pane_current_command}"
+	if strings.Join(gotArgs, " ") != want {
+		t.Errorf("tmux args = %q, want %q", strings.Join(gotArgs, " "), want)
+	}
+}
+
+func TestSplitPane(t *testing.T) {
+	var calls []string
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		if args[0] == "-V" {
+			return "tmux 3.3a\n", "", nil
+		}
+		calls = append(calls, strings.Join(args, " "))
+		return "%7\n", "", nil
+	}))
+	id, err := tm.SplitPane("%1", true, 30, "/work", "gt logs -f")
+	if err != nil || id != "%7" {
+		t.Fatalf("SplitPane() = %q, %v; want %%7", id, err)
+	}
+	if _, err := tm.SplitPane("%1", false, 0, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"split-window -d -P -F #{pane_id} -t %1 -v -l 30% -c /work gt logs -f",
+		"split-window -d -P -F #{pane_id} -t %1 -h",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("tmux calls = %q, want %q", calls, want)
+	}
+}
+
+func TestWaitForReadyPrompt(t *testing.T) {
+	var captures int
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		if args[0] != "capture-pane" {
+			return "", "", fmt.Errorf("unexpected tmux call %v", args)
+		}
+		captures++
+		if captures < 3 {
+			return "Loading instructions...\n", "", nil
+		}
+		return "Welcome back\n❯ \n", "", nil
+	}))
+
+	if err := tm.WaitForReadyPrompt("gt-mayor", "❯ ", time.Second, time.Millisecond); err != nil {
+		t.Fatalf("WaitForReadyPrompt() = %v, want nil", err)
+	}
+	if captures != 3 {
+		t.Errorf("captured pane %d times, want 3", captures)
+	}
+
+	// A prompt that never appears times out
+	if err := tm.WaitForReadyPrompt("gt-mayor", "> ", 20*time.Millisecond, time.Millisecond); err == nil {
+		t.Error("WaitForReadyPrompt() with missing prompt = nil, want timeout error")
+	}
+}
+
+func TestQuoteArg(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"exec claude", "exec claude"},
+		{"cd /x && echo 'a;b' \"c d\"", "cd /x && echo 'a;b' \"c d\""},
+		{"echo done;", `echo done\;`},
+		{`echo semi\;`, `echo semi\\;`}, // shell sees the \; it was given
+		{";", `\;`},
+	}
+	for _, tt := range tests {
+		if got := QuoteArg(tt.in); got != tt.want {
+			t.Errorf("QuoteArg(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestRespawnPaneQuotesCommand(t *testing.T) {
+	var got []string
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		got = args
+		return "", "", nil
+	}))
+
+	if err := tm.RespawnPane("%1", "echo 'it''s' \"a b\"; true;"); err != nil {
+		t.Fatalf("RespawnPane: %v", err)
+	}
+	want := []string{"respawn-pane", "-k", "-t", "%1", `echo 'it''s' "a b"; true\;`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tmux args = %q, want %q", got, want)
+	}
+}
+
+func TestAttachOrSwitch(t *testing.T) {
+	tests := []struct {
+		name string
+		tmux string
+		want []string
+	}{
+		{"inside tmux", "/tmp/tmux-1000/default,1,0", []string{"switch-client", "-t", "gt-gastown-witness"}},
+		{"outside tmux", "", []string{"attach-session", "-t", "gt-gastown-witness"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMUX", tt.tmux)
+			var got []string
+			tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+				got = args
+				return "", "", nil
+			}))
+			if err := tm.AttachOrSwitch("gt-gastown-witness"); err != nil {
+				t.Fatalf("AttachOrSwitch: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tmux args = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespawnPaneCommandSurvivesTmux(t *testing.T) {
+	if !hasTmux() {
+		t.Skip("tmux not installed")
+	}
+
+	tm := NewTmux()
+	sessionName := "gt-test-quote-" + t.Name()
+	_ = tm.KillSession(sessionName)
+	if err := tm.NewSessionWithCommand(sessionName, "", "sleep 30"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	defer func() { _ = tm.KillSession(sessionName) }()
+	pane, err := tm.GetPaneID(sessionName)
+	if err != nil {
+		t.Fatalf("GetPaneID: %v", err)
+	}
+
+	// Spaces, both quote styles, embedded semicolons, and a trailing
+	// escaped semicolon that tmux would otherwise strip.
+	out := filepath.Join(t.TempDir(), "out")
+	command := `exec >'` + out + `'; printf '%s|' "a b" 'q"x' "it's" 'mid;dle'; echo end\;`
+	if err := tm.RespawnPane(pane, command); err != nil {
+		t.Fatalf("RespawnPane: %v", err)
+	}
+
+	want := "a b|q\"x|it's|mid;dle|end;\n"
+	var got string
+	for deadline := time.Now().Add(3 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		if data, err := os.ReadFile(out); err == nil && strings.HasSuffix(string(data), "\n") {
+			got = string(data)
+			break
+		}
+	}
+	if got != want {
+		t.Errorf("pane output = %q, want %q", got, want)
+	}
+}
+
+func TestParseTmuxVersion(t *testing.T) {
+	tests := []struct {
+		out     string
+		want    tmuxRelease
+		wantErr bool
+	}{
+		{"tmux 3.3a\n", tmuxRelease{3, 3}, false},
+		{"tmux 2.6", tmuxRelease{2, 6}, false},
+		{"tmux 1.8", tmuxRelease{1, 8}, false},
+		{"tmux next-3.4", tmuxRelease{3, 4}, false},
+		{"tmux 10.12", tmuxRelease{10, 12}, false},
+		{"tmux master", tmuxRelease{}, true},
+		{"", tmuxRelease{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTmuxVersion(tt.out)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseTmuxVersion(%q) = %v, %v; want %v, err %v", tt.out, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	if !(tmuxRelease{2, 10}).atLeast(tmuxRelease{2, 6}) || (tmuxRelease{2, 1}).atLeast(tmuxRelease{2, 6}) ||
+		!(tmuxRelease{3, 0}).atLeast(tmuxRelease{2, 6}) || (tmuxRelease{1, 9}).atLeast(tmuxRelease{2, 0}) {
+		t.Error("tmuxRelease.atLeast compares major before minor")
+	}
+}
+
+// oldTmuxRunner fakes a tmux reporting version for -V, recording calls.
+func oldTmuxRunner(version string, calls *[][]string) Runner {
+	return func(args ...string) (string, string, error) {
+		*calls = append(*calls, args)
+		if args[0] == "-V" {
+			return "tmux " + version + "\n", "", nil
+		}
+		return "", "", nil
+	}
+}
+
+func TestRenameSession(t *testing.T) {
+	live := map[string]bool{"gt-frontend-crew-alice": true, "gt-frontend-crew-bob": true}
+	var renamed []string
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		switch args[0] {
+		case "has-session":
+			if live[strings.TrimPrefix(args[2], "=")] {
+				return "", "", nil
+			}
+			return "", "can't find session: " + args[2], errors.New("exit status 1")
+		case "rename-session":
+			renamed = args
+			return "", "", nil
+		}
+		t.Fatalf("unexpected tmux call %q", args)
+		return "", "", nil
+	}))
+
+	if err := tm.RenameSession("gt-frontend-crew-alice", "gt-frontend-crew-carol"); err != nil {
+		t.Fatalf("RenameSession: %v", err)
+	}
+	want := []string{"rename-session", "-t", "=gt-frontend-crew-alice", "gt-frontend-crew-carol"}
+	if !reflect.DeepEqual(renamed, want) {
+		t.Errorf("tmux args = %q, want %q", renamed, want)
+	}
+
+	renamed = nil
+	tests := []struct {
+		name     string
+		old, new string
+		want     error
+	}{
+		{"collision", "gt-frontend-crew-alice", "gt-frontend-crew-bob", ErrSessionExists},
+		{"missing session", "gt-frontend-crew-zed", "gt-frontend-crew-carol", ErrSessionNotFound},
+	}
+	for _, tt := range tests {
+		if err := tm.RenameSession(tt.old, tt.new); !errors.Is(err, tt.want) {
+			t.Errorf("%s: RenameSession() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "gt-frontend:crew", "gt.frontend", "has space", "semi;colon"} {
+		if err := tm.RenameSession("gt-frontend-crew-alice", bad); err == nil || !strings.Contains(err.Error(), "invalid session name") {
+			t.Errorf("RenameSession(%q) error = %v, want invalid session name", bad, err)
+		}
+	}
+	if renamed != nil {
+		t.Errorf("rejected renames still ran tmux %q", renamed)
+	}
+}
+
+func TestPipePane(t *testing.T) {
+	var got []string
+	tm := NewTmux(WithRunner(func(args ...string) (string, string, error) {
+		got = args
+		return "", "", nil
+	}))
+
+	if err := tm.PipePane("gt-web-crew-jane", "exec gt transcript record gt-web-crew-jane"); err != nil {
+		t.Fatalf("PipePane: %v", err)
+	}
+	want := []string{"pipe-pane", "-o", "-t", "gt-web-crew-jane", "exec gt transcript record gt-web-crew-jane"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tmux args = %q, want %q", got, want)
+	}
+	if err := tm.PipePane("web; rm -rf ~", "cat"); err == nil {
+		t.Error("PipePane accepted a session name that would reach the shell")
+	}
+}
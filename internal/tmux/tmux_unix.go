@@ -17,3 +17,8 @@ func killProcessGroup(pgid int) error {
 func killProcess(pid int, sig syscall.Signal) error {
 	return syscall.Kill(pid, sig)
 }
+
+// processAlive reports whether a process with the given PID is running.
+func processAlive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
@@ -0,0 +1,121 @@
+package tmux
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrVersionUnsupported means the installed tmux is older than MinVersion.
+var ErrVersionUnsupported = errors.New("tmux version not supported")
+
+// errUnrecognizedVersion is returned for tmux -V output without a release
+// number.
+var errUnrecognizedVersion = errors.New("unrecognized tmux version")
+
+// MinVersion is the oldest tmux release Gas Town runs on. respawn-pane -c
+// (handoffs into a fresh directory) and the pane-died hook need 2.6.
+const MinVersion = "2.6"
+
+var minTmuxVersion = tmuxRelease{2, 6}
+
+// Oldest tmux releases with features newer than MinVersion. On older ones
+// the compatibility fallback noted is used instead.
+var (
+	minSplitPercentVersion = tmuxRelease{3, 1} // split-window -l N%; -p N before
+	minPaneOptionsVersion  = tmuxRelease{3, 0} // Pane options; remain-on-exit was a window option before
+	minPopupVersion        = tmuxRelease{3, 2} // display-popup; run-shell output before
+)
+
+// tmuxRelease is a tmux major.minor version; letter suffixes like the
+// "a" in 3.3a are ignored.
+type tmuxRelease struct {
+	major, minor int
+}
+
+func (r tmuxRelease) atLeast(min tmuxRelease) bool {
+	if r.major != min.major {
+		return r.major > min.major
+	}
+	return r.minor >= min.minor
+}
+
+func (r tmuxRelease) String() string {
+	return fmt.Sprintf("%d.%d", r.major, r.minor)
+}
+
+var tmuxVersionRe = regexp.MustCompile(`(\d+)\.(\d+)`)
+
+// parseTmuxVersion parses tmux -V output: "tmux 3.3a", "tmux next-3.4".
+// Builds without a release number ("tmux master") are an error.
+func parseTmuxVersion(out string) (tmuxRelease, error) {
+	m := tmuxVersionRe.FindStringSubmatch(out)
+	if m == nil {
+		return tmuxRelease{}, fmt.Errorf("%w %q", errUnrecognizedVersion, strings.TrimSpace(out))
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	return tmuxRelease{major, minor}, nil
+}
+
+// tmuxVersions caches tmux -V by host ("" for this machine), so the
+// compatibility checks don't run it for every command.
+var tmuxVersions sync.Map // map[string]tmuxRelease
+
+// tmuxVersion reports the installed tmux version, from tmux -V.
+func (t *Tmux) tmuxVersion() (tmuxRelease, error) {
+	var host string
+	if t.remote != nil {
+		host = t.remote.Host
+	}
+	cache := t.runner == nil // An injected runner fakes its own tmux
+	if v, ok := tmuxVersions.Load(host); ok && cache {
+		return v.(tmuxRelease), nil
+	}
+	out, err := t.run("-V")
+	if err != nil {
+		return tmuxRelease{}, err
+	}
+	v, err := parseTmuxVersion(out)
+	if err == nil && cache {
+		tmuxVersions.Store(host, v)
+	}
+	return v, err
+}
+
+// Version returns the installed tmux version, e.g. "3.3".
+func (t *Tmux) Version() (string, error) {
+	v, err := t.tmuxVersion()
+	if err != nil {
+		return "", err
+	}
+	return v.String(), nil
+}
+
+// CheckVersion fails with ErrVersionUnsupported, naming MinVersion, if the
+// installed tmux is too old for Gas Town. Builds without a release number
+// ("tmux master") pass.
+func (t *Tmux) CheckVersion() error {
+	v, err := t.tmuxVersion()
+	if errors.Is(err, errUnrecognizedVersion) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("running tmux -V (is tmux installed?): %w", err)
+	}
+	if !v.atLeast(minTmuxVersion) {
+		return fmt.Errorf("%w: found tmux %s, Gas Town needs tmux %s or newer (3.2 or newer recommended); upgrade tmux from your package manager or build it from https://github.com/tmux/tmux",
+			ErrVersionUnsupported, v, MinVersion)
+	}
+	return nil
+}
+
+// supports reports whether the installed tmux is at least min. A version
+// that can't be determined is taken to be current.
+func (t *Tmux) supports(min tmuxRelease) bool {
+	v, err := t.tmuxVersion()
+	return err != nil || v.atLeast(min)
+}
@@ -0,0 +1,77 @@
+package tmux
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		wantErr bool
+	}{
+		{"3.3a", false},
+		{"2.6", false},
+		{"2.5", true},
+		{"1.8", true},
+		{"master", false}, // No release number: a development build
+	}
+	for _, tt := range tests {
+		var calls [][]string
+		tm := NewTmux(WithRunner(oldTmuxRunner(tt.version, &calls)))
+		err := tm.CheckVersion()
+		if got := errors.Is(err, ErrVersionUnsupported); got != tt.wantErr {
+			t.Errorf("tmux %s: CheckVersion() = %v, want unsupported %v", tt.version, err, tt.wantErr)
+		}
+		if tt.wantErr && !strings.Contains(err.Error(), "found tmux "+tt.version+", Gas Town needs tmux "+MinVersion+" or newer") {
+			t.Errorf("tmux %s: error %q should name the found and minimum versions", tt.version, err)
+		}
+	}
+
+	tm := NewTmux(WithRunner(oldTmuxRunner("2.1", new([][]string))))
+	if err := tm.NewSession("gt-gastown-crew-max", ""); !errors.Is(err, ErrVersionUnsupported) {
+		t.Errorf("NewSession on tmux 2.1 = %v, want ErrVersionUnsupported", err)
+	}
+}
+
+func TestCompatShims(t *testing.T) {
+	tests := []struct {
+		version string
+		want    [][]string
+	}{
+		{"3.3a", [][]string{
+			{"split-window", "-d", "-P", "-F", "#{pane_id}", "-t", "%1", "-v", "-l", "30%"},
+			{"set-option", "-t", "%1", "remain-on-exit", "on"},
+			{"bind-key", "-T", "root", "MouseDown1StatusRight", "display-popup", "-E", "-w", "60", "-h", "15", "gt mail peek || echo 'No unread mail'"},
+		}},
+		{"2.9", [][]string{
+			{"split-window", "-d", "-P", "-F", "#{pane_id}", "-t", "%1", "-v", "-p", "30"},
+			{"set-window-option", "-t", "%1", "remain-on-exit", "on"},
+			{"bind-key", "-T", "root", "MouseDown1StatusRight", "run-shell", "gt mail peek || echo 'No unread mail'"},
+		}},
+	}
+	for _, tt := range tests {
+		var calls [][]string
+		tm := NewTmux(WithRunner(oldTmuxRunner(tt.version, &calls)))
+		if _, err := tm.SplitPane("%1", true, 30, "", ""); err != nil {
+			t.Fatal(err)
+		}
+		if err := tm.SetRemainOnExit("%1", true); err != nil {
+			t.Fatal(err)
+		}
+		if err := tm.SetMailClickBinding("gt-gastown-crew-max"); err != nil {
+			t.Fatal(err)
+		}
+		var got [][]string
+		for _, call := range calls {
+			if call[0] != "-V" {
+				got = append(got, call)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tmux %s: calls = %q, want %q", tt.version, got, tt.want)
+		}
+	}
+}
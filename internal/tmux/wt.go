@@ -0,0 +1,295 @@
+package tmux
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+)
+
+// wtWindow is the Windows Terminal window sessions open their tabs in.
+const wtWindow = "gastown"
+
+// wtStartGrace is how long a session counts as running before its shell has
+// recorded its PID.
+const wtStartGrace = 30 * time.Second
+
+// EnvWTShell overrides the shell Windows Terminal tabs run commands with.
+const EnvWTShell = "GT_WT_SHELL"
+
+// WindowsTerminal implements Multiplexer natively on Windows, running each
+// session in a tab of a Windows Terminal window (wt.exe) instead of tmux
+// under WSL.
+//
+// wt.exe can open tabs but not list or close them, so sessions are tracked
+// in a state directory: gt records each session's working directory, and the
+// tab's shell writes its Windows PID before running the command. A session is
+// running while that process is alive; killing it kills its process tree.
+// Startup commands are POSIX shell, so tabs run them with Git Bash.
+type WindowsTerminal struct {
+	runner   Runner
+	dryRun   io.Writer
+	stateDir string
+	shell    string
+	alive    func(pid int) bool
+	kill     func(pid int) error
+}
+
+// NewWindowsTerminal creates a Windows Terminal backend. It takes the same
+// options as NewTmux: WithRunner executes wt.exe (not tmux) commands,
+// WithDryRun prints mutating commands, and WithKillGracePeriod is ignored.
+func NewWindowsTerminal(opts ...Option) *WindowsTerminal {
+	t := NewTmux(opts...)
+	stateDir := filepath.Join(os.TempDir(), "gt-sessions")
+	if home, err := os.UserHomeDir(); err == nil {
+		stateDir = filepath.Join(home, ".gt", "sessions")
+	}
+	return &WindowsTerminal{
+		runner:   t.runner,
+		dryRun:   t.dryRun,
+		stateDir: stateDir,
+		shell:    wtShell(),
+		alive:    processAlive,
+		kill:     killProcessGroup,
+	}
+}
+
+// wtShell returns the POSIX shell tabs run: $GT_WT_SHELL, else Git for
+// Windows' bash. A bare "bash" on PATH is often WSL's, hence the lookup.
+func wtShell() string {
+	if shell := os.Getenv(EnvWTShell); shell != "" {
+		return shell
+	}
+	for _, dir := range []string{os.Getenv("ProgramFiles"), os.Getenv("ProgramW6432")} {
+		if dir == "" {
+			continue
+		}
+		if path := filepath.Join(dir, "Git", "bin", "bash.exe"); fileExists(path) {
+			return path
+		}
+	}
+	return "bash"
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// wtExecRunner runs the real wt.exe.
+func wtExecRunner(args ...string) (string, string, error) {
+	cmd := exec.Command("wt.exe", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func (w *WindowsTerminal) runMutating(args ...string) error {
+	if w.dryRun != nil {
+		fmt.Fprintf(w.dryRun, "Would execute: wt.exe %s\n", strings.Join(args, " "))
+		return nil
+	}
+	runner := w.runner
+	if runner == nil {
+		runner = wtExecRunner
+	}
+	if _, stderr, err := runner(args...); err != nil {
+		if stderr = strings.TrimSpace(stderr); stderr != "" {
+			return fmt.Errorf("wt.exe %s: %s", args[0], stderr)
+		}
+		return fmt.Errorf("wt.exe %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// wtSession is a session's record in the state directory.
+type wtSession struct {
+	WorkDir string    `json:"work_dir,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+func (w *WindowsTerminal) recordPath(name string) string {
+	return filepath.Join(w.stateDir, name+".json")
+}
+
+func (w *WindowsTerminal) pidPath(name string) string {
+	return filepath.Join(w.stateDir, name+".pid")
+}
+
+// sessionState looks up a session: its record, the PID its shell wrote (0
+// while still starting), and whether it is running. Records of sessions that
+// exited, or never started, are removed.
+func (w *WindowsTerminal) sessionState(name string) (*wtSession, int, bool) {
+	data, err := os.ReadFile(w.recordPath(name))
+	if err != nil {
+		return nil, 0, false
+	}
+	var rec wtSession
+	if err := json.Unmarshal(data, &rec); err != nil {
+		w.forget(name)
+		return nil, 0, false
+	}
+
+	pidData, err := os.ReadFile(w.pidPath(name))
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(pidData)))
+	switch {
+	case pid > 0 && w.alive(pid):
+		return &rec, pid, true
+	case pid == 0 && os.IsNotExist(err) && time.Since(rec.Started) < wtStartGrace:
+		return &rec, 0, true
+	}
+	w.forget(name)
+	return nil, 0, false
+}
+
+// forget removes a session's state files. Dry runs leave them alone.
+func (w *WindowsTerminal) forget(name string) {
+	if w.dryRun != nil {
+		return
+	}
+	_ = os.Remove(w.recordPath(name))
+	_ = os.Remove(w.pidPath(name))
+}
+
+// ListSessions returns the names of running sessions.
+func (w *WindowsTerminal) ListSessions() ([]string, error) {
+	entries, err := os.ReadDir(w.stateDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sessions []string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok {
+			continue
+		}
+		if _, _, running := w.sessionState(name); running {
+			sessions = append(sessions, name)
+		}
+	}
+	return sessions, nil
+}
+
+// HasSession reports whether a running session named name exists.
+func (w *WindowsTerminal) HasSession(name string) (bool, error) {
+	_, _, running := w.sessionState(name)
+	return running, nil
+}
+
+// NewSessionWithCommand opens a tab titled name in the gastown window,
+// running command in workDir. The command also gets GT_SESSION, since no
+// multiplexer tells the session its own name.
+func (w *WindowsTerminal) NewSessionWithCommand(name, workDir, command string) error {
+	if !validSessionNameRe.MatchString(name) {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+	if _, _, running := w.sessionState(name); running {
+		return fmt.Errorf("%w: %s", ErrSessionExists, name)
+	}
+	return w.openTab(name, workDir, command)
+}
+
+// openTab records session name and opens its tab.
+func (w *WindowsTerminal) openTab(name, workDir, command string) error {
+	if w.dryRun == nil {
+		data, err := json.Marshal(wtSession{WorkDir: workDir, Started: time.Now()})
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(w.stateDir, 0700); err != nil {
+			return fmt.Errorf("creating session state: %w", err)
+		}
+		_ = os.Remove(w.pidPath(name))
+		if err := os.WriteFile(w.recordPath(name), data, 0600); err != nil {
+			return fmt.Errorf("recording session: %w", err)
+		}
+	}
+
+	// /proc/$$/winpid is the shell's Windows PID under Git Bash (MSYS2)
+	script := "cat /proc/$$/winpid > " + config.ShellQuote(filepath.ToSlash(w.pidPath(name))) +
+		" && " + config.PrependEnv(command, map[string]string{"GT_SESSION": name})
+	args := []string{"-w", wtWindow, "new-tab", "--title", name, "--suppressApplicationTitle"}
+	if workDir != "" {
+		args = append(args, "-d", workDir)
+	}
+	// wt.exe splits its command line into subcommands at unescaped semicolons
+	args = append(args, w.shell, "-lc", strings.ReplaceAll(script, ";", `\;`))
+	if err := w.runMutating(args...); err != nil {
+		w.forget(name)
+		return err
+	}
+	return nil
+}
+
+// KillSession kills the session's process tree, which closes its tab.
+func (w *WindowsTerminal) KillSession(name string) error {
+	_, pid, running := w.sessionState(name)
+	if !running {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, name)
+	}
+	if pid == 0 {
+		return fmt.Errorf("session %s is still starting", name)
+	}
+	if w.dryRun != nil {
+		fmt.Fprintf(w.dryRun, "Would execute: taskkill /F /T /PID %d\n", pid)
+		return nil
+	}
+	if err := w.kill(pid); err != nil {
+		return fmt.Errorf("killing session %s: %w", name, err)
+	}
+	w.forget(name)
+	return nil
+}
+
+// RespawnPane replaces the session's tab with a new one running command in
+// the same working directory; pane is the session name. The new tab opens
+// before the old process tree is killed, so a session can respawn itself.
+func (w *WindowsTerminal) RespawnPane(pane, command string) error {
+	rec, pid, running := w.sessionState(pane)
+	if !running {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, pane)
+	}
+	if pid == 0 {
+		return fmt.Errorf("session %s is still starting", pane)
+	}
+	if err := w.openTab(pane, rec.WorkDir, command); err != nil {
+		return err
+	}
+	if w.dryRun != nil {
+		fmt.Fprintf(w.dryRun, "Would execute: taskkill /F /T /PID %d\n", pid)
+		return nil
+	}
+	if err := w.kill(pid); err != nil {
+		return fmt.Errorf("killing old %s: %w", pane, err)
+	}
+	return nil
+}
+
+// SendKeys is not supported: Windows Terminal cannot type into a tab.
+func (w *WindowsTerminal) SendKeys(session, keys string) error {
+	return fmt.Errorf("sending keys to %s with Windows Terminal: %w", session, errors.ErrUnsupported)
+}
+
+// AttachSession checks the session is running. Its tab is already open in
+// the gastown window, so there is nothing to attach.
+func (w *WindowsTerminal) AttachSession(session string) error {
+	if _, _, running := w.sessionState(session); !running {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, session)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package tmux
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeWT is a Windows Terminal backend whose tabs and processes are faked:
+// wt.exe calls are recorded, and live holds the running PIDs.
+type fakeWT struct {
+	calls  [][]string
+	live   map[int]bool
+	killed []int
+}
+
+func newFakeWT(t *testing.T, opts ...Option) (*WindowsTerminal, *fakeWT) {
+	t.Helper()
+	f := &fakeWT{live: make(map[int]bool)}
+	w := NewWindowsTerminal(append([]Option{WithRunner(func(args ...string) (string, string, error) {
+		f.calls = append(f.calls, args)
+		return "", "", nil
+	})}, opts...)...)
+	w.stateDir = t.TempDir()
+	w.shell = "bash.exe"
+	w.alive = func(pid int) bool { return f.live[pid] }
+	w.kill = func(pid int) error {
+		f.killed = append(f.killed, pid)
+		delete(f.live, pid)
+		return nil
+	}
+	return w, f
+}
+
+// started plays the tab's shell writing its PID.
+func (f *fakeWT) started(t *testing.T, w *WindowsTerminal, name string, pid int) {
+	t.Helper()
+	if err := os.WriteFile(w.pidPath(name), []byte(strconv.Itoa(pid)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f.live[pid] = true
+}
+
+func TestWindowsTerminalNewSession(t *testing.T) {
+	w, f := newFakeWT(t)
+
+	if err := w.NewSessionWithCommand("gt-web-crew-jane", `C:\town\web\crew\jane`, "claude; echo done"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	pidFile := filepath.ToSlash(w.pidPath("gt-web-crew-jane"))
+	want := [][]string{{"-w", "gastown", "new-tab", "--title", "gt-web-crew-jane", "--suppressApplicationTitle",
+		"-d", `C:\town\web\crew\jane`, "bash.exe", "-lc",
+		"cat /proc/$$/winpid > " + pidFile + " && export GT_SESSION=gt-web-crew-jane && claude\\; echo done"}}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Errorf("wt.exe calls = %q, want %q", f.calls, want)
+	}
+
+	// Running while the shell starts up, before it has written its PID
+	if ok, _ := w.HasSession("gt-web-crew-jane"); !ok {
+		t.Error("HasSession() = false for a starting session")
+	}
+	if err := w.NewSessionWithCommand("gt-web-crew-jane", "", "claude"); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("existing session: err = %v, want ErrSessionExists", err)
+	}
+	if err := w.NewSessionWithCommand("bad;name", "", "claude"); err == nil {
+		t.Error("invalid session name accepted")
+	}
+}
+
+func TestWindowsTerminalListSessions(t *testing.T) {
+	w, f := newFakeWT(t)
+	for _, name := range []string{"gt-web-crew-jane", "gt-web-witness", "hq-mayor"} {
+		if err := w.NewSessionWithCommand(name, "", "claude"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.started(t, w, "gt-web-crew-jane", 101)
+	f.started(t, w, "gt-web-witness", 102)
+	delete(f.live, 102) // Exited
+	// hq-mayor's shell never recorded its PID
+	old := time.Now().Add(-time.Hour)
+	if err := os.WriteFile(w.recordPath("hq-mayor"), []byte(`{"started":"`+old.Format(time.RFC3339)+`"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := w.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if want := []string{"gt-web-crew-jane"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSessions() = %q, want %q", got, want)
+	}
+	for _, name := range []string{"gt-web-witness", "hq-mayor"} {
+		if _, err := os.Stat(w.recordPath(name)); !os.IsNotExist(err) {
+			t.Errorf("stale record of %s not removed", name)
+		}
+	}
+}
+
+func TestWindowsTerminalKillSession(t *testing.T) {
+	w, f := newFakeWT(t)
+	if err := w.NewSessionWithCommand("gt-web-crew-jane", "", "claude"); err != nil {
+		t.Fatal(err)
+	}
+	f.started(t, w, "gt-web-crew-jane", 101)
+
+	if err := w.KillSession("gt-web-crew-jane"); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	if !reflect.DeepEqual(f.killed, []int{101}) {
+		t.Errorf("killed = %v, want [101]", f.killed)
+	}
+	if err := w.KillSession("gt-web-crew-jane"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("second KillSession() error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestWindowsTerminalRespawnPane(t *testing.T) {
+	w, f := newFakeWT(t)
+	if err := w.NewSessionWithCommand("gt-web-crew-jane", `C:\jane`, "claude"); err != nil {
+		t.Fatal(err)
+	}
+	f.started(t, w, "gt-web-crew-jane", 101)
+	f.calls = nil
+
+	if err := w.RespawnPane("gt-web-crew-jane", "claude --continue"); err != nil {
+		t.Fatalf("RespawnPane: %v", err)
+	}
+	if len(f.calls) != 1 || !slices.Contains(f.calls[0], `C:\jane`) {
+		t.Errorf("wt.exe calls = %q, want one new tab in C:\\jane", f.calls)
+	}
+	if !reflect.DeepEqual(f.killed, []int{101}) {
+		t.Errorf("killed = %v, want the old shell [101]", f.killed)
+	}
+	// The new tab is starting under the same name
+	if ok, _ := w.HasSession("gt-web-crew-jane"); !ok {
+		t.Error("HasSession() = false after respawn")
+	}
+}
+
+func TestWindowsTerminalDryRun(t *testing.T) {
+	var out bytes.Buffer
+	w, f := newFakeWT(t, WithDryRun(&out))
+	if err := w.NewSessionWithCommand("gt-web-crew-jane", "", "claude"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("dry run executed %q", f.calls)
+	}
+	if !strings.HasPrefix(out.String(), "Would execute: wt.exe -w gastown new-tab --title gt-web-crew-jane") {
+		t.Errorf("dry run output = %q", out.String())
+	}
+	if entries, _ := os.ReadDir(w.stateDir); len(entries) != 0 {
+		t.Errorf("dry run recorded %d state files", len(entries))
+	}
+}
+
+func TestWindowsTerminalSendKeysUnsupported(t *testing.T) {
+	w, _ := newFakeWT(t)
+	if err := w.SendKeys("gt-web-crew-jane", "hi"); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("SendKeys() error = %v, want ErrUnsupported", err)
+	}
+}
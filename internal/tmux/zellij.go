@@ -0,0 +1,201 @@
+package tmux
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/constants"
+)
+
+// Zellij implements Multiplexer on the zellij CLI (0.39 or newer, for
+// attach --create-background).
+//
+// zellij has no equivalent of tmux's pane targeting or respawn-pane, so
+// commands are typed into the session's focused pane: a new session runs its
+// command from the default shell, and RespawnPane interrupts the running
+// agent with Ctrl-C before typing the replacement.
+type Zellij struct {
+	runner Runner
+	dryRun io.Writer
+}
+
+// NewZellij creates a zellij backend. It takes the same options as NewTmux:
+// WithRunner executes zellij (not tmux) commands, WithDryRun prints mutating
+// commands, and WithKillGracePeriod is ignored.
+func NewZellij(opts ...Option) *Zellij {
+	t := NewTmux(opts...)
+	return &Zellij{runner: t.runner, dryRun: t.dryRun}
+}
+
+// zellijExecRunner runs the real zellij binary.
+func zellijExecRunner(args ...string) (string, string, error) {
+	cmd := exec.Command("zellij", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func (z *Zellij) run(args ...string) (string, error) {
+	runner := z.runner
+	if runner == nil {
+		runner = zellijExecRunner
+	}
+
+	stdout, stderr, err := runner(args...)
+	if err != nil {
+		return "", z.wrapError(err, stderr, args)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+func (z *Zellij) runMutating(args ...string) (string, error) {
+	if z.dryRun != nil {
+		fmt.Fprintf(z.dryRun, "Would execute: zellij %s\n", strings.Join(args, " "))
+		return "", nil
+	}
+	return z.run(args...)
+}
+
+// wrapError maps zellij's messages onto the tmux sentinels, so callers can
+// check errors.Is(err, ErrSessionNotFound) whichever backend is in use.
+func (z *Zellij) wrapError(err error, stderr string, args []string) error {
+	stderr = strings.TrimSpace(stderr)
+
+	var sentinel error
+	switch {
+	case strings.Contains(stderr, "No active zellij sessions"):
+		sentinel = ErrNoServer
+	case strings.Contains(stderr, "already exists"):
+		sentinel = ErrSessionExists
+	case strings.Contains(stderr, "not found"),
+		strings.Contains(stderr, "No session named"),
+		strings.Contains(stderr, "No session with the name"):
+		sentinel = ErrSessionNotFound
+	}
+	if sentinel != nil {
+		return fmt.Errorf("%w (zellij: %s)", sentinel, stderr)
+	}
+
+	if stderr != "" {
+		return fmt.Errorf("zellij %s: %s", args[0], stderr)
+	}
+	return fmt.Errorf("zellij %s: %w", args[0], err)
+}
+
+// ListSessions returns the names of running sessions. Exited sessions that
+// zellij keeps around for resurrection are not included.
+func (z *Zellij) ListSessions() ([]string, error) {
+	out, err := z.run("list-sessions", "--no-formatting")
+	if err != nil {
+		if errors.Is(err, ErrNoServer) {
+			return nil, nil // No sessions = nothing running
+		}
+		return nil, err
+	}
+
+	var sessions []string
+	for _, line := range strings.Split(out, "\n") {
+		// "<name> [Created 2h ago] (current)" or "... (EXITED - attach to resurrect)"
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.Contains(line, "EXITED") {
+			continue
+		}
+		sessions = append(sessions, fields[0])
+	}
+	return sessions, nil
+}
+
+// HasSession reports whether a running session named name exists.
+func (z *Zellij) HasSession(name string) (bool, error) {
+	sessions, err := z.ListSessions()
+	if err != nil {
+		return false, err
+	}
+	for _, s := range sessions {
+		if s == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// NewSessionWithCommand creates a background session and starts command
+// in workDir from its default shell.
+func (z *Zellij) NewSessionWithCommand(name, workDir, command string) error {
+	if !validSessionNameRe.MatchString(name) {
+		return fmt.Errorf("invalid session name %q", name)
+	}
+	exists, err := z.HasSession(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrSessionExists, name)
+	}
+	if _, err := z.runMutating("attach", "--create-background", name); err != nil {
+		return err
+	}
+	if workDir != "" {
+		command = "cd " + config.ShellQuote(workDir) + " && " + command
+	}
+	return z.SendKeys(name, command)
+}
+
+// KillSession terminates a session and every process in it.
+func (z *Zellij) KillSession(name string) error {
+	_, err := z.runMutating("kill-session", name)
+	return err
+}
+
+// RespawnPane interrupts the agent in the focused pane and types command in
+// its place. zellij can't target panes from its CLI, so pane is the session
+// name. Ctrl-C is sent twice, since agents such as Claude Code only exit on
+// the second; unlike tmux respawn-pane -k, a process that ignores SIGINT
+// keeps running.
+func (z *Zellij) RespawnPane(pane, command string) error {
+	for i := 0; i < 2; i++ {
+		if _, err := z.runMutating("--session", pane, "action", "write", "3"); err != nil {
+			return err
+		}
+	}
+	if z.dryRun == nil {
+		time.Sleep(constants.ShutdownNotifyDelay)
+	}
+	return z.SendKeys(pane, command)
+}
+
+// SendKeys types keys into the session's focused pane and presses Enter.
+func (z *Zellij) SendKeys(session, keys string) error {
+	if _, err := z.runMutating("--session", session, "action", "write-chars", keys); err != nil {
+		return err
+	}
+	_, err := z.runMutating("--session", session, "action", "write", "13")
+	return err
+}
+
+// AttachSession attaches this terminal to an existing session, blocking
+// until the user detaches.
+func (z *Zellij) AttachSession(session string) error {
+	if z.dryRun != nil || z.runner != nil {
+		_, err := z.runMutating("attach", session)
+		return err
+	}
+	cmd := exec.Command("zellij", "attach", session)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return z.wrapError(err, "", []string{"attach"})
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package tmux
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeZellij records zellij calls and answers list-sessions from sessions.
+type fakeZellij struct {
+	sessions string
+	listErr  string
+	calls    [][]string
+}
+
+func (f *fakeZellij) run(args ...string) (string, string, error) {
+	if args[0] == "list-sessions" {
+		if f.listErr != "" {
+			return "", f.listErr, errors.New("exit status 1")
+		}
+		return f.sessions, "", nil
+	}
+	f.calls = append(f.calls, args)
+	return "", "", nil
+}
+
+func TestZellijListSessions(t *testing.T) {
+	f := &fakeZellij{sessions: "gt-web-crew-jane [Created 2h ago] (current)\n" +
+		"hq-mayor [Created 5m ago]\n" +
+		"gt-web-witness [Created 1d ago] (EXITED - attach to resurrect)\n"}
+	z := NewZellij(WithRunner(f.run))
+
+	got, err := z.ListSessions()
+	if err != nil {
+		t.Fatalf("ListSessions: %v", err)
+	}
+	if want := []string{"gt-web-crew-jane", "hq-mayor"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSessions() = %q, want %q (exited sessions skipped)", got, want)
+	}
+	if ok, _ := z.HasSession("gt-web-witness"); ok {
+		t.Error("HasSession reported an exited session as running")
+	}
+
+	f.listErr = "No active zellij sessions found."
+	got, err = z.ListSessions()
+	if err != nil || len(got) != 0 {
+		t.Errorf("no server: ListSessions() = %q, %v; want empty, nil", got, err)
+	}
+}
+
+func TestZellijNewSessionWithCommand(t *testing.T) {
+	f := &fakeZellij{sessions: "hq-mayor [Created 5m ago]"}
+	z := NewZellij(WithRunner(f.run))
+
+	if err := z.NewSessionWithCommand("gt-web-crew-jane", "/town/web/crew/jane", "claude --resume"); err != nil {
+		t.Fatalf("NewSessionWithCommand: %v", err)
+	}
+	want := [][]string{
+		{"attach", "--create-background", "gt-web-crew-jane"},
+		{"--session", "gt-web-crew-jane", "action", "write-chars", "cd /town/web/crew/jane && claude --resume"},
+		{"--session", "gt-web-crew-jane", "action", "write", "13"},
+	}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Errorf("zellij calls = %q, want %q", f.calls, want)
+	}
+
+	if err := z.NewSessionWithCommand("hq-mayor", "", "claude"); !errors.Is(err, ErrSessionExists) {
+		t.Errorf("existing session: err = %v, want ErrSessionExists", err)
+	}
+	if err := z.NewSessionWithCommand("bad;name", "", "claude"); err == nil {
+		t.Error("invalid session name accepted")
+	}
+}
+
+func TestZellijRespawnPaneDryRun(t *testing.T) {
+	var out bytes.Buffer
+	f := &fakeZellij{}
+	z := NewZellij(WithRunner(f.run), WithDryRun(&out))
+
+	if err := z.RespawnPane("gt-web-crew-jane", "claude"); err != nil {
+		t.Fatalf("RespawnPane: %v", err)
+	}
+	if len(f.calls) != 0 {
+		t.Errorf("dry run executed %q", f.calls)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	want := []string{
+		"Would execute: zellij --session gt-web-crew-jane action write 3",
+		"Would execute: zellij --session gt-web-crew-jane action write 3",
+		"Would execute: zellij --session gt-web-crew-jane action write-chars claude",
+		"Would execute: zellij --session gt-web-crew-jane action write 13",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("dry run output = %q, want %q", lines, want)
+	}
+}
+
+func TestZellijWrapError(t *testing.T) {
+	z := NewZellij(WithRunner(func(args ...string) (string, string, error) {
+		return "", "No session named \"gt-nope\" found.", errors.New("exit status 1")
+	}))
+	if err := z.KillSession("gt-nope"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("KillSession() error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestNewMultiplexer(t *testing.T) {
+	tests := []struct {
+		env     string
+		want    string
+		wantErr bool
+	}{
+		{"", "*tmux.Tmux", false},
+		{"tmux", "*tmux.Tmux", false},
+		{"Zellij", "*tmux.Zellij", false},
+		{"wt", "*tmux.WindowsTerminal", false},
+		{"screen", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			t.Setenv(EnvMultiplexer, tt.env)
+			mux, err := NewMultiplexer()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NewMultiplexer() = %T, want an error", mux)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewMultiplexer: %v", err)
+			}
+			if got := reflect.TypeOf(mux).String(); got != tt.want {
+				t.Errorf("NewMultiplexer() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
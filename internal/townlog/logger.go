@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
@@ -267,7 +268,8 @@ func parseLogLine(line string) (Event, error) {
 	if len(line) < 19 {
 		return event, fmt.Errorf("line too short")
 	}
-	ts, err := time.Parse("2006-01-02 15:04:05", line[:19])
+	// Timestamps are written in local time (see formatLogLine).
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05", line[:19], time.Local)
 	if err != nil {
 		return event, fmt.Errorf("parsing timestamp: %w", err)
 	}
@@ -311,12 +313,37 @@ func parseLogLine(line string) (Event, error) {
 		event.Agent = rest
 	} else {
 		event.Agent = rest[:spaceIdx]
-		// The rest is context info (not worth parsing further)
+		event.Context = parseContext(event.Type, rest[spaceIdx+1:])
 	}
 
 	return event, nil
 }
 
+// parenDetails maps event types whose detail is written as "<verb> (context)"
+// to their verb, so parseContext can recover the context.
+var parenDetails = map[EventType]string{
+	EventWake:         "resumed",
+	EventHandoff:      "handed off",
+	EventCrash:        "exited unexpectedly",
+	EventKill:         "killed",
+	EventSessionDeath: "session terminated",
+}
+
+// parseContext recovers an event's context from its detail text.
+// Only the "<verb> (context)" formats are reversible; other details
+// yield an empty context.
+func parseContext(eventType EventType, detail string) string {
+	verb, ok := parenDetails[eventType]
+	if !ok {
+		return ""
+	}
+	inner := strings.TrimPrefix(detail, verb+" (")
+	if inner == detail || !strings.HasSuffix(inner, ")") {
+		return ""
+	}
+	return strings.TrimSuffix(inner, ")")
+}
+
 func splitLines(s string) []string {
 	var lines []string
 	start := 0
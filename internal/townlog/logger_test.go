@@ -101,6 +101,20 @@ func TestParseLogLine(t *testing.T) {
 				return e.Type == EventNudge && e.Agent == "gastown/crew/max"
 			},
 		},
+		{
+			name: "handoff line keeps context",
+			line: "2025-12-26 15:32:10 [handoff] mayor handed off (reason: context full; cmd: exec claude)",
+			check: func(e Event) bool {
+				return e.Type == EventHandoff && e.Agent == "mayor" && e.Context == "reason: context full; cmd: exec claude"
+			},
+		},
+		{
+			name: "handoff line without context",
+			line: "2025-12-26 15:32:10 [handoff] mayor handed off",
+			check: func(e Event) bool {
+				return e.Type == EventHandoff && e.Context == ""
+			},
+		},
 		{
 			name:    "too short",
 			line:    "short",
@@ -0,0 +1,343 @@
+// Package transcript archives what agents print to their panes.
+//
+// Enable has tmux pipe-pane feed a Gas Town session's pane output to
+// `gt transcript record <session>` when the session starts, which appends it to
+// ~/.gastown/transcripts/<session>/transcript.log through a Writer. When the
+// log reaches its size limit it is rotated to transcript.log.1 (shifting older
+// files up) and the oldest beyond the keep limit is deleted, so a session's
+// history survives handoffs and restarts without growing without bound.
+//
+// Alongside each log the Writer keeps an index (transcript.log.idx, rotated
+// with it) of when output was written, so Chunks can put the output on a
+// timeline (see gt replay).
+package transcript
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// EnvDir overrides the directory transcripts are stored in.
+const EnvDir = "GT_TRANSCRIPT_DIR"
+
+const (
+	// DefaultMaxBytes is the size at which the current log is rotated.
+	DefaultMaxBytes = 10 << 20
+	// DefaultKeep is how many rotated logs are kept per session.
+	DefaultKeep = 5
+
+	// LogName is the file the current output is appended to.
+	LogName = "transcript.log"
+
+	// IndexSuffix is appended to a log's name to name its index.
+	IndexSuffix = ".idx"
+)
+
+// MarkInterval is how long output goes on after a time mark before the next
+// one is recorded in the index, so a stream of output is indexed about
+// once a second rather than once per write.
+const MarkInterval = time.Second
+
+// Dir returns the transcript root, $GT_TRANSCRIPT_DIR or
+// ~/.gastown/transcripts. Empty if there is no home.
+func Dir() string {
+	if dir := os.Getenv(EnvDir); dir != "" {
+		return dir
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".gastown", "transcripts")
+}
+
+// SessionDir returns the directory holding session's transcript.
+func SessionDir(session string) string {
+	return filepath.Join(Dir(), session)
+}
+
+// Exists reports whether session has a transcript.
+func Exists(session string) bool {
+	_, err := os.Stat(filepath.Join(SessionDir(session), LogName))
+	return err == nil
+}
+
+// Enable starts recording session's pane output to its transcript. Session
+// start paths call it after theming and, as with theming, ignore the error:
+// a session without a transcript still works. Enabling an already recorded
+// session keeps its recorder.
+func Enable(t *tmux.Tmux, session string) error {
+	gt, err := gtBinary()
+	if err != nil {
+		return fmt.Errorf("finding gt to record the transcript: %w", err)
+	}
+	return t.PipePane(session, "exec "+config.ShellQuote(gt)+" transcript record "+session)
+}
+
+// gtBinary returns the gt binary that records transcripts: the running
+// executable when it is gt, else gt from PATH. (Checking the name keeps a
+// test binary from being run as the recorder.)
+func gtBinary() (string, error) {
+	if exe, err := os.Executable(); err == nil && strings.TrimSuffix(filepath.Base(exe), ".exe") == "gt" {
+		return exe, nil
+	}
+	return exec.LookPath("gt")
+}
+
+// Writer appends to a session's transcript, rotating it by size.
+// It is not safe for concurrent use; each session has one recorder.
+type Writer struct {
+	dir      string
+	maxBytes int64
+	keep     int
+	f        *os.File
+	idx      *os.File
+	size     int64
+	lastMark time.Time
+}
+
+// NewWriter opens (creating if needed) the transcript in dir. A non-positive
+// maxBytes or negative keep falls back to the defaults.
+func NewWriter(dir string, maxBytes int64, keep int) (*Writer, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+	if keep < 0 {
+		keep = DefaultKeep
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("creating transcript dir: %w", err)
+	}
+	w := &Writer{dir: dir, maxBytes: maxBytes, keep: keep}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(filepath.Join(w.dir, LogName), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("opening transcript: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("opening transcript: %w", err)
+	}
+	idx, err := os.OpenFile(filepath.Join(w.dir, LogName+IndexSuffix), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("opening transcript index: %w", err)
+	}
+	w.f, w.idx, w.size, w.lastMark = f, idx, info.Size(), time.Time{}
+	return nil
+}
+
+// Write appends p, rotating first if p would take the log past its limit.
+// A single write larger than the limit is kept whole in a fresh log.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.size > 0 && w.size+int64(len(p)) > w.maxBytes {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if now := time.Now(); now.Sub(w.lastMark) >= MarkInterval {
+		// The index is best-effort: output is never lost for want of a mark
+		if _, err := fmt.Fprintf(w.idx, "%d %d\n", now.UnixMilli(), w.size); err == nil {
+			w.lastMark = now
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the current log.
+func (w *Writer) Close() error {
+	_ = w.idx.Close()
+	return w.f.Close()
+}
+
+// rotate shifts transcript.log.N to .N+1, dropping any beyond keep, moves
+// the current log to .1 and reopens an empty one.
+func (w *Writer) rotate() error {
+	_ = w.idx.Close()
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	base := filepath.Join(w.dir, LogName)
+	rotated, err := rotatedLogs(w.dir)
+	if err != nil {
+		return err
+	}
+	// Highest first, so nothing is overwritten
+	for i := len(rotated) - 1; i >= 0; i-- {
+		n := rotated[i]
+		from := fmt.Sprintf("%s.%d", base, n)
+		if n >= w.keep {
+			if err := os.Remove(from); err != nil {
+				return fmt.Errorf("rotating transcript: %w", err)
+			}
+			_ = os.Remove(from + IndexSuffix)
+			continue
+		}
+		to := fmt.Sprintf("%s.%d", base, n+1)
+		if err := os.Rename(from, to); err != nil {
+			return fmt.Errorf("rotating transcript: %w", err)
+		}
+		_ = os.Rename(from+IndexSuffix, to+IndexSuffix)
+	}
+	if w.keep > 0 {
+		err = os.Rename(base, base+".1")
+		_ = os.Rename(base+IndexSuffix, base+".1"+IndexSuffix)
+	} else {
+		err = os.Remove(base)
+		_ = os.Remove(base + IndexSuffix)
+	}
+	if err != nil {
+		return fmt.Errorf("rotating transcript: %w", err)
+	}
+	return w.open()
+}
+
+var rotatedLogRe = regexp.MustCompile(`^` + regexp.QuoteMeta(LogName) + `\.(\d+)$`)
+
+// rotatedLogs returns the rotation numbers present in dir, ascending.
+func rotatedLogs(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var nums []int
+	for _, e := range entries {
+		if m := rotatedLogRe.FindStringSubmatch(e.Name()); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			nums = append(nums, n)
+		}
+	}
+	sort.Ints(nums)
+	return nums, nil
+}
+
+// Files returns session's transcript files oldest first, ending with the
+// current log. A session without a transcript yields an error.
+func Files(session string) ([]string, error) {
+	dir := SessionDir(session)
+	rotated, err := rotatedLogs(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no transcript for %s", session)
+		}
+		return nil, err
+	}
+	var files []string
+	for i := len(rotated) - 1; i >= 0; i-- {
+		files = append(files, filepath.Join(dir, fmt.Sprintf("%s.%d", LogName, rotated[i])))
+	}
+	if current := filepath.Join(dir, LogName); fileExists(current) {
+		files = append(files, current)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no transcript for %s", session)
+	}
+	return files, nil
+}
+
+// Chunk is output a session printed, starting at Time.
+type Chunk struct {
+	Time time.Time
+	Text string
+}
+
+// Chunks returns session's transcript split at its index marks, oldest
+// first. Output recorded without an index (before indexing, or if the
+// index couldn't be written) is dated by its log's modification time.
+func Chunks(session string) ([]Chunk, error) {
+	files, err := Files(session)
+	if err != nil {
+		return nil, err
+	}
+	var chunks []Chunk
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		marks := readIndex(path + IndexSuffix)
+		if len(marks) == 0 || marks[0].offset > 0 {
+			// Output ahead of the first mark
+			end := int64(len(data))
+			if len(marks) > 0 {
+				end = min(marks[0].offset, end)
+			}
+			if info, err := os.Stat(path); err == nil && end > 0 {
+				chunks = append(chunks, Chunk{Time: info.ModTime(), Text: string(data[:end])})
+			}
+		}
+		for i, m := range marks {
+			end := int64(len(data))
+			if i+1 < len(marks) {
+				end = min(marks[i+1].offset, end)
+			}
+			if m.offset >= end {
+				continue
+			}
+			chunks = append(chunks, Chunk{Time: m.time, Text: string(data[m.offset:end])})
+		}
+	}
+	return chunks, nil
+}
+
+// mark is an index entry: output from offset on was written at time.
+type mark struct {
+	time   time.Time
+	offset int64
+}
+
+// readIndex returns the marks in the index at path, skipping malformed
+// lines and ones out of order. A missing index has none.
+func readIndex(path string) []mark {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var marks []mark
+	for _, line := range strings.Split(string(data), "\n") {
+		var ms, offset int64
+		if _, err := fmt.Sscanf(line, "%d %d", &ms, &offset); err != nil {
+			continue
+		}
+		if len(marks) > 0 && offset < marks[len(marks)-1].offset {
+			continue
+		}
+		marks = append(marks, mark{time: time.UnixMilli(ms), offset: offset})
+	}
+	return marks
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// ansiRe matches the terminal escape sequences agent TUIs draw with: CSI
+// sequences (colors, cursor movement), OSC sequences (titles, hyperlinks)
+// and two-byte escapes such as keypad mode (ESC =).
+var ansiRe = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)|\x1b[0-Z\\-_]`)
+
+// StripANSI removes terminal escape sequences and carriage returns, leaving
+// the text an agent printed.
+func StripANSI(s string) string {
+	return strings.ReplaceAll(ansiRe.ReplaceAllString(s, ""), "\r", "")
+}
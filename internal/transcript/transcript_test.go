@@ -0,0 +1,191 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestEnable(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gt is a shell script")
+	}
+	var got []string
+	tm := tmux.NewTmux(tmux.WithRunner(func(args ...string) (string, string, error) {
+		got = args
+		return "", "", nil
+	}))
+
+	// The test binary isn't gt, so the recorder is gt from PATH
+	bin := filepath.Join(t.TempDir(), "my bin")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bin, "gt"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	if err := Enable(tm, "gt-web-crew-jane"); err != nil {
+		t.Fatalf("Enable: %v", err)
+	}
+	want := []string{"pipe-pane", "-o", "-t", "gt-web-crew-jane", "exec '" + filepath.Join(bin, "gt") + "' transcript record gt-web-crew-jane"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tmux args = %q, want %q", got, want)
+	}
+
+	got = nil
+	t.Setenv("PATH", t.TempDir())
+	if err := Enable(tm, "gt-web-crew-jane"); err == nil || got != nil {
+		t.Errorf("Enable without gt = %v, ran tmux %q; want an error and no pipe", err, got)
+	}
+}
+
+func TestWriterRotates(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	dir := SessionDir("gt-web-crew-jane")
+
+	w, err := NewWriter(dir, 10, 2)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, chunk := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := Files("gt-web-crew-jane")
+	if err != nil {
+		t.Fatalf("Files: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, LogName+".2"),
+		filepath.Join(dir, LogName+".1"),
+		filepath.Join(dir, LogName),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Fatalf("Files() = %q, want %q (oldest first, aaaaaa dropped)", files, want)
+	}
+	var got []string
+	for _, f := range files {
+		got = append(got, readFile(t, f))
+	}
+	if strings.Join(got, "") != "bbbbbb\ncccccc\ndddddd\n" {
+		t.Errorf("transcript = %q", got)
+	}
+}
+
+func TestWriterAppendsToExistingLog(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, LogName), []byte("before\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWriter(dir, 10, 1)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	// "before\n" already counts towards the limit, so this rotates
+	if _, err := w.Write([]byte("after\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	_ = w.Close()
+
+	if got := readFile(t, filepath.Join(dir, LogName+".1")); got != "before\n" {
+		t.Errorf("rotated log = %q, want the previous session's output", got)
+	}
+	if got := readFile(t, filepath.Join(dir, LogName)); got != "after\n" {
+		t.Errorf("current log = %q", got)
+	}
+}
+
+func TestFilesMissing(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	if _, err := Files("gt-nope"); err == nil || !strings.Contains(err.Error(), "no transcript for gt-nope") {
+		t.Errorf("Files() error = %v, want no transcript", err)
+	}
+	if Exists("gt-nope") {
+		t.Error("Exists() = true for a session without a transcript")
+	}
+}
+
+func TestStripANSI(t *testing.T) {
+	in := "\x1b[1;32m✓\x1b[0m done\r\n\x1b]0;claude\x07\x1b[2K> prompt\x1b=\n"
+	if got, want := StripANSI(in), "✓ done\n> prompt\n"; got != want {
+		t.Errorf("StripANSI() = %q, want %q", got, want)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	t.Setenv(EnvDir, t.TempDir())
+	dir := SessionDir("gt-web-crew-jane")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	// Output recorded before indexing, then two marked writes
+	if err := os.WriteFile(filepath.Join(dir, LogName), []byte("old\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	index := "1700000000000 4\n1700000005000 10\n"
+	if err := os.WriteFile(filepath.Join(dir, LogName+IndexSuffix), []byte(index), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(filepath.Join(dir, LogName), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("first\nsecond\n")
+	_ = f.Close()
+
+	chunks, err := Chunks("gt-web-crew-jane")
+	if err != nil {
+		t.Fatalf("Chunks: %v", err)
+	}
+	var texts []string
+	for _, c := range chunks {
+		texts = append(texts, c.Text)
+	}
+	if want := []string{"old\n", "first\n", "second\n"}; !reflect.DeepEqual(texts, want) {
+		t.Fatalf("Chunks() texts = %q, want %q", texts, want)
+	}
+	if got := chunks[2].Time.UnixMilli(); got != 1700000005000 {
+		t.Errorf("second chunk time = %d, want the mark's", got)
+	}
+}
+
+func TestWriterIndexRotates(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewWriter(dir, 10, 1)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	for _, chunk := range []string{"aaaaaa\n", "bbbbbb\n"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	_ = w.Close()
+
+	// Each log starts with a mark at offset 0
+	for _, name := range []string{LogName, LogName + ".1"} {
+		if got := readIndex(filepath.Join(dir, name+IndexSuffix)); len(got) != 1 || got[0].offset != 0 {
+			t.Errorf("index of %s = %v, want one mark at offset 0", name, got)
+		}
+	}
+}
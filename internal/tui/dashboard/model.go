@@ -0,0 +1,245 @@
+// Package dashboard is the gt dashboard TUI: a live table of every agent
+// session with its role, agent, current bead, last activity and health.
+package dashboard
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultInterval is how often the dashboard reloads its sessions.
+const DefaultInterval = 5 * time.Second
+
+// Row is one agent session on the dashboard.
+type Row struct {
+	Session   string
+	Address   string // e.g. gastown/crew/max
+	Role      string
+	Agent     string
+	Bead      string // Hooked bead ID, empty when idle
+	BeadTitle string
+	Activity  time.Time // Last pane output; zero if unknown
+	Health    string    // healthy, degraded or dead
+	Detail    string    // Why the session isn't healthy
+}
+
+// Config wires the dashboard to Gas Town. Load is required; a nil action
+// disables its key.
+type Config struct {
+	// Load returns the current sessions.
+	Load func() ([]Row, error)
+
+	// Attach returns the command that attaches the terminal to a session,
+	// run with the dashboard suspended.
+	Attach func(Row) *exec.Cmd
+
+	// Handoff hands a session off to a fresh agent.
+	Handoff func(Row) error
+
+	// Kill tears a session down.
+	Kill func(Row) error
+
+	// Interval is the reload period; zero means DefaultInterval.
+	Interval time.Duration
+}
+
+// Model is the bubbletea model for the dashboard TUI.
+type Model struct {
+	cfg     Config
+	rows    []Row
+	cursor  int
+	err     error
+	status  string    // Outcome of the last action
+	confirm string    // Session awaiting kill confirmation
+	loaded  time.Time // When rows were loaded
+
+	// UI state
+	keys     KeyMap
+	help     help.Model
+	showHelp bool
+	width    int
+	height   int
+}
+
+// New creates a new dashboard model.
+func New(cfg Config) Model {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return Model{
+		cfg:  cfg,
+		keys: DefaultKeyMap(),
+		help: help.New(),
+	}
+}
+
+// loadMsg is the result of loading the sessions.
+type loadMsg struct {
+	rows []Row
+	err  error
+}
+
+// tickMsg triggers a periodic reload.
+type tickMsg time.Time
+
+// actionMsg reports the outcome of an attach, handoff or kill.
+type actionMsg struct {
+	done string
+	err  error
+}
+
+// Init initializes the model.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.load, m.tick())
+}
+
+func (m Model) load() tea.Msg {
+	rows, err := m.cfg.Load()
+	return loadMsg{rows: rows, err: err}
+}
+
+func (m Model) tick() tea.Cmd {
+	return tea.Tick(m.cfg.Interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+// selected returns the row under the cursor.
+func (m Model) selected() (Row, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return Row{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+// Update handles messages.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.help.Width = msg.Width
+		return m, nil
+
+	case loadMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.rows = msg.rows
+			m.loaded = time.Now()
+		}
+		m.clampCursor()
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.load, m.tick())
+
+	case actionMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Error: %v", msg.err)
+		} else {
+			m.status = msg.done
+		}
+		return m, m.load
+
+	case tea.KeyMsg:
+		if m.confirm != "" {
+			return m.updateConfirm(msg)
+		}
+		return m.updateKey(msg)
+	}
+
+	return m, nil
+}
+
+func (m Model) updateKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Quit):
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Help):
+		m.showHelp = !m.showHelp
+
+	case key.Matches(msg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+
+	case key.Matches(msg, m.keys.Top):
+		m.cursor = 0
+
+	case key.Matches(msg, m.keys.Bottom):
+		m.cursor = len(m.rows) - 1
+		m.clampCursor()
+
+	case key.Matches(msg, m.keys.Refresh):
+		m.status = ""
+		return m, m.load
+
+	case key.Matches(msg, m.keys.Attach):
+		row, ok := m.selected()
+		if !ok || m.cfg.Attach == nil {
+			return m, nil
+		}
+		return m, tea.ExecProcess(m.cfg.Attach(row), func(err error) tea.Msg {
+			return actionMsg{done: "Detached from " + row.Session, err: err}
+		})
+
+	case key.Matches(msg, m.keys.Handoff):
+		row, ok := m.selected()
+		if !ok || m.cfg.Handoff == nil {
+			return m, nil
+		}
+		m.status = "Handing off " + row.Session + "..."
+		return m, func() tea.Msg {
+			return actionMsg{done: "Handed off " + row.Session, err: m.cfg.Handoff(row)}
+		}
+
+	case key.Matches(msg, m.keys.Kill):
+		if row, ok := m.selected(); ok && m.cfg.Kill != nil {
+			m.confirm = row.Session
+		}
+	}
+	return m, nil
+}
+
+// updateConfirm handles the answer to "kill <session>?".
+func (m Model) updateConfirm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	session := m.confirm
+	m.confirm = ""
+	if msg.String() != "y" && msg.String() != "Y" {
+		m.status = "Kill cancelled"
+		return m, nil
+	}
+	for _, row := range m.rows {
+		if row.Session == session {
+			m.status = "Killing " + session + "..."
+			return m, func() tea.Msg {
+				return actionMsg{done: "Killed " + session, err: m.cfg.Kill(row)}
+			}
+		}
+	}
+	return m, nil
+}
+
+// clampCursor keeps the cursor on a row after the rows change.
+func (m *Model) clampCursor() {
+	if m.cursor >= len(m.rows) {
+		m.cursor = len(m.rows) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+// View renders the model.
+func (m Model) View() string {
+	return m.renderView()
+}
@@ -0,0 +1,212 @@
+package dashboard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styles for the dashboard TUI
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("12"))
+
+	headerStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("8"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Background(lipgloss.Color("236")).
+			Foreground(lipgloss.Color("15"))
+
+	healthyStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10")) // green
+
+	degradedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11")) // yellow
+
+	deadStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+
+	dimStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")) // gray
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")) // red
+)
+
+// Column widths, in runes.
+const (
+	colSession  = 28
+	colRole     = 9
+	colAgent    = 10
+	colBead     = 34
+	colActivity = 9
+)
+
+// renderView renders the entire view.
+func (m Model) renderView() string {
+	var b strings.Builder
+
+	// Title
+	b.WriteString(titleStyle.Render("Gas Town Dashboard"))
+	b.WriteString(dimStyle.Render(fmt.Sprintf("  %s — refreshed every %s", summarize(m.rows), m.cfg.Interval)))
+	b.WriteString("\n\n")
+
+	// Error message
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Error: %v", m.err)))
+		b.WriteString("\n\n")
+	}
+
+	// Empty state
+	if len(m.rows) == 0 && m.err == nil {
+		if m.loaded.IsZero() {
+			b.WriteString("Loading sessions...\n")
+		} else {
+			b.WriteString("No agent sessions running.\n")
+			b.WriteString("Start agents with: gt up\n")
+		}
+	}
+
+	if len(m.rows) > 0 {
+		b.WriteString(headerStyle.Render(formatRow("SESSION", "ROLE", "AGENT", "BEAD", "ACTIVE", "HEALTH")))
+		b.WriteString("\n")
+	}
+	now := time.Now()
+	for i, r := range m.rows {
+		bead := r.Bead
+		if r.BeadTitle != "" {
+			bead += " " + r.BeadTitle
+		}
+		if bead == "" {
+			bead = "-"
+		}
+		agent := r.Agent
+		if agent == "" {
+			agent = "-"
+		}
+		line := formatRow(r.Session, r.Role, agent, bead, formatAge(r.Activity, now), "")
+		if i == m.cursor {
+			b.WriteString(selectedStyle.Render(line))
+		} else {
+			b.WriteString(line)
+		}
+		b.WriteString(healthStyle(r.Health).Render(healthIcon(r.Health) + " " + r.Health))
+		b.WriteString("\n")
+	}
+
+	// Details of the selection
+	if row, ok := m.selected(); ok && row.Detail != "" {
+		b.WriteString("\n")
+		b.WriteString(dimStyle.Render(row.Session + ": " + row.Detail))
+		b.WriteString("\n")
+	}
+
+	// Confirmation or action status
+	b.WriteString("\n")
+	switch {
+	case m.confirm != "":
+		b.WriteString(degradedStyle.Render(fmt.Sprintf("Kill %s? (y/N)", m.confirm)))
+		b.WriteString("\n")
+	case m.status != "":
+		style := dimStyle
+		if strings.HasPrefix(m.status, "Error:") {
+			style = errorStyle
+		}
+		b.WriteString(style.Render(m.status))
+		b.WriteString("\n")
+	}
+
+	// Help footer
+	if m.showHelp {
+		b.WriteString(m.help.View(m.keys))
+	} else {
+		b.WriteString(dimStyle.Render("j/k:navigate  enter:attach  h:handoff  x:kill  r:refresh  q:quit  ?:help"))
+	}
+
+	return b.String()
+}
+
+// formatRow lays out the table columns; health is rendered separately.
+func formatRow(session, role, agent, bead, activity, health string) string {
+	return fmt.Sprintf("%s %s %s %s %s %s",
+		pad(session, colSession), pad(role, colRole), pad(agent, colAgent),
+		pad(bead, colBead), pad(activity, colActivity), health)
+}
+
+// pad truncates or right-pads s to width runes.
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+// summarize counts the sessions by health, e.g. "6 session(s), 1 dead".
+func summarize(rows []Row) string {
+	counts := make(map[string]int)
+	for _, r := range rows {
+		counts[r.Health]++
+	}
+	s := fmt.Sprintf("%d session(s)", len(rows))
+	for _, h := range []string{"degraded", "dead"} {
+		if counts[h] > 0 {
+			s += fmt.Sprintf(", %d %s", counts[h], h)
+		}
+	}
+	return s
+}
+
+func healthStyle(health string) lipgloss.Style {
+	switch health {
+	case "healthy":
+		return healthyStyle
+	case "dead":
+		return deadStyle
+	default:
+		return degradedStyle
+	}
+}
+
+func healthIcon(health string) string {
+	switch health {
+	case "healthy":
+		return "●"
+	case "dead":
+		return "✗"
+	default:
+		return "◐"
+	}
+}
+
+// formatAge renders how long ago t was: "12s", "5m", "3h", "2d"; "-" if unknown.
+func formatAge(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// truncate shortens a string to the given rune length, preserving UTF-8.
+func truncate(s string, maxLen int) string {
+	if utf8.RuneCountInString(s) <= maxLen {
+		return s
+	}
+	runes := []rune(s)
+	if maxLen <= 3 {
+		return "..."
+	}
+	return string(runes[:maxLen-3]) + "..."
+}
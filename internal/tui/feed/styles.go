@@ -156,5 +156,7 @@ var (
 		"nudge":   "⚡",
 		"boot":    "🔌",
 		"halt":    "⏹",
+		// Work handed back (gt bead handback)
+		"handback": "↪",
 	}
 )
@@ -0,0 +1,341 @@
+// Package usage tracks token usage and cost of agent sessions.
+//
+// Token counts are parsed from an agent's JSONL transcript (Claude Code's
+// project transcripts, or any agent whose transcript lines carry an
+// OpenAI-style usage object, such as Kimi) and appended to a ledger at
+// ~/.gt/usage.jsonl as Records. Each Record is a cumulative snapshot of one
+// transcript, so reports count the latest snapshot of each transcript once.
+package usage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tokens is the token usage summed over a transcript.
+type Tokens struct {
+	Model       string `json:"model,omitempty"`
+	Input       int    `json:"input_tokens"`
+	Output      int    `json:"output_tokens"`
+	CacheRead   int    `json:"cache_read_tokens,omitempty"`
+	CacheCreate int    `json:"cache_create_tokens,omitempty"`
+}
+
+// Total returns every token counted, cached or not.
+func (t *Tokens) Total() int {
+	return t.Input + t.Output + t.CacheRead + t.CacheCreate
+}
+
+// transcriptLine covers the transcript formats usage is read from: Claude
+// Code puts usage on assistant messages, OpenAI-compatible agents on the line
+// or its message.
+type transcriptLine struct {
+	Type    string       `json:"type"`
+	Model   string       `json:"model"`
+	Usage   *lineUsage   `json:"usage"`
+	Message *lineMessage `json:"message"`
+}
+
+type lineMessage struct {
+	Model string     `json:"model"`
+	Usage *lineUsage `json:"usage"`
+}
+
+type lineUsage struct {
+	// Anthropic
+	InputTokens              int `json:"input_tokens"`
+	OutputTokens             int `json:"output_tokens"`
+	CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+	CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
+
+	// OpenAI-compatible (Kimi, Codex)
+	PromptTokens        int `json:"prompt_tokens"`
+	CompletionTokens    int `json:"completion_tokens"`
+	CachedTokens        int `json:"cached_tokens"`
+	PromptTokensDetails *struct {
+		CachedTokens int `json:"cached_tokens"`
+	} `json:"prompt_tokens_details"`
+}
+
+// ParseTranscript sums the token usage reported in a JSONL transcript.
+// Lines that aren't JSON or carry no usage are skipped; the model is the
+// first one reported.
+func ParseTranscript(path string) (*Tokens, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tokens := &Tokens{}
+	scanner := bufio.NewScanner(f)
+	// Transcript lines can hold whole tool outputs
+	scanner.Buffer(make([]byte, 0, 256*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var line transcriptLine
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		u, model := line.Usage, line.Model
+		if line.Message != nil && line.Message.Usage != nil {
+			u, model = line.Message.Usage, line.Message.Model
+		}
+		if u == nil || line.Type == "user" {
+			continue
+		}
+		if tokens.Model == "" {
+			tokens.Model = model
+		}
+		tokens.add(u)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (t *Tokens) add(u *lineUsage) {
+	if u.PromptTokens == 0 && u.CompletionTokens == 0 {
+		t.Input += u.InputTokens
+		t.Output += u.OutputTokens
+		t.CacheRead += u.CacheReadInputTokens
+		t.CacheCreate += u.CacheCreationInputTokens
+		return
+	}
+	// prompt_tokens includes the cached ones; count those as cache reads
+	cached := u.CachedTokens
+	if u.PromptTokensDetails != nil && u.PromptTokensDetails.CachedTokens > 0 {
+		cached = u.PromptTokensDetails.CachedTokens
+	}
+	t.Input += u.PromptTokens - cached
+	t.CacheRead += cached
+	t.Output += u.CompletionTokens
+}
+
+// Pricing is the USD price per million tokens of a model.
+type Pricing struct {
+	Input       float64
+	Output      float64
+	CacheRead   float64
+	CacheCreate float64
+}
+
+// modelPricing is keyed by model name prefix; the longest matching prefix
+// wins. Prices are list prices as of early 2026.
+var modelPricing = map[string]Pricing{
+	"claude-opus-4-5":  {15.0, 75.0, 1.5, 18.75},
+	"claude-sonnet-4":  {3.0, 15.0, 0.3, 3.75},
+	"claude-3-5-haiku": {1.0, 5.0, 0.1, 1.25},
+	"kimi-k2":          {0.60, 2.50, 0.15, 0.60},
+	"kimi-k2-turbo":    {2.40, 10.0, 0.60, 2.40},
+	"kimi-for-coding":  {0.60, 2.50, 0.15, 0.60},
+	"moonshot-v1":      {2.0, 5.0, 2.0, 2.0},
+}
+
+// defaultPricing is used for models not in modelPricing (Sonnet pricing).
+var defaultPricing = Pricing{3.0, 15.0, 0.3, 3.75}
+
+// PricingFor returns the pricing for model.
+func PricingFor(model string) Pricing {
+	best, bestLen := defaultPricing, 0
+	for prefix, p := range modelPricing {
+		if strings.HasPrefix(model, prefix) && len(prefix) > bestLen {
+			best, bestLen = p, len(prefix)
+		}
+	}
+	return best
+}
+
+// Cost converts token usage to USD at its model's pricing.
+func Cost(t *Tokens) float64 {
+	if t == nil {
+		return 0
+	}
+	p := PricingFor(t.Model)
+	return (float64(t.Input)*p.Input +
+		float64(t.Output)*p.Output +
+		float64(t.CacheRead)*p.CacheRead +
+		float64(t.CacheCreate)*p.CacheCreate) / 1_000_000
+}
+
+// Record is one usage snapshot of a session's transcript.
+type Record struct {
+	Session    string `json:"session"`
+	Agent      string `json:"agent"`
+	Role       string `json:"role"`
+	Rig        string `json:"rig,omitempty"`
+	Worker     string `json:"worker,omitempty"`
+	Transcript string `json:"transcript,omitempty"`
+	Tokens
+	CostUSD    float64   `json:"cost_usd"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+// LogPath returns the usage ledger, ~/.gt/usage.jsonl next to the costs log.
+func LogPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "gt-usage.jsonl")
+	}
+	return filepath.Join(home, ".gt", "usage.jsonl")
+}
+
+// Append adds rec to the ledger at path.
+func Append(path string, rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshaling usage record: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating usage log directory: %w", err)
+	}
+	// One small O_APPEND write per record, so concurrent hooks don't interleave
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("opening usage log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing usage log: %w", err)
+	}
+	return nil
+}
+
+// Load reads the ledger at path, keeping the latest snapshot of each
+// transcript recorded at or after since. A missing ledger is empty.
+func Load(path string, since time.Time) ([]Record, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	latest := make(map[string]Record)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			continue // Skip malformed lines
+		}
+		key := rec.Session + "\x00" + rec.Transcript
+		if prev, ok := latest[key]; !ok || !rec.RecordedAt.Before(prev.RecordedAt) {
+			latest[key] = rec
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, rec := range latest {
+		if !rec.RecordedAt.Before(since) {
+			records = append(records, rec)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].RecordedAt.Before(records[j].RecordedAt) })
+	return records, nil
+}
+
+// Dimension is what a report groups records by.
+type Dimension string
+
+const (
+	BySession Dimension = "session"
+	ByAgent   Dimension = "agent"
+	ByRig     Dimension = "rig"
+	ByRole    Dimension = "role"
+	ByModel   Dimension = "model"
+	ByDay     Dimension = "day"
+)
+
+// Dimensions lists every Dimension, in the order the CLI documents them.
+var Dimensions = []Dimension{BySession, ByAgent, ByRig, ByRole, ByModel, ByDay}
+
+// ParseDimension validates a --by value.
+func ParseDimension(s string) (Dimension, error) {
+	for _, d := range Dimensions {
+		if string(d) == s {
+			return d, nil
+		}
+	}
+	names := make([]string, len(Dimensions))
+	for i, d := range Dimensions {
+		names[i] = string(d)
+	}
+	return "", fmt.Errorf("unknown grouping %q (want one of: %s)", s, strings.Join(names, ", "))
+}
+
+func (d Dimension) key(rec Record) string {
+	var k string
+	switch d {
+	case BySession:
+		k = rec.Session
+	case ByAgent:
+		k = rec.Agent
+	case ByRig:
+		k = rec.Rig
+	case ByRole:
+		k = rec.Role
+	case ByModel:
+		k = rec.Model
+	case ByDay:
+		k = rec.RecordedAt.Local().Format("2006-01-02")
+	}
+	if k == "" {
+		return "(none)"
+	}
+	return k
+}
+
+// Row is one group of a report.
+type Row struct {
+	Key      string  `json:"key"`
+	Sessions int     `json:"sessions"`
+	Tokens   Tokens  `json:"tokens"`
+	CostUSD  float64 `json:"cost_usd"`
+}
+
+// Aggregate groups records by d. Rows are sorted by cost, highest first,
+// except by day, which is chronological.
+func Aggregate(records []Record, d Dimension) []Row {
+	rows := make(map[string]*Row)
+	sessions := make(map[string]map[string]bool)
+	for _, rec := range records {
+		k := d.key(rec)
+		row, ok := rows[k]
+		if !ok {
+			row = &Row{Key: k}
+			rows[k] = row
+			sessions[k] = make(map[string]bool)
+		}
+		row.Tokens.Input += rec.Input
+		row.Tokens.Output += rec.Output
+		row.Tokens.CacheRead += rec.CacheRead
+		row.Tokens.CacheCreate += rec.CacheCreate
+		row.CostUSD += rec.CostUSD
+		sessions[k][rec.Session] = true
+	}
+
+	result := make([]Row, 0, len(rows))
+	for k, row := range rows {
+		row.Sessions = len(sessions[k])
+		result = append(result, *row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if d == ByDay || result[i].CostUSD == result[j].CostUSD {
+			return result[i].Key < result[j].Key
+		}
+		return result[i].CostUSD > result[j].CostUSD
+	})
+	return result
+}
@@ -0,0 +1,123 @@
+package usage
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func writeTranscript(t *testing.T, lines string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(lines), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseTranscriptClaude(t *testing.T) {
+	path := writeTranscript(t, `{"type":"user","message":{"role":"user","content":"hi"}}
+{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":10,"cache_creation_input_tokens":100,"cache_read_input_tokens":1000,"output_tokens":50}}}
+not json
+{"type":"assistant","message":{"model":"claude-sonnet-4-20250514","usage":{"input_tokens":5,"cache_read_input_tokens":2000,"output_tokens":25}}}
+`)
+	got, err := ParseTranscript(path)
+	if err != nil {
+		t.Fatalf("ParseTranscript: %v", err)
+	}
+	want := &Tokens{Model: "claude-sonnet-4-20250514", Input: 15, Output: 75, CacheRead: 3000, CacheCreate: 100}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTranscript() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTranscriptOpenAICompatible(t *testing.T) {
+	// Kimi reports prompt tokens including the cached ones
+	path := writeTranscript(t, `{"role":"assistant","model":"kimi-k2-0905-preview","usage":{"prompt_tokens":1200,"completion_tokens":300,"cached_tokens":1000}}
+{"role":"assistant","message":{"model":"kimi-k2-0905-preview","usage":{"prompt_tokens":400,"completion_tokens":100,"prompt_tokens_details":{"cached_tokens":300}}}}
+`)
+	got, err := ParseTranscript(path)
+	if err != nil {
+		t.Fatalf("ParseTranscript: %v", err)
+	}
+	want := &Tokens{Model: "kimi-k2-0905-preview", Input: 300, Output: 400, CacheRead: 1300}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseTranscript() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		tokens Tokens
+		want   float64
+	}{
+		{Tokens{Model: "claude-opus-4-5-20251101", Input: 1_000_000, Output: 1_000_000}, 90},
+		{Tokens{Model: "kimi-k2-0905-preview", Input: 1_000_000, Output: 1_000_000, CacheRead: 1_000_000}, 3.25},
+		{Tokens{Model: "kimi-k2-turbo-preview", Output: 1_000_000}, 10},
+		{Tokens{Model: "some-new-model", Input: 1_000_000}, 3}, // Sonnet pricing
+	}
+	for _, tt := range tests {
+		if got := Cost(&tt.tokens); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Cost(%+v) = %v, want %v", tt.tokens, got, tt.want)
+		}
+	}
+	if Cost(nil) != 0 {
+		t.Error("Cost(nil) != 0")
+	}
+}
+
+func TestLoadKeepsLatestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.jsonl")
+	now := time.Now()
+	for _, rec := range []Record{
+		{Session: "gt-web-crew-jane", Agent: "claude", Transcript: "a.jsonl", Tokens: Tokens{Output: 10}, CostUSD: 1, RecordedAt: now.Add(-2 * time.Hour)},
+		{Session: "gt-web-crew-jane", Agent: "claude", Transcript: "a.jsonl", Tokens: Tokens{Output: 30}, CostUSD: 3, RecordedAt: now.Add(-time.Hour)},
+		{Session: "gt-web-crew-jane", Agent: "claude", Transcript: "b.jsonl", Tokens: Tokens{Output: 5}, CostUSD: 0.5, RecordedAt: now.Add(-time.Hour)},
+		{Session: "gt-web-max", Agent: "kimi", Transcript: "c.jsonl", Tokens: Tokens{Output: 20}, CostUSD: 0.25, RecordedAt: now.Add(-30 * time.Minute)},
+		{Session: "hq-mayor", Agent: "claude", Transcript: "old.jsonl", CostUSD: 100, RecordedAt: now.Add(-10 * 24 * time.Hour)},
+	} {
+		if err := Append(path, rec); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	records, err := Load(path, now.Add(-7*24*time.Hour))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Load() returned %d records, want 3 (latest a.jsonl, b.jsonl, c.jsonl)", len(records))
+	}
+
+	rows := Aggregate(records, ByAgent)
+	want := []Row{
+		{Key: "claude", Sessions: 1, Tokens: Tokens{Output: 35}, CostUSD: 3.5},
+		{Key: "kimi", Sessions: 1, Tokens: Tokens{Output: 20}, CostUSD: 0.25},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("Aggregate(ByAgent) = %+v, want %+v", rows, want)
+	}
+
+	if rows := Aggregate(records, ByRig); len(rows) != 1 || rows[0].Key != "(none)" {
+		t.Errorf("Aggregate(ByRig) = %+v, want one (none) row", rows)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	records, err := Load(filepath.Join(t.TempDir(), "usage.jsonl"), time.Time{})
+	if err != nil || records != nil {
+		t.Errorf("Load() = %v, %v; want nil, nil", records, err)
+	}
+}
+
+func TestParseDimension(t *testing.T) {
+	if d, err := ParseDimension("day"); err != nil || d != ByDay {
+		t.Errorf("ParseDimension(day) = %q, %v", d, err)
+	}
+	if _, err := ParseDimension("color"); err == nil {
+		t.Error("ParseDimension(color) succeeded")
+	}
+}
@@ -0,0 +1,243 @@
+package watchdog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/steveyegge/gastown/internal/beads"
+	"github.com/steveyegge/gastown/internal/events"
+)
+
+// Metrics exports fleet health in the Prometheus text format: what the
+// watchdog sees on each poll (sessions, deaths, restarts, failures), plus
+// handoffs and bead stage moves counted from the town's events feed and
+// bead lifecycle log. A Metrics is an http.Handler for /metrics.
+type Metrics struct {
+	mu       sync.Mutex
+	families []*metricFamily
+
+	sessions     *metricFamily
+	deaths       *metricFamily
+	restarts     *metricFamily
+	throttled    *metricFamily
+	stuck        *metricFamily
+	errors       *metricFamily
+	scheduled    *metricFamily
+	polls        *metricFamily
+	pollErrors   *metricFamily
+	handoffs     *metricFamily
+	transitions  *metricFamily
+	beadsByStage *metricFamily
+
+	feed       jsonlTail
+	lifecycle  jsonlTail
+	beadStages map[string]beads.Stage
+}
+
+// NewMetrics creates the metrics of the town at townRoot.
+func NewMetrics(townRoot string) *Metrics {
+	m := &Metrics{
+		feed:       jsonlTail{path: filepath.Join(townRoot, events.EventsFile)},
+		lifecycle:  jsonlTail{path: beads.LifecyclePath(townRoot)},
+		beadStages: make(map[string]beads.Stage),
+	}
+	m.sessions = m.family("gt_agent_sessions", "gauge", "Agent sessions at the last poll, by role and whether the agent was alive.")
+	m.deaths = m.family("gt_agent_deaths_total", "counter", "Agents found dead in their sessions.")
+	m.restarts = m.family("gt_agent_restarts_total", "counter", "Dead agents restarted by the watchdog.")
+	m.throttled = m.family("gt_agent_restarts_throttled_total", "counter", "Restarts skipped because the session hit max_restarts within the hour.")
+	m.stuck = m.family("gt_agent_stuck_total", "counter", "Live agents found waiting for input or stopped on an error.")
+	m.errors = m.family("gt_agent_errors_total", "counter", "Failed watchdog restarts, scheduled handoffs and witness reports, by session.")
+	m.scheduled = m.family("gt_scheduled_handoffs_total", "counter", "Scheduled handoffs run by the watchdog.")
+	m.polls = m.family("gt_watchdog_polls_total", "counter", "Watchdog polls.")
+	m.pollErrors = m.family("gt_watchdog_poll_errors_total", "counter", "Watchdog polls that failed.")
+	m.handoffs = m.family("gt_handoffs_total", "counter", "Handoffs recorded in the events feed, by agent.")
+	m.transitions = m.family("gt_bead_transitions_total", "counter", "Bead lifecycle moves, by the stage moved to.")
+	m.beadsByStage = m.family("gt_beads", "gauge", "Tracked beads in each lifecycle stage.")
+	m.polls.set(0)
+	m.pollErrors.set(0)
+	return m
+}
+
+func (m *Metrics) family(name, typ, help string) *metricFamily {
+	f := &metricFamily{name: name, typ: typ, help: help, series: make(map[string]float64)}
+	m.families = append(m.families, f)
+	return f
+}
+
+// observePoll records a poll: the agent sessions seen by role, alive or
+// dead, and the actions taken. Methods of a nil Metrics do nothing.
+func (m *Metrics) observePoll(alive, dead map[string]int, actions []Action, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.polls.add(1)
+	if err != nil {
+		m.pollErrors.add(1)
+	}
+	clear(m.sessions.series)
+	for role, n := range alive {
+		m.sessions.set(float64(n), "role", role, "state", "alive")
+	}
+	for role, n := range dead {
+		m.sessions.set(float64(n), "role", role, "state", "dead")
+	}
+
+	for _, a := range actions {
+		if a.Stuck != "" {
+			if a.StuckFor == 0 { // Not again when it has been stuck for long
+				m.stuck.add(1, "session", a.Session, "role", a.Role, "status", string(a.Stuck))
+			}
+			continue
+		}
+		if a.Report != "" {
+			if a.Err != nil {
+				m.errors.add(1, "session", a.Session, "role", a.Role, "op", "report")
+			}
+			continue
+		}
+		if a.Schedule != "" {
+			m.scheduled.add(1, "role", a.Role)
+			if a.Err != nil {
+				m.errors.add(1, "session", a.Session, "role", a.Role, "op", "handoff")
+			}
+			continue
+		}
+		m.deaths.add(1, "session", a.Session, "role", a.Role)
+		switch {
+		case a.Restarted:
+			m.restarts.add(1, "role", a.Role, "policy", a.Policy)
+		case a.Err != nil:
+			m.errors.add(1, "session", a.Session, "role", a.Role, "op", "restart")
+		case a.Throttled:
+			m.throttled.add(1, "session", a.Session, "role", a.Role)
+		}
+	}
+}
+
+// refresh counts the events feed and lifecycle log lines written since the
+// last scrape.
+func (m *Metrics) refresh() {
+	_ = m.feed.read(func(line []byte) {
+		var e events.Event
+		if json.Unmarshal(line, &e) == nil && e.Type == events.TypeHandoff {
+			m.handoffs.add(1, "agent", e.Actor)
+		}
+	})
+	_ = m.lifecycle.read(func(line []byte) {
+		var t beads.Transition
+		if json.Unmarshal(line, &t) != nil || t.Bead == "" {
+			return
+		}
+		m.transitions.add(1, "stage", string(t.To))
+		m.beadStages[t.Bead] = t.To
+	})
+	clear(m.beadsByStage.series)
+	for _, stage := range beads.Stages {
+		m.beadsByStage.set(0, "stage", string(stage))
+	}
+	for _, stage := range m.beadStages {
+		m.beadsByStage.add(1, "stage", string(stage))
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.refresh()
+	var buf bytes.Buffer
+	for _, f := range m.families {
+		f.write(&buf)
+	}
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
+
+// metricFamily is one metric and its series, keyed by rendered label set.
+type metricFamily struct {
+	name, typ, help string
+	series          map[string]float64
+}
+
+func (f *metricFamily) add(v float64, labels ...string) {
+	f.series[renderLabels(labels)] += v
+}
+
+func (f *metricFamily) set(v float64, labels ...string) {
+	f.series[renderLabels(labels)] = v
+}
+
+func (f *metricFamily) write(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.typ)
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(w, "%s%s %g\n", f.name, k, f.series[k])
+	}
+}
+
+// renderLabels renders name/value pairs as a Prometheus label set,
+// e.g. {role="crew",state="alive"}.
+func renderLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", pairs[i], escape.Replace(pairs[i+1]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// jsonlTail reads the lines appended to a JSONL file since the last read.
+type jsonlTail struct {
+	path   string
+	offset int64
+}
+
+// read passes each complete line written since the last read to fn. A
+// file that shrank (rotated or truncated) is read again from the start.
+func (t *jsonlTail) read(fn func(line []byte)) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() < t.offset {
+		t.offset = 0
+	}
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReaderSize(f, 64*1024)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			return nil // A partial last line is read once complete
+		}
+		t.offset += int64(len(line))
+		fn(bytes.TrimSpace(line))
+	}
+}
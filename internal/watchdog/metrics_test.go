@@ -0,0 +1,121 @@
+package watchdog
+
+import (
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/beads"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q, want the Prometheus text format", ct)
+	}
+	return rec.Body.String()
+}
+
+func wantSamples(t *testing.T, body string, samples ...string) {
+	t.Helper()
+	for _, s := range samples {
+		if !strings.Contains(body, "\n"+s+"\n") {
+			t.Errorf("metrics missing %q:\n%s", s, body)
+		}
+	}
+}
+
+func TestMetrics_Poll(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"gt-gastown-crew-max", "gt-gastown-witness"},
+		alive:    map[string]bool{"gt-gastown-witness": true},
+	}
+	w, rec, _ := newTestWatchdog(t, nil, src)
+	w.Metrics = NewMetrics(w.townRoot)
+	pollN(t, w, DeadPolls)
+
+	wantSamples(t, scrape(t, w.Metrics),
+		"# TYPE gt_agent_restarts_total counter",
+		`gt_agent_sessions{role="crew",state="dead"} 1`,
+		`gt_agent_sessions{role="witness",state="alive"} 1`,
+		`gt_agent_deaths_total{session="gt-gastown-crew-max",role="crew"} 1`,
+		`gt_agent_restarts_total{role="crew",policy="resume"} 1`,
+		`gt_watchdog_polls_total 2`,
+		`gt_watchdog_poll_errors_total 0`,
+	)
+
+	// Max is back, and a failed restart counts as an error of its session
+	src.alive["gt-gastown-crew-max"] = true
+	src.sessions = append(src.sessions, "gt-gastown-crew-joe")
+	rec.err = errors.New("no pane")
+	pollN(t, w, DeadPolls)
+
+	body := scrape(t, w.Metrics)
+	wantSamples(t, body,
+		`gt_agent_sessions{role="crew",state="alive"} 1`,
+		`gt_agent_sessions{role="crew",state="dead"} 1`,
+		`gt_agent_errors_total{session="gt-gastown-crew-joe",role="crew",op="restart"} 1`,
+		`gt_agent_restarts_total{role="crew",policy="resume"} 1`,
+		`gt_watchdog_polls_total 4`,
+	)
+}
+
+func TestMetrics_FeedAndLifecycle(t *testing.T) {
+	townRoot := t.TempDir()
+	m := NewMetrics(townRoot)
+	lc := beads.NewLifecycle(townRoot)
+	for _, to := range []beads.Stage{beads.StageAssigned, beads.StageInProgress, beads.StageReview} {
+		if _, err := lc.Advance("gt-abc", to, "gastown/polecats/toast", "test", ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := lc.Advance("gt-xyz", beads.StageAssigned, "gastown/polecats/nux", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	feed := filepath.Join(townRoot, ".events.jsonl")
+	if err := os.WriteFile(feed, []byte(`{"type":"handoff","actor":"gastown/crew/max"}
+{"type":"sling","actor":"mayor"}
+{"type":"handoff","actor":"gastown/crew/max"}
+{"type":"handoff","actor":"mayor"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wantSamples(t, scrape(t, m),
+		`gt_handoffs_total{agent="gastown/crew/max"} 2`,
+		`gt_bead_transitions_total{stage="assigned"} 2`,
+		`gt_beads{stage="review"} 1`,
+		`gt_beads{stage="assigned"} 1`,
+		`gt_beads{stage="done"} 0`,
+	)
+
+	// Later lines are added on the next scrape, including one finished meanwhile
+	f, err := os.OpenFile(feed, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("}\n")
+	_ = f.Close()
+	if _, err := lc.Advance("gt-abc", beads.StageDone, "gastown/refinery", "test", ""); err != nil {
+		t.Fatal(err)
+	}
+	wantSamples(t, scrape(t, m),
+		`gt_handoffs_total{agent="gastown/crew/max"} 2`,
+		`gt_handoffs_total{agent="mayor"} 1`,
+		`gt_beads{stage="review"} 0`,
+		`gt_beads{stage="done"} 1`,
+	)
+}
+
+func TestRenderLabels(t *testing.T) {
+	if got := renderLabels([]string{"session", `a"b\c`, "role", "x\ny"}); got != `{session="a\"b\\c",role="x\ny"}` {
+		t.Errorf("renderLabels() = %s", got)
+	}
+	if got := renderLabels(nil); got != "" {
+		t.Errorf("renderLabels(nil) = %q, want empty", got)
+	}
+}
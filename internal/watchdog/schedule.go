@@ -0,0 +1,356 @@
+package watchdog
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/store"
+)
+
+// MinScheduleInterval is the shortest interval a handoff schedule may have.
+const MinScheduleInterval = time.Minute
+
+// HandoffSchedule is a standing order to hand off one session
+// automatically (gt handoff --schedule), so that long watches start over
+// with a fresh context window before the old one fills up.
+//
+// A pending handoff (SetPending) is a one-off: it has At instead of a Spec
+// and is dropped once run.
+type HandoffSchedule struct {
+	Session   string    `json:"session"`            // tmux session name
+	Spec      string    `json:"spec,omitempty"`     // "4h", or a cron expression like "0 */6 * * *"
+	At        time.Time `json:"at,omitempty"`       // for a pending handoff, when it is due
+	Reason    string    `json:"reason,omitempty"`   // recorded with the handoff; default "scheduled (<spec>)"
+	CreatedAt time.Time `json:"created_at"`         // when the schedule was set
+	LastRun   time.Time `json:"last_run,omitempty"` // the last scheduled handoff
+}
+
+// Pending reports whether h is a one-off handoff.
+func (h HandoffSchedule) Pending() bool {
+	return !h.At.IsZero()
+}
+
+// Label describes the schedule: its spec, or "once" for a pending handoff.
+func (h HandoffSchedule) Label() string {
+	if h.Pending() {
+		return "once"
+	}
+	return h.Spec
+}
+
+// Next returns when the session is next due for a handoff: the first
+// scheduled time after its last scheduled handoff (or after the schedule was
+// set), or At for a pending handoff. A time in the past means a handoff is
+// due now.
+func (h HandoffSchedule) Next() (time.Time, error) {
+	if h.Pending() {
+		return h.At, nil
+	}
+	s, err := ParseSchedule(h.Spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	from := h.LastRun
+	if from.IsZero() {
+		from = h.CreatedAt
+	}
+	return s.Next(from), nil
+}
+
+// Schedule is a parsed handoff schedule: a fixed interval or a cron
+// expression.
+type Schedule struct {
+	every time.Duration
+	cron  *cronExpr
+}
+
+// ParseSchedule parses a schedule spec: a duration ("4h", "90m") for a
+// handoff every that long, or a five-field cron expression (minute hour
+// day-of-month month day-of-week, in local time) for handoffs at those
+// times. Cron fields take *, numbers, ranges (1-5), lists (1,3) and steps
+// (*/6, 9-17/2).
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, err := time.ParseDuration(spec); err == nil {
+		if d < MinScheduleInterval {
+			return Schedule{}, fmt.Errorf("schedule interval %s is shorter than %s", d, MinScheduleInterval)
+		}
+		return Schedule{every: d}, nil
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: want a duration (4h) or a cron expression with 5 fields", spec)
+	}
+	c, err := parseCron(fields)
+	if err != nil {
+		return Schedule{}, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+	return Schedule{cron: c}, nil
+}
+
+// Next returns the first scheduled time after t.
+func (s Schedule) Next(t time.Time) time.Time {
+	if s.cron != nil {
+		return s.cron.next(t)
+	}
+	return t.Add(s.every)
+}
+
+// cronExpr is a parsed cron expression: the allowed values of each field.
+type cronExpr struct {
+	minute, hour, dom, month, dow [64]bool
+	domAny, dowAny                bool // The field was *: only the other day field restricts days
+}
+
+// cronFields are the ranges of the five cron fields, in order.
+var cronFields = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are both Sunday
+}
+
+func parseCron(fields []string) (*cronExpr, error) {
+	c := &cronExpr{}
+	sets := [5]*[64]bool{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+	for i, field := range fields {
+		if err := parseCronField(field, cronFields[i].min, cronFields[i].max, sets[i]); err != nil {
+			return nil, fmt.Errorf("%s: %w", cronFields[i].name, err)
+		}
+	}
+	if c.dow[7] {
+		c.dow[0] = true
+	}
+	c.domAny = strings.HasPrefix(fields[2], "*")
+	c.dowAny = strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// parseCronField marks the values field allows in set.
+func parseCronField(field string, min, max int, set *[64]bool) error {
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var errA, errB error
+			lo, errA = strconv.Atoi(a)
+			hi, errB = strconv.Atoi(b)
+			if errA != nil || errB != nil || lo > hi {
+				return fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return fmt.Errorf("invalid value %q", rangePart)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				hi = max // "5/15" means from 5 on, every 15
+			}
+		}
+		if lo < min || hi > max {
+			return fmt.Errorf("%q is out of range %d-%d", rangePart, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return nil
+}
+
+// dayMatches applies cron's day rule: when both day fields are restricted,
+// a day matching either one is scheduled.
+func (c *cronExpr) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// next returns the first minute after t that the expression matches,
+// skipping whole months, days and hours that can't match. An expression
+// that never matches (e.g. February 30th) gives the zero time.
+func (c *cronExpr) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !c.month[int(t.Month())]:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.hour[t.Hour()]:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.minute[t.Minute()]:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// SchedulesFile returns the town's handoff schedules file.
+func SchedulesFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "handoff-schedules.json")
+}
+
+// LoadSchedules returns the town's handoff schedules, sorted by session.
+// A town without schedules has none.
+func LoadSchedules(townRoot string) ([]HandoffSchedule, error) {
+	m, err := readSchedules(townRoot)
+	if err != nil {
+		return nil, err
+	}
+	schedules := make([]HandoffSchedule, 0, len(m))
+	for _, h := range m {
+		schedules = append(schedules, h)
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].Session < schedules[j].Session })
+	return schedules, nil
+}
+
+// SetSchedule schedules handoffs of session by spec, replacing any
+// schedule it had. The first handoff is one interval from now.
+func SetSchedule(townRoot, session, spec string) (HandoffSchedule, error) {
+	if _, err := ParseSchedule(spec); err != nil {
+		return HandoffSchedule{}, err
+	}
+	h := HandoffSchedule{Session: session, Spec: strings.TrimSpace(spec), CreatedAt: time.Now().UTC()}
+	err := updateSchedules(townRoot, func(m map[string]HandoffSchedule) bool {
+		m[session] = h
+		return true
+	})
+	return h, err
+}
+
+// RemoveSchedule removes session's handoff schedule, reporting whether it
+// had one.
+func RemoveSchedule(townRoot, session string) (bool, error) {
+	var removed bool
+	err := updateSchedules(townRoot, func(m map[string]HandoffSchedule) bool {
+		_, removed = m[session]
+		delete(m, session)
+		return removed
+	})
+	return removed, err
+}
+
+// PendingFile returns the town's pending one-off handoffs file.
+func PendingFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "handoff-pending.json")
+}
+
+// SetPending has session handed off once at at, for reason, replacing any
+// handoff it had pending. Its recurring schedule, if any, is unaffected.
+func SetPending(townRoot, session string, at time.Time, reason string) (HandoffSchedule, error) {
+	h := HandoffSchedule{Session: session, At: at.UTC(), Reason: reason, CreatedAt: time.Now().UTC()}
+	err := updatePending(townRoot, func(m map[string]HandoffSchedule) bool {
+		m[session] = h
+		return true
+	})
+	return h, err
+}
+
+// LoadPending returns the town's pending handoffs, sorted by session.
+func LoadPending(townRoot string) ([]HandoffSchedule, error) {
+	m := make(map[string]HandoffSchedule)
+	if _, err := store.Read(PendingFile(townRoot), &m); err != nil {
+		return nil, fmt.Errorf("pending handoffs: %w", err)
+	}
+	pending := make([]HandoffSchedule, 0, len(m))
+	for _, h := range m {
+		pending = append(pending, h)
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Session < pending[j].Session })
+	return pending, nil
+}
+
+// removePending drops h once it has run. A handoff set again meanwhile is
+// left alone.
+func removePending(townRoot string, h HandoffSchedule) error {
+	return updatePending(townRoot, func(m map[string]HandoffSchedule) bool {
+		cur, ok := m[h.Session]
+		if !ok || !cur.At.Equal(h.At) || !cur.CreatedAt.Equal(h.CreatedAt) {
+			return false
+		}
+		delete(m, h.Session)
+		return true
+	})
+}
+
+func updatePending(townRoot string, fn func(map[string]HandoffSchedule) bool) error {
+	err := store.Update(PendingFile(townRoot), func(m *map[string]HandoffSchedule) (bool, error) {
+		if *m == nil {
+			*m = make(map[string]HandoffSchedule)
+		}
+		return fn(*m), nil
+	})
+	if err != nil {
+		return fmt.Errorf("updating pending handoffs: %w", err)
+	}
+	return nil
+}
+
+// markScheduleRun records a scheduled handoff of session at t. A schedule
+// removed or replaced meanwhile is left alone.
+func markScheduleRun(townRoot string, h HandoffSchedule, t time.Time) error {
+	return updateSchedules(townRoot, func(m map[string]HandoffSchedule) bool {
+		cur, ok := m[h.Session]
+		if !ok || cur.Spec != h.Spec || !cur.CreatedAt.Equal(h.CreatedAt) {
+			return false
+		}
+		cur.LastRun = t.UTC()
+		m[h.Session] = cur
+		return true
+	})
+}
+
+// updateSchedules applies fn to the schedules under their lock and writes
+// them back if fn reports a change.
+func updateSchedules(townRoot string, fn func(map[string]HandoffSchedule) bool) error {
+	err := store.Update(SchedulesFile(townRoot), func(m *map[string]HandoffSchedule) (bool, error) {
+		if *m == nil {
+			*m = make(map[string]HandoffSchedule)
+		}
+		return fn(*m), nil
+	})
+	if err != nil {
+		return fmt.Errorf("updating handoff schedules: %w", err)
+	}
+	return nil
+}
+
+func readSchedules(townRoot string) (map[string]HandoffSchedule, error) {
+	m := make(map[string]HandoffSchedule)
+	if _, err := store.Read(SchedulesFile(townRoot), &m); err != nil {
+		return nil, fmt.Errorf("handoff schedules: %w", err)
+	}
+	return m, nil
+}
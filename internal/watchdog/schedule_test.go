@@ -0,0 +1,166 @@
+package watchdog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	t.Parallel()
+	for _, spec := range []string{"4h", "90m", "0 */6 * * *", "30 9-17/2 * * 1-5", "0 0 1,15 * *", "0 12 * * 7"} {
+		if _, err := ParseSchedule(spec); err != nil {
+			t.Errorf("ParseSchedule(%q) = %v, want nil", spec, err)
+		}
+	}
+	for _, spec := range []string{"", "30s", "every 4h", "0 */6 * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("ParseSchedule(%q) = nil, want an error", spec)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	t.Parallel()
+	// Wednesday
+	from := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"4h", from.Add(4 * time.Hour)},
+		{"0 */6 * * *", time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)},
+		{"15 10 * * *", time.Date(2026, 3, 5, 10, 15, 0, 0, time.UTC)},
+		{"0 9 * * 1", time.Date(2026, 3, 9, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 0", time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		// Both day fields restricted: either matches (the 10th, or Thursday the 5th)
+		{"0 0 10 * 4", time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2026, 3, 4, 10, 25, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}}, // Never
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("ParseSchedule(%q): %v", tt.spec, err)
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Next(%q) = %s, want %s", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestSchedulesPersist(t *testing.T) {
+	t.Parallel()
+	townRoot := t.TempDir()
+	if _, err := SetSchedule(townRoot, "hq-mayor", "bad"); err == nil {
+		t.Error("SetSchedule(bad) = nil, want an error")
+	}
+	if _, err := SetSchedule(townRoot, "hq-mayor", "4h"); err != nil {
+		t.Fatalf("SetSchedule: %v", err)
+	}
+	if _, err := SetSchedule(townRoot, "gt-gastown-witness", "0 */6 * * *"); err != nil {
+		t.Fatalf("SetSchedule: %v", err)
+	}
+	schedules, err := LoadSchedules(townRoot)
+	if err != nil || len(schedules) != 2 || schedules[0].Session != "gt-gastown-witness" || schedules[1].Spec != "4h" {
+		t.Fatalf("LoadSchedules() = %+v, %v", schedules, err)
+	}
+
+	removed, err := RemoveSchedule(townRoot, "hq-mayor")
+	if err != nil || !removed {
+		t.Errorf("RemoveSchedule(hq-mayor) = %v, %v; want true", removed, err)
+	}
+	if removed, _ := RemoveSchedule(townRoot, "hq-mayor"); removed {
+		t.Error("RemoveSchedule of a removed schedule reported a removal")
+	}
+}
+
+func TestPoll_RunsDueSchedules(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"hq-mayor", "gt-gastown-witness", "gt-gastown-crew-max"},
+		alive:    map[string]bool{"hq-mayor": true, "gt-gastown-witness": true},
+	}
+	w, _, _ := newTestWatchdog(t, nil, src)
+	var handedOff []string
+	w.Handoff = func(h HandoffSchedule) error {
+		handedOff = append(handedOff, h.Session)
+		return nil
+	}
+	for _, sess := range []string{"hq-mayor", "gt-gastown-witness", "gt-gastown-crew-max"} {
+		if _, err := SetSchedule(w.townRoot, sess, "4h"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := SetSchedule(w.townRoot, "gt-gastown-witness", "0 0 1 1 *"); err != nil {
+		t.Fatal(err)
+	}
+
+	if actions := pollN(t, w, 1); len(actions) != 0 {
+		t.Fatalf("poll before any schedule was due acted: %+v", actions)
+	}
+
+	// Four hours on, the mayor is due; the witness's yearly schedule isn't,
+	// and max's agent is dead, so the watchdog leaves that to its policy
+	start := time.Now()
+	w.now = func() time.Time { return start.Add(4*time.Hour + time.Minute) }
+	actions := pollN(t, w, 1)
+	var scheduled []Action
+	for _, a := range actions {
+		if a.Schedule != "" {
+			scheduled = append(scheduled, a)
+		}
+	}
+	if len(scheduled) != 1 || scheduled[0].Session != "hq-mayor" || scheduled[0].Role != "mayor" || len(handedOff) != 1 {
+		t.Fatalf("scheduled actions = %+v, handoffs %v; want one hq-mayor handoff", scheduled, handedOff)
+	}
+
+	// The run is recorded: the next poll doesn't hand off again
+	pollN(t, w, 1)
+	if len(handedOff) != 1 {
+		t.Errorf("handoffs after a second poll = %v, want one", handedOff)
+	}
+	schedules, _ := LoadSchedules(w.townRoot)
+	for _, h := range schedules {
+		if h.Session == "hq-mayor" && h.LastRun.IsZero() {
+			t.Error("hq-mayor's scheduled handoff was not recorded")
+		}
+	}
+}
+
+func TestPoll_RunsPendingHandoffsOnce(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"hq-mayor", "gt-gastown-witness"},
+		alive:    map[string]bool{"hq-mayor": true, "gt-gastown-witness": true},
+	}
+	w, _, _ := newTestWatchdog(t, nil, src)
+	var reasons []string
+	w.Handoff = func(h HandoffSchedule) error {
+		reasons = append(reasons, h.Session+": "+h.Reason)
+		return nil
+	}
+	start := time.Now()
+	w.now = func() time.Time { return start }
+	if _, err := SetSchedule(w.townRoot, "hq-mayor", "4h"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetPending(w.townRoot, "hq-mayor", start.Add(-time.Minute), "upgrade"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SetPending(w.townRoot, "gt-gastown-witness", start.Add(time.Hour), "upgrade"); err != nil {
+		t.Fatal(err)
+	}
+
+	actions := pollN(t, w, 2)
+	if len(actions) != 1 || actions[0].Schedule != "once" || len(reasons) != 1 || reasons[0] != "hq-mayor: upgrade" {
+		t.Fatalf("actions %+v, handoffs %v; want the mayor's pending handoff once", actions, reasons)
+	}
+	pending, _ := LoadPending(w.townRoot)
+	if len(pending) != 1 || pending[0].Session != "gt-gastown-witness" {
+		t.Errorf("pending = %+v, want only the witness's left", pending)
+	}
+	if schedules, _ := LoadSchedules(w.townRoot); len(schedules) != 1 || !schedules[0].LastRun.IsZero() {
+		t.Errorf("schedules = %+v, want the mayor's recurring one untouched", schedules)
+	}
+}
@@ -0,0 +1,618 @@
+// Package watchdog restarts agents that have died inside their sessions.
+//
+// An agent session can outlive its agent: the agent crashes or exits and the
+// tmux pane is left at a shell (or dead, with remain-on-exit), and nothing
+// notices until someone looks. The watchdog polls every Gas Town session,
+// checks the pane for the agent's processes (config.GetProcessNames for the
+// session's GT_AGENT), and applies the town's policy to agents found dead on
+// consecutive polls: restart them in their last conversation, restart them
+// fresh, or only notify. Restarts of a session are capped per hour so an
+// agent that dies on start isn't restarted in a loop.
+//
+// Live agents are watched too: their pane output is classified with the
+// preset's output patterns (package agentstatus), and an agent showing a
+// confirmation prompt or an error on consecutive polls is reported as stuck.
+//
+// The watchdog also runs scheduled handoffs (gt handoff --schedule): a
+// session with a schedule whose agent is alive is handed off when due.
+// Pending one-off handoffs (e.g. from gt upgrade --rollout) run the same
+// way and are then dropped.
+//
+// With a report interval set, it also has a witness report written for
+// each rig with live crew or polecat agents once per interval.
+//
+// The mayor is failed over when its session is gone altogether: a mayor
+// still in the session manifest (stopping it forgets it) whose session is
+// missing on consecutive polls is replaced by the standby mayor, if one is
+// running, or its session is recreated (see Watchdog.Failover).
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/steveyegge/gastown/internal/agentstatus"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+	"github.com/steveyegge/gastown/internal/tmux"
+)
+
+// DeadPolls is how many consecutive polls an agent must be found dead before
+// the policy is applied, so a pane between agent restarts (e.g. during a
+// handoff) isn't mistaken for a dead agent.
+const DeadPolls = 2
+
+// StuckPolls is how many consecutive polls a live agent must show it is
+// waiting for input or stopped on an error before it is reported, so a
+// prompt answered within a poll isn't.
+const StuckPolls = 2
+
+// paneLines is how much of a live agent's pane is captured to classify.
+const paneLines = 50
+
+// restartWindow is the window WatchdogConfig.MaxRestarts counts restarts in.
+const restartWindow = time.Hour
+
+// Action is what the watchdog did about one session: a dead agent, a
+// stuck one, a scheduled handoff, or a witness report.
+type Action struct {
+	Session   string
+	Role      string
+	Agent     string // The session's GT_AGENT; empty for the default agent
+	Policy    string // The policy applied (config.WatchdogResume, ...)
+	Schedule  string // For a scheduled handoff, the schedule's spec
+	Report    string // For a witness report, the rig
+	Restarted bool
+	Throttled bool  // Restart skipped: MaxRestarts reached within the hour
+	Err       error // Restart (or handoff) failed
+
+	// For the mayor's session gone (Missing): whether the failover
+	// promoted the standby mayor rather than recreating the session.
+	Missing  bool
+	Promoted bool
+
+	// For a live agent that is stuck: agentstatus.StatusWaiting or
+	// StatusError, and the pane line that shows it. StuckFor is set when
+	// it has been stuck for its StuckAfter.
+	Stuck    agentstatus.Status
+	Line     string
+	StuckFor time.Duration
+}
+
+// String describes the action for logs.
+func (a Action) String() string {
+	switch {
+	case a.StuckFor > 0 && a.Stuck == agentstatus.StatusWaiting:
+		return fmt.Sprintf("%s: agent waiting for input for %s: %s", a.Session, a.StuckFor.Round(time.Second), a.Line)
+	case a.StuckFor > 0:
+		return fmt.Sprintf("%s: agent stopped on an error for %s: %s", a.Session, a.StuckFor.Round(time.Second), a.Line)
+	case a.Stuck == agentstatus.StatusWaiting:
+		return fmt.Sprintf("%s: agent waiting for input: %s", a.Session, a.Line)
+	case a.Stuck != "":
+		return fmt.Sprintf("%s: agent stopped on an error: %s", a.Session, a.Line)
+	case a.Missing && a.Promoted:
+		return fmt.Sprintf("%s: mayor session gone, standby mayor promoted", a.Session)
+	case a.Missing && a.Restarted:
+		return fmt.Sprintf("%s: mayor session gone, recreated (%s)", a.Session, a.Policy)
+	case a.Missing && a.Err != nil:
+		return fmt.Sprintf("%s: mayor session gone, failover (%s) failed: %v", a.Session, a.Policy, a.Err)
+	case a.Missing && a.Throttled:
+		return fmt.Sprintf("%s: mayor session gone, not recreated (failed over too often in the last hour)", a.Session)
+	case a.Missing:
+		return fmt.Sprintf("%s: mayor session gone (notify only)", a.Session)
+	case a.Report != "" && a.Err != nil:
+		return fmt.Sprintf("%s: witness report failed: %v", a.Report, a.Err)
+	case a.Report != "":
+		return fmt.Sprintf("%s: witness report written", a.Report)
+	case a.Schedule != "" && a.Err != nil:
+		return fmt.Sprintf("%s: scheduled handoff (%s) failed: %v", a.Session, a.Schedule, a.Err)
+	case a.Schedule != "":
+		return fmt.Sprintf("%s: scheduled handoff (%s)", a.Session, a.Schedule)
+	case a.Restarted:
+		return fmt.Sprintf("%s: agent dead, restarted (%s)", a.Session, a.Policy)
+	case a.Err != nil:
+		return fmt.Sprintf("%s: agent dead, restart (%s) failed: %v", a.Session, a.Policy, a.Err)
+	case a.Throttled:
+		return fmt.Sprintf("%s: agent dead, not restarted (restarted too often in the last hour)", a.Session)
+	default:
+		return fmt.Sprintf("%s: agent dead (notify only)", a.Session)
+	}
+}
+
+// SessionSource abstracts the tmux queries the watchdog needs, for testing.
+type SessionSource interface {
+	ListSessions() ([]string, error)
+	GetEnvironment(session, key string) (string, error)
+	IsRuntimeRunning(session string, processNames []string) bool
+	CapturePane(session string, lines int) (string, error)
+}
+
+// Watchdog polls the agent sessions of one town.
+type Watchdog struct {
+	// Restart restarts the agent in session, in a new conversation if
+	// fresh is set.
+	Restart func(session string, fresh bool) error
+
+	// Notify reports a dead agent that was not restarted, or a stuck one.
+	// Called once per death, and once each time an agent gets stuck.
+	Notify func(Action)
+
+	// StuckAfter returns how long an agent in rig ("" for town-level
+	// agents) must stay stuck before Notify is called about it once more,
+	// with Action.StuckFor set. While nil, or for 0, it isn't.
+	StuckAfter func(rig string) time.Duration
+
+	// Handoff hands off the session of a due schedule. Schedules are
+	// ignored while it is nil.
+	Handoff func(HandoffSchedule) error
+
+	// Report writes a witness report of rig. Reports are skipped while it
+	// is nil or no report interval is configured.
+	Report func(rig string) error
+
+	// Failover brings the mayor back once its session is gone: it
+	// promotes the standby mayor if one is running (reporting promoted),
+	// otherwise recreates the session, in a new conversation if fresh is
+	// set. Missing mayors are ignored while it is nil.
+	Failover func(fresh bool) (promoted bool, err error)
+
+	// Metrics, if set, records every poll for the /metrics endpoint.
+	Metrics *Metrics
+
+	config   *config.WatchdogConfig
+	townRoot string
+	source   SessionSource
+	now      func() time.Time
+	manifest string // Session manifest, to tell a lost mayor from a stopped one
+
+	mayorMissing int  // Consecutive polls the recorded mayor's session was gone
+	mayorHandled bool // Missing mayor already notified about
+
+	dead     map[string]int         // Consecutive polls each session's agent was dead
+	handled  map[string]bool        // Dead agents already notified about
+	restarts map[string][]time.Time // Recent restarts of each session
+	activity map[string]activity    // What each live agent has been showing
+	reported map[string]time.Time   // When each rig's last report was due
+	adapters map[string]*agentstatus.Adapter
+}
+
+// activity is the status a live agent has shown on consecutive polls.
+type activity struct {
+	status agentstatus.Status
+	polls  int
+	since  time.Time // The first of those polls
+	long   bool      // Reported as stuck for StuckAfter
+}
+
+// New creates a watchdog for the town at townRoot with the given settings
+// (nil for the defaults).
+func New(townRoot string, cfg *config.WatchdogConfig, source SessionSource) *Watchdog {
+	return &Watchdog{
+		config:   cfg,
+		townRoot: townRoot,
+		source:   source,
+		now:      time.Now,
+		manifest: session.ManifestPath(),
+		dead:     make(map[string]int),
+		handled:  make(map[string]bool),
+		restarts: make(map[string][]time.Time),
+		activity: make(map[string]activity),
+		reported: make(map[string]time.Time),
+		adapters: make(map[string]*agentstatus.Adapter),
+	}
+}
+
+// Poll checks every agent session once and applies the policy to agents
+// that have now been dead for DeadPolls polls, and reports live agents that
+// have been stuck for StuckPolls. Paused agents (gt crew pause) are left
+// alone. Nothing is done while gt down is shutting the town down.
+func (w *Watchdog) Poll() ([]Action, error) {
+	if _, err := os.Stat(filepath.Join(w.townRoot, "daemon", "shutdown.lock")); err == nil {
+		return nil, nil
+	}
+	sessions, err := w.source.ListSessions()
+	if err != nil {
+		err = fmt.Errorf("listing sessions: %w", err)
+		w.Metrics.observePoll(nil, nil, nil, err)
+		return nil, err
+	}
+	sort.Strings(sessions)
+
+	var actions []Action
+	seen := make(map[string]bool)
+	alive := make(map[string]bool)
+	workingRigs := make(map[string]bool) // Rigs with live crew or polecats
+	aliveByRole, deadByRole := make(map[string]int), make(map[string]int)
+	for _, sess := range sessions {
+		if !strings.HasPrefix(sess, session.Prefix) && !strings.HasPrefix(sess, session.HQPrefix) {
+			continue
+		}
+		identity, err := session.ParseSessionName(sess)
+		if err != nil {
+			continue // Not an agent session
+		}
+		seen[sess] = true
+
+		agent, _ := w.source.GetEnvironment(sess, "GT_AGENT")
+		if w.source.IsRuntimeRunning(sess, config.GetProcessNames(agent)) {
+			aliveByRole[string(identity.Role)]++
+			delete(w.dead, sess)
+			delete(w.handled, sess)
+			if paused, _ := w.source.GetEnvironment(sess, tmux.PausedEnv); paused != "" {
+				// Paused (gt crew pause): neither stuck nor working, and
+				// handoffs wait for it to be resumed
+				delete(w.activity, sess)
+				continue
+			}
+			alive[sess] = true
+			if identity.Role == session.RoleCrew || identity.Role == session.RolePolecat {
+				workingRigs[identity.Rig] = true
+			}
+			if a, stuck := w.checkActivity(sess, identity.Rig, string(identity.Role), agent); stuck {
+				actions = append(actions, a)
+			}
+			continue
+		}
+		delete(w.activity, sess)
+		deadByRole[string(identity.Role)]++
+		w.dead[sess]++
+		if w.dead[sess] < DeadPolls || w.handled[sess] {
+			continue
+		}
+		actions = append(actions, w.handle(sess, string(identity.Role), agent))
+	}
+
+	// Forget sessions that are gone
+	for sess := range w.dead {
+		if !seen[sess] {
+			delete(w.dead, sess)
+			delete(w.handled, sess)
+		}
+	}
+	for sess := range w.activity {
+		if !seen[sess] {
+			delete(w.activity, sess)
+		}
+	}
+
+	if a, ok := w.checkMayor(seen[session.MayorSessionName()]); ok {
+		actions = append(actions, a)
+	}
+	scheduled, err := w.runSchedules(alive)
+	actions = append(actions, scheduled...)
+	actions = append(actions, w.runReports(workingRigs)...)
+	w.Metrics.observePoll(aliveByRole, deadByRole, actions, err)
+	return actions, err
+}
+
+// runSchedules hands off each session in alive that is due by its schedule.
+// Sessions that are gone or whose agent is dead keep their schedule; a
+// handoff missed meanwhile happens once they're back.
+func (w *Watchdog) runSchedules(alive map[string]bool) ([]Action, error) {
+	if w.Handoff == nil {
+		return nil, nil
+	}
+	schedules, err := LoadSchedules(w.townRoot)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := LoadPending(w.townRoot)
+	if err != nil {
+		return nil, err
+	}
+	schedules = append(schedules, pending...)
+	var actions []Action
+	for _, h := range schedules {
+		next, err := h.Next()
+		if err != nil || !alive[h.Session] || next.IsZero() || next.After(w.now()) {
+			continue
+		}
+		a := Action{Session: h.Session, Schedule: h.Label()}
+		if identity, err := session.ParseSessionName(h.Session); err == nil {
+			a.Role = string(identity.Role)
+		}
+		a.Agent, _ = w.source.GetEnvironment(h.Session, "GT_AGENT")
+		// Recorded even on failure, so a broken handoff isn't retried every poll
+		// (a pending handoff is dropped)
+		if h.Pending() {
+			err = removePending(w.townRoot, h)
+		} else {
+			err = markScheduleRun(w.townRoot, h, w.now())
+		}
+		if err != nil {
+			return actions, err
+		}
+		a.Err = w.Handoff(h)
+		actions = append(actions, a)
+	}
+	return actions, nil
+}
+
+// runReports writes a report of each rig in rigs that has had live crew or
+// polecats for a report interval since its last one. A rig seen for the
+// first time gets its first report one interval later.
+func (w *Watchdog) runReports(rigs map[string]bool) []Action {
+	interval := w.config.GetReportInterval()
+	if w.Report == nil || interval == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(rigs))
+	for rig := range rigs {
+		names = append(names, rig)
+	}
+	sort.Strings(names)
+
+	var actions []Action
+	now := w.now()
+	for _, rig := range names {
+		last, ok := w.reported[rig]
+		if !ok {
+			w.reported[rig] = now
+			continue
+		}
+		if now.Sub(last) < interval {
+			continue
+		}
+		w.reported[rig] = now
+		actions = append(actions, Action{
+			Session: session.WitnessSessionName(rig),
+			Role:    string(session.RoleWitness),
+			Report:  rig,
+			Err:     w.Report(rig),
+		})
+	}
+	return actions
+}
+
+// checkActivity classifies the pane of the live agent in sess, and reports
+// the agent once it has shown it is waiting for input or stopped on an
+// error for StuckPolls polls, and once more when it has for its rig's
+// StuckAfter. It is reported again only after its screen changes.
+func (w *Watchdog) checkActivity(sess, rig, role, agent string) (Action, bool) {
+	pane, err := w.source.CapturePane(sess, paneLines)
+	if err != nil {
+		return Action{}, false
+	}
+	adapter, ok := w.adapters[agent]
+	if !ok {
+		adapter = agentstatus.ForAgent(agent)
+		w.adapters[agent] = adapter
+	}
+	result := adapter.Classify(pane)
+
+	now := w.now()
+	prev := w.activity[sess]
+	if prev.status != result.Status {
+		prev = activity{status: result.Status, since: now}
+	}
+	prev.polls++
+	w.activity[sess] = prev
+	if result.Status != agentstatus.StatusWaiting && result.Status != agentstatus.StatusError {
+		return Action{}, false
+	}
+
+	a := Action{Session: sess, Role: role, Agent: agent, Stuck: result.Status, Line: result.Line}
+	switch {
+	case prev.polls == StuckPolls:
+	case prev.polls > StuckPolls && !prev.long && w.StuckAfter != nil:
+		after := w.StuckAfter(rig)
+		if after <= 0 || now.Sub(prev.since) < after {
+			return Action{}, false
+		}
+		prev.long = true
+		w.activity[sess] = prev
+		a.StuckFor = now.Sub(prev.since)
+	default:
+		return Action{}, false
+	}
+	if w.Notify != nil {
+		w.Notify(a)
+	}
+	return a, true
+}
+
+// handle applies the role's policy to the dead agent in sess.
+func (w *Watchdog) handle(sess, role, agent string) Action {
+	a := Action{Session: sess, Role: role, Agent: agent, Policy: w.config.PolicyFor(role)}
+	if a.Policy != config.WatchdogNotify {
+		if w.recentRestarts(sess) >= w.config.GetMaxRestarts() {
+			a.Throttled = true
+		} else {
+			w.restarts[sess] = append(w.restarts[sess], w.now())
+			if a.Err = w.Restart(sess, a.Policy == config.WatchdogFresh); a.Err == nil {
+				a.Restarted = true
+				delete(w.dead, sess)
+				return a
+			}
+		}
+	}
+	w.handled[sess] = true
+	if w.Notify != nil {
+		w.Notify(a)
+	}
+	return a
+}
+
+// checkMayor fails the mayor over once its session has been gone for
+// DeadPolls polls while it is still in the session manifest - it was lost
+// rather than stopped, which forgets it - applying the mayor's policy:
+// notify only, or Failover.
+func (w *Watchdog) checkMayor(present bool) (Action, bool) {
+	if w.Failover == nil {
+		return Action{}, false
+	}
+	sess := session.MayorSessionName()
+	entry, recorded := w.recorded(sess)
+	if present || !recorded {
+		w.mayorMissing = 0
+		w.mayorHandled = false
+		return Action{}, false
+	}
+	w.mayorMissing++
+	if w.mayorMissing < DeadPolls || w.mayorHandled {
+		return Action{}, false
+	}
+
+	role := string(session.RoleMayor)
+	a := Action{Session: sess, Role: role, Agent: entry.Agent, Policy: w.config.PolicyFor(role), Missing: true}
+	if a.Policy != config.WatchdogNotify {
+		if w.recentRestarts(sess) >= w.config.GetMaxRestarts() {
+			a.Throttled = true
+		} else {
+			w.restarts[sess] = append(w.restarts[sess], w.now())
+			if a.Promoted, a.Err = w.Failover(a.Policy == config.WatchdogFresh); a.Err == nil {
+				a.Restarted = true
+				w.mayorMissing = 0
+				return a, true
+			}
+		}
+	}
+	w.mayorHandled = true
+	if w.Notify != nil {
+		w.Notify(a)
+	}
+	return a, true
+}
+
+// recorded returns sess's entry in the session manifest, if it has one.
+func (w *Watchdog) recorded(sess string) (session.ManifestEntry, bool) {
+	entries, err := session.LoadManifest(w.manifest)
+	if err != nil {
+		return session.ManifestEntry{}, false
+	}
+	for _, e := range entries {
+		if e.Session == sess {
+			return e, true
+		}
+	}
+	return session.ManifestEntry{}, false
+}
+
+// recentRestarts returns how often sess was restarted within restartWindow,
+// dropping older restarts.
+func (w *Watchdog) recentRestarts(sess string) int {
+	cutoff := w.now().Add(-restartWindow)
+	recent := w.restarts[sess][:0]
+	for _, t := range w.restarts[sess] {
+		if t.After(cutoff) {
+			recent = append(recent, t)
+		}
+	}
+	w.restarts[sess] = recent
+	return len(recent)
+}
+
+// Run polls every interval (WatchdogConfig.Interval) until ctx is done,
+// passing each action and poll error to report.
+func (w *Watchdog) Run(ctx context.Context, report func(Action), reportErr func(error)) {
+	ticker := time.NewTicker(w.config.GetInterval())
+	defer ticker.Stop()
+	for {
+		actions, err := w.Poll()
+		if err != nil {
+			reportErr(err)
+		}
+		for _, a := range actions {
+			report(a)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dir returns the directory of the watchdog's PID, lock and log files,
+// which it shares with the daemon.
+func Dir(townRoot string) string {
+	return filepath.Join(townRoot, "daemon")
+}
+
+// LogFile returns the path to the watchdog log.
+func LogFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "watchdog.log")
+}
+
+func pidFile(townRoot string) string {
+	return filepath.Join(Dir(townRoot), "watchdog.pid")
+}
+
+// Lock takes the town's watchdog lock and records the current process as
+// the watchdog. The returned func releases both. It fails if a watchdog is
+// already running.
+func Lock(townRoot string) (func(), error) {
+	if err := os.MkdirAll(Dir(townRoot), 0755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", Dir(townRoot), err)
+	}
+	fileLock := flock.New(filepath.Join(Dir(townRoot), "watchdog.lock"))
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return nil, fmt.Errorf("acquiring lock: %w", err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("watchdog already running (lock held by another process)")
+	}
+	if err := os.WriteFile(pidFile(townRoot), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		_ = fileLock.Unlock()
+		return nil, fmt.Errorf("writing PID file: %w", err)
+	}
+	return func() {
+		_ = os.Remove(pidFile(townRoot))
+		_ = fileLock.Unlock()
+	}, nil
+}
+
+// IsRunning reports whether a watchdog holds the town's lock, and its PID.
+func IsRunning(townRoot string) (bool, int, error) {
+	lockPath := filepath.Join(Dir(townRoot), "watchdog.lock")
+	if _, err := os.Stat(lockPath); os.IsNotExist(err) {
+		return false, 0, nil
+	}
+	fileLock := flock.New(lockPath)
+	locked, err := fileLock.TryLock()
+	if err != nil {
+		return false, 0, fmt.Errorf("checking lock: %w", err)
+	}
+	if locked {
+		_ = fileLock.Unlock()
+		return false, 0, nil
+	}
+	data, err := os.ReadFile(pidFile(townRoot))
+	if err != nil {
+		return true, 0, nil // Lock taken, PID not written yet
+	}
+	pid, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return true, pid, nil
+}
+
+// Stop terminates the town's running watchdog.
+func Stop(townRoot string) (int, error) {
+	running, pid, err := IsRunning(townRoot)
+	if err != nil {
+		return 0, err
+	}
+	if !running || pid == 0 {
+		return 0, fmt.Errorf("watchdog is not running")
+	}
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return 0, fmt.Errorf("finding process: %w", err)
+	}
+	// SIGTERM lets the watchdog release its lock; Windows can only kill
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		if err := process.Kill(); err != nil {
+			return 0, fmt.Errorf("stopping watchdog (PID %d): %w", pid, err)
+		}
+	}
+	_ = os.Remove(pidFile(townRoot))
+	return pid, nil
+}
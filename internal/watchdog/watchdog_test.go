@@ -0,0 +1,377 @@
+package watchdog
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentstatus"
+	"github.com/steveyegge/gastown/internal/config"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+type fakeSource struct {
+	sessions []string
+	alive    map[string]bool
+	env      map[string]map[string]string
+	panes    map[string]string
+	checked  map[string][]string // Process names each session was checked for
+}
+
+func (f *fakeSource) ListSessions() ([]string, error) { return f.sessions, nil }
+
+func (f *fakeSource) GetEnvironment(session, key string) (string, error) {
+	return f.env[session][key], nil
+}
+
+func (f *fakeSource) CapturePane(session string, lines int) (string, error) {
+	return f.panes[session], nil
+}
+
+func (f *fakeSource) IsRuntimeRunning(session string, processNames []string) bool {
+	if f.checked == nil {
+		f.checked = make(map[string][]string)
+	}
+	f.checked[session] = processNames
+	return f.alive[session]
+}
+
+// restartRecorder is a Watchdog.Restart that records its calls.
+type restartRecorder struct {
+	calls []string
+	fresh []bool
+	err   error
+}
+
+func (r *restartRecorder) restart(session string, fresh bool) error {
+	r.calls = append(r.calls, session)
+	r.fresh = append(r.fresh, fresh)
+	return r.err
+}
+
+func newTestWatchdog(t *testing.T, cfg *config.WatchdogConfig, src *fakeSource) (*Watchdog, *restartRecorder, *[]Action) {
+	t.Helper()
+	w := New(t.TempDir(), cfg, src)
+	rec := &restartRecorder{}
+	var notified []Action
+	w.Restart = rec.restart
+	w.Notify = func(a Action) { notified = append(notified, a) }
+	return w, rec, &notified
+}
+
+func pollN(t *testing.T, w *Watchdog, n int) []Action {
+	t.Helper()
+	var all []Action
+	for i := 0; i < n; i++ {
+		actions, err := w.Poll()
+		if err != nil {
+			t.Fatalf("Poll: %v", err)
+		}
+		all = append(all, actions...)
+	}
+	return all
+}
+
+func TestPoll_RestartsAfterConsecutiveDeadPolls(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"gt-gastown-crew-max", "gt-gastown-witness", "scratch"},
+		alive:    map[string]bool{"gt-gastown-witness": true},
+		env:      map[string]map[string]string{"gt-gastown-crew-max": {"GT_AGENT": "kimi"}},
+	}
+	w, rec, notified := newTestWatchdog(t, nil, src)
+
+	if actions := pollN(t, w, 1); len(actions) != 0 {
+		t.Fatalf("first dead poll acted: %+v", actions)
+	}
+	actions := pollN(t, w, 1)
+	if len(actions) != 1 || !actions[0].Restarted || actions[0].Policy != config.WatchdogResume || actions[0].Agent != "kimi" {
+		t.Fatalf("second dead poll = %+v, want one resume restart", actions)
+	}
+	if len(rec.calls) != 1 || rec.calls[0] != "gt-gastown-crew-max" || rec.fresh[0] {
+		t.Errorf("restarts = %v (fresh %v), want one resume of gt-gastown-crew-max", rec.calls, rec.fresh)
+	}
+	if len(*notified) != 0 {
+		t.Errorf("notified %+v, want nothing for a restart", *notified)
+	}
+	if got := src.checked["gt-gastown-crew-max"]; len(got) == 0 || got[0] != "kimi" {
+		t.Errorf("crew checked for %v, want kimi's process names", got)
+	}
+	if _, ok := src.checked["scratch"]; ok {
+		t.Error("non-agent session was checked")
+	}
+}
+
+func TestPoll_Policies(t *testing.T) {
+	src := &fakeSource{sessions: []string{"hq-mayor", "gt-gastown-Toast"}}
+	cfg := &config.WatchdogConfig{
+		Policy:       config.WatchdogFresh,
+		RolePolicies: map[string]string{"mayor": config.WatchdogNotify},
+	}
+	w, rec, notified := newTestWatchdog(t, cfg, src)
+
+	pollN(t, w, 4)
+	if len(rec.calls) != 2 || rec.calls[0] != "gt-gastown-Toast" || !rec.fresh[0] {
+		t.Errorf("restarts = %v (fresh %v), want gt-gastown-Toast restarted fresh", rec.calls, rec.fresh)
+	}
+	if len(*notified) != 1 || (*notified)[0].Session != "hq-mayor" {
+		t.Errorf("notified %+v, want the mayor once", *notified)
+	}
+}
+
+func TestPoll_ThrottlesRestarts(t *testing.T) {
+	src := &fakeSource{sessions: []string{"gt-gastown-crew-max"}}
+	w, rec, notified := newTestWatchdog(t, &config.WatchdogConfig{MaxRestarts: 2}, src)
+	now := time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	pollN(t, w, 10)
+	if len(rec.calls) != 2 {
+		t.Errorf("restarted %d times, want 2", len(rec.calls))
+	}
+	if len(*notified) != 1 || !(*notified)[0].Throttled {
+		t.Errorf("notified %+v, want one throttled notice", *notified)
+	}
+
+	// An hour later the session may be restarted again, once it has been
+	// seen alive or dead anew
+	now = now.Add(2 * time.Hour)
+	src.alive = map[string]bool{"gt-gastown-crew-max": true}
+	pollN(t, w, 1)
+	src.alive = nil
+	pollN(t, w, 2)
+	if len(rec.calls) != 3 {
+		t.Errorf("restarted %d times after the window, want 3", len(rec.calls))
+	}
+}
+
+func TestPoll_RestartFailureNotifies(t *testing.T) {
+	src := &fakeSource{sessions: []string{"gt-gastown-crew-max"}}
+	w, rec, notified := newTestWatchdog(t, nil, src)
+	rec.err = errors.New("no pane")
+
+	pollN(t, w, 5)
+	if len(rec.calls) != 1 {
+		t.Errorf("restarted %d times, want 1", len(rec.calls))
+	}
+	if len(*notified) != 1 || (*notified)[0].Err == nil {
+		t.Errorf("notified %+v, want one failed restart", *notified)
+	}
+}
+
+func TestPoll_FailsOverLostMayor(t *testing.T) {
+	src := &fakeSource{sessions: []string{"gt-gastown-witness"}, alive: map[string]bool{"gt-gastown-witness": true}}
+	w, rec, notified := newTestWatchdog(t, nil, src)
+	w.manifest = filepath.Join(t.TempDir(), "sessions.json")
+	var fresh []bool
+	promote := false
+	w.Failover = func(f bool) (bool, error) {
+		fresh = append(fresh, f)
+		return promote, nil
+	}
+
+	// Never started (or stopped, which forgets it): nothing to fail over
+	if actions := pollN(t, w, DeadPolls+1); len(actions) != 0 {
+		t.Errorf("unrecorded mayor: actions %v, want none", actions)
+	}
+
+	if err := session.RecordSession(w.manifest, session.ManifestEntry{Session: "hq-mayor", Role: "mayor", Agent: "kimi"}); err != nil {
+		t.Fatal(err)
+	}
+	actions := pollN(t, w, DeadPolls)
+	if len(actions) != 1 || !actions[0].Missing || !actions[0].Restarted || actions[0].Agent != "kimi" {
+		t.Fatalf("lost mayor: actions %+v, want it recreated", actions)
+	}
+	if len(fresh) != 1 || fresh[0] {
+		t.Errorf("failovers %v, want one resuming the mayor's conversation", fresh)
+	}
+
+	// Lost again: this time a standby mayor takes over
+	promote = true
+	actions = pollN(t, w, DeadPolls)
+	if len(actions) != 1 || !actions[0].Promoted {
+		t.Errorf("lost mayor with a standby: actions %+v, want the standby promoted", actions)
+	}
+	if len(rec.calls) != 0 || len(*notified) != 0 {
+		t.Errorf("restarts %v, notified %v; want none", rec.calls, *notified)
+	}
+
+	// With the notify policy, it is only reported, once
+	w.config = &config.WatchdogConfig{RolePolicies: map[string]string{"mayor": config.WatchdogNotify}}
+	pollN(t, w, DeadPolls+2)
+	if len(fresh) != 2 || len(*notified) != 1 || !(*notified)[0].Missing {
+		t.Errorf("notify policy: failovers %v, notified %+v; want the lost mayor reported once", fresh, *notified)
+	}
+}
+
+func TestPoll_SkipsDuringShutdown(t *testing.T) {
+	src := &fakeSource{sessions: []string{"gt-gastown-crew-max"}}
+	w, rec, _ := newTestWatchdog(t, nil, src)
+	if err := os.MkdirAll(Dir(w.townRoot), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(Dir(w.townRoot), "shutdown.lock"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pollN(t, w, 3)
+	if len(rec.calls) != 0 {
+		t.Errorf("restarted %v during shutdown", rec.calls)
+	}
+}
+
+func TestLockAndIsRunning(t *testing.T) {
+	townRoot := t.TempDir()
+	if running, _, err := IsRunning(townRoot); running || err != nil {
+		t.Fatalf("IsRunning before Lock = %v, %v", running, err)
+	}
+
+	release, err := Lock(townRoot)
+	if err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	running, pid, err := IsRunning(townRoot)
+	if !running || pid != os.Getpid() || err != nil {
+		t.Errorf("IsRunning = %v, %d, %v; want true, %d", running, pid, err, os.Getpid())
+	}
+	if _, err := Lock(townRoot); err == nil {
+		t.Error("second Lock succeeded")
+	}
+
+	release()
+	if running, _, _ := IsRunning(townRoot); running {
+		t.Error("IsRunning after release = true")
+	}
+}
+
+func TestPoll_ReportsStuckAgent(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"gt-gastown-crew-max"},
+		alive:    map[string]bool{"gt-gastown-crew-max": true},
+		panes:    map[string]string{"gt-gastown-crew-max": "✻ Thinking… (esc to interrupt)\n❯"},
+	}
+	w, _, notified := newTestWatchdog(t, nil, src)
+
+	if actions := pollN(t, w, 3); len(actions) != 0 {
+		t.Fatalf("working agent: actions %v, want none", actions)
+	}
+
+	src.panes["gt-gastown-crew-max"] = " Do you want to proceed?\n ❯ 1. Yes\n   2. No"
+	if actions := pollN(t, w, StuckPolls-1); len(actions) != 0 {
+		t.Fatalf("actions %v before StuckPolls polls, want none", actions)
+	}
+	actions := pollN(t, w, 3)
+	if len(actions) != 1 || actions[0].Stuck != agentstatus.StatusWaiting || actions[0].Line != "❯ 1. Yes" {
+		t.Fatalf("actions %+v, want max reported waiting once", actions)
+	}
+	if len(*notified) != 1 || (*notified)[0].Session != "gt-gastown-crew-max" {
+		t.Errorf("notified %+v, want max", *notified)
+	}
+
+	// Answered, then stuck again: reported again
+	src.panes["gt-gastown-crew-max"] = "esc to interrupt"
+	pollN(t, w, 1)
+	src.panes["gt-gastown-crew-max"] = "API Error: 500"
+	actions = pollN(t, w, StuckPolls)
+	if len(actions) != 1 || actions[0].Stuck != agentstatus.StatusError {
+		t.Fatalf("actions %+v, want max reported on the error", actions)
+	}
+}
+
+func TestPoll_LeavesPausedAgents(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"gt-gastown-crew-max"},
+		alive:    map[string]bool{"gt-gastown-crew-max": true},
+		env:      map[string]map[string]string{"gt-gastown-crew-max": {"GT_PAUSED": "2026-01-01T09:00:00Z"}},
+		panes:    map[string]string{"gt-gastown-crew-max": " Do you want to proceed?\n ❯ 1. Yes\n   2. No"},
+	}
+	w, _, notified := newTestWatchdog(t, nil, src)
+
+	if actions := pollN(t, w, StuckPolls+1); len(actions) != 0 || len(*notified) != 0 {
+		t.Errorf("paused agent: actions %v, notified %v; want none", actions, *notified)
+	}
+}
+
+func TestPoll_ReportsLongStuckAgent(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"gt-gastown-crew-max"},
+		alive:    map[string]bool{"gt-gastown-crew-max": true},
+		panes:    map[string]string{"gt-gastown-crew-max": " Do you want to proceed?\n ❯ 1. Yes\n   2. No"},
+	}
+	w, _, notified := newTestWatchdog(t, nil, src)
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	var rigs []string
+	w.StuckAfter = func(rig string) time.Duration {
+		rigs = append(rigs, rig)
+		return 10 * time.Minute
+	}
+
+	if actions := pollN(t, w, StuckPolls); len(actions) != 1 || actions[0].StuckFor != 0 {
+		t.Fatalf("actions %+v, want max reported stuck", actions)
+	}
+	now = now.Add(9 * time.Minute)
+	if actions := pollN(t, w, 1); len(actions) != 0 {
+		t.Fatalf("actions %+v before StuckAfter, want none", actions)
+	}
+	now = now.Add(time.Minute)
+	actions := pollN(t, w, 1)
+	if len(actions) != 1 || actions[0].StuckFor != 10*time.Minute {
+		t.Fatalf("actions %+v, want max reported stuck for 10m", actions)
+	}
+	now = now.Add(time.Hour)
+	if actions := pollN(t, w, 2); len(actions) != 0 {
+		t.Fatalf("actions %+v after the StuckAfter report, want none", actions)
+	}
+	if len(*notified) != 2 {
+		t.Errorf("notified %+v, want both reports", *notified)
+	}
+	if len(rigs) == 0 || rigs[0] != "gastown" {
+		t.Errorf("StuckAfter asked for rigs %q, want gastown", rigs)
+	}
+}
+
+func TestPoll_RunsReports(t *testing.T) {
+	src := &fakeSource{
+		sessions: []string{"gt-gastown-crew-max", "gt-gastown-witness", "gt-beads-crew-joe", "hq-mayor"},
+		alive:    map[string]bool{"gt-gastown-crew-max": true, "gt-gastown-witness": true, "hq-mayor": true},
+	}
+	w, _, _ := newTestWatchdog(t, &config.WatchdogConfig{ReportInterval: "1h"}, src)
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	var reported []string
+	w.Report = func(rig string) error {
+		reported = append(reported, rig)
+		return nil
+	}
+
+	// The first report is an interval after the rig is first seen
+	pollN(t, w, 1)
+	now = now.Add(59 * time.Minute)
+	pollN(t, w, 1)
+	if len(reported) != 0 {
+		t.Fatalf("reported %v before the interval, want none", reported)
+	}
+
+	now = now.Add(time.Minute)
+	var reports []Action
+	for _, a := range pollN(t, w, 2) {
+		if a.Report != "" {
+			reports = append(reports, a)
+		}
+	}
+	if len(reported) != 1 || reported[0] != "gastown" {
+		t.Fatalf("reported %v, want gastown once (beads' crew is dead)", reported)
+	}
+	if len(reports) != 1 || reports[0].Session != "gt-gastown-witness" {
+		t.Errorf("report actions %+v, want one for gastown's witness", reports)
+	}
+
+	w.config = nil
+	now = now.Add(2 * time.Hour)
+	pollN(t, w, 1)
+	if len(reported) != 1 {
+		t.Errorf("reported %v without a report interval, want no more", reported)
+	}
+}
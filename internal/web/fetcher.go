@@ -1626,6 +1626,7 @@ func eventIcon(eventType string) string {
 		"sling":             "🎯",
 		"hook":              "🪝",
 		"unhook":            "🔓",
+		"handback":          "↩️",
 		"done":              "✅",
 		"mail":              "📬",
 		"spawn":             "🦨",
@@ -1682,6 +1683,9 @@ func eventSummary(eventType, actor string, payload map[string]interface{}) strin
 	case "unhook":
 		bead, _ := payload["bead"].(string)
 		return fmt.Sprintf("%s unhooked %s", shortActor, bead)
+	case "handback":
+		bead, _ := payload["bead"].(string)
+		return fmt.Sprintf("%s handed back %s", shortActor, bead)
 	case "merged":
 		branch, _ := payload["branch"].(string)
 		return fmt.Sprintf("merged %s", branch)
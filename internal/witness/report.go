@@ -0,0 +1,162 @@
+package witness
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steveyegge/gastown/internal/agentstatus"
+	"github.com/steveyegge/gastown/internal/session"
+)
+
+// DefaultReportLines is how much of each pane a report captures.
+const DefaultReportLines = 40
+
+// summaryLines is how many of a pane's last lines stand in for its summary
+// when no summarizer has one.
+const summaryLines = 3
+
+// ReportSource abstracts the tmux queries a report needs, for testing.
+type ReportSource interface {
+	ListSessions() ([]string, error)
+	GetEnvironment(session, key string) (string, error)
+	CapturePane(session string, lines int) (string, error)
+}
+
+// Summarizer summarizes the pane tail of a report entry (e.g. through the
+// report-summary hooks). An empty summary falls back to the tail's last
+// lines.
+type Summarizer func(entry ReportEntry, tail string) string
+
+// Report is a status summary of a rig's crew and polecat sessions at one
+// moment.
+type Report struct {
+	Rig     string        `json:"rig"`
+	Time    time.Time     `json:"time"`
+	Entries []ReportEntry `json:"entries"`
+}
+
+// ReportEntry is one session in a Report.
+type ReportEntry struct {
+	Session string             `json:"session"`
+	Worker  string             `json:"worker"` // "crew/max", "polecats/Toast"
+	Agent   string             `json:"agent,omitempty"`
+	Status  agentstatus.Status `json:"status"`
+	Summary string             `json:"summary,omitempty"`
+	Err     string             `json:"error,omitempty"` // The pane couldn't be captured
+}
+
+// BuildReport captures the last lines of each crew and polecat session of
+// rigName, classifies what the agent is doing and summarizes it with
+// summarize (nil for the pane's last lines).
+func BuildReport(rigName string, source ReportSource, lines int, summarize Summarizer) (*Report, error) {
+	if lines <= 0 {
+		lines = DefaultReportLines
+	}
+	sessions, err := source.ListSessions()
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	sort.Strings(sessions)
+
+	report := &Report{Rig: rigName, Time: time.Now().UTC()}
+	for _, sess := range sessions {
+		identity, err := session.ParseSessionName(sess)
+		if err != nil || identity.Rig != rigName {
+			continue
+		}
+		entry := ReportEntry{Session: sess}
+		switch identity.Role {
+		case session.RoleCrew:
+			entry.Worker = "crew/" + identity.Name
+		case session.RolePolecat:
+			entry.Worker = "polecats/" + identity.Name
+		default:
+			continue
+		}
+		entry.Agent, _ = source.GetEnvironment(sess, "GT_AGENT")
+
+		pane, err := source.CapturePane(sess, lines)
+		if err != nil {
+			entry.Status = agentstatus.StatusUnknown
+			entry.Err = err.Error()
+			report.Entries = append(report.Entries, entry)
+			continue
+		}
+		entry.Status = agentstatus.ForAgent(entry.Agent).Classify(pane).Status
+		tail := strings.TrimSpace(pane)
+		if summarize != nil {
+			entry.Summary = strings.TrimSpace(summarize(entry, tail))
+		}
+		if entry.Summary == "" {
+			entry.Summary = lastLines(tail, summaryLines)
+		}
+		report.Entries = append(report.Entries, entry)
+	}
+	return report, nil
+}
+
+// lastLines returns the last n non-blank lines of s, trailing space trimmed.
+func lastLines(s string, n int) string {
+	all := strings.Split(s, "\n")
+	var lines []string
+	for i := len(all) - 1; i >= 0 && len(lines) < n; i-- {
+		if line := strings.TrimRight(all[i], " \t\r"); strings.TrimSpace(line) != "" {
+			lines = append([]string{line}, lines...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Markdown formats the report as a section of the rig's status file.
+func (r *Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s - %s\n\n", r.Rig, r.Time.Format("2006-01-02 15:04 MST"))
+	if len(r.Entries) == 0 {
+		b.WriteString("No crew or polecat sessions running.\n")
+		return b.String()
+	}
+	for _, e := range r.Entries {
+		status := string(e.Status)
+		if e.Agent != "" {
+			status = e.Agent + ", " + status
+		}
+		fmt.Fprintf(&b, "- **%s** (%s)", e.Worker, status)
+		switch {
+		case e.Err != "":
+			fmt.Fprintf(&b, ": pane not captured: %s\n", e.Err)
+		case e.Summary == "":
+			b.WriteString(": (empty pane)\n")
+		default:
+			b.WriteString("\n")
+			for _, line := range strings.Split(e.Summary, "\n") {
+				fmt.Fprintf(&b, "  > %s\n", line)
+			}
+		}
+	}
+	return b.String()
+}
+
+// ReportFile returns the rig's status file, which reports are appended to.
+func ReportFile(rigPath string) string {
+	return filepath.Join(rigPath, "witness", "status.md")
+}
+
+// AppendReport appends r to the status file at path.
+func AppendReport(path string, r *Report) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is constructed internally
+	if err != nil {
+		return fmt.Errorf("opening status file: %w", err)
+	}
+	if _, err := f.WriteString(r.Markdown() + "\n"); err != nil {
+		f.Close()
+		return fmt.Errorf("writing status file: %w", err)
+	}
+	return f.Close()
+}
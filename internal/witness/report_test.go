@@ -0,0 +1,97 @@
+package witness
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/agentstatus"
+)
+
+type fakeReportSource struct {
+	sessions []string
+	agents   map[string]string
+	panes    map[string]string
+}
+
+func (f *fakeReportSource) ListSessions() ([]string, error) { return f.sessions, nil }
+
+func (f *fakeReportSource) GetEnvironment(session, key string) (string, error) {
+	return f.agents[session], nil
+}
+
+func (f *fakeReportSource) CapturePane(session string, lines int) (string, error) {
+	pane, ok := f.panes[session]
+	if !ok {
+		return "", errors.New("no such pane")
+	}
+	return pane, nil
+}
+
+func TestBuildReport(t *testing.T) {
+	src := &fakeReportSource{
+		sessions: []string{"gt-gastown-witness", "gt-gastown-crew-max", "gt-gastown-Toast", "gt-beads-crew-joe", "hq-mayor", "gt-gastown-crew-gone"},
+		agents:   map[string]string{"gt-gastown-Toast": "kimi"},
+		panes: map[string]string{
+			"gt-gastown-crew-max": "Ran tests\n\nAll 42 passed\n✻ Thinking… (esc to interrupt)\n\n",
+			"gt-gastown-Toast":    "Fixed the parser\n",
+			"gt-beads-crew-joe":   "not this rig",
+		},
+	}
+	summarize := func(e ReportEntry, tail string) string {
+		if e.Session == "gt-gastown-Toast" {
+			return "  Toast fixed the parser (" + tail + ")\n"
+		}
+		return ""
+	}
+
+	report, err := BuildReport("gastown", src, 0, summarize)
+	if err != nil {
+		t.Fatalf("BuildReport: %v", err)
+	}
+	if len(report.Entries) != 3 {
+		t.Fatalf("entries %+v, want gastown's crew and polecats only", report.Entries)
+	}
+	toast, gone, max := report.Entries[0], report.Entries[1], report.Entries[2]
+	if max.Worker != "crew/max" || max.Status != agentstatus.StatusWorking {
+		t.Errorf("max = %+v, want crew/max working", max)
+	}
+	if max.Summary != "Ran tests\nAll 42 passed\n✻ Thinking… (esc to interrupt)" {
+		t.Errorf("max summary = %q, want the pane's last lines", max.Summary)
+	}
+	if toast.Worker != "polecats/Toast" || toast.Agent != "kimi" || toast.Summary != "Toast fixed the parser (Fixed the parser)" {
+		t.Errorf("Toast = %+v, want the summarizer's summary", toast)
+	}
+	if gone.Err == "" || gone.Status != agentstatus.StatusUnknown {
+		t.Errorf("gone = %+v, want a capture error", gone)
+	}
+
+	md := report.Markdown()
+	for _, want := range []string{"## gastown - ", "- **crew/max** (working)\n  > Ran tests\n", "- **polecats/Toast** (kimi, ", "- **crew/gone** (unknown): pane not captured"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestAppendReport(t *testing.T) {
+	path := ReportFile(t.TempDir())
+	report := &Report{Rig: "gastown"}
+	for i := 0; i < 2; i++ {
+		if err := AppendReport(path, report); err != nil {
+			t.Fatalf("AppendReport: %v", err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(filepath.Dir(path)) != "witness" {
+		t.Errorf("ReportFile = %s, want it under the rig's witness directory", path)
+	}
+	if got := strings.Count(string(data), "No crew or polecat sessions running."); got != 2 {
+		t.Errorf("status file has %d reports, want 2:\n%s", got, data)
+	}
+}
@@ -0,0 +1,232 @@
+// Package worktree gives each bead slung to a crew member its own git
+// worktree, so crews sharing a checkout don't trample each other's edits.
+//
+// A bead's worktree lives inside the crew member's clone, at
+// .worktrees/<bead>, on a branch crew/<name>/<bead> started from the
+// remote's default branch. Keeping it under the clone keeps role detection
+// from the working directory intact; the directory is excluded from the
+// clone's git status. Removing a worktree keeps its branch, so committed
+// work is never lost with it.
+package worktree
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/steveyegge/gastown/internal/git"
+)
+
+// Dir is the directory of a clone that holds its bead worktrees.
+const Dir = ".worktrees"
+
+// ErrDirty indicates a worktree has uncommitted changes and was kept.
+var ErrDirty = errors.New("worktree has uncommitted changes")
+
+// Worktree is a bead's worktree.
+type Worktree struct {
+	Bead   string
+	Path   string
+	Branch string
+}
+
+// Manager manages the bead worktrees of one crew member's clone.
+type Manager struct {
+	clone    string
+	crewName string
+	git      *git.Git
+}
+
+// NewManager creates a manager for the bead worktrees of crew member
+// crewName, whose clone is at clonePath.
+func NewManager(clonePath, crewName string) *Manager {
+	return &Manager{
+		clone:    clonePath,
+		crewName: crewName,
+		git:      git.NewGit(clonePath),
+	}
+}
+
+// Path returns where bead's worktree lives.
+func (m *Manager) Path(bead string) string {
+	return filepath.Join(m.clone, Dir, bead)
+}
+
+// Branch returns the branch of bead's worktree.
+func (m *Manager) Branch(bead string) string {
+	return fmt.Sprintf("crew/%s/%s", m.crewName, bead)
+}
+
+// Contains reports whether path is inside one of the clone's bead
+// worktrees, and which bead's.
+func (m *Manager) Contains(path string) (string, bool) {
+	rel, err := filepath.Rel(filepath.Join(m.clone, Dir), path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return strings.Split(filepath.ToSlash(rel), "/")[0], true
+}
+
+// Create returns bead's worktree, creating it (and its branch, if new) when
+// it doesn't exist yet.
+func (m *Manager) Create(bead string) (*Worktree, error) {
+	if err := validateBead(bead); err != nil {
+		return nil, err
+	}
+	wt := &Worktree{Bead: bead, Path: m.Path(bead), Branch: m.Branch(bead)}
+	if _, err := os.Stat(filepath.Join(wt.Path, ".git")); err == nil {
+		return wt, nil
+	}
+
+	if err := m.excludeDir(); err != nil {
+		return nil, fmt.Errorf("excluding %s from git status: %w", Dir, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(wt.Path), 0755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", Dir, err)
+	}
+
+	exists, err := m.git.BranchExists(wt.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("checking branch %s: %w", wt.Branch, err)
+	}
+	if exists {
+		if err := m.git.WorktreeAddExisting(wt.Path, wt.Branch); err != nil {
+			return nil, fmt.Errorf("creating worktree: %w", err)
+		}
+		return wt, nil
+	}
+	if err := m.git.WorktreeAddFromRef(wt.Path, wt.Branch, m.startPoint()); err != nil {
+		return nil, fmt.Errorf("creating worktree: %w", err)
+	}
+	return wt, nil
+}
+
+// startPoint returns the ref new bead branches start from: the remote's
+// default branch, freshly fetched, or the clone's HEAD without a remote.
+func (m *Manager) startPoint() string {
+	if err := m.git.Fetch("origin"); err != nil {
+		return "HEAD"
+	}
+	branch := m.git.RemoteDefaultBranch()
+	if exists, err := m.git.RemoteBranchExists("origin", branch); err != nil || !exists {
+		return "HEAD"
+	}
+	return "origin/" + branch
+}
+
+// List returns the clone's bead worktrees.
+func (m *Manager) List() ([]Worktree, error) {
+	entries, err := os.ReadDir(filepath.Join(m.clone, Dir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var worktrees []Worktree
+	for _, entry := range entries {
+		path := filepath.Join(m.clone, Dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(path, ".git")); err != nil {
+			continue // Not a worktree
+		}
+		wt := Worktree{Bead: entry.Name(), Path: path}
+		if branch, err := git.NewGit(path).CurrentBranch(); err == nil {
+			wt.Branch = branch
+		}
+		worktrees = append(worktrees, wt)
+	}
+	return worktrees, nil
+}
+
+// Remove removes bead's worktree, keeping its branch. Without force it
+// fails with ErrDirty if the worktree has uncommitted changes.
+func (m *Manager) Remove(bead string, force bool) error {
+	path := m.Path(bead)
+	if !force {
+		dirty, err := git.NewGit(path).HasUncommittedChanges()
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", path, err)
+		}
+		if dirty {
+			return fmt.Errorf("%w: %s", ErrDirty, path)
+		}
+	}
+	if err := m.git.WorktreeRemove(path, force); err != nil {
+		return fmt.Errorf("removing worktree: %w", err)
+	}
+	return m.git.WorktreePrune()
+}
+
+// Prune removes the worktrees of beads that are done, except the one
+// holding path inUse (a session's working directory) and ones with
+// uncommitted changes. It returns the beads whose worktrees were removed.
+func (m *Manager) Prune(done func(bead string) bool, inUse string) ([]string, error) {
+	worktrees, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	busy, _ := m.Contains(inUse)
+	var removed []string
+	var errs []error
+	for _, wt := range worktrees {
+		if wt.Bead == busy || !done(wt.Bead) {
+			continue
+		}
+		if err := m.Remove(wt.Bead, false); err != nil {
+			if !errors.Is(err, ErrDirty) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		removed = append(removed, wt.Bead)
+	}
+	return removed, errors.Join(errs...)
+}
+
+// excludeDir adds Dir to the clone's info/exclude, once.
+func (m *Manager) excludeDir() error {
+	out, err := exec.Command("git", "-C", m.clone, "rev-parse", "--git-path", "info/exclude").Output()
+	if err != nil {
+		return err
+	}
+	path := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(m.clone, path)
+	}
+	pattern := "/" + Dir + "/"
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from git
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+		pattern = "\n" + pattern
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644) //nolint:gosec // G304: path is from git
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(pattern + "\n"); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// validateBead rejects bead IDs that can't name a directory and a branch.
+func validateBead(bead string) error {
+	if bead == "" || bead == "." || bead == ".." || strings.ContainsAny(bead, "/\\ ~^:?*[") {
+		return fmt.Errorf("invalid bead ID for a worktree: %q", bead)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package worktree
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// initClone creates a git repo with one commit, standing in for a crew clone.
+func initClone(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.email=test@example.com", "-c", "user.name=Test", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	return dir
+}
+
+func gitStatus(t *testing.T, dir string) string {
+	t.Helper()
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain").Output()
+	if err != nil {
+		t.Fatalf("git status: %v", err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCreateAndList(t *testing.T) {
+	clone := initClone(t)
+	m := NewManager(clone, "max")
+
+	wt, err := m.Create("gt-abc12")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if wt.Path != filepath.Join(clone, Dir, "gt-abc12") || wt.Branch != "crew/max/gt-abc12" {
+		t.Errorf("Create = %+v", wt)
+	}
+	if _, err := os.Stat(filepath.Join(wt.Path, ".git")); err != nil {
+		t.Errorf("worktree not created: %v", err)
+	}
+	if status := gitStatus(t, clone); status != "" {
+		t.Errorf("clone status = %q, want %s excluded", status, Dir)
+	}
+
+	// Creating again returns the existing worktree
+	if _, err := m.Create("gt-abc12"); err != nil {
+		t.Fatalf("Create again: %v", err)
+	}
+	worktrees, err := m.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(worktrees) != 1 || worktrees[0].Bead != "gt-abc12" || worktrees[0].Branch != "crew/max/gt-abc12" {
+		t.Errorf("List = %+v", worktrees)
+	}
+
+	if bead, ok := m.Contains(filepath.Join(wt.Path, "src")); !ok || bead != "gt-abc12" {
+		t.Errorf("Contains(worktree subdir) = %q, %v", bead, ok)
+	}
+	if _, ok := m.Contains(clone); ok {
+		t.Error("Contains(clone) = true")
+	}
+
+	if _, err := m.Create("../escape"); err == nil {
+		t.Error("Create(../escape) succeeded")
+	}
+}
+
+func TestRemoveKeepsBranch(t *testing.T) {
+	clone := initClone(t)
+	m := NewManager(clone, "max")
+	wt, err := m.Create("gt-abc12")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(wt.Path, "work.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Remove("gt-abc12", false); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Remove(dirty) = %v, want ErrDirty", err)
+	}
+	if err := m.Remove("gt-abc12", true); err != nil {
+		t.Fatalf("Remove(force): %v", err)
+	}
+	if _, err := os.Stat(wt.Path); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists: %v", err)
+	}
+	if exists, _ := m.git.BranchExists(wt.Branch); !exists {
+		t.Error("Remove deleted the branch")
+	}
+
+	// The branch is reused when the bead is slung again
+	if _, err := m.Create("gt-abc12"); err != nil {
+		t.Fatalf("Create after Remove: %v", err)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	clone := initClone(t)
+	m := NewManager(clone, "max")
+	for _, bead := range []string{"gt-done", "gt-open", "gt-busy", "gt-dirty"} {
+		if _, err := m.Create(bead); err != nil {
+			t.Fatalf("Create(%s): %v", bead, err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(m.Path("gt-dirty"), "work.txt"), []byte("wip"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	done := func(bead string) bool { return bead != "gt-open" }
+	removed, err := m.Prune(done, m.Path("gt-busy"))
+	if err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+	if strings.Join(removed, ",") != "gt-done" {
+		t.Errorf("Prune removed %v, want [gt-done]", removed)
+	}
+	worktrees, _ := m.List()
+	if len(worktrees) != 3 {
+		t.Errorf("%d worktrees left, want 3", len(worktrees))
+	}
+}